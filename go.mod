@@ -7,3 +7,7 @@ toolchain go1.24.11
 require github.com/yuin/goldmark v1.7.8
 
 require github.com/litao91/goldmark-mathjax v0.0.0-20210217064022-a43cf739a50f
+
+require golang.org/x/text v0.14.0
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,149 @@
+// Package document 从其他文档导入正文内容
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"path"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// importBodyElements 复制源文档正文中的段落和表格，供插入本文档使用
+//
+// 源文档的样式按"本文档样式优先"合并：同ID或同类型同名称的样式使用本文档的样式，
+// 其余样式导入本文档；编号定义以新的ID导入，图片复制到本文档并使用新的关系ID。
+// 节属性（包括段落中的分节）不会复制。返回的元素尚未加入正文，由调用方决定插入位置。
+func (d *Document) importBodyElements(src *Document) ([]interface{}, error) {
+	if src == nil || src.Body == nil {
+		return nil, NewValidationError("source", "", "源文档不能为空")
+	}
+	return d.importElements(src, src.Body.Elements)
+}
+
+// importElements 复制属于源文档的段落和表格，样式、编号和图片的处理与 importBodyElements 相同
+// 源文档为空或就是本文档时只复制元素
+func (d *Document) importElements(src *Document, source []interface{}) ([]interface{}, error) {
+	elements, err := d.copyBodyElements(source)
+	if err != nil || src == nil || src == d {
+		return elements, err
+	}
+
+	// 源文档打开时补充的预定义样式不导入
+	stylesXML, err := src.styleManager.MarshalStylesXML(func(string) bool { return false })
+	if err != nil {
+		return nil, WrapError("import_body", err)
+	}
+	result, err := d.styleManager.MergeStyles(stylesXML, &style.MergeOptions{ConflictPolicy: style.ConflictKeepExisting})
+	if err != nil {
+		return nil, WrapError("import_body", err)
+	}
+	renameElementStyles(elements, result.Renamed)
+
+	numIDs := d.importNumbering(src, result.Renamed)
+	imageIDs := make(map[string]string)
+	var importErr error
+	forEachParagraph(elements, func(p *Paragraph) {
+		if p.Properties != nil {
+			p.Properties.SectionProperties = nil
+			if numPr := p.Properties.NumberingProperties; numPr != nil && numPr.NumID != nil {
+				if newID, ok := numIDs[numPr.NumID.Val]; ok {
+					numPr.NumID.Val = newID
+				}
+			}
+		}
+		for i := range p.Runs {
+			blip := drawingBlip(p.Runs[i].Drawing)
+			if blip == nil || blip.Embed == "" {
+				continue
+			}
+			newID, ok := imageIDs[blip.Embed]
+			if !ok {
+				newID, err = d.importImage(src, blip.Embed)
+				if err != nil {
+					if importErr == nil {
+						importErr = err
+					}
+					continue
+				}
+				imageIDs[blip.Embed] = newID
+			}
+			blip.Embed = newID
+		}
+	})
+	if importErr != nil {
+		return nil, importErr
+	}
+
+	Debugf("导入正文内容: %d 个元素，%d 个样式，%d 个编号定义，%d 张图片",
+		len(elements), len(result.Imported), len(numIDs), len(imageIDs))
+	return elements, nil
+}
+
+// copyBodyElements 通过序列化复制段落和表格，避免与原文档共享对象
+// 节属性不会复制，复制出的段落属于本文档
+func (d *Document) copyBodyElements(source []interface{}) ([]interface{}, error) {
+	var buf bytes.Buffer
+	buf.WriteString("<body>")
+	encoder := xml.NewEncoder(&buf)
+	for _, element := range source {
+		if _, ok := element.(*SectionProperties); ok {
+			continue
+		}
+		if err := encoder.Encode(element); err != nil {
+			return nil, WrapError("copy_body", err)
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, WrapError("copy_body", err)
+	}
+	buf.WriteString("</body>")
+	elements, err := d.parseHeaderFooterElements(buf.Bytes())
+	if err != nil {
+		return nil, WrapError("copy_body", err)
+	}
+	return elements, nil
+}
+
+// drawingBlip 返回绘图元素引用的图片，没有图片时返回nil
+func drawingBlip(drawing *DrawingElement) *Blip {
+	if drawing == nil {
+		return nil
+	}
+	var graphic *DrawingGraphic
+	switch {
+	case drawing.Inline != nil:
+		graphic = drawing.Inline.Graphic
+	case drawing.Anchor != nil:
+		graphic = drawing.Anchor.Graphic
+	}
+	if graphic == nil || graphic.GraphicData == nil || graphic.GraphicData.Pic == nil || graphic.GraphicData.Pic.BlipFill == nil {
+		return nil
+	}
+	return graphic.GraphicData.Pic.BlipFill.Blip
+}
+
+// importImage 复制源文档中关系ID对应的图片，返回本文档中的关系ID
+func (d *Document) importImage(src *Document, relationID string) (string, error) {
+	if src.documentRelationships != nil {
+		for _, rel := range src.documentRelationships.Relationships {
+			if rel.ID != relationID || rel.Type != imageRelationshipType {
+				continue
+			}
+			data, ok := src.parts[path.Join("word", rel.Target)]
+			if !ok {
+				break
+			}
+			format, err := detectImageFormat(data)
+			if err != nil {
+				return "", WrapErrorWithContext("import_image", err, rel.Target)
+			}
+			info, err := d.AddImageFromDataWithoutElement(data, path.Base(rel.Target), format, 0, 0, nil)
+			if err != nil {
+				return "", WrapErrorWithContext("import_image", err, rel.Target)
+			}
+			return info.RelationID, nil
+		}
+	}
+	return "", NewValidationError("image_relationship", relationID, "源文档中不存在该图片")
+}
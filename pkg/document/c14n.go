@@ -0,0 +1,315 @@
+// Package document XML规范化（Canonical XML）实现
+//
+// XML数字签名要求对签名信息和被引用的XML元素进行规范化后再计算摘要。
+// 此处实现签名所需的 Canonical XML 1.0 和 Exclusive Canonical XML 1.0
+// （均不含注释），输入为解析后保留了命名空间前缀的元素树。
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// 规范化算法
+const (
+	c14nAlgorithm             = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	c14nWithCommentsAlgorithm = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315#WithComments"
+	excC14NAlgorithm          = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	excC14NWithComments       = "http://www.w3.org/2001/10/xml-exc-c14n#WithComments"
+
+	xmlNamespaceURI = "http://www.w3.org/XML/1998/namespace"
+)
+
+// xmlNode 保留命名空间前缀的XML元素
+type xmlNode struct {
+	prefix   string
+	local    string
+	attrs    []xml.Attr    // 属性（Name.Space 为前缀），包括命名空间声明
+	children []interface{} // *xmlNode 或 string（文本）
+	parent   *xmlNode
+}
+
+// parseXMLTree 将XML解析为元素树，保留命名空间前缀，丢弃注释和处理指令
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *xmlNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{prefix: t.Name.Space, local: t.Name.Local, attrs: t.Copy().Attr, parent: current}
+			if current == nil {
+				if root != nil {
+					return nil, fmt.Errorf("存在多个根元素")
+				}
+				root = node
+			} else {
+				current.children = append(current.children, node)
+			}
+			current = node
+		case xml.EndElement:
+			if current == nil || current.prefix != t.Name.Space || current.local != t.Name.Local {
+				return nil, fmt.Errorf("元素 %s 未正确闭合", t.Name.Local)
+			}
+			current = current.parent
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, string(t))
+			}
+		}
+	}
+	if root == nil || current != nil {
+		return nil, fmt.Errorf("XML不完整")
+	}
+	return root, nil
+}
+
+// qualifiedName 返回元素的限定名
+func (n *xmlNode) qualifiedName() string {
+	if n.prefix == "" {
+		return n.local
+	}
+	return n.prefix + ":" + n.local
+}
+
+// attr 返回指定的无前缀属性值
+func (n *xmlNode) attr(local string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// text 返回元素的文本内容
+func (n *xmlNode) text() string {
+	var sb strings.Builder
+	for _, child := range n.children {
+		switch c := child.(type) {
+		case string:
+			sb.WriteString(c)
+		case *xmlNode:
+			sb.WriteString(c.text())
+		}
+	}
+	return sb.String()
+}
+
+// namespaceURI 返回前缀在当前元素处绑定的命名空间
+func (n *xmlNode) namespaceURI(prefix string) string {
+	if prefix == "xml" {
+		return xmlNamespaceURI
+	}
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			if (prefix == "" && attr.Name.Space == "" && attr.Name.Local == "xmlns") ||
+				(prefix != "" && attr.Name.Space == "xmlns" && attr.Name.Local == prefix) {
+				return attr.Value
+			}
+		}
+	}
+	return ""
+}
+
+// is 检查元素的命名空间和本地名称
+func (n *xmlNode) is(namespace, local string) bool {
+	return n.local == local && n.namespaceURI(n.prefix) == namespace
+}
+
+// child 返回第一个指定名称的子元素
+func (n *xmlNode) child(namespace, local string) *xmlNode {
+	for _, child := range n.children {
+		if c, ok := child.(*xmlNode); ok && c.is(namespace, local) {
+			return c
+		}
+	}
+	return nil
+}
+
+// childElements 返回所有指定名称的子元素
+func (n *xmlNode) childElements(namespace, local string) []*xmlNode {
+	var result []*xmlNode
+	for _, child := range n.children {
+		if c, ok := child.(*xmlNode); ok && c.is(namespace, local) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// findByID 查找Id属性等于id的元素
+func (n *xmlNode) findByID(id string) *xmlNode {
+	if n.attr("Id") == id {
+		return n
+	}
+	for _, child := range n.children {
+		if c, ok := child.(*xmlNode); ok {
+			if found := c.findByID(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// inScopeNamespaces 返回元素处可见的所有命名空间声明（前缀 -> URI）
+func (n *xmlNode) inScopeNamespaces() map[string]string {
+	namespaces := make(map[string]string)
+	for node := n; node != nil; node = node.parent {
+		for _, attr := range node.attrs {
+			var prefix string
+			switch {
+			case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+				prefix = ""
+			case attr.Name.Space == "xmlns":
+				prefix = attr.Name.Local
+			default:
+				continue
+			}
+			if _, exists := namespaces[prefix]; !exists {
+				namespaces[prefix] = attr.Value
+			}
+		}
+	}
+	return namespaces
+}
+
+// canonicalize 按指定算法规范化元素及其子树
+func canonicalize(node *xmlNode, algorithm string) ([]byte, error) {
+	var exclusive bool
+	switch algorithm {
+	case c14nAlgorithm, c14nWithCommentsAlgorithm, "":
+	case excC14NAlgorithm, excC14NWithComments:
+		exclusive = true
+	default:
+		return nil, fmt.Errorf("不支持的规范化算法: %s", algorithm)
+	}
+
+	var buf bytes.Buffer
+	writeCanonical(&buf, node, map[string]string{}, exclusive)
+	return buf.Bytes(), nil
+}
+
+// writeCanonical 输出规范化的元素，rendered 为输出中祖先元素已声明的命名空间
+func writeCanonical(buf *bytes.Buffer, node *xmlNode, rendered map[string]string, exclusive bool) {
+	namespaces := node.inScopeNamespaces()
+
+	// 确定需要输出的命名空间声明
+	candidates := make([]string, 0, len(namespaces))
+	if exclusive {
+		// 仅输出元素和属性实际使用的前缀
+		used := map[string]bool{node.prefix: true}
+		for _, attr := range node.attrs {
+			if attr.Name.Space != "" && attr.Name.Space != "xmlns" && attr.Name.Space != "xml" {
+				used[attr.Name.Space] = true
+			}
+		}
+		for prefix := range used {
+			candidates = append(candidates, prefix)
+		}
+	} else {
+		for prefix := range namespaces {
+			candidates = append(candidates, prefix)
+		}
+	}
+	sort.Strings(candidates)
+
+	current := make(map[string]string, len(rendered))
+	for prefix, uri := range rendered {
+		current[prefix] = uri
+	}
+	var nsDecls []xml.Attr
+	for _, prefix := range candidates {
+		uri := namespaces[prefix]
+		previous, exists := rendered[prefix]
+		if prefix == "" && uri == "" {
+			// 空默认命名空间仅在需要取消父元素的默认命名空间时输出
+			if !exists || previous == "" {
+				continue
+			}
+		} else if exists && previous == uri {
+			continue
+		}
+		current[prefix] = uri
+		nsDecls = append(nsDecls, xml.Attr{Name: xml.Name{Local: prefix}, Value: uri})
+	}
+
+	// 普通属性按命名空间URI和本地名称排序
+	type canonicalAttr struct {
+		uri   string
+		name  string
+		local string
+		value string
+	}
+	var attrs []canonicalAttr
+	for _, attr := range node.attrs {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		name := attr.Name.Local
+		uri := ""
+		if attr.Name.Space != "" {
+			name = attr.Name.Space + ":" + attr.Name.Local
+			uri = node.namespaceURI(attr.Name.Space)
+		}
+		attrs = append(attrs, canonicalAttr{uri: uri, name: name, local: attr.Name.Local, value: attr.Value})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].uri != attrs[j].uri {
+			return attrs[i].uri < attrs[j].uri
+		}
+		return attrs[i].local < attrs[j].local
+	})
+
+	buf.WriteByte('<')
+	buf.WriteString(node.qualifiedName())
+	for _, ns := range nsDecls {
+		if ns.Name.Local == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + ns.Name.Local + `="`)
+		}
+		buf.WriteString(escapeCanonicalAttr(ns.Value))
+		buf.WriteByte('"')
+	}
+	for _, attr := range attrs {
+		buf.WriteString(" " + attr.name + `="`)
+		buf.WriteString(escapeCanonicalAttr(attr.value))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('>')
+
+	for _, child := range node.children {
+		switch c := child.(type) {
+		case string:
+			buf.WriteString(escapeCanonicalText(c))
+		case *xmlNode:
+			writeCanonical(buf, c, current, exclusive)
+		}
+	}
+
+	buf.WriteString("</" + node.qualifiedName() + ">")
+}
+
+// escapeCanonicalText 按规范化规则转义文本
+func escapeCanonicalText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;").Replace(s)
+}
+
+// escapeCanonicalAttr 按规范化规则转义属性值
+func escapeCanonicalAttr(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;",
+		"\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;").Replace(s)
+}
@@ -0,0 +1,634 @@
+// Package document OLE复合文件（Compound File Binary）读写实现
+//
+// 加密的Word文档不是ZIP包，而是OLE复合文件，其中的 EncryptionInfo 和
+// EncryptedPackage 流保存加密信息和加密后的ZIP包。此处仅实现读写加密文档
+// 所需的部分：按路径读取全部流，以及写入版本3（512字节扇区）的复合文件。
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// 复合文件常量
+const (
+	cfbHeaderSize       = 512
+	cfbSectorSize       = 512
+	cfbMiniSectorSize   = 64
+	cfbMiniStreamCutoff = 4096
+	cfbDirEntrySize     = 128
+	cfbHeaderDIFATCount = 109
+
+	cfbDIFSECT    = 0xFFFFFFFC // DIFAT扇区
+	cfbFATSECT    = 0xFFFFFFFD // FAT扇区
+	cfbENDOFCHAIN = 0xFFFFFFFE // 扇区链结束
+	cfbFREESECT   = 0xFFFFFFFF // 未使用扇区
+	cfbNOSTREAM   = 0xFFFFFFFF // 目录项不存在
+
+	cfbTypeStorage = 1
+	cfbTypeStream  = 2
+	cfbTypeRoot    = 5
+
+	cfbColorRed   = 0
+	cfbColorBlack = 1
+)
+
+// cfbSignature 复合文件签名
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// isCompoundFile 检查数据是否为OLE复合文件
+func isCompoundFile(data []byte) bool {
+	return len(data) >= cfbHeaderSize && bytes.Equal(data[:len(cfbSignature)], cfbSignature)
+}
+
+// cfbDirEntry 复合文件目录项
+type cfbDirEntry struct {
+	name        string
+	objectType  byte
+	left        uint32
+	right       uint32
+	child       uint32
+	startSector uint32
+	size        uint64
+}
+
+// cfbReader 复合文件读取器
+type cfbReader struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	miniCutoff     uint64
+	fat            []uint32
+	miniFAT        []uint32
+	miniStream     []byte
+	entries        []cfbDirEntry
+}
+
+// readCompoundFile 读取复合文件中的全部流
+// 返回以路径为键的流数据，存储与流名称之间用 "/" 分隔
+func readCompoundFile(data []byte) (map[string][]byte, error) {
+	if !isCompoundFile(data) {
+		return nil, fmt.Errorf("不是有效的复合文件")
+	}
+
+	sectorShift := binary.LittleEndian.Uint16(data[30:])
+	miniSectorShift := binary.LittleEndian.Uint16(data[32:])
+	if (sectorShift != 9 && sectorShift != 12) || miniSectorShift != 6 {
+		return nil, fmt.Errorf("不支持的扇区大小")
+	}
+	r := &cfbReader{
+		data:           data,
+		sectorSize:     1 << sectorShift,
+		miniSectorSize: 1 << miniSectorShift,
+		miniCutoff:     uint64(binary.LittleEndian.Uint32(data[56:])),
+	}
+
+	if err := r.readFAT(); err != nil {
+		return nil, err
+	}
+
+	dirData, err := r.readChain(binary.LittleEndian.Uint32(data[48:]))
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+	majorVersion := binary.LittleEndian.Uint16(data[26:])
+	for offset := 0; offset+cfbDirEntrySize <= len(dirData); offset += cfbDirEntrySize {
+		r.entries = append(r.entries, parseCFBDirEntry(dirData[offset:offset+cfbDirEntrySize], majorVersion))
+	}
+	if len(r.entries) == 0 || r.entries[0].objectType != cfbTypeRoot {
+		return nil, fmt.Errorf("缺少根目录项")
+	}
+
+	if firstMiniFAT := binary.LittleEndian.Uint32(data[60:]); firstMiniFAT != cfbENDOFCHAIN {
+		miniFATData, err := r.readChain(firstMiniFAT)
+		if err != nil {
+			return nil, fmt.Errorf("读取MiniFAT失败: %w", err)
+		}
+		r.miniFAT = bytesToUint32s(miniFATData)
+	}
+	root := r.entries[0]
+	if root.startSector != cfbENDOFCHAIN && root.size > 0 {
+		miniStream, err := r.readChain(root.startSector)
+		if err != nil {
+			return nil, fmt.Errorf("读取Mini流失败: %w", err)
+		}
+		if uint64(len(miniStream)) > root.size {
+			miniStream = miniStream[:root.size]
+		}
+		r.miniStream = miniStream
+	}
+
+	streams := make(map[string][]byte)
+	visited := make(map[uint32]bool)
+	if err := r.walk(root.child, "", streams, visited); err != nil {
+		return nil, err
+	}
+	return streams, nil
+}
+
+// readFAT 读取FAT（包括DIFAT扇区中记录的FAT扇区）
+func (r *cfbReader) readFAT() error {
+	numFATSectors := int(binary.LittleEndian.Uint32(r.data[44:]))
+	fatSectors := make([]uint32, 0, numFATSectors)
+	for i := 0; i < cfbHeaderDIFATCount && len(fatSectors) < numFATSectors; i++ {
+		fatSectors = append(fatSectors, binary.LittleEndian.Uint32(r.data[76+i*4:]))
+	}
+
+	difatSector := binary.LittleEndian.Uint32(r.data[68:])
+	perSector := r.sectorSize/4 - 1
+	for guard := 0; difatSector != cfbENDOFCHAIN && difatSector != cfbFREESECT && len(fatSectors) < numFATSectors; guard++ {
+		if guard > numFATSectors {
+			return fmt.Errorf("DIFAT扇区链存在循环")
+		}
+		sector, err := r.sector(difatSector)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < perSector && len(fatSectors) < numFATSectors; i++ {
+			fatSectors = append(fatSectors, binary.LittleEndian.Uint32(sector[i*4:]))
+		}
+		difatSector = binary.LittleEndian.Uint32(sector[perSector*4:])
+	}
+
+	for _, id := range fatSectors {
+		sector, err := r.sector(id)
+		if err != nil {
+			return fmt.Errorf("读取FAT失败: %w", err)
+		}
+		r.fat = append(r.fat, bytesToUint32s(sector)...)
+	}
+	return nil
+}
+
+// sector 返回指定扇区的数据
+func (r *cfbReader) sector(id uint32) ([]byte, error) {
+	offset := (int64(id) + 1) * int64(r.sectorSize)
+	if id >= cfbDIFSECT || offset+int64(r.sectorSize) > int64(len(r.data)) {
+		return nil, fmt.Errorf("扇区 %d 超出文件范围", id)
+	}
+	return r.data[offset : offset+int64(r.sectorSize)], nil
+}
+
+// readChain 读取从start开始的扇区链
+func (r *cfbReader) readChain(start uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	for id, count := start, 0; id != cfbENDOFCHAIN; count++ {
+		if count > len(r.fat) || int(id) >= len(r.fat) {
+			return nil, fmt.Errorf("扇区链无效")
+		}
+		sector, err := r.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sector)
+		id = r.fat[id]
+	}
+	return buf.Bytes(), nil
+}
+
+// readMiniChain 读取Mini流中从start开始的Mini扇区链
+func (r *cfbReader) readMiniChain(start uint32, size uint64) ([]byte, error) {
+	var buf bytes.Buffer
+	for id, count := start, 0; id != cfbENDOFCHAIN && uint64(buf.Len()) < size; count++ {
+		offset := int(id) * r.miniSectorSize
+		if count > len(r.miniFAT) || int(id) >= len(r.miniFAT) || offset+r.miniSectorSize > len(r.miniStream) {
+			return nil, fmt.Errorf("Mini扇区链无效")
+		}
+		buf.Write(r.miniStream[offset : offset+r.miniSectorSize])
+		id = r.miniFAT[id]
+	}
+	return buf.Bytes(), nil
+}
+
+// streamData 读取流目录项的数据
+func (r *cfbReader) streamData(entry cfbDirEntry) ([]byte, error) {
+	if entry.size == 0 {
+		return []byte{}, nil
+	}
+
+	var data []byte
+	var err error
+	if entry.size < r.miniCutoff {
+		data, err = r.readMiniChain(entry.startSector, entry.size)
+	} else {
+		data, err = r.readChain(entry.startSector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) < entry.size {
+		return nil, fmt.Errorf("流 %s 数据不完整", entry.name)
+	}
+	return data[:entry.size], nil
+}
+
+// walk 中序遍历目录项的红黑树，收集其中的流
+func (r *cfbReader) walk(id uint32, prefix string, streams map[string][]byte, visited map[uint32]bool) error {
+	if id == cfbNOSTREAM {
+		return nil
+	}
+	if int(id) >= len(r.entries) || visited[id] {
+		return fmt.Errorf("目录结构无效")
+	}
+	visited[id] = true
+	entry := r.entries[id]
+
+	if err := r.walk(entry.left, prefix, streams, visited); err != nil {
+		return err
+	}
+	switch entry.objectType {
+	case cfbTypeStream:
+		data, err := r.streamData(entry)
+		if err != nil {
+			return err
+		}
+		streams[prefix+entry.name] = data
+	case cfbTypeStorage:
+		if err := r.walk(entry.child, prefix+entry.name+"/", streams, visited); err != nil {
+			return err
+		}
+	}
+	return r.walk(entry.right, prefix, streams, visited)
+}
+
+// parseCFBDirEntry 解析目录项
+func parseCFBDirEntry(data []byte, majorVersion uint16) cfbDirEntry {
+	nameLength := int(binary.LittleEndian.Uint16(data[64:]))
+	if nameLength > 64 {
+		nameLength = 64
+	}
+	units := make([]uint16, 0, nameLength/2)
+	for i := 0; i+1 < nameLength; i += 2 {
+		unit := binary.LittleEndian.Uint16(data[i:])
+		if unit == 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+
+	size := binary.LittleEndian.Uint64(data[120:])
+	if majorVersion == 3 {
+		// 版本3的文件中高32位可能未初始化
+		size &= 0xFFFFFFFF
+	}
+	return cfbDirEntry{
+		name:        string(utf16.Decode(units)),
+		objectType:  data[66],
+		left:        binary.LittleEndian.Uint32(data[68:]),
+		right:       binary.LittleEndian.Uint32(data[72:]),
+		child:       binary.LittleEndian.Uint32(data[76:]),
+		startSector: binary.LittleEndian.Uint32(data[116:]),
+		size:        size,
+	}
+}
+
+// bytesToUint32s 将字节序列按小端转换为uint32数组
+func bytesToUint32s(data []byte) []uint32 {
+	values := make([]uint32, len(data)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+	return values
+}
+
+// cfbStream 待写入复合文件的流
+type cfbStream struct {
+	Path string // 流路径，存储之间用 "/" 分隔
+	Data []byte
+}
+
+// cfbNode 写入时的目录树节点
+type cfbNode struct {
+	name       string
+	objectType byte
+	data       []byte
+	children   []*cfbNode
+
+	id          uint32
+	left        uint32
+	right       uint32
+	child       uint32
+	color       byte
+	startSector uint32
+	size        uint64
+}
+
+// cfbWriter 复合文件写入器
+type cfbWriter struct {
+	fat     []uint32
+	sectors bytes.Buffer
+}
+
+// allocate 为数据分配连续的扇区，返回起始扇区
+func (w *cfbWriter) allocate(data []byte) uint32 {
+	if len(data) == 0 {
+		return cfbENDOFCHAIN
+	}
+	start := uint32(len(w.fat))
+	count := (len(data) + cfbSectorSize - 1) / cfbSectorSize
+	for i := 0; i < count; i++ {
+		next := start + uint32(i) + 1
+		if i == count-1 {
+			next = cfbENDOFCHAIN
+		}
+		w.fat = append(w.fat, next)
+	}
+	w.sectors.Write(data)
+	if pad := count*cfbSectorSize - len(data); pad > 0 {
+		w.sectors.Write(make([]byte, pad))
+	}
+	return start
+}
+
+// writeCompoundFile 将流写入版本3的OLE复合文件
+func writeCompoundFile(streams []cfbStream) ([]byte, error) {
+	root := &cfbNode{name: "Root Entry", objectType: cfbTypeRoot}
+	for _, stream := range streams {
+		if err := addCFBStream(root, stream); err != nil {
+			return nil, err
+		}
+	}
+
+	// 按深度优先顺序分配目录项ID
+	var nodes []*cfbNode
+	var collect func(node *cfbNode)
+	collect = func(node *cfbNode) {
+		node.id = uint32(len(nodes))
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			collect(child)
+		}
+	}
+	collect(root)
+	for _, node := range nodes {
+		node.left, node.right, node.child = cfbNOSTREAM, cfbNOSTREAM, cfbNOSTREAM
+		node.color = cfbColorBlack
+	}
+	for _, node := range nodes {
+		node.child = buildCFBTree(node.children)
+	}
+
+	w := &cfbWriter{}
+
+	// 大于等于阈值的流直接存放在扇区中，其余的流存放在Mini流中
+	var miniStream bytes.Buffer
+	var miniFAT []uint32
+	for _, node := range nodes {
+		if node.objectType != cfbTypeStream {
+			continue
+		}
+		node.size = uint64(len(node.data))
+		node.startSector = cfbENDOFCHAIN
+		if len(node.data) >= cfbMiniStreamCutoff {
+			node.startSector = w.allocate(node.data)
+			continue
+		}
+		if len(node.data) == 0 {
+			continue
+		}
+		node.startSector = uint32(len(miniFAT))
+		count := (len(node.data) + cfbMiniSectorSize - 1) / cfbMiniSectorSize
+		for i := 0; i < count; i++ {
+			next := node.startSector + uint32(i) + 1
+			if i == count-1 {
+				next = cfbENDOFCHAIN
+			}
+			miniFAT = append(miniFAT, next)
+		}
+		miniStream.Write(node.data)
+		miniStream.Write(make([]byte, count*cfbMiniSectorSize-len(node.data)))
+	}
+
+	root.startSector = w.allocate(miniStream.Bytes())
+	root.size = uint64(miniStream.Len())
+
+	firstMiniFAT := uint32(cfbENDOFCHAIN)
+	numMiniFAT := 0
+	if len(miniFAT) > 0 {
+		numMiniFAT = (len(miniFAT)*4 + cfbSectorSize - 1) / cfbSectorSize
+		firstMiniFAT = w.allocate(uint32sToBytes(miniFAT, numMiniFAT*cfbSectorSize, cfbFREESECT))
+	}
+
+	var dir bytes.Buffer
+	for _, node := range nodes {
+		dir.Write(marshalCFBDirEntry(node))
+	}
+	for dir.Len()%cfbSectorSize != 0 {
+		dir.Write(marshalCFBDirEntry(nil))
+	}
+	firstDir := w.allocate(dir.Bytes())
+
+	// 计算FAT扇区和DIFAT扇区数量（二者本身也占用FAT表项）
+	entriesPerSector := cfbSectorSize / 4
+	numFAT, numDIFAT := 0, 0
+	for {
+		total := len(w.fat) + numFAT + numDIFAT
+		needFAT := (total + entriesPerSector - 1) / entriesPerSector
+		needDIFAT := 0
+		if needFAT > cfbHeaderDIFATCount {
+			needDIFAT = (needFAT - cfbHeaderDIFATCount + entriesPerSector - 2) / (entriesPerSector - 1)
+		}
+		if needFAT == numFAT && needDIFAT == numDIFAT {
+			break
+		}
+		numFAT, numDIFAT = needFAT, needDIFAT
+	}
+
+	fatStart := uint32(len(w.fat))
+	fatSectors := make([]uint32, numFAT)
+	for i := range fatSectors {
+		fatSectors[i] = fatStart + uint32(i)
+		w.fat = append(w.fat, cfbFATSECT)
+	}
+	difatStart := uint32(len(w.fat))
+	for i := 0; i < numDIFAT; i++ {
+		w.fat = append(w.fat, cfbDIFSECT)
+	}
+
+	// 写入文件头
+	header := make([]byte, cfbHeaderSize)
+	copy(header, cfbSignature)
+	binary.LittleEndian.PutUint16(header[24:], 0x003E)
+	binary.LittleEndian.PutUint16(header[26:], 0x0003)
+	binary.LittleEndian.PutUint16(header[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[30:], 9)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], uint32(numFAT))
+	binary.LittleEndian.PutUint32(header[48:], firstDir)
+	binary.LittleEndian.PutUint32(header[56:], cfbMiniStreamCutoff)
+	binary.LittleEndian.PutUint32(header[60:], firstMiniFAT)
+	binary.LittleEndian.PutUint32(header[64:], uint32(numMiniFAT))
+	binary.LittleEndian.PutUint32(header[68:], cfbENDOFCHAIN)
+	if numDIFAT > 0 {
+		binary.LittleEndian.PutUint32(header[68:], difatStart)
+	}
+	binary.LittleEndian.PutUint32(header[72:], uint32(numDIFAT))
+	for i := 0; i < cfbHeaderDIFATCount; i++ {
+		value := uint32(cfbFREESECT)
+		if i < numFAT {
+			value = fatSectors[i]
+		}
+		binary.LittleEndian.PutUint32(header[76+i*4:], value)
+	}
+
+	var out bytes.Buffer
+	out.Write(header)
+	out.Write(w.sectors.Bytes())
+	out.Write(uint32sToBytes(w.fat, numFAT*cfbSectorSize, cfbFREESECT))
+
+	// 写入DIFAT扇区
+	remaining := fatSectors
+	if len(remaining) > cfbHeaderDIFATCount {
+		remaining = remaining[cfbHeaderDIFATCount:]
+	} else {
+		remaining = nil
+	}
+	for i := 0; i < numDIFAT; i++ {
+		values := make([]uint32, entriesPerSector)
+		for j := range values {
+			values[j] = cfbFREESECT
+		}
+		n := copy(values[:entriesPerSector-1], remaining)
+		remaining = remaining[n:]
+		values[entriesPerSector-1] = cfbENDOFCHAIN
+		if i < numDIFAT-1 {
+			values[entriesPerSector-1] = difatStart + uint32(i) + 1
+		}
+		out.Write(uint32sToBytes(values, cfbSectorSize, cfbFREESECT))
+	}
+
+	return out.Bytes(), nil
+}
+
+// addCFBStream 按路径将流加入目录树，自动创建中间存储
+func addCFBStream(root *cfbNode, stream cfbStream) error {
+	parts := strings.Split(stream.Path, "/")
+	parent := root
+	for i, part := range parts {
+		if part == "" || len(utf16.Encode([]rune(part))) > 31 {
+			return fmt.Errorf("无效的流名称: %s", stream.Path)
+		}
+
+		var existing *cfbNode
+		for _, child := range parent.children {
+			if strings.EqualFold(child.name, part) {
+				existing = child
+				break
+			}
+		}
+
+		if i == len(parts)-1 {
+			if existing != nil {
+				return fmt.Errorf("重复的流名称: %s", stream.Path)
+			}
+			parent.children = append(parent.children, &cfbNode{name: part, objectType: cfbTypeStream, data: stream.Data})
+			return nil
+		}
+		if existing == nil {
+			existing = &cfbNode{name: part, objectType: cfbTypeStorage}
+			parent.children = append(parent.children, existing)
+		} else if existing.objectType != cfbTypeStorage {
+			return fmt.Errorf("流名称与存储冲突: %s", stream.Path)
+		}
+		parent = existing
+	}
+	return nil
+}
+
+// buildCFBTree 将同级目录项组织为红黑树，返回根节点ID
+//
+// 按复合文件的比较规则排序后取中位数递归建树，得到各层（最后一层除外）
+// 都是满的平衡二叉树；最后一层不满时将其染为红色，其余为黑色，满足红黑树性质。
+func buildCFBTree(children []*cfbNode) uint32 {
+	if len(children) == 0 {
+		return cfbNOSTREAM
+	}
+
+	sorted := make([]*cfbNode, len(children))
+	copy(sorted, children)
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareCFBNames(sorted[i].name, sorted[j].name) < 0
+	})
+
+	height := bits.Len(uint(len(sorted)))
+	lastLevelFull := len(sorted) == 1<<height-1
+
+	var build func(nodes []*cfbNode, depth int) uint32
+	build = func(nodes []*cfbNode, depth int) uint32 {
+		if len(nodes) == 0 {
+			return cfbNOSTREAM
+		}
+		mid := len(nodes) / 2
+		node := nodes[mid]
+		node.left = build(nodes[:mid], depth+1)
+		node.right = build(nodes[mid+1:], depth+1)
+		if depth == height-1 && !lastLevelFull {
+			node.color = cfbColorRed
+		}
+		return node.id
+	}
+	return build(sorted, 0)
+}
+
+// compareCFBNames 按复合文件规则比较目录项名称：先比较长度，再按大写比较
+func compareCFBNames(a, b string) int {
+	ua := utf16.Encode([]rune(strings.ToUpper(a)))
+	ub := utf16.Encode([]rune(strings.ToUpper(b)))
+	if len(ua) != len(ub) {
+		return len(ua) - len(ub)
+	}
+	for i := range ua {
+		if ua[i] != ub[i] {
+			return int(ua[i]) - int(ub[i])
+		}
+	}
+	return 0
+}
+
+// marshalCFBDirEntry 序列化目录项，node为nil时生成空目录项
+func marshalCFBDirEntry(node *cfbNode) []byte {
+	data := make([]byte, cfbDirEntrySize)
+	if node == nil {
+		binary.LittleEndian.PutUint32(data[68:], cfbNOSTREAM)
+		binary.LittleEndian.PutUint32(data[72:], cfbNOSTREAM)
+		binary.LittleEndian.PutUint32(data[76:], cfbNOSTREAM)
+		return data
+	}
+
+	units := utf16.Encode([]rune(node.name))
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(data[i*2:], unit)
+	}
+	binary.LittleEndian.PutUint16(data[64:], uint16((len(units)+1)*2))
+	data[66] = node.objectType
+	data[67] = node.color
+	binary.LittleEndian.PutUint32(data[68:], node.left)
+	binary.LittleEndian.PutUint32(data[72:], node.right)
+	binary.LittleEndian.PutUint32(data[76:], node.child)
+	if node.objectType != cfbTypeStorage {
+		binary.LittleEndian.PutUint32(data[116:], node.startSector)
+		binary.LittleEndian.PutUint64(data[120:], node.size)
+	}
+	return data
+}
+
+// uint32sToBytes 将uint32数组按小端序列化，并用fill填充到size字节
+func uint32sToBytes(values []uint32, size int, fill uint32) []byte {
+	if size < len(values)*4 {
+		size = len(values) * 4
+	}
+	data := make([]byte, size)
+	for i := 0; i*4 < size; i++ {
+		value := fill
+		if i < len(values) {
+			value = values[i]
+		}
+		binary.LittleEndian.PutUint32(data[i*4:], value)
+	}
+	return data
+}
@@ -31,6 +31,17 @@ type Document struct {
 	parts map[string][]byte
 	// 图片ID计数器，确保每个图片都有唯一的ID
 	nextImageID int
+	// 编号管理器（列表和标题编号定义）
+	numberingManager *NumberingManager
+	// 文档设置（首次访问时从settings.xml解析）
+	settings *Settings
+	// 已加载或编辑过的页眉页脚（部件名称 -> 对象），保存时重新序列化
+	headers map[string]*Header
+	footers map[string]*Footer
+	// 页面背景
+	background *DocumentBackground
+	// 字体表（首次访问时从fontTable.xml解析）
+	fonts *fontTable
 }
 
 // Body 表示文档主体
@@ -93,9 +104,44 @@ func (t *Table) ElementType() string {
 
 // Paragraph 表示一个段落
 type Paragraph struct {
-	XMLName    xml.Name             `xml:"w:p"`
-	Properties *ParagraphProperties `xml:"w:pPr,omitempty"`
-	Runs       []Run                `xml:"w:r"`
+	XMLName     xml.Name             `xml:"w:p"`
+	Properties  *ParagraphProperties `xml:"w:pPr,omitempty"`
+	Runs        []Run                `xml:"w:r"`
+	Permissions []*PermissionMark    `xml:"-"` // 可编辑区域标记（w:permStart/w:permEnd）
+
+	doc *Document // 所属文档，用于应用样式时校验和补充样式
+}
+
+// MarshalXML 自定义段落的XML序列化
+// 可编辑区域标记按其位置穿插在Run之间输出
+func (p *Paragraph) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "w:p"}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if p.Properties != nil {
+		if err := e.EncodeElement(p.Properties, xml.StartElement{Name: xml.Name{Local: "w:pPr"}}); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i <= len(p.Runs); i++ {
+		for _, mark := range p.Permissions {
+			if mark.Position == i || (i == len(p.Runs) && mark.Position > i) {
+				if err := mark.marshal(e); err != nil {
+					return err
+				}
+			}
+		}
+		if i < len(p.Runs) {
+			if err := e.EncodeElement(&p.Runs[i], xml.StartElement{Name: xml.Name{Local: "w:r"}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
 // ParagraphProperties 段落属性
@@ -114,6 +160,7 @@ type ParagraphProperties struct {
 	PageBreakBefore     *PageBreakBefore     `xml:"w:pageBreakBefore,omitempty"` // 段前分页
 	WidowControl        *WidowControl        `xml:"w:widowControl,omitempty"`    // 孤行控制
 	OutlineLevel        *OutlineLevel        `xml:"w:outlineLvl,omitempty"`      // 大纲级别
+	SectionProperties   *SectionProperties   `xml:"w:sectPr,omitempty"`          // 段落级节属性（节在此段落处结束）
 }
 
 // SnapToGrid 网格对齐设置
@@ -195,6 +242,9 @@ type Run struct {
 	Drawing    *DrawingElement `xml:"w:drawing,omitempty"`
 	FieldChar  *FieldChar      `xml:"w:fldChar,omitempty"`
 	InstrText  *InstrText      `xml:"w:instrText,omitempty"`
+	Pict       *Pict           `xml:"w:pict,omitempty"` // VML图形（水印等）
+
+	doc *Document // 所属文档，用于应用样式时校验和补充样式
 }
 
 // MarshalXML 自定义Run的XML序列化
@@ -248,14 +298,28 @@ func (r *Run) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		}
 	}
 
+	// 序列化VML图形（如果存在）
+	if r.Pict != nil {
+		if err := e.EncodeElement(r.Pict, xml.StartElement{Name: xml.Name{Local: "w:pict"}}); err != nil {
+			return err
+		}
+	}
+
 	// 结束Run元素
 	return e.EncodeToken(xml.EndElement{Name: start.Name})
 }
 
+// Pict VML图形（w:pict），如水印和旧版文本框
+// 内容以原始XML保存，序列化时原样输出
+type Pict struct {
+	Content []byte `xml:",innerxml"`
+}
+
 // RunProperties 文本属性
 // 注意：字段顺序必须符合OpenXML标准，w:rFonts必须在w:color之前
 type RunProperties struct {
 	XMLName    xml.Name    `xml:"w:rPr"`
+	RunStyle   *RunStyle   `xml:"w:rStyle,omitempty"` // 字符样式引用
 	FontFamily *FontFamily `xml:"w:rFonts,omitempty"`
 	Bold       *Bold       `xml:"w:b,omitempty"`
 	BoldCs     *BoldCs     `xml:"w:bCs,omitempty"`
@@ -269,9 +333,16 @@ type RunProperties struct {
 	Highlight  *Highlight  `xml:"w:highlight,omitempty"`
 }
 
-// Bold 粗体
+// RunStyle 字符样式引用
+type RunStyle struct {
+	XMLName xml.Name `xml:"w:rStyle"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Bold 粗体（切换属性），Val 为 "0"/"false" 时表示关闭
 type Bold struct {
 	XMLName xml.Name `xml:"w:b"`
+	Val     string   `xml:"w:val,attr,omitempty"`
 }
 
 // BoldCs 复杂脚本粗体
@@ -279,9 +350,10 @@ type BoldCs struct {
 	XMLName xml.Name `xml:"w:bCs"`
 }
 
-// Italic 斜体
+// Italic 斜体（切换属性），Val 为 "0"/"false" 时表示关闭
 type Italic struct {
 	XMLName xml.Name `xml:"w:i"`
+	Val     string   `xml:"w:val,attr,omitempty"`
 }
 
 // ItalicCs 复杂脚本斜体
@@ -314,8 +386,11 @@ type FontSizeCs struct {
 
 // Color 颜色
 type Color struct {
-	XMLName xml.Name `xml:"w:color"`
-	Val     string   `xml:"w:val,attr"`
+	XMLName    xml.Name `xml:"w:color"`
+	Val        string   `xml:"w:val,attr"`
+	ThemeColor string   `xml:"w:themeColor,attr,omitempty"` // 主题颜色（如 accent1、text1），Val 为其回退值
+	ThemeTint  string   `xml:"w:themeTint,attr,omitempty"`  // 主题颜色淡化（十六进制 00-FF）
+	ThemeShade string   `xml:"w:themeShade,attr,omitempty"` // 主题颜色加深（十六进制 00-FF）
 }
 
 // Highlight 背景色
@@ -335,7 +410,7 @@ type Text struct {
 // Break represents page breaks in Word documents
 type Break struct {
 	XMLName xml.Name `xml:"w:br"`
-	Type    string   `xml:"w:type,attr,omitempty"` // "page" 表示分页符 / "page" indicates a page break
+	Type    string   `xml:"w:type,attr,omitempty"` // "page" 表示分页符，"column" 表示分栏符，为空时为换行符 / "page" indicates a page break
 }
 
 // Relationships 文档关系
@@ -380,6 +455,12 @@ type FontFamily struct {
 	EastAsia string   `xml:"w:eastAsia,attr,omitempty"`
 	CS       string   `xml:"w:cs,attr,omitempty"`
 	Hint     string   `xml:"w:hint,attr,omitempty"`
+
+	// 主题字体引用（如 minorHAnsi、majorEastAsia），优先于上面的字体名称
+	ASCIITheme    string `xml:"w:asciiTheme,attr,omitempty"`
+	HAnsiTheme    string `xml:"w:hAnsiTheme,attr,omitempty"`
+	EastAsiaTheme string `xml:"w:eastAsiaTheme,attr,omitempty"`
+	CSTheme       string `xml:"w:cstheme,attr,omitempty"`
 }
 
 // TextFormat 文本格式配置
@@ -393,6 +474,8 @@ type TextFormat struct {
 	Underline  bool   // 是否下划线
 	Strike     bool   // 删除线
 	Highlight  string //高亮颜色
+	ThemeFont  string // 主题字体（ThemeFontMajor 标题字体、ThemeFontMinor 正文字体）
+	ThemeColor string // 主题颜色（如 "accent1"），FontColor 为空时使用默认主题的颜色作为回退值
 }
 
 // AlignmentType 对齐类型
@@ -632,6 +715,11 @@ func openFromZipReader(zipReader *zip.Reader, filename string) (*Document, error
 		doc.styleManager = style.NewStyleManager()
 	}
 
+	// 解析编号定义，使新增列表不与已有编号冲突
+	if err := doc.parseNumbering(); err != nil {
+		Debugf("解析编号定义失败，编号管理器将从空白开始: %v", err)
+	}
+
 	// 解析文档关系（包括图片等资源的关系）
 	if err := doc.parseDocumentRelationships(); err != nil {
 		Debugf("解析文档关系失败，使用默认值: %v", err)
@@ -701,6 +789,24 @@ func (d *Document) Save(filename string) error {
 		return WrapError("serialize_styles", err)
 	}
 
+	// 序列化文档设置
+	if err := d.serializeSettings(); err != nil {
+		Errorf("序列化文档设置失败")
+		return err
+	}
+
+	// 序列化页眉页脚
+	if err := d.serializeHeaderFooters(); err != nil {
+		Errorf("序列化页眉页脚失败")
+		return err
+	}
+
+	// 序列化字体表及嵌入字体
+	if err := d.serializeFontTable(); err != nil {
+		Errorf("序列化字体表失败")
+		return err
+	}
+
 	// 序列化内容类型
 	d.serializeContentTypes()
 
@@ -763,7 +869,7 @@ func (d *Document) AddParagraph(text string) *Paragraph {
 		},
 	}
 
-	d.Body.Elements = append(d.Body.Elements, p)
+	d.Body.Elements = append(d.Body.Elements, d.bindParagraph(p))
 	return p
 }
 
@@ -825,6 +931,7 @@ func (d *Document) AddFormattedParagraph(text string, format *TextFormat) *Parag
 			color := strings.TrimPrefix(format.FontColor, "#")
 			runProps.Color = &Color{Val: color}
 		}
+		applyThemeFormat(runProps, format)
 
 		if format.FontSize > 0 {
 			// Word中字体大小是半磅为单位，所以需要乘以2
@@ -855,7 +962,7 @@ func (d *Document) AddFormattedParagraph(text string, format *TextFormat) *Parag
 		},
 	}
 
-	d.Body.Elements = append(d.Body.Elements, p)
+	d.Body.Elements = append(d.Body.Elements, d.bindParagraph(p))
 	return p
 }
 
@@ -1008,6 +1115,7 @@ func (p *Paragraph) AddFormattedText(text string, format *TextFormat) {
 			color := strings.TrimPrefix(format.FontColor, "#")
 			runProps.Color = &Color{Val: color}
 		}
+		applyThemeFormat(runProps, format)
 
 		if format.FontSize > 0 {
 			runProps.FontSize = &FontSize{Val: strconv.Itoa(format.FontSize * 2)}
@@ -1031,6 +1139,7 @@ func (p *Paragraph) AddFormattedText(text string, format *TextFormat) {
 			Content: text,
 			Space:   "preserve",
 		},
+		doc: p.doc,
 	}
 
 	p.Runs = append(p.Runs, run)
@@ -1112,8 +1221,12 @@ func (d *Document) AddHeadingParagraphWithBookmark(text string, level int, bookm
 	styleID := fmt.Sprintf("Heading%d", level)
 	Debugf("添加标题段落: %s (级别: %d, 样式: %s, 书签: %s)", text, level, styleID, bookmarkName)
 
-	// 获取样式管理器中的样式
+	// 获取样式管理器中的样式，导入参考文档后标题样式可能使用其他ID（如中文版Word的 "1"）
 	headingStyle := d.styleManager.GetStyle(styleID)
+	if headingStyle == nil {
+		styleID = d.headingStyleIDs()[level-1]
+		headingStyle = d.styleManager.GetStyle(styleID)
+	}
 	if headingStyle == nil {
 		Debugf("警告：找不到样式 %s，使用默认样式", styleID)
 		return d.AddParagraph(text)
@@ -1199,7 +1312,7 @@ func (d *Document) AddHeadingParagraphWithBookmark(text string, level int, bookm
 		Runs:       runs,
 	}
 
-	d.Body.Elements = append(d.Body.Elements, p)
+	d.Body.Elements = append(d.Body.Elements, d.bindParagraph(p))
 
 	// 如果需要添加书签，在段落结束后添加书签结束标记
 	if bookmarkName != "" {
@@ -1241,25 +1354,25 @@ func (d *Document) AddPageBreak() {
 		},
 	}
 
-	d.Body.Elements = append(d.Body.Elements, p)
+	d.Body.Elements = append(d.Body.Elements, d.bindParagraph(p))
 }
 
 // SetStyle 设置段落的样式。
 //
 // 参数 styleID 是要应用的样式ID，如 "Heading1"、"Normal" 等。
 // 此方法会设置段落的样式引用，确保段落使用指定的样式。
+// 文档中缺少的预定义样式会自动补充；样式不存在时仍写入样式引用并记录警告，
+// 需要校验结果时请使用 ApplyStyle。
 //
 // 示例:
 //
 //	para := doc.AddParagraph("这是一个段落")
 //	para.SetStyle("Heading2")  // 设置为二级标题样式
 func (p *Paragraph) SetStyle(styleID string) {
-	if p.Properties == nil {
-		p.Properties = &ParagraphProperties{}
+	if err := p.ApplyStyle(styleID); err != nil {
+		Warnf("设置段落样式 %s 失败: %v", styleID, err)
+		p.setStyleID(styleID)
 	}
-
-	p.Properties.ParagraphStyle = &ParagraphStyle{Val: styleID}
-	Debugf("设置段落样式: %s", styleID)
 }
 
 // SetIndentation 设置段落的缩进属性。
@@ -2055,6 +2168,12 @@ func (d *Document) parseDocumentElement(decoder *xml.Decoder) error {
 				if err := d.parseBodyElement(decoder); err != nil {
 					return err
 				}
+				d.promoteTrailingSectionProperties()
+			case t.Name.Local == "background":
+				// 解析页面背景
+				if err := d.parseDocumentBackground(decoder, t); err != nil {
+					return err
+				}
 			}
 		case xml.EndElement:
 			if t.Name.Local == "document" {
@@ -2096,6 +2215,28 @@ func (d *Document) parseBodyElement(decoder *xml.Decoder) error {
 	return nil
 }
 
+// promoteTrailingSectionProperties 处理缺少主体级节属性的文档
+// 一些文档只在最后一个段落的属性中保存节属性，此时将其提升为主体级节属性，
+// 以便页面设置和页眉页脚引用能够被正常访问
+func (d *Document) promoteTrailingSectionProperties() {
+	for _, element := range d.Body.Elements {
+		if _, ok := element.(*SectionProperties); ok {
+			return
+		}
+	}
+
+	for i := len(d.Body.Elements) - 1; i >= 0; i-- {
+		paragraph, ok := d.Body.Elements[i].(*Paragraph)
+		if !ok || paragraph.Properties == nil || paragraph.Properties.SectionProperties == nil {
+			continue
+		}
+		sectPr := paragraph.Properties.SectionProperties
+		paragraph.Properties.SectionProperties = nil
+		d.Body.Elements = append(d.Body.Elements, sectPr)
+		return
+	}
+}
+
 // parseBodySubElement 解析文档主体的子元素
 func (d *Document) parseBodySubElement(decoder *xml.Decoder, startElement xml.StartElement) (interface{}, error) {
 	switch startElement.Name.Local {
@@ -2144,6 +2285,29 @@ func (d *Document) parseParagraph(decoder *xml.Decoder, startElement xml.StartEl
 				if run != nil {
 					paragraph.Runs = append(paragraph.Runs, *run)
 				}
+			case "fldSimple":
+				// 简单域转换为等价的复杂域，保留域结果
+				runs, err := d.parseSimpleField(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				paragraph.Runs = append(paragraph.Runs, runs...)
+			case "permStart":
+				paragraph.Permissions = append(paragraph.Permissions, &PermissionMark{
+					Position: len(paragraph.Runs),
+					Start:    parsePermStart(t),
+				})
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
+			case "permEnd":
+				paragraph.Permissions = append(paragraph.Permissions, &PermissionMark{
+					Position: len(paragraph.Runs),
+					End:      &PermEnd{ID: getAttributeValue(t.Attr, "id")},
+				})
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
 			default:
 				// 跳过其他元素
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
@@ -2152,7 +2316,41 @@ func (d *Document) parseParagraph(decoder *xml.Decoder, startElement xml.StartEl
 			}
 		case xml.EndElement:
 			if t.Name.Local == "p" {
-				return paragraph, nil
+				return d.bindParagraph(paragraph), nil
+			}
+		}
+	}
+}
+
+// parseSimpleField 解析简单域（w:fldSimple），返回等价的复杂域Run集合
+func (d *Document) parseSimpleField(decoder *xml.Decoder, startElement xml.StartElement) ([]Run, error) {
+	instruction := getAttributeValue(startElement.Attr, "instr")
+	runs := []Run{
+		{FieldChar: &FieldChar{FieldCharType: "begin"}},
+		{InstrText: &InstrText{Space: "preserve", Content: instruction}},
+		{FieldChar: &FieldChar{FieldCharType: "separate"}},
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, WrapError("parse_simple_field", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "r" {
+				run, err := d.parseRun(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				runs = append(runs, *run)
+			} else if err := d.skipElement(decoder, t.Name.Local); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == "fldSimple" {
+				return append(runs, Run{FieldChar: &FieldChar{FieldCharType: "end"}}), nil
 			}
 		}
 	}
@@ -2218,12 +2416,12 @@ func (d *Document) parseParagraphProperties(decoder *xml.Decoder, paragraph *Par
 				}
 				paragraph.Properties.NumberingProperties = numPr
 			case "sectPr":
-				// 一些文档将节属性存储在段落属性中
+				// 段落级节属性表示一个节在此段落处结束，保留在段落上以维持多节结构
 				sectPr, err := d.parseSectionProperties(decoder, t)
 				if err != nil {
 					return err
 				}
-				d.setSectionProperties(sectPr)
+				paragraph.Properties.SectionProperties = sectPr
 			default:
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return err
@@ -2312,6 +2510,12 @@ func (d *Document) parseRun(decoder *xml.Decoder, startElement xml.StartElement)
 					return nil, err
 				}
 				run.Text.Content = content
+			case "br":
+				// 解析换行符、分页符和分栏符
+				run.Break = &Break{Type: getAttributeValue(t.Attr, "type")}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
 			case "drawing":
 				// 解析绘图元素（图片等）
 				drawing, err := d.parseDrawingElement(decoder, t)
@@ -2319,6 +2523,29 @@ func (d *Document) parseRun(decoder *xml.Decoder, startElement xml.StartElement)
 					return nil, err
 				}
 				run.Drawing = drawing
+			case "fldChar":
+				// 解析域字符（begin/separate/end）
+				run.FieldChar = &FieldChar{FieldCharType: getAttributeValue(t.Attr, "fldCharType")}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
+			case "instrText":
+				// 解析域指令文本
+				content, err := d.readElementText(decoder, "instrText")
+				if err != nil {
+					return nil, err
+				}
+				run.InstrText = &InstrText{
+					Space:   getAttributeValue(t.Attr, "space"),
+					Content: content,
+				}
+			case "pict":
+				// 保留VML图形的原始内容（水印、文本框等）
+				pict := &Pict{}
+				if err := decoder.DecodeElement(pict, &t); err != nil {
+					return nil, WrapError("parse_pict", err)
+				}
+				run.Pict = pict
 			default:
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return nil, err
@@ -2345,8 +2572,16 @@ func (d *Document) parseRunProperties(decoder *xml.Decoder, run *Run) error {
 		switch t := token.(type) {
 		case xml.StartElement:
 			switch t.Name.Local {
+			case "rStyle":
+				val := getAttributeValue(t.Attr, "val")
+				if val != "" {
+					run.Properties.RunStyle = &RunStyle{Val: val}
+				}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return err
+				}
 			case "b":
-				run.Properties.Bold = &Bold{}
+				run.Properties.Bold = &Bold{Val: getAttributeValue(t.Attr, "val")}
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return err
 				}
@@ -2356,7 +2591,7 @@ func (d *Document) parseRunProperties(decoder *xml.Decoder, run *Run) error {
 					return err
 				}
 			case "i":
-				run.Properties.Italic = &Italic{}
+				run.Properties.Italic = &Italic{Val: getAttributeValue(t.Attr, "val")}
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return err
 				}
@@ -2393,10 +2628,7 @@ func (d *Document) parseRunProperties(decoder *xml.Decoder, run *Run) error {
 					return err
 				}
 			case "color":
-				val := getAttributeValue(t.Attr, "val")
-				if val != "" {
-					run.Properties.Color = &Color{Val: val}
-				}
+				run.Properties.Color = parseColorAttrs(t.Attr)
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return err
 				}
@@ -2409,19 +2641,7 @@ func (d *Document) parseRunProperties(decoder *xml.Decoder, run *Run) error {
 					return err
 				}
 			case "rFonts":
-				ascii := getAttributeValue(t.Attr, "ascii")
-				hAnsi := getAttributeValue(t.Attr, "hAnsi")
-				eastAsia := getAttributeValue(t.Attr, "eastAsia")
-				cs := getAttributeValue(t.Attr, "cs")
-				hint := getAttributeValue(t.Attr, "hint")
-
-				run.Properties.FontFamily = &FontFamily{
-					ASCII:    ascii,
-					HAnsi:    hAnsi,
-					EastAsia: eastAsia,
-					CS:       cs,
-					Hint:     hint,
-				}
+				run.Properties.FontFamily = parseFontFamilyAttrs(t.Attr)
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return err
 				}
@@ -2479,7 +2699,7 @@ func (d *Document) parseTable(decoder *xml.Decoder, startElement xml.StartElemen
 			}
 		case xml.EndElement:
 			if t.Name.Local == "tbl" {
-				return table, nil
+				return d.bindTable(table), nil
 			}
 		}
 	}
@@ -2736,6 +2956,15 @@ func (d *Document) parseSectionProperties(decoder *xml.Decoder, startElement xml
 		switch t := token.(type) {
 		case xml.StartElement:
 			switch t.Name.Local {
+			case "type":
+				// 解析节起始类型
+				val := getAttributeValue(t.Attr, "val")
+				if val != "" {
+					sectPr.Type = &SectionType{Val: val}
+				}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
 			case "pgSz":
 				// 解析页面尺寸
 				w := getAttributeValue(t.Attr, "w")
@@ -2761,12 +2990,53 @@ func (d *Document) parseSectionProperties(decoder *xml.Decoder, startElement xml
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return nil, err
 				}
+			case "pgBorders":
+				// 解析页面边框
+				borders, err := d.parsePageBorders(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				sectPr.PageBorders = borders
+			case "lnNumType":
+				// 解析行号
+				sectPr.LineNumType = &LineNumType{
+					CountBy:  getAttributeValue(t.Attr, "countBy"),
+					Start:    getAttributeValue(t.Attr, "start"),
+					Distance: getAttributeValue(t.Attr, "distance"),
+					Restart:  getAttributeValue(t.Attr, "restart"),
+				}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
+			case "vAlign":
+				// 解析页面垂直对齐方式
+				if val := getAttributeValue(t.Attr, "val"); val != "" {
+					sectPr.VerticalAlign = &SectionVerticalAlign{Val: val}
+				}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
+			case "titlePg":
+				// 解析首页不同设置
+				if val := getAttributeValue(t.Attr, "val"); val == "" || parseOnOff(val) {
+					sectPr.TitlePage = &TitlePage{}
+				}
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
 			case "cols":
 				// 解析分栏
-				space := getAttributeValue(t.Attr, "space")
-				num := getAttributeValue(t.Attr, "num")
-				if space != "" || num != "" {
-					sectPr.Columns = &Columns{Space: space, Num: num}
+				columns, err := d.parseColumns(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				sectPr.Columns = columns
+			case "pgNumType":
+				// 解析页码类型
+				numFmt := getAttributeValue(t.Attr, "fmt")
+				start := getAttributeValue(t.Attr, "start")
+				if numFmt != "" || start != "" {
+					sectPr.PageNumType = &PageNumType{Fmt: numFmt, Start: start}
 				}
 				if err := d.skipElement(decoder, t.Name.Local); err != nil {
 					return nil, err
@@ -2896,17 +3166,26 @@ func (d *Document) serializeDocument() error {
 		XmlnsA   string   `xml:"xmlns:a,attr"`
 		XmlnsPic string   `xml:"xmlns:pic,attr"`
 		XmlnsR   string   `xml:"xmlns:r,attr"`
-		Body     *Body    `xml:"w:body"`
+		XmlnsV   string   `xml:"xmlns:v,attr"`
+		XmlnsO   string   `xml:"xmlns:o,attr"`
+		XmlnsW10 string   `xml:"xmlns:w10,attr"`
+		// 页面背景（需位于 w:body 之前）
+		Background *DocumentBackground `xml:"w:background,omitempty"`
+		Body       *Body               `xml:"w:body"`
 	}
 
 	doc := documentXML{
-		Xmlns:    "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
-		XmlnsW15: "http://schemas.microsoft.com/office/word/2012/wordml",
-		XmlnsWP:  "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing",
-		XmlnsA:   "http://schemas.openxmlformats.org/drawingml/2006/main",
-		XmlnsPic: "http://schemas.openxmlformats.org/drawingml/2006/picture",
-		XmlnsR:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
-		Body:     d.Body,
+		Xmlns:      "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+		XmlnsW15:   "http://schemas.microsoft.com/office/word/2012/wordml",
+		XmlnsWP:    "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing",
+		XmlnsA:     "http://schemas.openxmlformats.org/drawingml/2006/main",
+		XmlnsPic:   "http://schemas.openxmlformats.org/drawingml/2006/picture",
+		XmlnsR:     "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
+		XmlnsV:     "urn:schemas-microsoft-com:vml",
+		XmlnsO:     "urn:schemas-microsoft-com:office:office",
+		XmlnsW10:   "urn:schemas-microsoft-com:office:word",
+		Background: d.background,
+		Body:       d.Body,
 	}
 
 	// 序列化为XML
@@ -2963,54 +3242,32 @@ func (d *Document) serializeDocumentRelationships() {
 func (d *Document) serializeStyles() error {
 	Debugf("开始序列化样式")
 
-	// 如果在克隆文档时已经保留了完整的 styles.xml（含 docDefaults 等信息），
-	// 这里直接跳过重新生成，避免丢失模板原有的默认段落/字符设置。
-	if existing, ok := d.parts["word/styles.xml"]; ok && len(existing) > 0 {
-		Debugf("检测到已有 styles.xml，跳过样式重建以保留模板默认样式")
-		return nil
-	}
+	// 表格引用的样式模板需要在样式表中存在对应的表格样式
+	d.ensureTableTemplateStyles()
 
-	// 创建样式结构，包含完整的命名空间
-	type stylesXML struct {
-		XMLName     xml.Name       `xml:"w:styles"`
-		XmlnsW      string         `xml:"xmlns:w,attr"`
-		XmlnsMC     string         `xml:"xmlns:mc,attr"`
-		XmlnsO      string         `xml:"xmlns:o,attr"`
-		XmlnsR      string         `xml:"xmlns:r,attr"`
-		XmlnsM      string         `xml:"xmlns:m,attr"`
-		XmlnsV      string         `xml:"xmlns:v,attr"`
-		XmlnsW14    string         `xml:"xmlns:w14,attr"`
-		XmlnsW10    string         `xml:"xmlns:w10,attr"`
-		XmlnsSL     string         `xml:"xmlns:sl,attr"`
-		XmlnsWPS    string         `xml:"xmlns:wpsCustomData,attr"`
-		MCIgnorable string         `xml:"mc:Ignorable,attr"`
-		Styles      []*style.Style `xml:"w:style"`
-	}
-
-	doc := stylesXML{
-		XmlnsW:      "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
-		XmlnsMC:     "http://schemas.openxmlformats.org/markup-compatibility/2006",
-		XmlnsO:      "urn:schemas-microsoft-com:office:office",
-		XmlnsR:      "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
-		XmlnsM:      "http://schemas.openxmlformats.org/officeDocument/2006/math",
-		XmlnsV:      "urn:schemas-microsoft-com:vml",
-		XmlnsW14:    "http://schemas.microsoft.com/office/word/2010/wordml",
-		XmlnsW10:    "urn:schemas-microsoft-com:office:word",
-		XmlnsSL:     "http://schemas.openxmlformats.org/schemaLibrary/2006/main",
-		XmlnsWPS:    "http://www.wps.cn/officeDocument/2013/wpsCustomData",
-		MCIgnorable: "w14",
-		Styles:      d.styleManager.GetAllStyles(),
+	// 样式文件无法解析时（样式管理器未记录原始XML）保持原样，避免丢失原有样式
+	if existing, ok := d.parts["word/styles.xml"]; ok && len(existing) > 0 && !d.styleManager.HasSourceXML() {
+		Debugf("已有 styles.xml 无法解析，跳过样式重建")
+		return nil
 	}
 
-	// 序列化为XML
-	data, err := xml.MarshalIndent(doc, "", "  ")
+	// 打开的文档中未修改的样式按原始XML输出，加载时补充的预定义样式只在被引用时写入
+	used := d.usedStyleIDs()
+	data, err := d.styleManager.MarshalStylesXML(func(styleID string) bool {
+		return used[styleID]
+	})
 	if err != nil {
 		Errorf("XML序列化失败: %v", err)
 		return WrapError("marshal_xml", err)
 	}
+	d.parts["word/styles.xml"] = data
 
-	// 添加XML声明
-	d.parts["word/styles.xml"] = append([]byte(xml.Header), data...)
+	// 新建文档以首次生成的XML为基础，之后保存时只重新生成修改过的样式
+	if !d.styleManager.HasSourceXML() {
+		if err := d.styleManager.SetSourceXML(data); err != nil {
+			Warnf("记录样式XML失败: %v", err)
+		}
+	}
 
 	Debugf("样式序列化完成")
 	return nil
@@ -3152,6 +3409,21 @@ func (d *Document) ToBytes() ([]byte, error) {
 		return nil, err
 	}
 
+	// 序列化文档设置
+	if err := d.serializeSettings(); err != nil {
+		return nil, err
+	}
+
+	// 序列化页眉页脚
+	if err := d.serializeHeaderFooters(); err != nil {
+		return nil, err
+	}
+
+	// 序列化字体表及嵌入字体
+	if err := d.serializeFontTable(); err != nil {
+		return nil, err
+	}
+
 	// 序列化内容类型
 	d.serializeContentTypes()
 
@@ -0,0 +1,603 @@
+// Package document 提供样式层叠解析，计算段落和文本的最终格式
+package document
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// defaultFontSizeHalfPoints 未设置字号时 Word 使用的默认字号（半磅，即10磅）
+const defaultFontSizeHalfPoints = 20
+
+// outlineLevelBodyText 正文的大纲级别
+const outlineLevelBodyText = 9
+
+// EffectiveRunProperties 文本的最终格式
+//
+// 按 OOXML 规定的顺序层叠：文档默认格式、表格样式（含单元格适用的条件格式）、段落样式（含 basedOn 链）、
+// 字符样式（含 basedOn 链）、直接格式。粗体和斜体为切换属性，
+// 在各类样式之间按异或计算，直接格式则直接决定最终值。
+type EffectiveRunProperties struct {
+	StyleID    string     // 字符样式ID，未引用时为默认字符样式
+	Bold       bool       // 是否粗体
+	Italic     bool       // 是否斜体
+	Underline  string     // 下划线类型（如 single、double），空字符串表示无下划线
+	Strike     bool       // 是否删除线
+	FontSize   float64    // 字号（磅）
+	Color      string     // 字体颜色（十六进制或 auto），主题颜色已按文档主题计算
+	Highlight  string     // 高亮颜色，空字符串表示无高亮
+	FontFamily FontFamily // 各字符集的字体名称，主题字体已按文档主题解析
+}
+
+// EffectiveParagraphProperties 段落的最终格式
+//
+// 按 OOXML 规定的顺序层叠：文档默认格式、表格样式（含单元格适用的条件格式）、段落样式（含 basedOn 链）、
+// 编号级别的段落属性、直接格式。
+type EffectiveParagraphProperties struct {
+	StyleID         string        // 段落样式ID，未引用时为默认段落样式
+	Alignment       AlignmentType // 对齐方式
+	SpaceBefore     float64       // 段前间距（磅）
+	SpaceAfter      float64       // 段后间距（磅）
+	LineSpacing     float64       // 行距：LineRule 为 auto 时为倍数，否则为磅值
+	LineRule        string        // 行距规则：auto、exact、atLeast
+	IndentLeft      float64       // 左缩进（磅）
+	IndentRight     float64       // 右缩进（磅）
+	IndentFirstLine float64       // 首行缩进（磅），悬挂缩进时为负值
+	KeepNext        bool          // 与下段同页
+	KeepLines       bool          // 段中不分页
+	PageBreakBefore bool          // 段前分页
+	OutlineLevel    int           // 大纲级别（0-8），9 表示正文
+	NumID           string        // 编号实例ID，空字符串表示无编号
+	NumLevel        int           // 编号级别
+}
+
+// EffectiveProperties 计算文本的最终格式
+//
+// 文本需位于文档正文、表格或已加载的页眉页脚中，否则只按文档默认格式、
+// 默认段落样式、字符样式和直接格式计算。
+//
+// 示例：
+//
+//	for _, para := range doc.Body.GetParagraphs() {
+//		for i := range para.Runs {
+//			props := para.Runs[i].EffectiveProperties(doc)
+//			fmt.Printf("%s: %.1f磅 粗体=%v\n", para.Runs[i].Text.Content, props.FontSize, props.Bold)
+//		}
+//	}
+func (r *Run) EffectiveProperties(doc *Document) *EffectiveRunProperties {
+	if doc == nil {
+		return nil
+	}
+
+	location := doc.locateParagraph(func(p *Paragraph) bool {
+		for i := range p.Runs {
+			if &p.Runs[i] == r {
+				return true
+			}
+		}
+		return false
+	})
+
+	sm := doc.styleManager
+	theme := doc.Theme()
+	result := &EffectiveRunProperties{
+		FontSize: defaultFontSizeHalfPoints / 2,
+		Color:    "auto",
+	}
+
+	// 文档默认格式：切换属性在此处为普通属性
+	defaults := sm.GetDocumentDefaults().RunProperties()
+	result.applyStyleLayer(defaults, theme)
+	bold, italic := styleToggle(defaults)
+
+	// 各类样式之间的切换属性按异或计算
+	// 表格样式及其条件格式视为同一层，条件格式中的切换属性直接覆盖
+	var styleBold, styleItalic bool
+	for _, props := range doc.tableStyleRunLayers(location) {
+		result.applyStyleLayer(props, theme)
+		if props.Bold != nil {
+			styleBold = parseOnOff(props.Bold.Val)
+		}
+		if props.Italic != nil {
+			styleItalic = parseOnOff(props.Italic.Val)
+		}
+	}
+	if paragraphStyle := doc.paragraphStyle(location.paragraph); paragraphStyle != nil {
+		result.applyStyleLayer(paragraphStyle.RunPr, theme)
+		b, i := styleToggle(paragraphStyle.RunPr)
+		styleBold, styleItalic = styleBold != b, styleItalic != i
+	}
+
+	var characterStyle *style.Style
+	if r.Properties != nil && r.Properties.RunStyle != nil {
+		characterStyle = sm.GetStyleWithInheritance(r.Properties.RunStyle.Val)
+	}
+	if characterStyle == nil {
+		characterStyle = sm.GetDefaultStyle(style.StyleTypeCharacter)
+	}
+	if characterStyle != nil {
+		result.StyleID = characterStyle.StyleID
+		result.applyStyleLayer(characterStyle.RunPr, theme)
+		b, i := styleToggle(characterStyle.RunPr)
+		styleBold, styleItalic = styleBold != b, styleItalic != i
+	}
+	result.Bold, result.Italic = bold != styleBold, italic != styleItalic
+
+	// 直接格式
+	if props := r.Properties; props != nil {
+		if props.Bold != nil {
+			result.Bold = parseOnOff(props.Bold.Val)
+		}
+		if props.Italic != nil {
+			result.Italic = parseOnOff(props.Italic.Val)
+		}
+		if props.Underline != nil {
+			result.setUnderline(props.Underline.Val)
+		}
+		if props.Strike != nil {
+			result.Strike = true
+		}
+		if props.FontSize != nil {
+			result.setFontSize(props.FontSize.Val)
+		}
+		if props.Color != nil {
+			result.setColor(props.Color.Val, props.Color.ThemeColor, props.Color.ThemeTint, props.Color.ThemeShade, theme)
+		}
+		if props.Highlight != nil {
+			result.Highlight = props.Highlight.Val
+		}
+		if props.FontFamily != nil {
+			result.FontFamily.merge(props.FontFamily)
+		}
+	}
+
+	result.resolveThemeFonts(doc, theme)
+	return result
+}
+
+// EffectiveProperties 计算段落的最终格式
+//
+// 段落需位于文档正文、表格或已加载的页眉页脚中，否则不计算表格样式。
+func (p *Paragraph) EffectiveProperties(doc *Document) *EffectiveParagraphProperties {
+	if doc == nil {
+		return nil
+	}
+
+	location := doc.locateParagraph(func(candidate *Paragraph) bool {
+		return candidate == p
+	})
+
+	result := &EffectiveParagraphProperties{
+		Alignment:    AlignLeft,
+		LineSpacing:  1,
+		LineRule:     "auto",
+		OutlineLevel: outlineLevelBodyText,
+	}
+
+	result.applyStyleLayer(doc.styleManager.GetDocumentDefaults().ParagraphProperties())
+	for _, props := range doc.tableStyleParagraphLayers(location) {
+		result.applyStyleLayer(props)
+	}
+	if paragraphStyle := doc.paragraphStyle(p); paragraphStyle != nil {
+		result.StyleID = paragraphStyle.StyleID
+		result.applyStyleLayer(paragraphStyle.ParagraphPr)
+	}
+
+	// 编号级别的段落属性（缩进）位于段落样式之后、直接格式之前
+	definitions := make(map[string]*ListDefinition)
+	for _, definition := range doc.GetListDefinitions() {
+		definitions[definition.NumID] = definition
+	}
+	numID, ilvl := doc.paragraphNumbering(p, 0, definitions)
+	if definition := definitions[numID]; definition != nil {
+		result.NumID, result.NumLevel = numID, ilvl
+		if level := definition.GetLevel(ilvl); level != nil && level.PPr != nil && level.PPr.Ind != nil {
+			result.setIndentation(level.PPr.Ind.Left, "", level.PPr.Ind.FirstLine, level.PPr.Ind.Hanging)
+		}
+	}
+
+	// 直接格式
+	if props := p.Properties; props != nil {
+		if props.Justification != nil {
+			result.setAlignment(props.Justification.Val)
+		}
+		if props.Spacing != nil {
+			result.setSpacing(props.Spacing.Before, props.Spacing.After, props.Spacing.Line, props.Spacing.LineRule)
+		}
+		if props.Indentation != nil {
+			result.setIndentation(props.Indentation.Left, props.Indentation.Right, props.Indentation.FirstLine, "")
+		}
+		if props.KeepNext != nil {
+			result.KeepNext = parseOnOff(props.KeepNext.Val)
+		}
+		if props.KeepLines != nil {
+			result.KeepLines = parseOnOff(props.KeepLines.Val)
+		}
+		if props.PageBreakBefore != nil {
+			result.PageBreakBefore = parseOnOff(props.PageBreakBefore.Val)
+		}
+		if props.OutlineLevel != nil {
+			result.setOutlineLevel(props.OutlineLevel.Val)
+		}
+	}
+	return result
+}
+
+// paragraphLocation 段落在文档中的位置
+type paragraphLocation struct {
+	paragraph *Paragraph
+	table     *Table // 段落直接所在的表格，不在表格中时为nil
+	row, col  int    // 段落所在单元格的行列索引
+}
+
+// locateParagraph 在正文和已加载的页眉页脚中查找满足条件的段落，未找到时返回空位置
+func (d *Document) locateParagraph(match func(*Paragraph) bool) paragraphLocation {
+	if d.Body != nil {
+		if location, ok := findParagraph(d.Body.Elements, match); ok {
+			return location
+		}
+	}
+	for _, header := range d.headers {
+		if location, ok := findParagraph(header.Elements, match); ok {
+			return location
+		}
+	}
+	for _, footer := range d.footers {
+		if location, ok := findParagraph(footer.Elements, match); ok {
+			return location
+		}
+	}
+	return paragraphLocation{}
+}
+
+// findParagraph 递归查找满足条件的段落
+func findParagraph(elements []interface{}, match func(*Paragraph) bool) (paragraphLocation, bool) {
+	for _, element := range elements {
+		switch elem := element.(type) {
+		case *Paragraph:
+			if match(elem) {
+				return paragraphLocation{paragraph: elem}, true
+			}
+		case *Table:
+			if location, ok := findTableParagraph(elem, match); ok {
+				return location, true
+			}
+		case *SDT:
+			if elem.Content != nil {
+				if location, ok := findParagraph(elem.Content.Elements, match); ok {
+					return location, true
+				}
+			}
+		}
+	}
+	return paragraphLocation{}, false
+}
+
+// findTableParagraph 在表格（含嵌套表格）中查找满足条件的段落，返回段落直接所在的表格和单元格
+func findTableParagraph(table *Table, match func(*Paragraph) bool) (paragraphLocation, bool) {
+	for i := range table.Rows {
+		for j := range table.Rows[i].Cells {
+			cell := &table.Rows[i].Cells[j]
+			for k := range cell.Paragraphs {
+				if match(&cell.Paragraphs[k]) {
+					return paragraphLocation{paragraph: &cell.Paragraphs[k], table: table, row: i, col: j}, true
+				}
+			}
+			for k := range cell.Tables {
+				if location, ok := findTableParagraph(&cell.Tables[k], match); ok {
+					return location, true
+				}
+			}
+		}
+	}
+	return paragraphLocation{}, false
+}
+
+// tableStyle 获取表格引用的样式（含 basedOn 链），未引用时使用默认表格样式
+// 尚未加入样式表的样式模板按模板定义计算
+func (d *Document) tableStyle(table *Table) *style.Style {
+	if table == nil {
+		return nil
+	}
+	if table.Properties != nil && table.Properties.TableStyle != nil {
+		styleID := table.Properties.TableStyle.Val
+		if s := d.styleManager.GetStyleWithInheritance(styleID); s != nil {
+			return s
+		}
+		if s := tableTemplateStyle(TableStyleTemplate(styleID)); s != nil {
+			return s
+		}
+	}
+	if s := d.styleManager.GetDefaultStyle(style.StyleTypeTable); s != nil {
+		return d.styleManager.GetStyleWithInheritance(s.StyleID)
+	}
+	return nil
+}
+
+// tableStyleLayers 返回段落所在单元格适用的表格样式及条件格式，按应用顺序排列
+func (d *Document) tableStyleLayers(location paragraphLocation) []*style.TableStyleProperties {
+	tableStyle := d.tableStyle(location.table)
+	if tableStyle == nil {
+		return nil
+	}
+
+	layers := []*style.TableStyleProperties{{
+		Type:        style.TableConditionWholeTable,
+		ParagraphPr: tableStyle.ParagraphPr,
+		RunPr:       tableStyle.RunPr,
+	}}
+	for _, condition := range cellConditions(location.table, tableStyle, location.row, location.col) {
+		if conditional := tableStyle.GetConditionalFormat(condition); conditional != nil {
+			layers = append(layers, conditional)
+		}
+	}
+	return layers
+}
+
+// tableStyleRunLayers 返回表格样式各层的字符属性
+func (d *Document) tableStyleRunLayers(location paragraphLocation) []*style.RunProperties {
+	var result []*style.RunProperties
+	for _, layer := range d.tableStyleLayers(location) {
+		if layer.RunPr != nil {
+			result = append(result, layer.RunPr)
+		}
+	}
+	return result
+}
+
+// tableStyleParagraphLayers 返回表格样式各层的段落属性
+func (d *Document) tableStyleParagraphLayers(location paragraphLocation) []*style.ParagraphProperties {
+	var result []*style.ParagraphProperties
+	for _, layer := range d.tableStyleLayers(location) {
+		if layer.ParagraphPr != nil {
+			result = append(result, layer.ParagraphPr)
+		}
+	}
+	return result
+}
+
+// paragraphStyle 获取段落引用的样式（含 basedOn 链），未引用时使用默认段落样式
+func (d *Document) paragraphStyle(paragraph *Paragraph) *style.Style {
+	if paragraph != nil && paragraph.Properties != nil && paragraph.Properties.ParagraphStyle != nil {
+		if s := d.styleManager.GetStyleWithInheritance(paragraph.Properties.ParagraphStyle.Val); s != nil {
+			return s
+		}
+	}
+	if s := d.styleManager.GetDefaultStyle(style.StyleTypeParagraph); s != nil {
+		return d.styleManager.GetStyleWithInheritance(s.StyleID)
+	}
+	return nil
+}
+
+// styleToggle 返回样式中粗体和斜体切换属性的值
+func styleToggle(props *style.RunProperties) (bold, italic bool) {
+	if props == nil {
+		return false, false
+	}
+	return props.Bold != nil && parseOnOff(props.Bold.Val), props.Italic != nil && parseOnOff(props.Italic.Val)
+}
+
+// applyStyleLayer 应用一层样式中的非切换属性
+func (e *EffectiveRunProperties) applyStyleLayer(props *style.RunProperties, theme *Theme) {
+	if props == nil {
+		return
+	}
+	if props.Underline != nil {
+		e.setUnderline(props.Underline.Val)
+	}
+	if props.Strike != nil {
+		e.Strike = true
+	}
+	if props.FontSize != nil {
+		e.setFontSize(props.FontSize.Val)
+	}
+	if props.Color != nil {
+		e.setColor(props.Color.Val, props.Color.ThemeColor, props.Color.ThemeTint, props.Color.ThemeShade, theme)
+	}
+	if props.Highlight != nil {
+		e.Highlight = props.Highlight.Val
+	}
+	if props.FontFamily != nil {
+		e.FontFamily.merge(&FontFamily{
+			ASCII:         props.FontFamily.ASCII,
+			HAnsi:         props.FontFamily.HAnsi,
+			EastAsia:      props.FontFamily.EastAsia,
+			CS:            props.FontFamily.CS,
+			ASCIITheme:    props.FontFamily.ASCIITheme,
+			HAnsiTheme:    props.FontFamily.HAnsiTheme,
+			EastAsiaTheme: props.FontFamily.EastAsiaTheme,
+			CSTheme:       props.FontFamily.CSTheme,
+		})
+	}
+}
+
+// setUnderline 设置下划线，none 表示无下划线
+func (e *EffectiveRunProperties) setUnderline(val string) {
+	switch val {
+	case "none":
+		e.Underline = ""
+	case "":
+		e.Underline = "single"
+	default:
+		e.Underline = val
+	}
+}
+
+// setFontSize 按半磅值设置字号
+func (e *EffectiveRunProperties) setFontSize(val string) {
+	if halfPoints, err := strconv.ParseFloat(val, 64); err == nil && halfPoints > 0 {
+		e.FontSize = halfPoints / 2
+	}
+}
+
+// setColor 设置颜色，主题颜色能按文档主题计算时优先使用计算结果
+func (e *EffectiveRunProperties) setColor(val, themeColor, tint, shade string, theme *Theme) {
+	if val != "" {
+		e.Color = val
+	}
+	if themeColor == "" || theme == nil {
+		return
+	}
+	if resolved, ok := theme.ResolveColor(themeColor, tint, shade); ok {
+		e.Color = resolved
+	}
+}
+
+// resolveThemeFonts 按文档主题将主题字体引用解析为字体名称
+func (e *EffectiveRunProperties) resolveThemeFonts(doc *Document, theme *Theme) {
+	fonts := &e.FontFamily
+	if theme == nil || (fonts.ASCIITheme == "" && fonts.HAnsiTheme == "" && fonts.EastAsiaTheme == "" && fonts.CSTheme == "") {
+		return
+	}
+
+	script := "Hans"
+	// 仅在已有设置时读取主题字体语言，避免为只读查询创建 settings.xml
+	if doc.settings != nil || len(doc.parts["word/settings.xml"]) > 0 {
+		if settings := doc.Settings(); settings.ThemeFontLang != nil {
+			script = eastAsianScript(settings.ThemeFontLang.EastAsia)
+		}
+	}
+	resolve := func(name *string, themeFont, script string) {
+		if themeFont == "" {
+			return
+		}
+		if typeface := theme.ResolveFont(themeFont, script); typeface != "" {
+			*name = typeface
+		}
+	}
+	resolve(&fonts.ASCII, fonts.ASCIITheme, "")
+	resolve(&fonts.HAnsi, fonts.HAnsiTheme, "")
+	resolve(&fonts.EastAsia, fonts.EastAsiaTheme, script)
+	resolve(&fonts.CS, fonts.CSTheme, "")
+}
+
+// eastAsianScript 根据东亚语言代码返回主题字体的书写系统代码
+func eastAsianScript(lang string) string {
+	switch {
+	case strings.HasPrefix(lang, "ja"):
+		return "Jpan"
+	case strings.HasPrefix(lang, "ko"):
+		return "Hang"
+	case lang == "zh-TW" || lang == "zh-HK" || lang == "zh-MO":
+		return "Hant"
+	default:
+		return "Hans"
+	}
+}
+
+// merge 按字符集合并字体设置，同一字符集的字体名称或主题字体引用会整体覆盖
+func (f *FontFamily) merge(override *FontFamily) {
+	if override.ASCII != "" || override.ASCIITheme != "" {
+		f.ASCII, f.ASCIITheme = override.ASCII, override.ASCIITheme
+	}
+	if override.HAnsi != "" || override.HAnsiTheme != "" {
+		f.HAnsi, f.HAnsiTheme = override.HAnsi, override.HAnsiTheme
+	}
+	if override.EastAsia != "" || override.EastAsiaTheme != "" {
+		f.EastAsia, f.EastAsiaTheme = override.EastAsia, override.EastAsiaTheme
+	}
+	if override.CS != "" || override.CSTheme != "" {
+		f.CS, f.CSTheme = override.CS, override.CSTheme
+	}
+	if override.Hint != "" {
+		f.Hint = override.Hint
+	}
+}
+
+// applyStyleLayer 应用一层样式中的段落属性
+func (e *EffectiveParagraphProperties) applyStyleLayer(props *style.ParagraphProperties) {
+	if props == nil {
+		return
+	}
+	if props.Justification != nil {
+		e.setAlignment(props.Justification.Val)
+	}
+	if props.Spacing != nil {
+		e.setSpacing(props.Spacing.Before, props.Spacing.After, props.Spacing.Line, props.Spacing.LineRule)
+	}
+	if props.Indentation != nil {
+		e.setIndentation(props.Indentation.Left, props.Indentation.Right, props.Indentation.FirstLine, props.Indentation.Hanging)
+	}
+	if props.KeepNext != nil {
+		e.KeepNext = true
+	}
+	if props.KeepLines != nil {
+		e.KeepLines = true
+	}
+	if props.PageBreak != nil {
+		e.PageBreakBefore = true
+	}
+	if props.OutlineLevel != nil {
+		e.setOutlineLevel(props.OutlineLevel.Val)
+	}
+}
+
+// setAlignment 设置对齐方式，start/end 按从左到右的文字方向处理
+func (e *EffectiveParagraphProperties) setAlignment(val string) {
+	switch val {
+	case "":
+		return
+	case "start":
+		e.Alignment = AlignLeft
+	case "end":
+		e.Alignment = AlignRight
+	default:
+		e.Alignment = AlignmentType(val)
+	}
+}
+
+// setSpacing 设置间距，参数为缇（行距为 auto 时以240为单倍行距），空字符串表示不修改
+func (e *EffectiveParagraphProperties) setSpacing(before, after, line, lineRule string) {
+	if twips, ok := parseTwips(before); ok {
+		e.SpaceBefore = twips / 20
+	}
+	if twips, ok := parseTwips(after); ok {
+		e.SpaceAfter = twips / 20
+	}
+	if lineRule != "" {
+		e.LineRule = lineRule
+	}
+	if twips, ok := parseTwips(line); ok {
+		if lineRule == "" {
+			e.LineRule = "auto"
+		}
+		if e.LineRule == "auto" {
+			e.LineSpacing = twips / 240
+		} else {
+			e.LineSpacing = twips / 20
+		}
+	}
+}
+
+// setIndentation 设置缩进，参数为缇，悬挂缩进转换为负的首行缩进
+func (e *EffectiveParagraphProperties) setIndentation(left, right, firstLine, hanging string) {
+	if twips, ok := parseTwips(left); ok {
+		e.IndentLeft = twips / 20
+	}
+	if twips, ok := parseTwips(right); ok {
+		e.IndentRight = twips / 20
+	}
+	if twips, ok := parseTwips(hanging); ok {
+		e.IndentFirstLine = -twips / 20
+	} else if twips, ok := parseTwips(firstLine); ok {
+		e.IndentFirstLine = twips / 20
+	}
+}
+
+// setOutlineLevel 设置大纲级别
+func (e *EffectiveParagraphProperties) setOutlineLevel(val string) {
+	if level, err := strconv.Atoi(val); err == nil && level >= 0 && level <= outlineLevelBodyText {
+		e.OutlineLevel = level
+	}
+}
+
+// parseTwips 解析以缇为单位的数值
+func parseTwips(val string) (float64, bool) {
+	if val == "" {
+		return 0, false
+	}
+	twips, err := strconv.ParseFloat(val, 64)
+	return twips, err == nil
+}
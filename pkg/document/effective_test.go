@@ -0,0 +1,120 @@
+// Package document 样式层叠解析功能测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestEffectiveProperties 测试文档默认格式、样式链、切换属性和直接格式的层叠
+func TestEffectiveProperties(t *testing.T) {
+	doc := New()
+	sm := doc.GetStyleManager()
+	sm.SetDocumentDefaults(&style.RunProperties{
+		FontSize:   &style.FontSize{Val: "24"},
+		FontFamily: &style.FontFamily{ASCII: "Times New Roman", EastAsia: "仿宋"},
+	}, &style.ParagraphProperties{
+		Spacing: &style.Spacing{After: "200", Line: "360"},
+	})
+
+	quote := sm.CreateCustomStyle("Quote1", "引文", style.StyleTypeParagraph, "Normal")
+	quote.RunPr = &style.RunProperties{Bold: &style.Bold{}, Italic: &style.Italic{}}
+	quote.ParagraphPr = &style.ParagraphProperties{Indentation: &style.Indentation{Left: "420", Hanging: "210"}}
+	strong := sm.CreateCustomStyle("Strong1", "强调", style.StyleTypeCharacter, "")
+	strong.RunPr = &style.RunProperties{Bold: &style.Bold{}, FontSize: &style.FontSize{Val: "28"}}
+
+	para := doc.AddParagraph("引文")
+	para.SetStyle("Quote1")
+	para.Runs = append(para.Runs,
+		Run{Properties: &RunProperties{RunStyle: &RunStyle{Val: "Strong1"}}, Text: Text{Content: "强调"}},
+		Run{Properties: &RunProperties{RunStyle: &RunStyle{Val: "Strong1"}, Bold: &Bold{}}, Text: Text{Content: "加粗"}},
+		Run{Properties: &RunProperties{Italic: &Italic{Val: "0"}}, Text: Text{Content: "正体"}},
+	)
+
+	check := func(doc *Document, para *Paragraph) {
+		t.Helper()
+		expected := []struct {
+			size         float64
+			bold, italic bool
+		}{
+			{10.5, true, true}, // 段落样式的粗体斜体，字号来自 Normal
+			{14, false, true},  // 段落样式与字符样式的粗体相互抵消
+			{14, true, true},   // 直接格式决定最终值
+			{10.5, true, false},
+		}
+		for i, want := range expected {
+			props := para.Runs[i].EffectiveProperties(doc)
+			if props.FontSize != want.size || props.Bold != want.bold || props.Italic != want.italic {
+				t.Errorf("第%d个文本的格式不正确: %+v", i, props)
+			}
+		}
+		if font := para.Runs[0].EffectiveProperties(doc).FontFamily; font.EastAsia != "宋体" {
+			t.Errorf("东亚字体应来自 Normal 样式: %+v", font)
+		}
+
+		paraProps := para.EffectiveProperties(doc)
+		if paraProps.StyleID != "Quote1" || paraProps.IndentLeft != 21 || paraProps.IndentFirstLine != -10.5 ||
+			paraProps.SpaceAfter != 10 || paraProps.LineSpacing != 1.5 || paraProps.LineRule != "auto" {
+			t.Errorf("段落格式不正确: %+v", paraProps)
+		}
+	}
+	check(doc, para)
+
+	filename := filepath.Join(t.TempDir(), "effective.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	if !strings.Contains(string(doc.parts["word/styles.xml"]), "<w:docDefaults>") {
+		t.Error("styles.xml 中缺少文档默认格式")
+	}
+
+	// 重新打开后应从 styles.xml 读取默认格式和自定义样式
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	check(opened, opened.Body.GetParagraphs()[0])
+
+	// 修改默认格式只替换 w:docDefaults，其余样式保持不变
+	opened.GetStyleManager().SetDocumentDefaults(&style.RunProperties{FontSize: &style.FontSize{Val: "32"}}, nil)
+	if _, err := opened.ToBytes(); err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	stylesXML := string(opened.parts["word/styles.xml"])
+	if strings.Count(stylesXML, "<w:docDefaults>") != 1 || !strings.Contains(stylesXML, `<w:sz w:val="32">`) ||
+		!strings.Contains(stylesXML, `w:styleId="Strong1"`) {
+		t.Errorf("更新后的 styles.xml 不正确:\n%s", stylesXML)
+	}
+}
+
+// TestEffectivePropertiesContext 测试表格样式和编号级别参与层叠
+func TestEffectivePropertiesContext(t *testing.T) {
+	doc := New()
+	sm := doc.GetStyleManager()
+	grid := sm.CreateCustomStyle("BrandTable", "品牌表格", style.StyleTypeTable, "")
+	grid.RunPr = &style.RunProperties{Color: &style.Color{Val: "1F3864"}}
+
+	table, err := doc.AddTable(&TableConfig{Rows: 1, Cols: 1, Width: 4000, Data: [][]string{{"单元格"}}})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.Properties.TableStyle = &TableStyle{Val: "BrandTable"}
+	cell, _ := table.GetCell(0, 0)
+	if props := cell.Paragraphs[0].Runs[0].EffectiveProperties(doc); props.Color != "1F3864" || props.FontSize != 10.5 {
+		t.Errorf("表格样式未参与层叠: %+v", props)
+	}
+
+	item := doc.AddNumberedList("列表项", 1, ListTypeDecimal)
+	props := item.EffectiveProperties(doc)
+	if props.NumID == "" || props.NumLevel != 1 || props.IndentLeft <= 0 {
+		t.Errorf("编号级别的缩进未参与层叠: %+v", props)
+	}
+
+	outside := Run{Text: Text{Content: "游离文本"}}
+	if size := outside.EffectiveProperties(doc).FontSize; size != 10.5 {
+		t.Errorf("不在文档中的文本应使用默认段落样式: %v", size)
+	}
+}
@@ -0,0 +1,208 @@
+// Package document 文档加密功能测试
+package document
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// sequenceReader 返回递增字节的确定性随机数来源
+type sequenceReader struct {
+	next byte
+}
+
+func (r *sequenceReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.next
+		r.next++
+	}
+	return len(p), nil
+}
+
+// TestAgileKeyDerivation 使用已知向量测试Agile加密的密钥派生
+// 期望值由独立实现（Python hashlib）按 MS-OFFCRYPTO 2.3.4.11 - 2.3.4.13 的步骤计算：
+// H0 = SHA512(salt + UTF-16LE密码)，Hn = SHA512(LE32(n-1) + Hn-1) 迭代100000次，
+// 密钥 = SHA512(H + 块密钥) 截取32字节，分段初始向量 = SHA512(keyDataSalt + LE32(段号)) 截取16字节
+func TestAgileKeyDerivation(t *testing.T) {
+	salt := make([]byte, 16)
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	passwordHash := agilePasswordHash(sha512.New, "Password1234_", salt, 100000)
+
+	vectors := []struct {
+		blockKey []byte
+		expected string
+	}{
+		{blockKeyVerifierHashInput, "d79de5a4d066c1caf7856f110a72bce166d7928eff2d8a794da75748182b5f0d"},
+		{blockKeyEncryptedKey, "7a8b2091cd76dd40577bbc7b165de0985a9de0e0aded58ce94fc4b35294c0d0e"},
+	}
+	for _, v := range vectors {
+		key := fixAgileSize(agileDigest(sha512.New, passwordHash, v.blockKey), 32)
+		if hex.EncodeToString(key) != v.expected {
+			t.Errorf("派生密钥不正确: %x", key)
+		}
+	}
+
+	keyDataSalt := make([]byte, 16)
+	for i := range keyDataSalt {
+		keyDataSalt[i] = byte(16 + i)
+	}
+	iv := fixAgileSize(agileDigest(sha512.New, keyDataSalt, []byte{1, 0, 0, 0}), 16)
+	if hex.EncodeToString(iv) != "da22ccba062aff577d207b2dee4e165b" {
+		t.Errorf("分段初始向量不正确: %x", iv)
+	}
+}
+
+// TestEncryptedDocumentRoundTrip 测试加密保存和解密打开
+func TestEncryptedDocumentRoundTrip(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("工资明细")
+	for i := 0; i < 200; i++ {
+		doc.AddParagraph(fmt.Sprintf("员工%d：%d元", i, 10000+i))
+	}
+
+	filename := filepath.Join(t.TempDir(), "encrypted.docx")
+	if err := doc.SaveEncrypted(filename, ""); err == nil {
+		t.Error("空密码应返回错误")
+	}
+	if err := doc.SaveEncrypted(filename, "Password1234_"); err != nil {
+		t.Fatalf("保存加密文档失败: %v", err)
+	}
+	if _, err := Open(filename); err == nil {
+		t.Error("加密文档不应能直接打开")
+	}
+
+	if _, err := OpenEncrypted(filename, "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("密码错误应返回 ErrInvalidPassword，实际为: %v", err)
+	}
+	opened, err := OpenEncrypted(filename, "Password1234_")
+	if err != nil {
+		t.Fatalf("打开加密文档失败: %v", err)
+	}
+	paragraphs := opened.Body.GetParagraphs()
+	if len(paragraphs) != 201 || paragraphs[200].Runs[0].Text.Content != "员工199：10199元" {
+		t.Errorf("解密后的文档内容不正确，段落数: %d", len(paragraphs))
+	}
+}
+
+// TestEncryptedPackageIntegrity 测试加密结果的确定性和完整性校验
+func TestEncryptedPackageIntegrity(t *testing.T) {
+	data, err := New().ToBytes()
+	if err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+
+	first, err := encryptPackage(data, "secret", &sequenceReader{})
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	second, err := encryptPackage(data, "secret", &sequenceReader{})
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("相同的随机数来源应得到相同的加密结果")
+	}
+
+	streams, err := readCompoundFile(first)
+	if err != nil {
+		t.Fatalf("读取复合文件失败: %v", err)
+	}
+	for _, name := range []string{encryptionInfoStream, encryptedPackageStream, "\x06DataSpaces/DataSpaceMap",
+		"\x06DataSpaces/TransformInfo/StrongEncryptionTransform/\x06Primary"} {
+		if _, ok := streams[name]; !ok {
+			t.Errorf("复合文件中缺少流: %q", name)
+		}
+	}
+
+	// 篡改加密数据后完整性校验应失败
+	var tampered []cfbStream
+	for name, stream := range streams {
+		if name == encryptedPackageStream {
+			stream = append([]byte(nil), stream...)
+			stream[len(stream)-1] ^= 0xFF
+		}
+		tampered = append(tampered, cfbStream{Path: name, Data: stream})
+	}
+	tamperedFile, err := writeCompoundFile(tampered)
+	if err != nil {
+		t.Fatalf("写入复合文件失败: %v", err)
+	}
+	if _, err := decryptPackage(tamperedFile, "secret"); !errors.Is(err, ErrCorruptedFile) {
+		t.Errorf("篡改后应返回 ErrCorruptedFile，实际为: %v", err)
+	}
+}
+
+// TestEncryptedPackageSpinCountLimit 测试超出范围的迭代次数不会执行哈希计算
+func TestEncryptedPackageSpinCountLimit(t *testing.T) {
+	data, err := New().ToBytes()
+	if err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	encrypted, err := encryptPackage(data, "secret", &sequenceReader{})
+	if err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	streams, err := readCompoundFile(encrypted)
+	if err != nil {
+		t.Fatalf("读取复合文件失败: %v", err)
+	}
+
+	original := []byte(`spinCount="` + fmt.Sprint(agileSpinCount) + `"`)
+	for _, spinCount := range []string{"2147483647", "10000001", "-1"} {
+		var crafted []cfbStream
+		for name, stream := range streams {
+			if name == encryptionInfoStream {
+				stream = bytes.Replace(stream, original, []byte(`spinCount="`+spinCount+`"`), -1)
+			}
+			crafted = append(crafted, cfbStream{Path: name, Data: stream})
+		}
+		craftedFile, err := writeCompoundFile(crafted)
+		if err != nil {
+			t.Fatalf("写入复合文件失败: %v", err)
+		}
+		if _, err := decryptPackage(craftedFile, "secret"); !errors.Is(err, ErrCorruptedFile) {
+			t.Errorf("迭代次数 %s 应返回 ErrCorruptedFile，实际为: %v", spinCount, err)
+		}
+	}
+}
+
+// TestCompoundFileLargeStreams 测试复合文件中大小流混合及DIFAT扇区的读写
+func TestCompoundFileLargeStreams(t *testing.T) {
+	large := make([]byte, 8<<20) // 超过109个FAT扇区可描述的范围
+	for i := range large {
+		large[i] = byte(i * 7)
+	}
+	streams := []cfbStream{
+		{Path: "Large", Data: large},
+		{Path: "Small", Data: []byte("small stream")},
+		{Path: "Empty", Data: nil},
+		{Path: "Storage/Nested", Data: bytes.Repeat([]byte{1}, 5000)},
+	}
+	for i := 0; i < 20; i++ {
+		streams = append(streams, cfbStream{Path: fmt.Sprintf("Item%02d", i), Data: []byte{byte(i)}})
+	}
+
+	data, err := writeCompoundFile(streams)
+	if err != nil {
+		t.Fatalf("写入复合文件失败: %v", err)
+	}
+	read, err := readCompoundFile(data)
+	if err != nil {
+		t.Fatalf("读取复合文件失败: %v", err)
+	}
+	if len(read) != len(streams) {
+		t.Errorf("流数量不正确: %d", len(read))
+	}
+	for _, stream := range streams {
+		if !bytes.Equal(read[stream.Path], stream.Data) && len(stream.Data) > 0 {
+			t.Errorf("流 %s 内容不一致", stream.Path)
+		}
+	}
+}
@@ -22,6 +22,9 @@ var (
 
 	// ErrUnsupportedOperation 不支持的操作
 	ErrUnsupportedOperation = errors.New("unsupported operation")
+
+	// ErrInvalidPassword 密码错误
+	ErrInvalidPassword = errors.New("invalid password")
 )
 
 // DocumentError 文档操作错误
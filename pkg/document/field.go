@@ -4,6 +4,7 @@ package document
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 )
 
 // FieldChar 域字符
@@ -72,3 +73,172 @@ func CreatePageRefField(anchor string) PageRefField {
 		},
 	}
 }
+
+// createFieldRuns 创建复杂域的Run集合（begin、指令、separate、结果、end）
+func createFieldRuns(instruction, result string) []Run {
+	runs := []Run{
+		{FieldChar: &FieldChar{FieldCharType: "begin"}},
+		{InstrText: &InstrText{Space: "preserve", Content: instruction}},
+		{FieldChar: &FieldChar{FieldCharType: "separate"}},
+	}
+	if result != "" {
+		runs = append(runs, Run{Text: Text{Content: result, Space: "preserve"}})
+	}
+	return append(runs, Run{FieldChar: &FieldChar{FieldCharType: "end"}})
+}
+
+// AddField 在段落末尾添加域
+//
+// 参数 instruction 为域指令（如 PAGE、NUMPAGES、DATE \@ "yyyy-MM-dd"），
+// result 为Word更新域之前显示的结果文本。
+func (p *Paragraph) AddField(instruction, result string) {
+	p.Runs = append(p.Runs, createFieldRuns(" "+strings.TrimSpace(instruction)+" ", result)...)
+}
+
+// AddHyperlink 在段落末尾添加超链接（HYPERLINK 域），显示文本使用指定格式
+//
+// target 以 # 开头时链接到文档内的书签，否则为外部地址。
+func (p *Paragraph) AddHyperlink(target, text string, format *TextFormat) {
+	instruction := fmt.Sprintf(`HYPERLINK "%s"`, strings.ReplaceAll(target, `"`, `\"`))
+	if anchor := strings.TrimPrefix(target, "#"); anchor != target {
+		instruction = fmt.Sprintf(`HYPERLINK \l "%s"`, strings.ReplaceAll(anchor, `"`, `\"`))
+	}
+	p.AddField(instruction, "")
+	end := p.Runs[len(p.Runs)-1]
+	p.Runs = p.Runs[:len(p.Runs)-1]
+	p.AddFormattedText(text, format)
+	p.Runs = append(p.Runs, end)
+}
+
+// AddPageNumberField 在段落末尾添加当前页码域（PAGE）
+func (p *Paragraph) AddPageNumberField() {
+	p.Runs = append(p.Runs, createPageNumberRuns()...)
+}
+
+// AddPageCountField 在段落末尾添加总页数域（NUMPAGES）
+func (p *Paragraph) AddPageCountField() {
+	p.AddField("NUMPAGES  \\* MERGEFORMAT", "1")
+}
+
+// fieldInstruction 解析后的域指令
+type fieldInstruction struct {
+	Name     string            // 域名称，如 XE、INDEX、PAGE
+	Args     []string          // 位置参数（去除引号后）
+	Switches map[string]string // 开关及其参数，如 \t -> "参见 XXX"，无参数的开关值为空字符串
+}
+
+// parseFieldInstruction 解析域指令文本
+// 支持带引号的参数（引号内可使用 \" 转义）以及 \x 形式的开关
+func parseFieldInstruction(instr string) *fieldInstruction {
+	result := &fieldInstruction{Switches: make(map[string]string)}
+	tokens := tokenizeFieldInstruction(instr)
+	if len(tokens) == 0 {
+		return result
+	}
+
+	result.Name = strings.ToUpper(tokens[0].text)
+	for i := 1; i < len(tokens); i++ {
+		token := tokens[i]
+		if !token.quoted && strings.HasPrefix(token.text, "\\") && len(token.text) > 1 {
+			name := token.text
+			value := ""
+			// 开关后紧跟的参数（非开关）作为开关值
+			if i+1 < len(tokens) && (tokens[i+1].quoted || !strings.HasPrefix(tokens[i+1].text, "\\")) {
+				value = tokens[i+1].text
+				i++
+			}
+			result.Switches[name] = value
+			continue
+		}
+		result.Args = append(result.Args, token.text)
+	}
+	return result
+}
+
+// fieldToken 域指令词法单元
+type fieldToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeFieldInstruction 将域指令拆分为词法单元
+func tokenizeFieldInstruction(instr string) []fieldToken {
+	var tokens []fieldToken
+	runes := []rune(instr)
+	for i := 0; i < len(runes); {
+		switch {
+		case runes[i] == ' ' || runes[i] == '\t':
+			i++
+		case runes[i] == '"':
+			var sb strings.Builder
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == '"' {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			i++ // 跳过结束引号
+			tokens = append(tokens, fieldToken{text: sb.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, fieldToken{text: string(runes[start:i])})
+		}
+	}
+	return tokens
+}
+
+// quoteFieldArgument 为域参数添加引号并转义内部引号
+func quoteFieldArgument(value string) string {
+	return "\"" + strings.ReplaceAll(value, "\"", "\\\"") + "\""
+}
+
+// complexField 段落中的一个复杂域
+type complexField struct {
+	Instruction string // 域指令全文
+	BeginIndex  int    // begin 域字符所在Run的索引
+	EndIndex    int    // end 域字符所在Run的索引
+}
+
+// collectComplexFields 收集段落中的复杂域（fldChar/instrText 组合）
+// 对于嵌套域，内层域会先于外层域返回
+func collectComplexFields(paragraph *Paragraph) []complexField {
+	type openField struct {
+		begin       int
+		instr       strings.Builder
+		instrClosed bool
+	}
+
+	var fields []complexField
+	var stack []*openField
+	for i, run := range paragraph.Runs {
+		if run.FieldChar != nil {
+			switch run.FieldChar.FieldCharType {
+			case "begin":
+				stack = append(stack, &openField{begin: i})
+			case "separate":
+				if len(stack) > 0 {
+					stack[len(stack)-1].instrClosed = true
+				}
+			case "end":
+				if len(stack) > 0 {
+					top := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					fields = append(fields, complexField{
+						Instruction: top.instr.String(),
+						BeginIndex:  top.begin,
+						EndIndex:    i,
+					})
+				}
+			}
+		}
+		if run.InstrText != nil && len(stack) > 0 && !stack[len(stack)-1].instrClosed {
+			stack[len(stack)-1].instr.WriteString(run.InstrText.Content)
+		}
+	}
+	return fields
+}
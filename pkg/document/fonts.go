@@ -0,0 +1,727 @@
+// Package document 提供字体表（fontTable.xml）管理和字体嵌入功能
+//
+// 嵌入的字体按 ECMA-376 第1部分 17.8.1 的规定进行混淆：以随机GUID作为
+// w:fontKey，将字体数据前32字节与GUID字节（逆序）异或后保存为 .odttf 部件，
+// 并在字体表对应的 w:font 中添加 w:embedRegular 等引用。
+package document
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	fontTablePartName         = "word/fontTable.xml"
+	fontTableRelsPartName     = "word/_rels/fontTable.xml.rels"
+	fontTableRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable"
+	fontTableContentType      = "application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml"
+	fontRelationshipType      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/font"
+	obfuscatedFontContentType = "application/vnd.openxmlformats-officedocument.obfuscatedFont"
+	officeRelationshipsNS     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// FontStyle 嵌入字体的样式
+type FontStyle string
+
+const (
+	// FontStyleRegular 常规
+	FontStyleRegular FontStyle = "regular"
+	// FontStyleBold 粗体
+	FontStyleBold FontStyle = "bold"
+	// FontStyleItalic 斜体
+	FontStyleItalic FontStyle = "italic"
+	// FontStyleBoldItalic 粗斜体
+	FontStyleBoldItalic FontStyle = "boldItalic"
+)
+
+// fontEmbedElements 各样式对应的字体表元素名称
+var fontEmbedElements = map[FontStyle]string{
+	FontStyleRegular:    "embedRegular",
+	FontStyleBold:       "embedBold",
+	FontStyleItalic:     "embedItalic",
+	FontStyleBoldItalic: "embedBoldItalic",
+}
+
+// fontElementOrder CT_Font 子元素顺序
+var fontElementOrder = []string{
+	"altName", "panose1", "charset", "family", "notTrueType", "pitch", "sig",
+	"embedRegular", "embedBold", "embedItalic", "embedBoldItalic",
+}
+
+// FontEmbedOptions 字体嵌入选项
+type FontEmbedOptions struct {
+	// Style 字体文件对应的样式，默认为常规
+	Style FontStyle
+	// Subset 仅嵌入文档中实际使用的字符（保存时计算），可显著减小中文字体的体积
+	Subset bool
+	// Charset 字符集（w:charset），如 "86" 表示 GB2312，为空时不修改
+	Charset string
+	// Family 字体族（w:family）：roman、swiss、modern、script、decorative、auto，为空时不修改
+	Family string
+	// Pitch 字距（w:pitch）：fixed、variable、default，为空时不修改
+	Pitch string
+}
+
+// fontTable 字体表（word/fontTable.xml），未建模的内容原样保留
+type fontTable struct {
+	rootName      string             // 根元素名称（含前缀）
+	rootStart     []byte             // 原始根元素开始标签
+	fonts         []*fontEntry       // 字体，按原文档顺序
+	relationships *Relationships     // 字体表的关系（嵌入字体部件）
+	nextFontIndex int                // 下一个字体部件编号
+	embedded      []*embeddedFont    // 本次嵌入的字体，保存时写入部件
+	others        []*settingsElement // w:font 以外的子元素
+}
+
+// fontEntry 字体表中的单个字体
+type fontEntry struct {
+	name     string
+	start    []byte             // 原始开始标签，新建字体为nil
+	endName  string             // 结束标签名称
+	children []*settingsElement // 子元素原始XML
+}
+
+// embeddedFont 待写入的嵌入字体
+type embeddedFont struct {
+	entry     *fontEntry
+	element   string // 字体表元素名称（embedRegular等）
+	data      []byte // 未混淆的字体数据
+	subset    bool
+	fontKey   string
+	relID     string
+	partName  string
+	subsetted bool // 最近一次保存时是否成功子集化
+}
+
+// EmbedFont 将TrueType/OpenType字体嵌入文档
+//
+// name 为文档中使用的字体名称（与 w:rFonts 中的名称一致，如 "仿宋_GB2312"），
+// ttf 为字体文件内容。同一字体的粗体、斜体等样式需要分别嵌入。
+// 字体会以混淆格式保存为 word/fonts/fontN.odttf，并在 settings.xml 中开启
+// embedTrueTypeFonts。启用子集化时，保存文档时只保留正文、页眉页脚、脚注
+// 等部件中实际出现的字符（以及所有可打印ASCII字符）。
+//
+// 示例:
+//
+//	data, _ := os.ReadFile("fonts/FangSong_GB2312.ttf")
+//	err := doc.EmbedFont("仿宋_GB2312", data, &document.FontEmbedOptions{
+//		Subset:  true,
+//		Charset: "86",
+//	})
+func (d *Document) EmbedFont(name string, ttf []byte, opts *FontEmbedOptions) error {
+	if opts == nil {
+		opts = &FontEmbedOptions{}
+	}
+	if strings.TrimSpace(name) == "" {
+		return NewValidationError("name", name, "字体名称不能为空")
+	}
+	fontStyle := opts.Style
+	if fontStyle == "" {
+		fontStyle = FontStyleRegular
+	}
+	element, ok := fontEmbedElements[fontStyle]
+	if !ok {
+		return NewValidationError("style", string(fontStyle), "无效的字体样式")
+	}
+
+	font, err := parseSFNT(ttf)
+	if err != nil {
+		return WrapErrorWithContext("embed_font", NewValidationError("ttf", name, err.Error()), name)
+	}
+	if font.embeddingRestricted() {
+		return WrapErrorWithContext("embed_font", NewValidationError("ttf", name, "字体许可禁止嵌入（fsType为受限许可）"), name)
+	}
+	if opts.Subset && font.version == sfntVersionCFF {
+		Warnf("字体 %s 为CFF轮廓，将完整嵌入而不进行子集化", name)
+	}
+
+	fontKey, err := newFontKey()
+	if err != nil {
+		return WrapError("embed_font", err)
+	}
+
+	table := d.getFontTable()
+	entry := table.font(name)
+	if entry == nil {
+		entry = &fontEntry{name: name, endName: "w:font"}
+		table.fonts = append(table.fonts, entry)
+	}
+	entry.setVal("charset", opts.Charset)
+	entry.setVal("family", opts.Family)
+	entry.setVal("pitch", opts.Pitch)
+	d.removeEmbeddedFont(entry, element)
+
+	partName := table.nextFontPartName(d.parts)
+	relID := nextRelationshipID(table.relationships)
+	table.relationships.Relationships = append(table.relationships.Relationships, Relationship{
+		ID:     relID,
+		Type:   fontRelationshipType,
+		Target: strings.TrimPrefix(partName, "word/"),
+	})
+	table.embedded = append(table.embedded, &embeddedFont{
+		entry:    entry,
+		element:  element,
+		data:     append([]byte(nil), ttf...),
+		subset:   opts.Subset && font.version != sfntVersionCFF,
+		fontKey:  fontKey,
+		relID:    relID,
+		partName: partName,
+	})
+	// 占位，确保部件名称在保存前不会被重复分配
+	d.parts[partName] = nil
+
+	d.ensureObfuscatedFontContentType()
+	settings := d.Settings()
+	settings.EmbedTrueTypeFonts = true
+	if opts.Subset {
+		settings.SaveSubsetFonts = true
+	}
+
+	Infof("嵌入字体: %s (%s)", name, fontStyle)
+	return nil
+}
+
+// Fonts 返回文档中运行和样式引用的字体名称（已排序、去重）
+//
+// 包括正文（含表格、内容控件）、页眉页脚、脚注尾注、批注中运行属性的
+// w:rFonts，以及样式定义中的字体。主题字体引用（如 asciiTheme）不计入。
+func (d *Document) Fonts() []string {
+	names := make(map[string]bool)
+	addFamily := func(values ...string) {
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				names[value] = true
+			}
+		}
+	}
+
+	if d.Body != nil {
+		forEachParagraph(d.Body.Elements, func(p *Paragraph) {
+			for _, run := range p.Runs {
+				if run.Properties != nil && run.Properties.FontFamily != nil {
+					f := run.Properties.FontFamily
+					addFamily(f.ASCII, f.HAnsi, f.EastAsia, f.CS)
+				}
+			}
+		})
+	}
+
+	if d.styleManager != nil {
+		for _, s := range d.styleManager.GetAllStyles() {
+			if s.RunPr != nil && s.RunPr.FontFamily != nil {
+				f := s.RunPr.FontFamily
+				addFamily(f.ASCII, f.HAnsi, f.EastAsia, f.CS)
+			}
+		}
+	}
+
+	// 其他部件直接扫描XML，已编辑的页眉页脚先写回部件
+	if err := d.serializeHeaderFooters(); err != nil {
+		Warnf("序列化页眉页脚失败: %v", err)
+	}
+	for _, partName := range d.textPartNames(false) {
+		scanXMLElements(d.parts[partName], func(name xml.Name, attrs []xml.Attr, _ string) {
+			if name.Local == "rFonts" {
+				addFamily(getAttributeValue(attrs, "ascii"), getAttributeValue(attrs, "hAnsi"),
+					getAttributeValue(attrs, "eastAsia"), getAttributeValue(attrs, "cs"))
+			}
+		})
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getFontTable 获取文档的字体表，首次访问时从 fontTable.xml 解析，不存在时创建
+func (d *Document) getFontTable() *fontTable {
+	if d.fonts != nil {
+		return d.fonts
+	}
+
+	if data, ok := d.parts[fontTablePartName]; ok && len(data) > 0 {
+		table, err := parseFontTableXML(data)
+		if err == nil {
+			table.relationships = &Relationships{Xmlns: relationshipsNamespace}
+			if rels, ok := d.parts[fontTableRelsPartName]; ok {
+				if err := xml.Unmarshal(rels, table.relationships); err != nil {
+					Warnf("解析字体表关系失败: %v", err)
+				}
+				table.relationships.Xmlns = relationshipsNamespace
+			}
+			d.fonts = table
+			return d.fonts
+		}
+		Warnf("解析fontTable.xml失败，将重新创建字体表: %v", err)
+	}
+
+	d.fonts = &fontTable{relationships: &Relationships{Xmlns: relationshipsNamespace}}
+	d.addContentType(fontTablePartName, fontTableContentType)
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type == fontTableRelationshipType {
+			return d.fonts
+		}
+	}
+	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, Relationship{
+		ID:     fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2), // +2因为rId1保留给styles
+		Type:   fontTableRelationshipType,
+		Target: "fontTable.xml",
+	})
+	return d.fonts
+}
+
+// ensureObfuscatedFontContentType 确保 .odttf 扩展名已注册内容类型
+func (d *Document) ensureObfuscatedFontContentType() {
+	for _, def := range d.contentTypes.Defaults {
+		if strings.EqualFold(def.Extension, "odttf") {
+			return
+		}
+	}
+	d.contentTypes.Defaults = append(d.contentTypes.Defaults, Default{
+		Extension:   "odttf",
+		ContentType: obfuscatedFontContentType,
+	})
+}
+
+// removeEmbeddedFont 移除字体已有的指定样式嵌入（包括部件和关系）
+func (d *Document) removeEmbeddedFont(entry *fontEntry, element string) {
+	table := d.fonts
+	for i, font := range table.embedded {
+		if font.entry == entry && font.element == element {
+			table.embedded = append(table.embedded[:i], table.embedded[i+1:]...)
+			d.removeFontRelationship(font.relID)
+			return
+		}
+	}
+	for i, child := range entry.children {
+		if child.name.Local != element {
+			continue
+		}
+		if node, err := parseSettingsNode(child.raw); err == nil {
+			d.removeFontRelationship(getAttributeValue(node.attrs, "id"))
+		}
+		entry.children = append(entry.children[:i], entry.children[i+1:]...)
+		return
+	}
+}
+
+// removeFontRelationship 删除字体表关系及其目标部件
+func (d *Document) removeFontRelationship(relID string) {
+	rels := d.fonts.relationships
+	for i, rel := range rels.Relationships {
+		if rel.ID == relID {
+			delete(d.parts, "word/"+strings.TrimPrefix(rel.Target, "/word/"))
+			rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+			return
+		}
+	}
+}
+
+// serializeFontTable 写回字体表及嵌入字体部件（未访问过字体表时保持原文件不变）
+//
+// 需在正文和页眉页脚序列化之后调用，以便子集化使用最终的文本内容。
+func (d *Document) serializeFontTable() error {
+	table := d.fonts
+	if table == nil {
+		return nil
+	}
+
+	var chars map[rune]bool
+	for _, font := range table.embedded {
+		data := font.data
+		font.subsetted = false
+		if font.subset {
+			if chars == nil {
+				chars = d.usedCharacters()
+			}
+			subset, err := subsetTrueType(data, chars)
+			if err != nil {
+				Warnf("字体 %s 子集化失败，将完整嵌入: %v", font.entry.name, err)
+			} else {
+				data = subset
+				font.subsetted = true
+			}
+		}
+		obfuscated, err := obfuscateFont(data, font.fontKey)
+		if err != nil {
+			return WrapErrorWithContext("serialize_font_table", err, font.entry.name)
+		}
+		d.parts[font.partName] = obfuscated
+	}
+
+	d.parts[fontTablePartName] = table.marshal()
+	if len(table.relationships.Relationships) > 0 {
+		data, err := xml.MarshalIndent(table.relationships, "", "  ")
+		if err != nil {
+			return WrapError("serialize_font_table", err)
+		}
+		d.parts[fontTableRelsPartName] = append([]byte(xml.Header), data...)
+	} else {
+		delete(d.parts, fontTableRelsPartName)
+	}
+	return nil
+}
+
+// textPartNames 返回包含文本内容的部件名称（页眉页脚、脚注尾注、批注、编号）
+func (d *Document) textPartNames(includeBody bool) []string {
+	var names []string
+	for name := range d.parts {
+		if !strings.HasPrefix(name, "word/") || strings.Contains(name[len("word/"):], "/") {
+			continue
+		}
+		base := strings.TrimPrefix(name, "word/")
+		switch {
+		case base == "document.xml":
+			if includeBody {
+				names = append(names, name)
+			}
+		case strings.HasPrefix(base, "header"), strings.HasPrefix(base, "footer"),
+			base == "footnotes.xml", base == "endnotes.xml", base == "comments.xml", base == "numbering.xml":
+			if strings.HasSuffix(base, ".xml") {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// usedCharacters 收集文档中使用的字符，用于字体子集化
+//
+// 读取已序列化部件中的 w:t 文本和编号符号，并始终包含可打印ASCII字符，
+// 以覆盖页码等在显示时才生成的域结果。
+func (d *Document) usedCharacters() map[rune]bool {
+	chars := make(map[rune]bool)
+	for r := rune(0x20); r <= 0x7E; r++ {
+		chars[r] = true
+	}
+	for _, partName := range d.textPartNames(true) {
+		scanXMLElements(d.parts[partName], func(name xml.Name, attrs []xml.Attr, text string) {
+			switch name.Local {
+			case "t", "delText":
+				for _, r := range text {
+					chars[r] = true
+				}
+			case "lvlText":
+				for _, r := range getAttributeValue(attrs, "val") {
+					chars[r] = true
+				}
+			case "sym":
+				// 符号字符以十六进制码点表示
+				var code rune
+				if _, err := fmt.Sscanf(getAttributeValue(attrs, "char"), "%x", &code); err == nil {
+					chars[code] = true
+				}
+			}
+		})
+	}
+	return chars
+}
+
+// scanXMLElements 遍历XML中的所有元素，回调参数为元素名称、属性和直接文本内容
+func scanXMLElements(data []byte, fn func(name xml.Name, attrs []xml.Attr, text string)) {
+	if len(data) == 0 {
+		return
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	type openElement struct {
+		name  xml.Name
+		attrs []xml.Attr
+		text  strings.Builder
+	}
+	var stack []*openElement
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, &openElement{name: t.Name, attrs: t.Copy().Attr})
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return
+			}
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			fn(element.name, element.attrs, element.text.String())
+		}
+	}
+}
+
+// newFontKey 生成用于字体混淆的随机GUID，格式为 {XXXXXXXX-XXXX-XXXX-XXXX-XXXXXXXXXXXX}
+func newFontKey() (string, error) {
+	var guid [16]byte
+	if _, err := io.ReadFull(rand.Reader, guid[:]); err != nil {
+		return "", err
+	}
+	guid[6] = guid[6]&0x0F | 0x40 // 版本4
+	guid[8] = guid[8]&0x3F | 0x80
+	h := strings.ToUpper(hex.EncodeToString(guid[:]))
+	return fmt.Sprintf("{%s-%s-%s-%s-%s}", h[:8], h[8:12], h[12:16], h[16:20], h[20:]), nil
+}
+
+// fontObfuscationKey 由 w:fontKey 计算混淆密钥：GUID的16个字节按字符串中的逆序排列
+func fontObfuscationKey(fontKey string) ([]byte, error) {
+	digits := strings.NewReplacer("{", "", "}", "", "-", "").Replace(fontKey)
+	guid, err := hex.DecodeString(digits)
+	if err != nil || len(guid) != 16 {
+		return nil, fmt.Errorf("无效的字体密钥: %s", fontKey)
+	}
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = guid[15-i]
+	}
+	return key, nil
+}
+
+// obfuscateFont 混淆（或还原）字体数据：前32字节与密钥循环异或
+func obfuscateFont(data []byte, fontKey string) ([]byte, error) {
+	key, err := fontObfuscationKey(fontKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("字体数据过短")
+	}
+	result := append([]byte(nil), data...)
+	for i := 0; i < 32; i++ {
+		result[i] ^= key[i%16]
+	}
+	return result, nil
+}
+
+// font 按名称查找字体
+func (t *fontTable) font(name string) *fontEntry {
+	for _, entry := range t.fonts {
+		if entry.name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// nextFontPartName 返回下一个可用的嵌入字体部件名称
+func (t *fontTable) nextFontPartName(parts map[string][]byte) string {
+	for {
+		t.nextFontIndex++
+		name := fmt.Sprintf("word/fonts/font%d.odttf", t.nextFontIndex)
+		if _, exists := parts[name]; !exists {
+			return name
+		}
+	}
+}
+
+// setVal 设置 w:val 形式的子元素，val 为空时保持不变
+func (e *fontEntry) setVal(local, val string) {
+	if val == "" {
+		return
+	}
+	raw := []byte(`<w:` + local + ` w:val="` + escapeXMLAttr(val) + `"/>`)
+	for _, child := range e.children {
+		if child.name.Local == local {
+			child.raw = raw
+			return
+		}
+	}
+	e.children = append(e.children, &settingsElement{name: xml.Name{Space: "w", Local: local}, raw: raw})
+}
+
+// marshal 序列化字体表
+func (t *fontTable) marshal() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	rootName := t.rootName
+	if len(t.rootStart) > 0 {
+		root := string(t.rootStart)
+		if len(t.embedded) > 0 && !strings.Contains(root, `xmlns:r="`) {
+			root = strings.TrimSuffix(root, ">") + ` xmlns:r="` + officeRelationshipsNS + `">`
+		}
+		buf.WriteString(root)
+	} else {
+		rootName = "w:fonts"
+		buf.WriteString(`<w:fonts xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="` +
+			officeRelationshipsNS + `">`)
+	}
+
+	for _, entry := range t.fonts {
+		buf.WriteString("\n  ")
+		if len(entry.start) > 0 {
+			buf.Write(entry.start)
+		} else {
+			buf.WriteString(`<w:font w:name="` + escapeXMLAttr(entry.name) + `">`)
+		}
+
+		children := make(map[string][]byte)
+		var extra [][]byte
+		for _, child := range entry.children {
+			if child.name.Space == "w" && indexOf(fontElementOrder, child.name.Local) >= 0 {
+				children[child.name.Local] = child.raw
+			} else {
+				extra = append(extra, child.raw)
+			}
+		}
+		for _, font := range t.embedded {
+			if font.entry == entry {
+				element := `<w:` + font.element + ` r:id="` + font.relID + `" w:fontKey="` + font.fontKey + `"`
+				if font.subsetted {
+					element += ` w:subsetted="1"`
+				}
+				children[font.element] = []byte(element + `/>`)
+			}
+		}
+		for _, name := range fontElementOrder {
+			if raw, ok := children[name]; ok {
+				buf.WriteString("\n    ")
+				buf.Write(raw)
+			}
+		}
+		for _, raw := range extra {
+			buf.WriteString("\n    ")
+			buf.Write(raw)
+		}
+		buf.WriteString("\n  </" + entry.endName + ">")
+	}
+	for _, other := range t.others {
+		buf.WriteString("\n  ")
+		buf.Write(other.raw)
+	}
+
+	buf.WriteString("\n</" + rootName + ">")
+	return buf.Bytes()
+}
+
+// indexOf 返回字符串在切片中的位置，不存在时返回-1
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone 深拷贝字体表
+func (t *fontTable) clone() *fontTable {
+	cloned := &fontTable{
+		rootName:      t.rootName,
+		rootStart:     append([]byte(nil), t.rootStart...),
+		nextFontIndex: t.nextFontIndex,
+		relationships: &Relationships{
+			Xmlns:         t.relationships.Xmlns,
+			Relationships: append([]Relationship(nil), t.relationships.Relationships...),
+		},
+	}
+	cloneElement := func(element *settingsElement) *settingsElement {
+		return &settingsElement{name: element.name, raw: append([]byte(nil), element.raw...)}
+	}
+	entries := make(map[*fontEntry]*fontEntry, len(t.fonts))
+	for _, entry := range t.fonts {
+		clonedEntry := &fontEntry{
+			name:    entry.name,
+			start:   append([]byte(nil), entry.start...),
+			endName: entry.endName,
+		}
+		for _, child := range entry.children {
+			clonedEntry.children = append(clonedEntry.children, cloneElement(child))
+		}
+		entries[entry] = clonedEntry
+		cloned.fonts = append(cloned.fonts, clonedEntry)
+	}
+	for _, font := range t.embedded {
+		clonedFont := *font
+		clonedFont.entry = entries[font.entry]
+		cloned.embedded = append(cloned.embedded, &clonedFont)
+	}
+	for _, other := range t.others {
+		cloned.others = append(cloned.others, cloneElement(other))
+	}
+	return cloned
+}
+
+// parseFontTableXML 解析 fontTable.xml，字体的子元素按原始XML保留
+func parseFontTableXML(data []byte) (*fontTable, error) {
+	table := &fontTable{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	var elementStart int64
+	var entry *fontEntry
+	var other *settingsElement
+	var child *settingsElement
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, WrapError("parse_font_table", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			tag := string(data[offset:decoder.InputOffset()])
+			if strings.HasSuffix(tag, "/>") {
+				tag = strings.TrimSuffix(tag, "/>") + ">"
+			}
+			switch depth {
+			case 1:
+				table.rootName = qualifiedName(t.Name)
+				table.rootStart = []byte(tag)
+			case 2:
+				if t.Name.Local == "font" {
+					entry = &fontEntry{
+						name:    getAttributeValue(t.Attr, "name"),
+						start:   []byte(tag),
+						endName: qualifiedName(t.Name),
+					}
+					table.fonts = append(table.fonts, entry)
+				} else {
+					elementStart = offset
+					other = &settingsElement{name: t.Name}
+				}
+			case 3:
+				if entry != nil {
+					elementStart = offset
+					child = &settingsElement{name: t.Name}
+				}
+			}
+		case xml.EndElement:
+			switch depth {
+			case 2:
+				if other != nil {
+					other.raw = append([]byte(nil), data[elementStart:decoder.InputOffset()]...)
+					table.others = append(table.others, other)
+				}
+				entry, other = nil, nil
+			case 3:
+				if child != nil {
+					child.raw = append([]byte(nil), data[elementStart:decoder.InputOffset()]...)
+					entry.children = append(entry.children, child)
+					child = nil
+				}
+			}
+			depth--
+		}
+	}
+
+	if table.rootName == "" {
+		return nil, NewValidationError("fontTable.xml", "", "缺少w:fonts根元素")
+	}
+	return table, nil
+}
@@ -0,0 +1,232 @@
+// Package document 字体嵌入功能测试
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildTestFont 生成测试用的TrueType字体
+//
+// 字形：0 .notdef、1 'A'、2 'B'、3 '中'（引用字形1的复合字形）
+func buildTestFont(fsType uint16) []byte {
+	simple := func(marker byte) []byte {
+		glyph := make([]byte, 14)
+		binary.BigEndian.PutUint16(glyph, 1) // numberOfContours
+		glyph[13] = marker
+		return glyph
+	}
+	composite := make([]byte, 16)
+	binary.BigEndian.PutUint16(composite, 0xFFFF) // numberOfContours = -1
+	binary.BigEndian.PutUint16(composite[10:], 0x0001)
+	binary.BigEndian.PutUint16(composite[12:], 1) // 组件字形
+	glyphs := [][]byte{simple(0xA0), simple(0xA1), simple(0xA2), composite}
+
+	var glyf []byte
+	loca := make([]byte, (len(glyphs)+1)*4)
+	for i, glyph := range glyphs {
+		binary.BigEndian.PutUint32(loca[i*4:], uint32(len(glyf)))
+		glyf = append(glyf, glyph...)
+	}
+	binary.BigEndian.PutUint32(loca[len(glyphs)*4:], uint32(len(glyf)))
+
+	// cmap 格式4：'A'-'B' -> 1-2，'中' -> 3
+	segments := []struct{ start, end, delta uint16 }{
+		{'A', 'B', uint16((1 - 'A') & 0xFFFF)},
+		{'中', '中', uint16((3 - '中') & 0xFFFF)},
+		{0xFFFF, 0xFFFF, 1},
+	}
+	subtable := make([]byte, 16+len(segments)*8)
+	binary.BigEndian.PutUint16(subtable, 4)
+	binary.BigEndian.PutUint16(subtable[2:], uint16(len(subtable)))
+	binary.BigEndian.PutUint16(subtable[6:], uint16(len(segments)*2))
+	for i, seg := range segments {
+		binary.BigEndian.PutUint16(subtable[14+i*2:], seg.end)
+		binary.BigEndian.PutUint16(subtable[16+len(segments)*2+i*2:], seg.start)
+		binary.BigEndian.PutUint16(subtable[16+len(segments)*4+i*2:], seg.delta)
+	}
+	cmap := make([]byte, 12)
+	binary.BigEndian.PutUint16(cmap[2:], 1)
+	binary.BigEndian.PutUint16(cmap[4:], 3)
+	binary.BigEndian.PutUint16(cmap[6:], 1)
+	binary.BigEndian.PutUint32(cmap[8:], 12)
+	cmap = append(cmap, subtable...)
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint32(head, 0x00010000)
+	binary.BigEndian.PutUint32(head[12:], 0x5F0F3CF5)
+	binary.BigEndian.PutUint16(head[50:], 1) // 长偏移
+	maxp := make([]byte, 6)
+	binary.BigEndian.PutUint32(maxp, 0x00005000)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(len(glyphs)))
+	os2 := make([]byte, 78)
+	binary.BigEndian.PutUint16(os2[8:], fsType)
+
+	font := &sfntFont{version: sfntVersionTrueType, tables: []*sfntTable{
+		{tag: "cmap", data: cmap}, {tag: "glyf", data: glyf}, {tag: "head", data: head},
+		{tag: "loca", data: loca}, {tag: "maxp", data: maxp}, {tag: "OS/2", data: os2},
+	}}
+	return font.bytes()
+}
+
+// glyphData 返回字体中指定字形的数据
+func glyphData(t *testing.T, data []byte, glyph int) []byte {
+	t.Helper()
+	font, err := parseSFNT(data)
+	if err != nil {
+		t.Fatalf("解析字体失败: %v", err)
+	}
+	offsets, err := parseLoca(font.table("loca"), 4, true, len(font.table("glyf")))
+	if err != nil {
+		t.Fatalf("解析loca失败: %v", err)
+	}
+	return font.table("glyf")[offsets[glyph]:offsets[glyph+1]]
+}
+
+// TestFontObfuscation 测试字体混淆密钥的字节顺序
+func TestFontObfuscation(t *testing.T) {
+	key, err := fontObfuscationKey("{00112233-4455-6677-8899-AABBCCDDEEFF}")
+	if err != nil {
+		t.Fatalf("计算密钥失败: %v", err)
+	}
+	if key[0] != 0xFF || key[1] != 0xEE || key[15] != 0x00 {
+		t.Errorf("密钥字节顺序不正确: %x", key)
+	}
+
+	data := bytes.Repeat([]byte{0x5A}, 40)
+	obfuscated, err := obfuscateFont(data, "{00112233-4455-6677-8899-AABBCCDDEEFF}")
+	if err != nil {
+		t.Fatalf("混淆失败: %v", err)
+	}
+	if obfuscated[0] != 0x5A^0xFF || obfuscated[16] != 0x5A^0xFF || obfuscated[32] != 0x5A {
+		t.Errorf("只应混淆前32字节: %x", obfuscated)
+	}
+	restored, _ := obfuscateFont(obfuscated, "{00112233-4455-6677-8899-AABBCCDDEEFF}")
+	if !bytes.Equal(restored, data) {
+		t.Error("再次异或应还原字体数据")
+	}
+}
+
+// TestEmbedFont 测试嵌入字体、子集化及字体表的保存和重新打开
+func TestEmbedFont(t *testing.T) {
+	doc := New()
+	doc.AddFormattedParagraph("中", &TextFormat{FontFamily: "仿宋_GB2312"})
+
+	if err := doc.EmbedFont("受限字体", buildTestFont(0x0002), nil); err == nil {
+		t.Error("受限许可的字体不应允许嵌入")
+	}
+	if err := doc.EmbedFont("无效字体", []byte("not a font"), nil); err == nil {
+		t.Error("无效的字体数据应返回错误")
+	}
+	fontData := buildTestFont(0)
+	if err := doc.EmbedFont("仿宋_GB2312", fontData, &FontEmbedOptions{Subset: true, Charset: "86"}); err != nil {
+		t.Fatalf("嵌入字体失败: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "fonts.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	fontTableXML := string(opened.parts[fontTablePartName])
+	if !strings.Contains(fontTableXML, `<w:charset w:val="86"/>`) || !strings.Contains(fontTableXML, `w:subsetted="1"`) {
+		t.Errorf("字体表内容不正确:\n%s", fontTableXML)
+	}
+	if !opened.Settings().EmbedTrueTypeFonts || !opened.Settings().SaveSubsetFonts {
+		t.Error("应开启 embedTrueTypeFonts 和 saveSubsetFonts")
+	}
+	if !strings.Contains(string(opened.parts["[Content_Types].xml"]), `Extension="odttf"`) {
+		t.Error("应注册 odttf 内容类型")
+	}
+
+	table := opened.getFontTable()
+	entry := table.font("仿宋_GB2312")
+	if entry == nil {
+		t.Fatal("字体表中缺少嵌入的字体")
+	}
+	var fontKey, relID string
+	for _, child := range entry.children {
+		if child.name.Local == "embedRegular" {
+			node, _ := parseSettingsNode(child.raw)
+			fontKey, relID = getAttributeValue(node.attrs, "fontKey"), getAttributeValue(node.attrs, "id")
+		}
+	}
+	var target string
+	for _, rel := range table.relationships.Relationships {
+		if rel.ID == relID {
+			target = "word/" + rel.Target
+		}
+	}
+	embedded, err := obfuscateFont(opened.parts[target], fontKey)
+	if err != nil {
+		t.Fatalf("还原字体失败: %v", err)
+	}
+
+	// '中' 及其组件字形 'A'（可打印ASCII始终保留）应保留，校验和应有效
+	if len(glyphData(t, embedded, 3)) == 0 || len(glyphData(t, embedded, 1)) == 0 || len(glyphData(t, embedded, 0)) == 0 {
+		t.Error("使用的字形不应被清空")
+	}
+	if sfntChecksum(embedded) != headCheckSumMagic {
+		t.Errorf("字体校验和不正确: %x", sfntChecksum(embedded))
+	}
+
+	// 重新打开后追加粗体，原有的常规字体引用应保留
+	if err := opened.EmbedFont("仿宋_GB2312", fontData, &FontEmbedOptions{Style: FontStyleBold}); err != nil {
+		t.Fatalf("嵌入粗体失败: %v", err)
+	}
+	if _, err := opened.ToBytes(); err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	fontTableXML = string(opened.parts[fontTablePartName])
+	regular := strings.Index(fontTableXML, "<w:embedRegular")
+	bold := strings.Index(fontTableXML, "<w:embedBold")
+	if regular < 0 || bold < regular || strings.Count(fontTableXML, "<w:font ") != 1 {
+		t.Errorf("追加嵌入后的字体表不正确:\n%s", fontTableXML)
+	}
+}
+
+// TestSubsetTrueType 测试字形清空方式的子集化
+func TestSubsetTrueType(t *testing.T) {
+	subset, err := subsetTrueType(buildTestFont(0), map[rune]bool{'中': true})
+	if err != nil {
+		t.Fatalf("子集化失败: %v", err)
+	}
+	if len(glyphData(t, subset, 2)) != 0 {
+		t.Error("未使用的字形应被清空")
+	}
+	if len(glyphData(t, subset, 1)) == 0 || len(glyphData(t, subset, 3)) == 0 {
+		t.Error("复合字形及其组件应保留")
+	}
+}
+
+// TestDocumentFonts 测试列出文档引用的字体
+func TestDocumentFonts(t *testing.T) {
+	doc := New()
+	doc.AddFormattedParagraph("正文", &TextFormat{FontFamily: "仿宋_GB2312"})
+	doc.AddHeader(HeaderFooterTypeDefault, "页眉")
+	header := doc.GetHeader(HeaderFooterTypeDefault)
+	header.AddParagraph("公司名称").Runs[0].Properties = &RunProperties{FontFamily: &FontFamily{EastAsia: "方正小标宋简体"}}
+
+	fonts := doc.Fonts()
+	for _, name := range []string{"仿宋_GB2312", "方正小标宋简体"} {
+		found := false
+		for _, font := range fonts {
+			found = found || font == name
+		}
+		if !found {
+			t.Errorf("字体列表中缺少 %s: %v", name, fonts)
+		}
+	}
+	for i := 1; i < len(fonts); i++ {
+		if fonts[i-1] >= fonts[i] {
+			t.Errorf("字体列表应排序且去重: %v", fonts)
+		}
+	}
+}
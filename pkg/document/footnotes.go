@@ -83,6 +83,26 @@ const (
 	FootnoteFormatUpperLetter FootnoteNumberFormat = "upperLetter"
 	// FootnoteFormatSymbol 符号
 	FootnoteFormatSymbol FootnoteNumberFormat = "symbol"
+	// FootnoteFormatChineseCounting 中文计数（一、二、三）
+	FootnoteFormatChineseCounting FootnoteNumberFormat = "chineseCounting"
+	// FootnoteFormatChineseCountingThousand 中文计数（含百千万）
+	FootnoteFormatChineseCountingThousand FootnoteNumberFormat = "chineseCountingThousand"
+	// FootnoteFormatChineseLegalSimplified 中文大写数字（壹、贰、叁）
+	FootnoteFormatChineseLegalSimplified FootnoteNumberFormat = "chineseLegalSimplified"
+	// FootnoteFormatIdeographTraditional 天干（甲、乙、丙）
+	FootnoteFormatIdeographTraditional FootnoteNumberFormat = "ideographTraditional"
+	// FootnoteFormatDecimalEnclosedCircle 带圈数字（①、②、③）
+	FootnoteFormatDecimalEnclosedCircle FootnoteNumberFormat = "decimalEnclosedCircle"
+	// FootnoteFormatDecimalFullWidth 全角数字
+	FootnoteFormatDecimalFullWidth FootnoteNumberFormat = "decimalFullWidth"
+	// FootnoteFormatJapaneseCounting 日文计数
+	FootnoteFormatJapaneseCounting FootnoteNumberFormat = "japaneseCounting"
+	// FootnoteFormatKoreanDigital 韩文数字
+	FootnoteFormatKoreanDigital FootnoteNumberFormat = "koreanDigital"
+	// FootnoteFormatOrdinal 英文序数（1st、2nd）
+	FootnoteFormatOrdinal FootnoteNumberFormat = "ordinal"
+	// FootnoteFormatCardinalText 英文基数词（One、Two）
+	FootnoteFormatCardinalText FootnoteNumberFormat = "cardinalText"
 )
 
 // FootnoteRestart 脚注重新开始规则
@@ -127,44 +147,29 @@ type EndnoteProperties struct {
 	Position     string `xml:"w:pos,attr,omitempty"`
 }
 
-// Settings 文档设置XML结构
-type Settings struct {
-	XMLName                 xml.Name                 `xml:"w:settings"`
-	Xmlns                   string                   `xml:"xmlns:w,attr"`
-	DefaultTabStop          *DefaultTabStop          `xml:"w:defaultTabStop,omitempty"`
-	CharacterSpacingControl *CharacterSpacingControl `xml:"w:characterSpacingControl,omitempty"`
-	FootnotePr              *FootnotePr              `xml:"w:footnotePr,omitempty"`
-	EndnotePr               *EndnotePr               `xml:"w:endnotePr,omitempty"`
-}
-
-// DefaultTabStop 默认制表位设置
-type DefaultTabStop struct {
-	XMLName xml.Name `xml:"w:defaultTabStop"`
-	Val     string   `xml:"w:val,attr"`
-}
-
-// CharacterSpacingControl 字符间距控制
-type CharacterSpacingControl struct {
-	XMLName xml.Name `xml:"w:characterSpacingControl"`
-	Val     string   `xml:"w:val,attr"`
-}
-
 // FootnotePr 脚注属性设置
 type FootnotePr struct {
-	XMLName    xml.Name            `xml:"w:footnotePr"`
-	NumFmt     *FootnoteNumFmt     `xml:"w:numFmt,omitempty"`
-	NumStart   *FootnoteNumStart   `xml:"w:numStart,omitempty"`
-	NumRestart *FootnoteNumRestart `xml:"w:numRestart,omitempty"`
-	Pos        *FootnotePos        `xml:"w:pos,omitempty"`
+	XMLName          xml.Name            `xml:"w:footnotePr"`
+	Pos              *FootnotePos        `xml:"w:pos,omitempty"`
+	NumFmt           *FootnoteNumFmt     `xml:"w:numFmt,omitempty"`
+	NumStart         *FootnoteNumStart   `xml:"w:numStart,omitempty"`
+	NumRestart       *FootnoteNumRestart `xml:"w:numRestart,omitempty"`
+	SpecialFootnotes []*NoteSpecialRef   `xml:"w:footnote,omitempty"` // 分隔符等特殊脚注
 }
 
 // EndnotePr 尾注属性设置
 type EndnotePr struct {
-	XMLName    xml.Name           `xml:"w:endnotePr"`
-	NumFmt     *EndnoteNumFmt     `xml:"w:numFmt,omitempty"`
-	NumStart   *EndnoteNumStart   `xml:"w:numStart,omitempty"`
-	NumRestart *EndnoteNumRestart `xml:"w:numRestart,omitempty"`
-	Pos        *EndnotePos        `xml:"w:pos,omitempty"`
+	XMLName         xml.Name           `xml:"w:endnotePr"`
+	Pos             *EndnotePos        `xml:"w:pos,omitempty"`
+	NumFmt          *EndnoteNumFmt     `xml:"w:numFmt,omitempty"`
+	NumStart        *EndnoteNumStart   `xml:"w:numStart,omitempty"`
+	NumRestart      *EndnoteNumRestart `xml:"w:numRestart,omitempty"`
+	SpecialEndnotes []*NoteSpecialRef  `xml:"w:endnote,omitempty"` // 分隔符等特殊尾注
+}
+
+// NoteSpecialRef 设置中对特殊脚注/尾注（分隔符、延续分隔符）的引用
+type NoteSpecialRef struct {
+	ID string `xml:"w:id,attr"`
 }
 
 // FootnoteNumFmt 脚注编号格式
@@ -636,44 +641,10 @@ func (d *Document) RemoveEndnote(endnoteID string) error {
 	return nil
 }
 
-// ensureSettingsInitialized 确保文档设置已初始化
-func (d *Document) ensureSettingsInitialized() {
-	// 检查settings.xml是否存在，如果不存在则创建默认设置
-	if _, exists := d.parts["word/settings.xml"]; !exists {
-		d.initializeSettings()
-	}
-}
-
-// initializeSettings 初始化文档设置
-func (d *Document) initializeSettings() {
-	// 创建默认设置
-	settings := d.createDefaultSettings()
-
-	// 保存设置
-	if err := d.saveSettings(settings); err != nil {
-		// 如果保存失败，使用原有的硬编码方式作为后备
-		settingsXML := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
-  <w:defaultTabStop w:val="708"/>
-  <w:characterSpacingControl w:val="doNotCompress"/>
-</w:settings>`
-		d.parts["word/settings.xml"] = []byte(settingsXML)
-	}
-
-	// 添加内容类型
-	d.addContentType("word/settings.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml")
-
-	// 添加关系
-	d.addSettingsRelationship()
-}
-
 // updateDocumentSettings 更新文档设置中的脚注尾注配置
 func (d *Document) updateDocumentSettings(footnoteProps *FootnoteProperties, endnoteProps *EndnoteProperties) error {
-	// 解析现有的settings.xml
-	settings, err := d.parseSettings()
-	if err != nil {
-		return fmt.Errorf("解析设置文件失败: %v", err)
-	}
+	// 在现有设置的基础上修改，保留其他设置项
+	settings := d.Settings()
 
 	// 更新脚注设置
 	if footnoteProps != nil {
@@ -695,6 +666,10 @@ func (d *Document) updateDocumentSettings(footnoteProps *FootnoteProperties, end
 			footnotePr.Pos = &FootnotePos{Val: footnoteProps.Position}
 		}
 
+		// 保留对分隔符脚注的引用
+		if settings.FootnotePr != nil {
+			footnotePr.SpecialFootnotes = settings.FootnotePr.SpecialFootnotes
+		}
 		settings.FootnotePr = footnotePr
 	}
 
@@ -718,77 +693,13 @@ func (d *Document) updateDocumentSettings(footnoteProps *FootnoteProperties, end
 			endnotePr.Pos = &EndnotePos{Val: endnoteProps.Position}
 		}
 
+		// 保留对分隔符尾注的引用
+		if settings.EndnotePr != nil {
+			endnotePr.SpecialEndnotes = settings.EndnotePr.SpecialEndnotes
+		}
 		settings.EndnotePr = endnotePr
 	}
 
 	// 保存更新后的settings.xml
 	return d.saveSettings(settings)
 }
-
-// parseSettings 解析settings.xml文件
-func (d *Document) parseSettings() (*Settings, error) {
-	settingsData, exists := d.parts["word/settings.xml"]
-	if !exists {
-		// 如果settings.xml不存在，返回默认设置
-		return d.createDefaultSettings(), nil
-	}
-
-	var settings Settings
-
-	// 直接使用xml.Unmarshal可能有命名空间问题，我们改用字符串替换的方式
-	// 将w:settings替换为settings等，然后用一个简化的结构来解析
-	settingsStr := string(settingsData)
-
-	// 如果XML中包含w:前缀，说明是序列化的XML，直接创建默认设置并更新
-	// 这是一个简化的处理方式，避免命名空间解析问题
-	if len(settingsStr) > 0 {
-		// 如果文件存在且不为空，我们使用默认设置作为基础
-		settings = *d.createDefaultSettings()
-
-		// 后续可以在这里添加更复杂的XML解析逻辑
-		// 暂时简化处理，返回默认设置
-		return &settings, nil
-	}
-
-	return d.createDefaultSettings(), nil
-}
-
-// createDefaultSettings 创建默认设置
-func (d *Document) createDefaultSettings() *Settings {
-	return &Settings{
-		Xmlns: "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
-		DefaultTabStop: &DefaultTabStop{
-			Val: "708",
-		},
-		CharacterSpacingControl: &CharacterSpacingControl{
-			Val: "doNotCompress",
-		},
-	}
-}
-
-// saveSettings 保存settings.xml文件
-func (d *Document) saveSettings(settings *Settings) error {
-	// 序列化为XML
-	settingsXML, err := xml.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化settings.xml失败: %v", err)
-	}
-
-	// 添加XML声明
-	xmlDeclaration := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
-	d.parts["word/settings.xml"] = append(xmlDeclaration, settingsXML...)
-
-	return nil
-}
-
-// addSettingsRelationship 添加设置文件关系
-func (d *Document) addSettingsRelationship() {
-	relationshipID := fmt.Sprintf("rId%d", len(d.relationships.Relationships)+1)
-
-	relationship := Relationship{
-		ID:     relationshipID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings",
-		Target: "word/settings.xml",
-	}
-	d.relationships.Relationships = append(d.relationships.Relationships, relationship)
-}
@@ -22,50 +22,52 @@ const (
 
 // Header 页眉结构
 type Header struct {
-	XMLName     xml.Name     `xml:"w:hdr"`
-	XmlnsWPC    string       `xml:"xmlns:wpc,attr"`
-	XmlnsMC     string       `xml:"xmlns:mc,attr"`
-	XmlnsO      string       `xml:"xmlns:o,attr"`
-	XmlnsR      string       `xml:"xmlns:r,attr"`
-	XmlnsM      string       `xml:"xmlns:m,attr"`
-	XmlnsV      string       `xml:"xmlns:v,attr"`
-	XmlnsWP14   string       `xml:"xmlns:wp14,attr"`
-	XmlnsWP     string       `xml:"xmlns:wp,attr"`
-	XmlnsW10    string       `xml:"xmlns:w10,attr"`
-	XmlnsW      string       `xml:"xmlns:w,attr"`
-	XmlnsW14    string       `xml:"xmlns:w14,attr"`
-	XmlnsW15    string       `xml:"xmlns:w15,attr"`
-	XmlnsWPG    string       `xml:"xmlns:wpg,attr"`
-	XmlnsWPI    string       `xml:"xmlns:wpi,attr"`
-	XmlnsWNE    string       `xml:"xmlns:wne,attr"`
-	XmlnsWPS    string       `xml:"xmlns:wps,attr"`
-	XmlnsWPSCD  string       `xml:"xmlns:wpsCustomData,attr"`
-	MCIgnorable string       `xml:"mc:Ignorable,attr"`
-	Paragraphs  []*Paragraph `xml:"w:p"`
+	XMLName     xml.Name `xml:"w:hdr"`
+	XmlnsWPC    string   `xml:"xmlns:wpc,attr"`
+	XmlnsMC     string   `xml:"xmlns:mc,attr"`
+	XmlnsO      string   `xml:"xmlns:o,attr"`
+	XmlnsR      string   `xml:"xmlns:r,attr"`
+	XmlnsM      string   `xml:"xmlns:m,attr"`
+	XmlnsV      string   `xml:"xmlns:v,attr"`
+	XmlnsWP14   string   `xml:"xmlns:wp14,attr"`
+	XmlnsWP     string   `xml:"xmlns:wp,attr"`
+	XmlnsW10    string   `xml:"xmlns:w10,attr"`
+	XmlnsW      string   `xml:"xmlns:w,attr"`
+	XmlnsW14    string   `xml:"xmlns:w14,attr"`
+	XmlnsW15    string   `xml:"xmlns:w15,attr"`
+	XmlnsWPG    string   `xml:"xmlns:wpg,attr"`
+	XmlnsWPI    string   `xml:"xmlns:wpi,attr"`
+	XmlnsWNE    string   `xml:"xmlns:wne,attr"`
+	XmlnsWPS    string   `xml:"xmlns:wps,attr"`
+	XmlnsWPSCD  string   `xml:"xmlns:wpsCustomData,attr"`
+	MCIgnorable string   `xml:"mc:Ignorable,attr"`
+
+	headerFooterContent
 }
 
 // Footer 页脚结构
 type Footer struct {
-	XMLName     xml.Name     `xml:"w:ftr"`
-	XmlnsWPC    string       `xml:"xmlns:wpc,attr"`
-	XmlnsMC     string       `xml:"xmlns:mc,attr"`
-	XmlnsO      string       `xml:"xmlns:o,attr"`
-	XmlnsR      string       `xml:"xmlns:r,attr"`
-	XmlnsM      string       `xml:"xmlns:m,attr"`
-	XmlnsV      string       `xml:"xmlns:v,attr"`
-	XmlnsWP14   string       `xml:"xmlns:wp14,attr"`
-	XmlnsWP     string       `xml:"xmlns:wp,attr"`
-	XmlnsW10    string       `xml:"xmlns:w10,attr"`
-	XmlnsW      string       `xml:"xmlns:w,attr"`
-	XmlnsW14    string       `xml:"xmlns:w14,attr"`
-	XmlnsW15    string       `xml:"xmlns:w15,attr"`
-	XmlnsWPG    string       `xml:"xmlns:wpg,attr"`
-	XmlnsWPI    string       `xml:"xmlns:wpi,attr"`
-	XmlnsWNE    string       `xml:"xmlns:wne,attr"`
-	XmlnsWPS    string       `xml:"xmlns:wps,attr"`
-	XmlnsWPSCD  string       `xml:"xmlns:wpsCustomData,attr"`
-	MCIgnorable string       `xml:"mc:Ignorable,attr"`
-	Paragraphs  []*Paragraph `xml:"w:p"`
+	XMLName     xml.Name `xml:"w:ftr"`
+	XmlnsWPC    string   `xml:"xmlns:wpc,attr"`
+	XmlnsMC     string   `xml:"xmlns:mc,attr"`
+	XmlnsO      string   `xml:"xmlns:o,attr"`
+	XmlnsR      string   `xml:"xmlns:r,attr"`
+	XmlnsM      string   `xml:"xmlns:m,attr"`
+	XmlnsV      string   `xml:"xmlns:v,attr"`
+	XmlnsWP14   string   `xml:"xmlns:wp14,attr"`
+	XmlnsWP     string   `xml:"xmlns:wp,attr"`
+	XmlnsW10    string   `xml:"xmlns:w10,attr"`
+	XmlnsW      string   `xml:"xmlns:w,attr"`
+	XmlnsW14    string   `xml:"xmlns:w14,attr"`
+	XmlnsW15    string   `xml:"xmlns:w15,attr"`
+	XmlnsWPG    string   `xml:"xmlns:wpg,attr"`
+	XmlnsWPI    string   `xml:"xmlns:wpi,attr"`
+	XmlnsWNE    string   `xml:"xmlns:wne,attr"`
+	XmlnsWPS    string   `xml:"xmlns:wps,attr"`
+	XmlnsWPSCD  string   `xml:"xmlns:wpsCustomData,attr"`
+	MCIgnorable string   `xml:"mc:Ignorable,attr"`
+
+	headerFooterContent
 }
 
 // HeaderFooterReference 页眉页脚引用
@@ -115,7 +117,6 @@ func createStandardHeader() *Header {
 		XmlnsWPS:    "http://schemas.microsoft.com/office/word/2010/wordprocessingShape",
 		XmlnsWPSCD:  "http://www.wps.cn/officeDocument/2013/wpsCustomData",
 		MCIgnorable: "w14 w15 wp14",
-		Paragraphs:  make([]*Paragraph, 0),
 	}
 }
 
@@ -140,40 +141,12 @@ func createStandardFooter() *Footer {
 		XmlnsWPS:    "http://schemas.microsoft.com/office/word/2010/wordprocessingShape",
 		XmlnsWPSCD:  "http://www.wps.cn/officeDocument/2013/wpsCustomData",
 		MCIgnorable: "w14 w15 wp14",
-		Paragraphs:  make([]*Paragraph, 0),
 	}
 }
 
 // createPageNumberRuns 创建页码域代码的Run集合
 func createPageNumberRuns() []Run {
-	return []Run{
-		{
-			FieldChar: &FieldChar{
-				FieldCharType: "begin",
-			},
-		},
-		{
-			InstrText: &InstrText{
-				Space:   "preserve",
-				Content: " PAGE  \\* MERGEFORMAT ",
-			},
-		},
-		{
-			FieldChar: &FieldChar{
-				FieldCharType: "separate",
-			},
-		},
-		{
-			Text: Text{
-				Content: "1",
-			},
-		},
-		{
-			FieldChar: &FieldChar{
-				FieldCharType: "end",
-			},
-		},
-	}
+	return createFieldRuns(" PAGE  \\* MERGEFORMAT ", "1")
 }
 
 // getFileNameForType 获取页眉页脚文件名
@@ -205,42 +178,9 @@ func (d *Document) AddHeader(headerType HeaderFooterType, text string) error {
 		}
 		paragraph.Runs = append(paragraph.Runs, run)
 	}
-	header.Paragraphs = append(header.Paragraphs, paragraph)
-
-	// 生成关系ID
-	headerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页眉
-	headerXML, err := xml.MarshalIndent(header, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页眉失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), headerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("header", headerType)
-	headerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页眉内容
-	d.parts[headerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     headerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
+	header.AddElement(paragraph)
 
-	// 添加内容类型
-	d.addContentType(headerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml")
-
-	// 更新节属性
-	d.addHeaderReference(headerType, headerID)
-
-	return nil
+	return d.storeHeader(headerType, header)
 }
 
 // AddFooter 添加页脚
@@ -258,42 +198,9 @@ func (d *Document) AddFooter(footerType HeaderFooterType, text string) error {
 		}
 		paragraph.Runs = append(paragraph.Runs, run)
 	}
-	footer.Paragraphs = append(footer.Paragraphs, paragraph)
-
-	// 生成关系ID
-	footerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页脚
-	footerXML, err := xml.MarshalIndent(footer, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页脚失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), footerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("footer", footerType)
-	footerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页脚内容
-	d.parts[footerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     footerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
-
-	// 添加内容类型
-	d.addContentType(footerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml")
+	footer.AddElement(paragraph)
 
-	// 更新节属性
-	d.addFooterReference(footerType, footerID)
-
-	return nil
+	return d.storeFooter(footerType, footer)
 }
 
 // AddHeaderWithPageNumber 添加带页码的页眉
@@ -337,42 +244,9 @@ func (d *Document) AddHeaderWithPageNumber(headerType HeaderFooterType, text str
 		paragraph.Runs = append(paragraph.Runs, pageNumRun2)
 	}
 
-	header.Paragraphs = append(header.Paragraphs, paragraph)
-
-	// 生成关系ID
-	headerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页眉
-	headerXML, err := xml.MarshalIndent(header, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页眉失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), headerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("header", headerType)
-	headerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页眉内容
-	d.parts[headerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     headerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
-
-	// 添加内容类型
-	d.addContentType(headerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml")
+	header.AddElement(paragraph)
 
-	// 更新节属性
-	d.addHeaderReference(headerType, headerID)
-
-	return nil
+	return d.storeHeader(headerType, header)
 }
 
 // AddFooterWithPageNumber 添加带页码的页脚
@@ -416,42 +290,9 @@ func (d *Document) AddFooterWithPageNumber(footerType HeaderFooterType, text str
 		paragraph.Runs = append(paragraph.Runs, pageNumRun2)
 	}
 
-	footer.Paragraphs = append(footer.Paragraphs, paragraph)
-
-	// 生成关系ID
-	footerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页脚
-	footerXML, err := xml.MarshalIndent(footer, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页脚失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), footerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("footer", footerType)
-	footerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页脚内容
-	d.parts[footerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     footerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
-
-	// 添加内容类型
-	d.addContentType(footerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml")
-
-	// 更新节属性
-	d.addFooterReference(footerType, footerID)
+	footer.AddElement(paragraph)
 
-	return nil
+	return d.storeFooter(footerType, footer)
 }
 
 // HeaderFooterConfig 页眉页脚配置
@@ -517,6 +358,7 @@ func createFormattedParagraph(text string, format *TextFormat, alignment Alignme
 				color := strings.TrimPrefix(format.FontColor, "#")
 				runProps.Color = &Color{Val: color}
 			}
+			applyThemeFormat(runProps, format)
 
 			// 设置字体大小
 			if format.FontSize > 0 {
@@ -575,42 +417,9 @@ func (d *Document) AddFormattedHeader(headerType HeaderFooterType, config *Heade
 		config = &HeaderFooterConfig{}
 	}
 	paragraph := createFormattedParagraph(config.Text, config.Format, config.Alignment)
-	header.Paragraphs = append(header.Paragraphs, paragraph)
-
-	// 生成关系ID
-	headerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页眉
-	headerXML, err := xml.MarshalIndent(header, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页眉失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), headerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("header", headerType)
-	headerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页眉内容
-	d.parts[headerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     headerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
-
-	// 添加内容类型
-	d.addContentType(headerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml")
-
-	// 更新节属性
-	d.addHeaderReference(headerType, headerID)
+	header.AddElement(paragraph)
 
-	return nil
+	return d.storeHeader(headerType, header)
 }
 
 // AddFormattedFooter 添加格式化页脚
@@ -640,42 +449,9 @@ func (d *Document) AddFormattedFooter(footerType HeaderFooterType, config *Heade
 		config = &HeaderFooterConfig{}
 	}
 	paragraph := createFormattedParagraph(config.Text, config.Format, config.Alignment)
-	footer.Paragraphs = append(footer.Paragraphs, paragraph)
+	footer.AddElement(paragraph)
 
-	// 生成关系ID
-	footerID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
-
-	// 序列化页脚
-	footerXML, err := xml.MarshalIndent(footer, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化页脚失败: %v", err)
-	}
-
-	// 添加XML声明
-	fullXML := append([]byte(xml.Header), footerXML...)
-
-	// 获取文件名
-	fileName := getFileNameForType("footer", footerType)
-	footerPartName := fmt.Sprintf("word/%s", fileName)
-
-	// 存储页脚内容
-	d.parts[footerPartName] = fullXML
-
-	// 添加关系到文档关系
-	relationship := Relationship{
-		ID:     footerID,
-		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer",
-		Target: fileName,
-	}
-	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
-
-	// 添加内容类型
-	d.addContentType(footerPartName, "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml")
-
-	// 更新节属性
-	d.addFooterReference(footerType, footerID)
-
-	return nil
+	return d.storeFooter(footerType, footer)
 }
 
 // SetDifferentFirstPage 设置首页不同
@@ -688,10 +464,21 @@ func (d *Document) SetDifferentFirstPage(different bool) {
 	}
 }
 
+// SetDifferentOddEvenPages 设置奇偶页不同
+//
+// 开启后偶数页使用 HeaderFooterTypeEven 类型的页眉页脚，奇数页使用默认页眉页脚。
+// 该设置作用于整个文档（settings.xml 中的 w:evenAndOddHeaders）。
+func (d *Document) SetDifferentOddEvenPages(different bool) {
+	d.Settings().EvenAndOddHeaders = different
+}
+
 // addHeaderReference 添加页眉引用到节属性
 func (d *Document) addHeaderReference(headerType HeaderFooterType, headerID string) {
-	sectPr := d.getSectionPropertiesForHeaderFooter()
+	addHeaderReferenceTo(d.getSectionPropertiesForHeaderFooter(), headerType, headerID)
+}
 
+// addHeaderReferenceTo 添加页眉引用到指定的节属性
+func addHeaderReferenceTo(sectPr *SectionProperties, headerType HeaderFooterType, headerID string) {
 	// 确保设置关系命名空间
 	if sectPr.XmlnsR == "" {
 		sectPr.XmlnsR = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
@@ -702,6 +489,13 @@ func (d *Document) addHeaderReference(headerType HeaderFooterType, headerID stri
 		ID:   headerID,
 	}
 
+	// 同一类型的页眉只保留一个引用
+	for i, ref := range sectPr.HeaderReferences {
+		if ref.Type == string(headerType) {
+			sectPr.HeaderReferences[i] = headerRef
+			return
+		}
+	}
 	sectPr.HeaderReferences = append(sectPr.HeaderReferences, headerRef)
 }
 
@@ -719,6 +513,13 @@ func (d *Document) addFooterReference(footerType HeaderFooterType, footerID stri
 		ID:   footerID,
 	}
 
+	// 同一类型的页脚只保留一个引用
+	for i, ref := range sectPr.FooterReferences {
+		if ref.Type == string(footerType) {
+			sectPr.FooterReferences[i] = footerRef
+			return
+		}
+	}
 	sectPr.FooterReferences = append(sectPr.FooterReferences, footerRef)
 }
 
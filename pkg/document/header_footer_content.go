@@ -0,0 +1,730 @@
+// Package document 提供页眉页脚内容的解析与编辑功能
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	headerRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	footerRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	imageRelationshipType  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+)
+
+// headerFooterContent 页眉页脚的内容及其所属部件信息
+type headerFooterContent struct {
+	// Elements 页眉页脚中的元素（*Paragraph、*Table）
+	Elements []interface{} `xml:"-"`
+
+	doc           *Document      // 所属文档
+	partName      string         // 部件名称，如 word/header1.xml
+	relationships *Relationships // 部件关系（图片等）
+	rootAttrs     []xml.Attr     // 打开的文档中根元素的原始属性（命名空间声明等）
+
+	// 解析后立即序列化的结果，保存时与当前内容比较，未修改的部件保留原始XML
+	parsed     []byte
+	parsedRels []byte
+}
+
+// AddElement 添加元素（段落或表格）
+func (c *headerFooterContent) AddElement(element interface{}) {
+	c.Elements = append(c.Elements, element)
+}
+
+// AddParagraph 添加段落，返回的段落可使用与正文相同的段落方法设置格式
+func (c *headerFooterContent) AddParagraph(text string) *Paragraph {
+	paragraph := &Paragraph{}
+	if text != "" {
+		paragraph.Runs = append(paragraph.Runs, Run{
+			Text: Text{
+				Content: text,
+				Space:   "preserve",
+			},
+		})
+	}
+	if c.doc != nil {
+		c.doc.bindParagraph(paragraph)
+	}
+	c.AddElement(paragraph)
+	return paragraph
+}
+
+// AddTable 添加表格
+func (c *headerFooterContent) AddTable(config *TableConfig) (*Table, error) {
+	if c.doc == nil {
+		return nil, NewValidationError("header_footer", c.partName, "页眉页脚未关联文档")
+	}
+	table, err := c.doc.CreateTable(config)
+	if err != nil {
+		return nil, err
+	}
+	c.AddElement(table)
+	return table, nil
+}
+
+// GetParagraphs 获取所有段落（包括表格单元格中的段落）
+func (c *headerFooterContent) GetParagraphs() []*Paragraph {
+	var paragraphs []*Paragraph
+	forEachParagraph(c.Elements, func(paragraph *Paragraph) {
+		paragraphs = append(paragraphs, paragraph)
+	})
+	return paragraphs
+}
+
+// GetTables 获取所有表格
+func (c *headerFooterContent) GetTables() []*Table {
+	var tables []*Table
+	for _, element := range c.Elements {
+		if table, ok := element.(*Table); ok {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// GetText 获取页眉页脚的纯文本，段落之间以换行分隔
+func (c *headerFooterContent) GetText() string {
+	var lines []string
+	for _, paragraph := range c.GetParagraphs() {
+		var builder strings.Builder
+		for _, run := range paragraph.Runs {
+			builder.WriteString(run.Text.Content)
+		}
+		lines = append(lines, builder.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Clear 清空页眉页脚内容
+func (c *headerFooterContent) Clear() {
+	c.Elements = nil
+}
+
+// AddImageFromFile 从文件添加图片到页眉页脚
+func (c *headerFooterContent) AddImageFromFile(filePath string, config *ImageConfig) (*ImageInfo, error) {
+	imageData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, WrapErrorWithContext("read_image", err, filePath)
+	}
+	format, err := detectImageFormat(imageData)
+	if err != nil {
+		return nil, WrapErrorWithContext("detect_image_format", err, filePath)
+	}
+	width, height, err := getImageDimensions(imageData, format)
+	if err != nil {
+		return nil, WrapErrorWithContext("get_image_dimensions", err, filePath)
+	}
+	return c.AddImageFromData(imageData, filepath.Base(filePath), format, width, height, config)
+}
+
+// AddImageFromData 从数据添加图片到页眉页脚
+//
+// 图片关系写入页眉页脚自身的关系部件（如 word/_rels/header1.xml.rels）。
+func (c *headerFooterContent) AddImageFromData(imageData []byte, fileName string, format ImageFormat, width, height int, config *ImageConfig) (*ImageInfo, error) {
+	if c.doc == nil {
+		return nil, NewValidationError("header_footer", c.partName, "页眉页脚未关联文档")
+	}
+	d := c.doc
+
+	imageID, safeFileName := d.addMediaPart(imageData, fileName, format)
+	relationID := c.addImageRelationship(safeFileName)
+
+	imageInfo := &ImageInfo{
+		ID:         fmt.Sprintf("%d", imageID),
+		RelationID: relationID,
+		Format:     format,
+		Width:      width,
+		Height:     height,
+		Data:       imageData,
+		Config:     config,
+	}
+	c.AddElement(d.createImageParagraph(imageInfo))
+
+	Debugf("已添加页眉页脚图片: %s (%s)", safeFileName, c.partName)
+	return imageInfo, nil
+}
+
+// addImageRelationship 在页眉页脚的关系部件中添加图片关系，返回关系ID
+func (c *headerFooterContent) addImageRelationship(mediaFileName string) string {
+	if c.relationships == nil {
+		c.relationships = &Relationships{Xmlns: "http://schemas.openxmlformats.org/package/2006/relationships"}
+	}
+	relationID := nextRelationshipID(c.relationships)
+	c.relationships.Relationships = append(c.relationships.Relationships, Relationship{
+		ID:     relationID,
+		Type:   imageRelationshipType,
+		Target: "media/" + mediaFileName,
+	})
+	return relationID
+}
+
+// addMediaPart 保存图片到 word/media 目录，返回图片序号和文件名
+// 页眉页脚中的图片可能与正文图片使用相同的序号，因此跳过已存在的文件名
+func (d *Document) addMediaPart(imageData []byte, fileName string, format ImageFormat) (int, string) {
+	imageID := d.nextImageID
+	safeFileName := generateSafeImageFileName(imageID, fileName, format)
+	for {
+		if _, exists := d.parts["word/media/"+safeFileName]; !exists {
+			break
+		}
+		imageID++
+		safeFileName = generateSafeImageFileName(imageID, fileName, format)
+	}
+	d.nextImageID = imageID + 1
+	d.parts["word/media/"+safeFileName] = imageData
+	d.addImageContentType(format)
+	return imageID, safeFileName
+}
+
+// nextRelationshipID 生成关系集合中未使用的关系ID
+func nextRelationshipID(relationships *Relationships) string {
+	used := make(map[string]bool, len(relationships.Relationships))
+	for _, rel := range relationships.Relationships {
+		used[rel.ID] = true
+	}
+	for i := len(relationships.Relationships) + 1; ; i++ {
+		id := fmt.Sprintf("rId%d", i)
+		if !used[id] {
+			return id
+		}
+	}
+}
+
+// MarshalXML 序列化页眉
+func (h *Header) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	attrs := h.rootAttrs
+	if attrs == nil {
+		attrs = namespaceAttrs(
+			"xmlns:wpc", h.XmlnsWPC, "xmlns:mc", h.XmlnsMC, "xmlns:o", h.XmlnsO, "xmlns:r", h.XmlnsR,
+			"xmlns:m", h.XmlnsM, "xmlns:v", h.XmlnsV, "xmlns:wp14", h.XmlnsWP14, "xmlns:wp", h.XmlnsWP,
+			"xmlns:w10", h.XmlnsW10, "xmlns:w", h.XmlnsW, "xmlns:w14", h.XmlnsW14, "xmlns:w15", h.XmlnsW15,
+			"xmlns:wpg", h.XmlnsWPG, "xmlns:wpi", h.XmlnsWPI, "xmlns:wne", h.XmlnsWNE, "xmlns:wps", h.XmlnsWPS,
+			"xmlns:wpsCustomData", h.XmlnsWPSCD, "mc:Ignorable", h.MCIgnorable,
+		)
+	}
+	return h.marshalContent(e, "w:hdr", attrs)
+}
+
+// MarshalXML 序列化页脚
+func (f *Footer) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	attrs := f.rootAttrs
+	if attrs == nil {
+		attrs = namespaceAttrs(
+			"xmlns:wpc", f.XmlnsWPC, "xmlns:mc", f.XmlnsMC, "xmlns:o", f.XmlnsO, "xmlns:r", f.XmlnsR,
+			"xmlns:m", f.XmlnsM, "xmlns:v", f.XmlnsV, "xmlns:wp14", f.XmlnsWP14, "xmlns:wp", f.XmlnsWP,
+			"xmlns:w10", f.XmlnsW10, "xmlns:w", f.XmlnsW, "xmlns:w14", f.XmlnsW14, "xmlns:w15", f.XmlnsW15,
+			"xmlns:wpg", f.XmlnsWPG, "xmlns:wpi", f.XmlnsWPI, "xmlns:wne", f.XmlnsWNE, "xmlns:wps", f.XmlnsWPS,
+			"xmlns:wpsCustomData", f.XmlnsWPSCD, "mc:Ignorable", f.MCIgnorable,
+		)
+	}
+	return f.marshalContent(e, "w:ftr", attrs)
+}
+
+// namespaceAttrs 按名称/值对构建属性列表，忽略空值
+func namespaceAttrs(pairs ...string) []xml.Attr {
+	var attrs []xml.Attr
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: pairs[i]}, Value: pairs[i+1]})
+		}
+	}
+	return attrs
+}
+
+// marshalContent 输出页眉页脚根元素及其内容
+func (c *headerFooterContent) marshalContent(e *xml.Encoder, name string, attrs []xml.Attr) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	// 页眉页脚至少需要包含一个段落
+	if len(c.Elements) == 0 {
+		if err := e.Encode(&Paragraph{}); err != nil {
+			return err
+		}
+	}
+	for _, element := range c.Elements {
+		if err := e.Encode(element); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// GetHeader 获取文档（最后一节）指定类型的页眉
+//
+// 打开的文档会解析对应的页眉部件，返回的页眉可以使用与正文相同的方法编辑段落、
+// 表格、图片和域，修改在保存文档时写回。不存在该类型的页眉时返回nil。
+//
+// 示例:
+//
+//	header := doc.GetHeader(document.HeaderFooterTypeDefault)
+//	if header != nil {
+//		para := header.AddParagraph("第 ")
+//		para.AddPageNumberField()
+//		para.SetAlignment(document.AlignRight)
+//	}
+func (d *Document) GetHeader(headerType HeaderFooterType) *Header {
+	partName := d.headerFooterPartName(headerType, true)
+	if partName == "" {
+		return nil
+	}
+	return d.headerForPart(partName)
+}
+
+// headerForPart 获取指定部件的页眉对象，首次访问时解析部件内容
+func (d *Document) headerForPart(partName string) *Header {
+	if header, ok := d.headers[partName]; ok {
+		return header
+	}
+
+	header := &Header{}
+	if err := d.loadHeaderFooterContent(partName, &header.headerFooterContent); err != nil {
+		Errorf("解析页眉失败 %s: %v", partName, err)
+		return nil
+	}
+	d.cacheHeader(partName, header)
+	header.snapshot(header)
+	return header
+}
+
+// peekHeader 获取指定部件的页眉对象用于读取，已加载时返回缓存对象，
+// 否则临时解析，不加入缓存，保存时部件保持原样
+func (d *Document) peekHeader(partName string) *Header {
+	if header, ok := d.headers[partName]; ok {
+		return header
+	}
+
+	header := &Header{}
+	if err := d.loadHeaderFooterContent(partName, &header.headerFooterContent); err != nil {
+		Errorf("解析页眉失败 %s: %v", partName, err)
+		return nil
+	}
+	header.doc = d
+	header.partName = partName
+	return header
+}
+
+// loadHeaderFooters 解析文档引用的所有未加载的页眉页脚并加入缓存
+func (d *Document) loadHeaderFooters() {
+	for _, rel := range d.documentRelationships.Relationships {
+		partName := d.relationshipPartName(rel.ID)
+		switch rel.Type {
+		case headerRelationshipType:
+			d.headerForPart(partName)
+		case footerRelationshipType:
+			d.footerForPart(partName)
+		}
+	}
+}
+
+// GetFooter 获取文档（最后一节）指定类型的页脚，不存在时返回nil
+//
+// 用法与 GetHeader 相同。
+func (d *Document) GetFooter(footerType HeaderFooterType) *Footer {
+	partName := d.headerFooterPartName(footerType, false)
+	if partName == "" {
+		return nil
+	}
+	return d.footerForPart(partName)
+}
+
+// footerForPart 获取指定部件的页脚对象，首次访问时解析部件内容
+func (d *Document) footerForPart(partName string) *Footer {
+	if footer, ok := d.footers[partName]; ok {
+		return footer
+	}
+
+	footer := &Footer{}
+	if err := d.loadHeaderFooterContent(partName, &footer.headerFooterContent); err != nil {
+		Errorf("解析页脚失败 %s: %v", partName, err)
+		return nil
+	}
+	d.cacheFooter(partName, footer)
+	footer.snapshot(footer)
+	return footer
+}
+
+// headerFooterPartName 根据节属性中的引用查找页眉（isHeader为真）或页脚的部件名称
+func (d *Document) headerFooterPartName(headerFooterType HeaderFooterType, isHeader bool) string {
+	var sectPr *SectionProperties
+	for _, element := range d.Body.Elements {
+		if s, ok := element.(*SectionProperties); ok {
+			sectPr = s
+		}
+	}
+	if sectPr == nil {
+		return ""
+	}
+
+	relationID := ""
+	if isHeader {
+		for _, ref := range sectPr.HeaderReferences {
+			if ref.Type == string(headerFooterType) {
+				relationID = ref.ID
+			}
+		}
+	} else {
+		for _, ref := range sectPr.FooterReferences {
+			if ref.Type == string(headerFooterType) {
+				relationID = ref.ID
+			}
+		}
+	}
+	return d.relationshipPartName(relationID)
+}
+
+// relationshipPartName 根据文档关系ID获取目标部件名称，如 word/header1.xml
+func (d *Document) relationshipPartName(relationID string) string {
+	if relationID == "" {
+		return ""
+	}
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.ID == relationID {
+			return "word/" + strings.TrimPrefix(rel.Target, "/word/")
+		}
+	}
+	return ""
+}
+
+// cacheHeader 缓存页眉对象，保存文档时内容有修改则重新序列化
+func (d *Document) cacheHeader(partName string, header *Header) {
+	if d.headers == nil {
+		d.headers = make(map[string]*Header)
+	}
+	header.doc = d
+	header.partName = partName
+	d.bindElements(header.Elements)
+	d.headers[partName] = header
+}
+
+// cacheFooter 缓存页脚对象，保存文档时内容有修改则重新序列化
+func (d *Document) cacheFooter(partName string, footer *Footer) {
+	if d.footers == nil {
+		d.footers = make(map[string]*Footer)
+	}
+	footer.doc = d
+	footer.partName = partName
+	d.bindElements(footer.Elements)
+	d.footers[partName] = footer
+}
+
+// loadHeaderFooterContent 解析页眉页脚部件及其关系
+func (d *Document) loadHeaderFooterContent(partName string, content *headerFooterContent) error {
+	data, ok := d.parts[partName]
+	if !ok {
+		return WrapErrorWithContext("load_header_footer", ErrDocumentNotFound, partName)
+	}
+
+	rootAttrs, err := readRootAttrs(data)
+	if err != nil {
+		return WrapErrorWithContext("load_header_footer", err, partName)
+	}
+	content.rootAttrs = ensureNamespaceAttrs(rootAttrs)
+
+	elements, err := d.parseHeaderFooterElements(data)
+	if err != nil {
+		return WrapErrorWithContext("load_header_footer", err, partName)
+	}
+	content.Elements = elements
+
+	if relsData, ok := d.parts[headerFooterRelsPartName(partName)]; ok {
+		var relationships Relationships
+		if err := xml.Unmarshal(relsData, &relationships); err != nil {
+			return WrapErrorWithContext("load_header_footer_rels", err, partName)
+		}
+		content.relationships = &relationships
+	}
+	return nil
+}
+
+// headerFooterElements 获取页眉页脚部件中的元素
+// 已加载的部件返回缓存对象的元素，其余部件临时解析，不加入缓存
+func (d *Document) headerFooterElements(partName string) ([]interface{}, error) {
+	if header, ok := d.headers[partName]; ok {
+		return header.Elements, nil
+	}
+	if footer, ok := d.footers[partName]; ok {
+		return footer.Elements, nil
+	}
+	var content headerFooterContent
+	if err := d.loadHeaderFooterContent(partName, &content); err != nil {
+		return nil, err
+	}
+	return content.Elements, nil
+}
+
+// readRootAttrs 读取根元素的原始属性（保留前缀）
+func readRootAttrs(data []byte) ([]xml.Attr, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			attrs := make([]xml.Attr, 0, len(start.Attr))
+			for _, attr := range start.Attr {
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: qualifiedName(attr.Name)}, Value: attr.Value})
+			}
+			return attrs, nil
+		}
+	}
+}
+
+// ensureNamespaceAttrs 确保序列化内容所需的命名空间已声明
+func ensureNamespaceAttrs(attrs []xml.Attr) []xml.Attr {
+	required := []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:w"}, Value: "http://schemas.openxmlformats.org/wordprocessingml/2006/main"},
+		{Name: xml.Name{Local: "xmlns:r"}, Value: "http://schemas.openxmlformats.org/officeDocument/2006/relationships"},
+		{Name: xml.Name{Local: "xmlns:wp"}, Value: "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"},
+		{Name: xml.Name{Local: "xmlns:v"}, Value: "urn:schemas-microsoft-com:vml"},
+		{Name: xml.Name{Local: "xmlns:o"}, Value: "urn:schemas-microsoft-com:office:office"},
+		{Name: xml.Name{Local: "xmlns:w10"}, Value: "urn:schemas-microsoft-com:office:word"},
+	}
+	for _, req := range required {
+		found := false
+		for _, attr := range attrs {
+			if attr.Name.Local == req.Name.Local {
+				found = true
+				break
+			}
+		}
+		if !found {
+			attrs = append(attrs, req)
+		}
+	}
+	return attrs
+}
+
+// parseHeaderFooterElements 解析页眉页脚中的段落和表格
+// 块级内容控件（如Word页码库生成的 w:sdt）会展开为其内容
+func (d *Document) parseHeaderFooterElements(data []byte) ([]interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var elements []interface{}
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				depth++
+				continue
+			}
+			parsed, err := d.parseHeaderFooterElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, parsed...)
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return elements, nil
+}
+
+// parseHeaderFooterElement 解析页眉页脚的单个块级元素
+func (d *Document) parseHeaderFooterElement(decoder *xml.Decoder, startElement xml.StartElement) ([]interface{}, error) {
+	if startElement.Name.Local != "sdt" {
+		element, err := d.parseBodySubElement(decoder, startElement)
+		if err != nil || element == nil {
+			return nil, err
+		}
+		return []interface{}{element}, nil
+	}
+
+	var elements []interface{}
+	inContent := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, WrapError("parse_header_footer_sdt", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "sdtContent":
+				inContent = true
+			case inContent:
+				parsed, err := d.parseHeaderFooterElement(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, parsed...)
+			default:
+				if err := d.skipElement(decoder, t.Name.Local); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "sdtContent":
+				inContent = false
+			case "sdt":
+				return elements, nil
+			}
+		}
+	}
+}
+
+// headerFooterRelsPartName 获取页眉页脚关系部件名称，如 word/_rels/header1.xml.rels
+func headerFooterRelsPartName(partName string) string {
+	return path.Join(path.Dir(partName), "_rels", path.Base(partName)+".rels")
+}
+
+// storeHeader 保存页眉部件，添加关系、内容类型和节属性引用
+func (d *Document) storeHeader(headerType HeaderFooterType, header *Header) error {
+	partName, relationID := d.prepareHeaderFooterPart("header", headerType, true)
+	d.cacheHeader(partName, header)
+	if err := d.serializeHeaderFooterPart(partName, header, header.relationships); err != nil {
+		return fmt.Errorf("序列化页眉失败: %v", err)
+	}
+
+	d.addContentType(partName, "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml")
+	d.addHeaderReference(headerType, relationID)
+	if headerType == HeaderFooterTypeEven {
+		d.SetDifferentOddEvenPages(true)
+	}
+	return nil
+}
+
+// storeFooter 保存页脚部件，添加关系、内容类型和节属性引用
+func (d *Document) storeFooter(footerType HeaderFooterType, footer *Footer) error {
+	partName, relationID := d.prepareHeaderFooterPart("footer", footerType, false)
+	d.cacheFooter(partName, footer)
+	if err := d.serializeHeaderFooterPart(partName, footer, footer.relationships); err != nil {
+		return fmt.Errorf("序列化页脚失败: %v", err)
+	}
+
+	d.addContentType(partName, "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml")
+	d.addFooterReference(footerType, relationID)
+	if footerType == HeaderFooterTypeEven {
+		d.SetDifferentOddEvenPages(true)
+	}
+	return nil
+}
+
+// prepareHeaderFooterPart 确定页眉页脚的部件名称和关系ID
+// 已存在同类型的页眉页脚时复用其部件（内容被替换），否则创建不与已有部件冲突的新部件
+func (d *Document) prepareHeaderFooterPart(prefix string, headerFooterType HeaderFooterType, isHeader bool) (string, string) {
+	if partName := d.headerFooterPartName(headerFooterType, isHeader); partName != "" {
+		for _, rel := range d.documentRelationships.Relationships {
+			if "word/"+rel.Target == partName {
+				delete(d.parts, headerFooterRelsPartName(partName))
+				return partName, rel.ID
+			}
+		}
+	}
+
+	return d.newHeaderFooterPart(prefix, headerFooterType, isHeader)
+}
+
+// newHeaderFooterPart 创建不与已有部件冲突的页眉页脚部件名称及其文档关系
+func (d *Document) newHeaderFooterPart(prefix string, headerFooterType HeaderFooterType, isHeader bool) (string, string) {
+	fileName := getFileNameForType(prefix, headerFooterType)
+	baseName := strings.TrimSuffix(strings.TrimSuffix(fileName, ".xml"), "1")
+	for i := 2; ; i++ {
+		if _, exists := d.parts["word/"+fileName]; !exists {
+			break
+		}
+		fileName = fmt.Sprintf("%s%d.xml", baseName, i)
+	}
+
+	relationType := headerRelationshipType
+	if !isHeader {
+		relationType = footerRelationshipType
+	}
+	relationID := fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2) // +2因为rId1保留给styles
+	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, Relationship{
+		ID:     relationID,
+		Type:   relationType,
+		Target: fileName,
+	})
+	return "word/" + fileName, relationID
+}
+
+// marshalHeaderFooterPart 序列化页眉页脚部件及其关系部件，没有关系时relsData为nil
+func marshalHeaderFooterPart(part interface{}, relationships *Relationships) ([]byte, []byte, error) {
+	data, err := xml.MarshalIndent(part, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	var relsData []byte
+	if relationships != nil && len(relationships.Relationships) > 0 {
+		if relsData, err = xml.MarshalIndent(relationships, "", "  "); err != nil {
+			return nil, nil, err
+		}
+	}
+	return data, relsData, nil
+}
+
+// snapshot 记录刚解析的内容的序列化结果，part为内容所属的页眉或页脚
+// 解析会丢弃模型不支持的内容（制表位、书签、超链接、内容控件等），
+// 未修改的部件在保存时保留原始XML
+func (c *headerFooterContent) snapshot(part interface{}) {
+	data, relsData, err := marshalHeaderFooterPart(part, c.relationships)
+	if err != nil {
+		return
+	}
+	c.parsed, c.parsedRels = data, relsData
+}
+
+// unchanged 判断内容自解析以来是否未被修改
+func (c *headerFooterContent) unchanged(part interface{}) bool {
+	if c.parsed == nil {
+		return false
+	}
+	data, relsData, err := marshalHeaderFooterPart(part, c.relationships)
+	return err == nil && bytes.Equal(data, c.parsed) && bytes.Equal(relsData, c.parsedRels)
+}
+
+// serializeHeaderFooterPart 序列化页眉页脚部件及其关系部件
+func (d *Document) serializeHeaderFooterPart(partName string, part interface{}, relationships *Relationships) error {
+	data, relsData, err := marshalHeaderFooterPart(part, relationships)
+	if err != nil {
+		return err
+	}
+	d.parts[partName] = append([]byte(xml.Header), data...)
+	if relsData != nil {
+		d.parts[headerFooterRelsPartName(partName)] = append([]byte(xml.Header), relsData...)
+	}
+	return nil
+}
+
+// serializeHeaderFooters 将编辑过的页眉页脚写回文档部件，未修改的部件保持原样
+func (d *Document) serializeHeaderFooters() error {
+	for partName, header := range d.headers {
+		if header.unchanged(header) {
+			continue
+		}
+		if err := d.serializeHeaderFooterPart(partName, header, header.relationships); err != nil {
+			return WrapErrorWithContext("serialize_header", err, partName)
+		}
+	}
+	for partName, footer := range d.footers {
+		if footer.unchanged(footer) {
+			continue
+		}
+		if err := d.serializeHeaderFooterPart(partName, footer, footer.relationships); err != nil {
+			return WrapErrorWithContext("serialize_footer", err, partName)
+		}
+	}
+	return nil
+}
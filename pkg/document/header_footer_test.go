@@ -0,0 +1,156 @@
+// Package document 页眉页脚功能测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHeaderFooterEditRoundTrip 测试页眉页脚的读取、编辑和保存
+func TestHeaderFooterEditRoundTrip(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+	if err := doc.AddHeader(HeaderFooterTypeDefault, "公司名称"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	if err := doc.AddFooterWithPageNumber(HeaderFooterTypeDefault, "第", true); err != nil {
+		t.Fatalf("添加页脚失败: %v", err)
+	}
+	if err := doc.AddHeader(HeaderFooterTypeEven, "偶数页页眉"); err != nil {
+		t.Fatalf("添加偶数页页眉失败: %v", err)
+	}
+	if !doc.Settings().EvenAndOddHeaders {
+		t.Error("添加偶数页页眉后应启用奇偶页不同")
+	}
+
+	header := doc.GetHeader(HeaderFooterTypeDefault)
+	if header == nil || header.GetText() != "公司名称" {
+		t.Fatalf("获取页眉失败: %+v", header)
+	}
+	para := header.AddParagraph("页码 ")
+	para.AddPageNumberField()
+	para.SetAlignment(AlignRight)
+	if _, err := header.AddTable(&TableConfig{Rows: 1, Cols: 2, Width: 6000, Data: [][]string{{"左", "右"}}}); err != nil {
+		t.Fatalf("页眉添加表格失败: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "header_footer.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	header = opened.GetHeader(HeaderFooterTypeDefault)
+	if header == nil {
+		t.Fatal("重新打开后应能获取页眉")
+	}
+	if len(header.GetTables()) != 1 || len(header.GetParagraphs()) != 4 {
+		t.Errorf("页眉内容不正确: 表格%d 段落%d", len(header.GetTables()), len(header.GetParagraphs()))
+	}
+	fields := collectComplexFields(header.GetParagraphs()[1])
+	if len(fields) != 1 || !strings.Contains(fields[0].Instruction, "PAGE") {
+		t.Errorf("页眉中的页码域应被保留: %+v", fields)
+	}
+
+	footer := opened.GetFooter(HeaderFooterTypeDefault)
+	if footer == nil || !strings.Contains(footer.GetText(), "第") {
+		t.Fatalf("获取页脚失败: %+v", footer)
+	}
+	if opened.GetHeader(HeaderFooterTypeEven) == nil || opened.GetFooter(HeaderFooterTypeFirst) != nil {
+		t.Error("偶数页页眉应存在，首页页脚不应存在")
+	}
+	if !opened.Settings().EvenAndOddHeaders {
+		t.Error("奇偶页不同设置应被保留")
+	}
+
+	// 修改已打开文档的页眉并再次保存
+	header.Clear()
+	header.AddParagraph("新页眉")
+	filename2 := filepath.Join(t.TempDir(), "header_footer2.docx")
+	if err := opened.Save(filename2); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename2)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if text := reopened.GetHeader(HeaderFooterTypeDefault).GetText(); text != "新页眉" {
+		t.Errorf("修改后的页眉应被保存，实际为: %q", text)
+	}
+}
+
+// TestParseHeaderSimpleField 测试解析页眉中的简单域和内容控件
+func TestParseHeaderSimpleField(t *testing.T) {
+	doc := New()
+	if err := doc.AddFooter(HeaderFooterTypeDefault, "占位"); err != nil {
+		t.Fatalf("添加页脚失败: %v", err)
+	}
+	footer := doc.GetFooter(HeaderFooterTypeDefault)
+	partName := footer.partName
+	delete(doc.footers, partName)
+
+	doc.parts[partName] = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml"><w:sdt><w:sdtPr><w:docPartObj><w:docPartGallery w:val="Page Numbers (Bottom of Page)"/></w:docPartObj></w:sdtPr><w:sdtContent><w:p><w:fldSimple w:instr=" PAGE "><w:r><w:t>3</w:t></w:r></w:fldSimple></w:p></w:sdtContent></w:sdt></w:ftr>`)
+
+	footer = doc.GetFooter(HeaderFooterTypeDefault)
+	if footer == nil {
+		t.Fatal("应能解析页脚")
+	}
+	paragraphs := footer.GetParagraphs()
+	if len(paragraphs) != 1 {
+		t.Fatalf("内容控件中的段落应被展开，实际段落数: %d", len(paragraphs))
+	}
+	fields := collectComplexFields(paragraphs[0])
+	if len(fields) != 1 || strings.TrimSpace(fields[0].Instruction) != "PAGE" {
+		t.Errorf("简单域应转换为复杂域: %+v", fields)
+	}
+
+	// 未修改的页脚保留原始XML，修改后重新序列化
+	original := string(doc.parts[partName])
+	if err := doc.serializeHeaderFooters(); err != nil {
+		t.Fatalf("序列化页脚失败: %v", err)
+	}
+	if string(doc.parts[partName]) != original {
+		t.Error("未修改的页脚应保持原样")
+	}
+	footer.AddParagraph("第二段")
+	if err := doc.serializeHeaderFooters(); err != nil {
+		t.Fatalf("序列化页脚失败: %v", err)
+	}
+	output := string(doc.parts[partName])
+	if !strings.Contains(output, `xmlns:w14=`) || !strings.Contains(output, `w:fldCharType="begin"`) {
+		t.Errorf("序列化结果应保留命名空间并包含域: %s", output)
+	}
+}
+
+// TestHeaderFooterUnmodifiedRoundTrip 测试读取但未修改的页眉保存时保留模型不支持的内容
+func TestHeaderFooterUnmodifiedRoundTrip(t *testing.T) {
+	doc := New()
+	if err := doc.AddHeader(HeaderFooterTypeDefault, "占位"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	partName := doc.GetHeader(HeaderFooterTypeDefault).partName
+	delete(doc.headers, partName)
+	original := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:sdt><w:sdtPr><w:alias w:val="标题"/></w:sdtPr><w:sdtContent><w:p><w:pPr><w:tabs><w:tab w:val="right" w:pos="9000"/></w:tabs></w:pPr><w:bookmarkStart w:id="0" w:name="top"/><w:r><w:t>公司</w:t></w:r><w:r><w:tab/></w:r><w:bookmarkEnd w:id="0"/><w:hyperlink r:id="rId9"><w:r><w:t>官网</w:t></w:r></w:hyperlink></w:p></w:sdtContent></w:sdt></w:hdr>`
+	doc.parts[partName] = []byte(original)
+
+	filename := filepath.Join(t.TempDir(), "header_roundtrip.docx")
+	if header := doc.GetHeader(HeaderFooterTypeDefault); header == nil || !strings.Contains(header.GetText(), "公司") {
+		t.Fatalf("应能解析页眉: %+v", header)
+	}
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if output := string(opened.parts[partName]); output != original {
+		t.Errorf("未修改的页眉应原样保存，实际为: %s", output)
+	}
+}
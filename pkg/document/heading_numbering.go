@@ -0,0 +1,271 @@
+// Package document 提供标题自动编号功能
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// HeadingNumberingScheme 标题编号方案
+type HeadingNumberingScheme string
+
+const (
+	// HeadingNumberingNone 取消标题编号
+	HeadingNumberingNone HeadingNumberingScheme = "none"
+	// HeadingNumberingLegal 法律/技术文档多级编号：1、1.1、1.1.1 ...
+	HeadingNumberingLegal HeadingNumberingScheme = "legal"
+	// HeadingNumberingChinese 中文公文编号：第一章、一、（一）、1.、（1）...
+	HeadingNumberingChinese HeadingNumberingScheme = "chinese"
+	// HeadingNumberingAppendix 附录编号：附录A、A.1、A.1.1 ...
+	HeadingNumberingAppendix HeadingNumberingScheme = "appendix"
+)
+
+// headingLevelDef 标题编号级别定义
+type headingLevelDef struct {
+	numFmt    string // 编号格式
+	levelText string // 级别文本
+	suffix    string // 编号后缀
+}
+
+// headingNumberingSchemes 各编号方案的9级定义
+var headingNumberingSchemes = map[HeadingNumberingScheme][]headingLevelDef{
+	HeadingNumberingLegal: {
+		{"decimal", "%1", "space"},
+		{"decimal", "%1.%2", "space"},
+		{"decimal", "%1.%2.%3", "space"},
+		{"decimal", "%1.%2.%3.%4", "space"},
+		{"decimal", "%1.%2.%3.%4.%5", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8.%9", "space"},
+	},
+	HeadingNumberingChinese: {
+		{"chineseCounting", "第%1章", "space"},
+		{"chineseCounting", "%2、", "nothing"},
+		{"chineseCounting", "（%3）", "nothing"},
+		{"decimal", "%4.", "space"},
+		{"decimal", "（%5）", "nothing"},
+		{"decimalEnclosedCircle", "%6", "space"},
+		{"lowerLetter", "%7.", "space"},
+		{"lowerLetter", "（%8）", "nothing"},
+		{"lowerRoman", "%9.", "space"},
+	},
+	HeadingNumberingAppendix: {
+		{"upperLetter", "附录%1", "space"},
+		{"decimal", "%1.%2", "space"},
+		{"decimal", "%1.%2.%3", "space"},
+		{"decimal", "%1.%2.%3.%4", "space"},
+		{"decimal", "%1.%2.%3.%4.%5", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8", "space"},
+		{"decimal", "%1.%2.%3.%4.%5.%6.%7.%8.%9", "space"},
+	},
+}
+
+// SetHeadingNumbering 为标题样式（Heading1 - Heading9）设置自动编号
+//
+// 该方法创建一个多级编号定义，其每一级通过 w:pStyle 关联到对应的标题样式，
+// 并将编号属性（w:numPr）写入样式管理器中的标题样式。之后通过
+// AddHeadingParagraph 添加的标题会由Word自动编号，生成的目录也会包含编号。
+//
+// 再次调用时替换已有标题编号定义的各级格式，编号实例保持不变；
+// 传入 HeadingNumberingNone 可取消标题编号，并删除其编号定义。
+//
+// 示例:
+//
+//	doc := document.New()
+//	doc.SetHeadingNumbering(document.HeadingNumberingChinese)
+//	doc.AddHeadingParagraph("总则", 1)     // 第一章 总则
+//	doc.AddHeadingParagraph("适用范围", 2) // 一、适用范围
+func (d *Document) SetHeadingNumbering(scheme HeadingNumberingScheme) error {
+	if scheme == HeadingNumberingNone {
+		d.removeHeadingNumbering()
+		d.applyHeadingNumPr("")
+		Infof("已取消标题编号")
+		return nil
+	}
+
+	defs, ok := headingNumberingSchemes[scheme]
+	if !ok {
+		return NewValidationError("scheme", string(scheme), "不支持的标题编号方案")
+	}
+
+	d.ensureNumberingInitialized()
+	manager := d.getNumberingManager()
+
+	styleIDs := d.headingStyleIDs()
+	var levels []*Level
+	for i, def := range defs {
+		level := &Level{
+			ILevel:    strconv.Itoa(i),
+			Start:     &Start{Val: "1"},
+			NumFmt:    &NumFmt{Val: def.numFmt},
+			PStyle:    &LevelPStyle{Val: styleIDs[i]},
+			Suffix:    &LevelSuffix{Val: def.suffix},
+			LevelText: &LevelText{Val: def.levelText},
+			LevelJc:   &LevelJc{Val: "left"},
+		}
+		if scheme == HeadingNumberingLegal && i > 0 {
+			level.IsLgl = &LevelIsLgl{}
+		}
+		levels = append(levels, level)
+	}
+
+	// 已设置过标题编号时替换其级别定义，编号实例保持不变
+	if numID, key, ok := d.headingNumbering(); ok {
+		manager.abstractNums[key].Levels = levels
+		d.updateNumberingFile()
+		d.applyHeadingNumPr(numID)
+		Infof("更新标题编号方案: %s (numId=%s)", scheme, numID)
+		return nil
+	}
+
+	abstractNum := &AbstractNum{
+		AbstractNumID:  strconv.Itoa(manager.nextAbstractNumID),
+		MultiLevelType: &MultiLevelType{Val: "multilevel"},
+		Levels:         levels,
+	}
+	manager.nextAbstractNumID++
+	manager.abstractNums[fmt.Sprintf("heading_%s_%s", scheme, abstractNum.AbstractNumID)] = abstractNum
+
+	numID := strconv.Itoa(manager.nextNumID)
+	manager.nextNumID++
+	manager.numInstances[numID] = &NumInstance{
+		NumID:         numID,
+		AbstractNumID: &AbstractNumReference{Val: abstractNum.AbstractNumID},
+	}
+	d.updateNumberingFile()
+
+	d.applyHeadingNumPr(numID)
+	Infof("设置标题编号方案: %s (numId=%s)", scheme, numID)
+	return nil
+}
+
+// headingNumbering 返回标题样式当前使用的编号实例ID及其抽象编号定义在编号管理器中的键
+// 只识别首级通过 w:pStyle 关联标题1样式的编号定义，即 SetHeadingNumbering 创建的定义
+func (d *Document) headingNumbering() (string, string, bool) {
+	styleID := d.headingStyleIDs()[0]
+	heading := d.styleManager.GetStyle(styleID)
+	if heading == nil || heading.ParagraphPr == nil || heading.ParagraphPr.NumPr == nil || heading.ParagraphPr.NumPr.NumID == nil {
+		return "", "", false
+	}
+	numID := heading.ParagraphPr.NumPr.NumID.Val
+	manager := d.getNumberingManager()
+	instance, ok := manager.numInstances[numID]
+	if !ok || instance.AbstractNumID == nil {
+		return "", "", false
+	}
+	for key, abstractNum := range manager.abstractNums {
+		if abstractNum.AbstractNumID != instance.AbstractNumID.Val {
+			continue
+		}
+		if len(abstractNum.Levels) == 0 || abstractNum.Levels[0].PStyle == nil || abstractNum.Levels[0].PStyle.Val != styleID {
+			return "", "", false
+		}
+		return numID, key, true
+	}
+	return "", "", false
+}
+
+// removeHeadingNumbering 删除标题编号的编号实例和抽象编号定义
+// 正文段落直接引用该编号实例或其他实例共用该定义时保留
+func (d *Document) removeHeadingNumbering() {
+	numID, key, ok := d.headingNumbering()
+	if !ok {
+		return
+	}
+	referenced := false
+	forEachParagraph(d.Body.Elements, func(p *Paragraph) {
+		if p.Properties != nil && p.Properties.NumberingProperties != nil &&
+			p.Properties.NumberingProperties.NumID != nil && p.Properties.NumberingProperties.NumID.Val == numID {
+			referenced = true
+		}
+	})
+	if referenced {
+		return
+	}
+
+	manager := d.getNumberingManager()
+	abstractNumID := manager.abstractNums[key].AbstractNumID
+	delete(manager.numInstances, numID)
+	shared := false
+	for _, instance := range manager.numInstances {
+		if instance.AbstractNumID != nil && instance.AbstractNumID.Val == abstractNumID {
+			shared = true
+		}
+	}
+	if !shared {
+		delete(manager.abstractNums, key)
+	}
+	d.updateNumberingFile()
+}
+
+// headingStyleIDs 获取1-9级标题样式的样式ID
+// 默认为 Heading1 - Heading9，样式表中有按样式名称（heading 1 等）识别的其他样式时使用其ID，
+// 例如中文版Word生成的文档中标题1的样式ID通常为 "2"
+func (d *Document) headingStyleIDs() []string {
+	ids := make([]string, 9)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("Heading%d", i+1)
+	}
+
+	for _, s := range d.styleManager.GetStylesByType(style.StyleTypeParagraph) {
+		if s.Name == nil {
+			continue
+		}
+		if level := headingLevelFromName(s.Name.Val); level > 0 && s.StyleID != fmt.Sprintf("Heading%d", level) {
+			ids[level-1] = s.StyleID
+		}
+	}
+	return ids
+}
+
+// applyHeadingNumPr 将编号属性写入标题样式，numID为空时移除编号属性
+// 文档自带的标题样式与 AddHeadingParagraph 使用的 Heading1 - Heading9 都会被更新
+func (d *Document) applyHeadingNumPr(numID string) {
+	for level, styleID := range d.headingStyleIDs() {
+		candidates := []string{styleID}
+		if defaultID := fmt.Sprintf("Heading%d", level+1); defaultID != styleID {
+			candidates = append(candidates, defaultID)
+		}
+		for _, candidate := range candidates {
+			if headingStyle := d.styleManager.GetStyle(candidate); headingStyle != nil {
+				setHeadingStyleNumPr(headingStyle, level, numID)
+			}
+		}
+	}
+}
+
+// setHeadingStyleNumPr 设置单个标题样式的编号属性，numID为空时移除
+func setHeadingStyleNumPr(headingStyle *style.Style, level int, numID string) {
+	if numID == "" {
+		if headingStyle.ParagraphPr != nil {
+			headingStyle.ParagraphPr.NumPr = nil
+		}
+		return
+	}
+	if headingStyle.ParagraphPr == nil {
+		headingStyle.ParagraphPr = &style.ParagraphProperties{}
+	}
+	headingStyle.ParagraphPr.NumPr = &style.NumPr{
+		ILvl:  &style.NumPrILvl{Val: strconv.Itoa(level)},
+		NumID: &style.NumPrID{Val: numID},
+	}
+}
+
+var headingStyleIDPattern = regexp.MustCompile(`(?i)^heading\s*([1-9])$`)
+
+// headingLevelFromName 从样式名称或ID（如 "heading 1"、"Heading1"）中解析标题级别
+func headingLevelFromName(name string) int {
+	if m := headingStyleIDPattern.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
+		level, _ := strconv.Atoi(m[1])
+		return level
+	}
+	return 0
+}
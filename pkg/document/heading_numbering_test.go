@@ -0,0 +1,233 @@
+// Package document 标题编号功能测试
+package document
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestSetHeadingNumberingLegal 测试多级数字标题编号
+func TestSetHeadingNumberingLegal(t *testing.T) {
+	doc := New()
+	if err := doc.SetHeadingNumbering(HeadingNumberingLegal); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+
+	heading2 := doc.styleManager.GetStyle("Heading2")
+	if heading2.ParagraphPr.NumPr == nil || heading2.ParagraphPr.NumPr.ILvl.Val != "1" {
+		t.Fatal("标题2样式应包含级别为1的编号属性")
+	}
+
+	doc.AddHeadingParagraph("概述", 1)
+	doc.AddHeadingParagraph("背景", 2)
+	doc.AddHeadingParagraph("目标", 2)
+	doc.AddHeadingParagraph("细节", 3)
+	doc.AddHeadingParagraph("设计", 1)
+	doc.AddHeadingParagraph("架构", 2)
+
+	var got []string
+	for _, entry := range doc.ListHeadings() {
+		got = append(got, entry.displayText())
+	}
+	expected := "1 概述|1.1 背景|1.2 目标|1.2.1 细节|2 设计|2.1 架构"
+	if strings.Join(got, "|") != expected {
+		t.Errorf("标题编号不正确，期望: %s，实际: %s", expected, strings.Join(got, "|"))
+	}
+
+	numberingXML := string(doc.parts["word/numbering.xml"])
+	if !strings.Contains(numberingXML, `<w:pStyle w:val="Heading3">`) {
+		t.Error("编号定义应通过w:pStyle关联标题样式")
+	}
+}
+
+// TestSetHeadingNumberingChinese 测试中文标题编号及目录
+func TestSetHeadingNumberingChinese(t *testing.T) {
+	doc := New()
+	if err := doc.SetHeadingNumbering(HeadingNumberingChinese); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+
+	doc.AddHeadingParagraph("总则", 1)
+	doc.AddHeadingParagraph("适用范围", 2)
+	doc.AddHeadingParagraph("一般规定", 3)
+	doc.AddHeadingParagraph("附则", 1)
+
+	entries := doc.ListHeadings()
+	expected := []string{"第一章 总则", "一、适用范围", "（一）一般规定", "第二章 附则"}
+	for i, entry := range entries {
+		if entry.displayText() != expected[i] {
+			t.Errorf("第%d个标题应为 %s，实际为: %s", i+1, expected[i], entry.displayText())
+		}
+	}
+
+	if err := doc.AutoGenerateTOC(DefaultTOCConfig()); err != nil {
+		t.Fatalf("生成目录失败: %v", err)
+	}
+	sdt, ok := doc.Body.Elements[0].(*SDT)
+	if !ok {
+		t.Fatal("目录应位于文档开头")
+	}
+	found := false
+	for _, element := range sdt.Content.Elements {
+		if para, ok := element.(*Paragraph); ok {
+			for _, run := range para.Runs {
+				if run.Text.Content == "第一章 总则" {
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("目录条目应包含标题编号")
+	}
+}
+
+// TestSetHeadingNumberingNone 测试取消标题编号
+func TestSetHeadingNumberingNone(t *testing.T) {
+	doc := New()
+	if err := doc.SetHeadingNumbering(HeadingNumberingAppendix); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+	doc.AddHeadingParagraph("术语", 1)
+	doc.AddHeadingParagraph("缩略语", 2)
+	if got := doc.ListHeadings()[1].displayText(); got != "A.1 缩略语" {
+		t.Errorf("附录编号不正确: %s", got)
+	}
+
+	if err := doc.SetHeadingNumbering(HeadingNumberingNone); err != nil {
+		t.Fatalf("取消标题编号失败: %v", err)
+	}
+	if doc.ListHeadings()[0].Number != "" {
+		t.Error("取消后标题不应再有编号")
+	}
+
+	if err := doc.SetHeadingNumbering("unknown"); err == nil {
+		t.Error("不支持的编号方案应返回错误")
+	}
+}
+
+// TestHeadingNumberingExistingStyles 测试打开的文档按样式名称识别标题样式并写入编号属性
+func TestHeadingNumberingExistingStyles(t *testing.T) {
+	data := []byte(`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="a"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="2"><w:name w:val="heading 1"/><w:basedOn w:val="a"/><w:pPr><w:keepNext/><w:spacing w:before="240"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="3"><w:name w:val="heading 2"/><w:rPr><w:b/></w:rPr></w:style>` +
+		`</w:styles>`)
+
+	doc := New()
+	if err := doc.styleManager.LoadStylesFromDocument(data); err != nil {
+		t.Fatalf("加载样式失败: %v", err)
+	}
+	doc.parts["word/styles.xml"] = data
+	if ids := doc.headingStyleIDs(); ids[0] != "2" || ids[1] != "3" || ids[2] != "Heading3" {
+		t.Errorf("标题样式ID识别不正确: %v", ids)
+	}
+
+	if err := doc.SetHeadingNumbering(HeadingNumberingLegal); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+	if err := doc.serializeStyles(); err != nil {
+		t.Fatalf("序列化样式失败: %v", err)
+	}
+	stylesXML := string(doc.parts["word/styles.xml"])
+	if !strings.Contains(stylesXML, `<w:keepNext></w:keepNext><w:numPr><w:ilvl w:val="0"></w:ilvl>`) {
+		t.Errorf("标题1样式的编号属性位置不正确: %s", stylesXML)
+	}
+	if strings.Count(stylesXML, "<w:numPr>") != 2 || strings.Contains(stylesXML, `w:styleId="Heading1"`) {
+		t.Errorf("只应更新文档自带的标题样式: %s", stylesXML)
+	}
+
+	if err := doc.SetHeadingNumbering(HeadingNumberingNone); err != nil {
+		t.Fatalf("取消标题编号失败: %v", err)
+	}
+	if err := doc.serializeStyles(); err != nil {
+		t.Fatalf("序列化样式失败: %v", err)
+	}
+	if strings.Contains(string(doc.parts["word/styles.xml"]), "numPr") {
+		t.Error("移除编号后不应包含编号属性")
+	}
+}
+
+// TestSetHeadingNumberingReplace 测试切换标题编号方案时复用编号定义，取消编号时删除编号定义
+func TestSetHeadingNumberingReplace(t *testing.T) {
+	doc := New()
+	doc.AddHeadingParagraph("总则", 1)
+	for _, scheme := range []HeadingNumberingScheme{HeadingNumberingLegal, HeadingNumberingChinese, HeadingNumberingAppendix, HeadingNumberingChinese} {
+		if err := doc.SetHeadingNumbering(scheme); err != nil {
+			t.Fatalf("设置标题编号 %s 失败: %v", scheme, err)
+		}
+	}
+
+	numberingXML := string(doc.parts["word/numbering.xml"])
+	if count := strings.Count(numberingXML, "<w:abstractNum "); count != 1 {
+		t.Errorf("多次切换方案后应只有一个抽象编号定义，实际 %d 个", count)
+	}
+	if count := strings.Count(numberingXML, "<w:num "); count != 1 {
+		t.Errorf("多次切换方案后应只有一个编号实例，实际 %d 个", count)
+	}
+	if entries := doc.ListHeadings(); len(entries) != 1 || entries[0].displayText() != "第一章 总则" {
+		t.Errorf("标题编号应使用最后设置的方案: %+v", entries)
+	}
+
+	// 保存后重新打开，仍然复用已有定义
+	data, err := doc.ToBytes()
+	if err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	reopened, err := OpenFromMemory(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if err := reopened.SetHeadingNumbering(HeadingNumberingLegal); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+	if count := len(reopened.GetListDefinitions()); count != 1 {
+		t.Errorf("重新打开后切换方案应复用编号定义，实际 %d 个", count)
+	}
+
+	if err := reopened.SetHeadingNumbering(HeadingNumberingNone); err != nil {
+		t.Fatalf("取消标题编号失败: %v", err)
+	}
+	numberingXML = string(reopened.parts["word/numbering.xml"])
+	if strings.Contains(numberingXML, "<w:abstractNum ") || strings.Contains(numberingXML, "<w:num ") {
+		t.Errorf("取消标题编号后应删除编号定义: %s", numberingXML)
+	}
+}
+
+// TestFormatNumber 测试编号格式渲染
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		value    int
+		numFmt   string
+		expected string
+	}{
+		{3, "decimal", "3"},
+		{28, "upperLetter", "BB"},
+		{4, "lowerRoman", "iv"},
+		{10, "chineseCounting", "十"},
+		{21, "chineseCounting", "二十一"},
+		{3, "decimalEnclosedCircle", "③"},
+		{101, "chineseCounting", "一〇一"},
+		{110, "chineseCountingThousand", "一百一十"},
+		{1005, "chineseCountingThousand", "一千零五"},
+		{10001, "chineseCountingThousand", "一万零一"},
+		{10, "chineseLegalSimplified", "壹拾"},
+		{203, "chineseLegalSimplified", "贰佰零叁"},
+		{11, "ideographTraditional", "甲"},
+		{36, "decimalEnclosedCircle", "㊱"},
+		{12, "decimalFullWidth", "１２"},
+		{111, "japaneseCounting", "百十一"},
+		{23, "koreanDigital", "이십삼"},
+		{12, "ordinal", "12th"},
+		{22, "ordinal", "22nd"},
+		{21, "cardinalText", "Twenty-One"},
+		{105, "cardinalText", "One Hundred Five"},
+	}
+	for _, c := range cases {
+		if got := formatNumber(c.value, c.numFmt); got != c.expected {
+			t.Errorf("formatNumber(%d, %s) 应为 %s，实际为: %s", c.value, c.numFmt, c.expected, got)
+		}
+	}
+}
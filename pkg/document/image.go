@@ -594,7 +594,7 @@ func (d *Document) createImageParagraph(imageInfo *ImageInfo) *Paragraph {
 		}
 	}
 
-	return paragraph
+	return d.bindParagraph(paragraph)
 }
 
 // createInlineImageDrawing 创建嵌入式图片绘图元素
@@ -0,0 +1,600 @@
+// Package document 提供Word文档索引（XE/INDEX域）生成功能
+package document
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// IndexSortOrder 索引排序方式
+type IndexSortOrder string
+
+const (
+	// IndexSortPinyin 按拼音排序（中文词条按汉语拼音，西文按字母）
+	IndexSortPinyin IndexSortOrder = "pinyin"
+	// IndexSortStroke 按笔画排序（中文词条按笔画数）
+	IndexSortStroke IndexSortOrder = "stroke"
+)
+
+// IndexEntryOptions 索引项（XE域）选项
+type IndexEntryOptions struct {
+	CrossReference string // 交叉引用文本（\t 开关），如 "参见 排版"，设置后不显示页码
+	Bold           bool   // 页码加粗（\b 开关），通常用于标记主要出处
+	Italic         bool   // 页码倾斜（\i 开关）
+}
+
+// IndexConfig 索引生成配置
+type IndexConfig struct {
+	Title                 string         // 索引标题，默认为"索引"，为空时不添加标题
+	Columns               int            // 栏数，默认为2
+	ColumnSpacing         float64        // 栏间距（毫米），默认为12.7
+	SortOrder             IndexSortOrder // 排序方式，默认为拼音排序
+	GroupHeadings         bool           // 是否按首字母（拼音首字母）插入分组标题，笔画排序时不插入
+	RightAlignPageNumbers bool           // 页码是否右对齐（使用点状引导线）
+	LanguageID            int            // 排序语言ID（INDEX域 \z 开关），默认为2052（简体中文）
+}
+
+// IndexPageRef 索引项的页码引用
+type IndexPageRef struct {
+	Page   int  // 页码
+	Bold   bool // 页码加粗
+	Italic bool // 页码倾斜
+}
+
+// IndexEntry 从文档中收集到的索引项
+type IndexEntry struct {
+	Levels         []string       // 词条层级，如 ["排版", "分栏"] 对应 XE "排版:分栏"
+	Pages          []IndexPageRef // 页码引用（按页码排序、去重）
+	CrossReference string         // 交叉引用文本
+}
+
+// Term 返回以冒号连接的完整词条文本
+func (e *IndexEntry) Term() string {
+	return strings.Join(e.Levels, ":")
+}
+
+// DefaultIndexConfig 返回默认索引配置
+func DefaultIndexConfig() *IndexConfig {
+	return &IndexConfig{
+		Title:                 "索引",
+		Columns:               2,
+		ColumnSpacing:         12.7,
+		SortOrder:             IndexSortPinyin,
+		GroupHeadings:         true,
+		RightAlignPageNumbers: false,
+		LanguageID:            2052,
+	}
+}
+
+// NewIndexEntryRuns 创建标记索引项的XE域Run集合
+//
+// 参数 term 是索引词条，使用冒号分隔子词条，如 "排版:分栏"。
+// 返回的Run可以插入到任意段落中，XE域本身不产生可见文本。
+//
+// 示例:
+//
+//	para := doc.AddParagraph("分栏排版适用于报刊。")
+//	para.Runs = append(para.Runs, document.NewIndexEntryRuns("排版:分栏", nil)...)
+func NewIndexEntryRuns(term string, opts *IndexEntryOptions) []Run {
+	instr := " XE " + quoteFieldArgument(term)
+	if opts != nil {
+		if opts.CrossReference != "" {
+			instr += " \\t " + quoteFieldArgument(opts.CrossReference)
+		}
+		if opts.Bold {
+			instr += " \\b"
+		}
+		if opts.Italic {
+			instr += " \\i"
+		}
+	}
+	instr += " "
+
+	return []Run{
+		{
+			FieldChar: &FieldChar{FieldCharType: "begin"},
+		},
+		{
+			InstrText: &InstrText{
+				Space:   "preserve",
+				Content: instr,
+			},
+		},
+		{
+			FieldChar: &FieldChar{FieldCharType: "end"},
+		},
+	}
+}
+
+// AddIndexEntry 在段落末尾标记一个索引项
+//
+// 示例:
+//
+//	para := doc.AddParagraph("宋体是最常用的中文字体。")
+//	para.AddIndexEntry("字体:宋体", &document.IndexEntryOptions{Bold: true})
+//	para.AddIndexEntry("明体", &document.IndexEntryOptions{CrossReference: "参见 宋体"})
+func (p *Paragraph) AddIndexEntry(term string, opts *IndexEntryOptions) {
+	p.Runs = append(p.Runs, NewIndexEntryRuns(term, opts)...)
+	Debugf("添加索引项: %s", term)
+}
+
+// InsertIndexEntry 在指定Run之后标记一个索引项
+//
+// 参数 runIndex 是段落中Run的索引，索引项将紧跟在该Run之后，
+// 使索引项与被标记的文本位置保持一致。
+func (p *Paragraph) InsertIndexEntry(runIndex int, term string, opts *IndexEntryOptions) error {
+	if runIndex < 0 || runIndex >= len(p.Runs) {
+		return NewValidationError("runIndex", strconv.Itoa(runIndex), "Run索引超出范围")
+	}
+
+	entryRuns := NewIndexEntryRuns(term, opts)
+	runs := make([]Run, 0, len(p.Runs)+len(entryRuns))
+	runs = append(runs, p.Runs[:runIndex+1]...)
+	runs = append(runs, entryRuns...)
+	runs = append(runs, p.Runs[runIndex+1:]...)
+	p.Runs = runs
+
+	Debugf("在Run %d 之后插入索引项: %s", runIndex, term)
+	return nil
+}
+
+// GetIndexEntries 收集文档中所有XE域标记的索引项
+//
+// 相同词条的多个出处会合并为一个索引项，页码按照文档中的显式分页符、
+// 段前分页和分节符估算（Word打开文档并更新域后会重新计算准确页码）。
+func (d *Document) GetIndexEntries() []*IndexEntry {
+	entries := make(map[string]*IndexEntry)
+	var order []string
+
+	page := 1
+	forEachParagraph(d.Body.Elements, func(paragraph *Paragraph) {
+		if paragraph.Properties != nil && paragraph.Properties.PageBreakBefore != nil &&
+			paragraph.Properties.PageBreakBefore.Val != "0" && paragraph.Properties.PageBreakBefore.Val != "false" {
+			page++
+		}
+
+		runIndex := 0
+		for _, field := range collectComplexFields(paragraph) {
+			// 统计域之前的分页符
+			for ; runIndex < field.BeginIndex; runIndex++ {
+				page += countPageBreaks(&paragraph.Runs[runIndex])
+			}
+
+			instr := parseFieldInstruction(field.Instruction)
+			if instr.Name != "XE" || len(instr.Args) == 0 {
+				continue
+			}
+
+			levels := splitIndexTerm(instr.Args[0])
+			if len(levels) == 0 {
+				continue
+			}
+
+			key := strings.Join(levels, "\x00")
+			entry, ok := entries[key]
+			if !ok {
+				entry = &IndexEntry{Levels: levels}
+				entries[key] = entry
+				order = append(order, key)
+			}
+
+			if crossRef, ok := instr.Switches["\\t"]; ok {
+				entry.CrossReference = crossRef
+				continue
+			}
+			_, bold := instr.Switches["\\b"]
+			_, italic := instr.Switches["\\i"]
+			entry.addPage(IndexPageRef{Page: page, Bold: bold, Italic: italic})
+		}
+		for ; runIndex < len(paragraph.Runs); runIndex++ {
+			page += countPageBreaks(&paragraph.Runs[runIndex])
+		}
+
+		// 分节符（除连续分节外）同样开始新页
+		if paragraph.Properties != nil && paragraph.Properties.SectionProperties != nil {
+			sectType := paragraph.Properties.SectionProperties.Type
+			if sectType == nil || sectType.Val != string(SectionBreakContinuous) {
+				page++
+			}
+		}
+	})
+
+	result := make([]*IndexEntry, 0, len(order))
+	for _, key := range order {
+		result = append(result, entries[key])
+	}
+	return result
+}
+
+// GenerateIndex 在文档末尾生成索引
+//
+// 该方法收集所有XE域标记的索引项，按拼音或笔画排序后，插入INDEX域及预先渲染的
+// 缓存结果。索引内容位于一个新的连续分节中，并按配置的栏数分栏排版。
+//
+// 示例:
+//
+//	config := document.DefaultIndexConfig()
+//	config.SortOrder = document.IndexSortStroke
+//	if err := doc.GenerateIndex(config); err != nil {
+//		log.Fatal(err)
+//	}
+func (d *Document) GenerateIndex(config *IndexConfig) error {
+	if config == nil {
+		config = DefaultIndexConfig()
+	} else {
+		// 复制配置后再填充默认值，不修改调用方的配置
+		copied := *config
+		config = &copied
+	}
+	if config.Columns <= 0 {
+		config.Columns = 2
+	}
+	if config.ColumnSpacing <= 0 {
+		config.ColumnSpacing = 12.7
+	}
+	if config.SortOrder == "" {
+		config.SortOrder = IndexSortPinyin
+	}
+	if config.LanguageID <= 0 {
+		config.LanguageID = 2052
+	}
+
+	entries := d.GetIndexEntries()
+	if len(entries) == 0 {
+		return WrapError("generate_index", errors.New("文档中未找到索引项（XE域）"))
+	}
+
+	sorter := newIndexSorter(config.SortOrder)
+	sorter.sortEntries(entries)
+
+	// 计算单栏宽度，用于页码右对齐的制表位
+	settings := d.GetPageSettings()
+	pageWidth, _ := getPageDimensions(settings)
+	textWidth := pageWidth - settings.MarginLeft - settings.MarginRight - settings.GutterWidth
+	columnWidth := (textWidth - config.ColumnSpacing*float64(config.Columns-1)) / float64(config.Columns)
+
+	// 结束当前节：将主体节属性复制到分节段落上，主体节属性改为连续分栏节
+	bodySectPr := d.getSectionProperties()
+	breakPara := &Paragraph{
+		Properties: &ParagraphProperties{
+			SectionProperties: cloneSectionProperties(bodySectPr),
+		},
+	}
+	if config.Title != "" {
+		breakPara.Properties.Justification = &Justification{Val: string(AlignCenter)}
+		breakPara.Properties.Spacing = &Spacing{Before: "240", After: "240"}
+		breakPara.Runs = []Run{
+			{
+				Properties: &RunProperties{Bold: &Bold{}, FontSize: &FontSize{Val: "32"}},
+				Text:       Text{Content: config.Title, Space: "preserve"},
+			},
+		}
+	}
+
+	indexParas := d.renderIndexParagraphs(entries, config, sorter, mmToTwips(columnWidth))
+
+	// 在第一个段落插入INDEX域开始，在最后一个段落插入域结束
+	fieldStart := []Run{
+		{FieldChar: &FieldChar{FieldCharType: "begin"}},
+		{InstrText: &InstrText{Space: "preserve", Content: buildIndexInstruction(config)}},
+		{FieldChar: &FieldChar{FieldCharType: "separate"}},
+	}
+	first := indexParas[0]
+	first.Runs = append(fieldStart, first.Runs...)
+	last := indexParas[len(indexParas)-1]
+	last.Runs = append(last.Runs, Run{FieldChar: &FieldChar{FieldCharType: "end"}})
+
+	elements := make([]interface{}, 0, len(indexParas)+1)
+	elements = append(elements, breakPara)
+	for _, para := range indexParas {
+		elements = append(elements, para)
+	}
+	d.insertBeforeSectionProperties(elements)
+
+	bodySectPr.Type = &SectionType{Val: string(SectionBreakContinuous)}
+	bodySectPr.Columns = &Columns{
+		Num:   strconv.Itoa(config.Columns),
+		Space: fmt.Sprintf("%.0f", mmToTwips(config.ColumnSpacing)),
+	}
+
+	Infof("生成索引完成，共 %d 个索引项", len(entries))
+	return nil
+}
+
+// groupHeadings 判断是否插入分组标题
+// 笔画排序下每个不同的首字会各自成组，分组标题没有意义，因此不插入
+func (c *IndexConfig) groupHeadings() bool {
+	return c.GroupHeadings && c.SortOrder != IndexSortStroke
+}
+
+// buildIndexInstruction 构建INDEX域指令
+func buildIndexInstruction(config *IndexConfig) string {
+	instr := fmt.Sprintf(" INDEX \\c \"%d\" \\z \"%d\"", config.Columns, config.LanguageID)
+	if config.groupHeadings() {
+		instr += " \\h \"A\""
+	}
+	if config.RightAlignPageNumbers {
+		instr += " \\e \"\t\""
+	}
+	return instr + " "
+}
+
+// renderIndexParagraphs 渲染索引的缓存结果段落
+func (d *Document) renderIndexParagraphs(entries []*IndexEntry, config *IndexConfig, sorter *indexSorter, columnWidthTwips float64) []*Paragraph {
+	var paragraphs []*Paragraph
+	var previous []string
+	currentGroup := ""
+
+	for _, entry := range entries {
+		if config.groupHeadings() {
+			group := sorter.groupOf(entry.Levels[0])
+			if group != currentGroup {
+				currentGroup = group
+				paragraphs = append(paragraphs, &Paragraph{
+					Properties: &ParagraphProperties{
+						KeepNext: &KeepNext{},
+						Spacing:  &Spacing{Before: "240", After: "120"},
+					},
+					Runs: []Run{
+						{
+							Properties: &RunProperties{Bold: &Bold{}},
+							Text:       Text{Content: group, Space: "preserve"},
+						},
+					},
+				})
+				previous = nil
+			}
+		}
+
+		// 为尚未输出的上级词条补充仅含文本的段落
+		shared := 0
+		for shared < len(previous) && shared < len(entry.Levels)-1 && previous[shared] == entry.Levels[shared] {
+			shared++
+		}
+		for level := shared; level < len(entry.Levels)-1; level++ {
+			paragraphs = append(paragraphs, newIndexEntryParagraph(level, columnWidthTwips, config))
+			para := paragraphs[len(paragraphs)-1]
+			para.Runs = append(para.Runs, Run{Text: Text{Content: entry.Levels[level], Space: "preserve"}})
+		}
+
+		level := len(entry.Levels) - 1
+		para := newIndexEntryParagraph(level, columnWidthTwips, config)
+		para.Runs = append(para.Runs, Run{Text: Text{Content: entry.Levels[level], Space: "preserve"}})
+
+		if entry.CrossReference != "" {
+			separator := "，"
+			if len(entry.Pages) == 0 {
+				separator = "。"
+			}
+			if len(entry.Pages) > 0 {
+				para.Runs = append(para.Runs, indexPageRuns(entry.Pages, config)...)
+			}
+			para.Runs = append(para.Runs, Run{Text: Text{Content: separator, Space: "preserve"}})
+			para.Runs = append(para.Runs, Run{
+				Properties: &RunProperties{Italic: &Italic{}},
+				Text:       Text{Content: entry.CrossReference, Space: "preserve"},
+			})
+		} else {
+			para.Runs = append(para.Runs, indexPageRuns(entry.Pages, config)...)
+		}
+
+		paragraphs = append(paragraphs, para)
+		previous = entry.Levels
+	}
+
+	return paragraphs
+}
+
+// newIndexEntryParagraph 创建指定层级的索引条目段落
+func newIndexEntryParagraph(level int, columnWidthTwips float64, config *IndexConfig) *Paragraph {
+	// 每级缩进240 TWIPs
+	props := &ParagraphProperties{
+		Indentation: &Indentation{
+			Left: strconv.Itoa(level * 240),
+		},
+		Spacing: &Spacing{After: "0"},
+	}
+	if config.RightAlignPageNumbers {
+		props.Tabs = &Tabs{
+			Tabs: []TabDef{
+				{Val: "right", Leader: "dot", Pos: fmt.Sprintf("%.0f", columnWidthTwips)},
+			},
+		}
+	}
+	return &Paragraph{Properties: props}
+}
+
+// indexPageRuns 生成页码部分的Run
+func indexPageRuns(pages []IndexPageRef, config *IndexConfig) []Run {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	var runs []Run
+	if config.RightAlignPageNumbers {
+		runs = append(runs, Run{Text: Text{Content: "\t", Space: "preserve"}})
+	} else {
+		runs = append(runs, Run{Text: Text{Content: "，", Space: "preserve"}})
+	}
+
+	for i, ref := range pages {
+		if i > 0 {
+			runs = append(runs, Run{Text: Text{Content: ", ", Space: "preserve"}})
+		}
+		run := Run{Text: Text{Content: strconv.Itoa(ref.Page), Space: "preserve"}}
+		if ref.Bold || ref.Italic {
+			run.Properties = &RunProperties{}
+			if ref.Bold {
+				run.Properties.Bold = &Bold{}
+			}
+			if ref.Italic {
+				run.Properties.Italic = &Italic{}
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// addPage 添加页码引用，同一页码只保留一次（加粗/倾斜标记取并集）
+func (e *IndexEntry) addPage(ref IndexPageRef) {
+	for i := range e.Pages {
+		if e.Pages[i].Page == ref.Page {
+			e.Pages[i].Bold = e.Pages[i].Bold || ref.Bold
+			e.Pages[i].Italic = e.Pages[i].Italic || ref.Italic
+			return
+		}
+	}
+	e.Pages = append(e.Pages, ref)
+	sort.Slice(e.Pages, func(i, j int) bool { return e.Pages[i].Page < e.Pages[j].Page })
+}
+
+// splitIndexTerm 按冒号拆分索引词条（\: 表示字面冒号）
+func splitIndexTerm(term string) []string {
+	const escapedColon = "\x00"
+	term = strings.ReplaceAll(term, "\\:", escapedColon)
+
+	var levels []string
+	for _, part := range strings.Split(term, ":") {
+		part = strings.TrimSpace(strings.ReplaceAll(part, escapedColon, ":"))
+		if part != "" {
+			levels = append(levels, part)
+		}
+	}
+	return levels
+}
+
+// countPageBreaks 统计Run中的分页符数量
+func countPageBreaks(run *Run) int {
+	if run.Break != nil && run.Break.Type == "page" {
+		return 1
+	}
+	return 0
+}
+
+// insertBeforeSectionProperties 将元素插入到主体节属性之前（即文档末尾）
+func (d *Document) insertBeforeSectionProperties(elements []interface{}) {
+	for i, element := range d.Body.Elements {
+		if _, ok := element.(*SectionProperties); ok {
+			newElements := make([]interface{}, 0, len(d.Body.Elements)+len(elements))
+			newElements = append(newElements, d.Body.Elements[:i]...)
+			newElements = append(newElements, elements...)
+			newElements = append(newElements, d.Body.Elements[i:]...)
+			d.Body.Elements = newElements
+			return
+		}
+	}
+	d.Body.Elements = append(d.Body.Elements, elements...)
+}
+
+// forEachParagraph 按文档顺序遍历元素中的所有段落（包括表格单元格和SDT中的段落）
+func forEachParagraph(elements []interface{}, fn func(*Paragraph)) {
+	for _, element := range elements {
+		switch elem := element.(type) {
+		case *Paragraph:
+			fn(elem)
+		case *Table:
+			forEachTableParagraph(elem, fn)
+		case *SDT:
+			if elem.Content != nil {
+				forEachParagraph(elem.Content.Elements, fn)
+			}
+		}
+	}
+}
+
+// forEachTableParagraph 遍历表格（含嵌套表格）中的所有段落
+func forEachTableParagraph(table *Table, fn func(*Paragraph)) {
+	for i := range table.Rows {
+		for j := range table.Rows[i].Cells {
+			cell := &table.Rows[i].Cells[j]
+			for k := range cell.Paragraphs {
+				fn(&cell.Paragraphs[k])
+			}
+			for k := range cell.Tables {
+				forEachTableParagraph(&cell.Tables[k], fn)
+			}
+		}
+	}
+}
+
+// pinyinGroupBoundaries 拼音首字母分组的边界汉字（按拼音排序规则，每个字母的首个常用字）
+var pinyinGroupBoundaries = []struct {
+	letter string
+	char   string
+}{
+	{"A", "阿"}, {"B", "八"}, {"C", "嚓"}, {"D", "咑"}, {"E", "妸"}, {"F", "发"},
+	{"G", "旮"}, {"H", "哈"}, {"J", "丌"}, {"K", "咔"}, {"L", "垃"}, {"M", "妈"},
+	{"N", "嗯"}, {"O", "噢"}, {"P", "妑"}, {"Q", "七"}, {"R", "呥"}, {"S", "仨"},
+	{"T", "他"}, {"W", "屲"}, {"X", "夕"}, {"Y", "丫"}, {"Z", "帀"},
+}
+
+// indexSorter 索引排序器
+type indexSorter struct {
+	order    IndexSortOrder
+	collator *collate.Collator
+	pinyin   *collate.Collator
+}
+
+// newIndexSorter 创建索引排序器
+func newIndexSorter(order IndexSortOrder) *indexSorter {
+	tag := language.MustParse("zh")
+	if order == IndexSortStroke {
+		tag = language.MustParse("zh-u-co-stroke")
+	}
+	return &indexSorter{
+		order:    order,
+		collator: collate.New(tag, collate.IgnoreCase),
+		pinyin:   collate.New(language.MustParse("zh"), collate.IgnoreCase),
+	}
+}
+
+// compare 比较两个词条
+func (s *indexSorter) compare(a, b string) int {
+	return s.collator.CompareString(a, b)
+}
+
+// sortEntries 按词条层级依次排序
+func (s *indexSorter) sortEntries(entries []*IndexEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Levels, entries[j].Levels
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if c := s.compare(a[k], b[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
+// groupOf 返回词条所属的分组标题
+// 西文词条取首字母，中文词条取拼音首字母，其余归入"#"
+func (s *indexSorter) groupOf(term string) string {
+	runes := []rune(term)
+	if len(runes) == 0 {
+		return "#"
+	}
+
+	first := runes[0]
+	switch {
+	case first < unicode.MaxASCII && unicode.IsLetter(first):
+		return strings.ToUpper(string(first))
+	case unicode.Is(unicode.Han, first):
+		group := "#"
+		for _, boundary := range pinyinGroupBoundaries {
+			if s.pinyin.CompareString(string(first), boundary.char) >= 0 {
+				group = boundary.letter
+			}
+		}
+		return group
+	default:
+		return "#"
+	}
+}
@@ -0,0 +1,216 @@
+// Package document 索引功能测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewIndexEntryRuns 测试XE域Run的生成
+func TestNewIndexEntryRuns(t *testing.T) {
+	runs := NewIndexEntryRuns("排版:分栏", &IndexEntryOptions{Bold: true, CrossReference: "参见 \"版式\""})
+	if len(runs) != 3 {
+		t.Fatalf("XE域应包含3个Run，实际为: %d", len(runs))
+	}
+
+	instr := runs[1].InstrText.Content
+	expected := ` XE "排版:分栏" \t "参见 \"版式\"" \b `
+	if instr != expected {
+		t.Errorf("XE域指令不正确，期望: %q，实际: %q", expected, instr)
+	}
+
+	parsed := parseFieldInstruction(instr)
+	if parsed.Name != "XE" || parsed.Args[0] != "排版:分栏" {
+		t.Errorf("XE域指令解析结果不正确: %+v", parsed)
+	}
+	if parsed.Switches["\\t"] != `参见 "版式"` {
+		t.Errorf("交叉引用解析不正确: %q", parsed.Switches["\\t"])
+	}
+}
+
+// TestGetIndexEntries 测试索引项收集与页码估算
+func TestGetIndexEntries(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("第一页内容").AddIndexEntry("字体:宋体", nil)
+	doc.AddParagraph("同页再次引用").AddIndexEntry("字体:宋体", &IndexEntryOptions{Bold: true})
+	doc.AddPageBreak()
+	doc.AddParagraph("第二页内容").AddIndexEntry("字体:宋体", nil)
+	doc.AddParagraph("交叉引用").AddIndexEntry("明体", &IndexEntryOptions{CrossReference: "参见 宋体"})
+
+	entries := doc.GetIndexEntries()
+	if len(entries) != 2 {
+		t.Fatalf("应收集到2个索引项，实际为: %d", len(entries))
+	}
+
+	songti := entries[0]
+	if songti.Term() != "字体:宋体" {
+		t.Errorf("索引项词条不正确: %s", songti.Term())
+	}
+	if len(songti.Pages) != 2 || songti.Pages[0].Page != 1 || songti.Pages[1].Page != 2 {
+		t.Fatalf("索引项页码不正确: %+v", songti.Pages)
+	}
+	if !songti.Pages[0].Bold {
+		t.Error("第1页的引用应为加粗页码")
+	}
+
+	if entries[1].CrossReference != "参见 宋体" || len(entries[1].Pages) != 0 {
+		t.Errorf("交叉引用索引项不正确: %+v", entries[1])
+	}
+}
+
+// TestIndexSorter 测试拼音和笔画排序
+func TestIndexSorter(t *testing.T) {
+	newEntries := func(terms ...string) []*IndexEntry {
+		var entries []*IndexEntry
+		for _, term := range terms {
+			entries = append(entries, &IndexEntry{Levels: splitIndexTerm(term)})
+		}
+		return entries
+	}
+	terms := func(entries []*IndexEntry) string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Term())
+		}
+		return strings.Join(result, ",")
+	}
+
+	pinyin := newIndexSorter(IndexSortPinyin)
+	entries := newEntries("上海", "北京", "广州", "北京:朝阳")
+	pinyin.sortEntries(entries)
+	if got := terms(entries); got != "北京,北京:朝阳,广州,上海" {
+		t.Errorf("拼音排序结果不正确: %s", got)
+	}
+
+	groups := map[string]string{"北京": "B", "广州": "G", "上海": "S", "word": "W", "123": "#"}
+	for term, expected := range groups {
+		if got := pinyin.groupOf(term); got != expected {
+			t.Errorf("词条 %s 的分组应为 %s，实际为: %s", term, expected, got)
+		}
+	}
+
+	stroke := newIndexSorter(IndexSortStroke)
+	entries = newEntries("龙", "一", "王")
+	stroke.sortEntries(entries)
+	if got := terms(entries); got != "一,王,龙" {
+		t.Errorf("笔画排序结果不正确: %s", got)
+	}
+}
+
+// TestGenerateIndex 测试生成索引及保存后重新解析
+func TestGenerateIndex(t *testing.T) {
+	doc := New()
+	if err := doc.GenerateIndex(nil); err == nil {
+		t.Error("没有索引项时生成索引应返回错误")
+	}
+
+	doc.AddParagraph("上海是一座城市。").AddIndexEntry("上海", nil)
+	doc.AddParagraph("北京是首都。").AddIndexEntry("北京", nil)
+
+	config := DefaultIndexConfig()
+	config.Columns = 3
+	if err := doc.GenerateIndex(config); err != nil {
+		t.Fatalf("生成索引失败: %v", err)
+	}
+
+	sectPr := doc.getSectionProperties()
+	if sectPr.Type == nil || sectPr.Type.Val != string(SectionBreakContinuous) {
+		t.Error("索引所在的节应为连续分节")
+	}
+	if sectPr.Columns == nil || sectPr.Columns.Num != "3" {
+		t.Error("索引所在的节应设置为3栏")
+	}
+
+	// 默认值填充在配置副本上，不修改调用方的配置
+	partial := &IndexConfig{Title: "索引"}
+	other := New()
+	other.AddParagraph("杭州").AddIndexEntry("杭州", nil)
+	if err := other.GenerateIndex(partial); err != nil {
+		t.Fatalf("生成索引失败: %v", err)
+	}
+	if partial.Columns != 0 || partial.SortOrder != "" || partial.LanguageID != 0 {
+		t.Errorf("生成索引不应修改调用方的配置: %+v", partial)
+	}
+
+	var texts []string
+	foundIndexField := false
+	for _, para := range doc.Body.GetParagraphs() {
+		for _, run := range para.Runs {
+			if run.InstrText != nil && strings.Contains(run.InstrText.Content, "INDEX") {
+				foundIndexField = true
+			}
+			if run.Text.Content != "" {
+				texts = append(texts, run.Text.Content)
+			}
+		}
+	}
+	if !foundIndexField {
+		t.Error("应插入INDEX域")
+	}
+	all := strings.Join(texts, "|")
+	if !strings.Contains(all, "索引|B|北京|，|1|S|上海|，|1") {
+		t.Errorf("索引缓存结果不正确: %s", all)
+	}
+
+	filename := filepath.Join(t.TempDir(), "index.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if entries := reopened.GetIndexEntries(); len(entries) != 2 {
+		t.Errorf("重新打开后应有2个索引项，实际为: %d", len(entries))
+	}
+	reopenedSectPr := reopened.getSectionProperties()
+	if reopenedSectPr.Columns == nil || reopenedSectPr.Columns.Num != "3" {
+		t.Error("重新打开后索引节的分栏设置丢失")
+	}
+	breaks := 0
+	for _, para := range reopened.Body.GetParagraphs() {
+		if para.Properties != nil && para.Properties.SectionProperties != nil {
+			breaks++
+		}
+	}
+	if breaks != 1 {
+		t.Errorf("重新打开后应有1个段落级分节符，实际为: %d", breaks)
+	}
+}
+
+// TestGenerateIndexStrokeOrder 测试笔画排序的索引不按首字插入分组标题
+func TestGenerateIndexStrokeOrder(t *testing.T) {
+	doc := New()
+	for _, term := range []string{"王府井", "木材", "中心", "日程", "月报"} {
+		doc.AddParagraph(term + "相关内容。").AddIndexEntry(term, nil)
+	}
+
+	config := DefaultIndexConfig()
+	config.SortOrder = IndexSortStroke
+	if err := doc.GenerateIndex(config); err != nil {
+		t.Fatalf("生成索引失败: %v", err)
+	}
+
+	var texts []string
+	for _, para := range doc.Body.GetParagraphs() {
+		for _, run := range para.Runs {
+			if run.InstrText != nil && strings.Contains(run.InstrText.Content, "INDEX") && strings.Contains(run.InstrText.Content, `\h`) {
+				t.Errorf("笔画排序的INDEX域不应包含分组标题开关: %s", run.InstrText.Content)
+			}
+			if run.Text.Content != "" {
+				texts = append(texts, run.Text.Content)
+			}
+		}
+	}
+	all := strings.Join(texts, "|")
+	for _, first := range []string{"王", "木", "中", "日", "月"} {
+		if strings.Contains(all, "|"+first+"|") {
+			t.Errorf("首字 %s 不应作为分组标题: %s", first, all)
+		}
+	}
+	if !strings.Contains(all, "索引|") || !strings.Contains(all, "中心") {
+		t.Errorf("索引缓存结果不正确: %s", all)
+	}
+}
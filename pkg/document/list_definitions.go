@@ -0,0 +1,763 @@
+// Package document 提供编号定义的解析、查询与修改功能
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// ListDefinition 列表定义（编号实例及其引用的抽象编号）
+type ListDefinition struct {
+	NumID       string           // 编号实例ID（段落 w:numId 引用的值）
+	AbstractNum *AbstractNum     // 抽象编号定义
+	Overrides   []*LevelOverride // 编号实例上的级别覆盖
+}
+
+// GetLevel 获取指定级别的有效定义（已应用 w:lvlOverride 中的级别替换和起始编号）
+func (ld *ListDefinition) GetLevel(ilvl int) *Level {
+	var level *Level
+	if ld.AbstractNum != nil {
+		for _, l := range ld.AbstractNum.Levels {
+			if l.ILevel == strconv.Itoa(ilvl) {
+				level = l
+				break
+			}
+		}
+	}
+
+	for _, override := range ld.Overrides {
+		if override.ILevel != strconv.Itoa(ilvl) {
+			continue
+		}
+		if override.Level != nil {
+			level = override.Level
+		}
+		if override.StartOverride != nil && level != nil {
+			overridden := *level
+			overridden.Start = &Start{Val: override.StartOverride.Val}
+			level = &overridden
+		}
+	}
+	return level
+}
+
+// ListLevelConfig 列表级别修改配置，零值字段表示保持不变
+type ListLevelConfig struct {
+	NumFmt        string // 编号格式，如 decimal、lowerLetter、bullet、chineseCounting
+	LevelText     string // 级别文本，如 "%1.%2."、"•"
+	Start         int    // 起始编号
+	Restart       *int   // 重新开始编号的级别（w:lvlRestart，0表示从不重新开始）
+	IndentLeft    int    // 左缩进（TWIPs）
+	Hanging       int    // 悬挂缩进（TWIPs）
+	BulletFont    string // 项目符号字体，如 Symbol、Wingdings
+	Justification string // 编号对齐方式：left、center、right
+}
+
+// parseNumbering 解析 word/numbering.xml，将已有的编号定义载入编号管理器
+func (d *Document) parseNumbering() error {
+	data, ok := d.parts["word/numbering.xml"]
+	if !ok {
+		Debugf("文档不包含编号定义")
+		return nil
+	}
+
+	numbering, err := parseNumberingXML(data)
+	if err != nil {
+		return WrapError("parse_numbering", err)
+	}
+
+	d.numberingManager = newNumberingManager()
+	d.numberingManager.load(numbering)
+	Debugf("编号定义解析完成: %d 个抽象编号, %d 个编号实例", len(numbering.AbstractNums), len(numbering.NumberingInstances))
+	return nil
+}
+
+// load 载入编号定义，并使新分配的ID不与已有ID冲突
+func (m *NumberingManager) load(numbering *Numbering) {
+	m.start, m.before, m.after = numbering.start, numbering.before, numbering.after
+	for _, abstractNum := range numbering.AbstractNums {
+		m.abstractNums["abstract_"+abstractNum.AbstractNumID] = abstractNum
+		if id, err := strconv.Atoi(abstractNum.AbstractNumID); err == nil && id >= m.nextAbstractNumID {
+			m.nextAbstractNumID = id + 1
+		}
+	}
+	for _, instance := range numbering.NumberingInstances {
+		m.numInstances[instance.NumID] = instance
+		if id, err := strconv.Atoi(instance.NumID); err == nil && id >= m.nextNumID {
+			m.nextNumID = id + 1
+		}
+	}
+}
+
+// clone 深拷贝编号管理器
+func (m *NumberingManager) clone() *NumberingManager {
+	cloned := newNumberingManager()
+	data, err := m.marshal()
+	if err == nil {
+		if numbering, err := parseNumberingXML(data); err == nil {
+			cloned.load(numbering)
+		}
+	}
+	cloned.nextAbstractNumID = m.nextAbstractNumID
+	cloned.nextNumID = m.nextNumID
+	return cloned
+}
+
+// abstractNumByID 根据抽象编号ID查找定义
+func (m *NumberingManager) abstractNumByID(abstractNumID string) *AbstractNum {
+	for _, abstractNum := range m.abstractNums {
+		if abstractNum.AbstractNumID == abstractNumID {
+			return abstractNum
+		}
+	}
+	return nil
+}
+
+// GetListDefinitions 获取文档中的所有列表定义（按编号实例ID排序）
+//
+// 打开的文档会解析 word/numbering.xml 中已有的定义，新增的列表会分配不冲突的ID。
+//
+// 示例:
+//
+//	for _, def := range doc.GetListDefinitions() {
+//		level := def.GetLevel(0)
+//		fmt.Printf("numId=%s 格式=%s 文本=%s\n", def.NumID, level.NumFmt.Val, level.LevelText.Val)
+//	}
+func (d *Document) GetListDefinitions() []*ListDefinition {
+	manager := d.getNumberingManager()
+
+	definitions := make([]*ListDefinition, 0, len(manager.numInstances))
+	for _, instance := range manager.numInstances {
+		definition := &ListDefinition{
+			NumID:     instance.NumID,
+			Overrides: instance.LevelOverrides,
+		}
+		if instance.AbstractNumID != nil {
+			definition.AbstractNum = manager.abstractNumByID(instance.AbstractNumID.Val)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return compareNumericID(definitions[i].NumID, definitions[j].NumID)
+	})
+	return definitions
+}
+
+// GetListDefinition 根据编号实例ID获取列表定义
+func (d *Document) GetListDefinition(numID string) *ListDefinition {
+	for _, definition := range d.GetListDefinitions() {
+		if definition.NumID == numID {
+			return definition
+		}
+	}
+	return nil
+}
+
+// ModifyListLevel 修改列表指定级别的编号格式
+//
+// 如果编号实例通过 w:lvlOverride 替换了该级别，则修改替换后的级别；否则修改其
+// 引用的抽象编号，此时所有引用同一抽象编号的列表都会受到影响。
+//
+// 示例:
+//
+//	// 将第二级改为 "a)" 格式
+//	err := doc.ModifyListLevel("1", 1, &document.ListLevelConfig{
+//		NumFmt:    "lowerLetter",
+//		LevelText: "%2)",
+//	})
+func (d *Document) ModifyListLevel(numID string, ilvl int, config *ListLevelConfig) error {
+	if config == nil {
+		return NewValidationError("config", "", "级别配置不能为空")
+	}
+	if ilvl < 0 || ilvl > 8 {
+		return NewValidationError("ilvl", strconv.Itoa(ilvl), "列表级别必须在0-8之间")
+	}
+
+	manager := d.getNumberingManager()
+	instance, ok := manager.numInstances[numID]
+	if !ok {
+		return WrapErrorWithContext("modify_list_level", fmt.Errorf("编号实例不存在"), numID)
+	}
+
+	var level *Level
+	for _, override := range instance.LevelOverrides {
+		if override.ILevel == strconv.Itoa(ilvl) && override.Level != nil {
+			level = override.Level
+		}
+	}
+	if level == nil && instance.AbstractNumID != nil {
+		if abstractNum := manager.abstractNumByID(instance.AbstractNumID.Val); abstractNum != nil {
+			for _, l := range abstractNum.Levels {
+				if l.ILevel == strconv.Itoa(ilvl) {
+					level = l
+				}
+			}
+		}
+	}
+	if level == nil {
+		return WrapErrorWithContext("modify_list_level", fmt.Errorf("列表级别 %d 不存在", ilvl), numID)
+	}
+
+	applyListLevelConfig(level, config)
+	d.ensureNumberingInitialized()
+	d.updateNumberingFile()
+
+	Infof("修改列表 %s 第 %d 级编号格式", numID, ilvl)
+	return nil
+}
+
+// applyListLevelConfig 将级别配置应用到级别定义
+func applyListLevelConfig(level *Level, config *ListLevelConfig) {
+	if config.NumFmt != "" {
+		level.NumFmt = &NumFmt{Val: config.NumFmt}
+	}
+	if config.LevelText != "" {
+		level.LevelText = &LevelText{Val: config.LevelText}
+	}
+	if config.Start > 0 {
+		level.Start = &Start{Val: strconv.Itoa(config.Start)}
+	}
+	if config.Restart != nil {
+		level.LvlRestart = &NumberingValue{Val: strconv.Itoa(*config.Restart)}
+	}
+	if config.Justification != "" {
+		level.LevelJc = &LevelJc{Val: config.Justification}
+	}
+	if config.IndentLeft > 0 || config.Hanging > 0 {
+		if level.PPr == nil {
+			level.PPr = &LevelPPr{}
+		}
+		if level.PPr.Ind == nil {
+			level.PPr.Ind = &LevelIndent{}
+		}
+		if config.IndentLeft > 0 {
+			level.PPr.Ind.Left = strconv.Itoa(config.IndentLeft)
+		}
+		if config.Hanging > 0 {
+			level.PPr.Ind.Hanging = strconv.Itoa(config.Hanging)
+			level.PPr.Ind.FirstLine = ""
+		}
+	}
+	if config.BulletFont != "" {
+		level.RPr = &LevelRPr{
+			FontFamily: &FontFamily{ASCII: config.BulletFont, HAnsi: config.BulletFont, Hint: "default"},
+		}
+	}
+}
+
+// GetListLabel 计算段落在文档中渲染后的列表编号标签，如 "2.1.a"、"第一章"、"•"
+//
+// 编号按文档顺序计数，遵循起始编号、lvlRestart、startOverride 和 isLgl 设置；
+// 通过样式（如标题编号）获得编号的段落同样适用。非列表段落和未添加到文档的段落
+// 返回空字符串。需要所有段落的标签时使用 Document.GetListLabels，只遍历一次文档。
+//
+// 示例:
+//
+//	para := doc.AddNumberedList("第一项", 0, document.ListTypeDecimal)
+//	fmt.Println(para.GetListLabel()) // 1.
+func (p *Paragraph) GetListLabel() string {
+	doc := p.doc
+	if doc == nil || doc.Body == nil {
+		return ""
+	}
+
+	numberer := doc.newListNumberer()
+	label := ""
+	found := false
+	forEachParagraph(doc.Body.Elements, func(paragraph *Paragraph) {
+		if found {
+			return
+		}
+		current, _ := numberer.next(paragraph, doc.getHeadingLevel(paragraph))
+		if paragraph == p {
+			label = current
+			found = true
+		}
+	})
+	return label
+}
+
+// GetListLabels 按文档顺序一次计算正文（含表格）中所有编号段落的列表编号标签
+//
+// 示例:
+//
+//	labels := doc.GetListLabels()
+//	for _, para := range doc.Body.GetParagraphs() {
+//		if label := labels[para]; label != "" {
+//			fmt.Println(label, para.GetText())
+//		}
+//	}
+func (d *Document) GetListLabels() map[*Paragraph]string {
+	labels := make(map[*Paragraph]string)
+	if d.Body == nil {
+		return labels
+	}
+
+	numberer := d.newListNumberer()
+	forEachParagraph(d.Body.Elements, func(paragraph *Paragraph) {
+		if label, _ := numberer.next(paragraph, d.getHeadingLevel(paragraph)); label != "" {
+			labels[paragraph] = label
+		}
+	})
+	return labels
+}
+
+// listNumberer 按文档顺序计算列表编号标签
+type listNumberer struct {
+	doc         *Document
+	definitions map[string]*ListDefinition // 按编号实例ID索引的列表定义
+	counters    map[string][]int           // 按抽象编号记录各级计数（Word中引用同一抽象编号的列表连续编号）
+	seen        map[string]bool            // 已出现的编号实例
+}
+
+// newListNumberer 创建列表编号计算器
+func (d *Document) newListNumberer() *listNumberer {
+	definitions := make(map[string]*ListDefinition)
+	for _, definition := range d.GetListDefinitions() {
+		definitions[definition.NumID] = definition
+	}
+	return &listNumberer{
+		doc:         d,
+		definitions: definitions,
+		counters:    make(map[string][]int),
+		seen:        make(map[string]bool),
+	}
+}
+
+// next 计算段落的编号标签及其后的分隔文本，并推进计数；未编号的段落返回空字符串
+func (n *listNumberer) next(paragraph *Paragraph, headingLevel int) (string, string) {
+	numID, ilvl := n.doc.paragraphNumbering(paragraph, headingLevel, n.definitions)
+	if numID == "" || ilvl < 0 || ilvl > 8 {
+		return "", ""
+	}
+
+	definition := n.definitions[numID]
+	if definition == nil || definition.AbstractNum == nil {
+		return "", ""
+	}
+
+	levels := make([]*Level, 9)
+	for i := range levels {
+		levels[i] = definition.GetLevel(i)
+	}
+	if levels[ilvl] == nil {
+		return "", ""
+	}
+
+	key := definition.AbstractNum.AbstractNumID
+	counters, ok := n.counters[key]
+	if !ok {
+		counters = make([]int, 9)
+		for i := range counters {
+			counters[i] = levelStart(levels[i]) - 1
+		}
+		n.counters[key] = counters
+	}
+
+	// 编号实例首次出现时应用起始编号覆盖
+	if !n.seen[numID] {
+		n.seen[numID] = true
+		for _, override := range definition.Overrides {
+			if override.StartOverride == nil {
+				continue
+			}
+			if i, err := strconv.Atoi(override.ILevel); err == nil && i >= 0 && i < 9 {
+				counters[i] = levelStart(levels[i]) - 1
+			}
+		}
+	}
+
+	counters[ilvl]++
+	for i := ilvl + 1; i < 9; i++ {
+		if levelRestartsAfter(levels[i], i, ilvl) {
+			counters[i] = levelStart(levels[i]) - 1
+		}
+	}
+	// 上级尚未出现时从起始值计算
+	for i := 0; i < ilvl; i++ {
+		if counters[i] < levelStart(levels[i]) {
+			counters[i] = levelStart(levels[i])
+		}
+	}
+
+	level := levels[ilvl]
+	if level.LevelText == nil {
+		return "", ""
+	}
+
+	suffix := " " // w:suff 默认为tab，在纯文本中以空格表示
+	if level.Suffix != nil && level.Suffix.Val == "nothing" {
+		suffix = ""
+	}
+	return formatLevelText(level.LevelText.Val, counters[:ilvl+1], levels, level.IsLgl != nil), suffix
+}
+
+// levelRestartsAfter 判断第 ilvl 级出现时，第 index 级是否重新开始编号
+func levelRestartsAfter(level *Level, index, ilvl int) bool {
+	if level == nil || level.LvlRestart == nil {
+		return true
+	}
+	restart, err := strconv.Atoi(level.LvlRestart.Val)
+	if err != nil {
+		return true
+	}
+	if restart == 0 {
+		return false
+	}
+	// lvlRestart 为从1开始的级别编号：当该级别或更高级别出现时重新开始
+	return ilvl < restart && ilvl < index
+}
+
+// paragraphNumbering 获取段落的编号实例和级别（段落直接编号优先于样式编号）
+func (d *Document) paragraphNumbering(paragraph *Paragraph, headingLevel int, definitions map[string]*ListDefinition) (string, int) {
+	numID, ilvl := "", -1
+	if paragraph.Properties != nil && paragraph.Properties.NumberingProperties != nil {
+		numPr := paragraph.Properties.NumberingProperties
+		if numPr.NumID != nil {
+			if numPr.NumID.Val == "0" {
+				return "", 0
+			}
+			numID = numPr.NumID.Val
+		}
+		if numPr.ILevel != nil {
+			ilvl, _ = strconv.Atoi(numPr.ILevel.Val)
+		}
+	}
+
+	if numID == "" || ilvl < 0 {
+		styleID, styleNumPr := d.paragraphStyleNumPr(paragraph, headingLevel)
+		if styleNumPr == nil {
+			if numID != "" {
+				return numID, 0
+			}
+			return "", 0
+		}
+		if numID == "" {
+			if styleNumPr.NumID == nil || styleNumPr.NumID.Val == "0" {
+				return "", 0
+			}
+			numID = styleNumPr.NumID.Val
+		}
+		if ilvl < 0 {
+			ilvl = styleNumberingLevel(definitions[numID], styleID, styleNumPr)
+		}
+	}
+	return numID, ilvl
+}
+
+// paragraphStyleNumPr 获取段落样式上的编号属性
+func (d *Document) paragraphStyleNumPr(paragraph *Paragraph, headingLevel int) (string, *style.NumPr) {
+	var paragraphStyle *style.Style
+	if paragraph.Properties != nil && paragraph.Properties.ParagraphStyle != nil {
+		paragraphStyle = d.styleManager.GetStyleWithInheritance(paragraph.Properties.ParagraphStyle.Val)
+	}
+	if (paragraphStyle == nil || paragraphStyle.ParagraphPr == nil || paragraphStyle.ParagraphPr.NumPr == nil) && headingLevel > 0 {
+		paragraphStyle = d.styleManager.GetStyle(fmt.Sprintf("Heading%d", headingLevel))
+	}
+	if paragraphStyle == nil || paragraphStyle.ParagraphPr == nil || paragraphStyle.ParagraphPr.NumPr == nil {
+		return "", nil
+	}
+	return paragraphStyle.StyleID, paragraphStyle.ParagraphPr.NumPr
+}
+
+// styleNumberingLevel 确定样式编号的级别：优先使用样式中的 w:ilvl，
+// 否则查找通过 w:pStyle 关联该样式的级别
+func styleNumberingLevel(definition *ListDefinition, styleID string, numPr *style.NumPr) int {
+	if numPr.ILvl != nil {
+		if ilvl, err := strconv.Atoi(numPr.ILvl.Val); err == nil {
+			return ilvl
+		}
+	}
+	if definition != nil && definition.AbstractNum != nil {
+		for _, level := range definition.AbstractNum.Levels {
+			if level.PStyle != nil && level.PStyle.Val == styleID {
+				ilvl, _ := strconv.Atoi(level.ILevel)
+				return ilvl
+			}
+		}
+	}
+	return 0
+}
+
+// levelStart 获取级别的起始编号
+func levelStart(level *Level) int {
+	if level == nil || level.Start == nil {
+		return 1
+	}
+	start, err := strconv.Atoi(level.Start.Val)
+	if err != nil {
+		return 1
+	}
+	return start
+}
+
+// parseNumberingXML 解析 numbering.xml 内容
+//
+// 同时记录各定义的原始XML，未修改的定义写回时原样输出（参见 NumberingManager.marshal）。
+func parseNumberingXML(data []byte) (*Numbering, error) {
+	numbering := &Numbering{
+		Xmlns:              "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+		AbstractNums:       []*AbstractNum{},
+		NumberingInstances: []*NumInstance{},
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t, ok := token.(xml.StartElement)
+		if !ok {
+			if _, ok := token.(xml.EndElement); ok {
+				depth--
+			}
+			continue
+		}
+		if depth == 0 {
+			// 根元素开始标签，命名空间声明与模型使用的 w 前缀一致时保留
+			start := data[offset:decoder.InputOffset()]
+			if bytes.Contains(start, []byte(`xmlns:w="`+numbering.Xmlns+`"`)) && !bytes.HasSuffix(start, []byte("/>")) {
+				numbering.start = append([]byte(nil), start...)
+			}
+			depth++
+			continue
+		}
+
+		switch t.Name.Local {
+		case "abstractNum":
+			abstractNum, err := parseAbstractNum(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			abstractNum.raw = append([]byte(nil), data[offset:decoder.InputOffset()]...)
+			if abstractNum.parsed, err = xml.Marshal(abstractNum); err != nil {
+				return nil, err
+			}
+			numbering.AbstractNums = append(numbering.AbstractNums, abstractNum)
+		case "num":
+			instance, err := parseNumInstance(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			instance.raw = append([]byte(nil), data[offset:decoder.InputOffset()]...)
+			if instance.parsed, err = xml.Marshal(instance); err != nil {
+				return nil, err
+			}
+			numbering.NumberingInstances = append(numbering.NumberingInstances, instance)
+		default:
+			// 模型未覆盖的元素原样保留：编号实例之前的（如 w:numPicBullet）写在抽象编号之前
+			if err := skipNumberingElement(decoder); err != nil {
+				return nil, err
+			}
+			raw := data[offset:decoder.InputOffset()]
+			if len(numbering.NumberingInstances) == 0 {
+				numbering.before = append(numbering.before, raw...)
+			} else {
+				numbering.after = append(numbering.after, raw...)
+			}
+		}
+	}
+
+	return numbering, nil
+}
+
+// parseAbstractNum 解析抽象编号定义
+func parseAbstractNum(decoder *xml.Decoder, startElement xml.StartElement) (*AbstractNum, error) {
+	abstractNum := &AbstractNum{
+		AbstractNumID: getAttributeValue(startElement.Attr, "abstractNumId"),
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			val := getAttributeValue(t.Attr, "val")
+			switch t.Name.Local {
+			case "nsid":
+				abstractNum.Nsid = &NumberingValue{Val: val}
+			case "multiLevelType":
+				abstractNum.MultiLevelType = &MultiLevelType{Val: val}
+			case "tmpl":
+				abstractNum.Tmpl = &NumberingValue{Val: val}
+			case "name":
+				abstractNum.Name = &NumberingValue{Val: val}
+			case "styleLink":
+				abstractNum.StyleLink = &NumberingValue{Val: val}
+			case "numStyleLink":
+				abstractNum.NumStyleLink = &NumberingValue{Val: val}
+			case "lvl":
+				level, err := parseNumberingLevel(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				abstractNum.Levels = append(abstractNum.Levels, level)
+				continue
+			}
+			if err := skipNumberingElement(decoder); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == startElement.Name.Local {
+				return abstractNum, nil
+			}
+		}
+	}
+}
+
+// parseNumInstance 解析编号实例
+func parseNumInstance(decoder *xml.Decoder, startElement xml.StartElement) (*NumInstance, error) {
+	instance := &NumInstance{
+		NumID: getAttributeValue(startElement.Attr, "numId"),
+	}
+
+	var currentOverride *LevelOverride
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "abstractNumId":
+				instance.AbstractNumID = &AbstractNumReference{Val: getAttributeValue(t.Attr, "val")}
+			case "lvlOverride":
+				currentOverride = &LevelOverride{ILevel: getAttributeValue(t.Attr, "ilvl")}
+				instance.LevelOverrides = append(instance.LevelOverrides, currentOverride)
+				continue
+			case "startOverride":
+				if currentOverride != nil {
+					currentOverride.StartOverride = &NumberingValue{Val: getAttributeValue(t.Attr, "val")}
+				}
+			case "lvl":
+				level, err := parseNumberingLevel(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				if currentOverride != nil {
+					currentOverride.Level = level
+				}
+				continue
+			}
+			if err := skipNumberingElement(decoder); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "lvlOverride":
+				currentOverride = nil
+			case startElement.Name.Local:
+				return instance, nil
+			}
+		}
+	}
+}
+
+// parseNumberingLevel 解析编号级别
+func parseNumberingLevel(decoder *xml.Decoder, startElement xml.StartElement) (*Level, error) {
+	level := &Level{
+		ILevel:    getAttributeValue(startElement.Attr, "ilvl"),
+		Tplc:      getAttributeValue(startElement.Attr, "tplc"),
+		Tentative: getAttributeValue(startElement.Attr, "tentative"),
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			val := getAttributeValue(t.Attr, "val")
+			switch t.Name.Local {
+			case "start":
+				level.Start = &Start{Val: val}
+			case "numFmt":
+				level.NumFmt = &NumFmt{Val: val}
+			case "lvlRestart":
+				level.LvlRestart = &NumberingValue{Val: val}
+			case "pStyle":
+				level.PStyle = &LevelPStyle{Val: val}
+			case "isLgl":
+				if val != "0" && val != "false" {
+					level.IsLgl = &LevelIsLgl{}
+				}
+			case "suff":
+				level.Suffix = &LevelSuffix{Val: val}
+			case "lvlText":
+				level.LevelText = &LevelText{Val: val}
+			case "lvlJc":
+				level.LevelJc = &LevelJc{Val: val}
+			case "ind":
+				if level.PPr == nil {
+					level.PPr = &LevelPPr{}
+				}
+				level.PPr.Ind = &LevelIndent{
+					Left:      firstAttributeValue(t.Attr, "left", "start"),
+					Hanging:   getAttributeValue(t.Attr, "hanging"),
+					FirstLine: getAttributeValue(t.Attr, "firstLine"),
+				}
+			case "rFonts":
+				level.RPr = &LevelRPr{FontFamily: parseFontFamilyAttrs(t.Attr)}
+			case "pPr", "rPr":
+				// 容器元素，继续解析其子元素
+				continue
+			}
+			if err := skipNumberingElement(decoder); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == startElement.Name.Local {
+				return level, nil
+			}
+		}
+	}
+}
+
+// firstAttributeValue 返回第一个存在的属性值
+func firstAttributeValue(attrs []xml.Attr, names ...string) string {
+	for _, name := range names {
+		if value := getAttributeValue(attrs, name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// skipNumberingElement 跳过当前元素的剩余内容
+func skipNumberingElement(decoder *xml.Decoder) error {
+	depth := 1
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Package document 编号定义解析与编辑测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testNumberingXML 测试用编号定义：多级编号 "%1." / "%1.%2" / "%1.%2.%3"（第三级为小写字母）
+const testNumberingXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:abstractNum w:abstractNumId="3">
+    <w:nsid w:val="1A2B3C4D"/>
+    <w:multiLevelType w:val="multilevel"/>
+    <w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="420" w:hanging="420"/></w:pPr></w:lvl>
+    <w:lvl w:ilvl="1"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1.%2"/><w:lvlJc w:val="left"/></w:lvl>
+    <w:lvl w:ilvl="2"><w:start w:val="1"/><w:numFmt w:val="lowerLetter"/><w:lvlRestart w:val="1"/><w:lvlText w:val="%1.%2.%3"/><w:lvlJc w:val="left"/></w:lvl>
+  </w:abstractNum>
+  <w:abstractNum w:abstractNumId="7">
+    <w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/><w:lvlText w:val=""/><w:rPr><w:rFonts w:ascii="Wingdings" w:hAnsi="Wingdings" w:hint="default"/></w:rPr></w:lvl>
+  </w:abstractNum>
+  <w:num w:numId="5"><w:abstractNumId w:val="3"/></w:num>
+  <w:num w:numId="6"><w:abstractNumId w:val="3"/><w:lvlOverride w:ilvl="0"><w:startOverride w:val="10"/></w:lvlOverride></w:num>
+  <w:num w:numId="9"><w:abstractNumId w:val="7"/></w:num>
+</w:numbering>`
+
+// newTestListDocument 创建包含测试编号定义的文档
+func newTestListDocument(t *testing.T) *Document {
+	doc := New()
+	numbering, err := parseNumberingXML([]byte(testNumberingXML))
+	if err != nil {
+		t.Fatalf("解析编号定义失败: %v", err)
+	}
+	doc.getNumberingManager().load(numbering)
+	doc.parts["word/numbering.xml"] = []byte(testNumberingXML)
+	return doc
+}
+
+// addTestListParagraph 添加引用指定编号的段落
+func addTestListParagraph(doc *Document, text, numID, ilvl string) *Paragraph {
+	para := doc.AddParagraph(text)
+	para.Properties = &ParagraphProperties{
+		NumberingProperties: &NumberingProperties{
+			ILevel: &ILevel{Val: ilvl},
+			NumID:  &NumID{Val: numID},
+		},
+	}
+	return para
+}
+
+// TestParseNumberingXML 测试解析编号定义
+func TestParseNumberingXML(t *testing.T) {
+	doc := newTestListDocument(t)
+
+	definitions := doc.GetListDefinitions()
+	if len(definitions) != 3 || definitions[0].NumID != "5" {
+		t.Fatalf("应解析出3个列表定义，实际为: %d", len(definitions))
+	}
+
+	level := definitions[0].GetLevel(0)
+	if level.LevelText.Val != "%1." || level.PPr.Ind.Left != "420" || level.PPr.Ind.Hanging != "420" {
+		t.Errorf("第一级定义解析不正确: %+v", level)
+	}
+	if definitions[0].GetLevel(2).LvlRestart.Val != "1" {
+		t.Error("lvlRestart 解析不正确")
+	}
+	if definitions[1].GetLevel(0).Start.Val != "10" {
+		t.Error("startOverride 应覆盖起始编号")
+	}
+	if definitions[2].GetLevel(0).RPr.FontFamily.ASCII != "Wingdings" {
+		t.Error("项目符号字体解析不正确")
+	}
+
+	// 新分配的ID不应与已有ID冲突
+	para := doc.AddNumberedList("新列表", 0, ListTypeDecimal)
+	numID := para.Properties.NumberingProperties.NumID.Val
+	if numID != "10" {
+		t.Errorf("新列表的numId应为10，实际为: %s", numID)
+	}
+	if definition := doc.GetListDefinition(numID); definition.AbstractNum.AbstractNumID != "8" {
+		t.Errorf("新抽象编号ID应为8，实际为: %s", definition.AbstractNum.AbstractNumID)
+	}
+}
+
+// TestGetListLabel 测试计算列表编号标签
+func TestGetListLabel(t *testing.T) {
+	doc := newTestListDocument(t)
+
+	paras := []*Paragraph{
+		addTestListParagraph(doc, "一", "5", "0"),
+		addTestListParagraph(doc, "二", "5", "0"),
+		addTestListParagraph(doc, "二.一", "5", "1"),
+		addTestListParagraph(doc, "a", "5", "2"),
+		addTestListParagraph(doc, "b", "5", "2"),
+		addTestListParagraph(doc, "二.二", "5", "1"),
+		addTestListParagraph(doc, "c", "5", "2"),
+		addTestListParagraph(doc, "重新编号", "6", "0"),
+		addTestListParagraph(doc, "符号", "9", "0"),
+	}
+	doc.AddParagraph("普通段落")
+
+	expected := []string{"1.", "2.", "2.1", "2.1.a", "2.1.b", "2.2", "2.2.c", "10.", ""}
+	for i, para := range paras {
+		if got := para.GetListLabel(); got != expected[i] {
+			t.Errorf("第%d个段落的编号应为 %q，实际为: %q", i+1, expected[i], got)
+		}
+	}
+
+	if label := doc.Body.GetParagraphs()[len(paras)].GetListLabel(); label != "" {
+		t.Errorf("普通段落不应有编号，实际为: %q", label)
+	}
+
+	// 一次计算所有段落的标签，结果与逐个计算一致
+	labels := doc.GetListLabels()
+	for i, para := range paras {
+		if labels[para] != expected[i] {
+			t.Errorf("第%d个段落的批量编号应为 %q，实际为: %q", i+1, expected[i], labels[para])
+		}
+	}
+	if label := (&Paragraph{}).GetListLabel(); label != "" {
+		t.Errorf("未添加到文档的段落不应有编号，实际为: %q", label)
+	}
+}
+
+// TestModifyListLevel 测试修改列表级别并保存后重新打开
+func TestModifyListLevel(t *testing.T) {
+	doc := newTestListDocument(t)
+	addTestListParagraph(doc, "一", "5", "0")
+	para := addTestListParagraph(doc, "一.一", "5", "1")
+
+	if err := doc.ModifyListLevel("5", 1, &ListLevelConfig{NumFmt: "upperRoman", LevelText: "%2)", IndentLeft: 840}); err != nil {
+		t.Fatalf("修改列表级别失败: %v", err)
+	}
+	if got := para.GetListLabel(); got != "I)" {
+		t.Errorf("修改后编号应为 I)，实际为: %s", got)
+	}
+	if err := doc.ModifyListLevel("99", 0, &ListLevelConfig{NumFmt: "decimal"}); err == nil {
+		t.Error("不存在的编号实例应返回错误")
+	}
+
+	filename := filepath.Join(t.TempDir(), "list.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+
+	definition := reopened.GetListDefinition("5")
+	if definition == nil {
+		t.Fatal("重新打开后应包含编号实例5")
+	}
+	level := definition.GetLevel(1)
+	if level.NumFmt.Val != "upperRoman" || level.PPr.Ind.Left != "840" {
+		t.Errorf("重新打开后级别定义不正确: %+v", level)
+	}
+	if !strings.Contains(string(reopened.parts["word/numbering.xml"]), "1A2B3C4D") {
+		t.Error("nsid 应在保存后保留")
+	}
+
+	var labels []string
+	for _, p := range reopened.Body.GetParagraphs() {
+		if label := p.GetListLabel(); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	if strings.Join(labels, ",") != "1.,I)" {
+		t.Errorf("重新打开后的编号不正确: %v", labels)
+	}
+}
+
+// TestNumberingRoundTrip 测试修改编号定义时保留未修改定义中模型未覆盖的内容
+func TestNumberingRoundTrip(t *testing.T) {
+	picture := `<w:numPicBullet w:numPicBulletId="0"><w:pict><v:shape id="_x0000_i1025" type="#_x0000_t75"/></w:pict></w:numPicBullet>`
+	preserved := `<w:abstractNum w:abstractNumId="1" w15:restartNumberingAfterBreak="0"><w:multiLevelType w:val="hybridMultilevel"/>` +
+		`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="&#xF0B7;"/><w:lvlPicBulletId w:val="0"/><w:lvlJc w:val="left"/>` +
+		`<w:pPr><w:tabs><w:tab w:val="num" w:pos="720"/></w:tabs><w:ind w:left="720" w:hanging="360"/></w:pPr>` +
+		`<w:rPr><w:rFonts w:ascii="Symbol" w:hAnsi="Symbol" w:hint="default"/><w:color w:val="FF0000"/></w:rPr></w:lvl></w:abstractNum>`
+	modified := `<w:abstractNum w:abstractNumId="2"><w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/><w:legacy w:legacy="1"/></w:lvl></w:abstractNum>`
+	data := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml">` +
+		picture + preserved + modified +
+		`<w:num w:numId="1"><w:abstractNumId w:val="1"/></w:num><w:num w:numId="2"><w:abstractNumId w:val="2"/></w:num>` +
+		`<w:numIdMacAtCleanup w:val="1"/></w:numbering>`
+
+	doc := New()
+	doc.parts["word/numbering.xml"] = []byte(data)
+	if err := doc.parseNumbering(); err != nil {
+		t.Fatalf("解析编号定义失败: %v", err)
+	}
+	if err := doc.ModifyListLevel("2", 0, &ListLevelConfig{NumFmt: "upperRoman"}); err != nil {
+		t.Fatalf("修改列表级别失败: %v", err)
+	}
+	doc.AddNumberedList("新列表", 0, ListTypeDecimal)
+
+	result := string(doc.parts["word/numbering.xml"])
+	for _, expected := range []string{picture, preserved, `<w:num w:numId="1"><w:abstractNumId w:val="1"/></w:num>`, `<w:numIdMacAtCleanup w:val="1"/>`, `xmlns:w15=`} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("未修改的内容应原样保留: %s", expected)
+		}
+	}
+	if strings.Contains(result, modified) || !strings.Contains(result, `w:val="upperRoman"`) {
+		t.Error("修改过的抽象编号应重新生成")
+	}
+	if strings.Index(result, "numPicBullet") > strings.Index(result, "abstractNum") ||
+		strings.Index(result, "numIdMacAtCleanup") < strings.LastIndex(result, "<w:num ") {
+		t.Error("保留的元素顺序应符合规范")
+	}
+
+	// 重新解析后定义完整，新列表可用
+	reparsed, err := parseNumberingXML([]byte(result))
+	if err != nil {
+		t.Fatalf("重新解析编号定义失败: %v", err)
+	}
+	if len(reparsed.AbstractNums) != 3 || len(reparsed.NumberingInstances) != 3 {
+		t.Errorf("重新解析后的定义数量不正确: %d %d", len(reparsed.AbstractNums), len(reparsed.NumberingInstances))
+	}
+}
+
+// TestExtendedListTypes 测试中日韩及扩展编号格式的列表
+func TestExtendedListTypes(t *testing.T) {
+	doc := New()
+	if err := doc.CreateMultiLevelList([]ListItem{
+		{Text: "总则", Level: 0, Type: ListTypeChineseLegalSimplified, StartNumber: 1},
+		{Text: "范围", Level: 1, Type: ListTypeDecimalEnclosedCircle, StartNumber: 1},
+		{Text: "定义", Level: 1, Type: ListTypeDecimalEnclosedCircle, StartNumber: 1},
+	}); err != nil {
+		t.Fatalf("创建多级列表失败: %v", err)
+	}
+
+	var labels []string
+	for _, para := range doc.Body.GetParagraphs() {
+		labels = append(labels, para.GetListLabel())
+	}
+	if strings.Join(labels, ",") != "壹、,①,②" {
+		t.Errorf("扩展格式编号不正确: %v", labels)
+	}
+
+	para := doc.AddNumberedList("序数", 0, ListTypeOrdinal)
+	if got := para.GetListLabel(); got != "1st." {
+		t.Errorf("序数编号应为 1st.，实际为: %s", got)
+	}
+}
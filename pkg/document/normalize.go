@@ -0,0 +1,402 @@
+// Package document 直接格式转换为样式
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// NormalizeOptions 直接格式转换为样式的选项
+type NormalizeOptions struct {
+	// MinOccurrences 相同的直接格式至少出现的次数，达到后才转换为样式，默认为2
+	MinOccurrences int
+	// StyleIDPrefix 新建样式ID的前缀，默认为 "Normalized"
+	// 新建的段落样式为 NormalizedParagraph1、NormalizedParagraph2…，字符样式为 NormalizedCharacter1…
+	StyleIDPrefix string
+
+	SkipParagraphs bool // 不转换段落的直接格式
+	SkipRuns       bool // 不转换文本的直接格式
+	SkipMergeRuns  bool // 不合并相邻的相同格式文本
+}
+
+// NormalizeResult 直接格式转换为样式的结果
+type NormalizeResult struct {
+	// CreatedStyles 新建的样式ID
+	CreatedStyles []string
+	// ReusedStyles 格式相同而被直接引用的现有样式ID
+	ReusedStyles []string
+	// ParagraphsUpdated 改为引用样式的段落数
+	ParagraphsUpdated int
+	// RunsUpdated 改为引用样式的文本数
+	RunsUpdated int
+	// RunsMerged 合并相邻文本后减少的文本数
+	RunsMerged int
+}
+
+// formattingGroup 基于同一样式、直接格式相同的段落或文本
+type formattingGroup struct {
+	baseID     string // 原来引用的样式ID，未引用时为空
+	data       []byte // 直接格式的XML
+	paragraphs []*Paragraph
+	runs       []*Run
+}
+
+// count 返回组内段落或文本的数量
+func (g *formattingGroup) count() int {
+	return len(g.paragraphs) + len(g.runs)
+}
+
+// NormalizeFormatting 将正文和页眉页脚中重复出现的直接格式转换为样式
+//
+// 原来引用的样式相同、直接格式也相同的段落（或文本）归为一组，出现次数达到
+// MinOccurrences 时，组内段落（或文本）改为引用一个样式：样式表中已有基于同一样式、
+// 格式完全相同的样式时直接引用，否则新建基于原样式的段落（或字符）样式。
+// 段落的编号和节属性保留在段落上。之后合并同一段落中格式相同的相邻文本。
+func (d *Document) NormalizeFormatting(opts *NormalizeOptions) (*NormalizeResult, error) {
+	if opts == nil {
+		opts = &NormalizeOptions{}
+	}
+	minOccurrences := opts.MinOccurrences
+	if minOccurrences <= 0 {
+		minOccurrences = 2
+	}
+	prefix := opts.StyleIDPrefix
+	if prefix == "" {
+		prefix = "Normalized"
+	}
+
+	// 页眉页脚中的段落同样参与归并，保存时只写回有修改的页眉页脚
+	d.loadHeaderFooters()
+	var paragraphs []*Paragraph
+	for _, container := range d.styleContainers() {
+		forEachParagraph(container.elements, func(p *Paragraph) {
+			paragraphs = append(paragraphs, p)
+		})
+	}
+
+	result := &NormalizeResult{}
+	if !opts.SkipParagraphs {
+		var groups []*formattingGroup
+		index := make(map[string]*formattingGroup)
+		for _, p := range paragraphs {
+			data := paragraphFormattingXML(p.Properties)
+			if data == nil {
+				continue
+			}
+			baseID := ""
+			if p.Properties.ParagraphStyle != nil {
+				baseID = p.Properties.ParagraphStyle.Val
+			}
+			key := baseID + "\x00" + string(data)
+			group, ok := index[key]
+			if !ok {
+				group = &formattingGroup{baseID: baseID, data: data}
+				index[key] = group
+				groups = append(groups, group)
+			}
+			group.paragraphs = append(group.paragraphs, p)
+		}
+
+		for _, group := range groups {
+			if group.count() < minOccurrences {
+				continue
+			}
+			styleID, err := d.normalizedStyle(style.StyleTypeParagraph, group, prefix, result)
+			if err != nil {
+				return nil, err
+			}
+			if styleID == "" {
+				continue
+			}
+			for _, p := range group.paragraphs {
+				p.Properties = &ParagraphProperties{
+					ParagraphStyle:      &ParagraphStyle{Val: styleID},
+					NumberingProperties: p.Properties.NumberingProperties,
+					SectionProperties:   p.Properties.SectionProperties,
+				}
+			}
+			result.ParagraphsUpdated += len(group.paragraphs)
+		}
+	}
+
+	if !opts.SkipRuns {
+		var groups []*formattingGroup
+		index := make(map[string]*formattingGroup)
+		for _, p := range paragraphs {
+			for i := range p.Runs {
+				run := &p.Runs[i]
+				data := runFormattingXML(run.Properties)
+				if data == nil {
+					continue
+				}
+				baseID := ""
+				if run.Properties.RunStyle != nil {
+					baseID = run.Properties.RunStyle.Val
+				}
+				key := baseID + "\x00" + string(data)
+				group, ok := index[key]
+				if !ok {
+					group = &formattingGroup{baseID: baseID, data: data}
+					index[key] = group
+					groups = append(groups, group)
+				}
+				group.runs = append(group.runs, run)
+			}
+		}
+
+		for _, group := range groups {
+			if group.count() < minOccurrences {
+				continue
+			}
+			styleID, err := d.normalizedStyle(style.StyleTypeCharacter, group, prefix, result)
+			if err != nil {
+				return nil, err
+			}
+			if styleID == "" {
+				continue
+			}
+			for _, run := range group.runs {
+				run.Properties = &RunProperties{RunStyle: &RunStyle{Val: styleID}}
+			}
+			result.RunsUpdated += len(group.runs)
+		}
+	}
+
+	if !opts.SkipMergeRuns {
+		for _, p := range paragraphs {
+			result.RunsMerged += mergeAdjacentRuns(p)
+		}
+	}
+
+	sort.Strings(result.ReusedStyles)
+	Infof("直接格式转换完成：新建 %d 个样式，复用 %d 个样式，更新 %d 个段落、%d 个文本，合并 %d 个文本",
+		len(result.CreatedStyles), len(result.ReusedStyles), result.ParagraphsUpdated, result.RunsUpdated, result.RunsMerged)
+	return result, nil
+}
+
+// normalizedStyle 返回组内直接格式对应的样式ID，优先复用格式完全相同的现有样式
+// 直接格式无法用样式完整表示时返回空字符串，组内内容保持不变
+func (d *Document) normalizedStyle(styleType style.StyleType, group *formattingGroup, prefix string, result *NormalizeResult) (string, error) {
+	sm := d.styleManager
+	basedOn := group.baseID
+	if basedOn == "" {
+		if defaultStyle := sm.GetDefaultStyle(styleType); defaultStyle != nil {
+			basedOn = defaultStyle.StyleID
+		}
+	}
+
+	candidate := &style.Style{Type: string(styleType)}
+	if basedOn != "" {
+		candidate.BasedOn = &style.BasedOn{Val: basedOn}
+	}
+	var err error
+	if styleType == style.StyleTypeParagraph {
+		candidate.ParagraphPr, err = style.ParseParagraphPropertiesXML(group.data)
+	} else {
+		candidate.RunPr, err = style.ParseRunPropertiesXML(group.data)
+	}
+	if err != nil {
+		return "", WrapError("NormalizeFormatting", err)
+	}
+
+	signature := formattingSignature(group.data)
+	if styleFormattingSignature(candidate) != signature {
+		// 样式不支持的属性值（如关闭的 keepNext）会在转换中丢失
+		Debugf("直接格式无法完整转换为样式，保留原格式: %s", group.data)
+		return "", nil
+	}
+
+	var existing []*style.Style
+	for _, s := range sm.GetAllStyles() {
+		if s.Type == candidate.Type && !s.Default && s.BasedOn != nil && s.BasedOn.Val == basedOn &&
+			s.TablePr == nil && styleFormattingSignature(s) == signature {
+			existing = append(existing, s)
+		}
+	}
+	if len(existing) > 0 {
+		sort.Slice(existing, func(i, j int) bool { return existing[i].StyleID < existing[j].StyleID })
+		styleID := existing[0].StyleID
+		if !containsString(result.ReusedStyles, styleID) && !containsString(result.CreatedStyles, styleID) {
+			result.ReusedStyles = append(result.ReusedStyles, styleID)
+		}
+		return styleID, nil
+	}
+
+	kind := "Paragraph"
+	if styleType == style.StyleTypeCharacter {
+		kind = "Character"
+	}
+	for i := 1; ; i++ {
+		styleID := fmt.Sprintf("%s%s%d", prefix, kind, i)
+		if !sm.StyleExists(styleID) {
+			candidate.StyleID = styleID
+			candidate.Name = &style.StyleName{Val: fmt.Sprintf("%s %s %d", prefix, kind, i)}
+			candidate.CustomStyle = true
+			candidate.QFormat = &style.OnOff{}
+			break
+		}
+	}
+	sm.AddStyle(candidate)
+	result.CreatedStyles = append(result.CreatedStyles, candidate.StyleID)
+	Debugf("直接格式转换为样式 %s，应用于 %d 处", candidate.StyleID, group.count())
+	return candidate.StyleID, nil
+}
+
+// paragraphFormattingXML 返回段落的直接格式XML，没有直接格式时返回nil
+// 样式引用、编号和节属性不属于直接格式
+func paragraphFormattingXML(props *ParagraphProperties) []byte {
+	if props == nil {
+		return nil
+	}
+	formatting := *props
+	formatting.ParagraphStyle = nil
+	formatting.NumberingProperties = nil
+	formatting.SectionProperties = nil
+	if formatting == (ParagraphProperties{XMLName: formatting.XMLName}) {
+		return nil
+	}
+	data, err := xml.Marshal(&formatting)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// runFormattingXML 返回文本的直接格式XML（不含字符样式引用），没有直接格式时返回nil
+func runFormattingXML(props *RunProperties) []byte {
+	if props == nil {
+		return nil
+	}
+	formatting := *props
+	formatting.RunStyle = nil
+	if formatting == (RunProperties{XMLName: formatting.XMLName}) {
+		return nil
+	}
+	data, err := xml.Marshal(&formatting)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// styleFormattingSignature 返回样式段落和字符属性的格式签名
+func styleFormattingSignature(s *style.Style) string {
+	var signatures []string
+	if s.ParagraphPr != nil {
+		data, err := xml.Marshal(s.ParagraphPr)
+		if err != nil {
+			return ""
+		}
+		signatures = append(signatures, formattingSignature(data))
+	}
+	if s.RunPr != nil {
+		data, err := xml.Marshal(s.RunPr)
+		if err != nil {
+			return ""
+		}
+		signatures = append(signatures, formattingSignature(data))
+	}
+	return strings.Join(signatures, "\n")
+}
+
+// formattingSignature 返回与子元素顺序和属性顺序无关的格式签名，用于比较格式是否相同
+func formattingSignature(data []byte) string {
+	var lines []string
+	var path []string
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			path = append(path, t.Name.Local)
+			attrs := make([]string, 0, len(t.Attr))
+			for _, attr := range t.Attr {
+				attrs = append(attrs, attr.Name.Local+"="+attr.Value)
+			}
+			sort.Strings(attrs)
+			lines = append(lines, strings.Join(path, "/")+" "+strings.Join(attrs, " "))
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// formattingElementNames 返回格式XML中直接设置的属性名称（子元素名称，不含前缀）
+func formattingElementNames(data []byte) []string {
+	var names []string
+	depth := 0
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				names = append(names, t.Name.Local)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return names
+}
+
+// mergeAdjacentRuns 合并段落中格式相同的相邻纯文本，返回减少的文本数
+// 包含可编辑区域标记的段落不合并，避免标记位置失效
+func mergeAdjacentRuns(p *Paragraph) int {
+	if len(p.Runs) < 2 || len(p.Permissions) > 0 {
+		return 0
+	}
+
+	merged := p.Runs[:1]
+	for i := 1; i < len(p.Runs); i++ {
+		previous := &merged[len(merged)-1]
+		run := p.Runs[i]
+		if isPlainTextRun(previous) && isPlainTextRun(&run) &&
+			bytes.Equal(runPropertiesXML(previous.Properties), runPropertiesXML(run.Properties)) {
+			previous.Text.Content += run.Text.Content
+			content := previous.Text.Content
+			if previous.Text.Space == "preserve" || run.Text.Space == "preserve" ||
+				strings.TrimSpace(content) != content {
+				previous.Text.Space = "preserve"
+			}
+			continue
+		}
+		merged = append(merged, run)
+	}
+	count := len(p.Runs) - len(merged)
+	p.Runs = merged
+	return count
+}
+
+// isPlainTextRun 判断文本是否只包含文字（没有分隔符、图片、域和图形）
+func isPlainTextRun(run *Run) bool {
+	return run.Break == nil && run.Drawing == nil && run.FieldChar == nil && run.InstrText == nil && run.Pict == nil
+}
+
+// runPropertiesXML 返回文本属性的XML，用于比较格式是否相同
+func runPropertiesXML(props *RunProperties) []byte {
+	if props == nil {
+		return nil
+	}
+	data, err := xml.Marshal(props)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,349 @@
+// Package document 提供编号格式（numFmt）的文本渲染
+package document
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumberFormat OpenXML编号格式（ST_NumberFormat），用于列表、脚注尾注和页码
+type NumberFormat string
+
+const (
+	// NumberFormatDecimal 阿拉伯数字：1, 2, 3
+	NumberFormatDecimal NumberFormat = "decimal"
+	// NumberFormatUpperLetter 大写字母：A, B, C
+	NumberFormatUpperLetter NumberFormat = "upperLetter"
+	// NumberFormatLowerLetter 小写字母：a, b, c
+	NumberFormatLowerLetter NumberFormat = "lowerLetter"
+	// NumberFormatUpperRoman 大写罗马数字：I, II, III
+	NumberFormatUpperRoman NumberFormat = "upperRoman"
+	// NumberFormatLowerRoman 小写罗马数字：i, ii, iii
+	NumberFormatLowerRoman NumberFormat = "lowerRoman"
+	// NumberFormatChineseCounting 中文计数：一, 二, 十, 十一
+	NumberFormatChineseCounting NumberFormat = "chineseCounting"
+	// NumberFormatChineseCountingThousand 中文计数（含百千万）：一百零一, 一千
+	NumberFormatChineseCountingThousand NumberFormat = "chineseCountingThousand"
+	// NumberFormatChineseLegalSimplified 中文大写数字：壹, 贰, 叁, 壹拾
+	NumberFormatChineseLegalSimplified NumberFormat = "chineseLegalSimplified"
+	// NumberFormatIdeographTraditional 天干：甲, 乙, 丙
+	NumberFormatIdeographTraditional NumberFormat = "ideographTraditional"
+	// NumberFormatDecimalEnclosedCircle 带圈数字：①, ②, ③
+	NumberFormatDecimalEnclosedCircle NumberFormat = "decimalEnclosedCircle"
+	// NumberFormatDecimalFullWidth 全角数字：１, ２, ３
+	NumberFormatDecimalFullWidth NumberFormat = "decimalFullWidth"
+	// NumberFormatJapaneseCounting 日文计数：一, 十, 百一
+	NumberFormatJapaneseCounting NumberFormat = "japaneseCounting"
+	// NumberFormatKoreanDigital 韩文数字：일, 이, 삼, 십
+	NumberFormatKoreanDigital NumberFormat = "koreanDigital"
+	// NumberFormatOrdinal 英文序数：1st, 2nd, 3rd
+	NumberFormatOrdinal NumberFormat = "ordinal"
+	// NumberFormatCardinalText 英文基数词：One, Two, Three
+	NumberFormatCardinalText NumberFormat = "cardinalText"
+	// NumberFormatBullet 项目符号
+	NumberFormatBullet NumberFormat = "bullet"
+	// NumberFormatNone 不显示编号
+	NumberFormatNone NumberFormat = "none"
+)
+
+// cjkNumberStyle 中日韩数字的书写规则
+type cjkNumberStyle struct {
+	digits        [10]string // 0-9 对应的数字字符
+	units         [4]string  // 个、十、百、千位单位
+	tenThousand   string     // 万
+	zero          string     // 中间的零（为空时省略）
+	omitOne       bool       // 十、百、千前的"一"是否总是省略（日文、韩文）
+	omitLeadOne   bool       // 首位为十时是否省略"一"（中文：十一 而非 一十一）
+	digitsOnlyMin int        // 大于等于该值时逐位书写（0表示不逐位书写）
+}
+
+var (
+	chineseCountingStyle = cjkNumberStyle{
+		digits:        [10]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"},
+		units:         [4]string{"", "十", "百", "千"},
+		tenThousand:   "万",
+		zero:          "零",
+		omitLeadOne:   true,
+		digitsOnlyMin: 100,
+	}
+	chineseCountingThousandStyle = cjkNumberStyle{
+		digits:      [10]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"},
+		units:       [4]string{"", "十", "百", "千"},
+		tenThousand: "万",
+		zero:        "零",
+		omitLeadOne: true,
+	}
+	chineseLegalSimplifiedStyle = cjkNumberStyle{
+		digits:      [10]string{"零", "壹", "贰", "叁", "肆", "伍", "陆", "柒", "捌", "玖"},
+		units:       [4]string{"", "拾", "佰", "仟"},
+		tenThousand: "万",
+		zero:        "零",
+	}
+	japaneseCountingStyle = cjkNumberStyle{
+		digits:      [10]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"},
+		units:       [4]string{"", "十", "百", "千"},
+		tenThousand: "万",
+		omitOne:     true,
+	}
+	koreanDigitalStyle = cjkNumberStyle{
+		digits:      [10]string{"영", "일", "이", "삼", "사", "오", "육", "칠", "팔", "구"},
+		units:       [4]string{"", "십", "백", "천"},
+		tenThousand: "만",
+		omitOne:     true,
+	}
+)
+
+// ideographTraditional 天干
+var ideographTraditional = []string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+
+// FormatNumber 按照OpenXML编号格式将数字渲染为文本
+//
+// 渲染结果与Word显示的编号一致，用于在Markdown、纯文本等不经过Word排版的
+// 导出场景中计算列表编号、脚注编号和页码。未识别的格式按阿拉伯数字输出。
+//
+// 示例:
+//
+//	document.FormatNumber(12, document.NumberFormatChineseCountingThousand) // "十二"
+//	document.FormatNumber(3, document.NumberFormatDecimalEnclosedCircle)    // "③"
+//	document.FormatNumber(22, document.NumberFormatOrdinal)                 // "22nd"
+func FormatNumber(value int, format NumberFormat) string {
+	switch format {
+	case NumberFormatNone, NumberFormatBullet:
+		return ""
+	case NumberFormatUpperLetter:
+		return formatLetter(value, 'A')
+	case NumberFormatLowerLetter:
+		return formatLetter(value, 'a')
+	case NumberFormatUpperRoman:
+		return toRomanUpper(value)
+	case NumberFormatLowerRoman:
+		return toRomanLower(value)
+	case NumberFormatChineseCounting:
+		return formatCJKNumber(value, &chineseCountingStyle)
+	case NumberFormatChineseCountingThousand:
+		return formatCJKNumber(value, &chineseCountingThousandStyle)
+	case NumberFormatChineseLegalSimplified:
+		return formatCJKNumber(value, &chineseLegalSimplifiedStyle)
+	case NumberFormatJapaneseCounting:
+		return formatCJKNumber(value, &japaneseCountingStyle)
+	case NumberFormatKoreanDigital:
+		return formatCJKNumber(value, &koreanDigitalStyle)
+	case NumberFormatIdeographTraditional:
+		if value <= 0 {
+			return strconv.Itoa(value)
+		}
+		return ideographTraditional[(value-1)%len(ideographTraditional)]
+	case NumberFormatDecimalEnclosedCircle:
+		return formatEnclosedCircle(value)
+	case NumberFormatDecimalFullWidth:
+		return formatFullWidth(value)
+	case NumberFormatOrdinal:
+		return formatOrdinal(value)
+	case NumberFormatCardinalText:
+		return formatCardinalText(value)
+	case "decimalZero":
+		if value >= 0 && value < 10 {
+			return "0" + strconv.Itoa(value)
+		}
+		return strconv.Itoa(value)
+	default:
+		return strconv.Itoa(value)
+	}
+}
+
+// formatNumber 按 w:numFmt 属性值渲染数字
+func formatNumber(value int, numFmt string) string {
+	return FormatNumber(value, NumberFormat(numFmt))
+}
+
+// formatLetter 字母编号：A..Z 之后为 AA..ZZ、AAA..ZZZ（与Word一致）
+func formatLetter(value int, base rune) string {
+	if value <= 0 {
+		return strconv.Itoa(value)
+	}
+	letter := string(base + rune((value-1)%26))
+	return strings.Repeat(letter, (value-1)/26+1)
+}
+
+// formatCJKNumber 按中日韩数字规则书写（支持到 99999999）
+func formatCJKNumber(value int, style *cjkNumberStyle) string {
+	if value <= 0 || value > 99999999 {
+		return strconv.Itoa(value)
+	}
+
+	// 逐位书写，如 chineseCounting 的 一〇一
+	if style.digitsOnlyMin > 0 && value >= style.digitsOnlyMin {
+		var builder strings.Builder
+		for _, digit := range strconv.Itoa(value) {
+			builder.WriteString(style.digits[digit-'0'])
+		}
+		return builder.String()
+	}
+
+	high, low := value/10000, value%10000
+	if high == 0 {
+		return formatCJKGroup(low, style, true)
+	}
+
+	result := formatCJKGroup(high, style, true) + style.tenThousand
+	if low > 0 {
+		if low < 1000 && style.zero != "" {
+			result += style.zero
+		}
+		result += formatCJKGroup(low, style, false)
+	}
+	return result
+}
+
+// formatCJKGroup 书写 1-9999 范围内的数字
+func formatCJKGroup(value int, style *cjkNumberStyle, leading bool) string {
+	var builder strings.Builder
+	pendingZero := false
+	written := false
+
+	for position := 3; position >= 0; position-- {
+		divisor := 1
+		for i := 0; i < position; i++ {
+			divisor *= 10
+		}
+		digit := value / divisor % 10
+
+		if digit == 0 {
+			if written {
+				pendingZero = true
+			}
+			continue
+		}
+
+		if pendingZero && style.zero != "" {
+			builder.WriteString(style.zero)
+		}
+		pendingZero = false
+
+		omitDigit := digit == 1 && position > 0 &&
+			(style.omitOne || (style.omitLeadOne && leading && !written && position == 1))
+		if !omitDigit {
+			builder.WriteString(style.digits[digit])
+		}
+		builder.WriteString(style.units[position])
+		written = true
+	}
+	return builder.String()
+}
+
+// formatEnclosedCircle 带圈数字：①-⑳、㉑-㉟、㊱-㊿，超出范围输出阿拉伯数字
+func formatEnclosedCircle(value int) string {
+	switch {
+	case value >= 1 && value <= 20:
+		return string(rune(0x2460 + value - 1))
+	case value >= 21 && value <= 35:
+		return string(rune(0x3251 + value - 21))
+	case value >= 36 && value <= 50:
+		return string(rune(0x32B1 + value - 36))
+	default:
+		return strconv.Itoa(value)
+	}
+}
+
+// formatFullWidth 全角阿拉伯数字
+func formatFullWidth(value int) string {
+	var builder strings.Builder
+	for _, char := range strconv.Itoa(value) {
+		if char >= '0' && char <= '9' {
+			builder.WriteRune(0xFF10 + char - '0')
+		} else {
+			builder.WriteRune(char)
+		}
+	}
+	return builder.String()
+}
+
+// formatOrdinal 英文序数：1st、2nd、3rd、4th、11th、21st
+func formatOrdinal(value int) string {
+	suffix := "th"
+	if value%100 < 11 || value%100 > 13 {
+		switch value % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(value) + suffix
+}
+
+var (
+	cardinalOnes = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	cardinalTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+// formatCardinalText 英文基数词（首字母大写）：One、Twenty-One、One Hundred Five
+func formatCardinalText(value int) string {
+	if value < 0 || value > 999999 {
+		return strconv.Itoa(value)
+	}
+	words := cardinalWords(value)
+	for i, word := range words {
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			parts[j] = strings.ToUpper(part[:1]) + part[1:]
+		}
+		words[i] = strings.Join(parts, "-")
+	}
+	return strings.Join(words, " ")
+}
+
+// cardinalWords 将数字拆分为英文单词
+func cardinalWords(value int) []string {
+	switch {
+	case value < 20:
+		return []string{cardinalOnes[value]}
+	case value < 100:
+		word := cardinalTens[value/10]
+		if value%10 > 0 {
+			word += "-" + cardinalOnes[value%10]
+		}
+		return []string{word}
+	case value < 1000:
+		words := []string{cardinalOnes[value/100], "hundred"}
+		if value%100 > 0 {
+			words = append(words, cardinalWords(value%100)...)
+		}
+		return words
+	default:
+		words := append(cardinalWords(value/1000), "thousand")
+		if value%1000 > 0 {
+			words = append(words, cardinalWords(value%1000)...)
+		}
+		return words
+	}
+}
+
+// formatLevelText 使用各级当前计数渲染级别文本（w:lvlText），如 "%1.%2" -> "2.1"
+//
+// 参数 counters 为从第1级开始的各级当前值，levels 为对应的级别定义，
+// isLgl 为真时所有占位符均以阿拉伯数字显示。
+func formatLevelText(levelText string, counters []int, levels []*Level, isLgl bool) string {
+	var builder strings.Builder
+	runes := []rune(levelText)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '%' && i+1 < len(runes) && runes[i+1] >= '1' && runes[i+1] <= '9' {
+			index := int(runes[i+1] - '1')
+			i++
+			if index >= len(counters) {
+				continue
+			}
+			numFmt := "decimal"
+			if !isLgl && index < len(levels) && levels[index] != nil && levels[index].NumFmt != nil {
+				numFmt = levels[index].NumFmt.Val
+			}
+			builder.WriteString(formatNumber(counters[index], numFmt))
+			continue
+		}
+		builder.WriteRune(runes[i])
+	}
+	return builder.String()
+}
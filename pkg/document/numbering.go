@@ -2,8 +2,10 @@
 package document
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"sort"
 	"strconv"
 )
 
@@ -25,6 +27,26 @@ const (
 	ListTypeLowerRoman ListType = "lowerRoman"
 	// ListTypeUpperRoman 大写罗马数字
 	ListTypeUpperRoman ListType = "upperRoman"
+	// ListTypeChineseCounting 中文计数（一、二、三）
+	ListTypeChineseCounting ListType = "chineseCounting"
+	// ListTypeChineseCountingThousand 中文计数（含百千万，如 一百零一）
+	ListTypeChineseCountingThousand ListType = "chineseCountingThousand"
+	// ListTypeChineseLegalSimplified 中文大写数字（壹、贰、叁）
+	ListTypeChineseLegalSimplified ListType = "chineseLegalSimplified"
+	// ListTypeIdeographTraditional 天干（甲、乙、丙）
+	ListTypeIdeographTraditional ListType = "ideographTraditional"
+	// ListTypeDecimalEnclosedCircle 带圈数字（①、②、③）
+	ListTypeDecimalEnclosedCircle ListType = "decimalEnclosedCircle"
+	// ListTypeDecimalFullWidth 全角数字（１、２、３）
+	ListTypeDecimalFullWidth ListType = "decimalFullWidth"
+	// ListTypeJapaneseCounting 日文计数
+	ListTypeJapaneseCounting ListType = "japaneseCounting"
+	// ListTypeKoreanDigital 韩文数字（일、이、삼）
+	ListTypeKoreanDigital ListType = "koreanDigital"
+	// ListTypeOrdinal 英文序数（1st、2nd、3rd）
+	ListTypeOrdinal ListType = "ordinal"
+	// ListTypeCardinalText 英文基数词（One、Two、Three）
+	ListTypeCardinalText ListType = "cardinalText"
 )
 
 // BulletType 项目符号类型
@@ -49,20 +71,58 @@ type Numbering struct {
 	Xmlns              string         `xml:"xmlns:w,attr"`
 	AbstractNums       []*AbstractNum `xml:"w:abstractNum"`
 	NumberingInstances []*NumInstance `xml:"w:num"`
+
+	// 解析已有文档时保留的原始XML，写回时原样输出
+	start  []byte // 根元素开始标签（含命名空间声明）
+	before []byte // 抽象编号之前的元素，如图片项目符号 w:numPicBullet
+	after  []byte // 编号实例之后的元素，如 w:numIdMacAtCleanup
 }
 
 // AbstractNum 抽象编号定义
+// 注意：字段顺序必须符合OpenXML标准（CT_AbstractNum的子元素顺序）
 type AbstractNum struct {
-	XMLName       xml.Name `xml:"w:abstractNum"`
-	AbstractNumID string   `xml:"w:abstractNumId,attr"`
-	Levels        []*Level `xml:"w:lvl"`
+	XMLName        xml.Name        `xml:"w:abstractNum"`
+	AbstractNumID  string          `xml:"w:abstractNumId,attr"`
+	Nsid           *NumberingValue `xml:"w:nsid,omitempty"`
+	MultiLevelType *MultiLevelType `xml:"w:multiLevelType,omitempty"`
+	Tmpl           *NumberingValue `xml:"w:tmpl,omitempty"`
+	Name           *NumberingValue `xml:"w:name,omitempty"`
+	StyleLink      *NumberingValue `xml:"w:styleLink,omitempty"`
+	NumStyleLink   *NumberingValue `xml:"w:numStyleLink,omitempty"`
+	Levels         []*Level        `xml:"w:lvl"`
+
+	raw    []byte // 解析时的原始XML
+	parsed []byte // 解析后的序列化结果，写回时与之相同说明未修改，输出原始XML
+}
+
+// NumberingValue 编号定义中仅包含 w:val 属性的简单元素（如 w:nsid、w:tmpl、w:lvlRestart）
+type NumberingValue struct {
+	Val string `xml:"w:val,attr"`
+}
+
+// MultiLevelType 多级列表类型（singleLevel、multilevel、hybridMultilevel）
+type MultiLevelType struct {
+	XMLName xml.Name `xml:"w:multiLevelType"`
+	Val     string   `xml:"w:val,attr"`
 }
 
 // NumInstance 编号实例
 type NumInstance struct {
-	XMLName       xml.Name              `xml:"w:num"`
-	NumID         string                `xml:"w:numId,attr"`
-	AbstractNumID *AbstractNumReference `xml:"w:abstractNumId"`
+	XMLName        xml.Name              `xml:"w:num"`
+	NumID          string                `xml:"w:numId,attr"`
+	AbstractNumID  *AbstractNumReference `xml:"w:abstractNumId"`
+	LevelOverrides []*LevelOverride      `xml:"w:lvlOverride,omitempty"`
+
+	raw    []byte // 解析时的原始XML
+	parsed []byte // 解析后的序列化结果，写回时与之相同说明未修改，输出原始XML
+}
+
+// LevelOverride 编号实例的级别覆盖（重设起始编号或替换级别定义）
+type LevelOverride struct {
+	XMLName       xml.Name        `xml:"w:lvlOverride"`
+	ILevel        string          `xml:"w:ilvl,attr"`
+	StartOverride *NumberingValue `xml:"w:startOverride,omitempty"`
+	Level         *Level          `xml:"w:lvl,omitempty"`
 }
 
 // AbstractNumReference 抽象编号引用
@@ -72,15 +132,22 @@ type AbstractNumReference struct {
 }
 
 // Level 编号级别
+// 注意：字段顺序必须符合OpenXML标准（CT_Lvl的子元素顺序）
 type Level struct {
-	XMLName   xml.Name   `xml:"w:lvl"`
-	ILevel    string     `xml:"w:ilvl,attr"`
-	Start     *Start     `xml:"w:start,omitempty"`
-	NumFmt    *NumFmt    `xml:"w:numFmt,omitempty"`
-	LevelText *LevelText `xml:"w:lvlText,omitempty"`
-	LevelJc   *LevelJc   `xml:"w:lvlJc,omitempty"`
-	PPr       *LevelPPr  `xml:"w:pPr,omitempty"`
-	RPr       *LevelRPr  `xml:"w:rPr,omitempty"`
+	XMLName    xml.Name        `xml:"w:lvl"`
+	ILevel     string          `xml:"w:ilvl,attr"`
+	Tplc       string          `xml:"w:tplc,attr,omitempty"`
+	Tentative  string          `xml:"w:tentative,attr,omitempty"`
+	Start      *Start          `xml:"w:start,omitempty"`
+	NumFmt     *NumFmt         `xml:"w:numFmt,omitempty"`
+	LvlRestart *NumberingValue `xml:"w:lvlRestart,omitempty"`
+	PStyle     *LevelPStyle    `xml:"w:pStyle,omitempty"`
+	IsLgl      *LevelIsLgl     `xml:"w:isLgl,omitempty"`
+	Suffix     *LevelSuffix    `xml:"w:suff,omitempty"`
+	LevelText  *LevelText      `xml:"w:lvlText,omitempty"`
+	LevelJc    *LevelJc        `xml:"w:lvlJc,omitempty"`
+	PPr        *LevelPPr       `xml:"w:pPr,omitempty"`
+	RPr        *LevelRPr       `xml:"w:rPr,omitempty"`
 }
 
 // Start 起始编号
@@ -95,6 +162,23 @@ type NumFmt struct {
 	Val     string   `xml:"w:val,attr"`
 }
 
+// LevelPStyle 级别关联的段落样式（如标题样式）
+type LevelPStyle struct {
+	XMLName xml.Name `xml:"w:pStyle"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LevelIsLgl 法律编号格式（所有上级编号均以阿拉伯数字显示）
+type LevelIsLgl struct {
+	XMLName xml.Name `xml:"w:isLgl"`
+}
+
+// LevelSuffix 编号后缀（tab、space、nothing）
+type LevelSuffix struct {
+	XMLName xml.Name `xml:"w:suff"`
+	Val     string   `xml:"w:val,attr"`
+}
+
 // LevelText 级别文本
 type LevelText struct {
 	XMLName xml.Name `xml:"w:lvlText"`
@@ -115,9 +199,10 @@ type LevelPPr struct {
 
 // LevelIndent 级别缩进
 type LevelIndent struct {
-	XMLName xml.Name `xml:"w:ind"`
-	Left    string   `xml:"w:left,attr,omitempty"`
-	Hanging string   `xml:"w:hanging,attr,omitempty"`
+	XMLName   xml.Name `xml:"w:ind"`
+	Left      string   `xml:"w:left,attr,omitempty"`
+	Hanging   string   `xml:"w:hanging,attr,omitempty"`
+	FirstLine string   `xml:"w:firstLine,attr,omitempty"`
 }
 
 // LevelRPr 级别文本属性
@@ -134,28 +219,35 @@ type ListConfig struct {
 	IndentLevel  int        // 缩进级别（0-8）
 }
 
-// 全局编号管理器
-var globalNumberingManager *NumberingManager
-
-// NumberingManager 编号管理器
+// NumberingManager 编号管理器（每个文档独立维护）
 type NumberingManager struct {
 	nextAbstractNumID int
 	nextNumID         int
 	abstractNums      map[string]*AbstractNum
 	numInstances      map[string]*NumInstance
+
+	// 已有 numbering.xml 中模型未覆盖的部分，参见 Numbering
+	start  []byte
+	before []byte
+	after  []byte
 }
 
-// getNumberingManager 获取全局编号管理器
-func getNumberingManager() *NumberingManager {
-	if globalNumberingManager == nil {
-		globalNumberingManager = &NumberingManager{
-			nextAbstractNumID: 0,
-			nextNumID:         1,
-			abstractNums:      make(map[string]*AbstractNum),
-			numInstances:      make(map[string]*NumInstance),
-		}
+// newNumberingManager 创建空的编号管理器
+func newNumberingManager() *NumberingManager {
+	return &NumberingManager{
+		nextAbstractNumID: 0,
+		nextNumID:         1,
+		abstractNums:      make(map[string]*AbstractNum),
+		numInstances:      make(map[string]*NumInstance),
 	}
-	return globalNumberingManager
+}
+
+// getNumberingManager 获取文档的编号管理器
+func (d *Document) getNumberingManager() *NumberingManager {
+	if d.numberingManager == nil {
+		d.numberingManager = newNumberingManager()
+	}
+	return d.numberingManager
 }
 
 // AddListItem 添加列表项
@@ -195,7 +287,7 @@ func (d *Document) AddListItem(text string, config *ListConfig) *Paragraph {
 	}
 
 	// 添加到文档
-	d.Body.Elements = append(d.Body.Elements, paragraph)
+	d.Body.Elements = append(d.Body.Elements, d.bindParagraph(paragraph))
 	return paragraph
 }
 
@@ -277,7 +369,7 @@ func (d *Document) initializeNumbering() {
 
 // getOrCreateNumbering 获取或创建编号定义
 func (d *Document) getOrCreateNumbering(config *ListConfig) string {
-	manager := getNumberingManager()
+	manager := d.getNumberingManager()
 
 	// 生成抽象编号键
 	abstractKey := fmt.Sprintf("%s_%s_%d", config.Type, config.BulletSymbol, config.IndentLevel)
@@ -366,6 +458,21 @@ func (d *Document) createLevel(levelIndex int, config *ListConfig) *Level {
 	case ListTypeUpperRoman:
 		level.NumFmt = &NumFmt{Val: "upperRoman"}
 		level.LevelText = &LevelText{Val: fmt.Sprintf("%%%d.", levelIndex+1)}
+	case ListTypeChineseCounting, ListTypeChineseCountingThousand, ListTypeChineseLegalSimplified,
+		ListTypeIdeographTraditional, ListTypeJapaneseCounting:
+		// 中文习惯使用顿号：一、二、
+		level.NumFmt = &NumFmt{Val: string(config.Type)}
+		level.LevelText = &LevelText{Val: fmt.Sprintf("%%%d、", levelIndex+1)}
+	case ListTypeDecimalEnclosedCircle:
+		// 带圈数字本身已可区分，不再附加标点
+		level.NumFmt = &NumFmt{Val: string(config.Type)}
+		level.LevelText = &LevelText{Val: fmt.Sprintf("%%%d", levelIndex+1)}
+	case ListTypeDecimalFullWidth:
+		level.NumFmt = &NumFmt{Val: string(config.Type)}
+		level.LevelText = &LevelText{Val: fmt.Sprintf("%%%d．", levelIndex+1)}
+	case ListTypeKoreanDigital, ListTypeOrdinal, ListTypeCardinalText:
+		level.NumFmt = &NumFmt{Val: string(config.Type)}
+		level.LevelText = &LevelText{Val: fmt.Sprintf("%%%d.", levelIndex+1)}
 	}
 
 	return level
@@ -373,8 +480,64 @@ func (d *Document) createLevel(levelIndex int, config *ListConfig) *Level {
 
 // updateNumberingFile 更新编号定义文件
 func (d *Document) updateNumberingFile() {
-	manager := getNumberingManager()
+	data, err := d.getNumberingManager().marshal()
+	if err != nil {
+		Warnf("序列化编号定义失败: %v", err)
+		return
+	}
+	d.parts["word/numbering.xml"] = data
+}
+
+// marshal 序列化编号定义
+//
+// 打开的文档中未修改的抽象编号和编号实例原样写回，模型未覆盖的内容（图片项目符号、
+// w:legacy、级别的制表位和字体以外的文本属性等）不会丢失；只有修改过或新增的定义重新生成。
+func (m *NumberingManager) marshal() ([]byte, error) {
+	numbering := m.buildNumbering()
+
+	// 原始XML可能使用根元素声明的其他命名空间，只在保留原根元素时使用
+	keepRaw := m.start != nil
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	if keepRaw {
+		buf.Write(m.start)
+		buf.Write(m.before)
+	} else {
+		buf.WriteString(`<w:numbering xmlns:w="` + numbering.Xmlns + `">`)
+	}
+	for _, abstractNum := range numbering.AbstractNums {
+		if err := writeNumberingElement(&buf, abstractNum, abstractNum.raw, abstractNum.parsed, keepRaw); err != nil {
+			return nil, err
+		}
+	}
+	for _, instance := range numbering.NumberingInstances {
+		if err := writeNumberingElement(&buf, instance, instance.raw, instance.parsed, keepRaw); err != nil {
+			return nil, err
+		}
+	}
+	if keepRaw {
+		buf.Write(m.after)
+	}
+	buf.WriteString("</w:numbering>")
+	return buf.Bytes(), nil
+}
+
+// writeNumberingElement 写入编号定义元素，序列化结果与解析时相同时写入原始XML
+func writeNumberingElement(buf *bytes.Buffer, element interface{}, raw, parsed []byte, keepRaw bool) error {
+	data, err := xml.Marshal(element)
+	if err != nil {
+		return err
+	}
+	if keepRaw && raw != nil && bytes.Equal(data, parsed) {
+		data = raw
+	}
+	buf.WriteByte('\n')
+	buf.Write(data)
+	return nil
+}
 
+// buildNumbering 按ID顺序构建编号定义
+func (m *NumberingManager) buildNumbering() *Numbering {
 	numbering := &Numbering{
 		Xmlns:              "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
 		AbstractNums:       []*AbstractNum{},
@@ -382,24 +545,32 @@ func (d *Document) updateNumberingFile() {
 	}
 
 	// 添加所有抽象编号
-	for _, abstractNum := range manager.abstractNums {
+	for _, abstractNum := range m.abstractNums {
 		numbering.AbstractNums = append(numbering.AbstractNums, abstractNum)
 	}
+	sort.Slice(numbering.AbstractNums, func(i, j int) bool {
+		return compareNumericID(numbering.AbstractNums[i].AbstractNumID, numbering.AbstractNums[j].AbstractNumID)
+	})
 
 	// 添加所有编号实例
-	for _, numInstance := range manager.numInstances {
+	for _, numInstance := range m.numInstances {
 		numbering.NumberingInstances = append(numbering.NumberingInstances, numInstance)
 	}
+	sort.Slice(numbering.NumberingInstances, func(i, j int) bool {
+		return compareNumericID(numbering.NumberingInstances[i].NumID, numbering.NumberingInstances[j].NumID)
+	})
 
-	// 序列化
-	numberingXML, err := xml.MarshalIndent(numbering, "", "  ")
-	if err != nil {
-		return
-	}
+	return numbering
+}
 
-	// 添加XML声明
-	xmlDeclaration := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
-	d.parts["word/numbering.xml"] = append(xmlDeclaration, numberingXML...)
+// compareNumericID 按数值比较编号ID，非数字ID按字符串比较
+func compareNumericID(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return x < y
 }
 
 // addNumberingRelationship 添加编号关系
@@ -420,7 +591,7 @@ func (d *Document) addNumberingRelationship() {
 func (d *Document) RestartNumbering(numID string) {
 	// 重置编号计数器
 	// 在实际实现中，需要创建新的编号实例来重置计数
-	manager := getNumberingManager()
+	manager := d.getNumberingManager()
 
 	// 创建新的编号实例
 	newNumID := strconv.Itoa(manager.nextNumID)
@@ -57,19 +57,46 @@ var (
 )
 
 // SectionProperties 节属性，包含页面设置信息
+// 注意：字段顺序必须符合OpenXML标准（CT_SectPr的子元素顺序）
 type SectionProperties struct {
 	XMLName          xml.Name                 `xml:"w:sectPr"`
 	XmlnsR           string                   `xml:"xmlns:r,attr,omitempty"`
+	HeaderReferences []*HeaderFooterReference `xml:"w:headerReference,omitempty"`
+	FooterReferences []*FooterReference       `xml:"w:footerReference,omitempty"`
+	Type             *SectionType             `xml:"w:type,omitempty"`
 	PageSize         *PageSizeXML             `xml:"w:pgSz,omitempty"`
 	PageMargins      *PageMargin              `xml:"w:pgMar,omitempty"`
+	PageBorders      *PageBorders             `xml:"w:pgBorders,omitempty"`
+	LineNumType      *LineNumType             `xml:"w:lnNumType,omitempty"`
+	PageNumType      *PageNumType             `xml:"w:pgNumType,omitempty"`
 	Columns          *Columns                 `xml:"w:cols,omitempty"`
-	HeaderReferences []*HeaderFooterReference `xml:"w:headerReference,omitempty"`
-	FooterReferences []*FooterReference       `xml:"w:footerReference,omitempty"`
+	VerticalAlign    *SectionVerticalAlign    `xml:"w:vAlign,omitempty"`
 	TitlePage        *TitlePage               `xml:"w:titlePg,omitempty"`
-	PageNumType      *PageNumType             `xml:"w:pgNumType,omitempty"`
 	DocGrid          *DocGrid                 `xml:"w:docGrid,omitempty"`
 }
 
+// SectionBreakType 分节符类型
+type SectionBreakType string
+
+const (
+	// SectionBreakNextPage 下一页开始新节
+	SectionBreakNextPage SectionBreakType = "nextPage"
+	// SectionBreakContinuous 连续分节（同一页开始新节）
+	SectionBreakContinuous SectionBreakType = "continuous"
+	// SectionBreakEvenPage 偶数页开始新节
+	SectionBreakEvenPage SectionBreakType = "evenPage"
+	// SectionBreakOddPage 奇数页开始新节
+	SectionBreakOddPage SectionBreakType = "oddPage"
+	// SectionBreakNextColumn 下一栏开始新节
+	SectionBreakNextColumn SectionBreakType = "nextColumn"
+)
+
+// SectionType 节的起始类型
+type SectionType struct {
+	XMLName xml.Name `xml:"w:type"`
+	Val     string   `xml:"w:val,attr"`
+}
+
 // PageSizeXML 页面尺寸XML结构
 type PageSizeXML struct {
 	XMLName xml.Name `xml:"w:pgSz"`
@@ -92,15 +119,19 @@ type PageMargin struct {
 
 // Columns 分栏设置
 type Columns struct {
-	XMLName xml.Name `xml:"w:cols"`
-	Space   string   `xml:"w:space,attr,omitempty"` // 栏间距
-	Num     string   `xml:"w:num,attr,omitempty"`   // 栏数
+	XMLName    xml.Name  `xml:"w:cols"`
+	EqualWidth string    `xml:"w:equalWidth,attr,omitempty"` // 是否等宽（"0"表示不等宽，使用 w:col 指定各栏宽度）
+	Space      string    `xml:"w:space,attr,omitempty"`      // 栏间距
+	Num        string    `xml:"w:num,attr,omitempty"`        // 栏数
+	Sep        string    `xml:"w:sep,attr,omitempty"`        // 栏间分隔线
+	Cols       []*Column `xml:"w:col,omitempty"`             // 各栏宽度和间距（不等宽分栏）
 }
 
 // PageNumType 页码类型
 type PageNumType struct {
 	XMLName xml.Name `xml:"w:pgNumType"`
 	Fmt     string   `xml:"w:fmt,attr,omitempty"`
+	Start   string   `xml:"w:start,attr,omitempty"`
 }
 
 // PageSettings 页面设置配置
@@ -126,6 +157,18 @@ type PageSettings struct {
 	DocGridType      DocGridType // 文档网格类型
 	DocGridLinePitch int         // 行网格间距（1/20磅）
 	DocGridCharSpace int         // 字符间距
+	// 以下版式设置为nil或空时 SetPageSettings 保持文档原有设置不变，
+	// 清除时使用 SetPageBorders(nil)、SetLineNumbering(nil) 等专用方法
+	// 页面边框
+	PageBorders *PageBorderConfig
+	// 行号
+	LineNumbering *LineNumberingConfig
+	// 页面垂直对齐方式
+	VerticalAlign PageVerticalAlign
+	// 页面背景颜色（十六进制，如 "FFF2CC"）
+	BackgroundColor string
+	// 分栏
+	Columns *ColumnConfig
 }
 
 // 预定义页面尺寸（毫米）
@@ -203,6 +246,23 @@ func (d *Document) SetPageSettings(settings *PageSettings) error {
 		}
 	}
 
+	// 设置页面边框、行号、分栏、垂直对齐和背景，未设置的项保持不变
+	if settings.PageBorders != nil {
+		sectPr.PageBorders = pageBordersToXML(settings.PageBorders)
+	}
+	if settings.Columns != nil {
+		sectPr.Columns = columnsToXML(settings.Columns)
+	}
+	if settings.LineNumbering != nil {
+		sectPr.LineNumType = lineNumberingToXML(settings.LineNumbering)
+	}
+	if settings.VerticalAlign != "" {
+		sectPr.VerticalAlign = verticalAlignToXML(settings.VerticalAlign)
+	}
+	if settings.BackgroundColor != "" {
+		d.applyBackgroundColor(settings.BackgroundColor)
+	}
+
 	Infof("页面设置已更新: 尺寸=%s, 方向=%s", settings.Size, settings.Orientation)
 	return nil
 }
@@ -258,6 +318,15 @@ func (d *Document) GetPageSettings() *PageSettings {
 		}
 	}
 
+	// 解析页面边框、行号、分栏和垂直对齐
+	settings.PageBorders = pageBordersFromXML(sectPr.PageBorders)
+	settings.Columns = columnsFromXML(sectPr.Columns)
+	settings.LineNumbering = lineNumberingFromXML(sectPr.LineNumType)
+	if sectPr.VerticalAlign != nil {
+		settings.VerticalAlign = PageVerticalAlign(sectPr.VerticalAlign.Val)
+	}
+	settings.BackgroundColor = d.GetBackgroundColor()
+
 	return settings
 }
 
@@ -302,6 +371,35 @@ func (d *Document) SetPageMargins(top, right, bottom, left float64) error {
 	return d.SetPageSettings(settings)
 }
 
+// SetPageNumberFormat 设置页码编号格式及起始页码
+//
+// format 可使用任意 NumberFormat，如 NumberFormatChineseCounting（一、二、三）
+// 或 NumberFormatDecimalFullWidth（全角数字），页眉页脚中的PAGE域将按该格式显示。
+// start 小于等于0时表示从上一节继续编号。
+func (d *Document) SetPageNumberFormat(format NumberFormat, start int) error {
+	if format == "" || format == NumberFormatBullet {
+		return WrapError("SetPageNumberFormat", NewValidationError("format", string(format), "不支持的页码格式"))
+	}
+
+	sectPr := d.getSectionProperties()
+	sectPr.PageNumType = &PageNumType{Fmt: string(format)}
+	if start > 0 {
+		sectPr.PageNumType.Start = strconv.Itoa(start)
+	}
+
+	Infof("页码格式已设置: %s, 起始页码=%d", format, start)
+	return nil
+}
+
+// GetPageNumberLabel 按当前节的页码格式渲染指定页码的显示文本
+func (d *Document) GetPageNumberLabel(page int) string {
+	format := NumberFormatDecimal
+	if sectPr := d.getSectionProperties(); sectPr.PageNumType != nil && sectPr.PageNumType.Fmt != "" {
+		format = NumberFormat(sectPr.PageNumType.Fmt)
+	}
+	return FormatNumber(page, format)
+}
+
 // SetHeaderFooterDistance 设置页眉页脚距离（毫米）
 func (d *Document) SetHeaderFooterDistance(header, footer float64) error {
 	if header < 0 || footer < 0 {
@@ -366,6 +464,126 @@ func (d *Document) setSectionProperties(sectPr *SectionProperties) {
 	d.Body.Elements = append(d.Body.Elements, sectPr)
 }
 
+// cloneSectionProperties 深度复制节属性
+func cloneSectionProperties(source *SectionProperties) *SectionProperties {
+	if source == nil {
+		return nil
+	}
+
+	sectPr := &SectionProperties{
+		XmlnsR: source.XmlnsR,
+	}
+
+	// 复制节类型
+	if source.Type != nil {
+		sectPr.Type = &SectionType{Val: source.Type.Val}
+	}
+
+	// 复制页面尺寸
+	if source.PageSize != nil {
+		sectPr.PageSize = &PageSizeXML{
+			W:      source.PageSize.W,
+			H:      source.PageSize.H,
+			Orient: source.PageSize.Orient,
+		}
+	}
+
+	// 复制页面边距
+	if source.PageMargins != nil {
+		sectPr.PageMargins = &PageMargin{
+			Top:    source.PageMargins.Top,
+			Right:  source.PageMargins.Right,
+			Bottom: source.PageMargins.Bottom,
+			Left:   source.PageMargins.Left,
+			Header: source.PageMargins.Header,
+			Footer: source.PageMargins.Footer,
+			Gutter: source.PageMargins.Gutter,
+		}
+	}
+
+	// 复制分栏设置
+	if source.Columns != nil {
+		sectPr.Columns = &Columns{
+			EqualWidth: source.Columns.EqualWidth,
+			Space:      source.Columns.Space,
+			Num:        source.Columns.Num,
+			Sep:        source.Columns.Sep,
+		}
+		for _, column := range source.Columns.Cols {
+			sectPr.Columns.Cols = append(sectPr.Columns.Cols, &Column{W: column.W, Space: column.Space})
+		}
+	}
+
+	// 复制页眉引用
+	if source.HeaderReferences != nil {
+		sectPr.HeaderReferences = make([]*HeaderFooterReference, len(source.HeaderReferences))
+		for i, ref := range source.HeaderReferences {
+			sectPr.HeaderReferences[i] = &HeaderFooterReference{
+				Type: ref.Type,
+				ID:   ref.ID,
+			}
+		}
+	}
+
+	// 复制页脚引用
+	if source.FooterReferences != nil {
+		sectPr.FooterReferences = make([]*FooterReference, len(source.FooterReferences))
+		for i, ref := range source.FooterReferences {
+			sectPr.FooterReferences[i] = &FooterReference{
+				Type: ref.Type,
+				ID:   ref.ID,
+			}
+		}
+	}
+
+	// 复制首页不同设置
+	if source.TitlePage != nil {
+		sectPr.TitlePage = &TitlePage{}
+	}
+
+	// 复制页码类型
+	if source.PageNumType != nil {
+		sectPr.PageNumType = &PageNumType{
+			Fmt:   source.PageNumType.Fmt,
+			Start: source.PageNumType.Start,
+		}
+	}
+
+	// 复制页面边框
+	if source.PageBorders != nil {
+		borders := *source.PageBorders
+		for _, border := range []**PageBorder{&borders.Top, &borders.Left, &borders.Bottom, &borders.Right} {
+			if *border != nil {
+				copied := **border
+				*border = &copied
+			}
+		}
+		sectPr.PageBorders = &borders
+	}
+
+	// 复制行号设置
+	if source.LineNumType != nil {
+		lnNumType := *source.LineNumType
+		sectPr.LineNumType = &lnNumType
+	}
+
+	// 复制垂直对齐方式
+	if source.VerticalAlign != nil {
+		sectPr.VerticalAlign = &SectionVerticalAlign{Val: source.VerticalAlign.Val}
+	}
+
+	// 复制文档网格
+	if source.DocGrid != nil {
+		sectPr.DocGrid = &DocGrid{
+			Type:      source.DocGrid.Type,
+			LinePitch: source.DocGrid.LinePitch,
+			CharSpace: source.DocGrid.CharSpace,
+		}
+	}
+
+	return sectPr
+}
+
 // ElementType 返回节属性元素类型
 func (s *SectionProperties) ElementType() string {
 	return "sectionProperties"
@@ -394,7 +612,7 @@ func validatePageSettings(settings *PageSettings) error {
 		return errors.New("无效的页面方向")
 	}
 
-	return nil
+	return validatePageLayout(settings)
 }
 
 // getPageDimensions 获取页面尺寸（毫米）
@@ -0,0 +1,672 @@
+// Package document 提供页面边框、行号、垂直对齐、页面背景和分栏功能
+package document
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 艺术型页面边框（w:pgBorders 中使用，Size 为图案宽度，单位磅）
+const (
+	// BorderArtApples 苹果
+	BorderArtApples BorderStyle = "apples"
+	// BorderArtBasicBlackDots 黑色圆点
+	BorderArtBasicBlackDots BorderStyle = "basicBlackDots"
+	// BorderArtBasicThinLines 细线
+	BorderArtBasicThinLines BorderStyle = "basicThinLines"
+	// BorderArtCelticKnotwork 凯尔特结
+	BorderArtCelticKnotwork BorderStyle = "celticKnotwork"
+	// BorderArtDoubleDiamonds 双菱形
+	BorderArtDoubleDiamonds BorderStyle = "doubleDiamonds"
+	// BorderArtHearts 心形
+	BorderArtHearts BorderStyle = "hearts"
+	// BorderArtStars 星形
+	BorderArtStars BorderStyle = "stars"
+	// BorderArtTrees 树
+	BorderArtTrees BorderStyle = "trees"
+)
+
+// PageBorderOffset 页面边框的度量基准
+type PageBorderOffset string
+
+const (
+	// PageBorderOffsetText 相对正文（默认）
+	PageBorderOffsetText PageBorderOffset = "text"
+	// PageBorderOffsetPage 相对页面边缘
+	PageBorderOffsetPage PageBorderOffset = "page"
+)
+
+// PageBorderDisplay 页面边框的应用范围
+type PageBorderDisplay string
+
+const (
+	// PageBorderDisplayAllPages 所有页（默认）
+	PageBorderDisplayAllPages PageBorderDisplay = "allPages"
+	// PageBorderDisplayFirstPage 仅首页
+	PageBorderDisplayFirstPage PageBorderDisplay = "firstPage"
+	// PageBorderDisplayNotFirstPage 除首页外所有页
+	PageBorderDisplayNotFirstPage PageBorderDisplay = "notFirstPage"
+)
+
+// LineNumberRestart 行号重新编号方式
+type LineNumberRestart string
+
+const (
+	// LineNumberRestartNewPage 每页重新编号（默认）
+	LineNumberRestartNewPage LineNumberRestart = "newPage"
+	// LineNumberRestartNewSection 每节重新编号
+	LineNumberRestartNewSection LineNumberRestart = "newSection"
+	// LineNumberRestartContinuous 连续编号
+	LineNumberRestartContinuous LineNumberRestart = "continuous"
+)
+
+// PageVerticalAlign 页面垂直对齐方式
+type PageVerticalAlign string
+
+const (
+	// PageVerticalAlignTop 顶端对齐（默认）
+	PageVerticalAlignTop PageVerticalAlign = "top"
+	// PageVerticalAlignCenter 居中
+	PageVerticalAlignCenter PageVerticalAlign = "center"
+	// PageVerticalAlignBoth 两端对齐
+	PageVerticalAlignBoth PageVerticalAlign = "both"
+	// PageVerticalAlignBottom 底端对齐
+	PageVerticalAlignBottom PageVerticalAlign = "bottom"
+)
+
+// PageBorderLine 页面边框线配置
+type PageBorderLine struct {
+	Style BorderStyle // 线型（如 BorderStyleSingle）或艺术型（如 BorderArtStars）
+	Size  int         // 线宽（1/8磅），艺术型边框为图案宽度（磅）
+	Space int         // 与正文或页面边缘的距离（磅）
+	Color string      // 颜色（十六进制），为空时为自动
+}
+
+// PageBorderConfig 页面边框配置
+type PageBorderConfig struct {
+	Top    *PageBorderLine
+	Left   *PageBorderLine
+	Bottom *PageBorderLine
+	Right  *PageBorderLine
+	// OffsetFrom 边距度量基准，为空时相对正文
+	OffsetFrom PageBorderOffset
+	// Display 应用范围，为空时应用于所有页
+	Display PageBorderDisplay
+}
+
+// LineNumberingConfig 行号配置
+type LineNumberingConfig struct {
+	Start    int               // 起始编号（从1开始，0表示默认值1）
+	CountBy  int               // 行号间隔（每隔几行显示，0表示1）
+	Restart  LineNumberRestart // 重新编号方式，为空时每页重新编号
+	Distance float64           // 行号与正文的距离（毫米），0表示自动
+}
+
+// PageBorders 页面边框XML结构
+type PageBorders struct {
+	XMLName    xml.Name    `xml:"w:pgBorders"`
+	ZOrder     string      `xml:"w:zOrder,attr,omitempty"`
+	Display    string      `xml:"w:display,attr,omitempty"`
+	OffsetFrom string      `xml:"w:offsetFrom,attr,omitempty"`
+	Top        *PageBorder `xml:"w:top,omitempty"`
+	Left       *PageBorder `xml:"w:left,omitempty"`
+	Bottom     *PageBorder `xml:"w:bottom,omitempty"`
+	Right      *PageBorder `xml:"w:right,omitempty"`
+}
+
+// PageBorder 页面单边边框XML结构
+type PageBorder struct {
+	Val   string `xml:"w:val,attr"`
+	Sz    string `xml:"w:sz,attr,omitempty"`
+	Space string `xml:"w:space,attr,omitempty"`
+	Color string `xml:"w:color,attr,omitempty"`
+}
+
+// LineNumType 行号XML结构
+type LineNumType struct {
+	XMLName  xml.Name `xml:"w:lnNumType"`
+	CountBy  string   `xml:"w:countBy,attr,omitempty"`
+	Start    string   `xml:"w:start,attr,omitempty"`
+	Distance string   `xml:"w:distance,attr,omitempty"`
+	Restart  string   `xml:"w:restart,attr,omitempty"`
+}
+
+// SectionVerticalAlign 页面垂直对齐XML结构
+type SectionVerticalAlign struct {
+	XMLName xml.Name `xml:"w:vAlign"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// DocumentBackground 文档背景（w:background）
+type DocumentBackground struct {
+	XMLName    xml.Name `xml:"w:background"`
+	Color      string   `xml:"w:color,attr,omitempty"`
+	ThemeColor string   `xml:"w:themeColor,attr,omitempty"`
+	ThemeTint  string   `xml:"w:themeTint,attr,omitempty"`
+	ThemeShade string   `xml:"w:themeShade,attr,omitempty"`
+	// Content 背景填充等子元素（v:background），原样保留
+	Content []byte `xml:",innerxml"`
+}
+
+// SetPageBorders 设置页面边框，config 为nil时清除边框
+//
+// 示例:
+//
+//	line := &document.PageBorderLine{Style: document.BorderStyleDouble, Size: 4, Space: 24, Color: "1F4E79"}
+//	doc.SetPageBorders(&document.PageBorderConfig{
+//		Top: line, Left: line, Bottom: line, Right: line,
+//		OffsetFrom: document.PageBorderOffsetPage,
+//	})
+func (d *Document) SetPageBorders(config *PageBorderConfig) error {
+	if err := validatePageLayout(&PageSettings{PageBorders: config}); err != nil {
+		return WrapError("SetPageBorders", err)
+	}
+	d.getSectionProperties().PageBorders = pageBordersToXML(config)
+	return nil
+}
+
+// SetLineNumbering 设置行号，config 为nil时关闭行号
+func (d *Document) SetLineNumbering(config *LineNumberingConfig) error {
+	if err := validatePageLayout(&PageSettings{LineNumbering: config}); err != nil {
+		return WrapError("SetLineNumbering", err)
+	}
+	d.getSectionProperties().LineNumType = lineNumberingToXML(config)
+	return nil
+}
+
+// SetPageVerticalAlign 设置页面内容的垂直对齐方式，为空时恢复顶端对齐
+func (d *Document) SetPageVerticalAlign(align PageVerticalAlign) error {
+	if err := validatePageLayout(&PageSettings{VerticalAlign: align}); err != nil {
+		return WrapError("SetPageVerticalAlign", err)
+	}
+	d.getSectionProperties().VerticalAlign = verticalAlignToXML(align)
+	return nil
+}
+
+// verticalAlignToXML 将垂直对齐方式转换为XML结构，顶端对齐为默认值，不写入
+func verticalAlignToXML(align PageVerticalAlign) *SectionVerticalAlign {
+	if align == "" || align == PageVerticalAlignTop {
+		return nil
+	}
+	return &SectionVerticalAlign{Val: string(align)}
+}
+
+// SetBackgroundColor 设置页面背景颜色（十六进制，如 "FFF2CC"），为空时清除背景
+//
+// 同时在文档设置中启用 displayBackgroundShape，使Word显示背景颜色。
+func (d *Document) SetBackgroundColor(color string) error {
+	if err := validatePageLayout(&PageSettings{BackgroundColor: color}); err != nil {
+		return WrapError("SetBackgroundColor", err)
+	}
+	d.applyBackgroundColor(color)
+	return nil
+}
+
+// GetBackgroundColor 获取页面背景颜色，无背景时返回空字符串
+func (d *Document) GetBackgroundColor() string {
+	if d.background == nil {
+		return ""
+	}
+	return d.background.Color
+}
+
+// applyBackgroundColor 应用页面背景颜色
+func (d *Document) applyBackgroundColor(color string) {
+	if color == "" {
+		if d.background != nil {
+			d.background = nil
+			d.Settings().DisplayBackgroundShape = false
+		}
+		return
+	}
+
+	color = strings.TrimPrefix(strings.ToUpper(color), "#")
+	if d.background != nil && d.background.Color == color {
+		return
+	}
+	// 修改颜色时清除主题颜色和原有填充，避免覆盖新颜色
+	d.background = &DocumentBackground{Color: color}
+	d.Settings().DisplayBackgroundShape = true
+}
+
+// validatePageLayout 验证页面边框、行号、垂直对齐、分栏和背景设置
+func validatePageLayout(settings *PageSettings) error {
+	if borders := settings.PageBorders; borders != nil {
+		switch borders.OffsetFrom {
+		case "", PageBorderOffsetText, PageBorderOffsetPage:
+		default:
+			return fmt.Errorf("无效的页面边框度量基准: %s", borders.OffsetFrom)
+		}
+		switch borders.Display {
+		case "", PageBorderDisplayAllPages, PageBorderDisplayFirstPage, PageBorderDisplayNotFirstPage:
+		default:
+			return fmt.Errorf("无效的页面边框应用范围: %s", borders.Display)
+		}
+		for _, line := range []*PageBorderLine{borders.Top, borders.Left, borders.Bottom, borders.Right} {
+			if line != nil && (line.Size < 0 || line.Space < 0 || line.Space > 31) {
+				return errors.New("页面边框线宽不能为负数，间距必须在0-31磅之间")
+			}
+		}
+	}
+
+	if numbering := settings.LineNumbering; numbering != nil {
+		if numbering.Start < 0 || numbering.CountBy < 0 || numbering.Distance < 0 {
+			return errors.New("行号起始编号、间隔和距离不能为负数")
+		}
+		switch numbering.Restart {
+		case "", LineNumberRestartNewPage, LineNumberRestartNewSection, LineNumberRestartContinuous:
+		default:
+			return fmt.Errorf("无效的行号重新编号方式: %s", numbering.Restart)
+		}
+	}
+
+	switch settings.VerticalAlign {
+	case "", PageVerticalAlignTop, PageVerticalAlignCenter, PageVerticalAlignBoth, PageVerticalAlignBottom:
+	default:
+		return fmt.Errorf("无效的页面垂直对齐方式: %s", settings.VerticalAlign)
+	}
+
+	if settings.Columns != nil {
+		if err := validateColumns(settings.Columns); err != nil {
+			return err
+		}
+	}
+
+	if color := strings.TrimPrefix(settings.BackgroundColor, "#"); color != "" {
+		if _, err := strconv.ParseUint(color, 16, 32); err != nil || len(color) != 6 {
+			return fmt.Errorf("无效的背景颜色: %s", settings.BackgroundColor)
+		}
+	}
+	return nil
+}
+
+// pageBordersToXML 将页面边框配置转换为XML结构
+func pageBordersToXML(config *PageBorderConfig) *PageBorders {
+	if config == nil {
+		return nil
+	}
+	borders := &PageBorders{
+		Top:    pageBorderToXML(config.Top),
+		Left:   pageBorderToXML(config.Left),
+		Bottom: pageBorderToXML(config.Bottom),
+		Right:  pageBorderToXML(config.Right),
+	}
+	if config.OffsetFrom != "" && config.OffsetFrom != PageBorderOffsetText {
+		borders.OffsetFrom = string(config.OffsetFrom)
+	}
+	if config.Display != "" && config.Display != PageBorderDisplayAllPages {
+		borders.Display = string(config.Display)
+	}
+	return borders
+}
+
+// pageBorderToXML 将单边边框配置转换为XML结构
+func pageBorderToXML(line *PageBorderLine) *PageBorder {
+	if line == nil {
+		return nil
+	}
+	border := &PageBorder{
+		Val:   string(line.Style),
+		Sz:    strconv.Itoa(line.Size),
+		Space: strconv.Itoa(line.Space),
+		Color: strings.TrimPrefix(line.Color, "#"),
+	}
+	if border.Val == "" {
+		border.Val = string(BorderStyleSingle)
+	}
+	if border.Color == "" {
+		border.Color = "auto"
+	}
+	return border
+}
+
+// pageBordersFromXML 将页面边框XML结构转换为配置
+func pageBordersFromXML(borders *PageBorders) *PageBorderConfig {
+	if borders == nil {
+		return nil
+	}
+	config := &PageBorderConfig{
+		Top:        pageBorderFromXML(borders.Top),
+		Left:       pageBorderFromXML(borders.Left),
+		Bottom:     pageBorderFromXML(borders.Bottom),
+		Right:      pageBorderFromXML(borders.Right),
+		OffsetFrom: PageBorderOffset(borders.OffsetFrom),
+		Display:    PageBorderDisplay(borders.Display),
+	}
+	if config.OffsetFrom == "" {
+		config.OffsetFrom = PageBorderOffsetText
+	}
+	if config.Display == "" {
+		config.Display = PageBorderDisplayAllPages
+	}
+	return config
+}
+
+// pageBorderFromXML 将单边边框XML结构转换为配置
+func pageBorderFromXML(border *PageBorder) *PageBorderLine {
+	if border == nil {
+		return nil
+	}
+	line := &PageBorderLine{
+		Style: BorderStyle(border.Val),
+		Size:  int(parseFloat(border.Sz)),
+		Space: int(parseFloat(border.Space)),
+		Color: border.Color,
+	}
+	if line.Color == "auto" {
+		line.Color = ""
+	}
+	return line
+}
+
+// lineNumberingToXML 将行号配置转换为XML结构
+// w:start 为从0开始的值，Word界面中的"起始编号1"对应 w:start="0"
+func lineNumberingToXML(config *LineNumberingConfig) *LineNumType {
+	if config == nil {
+		return nil
+	}
+	lnNumType := &LineNumType{CountBy: "1"}
+	if config.CountBy > 0 {
+		lnNumType.CountBy = strconv.Itoa(config.CountBy)
+	}
+	if config.Start > 1 {
+		lnNumType.Start = strconv.Itoa(config.Start - 1)
+	}
+	if config.Distance > 0 {
+		lnNumType.Distance = fmt.Sprintf("%.0f", mmToTwips(config.Distance))
+	}
+	if config.Restart != "" && config.Restart != LineNumberRestartNewPage {
+		lnNumType.Restart = string(config.Restart)
+	}
+	return lnNumType
+}
+
+// lineNumberingFromXML 将行号XML结构转换为配置
+func lineNumberingFromXML(lnNumType *LineNumType) *LineNumberingConfig {
+	if lnNumType == nil {
+		return nil
+	}
+	config := &LineNumberingConfig{
+		Start:   int(parseFloat(lnNumType.Start)) + 1,
+		CountBy: int(parseFloat(lnNumType.CountBy)),
+		Restart: LineNumberRestart(lnNumType.Restart),
+	}
+	if config.CountBy == 0 {
+		config.CountBy = 1
+	}
+	if config.Restart == "" {
+		config.Restart = LineNumberRestartNewPage
+	}
+	if lnNumType.Distance != "" {
+		config.Distance = twipsToMM(parseFloat(lnNumType.Distance))
+	}
+	return config
+}
+
+// parsePageBorders 解析页面边框
+func (d *Document) parsePageBorders(decoder *xml.Decoder, startElement xml.StartElement) (*PageBorders, error) {
+	borders := &PageBorders{
+		ZOrder:     getAttributeValue(startElement.Attr, "zOrder"),
+		Display:    getAttributeValue(startElement.Attr, "display"),
+		OffsetFrom: getAttributeValue(startElement.Attr, "offsetFrom"),
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, WrapError("parse_page_borders", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			border := &PageBorder{
+				Val:   getAttributeValue(t.Attr, "val"),
+				Sz:    getAttributeValue(t.Attr, "sz"),
+				Space: getAttributeValue(t.Attr, "space"),
+				Color: getAttributeValue(t.Attr, "color"),
+			}
+			switch t.Name.Local {
+			case "top":
+				borders.Top = border
+			case "left", "start":
+				borders.Left = border
+			case "bottom":
+				borders.Bottom = border
+			case "right", "end":
+				borders.Right = border
+			}
+			if err := d.skipElement(decoder, t.Name.Local); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == "pgBorders" {
+				return borders, nil
+			}
+		}
+	}
+}
+
+// parseDocumentBackground 解析文档背景
+func (d *Document) parseDocumentBackground(decoder *xml.Decoder, startElement xml.StartElement) error {
+	var raw struct {
+		Content []byte `xml:",innerxml"`
+	}
+	if err := decoder.DecodeElement(&raw, &startElement); err != nil {
+		return WrapError("parse_background", err)
+	}
+	d.background = &DocumentBackground{
+		Color:      getAttributeValue(startElement.Attr, "color"),
+		ThemeColor: getAttributeValue(startElement.Attr, "themeColor"),
+		ThemeTint:  getAttributeValue(startElement.Attr, "themeTint"),
+		ThemeShade: getAttributeValue(startElement.Attr, "themeShade"),
+		Content:    raw.Content,
+	}
+	return nil
+}
+
+// ColumnConfig 分栏配置
+type ColumnConfig struct {
+	Count     int       // 栏数
+	Spacing   float64   // 栏间距（毫米），0表示默认值12.7毫米
+	Widths    []float64 // 各栏宽度（毫米），不为空时为不等宽分栏，长度须等于栏数
+	Spacings  []float64 // 不等宽分栏时各栏之后的间距（毫米），为空时使用 Spacing
+	Separator bool      // 是否显示栏间分隔线
+}
+
+// Column 单栏设置（不等宽分栏）
+type Column struct {
+	XMLName xml.Name `xml:"w:col"`
+	W       string   `xml:"w:w,attr"`               // 栏宽（twips）
+	Space   string   `xml:"w:space,attr,omitempty"` // 与下一栏的间距（twips）
+}
+
+// defaultColumnSpacing 默认栏间距（毫米，720twips）
+const defaultColumnSpacing = 12.7
+
+// SetColumns 设置最后一节的分栏，config 为nil时恢复为单栏
+//
+// 示例:
+//
+//	// 两栏，栏间距5毫米，带分隔线
+//	doc.SetColumns(&document.ColumnConfig{Count: 2, Spacing: 5, Separator: true})
+//
+//	// 不等宽两栏
+//	doc.SetColumns(&document.ColumnConfig{Count: 2, Widths: []float64{100, 55}, Spacing: 5})
+func (d *Document) SetColumns(config *ColumnConfig) error {
+	if err := validatePageLayout(&PageSettings{Columns: config}); err != nil {
+		return WrapError("SetColumns", err)
+	}
+	d.getSectionProperties().Columns = columnsToXML(config)
+	return nil
+}
+
+// AddSectionBreak 在文档末尾插入分节符
+//
+// 此前的内容成为独立的一节（保留原有页面设置、分栏和页眉页脚），
+// 之后添加的内容属于新的一节，新节的起始方式为 breakType，页面设置沿用上一节。
+func (d *Document) AddSectionBreak(breakType SectionBreakType) error {
+	switch breakType {
+	case SectionBreakNextPage, SectionBreakContinuous, SectionBreakEvenPage, SectionBreakOddPage, SectionBreakNextColumn:
+	default:
+		return WrapError("AddSectionBreak", fmt.Errorf("无效的分节符类型: %s", breakType))
+	}
+
+	bodySectPr := d.getSectionProperties()
+	breakPara := &Paragraph{
+		Properties: &ParagraphProperties{
+			SectionProperties: cloneSectionProperties(bodySectPr),
+		},
+	}
+	d.insertBeforeSectionProperties([]interface{}{breakPara})
+
+	bodySectPr.Type = &SectionType{Val: string(breakType)}
+	Debugf("添加分节符: %s", breakType)
+	return nil
+}
+
+// AddContinuousSection 插入连续分节符并为新节设置分栏
+//
+// 适用于单栏标题下的多栏正文（如报刊排版）：
+//
+//	doc.AddHeadingParagraph("社区通讯", 1)
+//	doc.AddContinuousSection(&document.ColumnConfig{Count: 2, Separator: true})
+//	doc.AddParagraph("正文第一栏...")
+func (d *Document) AddContinuousSection(columns *ColumnConfig) error {
+	if columns != nil {
+		if err := validateColumns(columns); err != nil {
+			return WrapError("AddContinuousSection", err)
+		}
+	}
+	if err := d.AddSectionBreak(SectionBreakContinuous); err != nil {
+		return err
+	}
+	return d.SetColumns(columns)
+}
+
+// AddColumnBreak 向段落添加分栏符，之后的内容从下一栏开始
+func (p *Paragraph) AddColumnBreak() {
+	p.Runs = append(p.Runs, Run{Break: &Break{Type: "column"}})
+	Debugf("向段落添加分栏符")
+}
+
+// validateColumns 验证分栏配置
+func validateColumns(config *ColumnConfig) error {
+	if config.Count < 1 {
+		return errors.New("栏数必须大于0")
+	}
+	if config.Spacing < 0 {
+		return errors.New("栏间距不能为负数")
+	}
+	if len(config.Widths) > 0 && len(config.Widths) != config.Count {
+		return fmt.Errorf("栏宽数量（%d）必须等于栏数（%d）", len(config.Widths), config.Count)
+	}
+	for _, width := range config.Widths {
+		if width <= 0 {
+			return errors.New("栏宽必须大于0")
+		}
+	}
+	for _, spacing := range config.Spacings {
+		if spacing < 0 {
+			return errors.New("栏间距不能为负数")
+		}
+	}
+	return nil
+}
+
+// columnsToXML 将分栏配置转换为XML结构
+func columnsToXML(config *ColumnConfig) *Columns {
+	if config == nil {
+		return nil
+	}
+	spacing := config.Spacing
+	if spacing == 0 {
+		spacing = defaultColumnSpacing
+	}
+	columns := &Columns{
+		Space: fmt.Sprintf("%.0f", mmToTwips(spacing)),
+		Num:   strconv.Itoa(config.Count),
+	}
+	if config.Separator {
+		columns.Sep = "1"
+	}
+	if len(config.Widths) > 0 {
+		columns.EqualWidth = "0"
+		for i, width := range config.Widths {
+			column := &Column{W: fmt.Sprintf("%.0f", mmToTwips(width))}
+			if i < len(config.Widths)-1 {
+				columnSpacing := spacing
+				if i < len(config.Spacings) {
+					columnSpacing = config.Spacings[i]
+				}
+				column.Space = fmt.Sprintf("%.0f", mmToTwips(columnSpacing))
+			}
+			columns.Cols = append(columns.Cols, column)
+		}
+	}
+	return columns
+}
+
+// columnsFromXML 将分栏XML结构转换为配置
+func columnsFromXML(columns *Columns) *ColumnConfig {
+	if columns == nil {
+		return nil
+	}
+	config := &ColumnConfig{
+		Count:     int(parseFloat(columns.Num)),
+		Spacing:   defaultColumnSpacing,
+		Separator: parseOnOff(columns.Sep) && columns.Sep != "",
+	}
+	if columns.Space != "" {
+		config.Spacing = twipsToMM(parseFloat(columns.Space))
+	}
+	if config.Count < 1 {
+		config.Count = 1
+	}
+	if columns.EqualWidth != "" && !parseOnOff(columns.EqualWidth) && len(columns.Cols) > 0 {
+		config.Count = len(columns.Cols)
+		for i, column := range columns.Cols {
+			config.Widths = append(config.Widths, twipsToMM(parseFloat(column.W)))
+			if i < len(columns.Cols)-1 {
+				config.Spacings = append(config.Spacings, twipsToMM(parseFloat(column.Space)))
+			}
+		}
+	}
+	return config
+}
+
+// parseColumns 解析分栏设置
+func (d *Document) parseColumns(decoder *xml.Decoder, startElement xml.StartElement) (*Columns, error) {
+	columns := &Columns{
+		EqualWidth: getAttributeValue(startElement.Attr, "equalWidth"),
+		Space:      getAttributeValue(startElement.Attr, "space"),
+		Num:        getAttributeValue(startElement.Attr, "num"),
+		Sep:        getAttributeValue(startElement.Attr, "sep"),
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, WrapError("parse_columns", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "col" {
+				columns.Cols = append(columns.Cols, &Column{
+					W:     getAttributeValue(t.Attr, "w"),
+					Space: getAttributeValue(t.Attr, "space"),
+				})
+			}
+			if err := d.skipElement(decoder, t.Name.Local); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if t.Name.Local == "cols" {
+				return columns, nil
+			}
+		}
+	}
+}
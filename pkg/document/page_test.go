@@ -2,6 +2,7 @@
 package document
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -401,3 +402,245 @@ func TestCompletePageSettings(t *testing.T) {
 		t.Errorf("上边距不匹配，期望: %.1fmm, 实际: %.1fmm", settings.MarginTop, retrieved.MarginTop)
 	}
 }
+
+// TestSetPageNumberFormat 测试设置页码格式并保存后重新打开
+func TestSetPageNumberFormat(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+
+	if err := doc.SetPageNumberFormat(NumberFormatChineseCounting, 3); err != nil {
+		t.Fatalf("设置页码格式失败: %v", err)
+	}
+	if got := doc.GetPageNumberLabel(12); got != "十二" {
+		t.Errorf("页码应显示为 十二，实际为: %s", got)
+	}
+	if err := doc.SetPageNumberFormat(NumberFormatBullet, 0); err == nil {
+		t.Error("项目符号不能作为页码格式")
+	}
+
+	filename := filepath.Join(t.TempDir(), "page_number.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	pgNumType := reopened.getSectionProperties().PageNumType
+	if pgNumType == nil || pgNumType.Fmt != "chineseCounting" || pgNumType.Start != "3" {
+		t.Errorf("重新打开后页码类型不正确: %+v", pgNumType)
+	}
+}
+
+// TestPageLayoutRoundTrip 测试页面边框、行号、垂直对齐和背景的往返
+func TestPageLayoutRoundTrip(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+
+	line := &PageBorderLine{Style: BorderStyleDouble, Size: 4, Space: 24, Color: "1F4E79"}
+	if err := doc.SetPageBorders(&PageBorderConfig{
+		Top: line, Left: line, Bottom: line,
+		Right:      &PageBorderLine{Style: BorderArtStars, Size: 12, Space: 24},
+		OffsetFrom: PageBorderOffsetPage,
+		Display:    PageBorderDisplayFirstPage,
+	}); err != nil {
+		t.Fatalf("设置页面边框失败: %v", err)
+	}
+	if err := doc.SetLineNumbering(&LineNumberingConfig{Start: 5, CountBy: 5, Restart: LineNumberRestartContinuous, Distance: 5}); err != nil {
+		t.Fatalf("设置行号失败: %v", err)
+	}
+	if err := doc.SetPageVerticalAlign(PageVerticalAlignCenter); err != nil {
+		t.Fatalf("设置垂直对齐失败: %v", err)
+	}
+	if err := doc.SetBackgroundColor("#fff2cc"); err != nil {
+		t.Fatalf("设置背景颜色失败: %v", err)
+	}
+	if err := doc.SetBackgroundColor("red"); err == nil {
+		t.Error("无效的背景颜色应返回错误")
+	}
+	if err := doc.SetPageVerticalAlign("middle"); err == nil {
+		t.Error("无效的垂直对齐方式应返回错误")
+	}
+
+	filename := filepath.Join(t.TempDir(), "page_layout.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+
+	settings := reopened.GetPageSettings()
+	borders := settings.PageBorders
+	if borders == nil || borders.OffsetFrom != PageBorderOffsetPage || borders.Display != PageBorderDisplayFirstPage {
+		t.Fatalf("页面边框设置不正确: %+v", borders)
+	}
+	if *borders.Top != *line || borders.Right.Style != BorderArtStars || borders.Right.Color != "" {
+		t.Errorf("页面边框线不正确: %+v %+v", borders.Top, borders.Right)
+	}
+	numbering := settings.LineNumbering
+	if numbering == nil || numbering.Start != 5 || numbering.CountBy != 5 || numbering.Restart != LineNumberRestartContinuous {
+		t.Errorf("行号设置不正确: %+v", numbering)
+	}
+	if numbering != nil && (numbering.Distance < 4.9 || numbering.Distance > 5.1) {
+		t.Errorf("行号距离不正确: %v", numbering.Distance)
+	}
+	if settings.VerticalAlign != PageVerticalAlignCenter {
+		t.Errorf("垂直对齐方式不正确: %s", settings.VerticalAlign)
+	}
+	if settings.BackgroundColor != "FFF2CC" || !reopened.Settings().DisplayBackgroundShape {
+		t.Errorf("页面背景不正确: %s", settings.BackgroundColor)
+	}
+
+	// 通过 SetPageSettings 写回读取的设置后保持不变
+	if err := reopened.SetPageSettings(settings); err != nil {
+		t.Fatalf("写回页面设置失败: %v", err)
+	}
+	sectPr := reopened.getSectionProperties()
+	if sectPr.LineNumType.Start != "4" || sectPr.PageBorders.Right.Color != "auto" {
+		t.Errorf("写回后的节属性不正确: %+v %+v", sectPr.LineNumType, sectPr.PageBorders.Right)
+	}
+
+	if err := reopened.SetLineNumbering(nil); err != nil {
+		t.Fatalf("关闭行号失败: %v", err)
+	}
+	if err := reopened.SetBackgroundColor(""); err != nil {
+		t.Fatalf("清除背景失败: %v", err)
+	}
+	if reopened.getSectionProperties().LineNumType != nil || reopened.GetBackgroundColor() != "" {
+		t.Error("行号和背景应被清除")
+	}
+}
+
+// TestSetPageSettingsKeepsLayout 测试页面设置中未设置的版式项保持不变
+func TestSetPageSettingsKeepsLayout(t *testing.T) {
+	doc := New()
+	line := &PageBorderLine{Style: BorderStyleSingle, Size: 4}
+	if err := doc.SetPageBorders(&PageBorderConfig{Top: line, Bottom: line}); err != nil {
+		t.Fatalf("设置页面边框失败: %v", err)
+	}
+	if err := doc.SetLineNumbering(&LineNumberingConfig{CountBy: 5}); err != nil {
+		t.Fatalf("设置行号失败: %v", err)
+	}
+	if err := doc.SetPageVerticalAlign(PageVerticalAlignBottom); err != nil {
+		t.Fatalf("设置垂直对齐失败: %v", err)
+	}
+	if err := doc.SetColumns(&ColumnConfig{Count: 2}); err != nil {
+		t.Fatalf("设置分栏失败: %v", err)
+	}
+	if err := doc.SetBackgroundColor("FFF2CC"); err != nil {
+		t.Fatalf("设置背景颜色失败: %v", err)
+	}
+
+	// 只修改纸张和边距
+	settings := DefaultPageSettings()
+	settings.Size = PageSizeA3
+	if err := doc.SetPageSettings(settings); err != nil {
+		t.Fatalf("设置页面失败: %v", err)
+	}
+
+	result := doc.GetPageSettings()
+	if result.Size != PageSizeA3 {
+		t.Errorf("页面尺寸应更新为A3，实际为 %s", result.Size)
+	}
+	if result.PageBorders == nil || result.PageBorders.Top == nil {
+		t.Error("页面边框不应被清除")
+	}
+	if result.LineNumbering == nil || result.LineNumbering.CountBy != 5 {
+		t.Error("行号不应被清除")
+	}
+	if result.VerticalAlign != PageVerticalAlignBottom {
+		t.Errorf("垂直对齐方式不应被修改，实际为 %s", result.VerticalAlign)
+	}
+	if result.Columns == nil || result.Columns.Count != 2 {
+		t.Error("分栏不应被清除")
+	}
+	if result.BackgroundColor != "FFF2CC" {
+		t.Errorf("页面背景不应被清除，实际为 %q", result.BackgroundColor)
+	}
+
+	// 专用方法可以清除对应设置
+	if err := doc.SetPageBorders(nil); err != nil {
+		t.Fatalf("清除页面边框失败: %v", err)
+	}
+	if err := doc.SetPageVerticalAlign(""); err != nil {
+		t.Fatalf("清除垂直对齐失败: %v", err)
+	}
+	if err := doc.SetColumns(nil); err != nil {
+		t.Fatalf("清除分栏失败: %v", err)
+	}
+	result = doc.GetPageSettings()
+	if result.PageBorders != nil || result.VerticalAlign != "" || (result.Columns != nil && result.Columns.Count > 1) {
+		t.Errorf("专用方法应清除对应设置: %+v", result)
+	}
+}
+
+// TestColumnsAndSections 测试分栏、分栏符和连续分节
+func TestColumnsAndSections(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("社区通讯")
+	if err := doc.AddContinuousSection(&ColumnConfig{Count: 2, Spacing: 5, Separator: true}); err != nil {
+		t.Fatalf("添加连续分节失败: %v", err)
+	}
+	para := doc.AddParagraph("第一栏内容")
+	para.AddColumnBreak()
+	doc.AddParagraph("第二栏内容")
+
+	if err := doc.AddSectionBreak("invalid"); err == nil {
+		t.Error("无效的分节符类型应返回错误")
+	}
+	if err := doc.SetColumns(&ColumnConfig{Count: 2, Widths: []float64{100}}); err == nil {
+		t.Error("栏宽数量与栏数不一致应返回错误")
+	}
+
+	filename := filepath.Join(t.TempDir(), "columns.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+
+	sections := reopened.allSectionProperties()
+	if len(sections) != 2 {
+		t.Fatalf("应有2个节，实际为: %d", len(sections))
+	}
+	if sections[0].Columns != nil && sections[0].Columns.Num != "" && sections[0].Columns.Num != "1" {
+		t.Errorf("标题所在节应为单栏: %+v", sections[0].Columns)
+	}
+	if sections[1].Type == nil || sections[1].Type.Val != string(SectionBreakContinuous) {
+		t.Errorf("正文节应为连续分节: %+v", sections[1].Type)
+	}
+
+	columns := reopened.GetPageSettings().Columns
+	if columns == nil || columns.Count != 2 || !columns.Separator || columns.Spacing < 4.9 || columns.Spacing > 5.1 {
+		t.Errorf("分栏设置不正确: %+v", columns)
+	}
+
+	foundBreak := false
+	for _, p := range reopened.Body.GetParagraphs() {
+		for _, run := range p.Runs {
+			if run.Break != nil && run.Break.Type == "column" {
+				foundBreak = true
+			}
+		}
+	}
+	if !foundBreak {
+		t.Error("分栏符应被保留")
+	}
+
+	// 不等宽分栏
+	if err := reopened.SetColumns(&ColumnConfig{Count: 2, Widths: []float64{100, 50}, Spacings: []float64{10}}); err != nil {
+		t.Fatalf("设置不等宽分栏失败: %v", err)
+	}
+	cols := reopened.getSectionProperties().Columns
+	if cols.EqualWidth != "0" || len(cols.Cols) != 2 || cols.Cols[0].Space != "567" || cols.Cols[1].Space != "" {
+		t.Errorf("不等宽分栏XML不正确: %+v", cols)
+	}
+	columns = reopened.GetPageSettings().Columns
+	if len(columns.Widths) != 2 || len(columns.Spacings) != 1 || columns.Widths[1] < 49.9 || columns.Widths[1] > 50.1 {
+		t.Errorf("不等宽分栏读取不正确: %+v", columns)
+	}
+}
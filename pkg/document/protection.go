@@ -0,0 +1,425 @@
+// Package document 文档保护与可编辑区域功能实现
+package document
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"math/big"
+	"strconv"
+	"unicode/utf16"
+)
+
+// ProtectionMode 文档保护模式，决定保护后允许的编辑类型
+type ProtectionMode string
+
+const (
+	// ProtectionReadOnly 只读，仅可编辑区域允许修改
+	ProtectionReadOnly ProtectionMode = "readOnly"
+	// ProtectionComments 仅允许批注
+	ProtectionComments ProtectionMode = "comments"
+	// ProtectionTrackedChanges 仅允许修订（所有修改都会被跟踪）
+	ProtectionTrackedChanges ProtectionMode = "trackedChanges"
+	// ProtectionForms 仅允许填写窗体域
+	ProtectionForms ProtectionMode = "forms"
+)
+
+const (
+	// protectionAlgorithm 密码哈希算法
+	protectionAlgorithm = "SHA-512"
+	// protectionSpinCount 哈希迭代次数（与Word一致）
+	protectionSpinCount = 100000
+	// protectionSaltSize 盐值长度（字节）
+	protectionSaltSize = 16
+	// maxPasswordLength 旧版密码哈希处理的最大密码长度
+	maxPasswordLength = 15
+)
+
+// EditorEveryone 可编辑区域对所有人开放
+const EditorEveryone = "everyone"
+
+// permissionEditorGroups 可编辑区域支持的编辑者组（w:edGrp）
+var permissionEditorGroups = map[string]bool{
+	"none":           true,
+	"everyone":       true,
+	"administrators": true,
+	"contributors":   true,
+	"editors":        true,
+	"owners":         true,
+	"current":        true,
+}
+
+// PermStart 可编辑区域开始（w:permStart）
+type PermStart struct {
+	XMLName  xml.Name `xml:"w:permStart"`
+	ID       string   `xml:"w:id,attr"`
+	EdGrp    string   `xml:"w:edGrp,attr,omitempty"`    // 编辑者组，如 everyone
+	Ed       string   `xml:"w:ed,attr,omitempty"`       // 单个编辑者，如 user@example.com
+	ColFirst string   `xml:"w:colFirst,attr,omitempty"` // 表格中的起始列
+	ColLast  string   `xml:"w:colLast,attr,omitempty"`  // 表格中的结束列
+}
+
+// PermEnd 可编辑区域结束（w:permEnd）
+type PermEnd struct {
+	XMLName xml.Name `xml:"w:permEnd"`
+	ID      string   `xml:"w:id,attr"`
+}
+
+// PermissionMark 段落中的可编辑区域标记
+// Position 为标记在段落中的位置，即其后第一个Run的索引；
+// 等于Run数量时标记位于段落末尾。开始和结束标记可以位于不同段落。
+type PermissionMark struct {
+	Position int
+	Start    *PermStart
+	End      *PermEnd
+}
+
+// marshal 序列化可编辑区域标记
+func (m *PermissionMark) marshal(e *xml.Encoder) error {
+	if m.Start != nil {
+		return e.EncodeElement(m.Start, xml.StartElement{Name: xml.Name{Local: "w:permStart"}})
+	}
+	if m.End != nil {
+		return e.EncodeElement(m.End, xml.StartElement{Name: xml.Name{Local: "w:permEnd"}})
+	}
+	return nil
+}
+
+// Protect 为文档设置编辑限制
+//
+// 保护信息写入settings.xml的w:documentProtection元素。
+// password 为空时仅限制编辑而不设置密码；否则使用ECMA-376规定的
+// 加盐、迭代（100000次）SHA-512哈希保存密码，文档中不保存明文。
+// 已有的保护设置会被替换。
+func (d *Document) Protect(mode ProtectionMode, password string) error {
+	switch mode {
+	case ProtectionReadOnly, ProtectionComments, ProtectionTrackedChanges, ProtectionForms:
+	default:
+		return NewValidationError("mode", string(mode), "不支持的保护模式")
+	}
+
+	protection := &DocumentProtection{
+		Edit:        string(mode),
+		Enforcement: "1",
+	}
+	if password != "" {
+		salt := make([]byte, protectionSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return WrapError("generate_protection_salt", err)
+		}
+		hashValue, err := hashProtectionPassword(password, protectionAlgorithm, salt, protectionSpinCount, true)
+		if err != nil {
+			return WrapError("hash_protection_password", err)
+		}
+		protection.AlgorithmName = protectionAlgorithm
+		protection.HashValue = base64.StdEncoding.EncodeToString(hashValue)
+		protection.SaltValue = base64.StdEncoding.EncodeToString(salt)
+		protection.SpinCount = strconv.Itoa(protectionSpinCount)
+	}
+
+	settings := d.Settings()
+	settings.DocumentProtection = protection
+	if mode == ProtectionTrackedChanges {
+		// 修订保护要求同时打开修订跟踪
+		settings.TrackRevisions = true
+	}
+
+	Infof("设置文档保护: %s", mode)
+	return nil
+}
+
+// Unprotect 移除文档的编辑限制
+func (d *Document) Unprotect() {
+	settings := d.Settings()
+	if settings.DocumentProtection == nil {
+		return
+	}
+	settings.DocumentProtection = nil
+	Infof("移除文档保护")
+}
+
+// IsProtected 检查文档是否启用了编辑限制
+func (d *Document) IsProtected() bool {
+	protection := d.Settings().DocumentProtection
+	return protection != nil && protection.Enforcement != "" && parseOnOff(protection.Enforcement)
+}
+
+// GetProtectionMode 获取文档的保护模式，未保护时返回空字符串
+func (d *Document) GetProtectionMode() ProtectionMode {
+	if !d.IsProtected() {
+		return ""
+	}
+	return ProtectionMode(d.Settings().DocumentProtection.Edit)
+}
+
+// VerifyPassword 验证文档保护密码是否正确
+//
+// 同时支持Word 2010及以后版本的哈希属性（algorithmName/hashValue）
+// 和旧版的哈希属性（cryptAlgorithmSid/hash）。
+// 文档未保护或保护未设置密码时，仅空密码验证通过。
+func (d *Document) VerifyPassword(password string) bool {
+	protection := d.Settings().DocumentProtection
+	if protection == nil {
+		return password == ""
+	}
+
+	if protection.HashValue != "" {
+		return verifyProtectionHash(password, protection.AlgorithmName,
+			protection.HashValue, protection.SaltValue, protection.SpinCount)
+	}
+	if protection.Hash != "" {
+		return verifyProtectionHash(password, cryptAlgorithmName(protection.CryptAlgorithmSid),
+			protection.Hash, protection.Salt, protection.CryptSpinCount)
+	}
+	return password == ""
+}
+
+// AddEditableRange 在段落中添加可编辑区域
+//
+// 文档受保护时，startRun到endRun（包含，按Run索引）之间的内容仍允许指定编辑者修改。
+// editor 可以是编辑者组（everyone、editors、owners、current 等）或单个用户
+// （如 user@example.com），为空时对所有人开放。
+func (p *Paragraph) AddEditableRange(startRun, endRun int, editor string) error {
+	if startRun < 0 || startRun >= len(p.Runs) {
+		return NewValidationError("startRun", strconv.Itoa(startRun), "Run索引超出范围")
+	}
+	if endRun < startRun || endRun >= len(p.Runs) {
+		return NewValidationError("endRun", strconv.Itoa(endRun), "Run索引超出范围")
+	}
+
+	id, err := newPermissionID()
+	if err != nil {
+		return WrapError("add_editable_range", err)
+	}
+
+	start := &PermStart{ID: id}
+	if editor == "" {
+		editor = EditorEveryone
+	}
+	if permissionEditorGroups[editor] {
+		start.EdGrp = editor
+	} else {
+		start.Ed = editor
+	}
+
+	p.Permissions = append(p.Permissions,
+		&PermissionMark{Position: startRun, Start: start},
+		&PermissionMark{Position: endRun + 1, End: &PermEnd{ID: id}},
+	)
+
+	Debugf("添加可编辑区域: Run %d-%d, 编辑者 %s", startRun, endRun, editor)
+	return nil
+}
+
+// newPermissionID 生成可编辑区域ID
+// 与Word一致使用随机ID，避免与已有文档中的区域冲突
+func newPermissionID() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<31-1))
+	if err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+// parsePermStart 从XML属性解析可编辑区域开始标记
+func parsePermStart(start xml.StartElement) *PermStart {
+	return &PermStart{
+		ID:       getAttributeValue(start.Attr, "id"),
+		EdGrp:    getAttributeValue(start.Attr, "edGrp"),
+		Ed:       getAttributeValue(start.Attr, "ed"),
+		ColFirst: getAttributeValue(start.Attr, "colFirst"),
+		ColLast:  getAttributeValue(start.Attr, "colLast"),
+	}
+}
+
+// clonePermissionMarks 深度复制可编辑区域标记
+func clonePermissionMarks(marks []*PermissionMark) []*PermissionMark {
+	if len(marks) == 0 {
+		return nil
+	}
+	cloned := make([]*PermissionMark, len(marks))
+	for i, mark := range marks {
+		copied := &PermissionMark{Position: mark.Position}
+		if mark.Start != nil {
+			start := *mark.Start
+			copied.Start = &start
+		}
+		if mark.End != nil {
+			end := *mark.End
+			copied.End = &end
+		}
+		cloned[i] = copied
+	}
+	return cloned
+}
+
+// verifyProtectionHash 使用保存的算法、盐值和迭代次数验证密码
+func verifyProtectionHash(password, algorithm, hashValue, saltValue, spinCount string) bool {
+	expected, err := base64.StdEncoding.DecodeString(hashValue)
+	if err != nil {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(saltValue)
+	if err != nil {
+		return false
+	}
+	spin, err := strconv.Atoi(spinCount)
+	if err != nil || spin < 0 || spin > maxPasswordSpinCount {
+		return false
+	}
+
+	// Word对密码先做旧版哈希预处理，其他生成工具可能直接哈希密码
+	for _, legacy := range []bool{true, false} {
+		actual, err := hashProtectionPassword(password, algorithm, salt, spin, legacy)
+		if err != nil {
+			return false
+		}
+		if subtle.ConstantTimeCompare(actual, expected) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// hashProtectionPassword 计算文档保护密码哈希
+//
+// 算法（ECMA-376 第1部分 17.15.1.29，以及Word的实现说明）：
+//  1. legacy 为true时，密码先经过旧版异或哈希，得到的4字节密钥按字节反序后
+//     转为大写十六进制字符串，作为后续步骤的输入
+//  2. H0 = HASH(salt + UTF-16LE(输入))
+//  3. Hn = HASH(Hn-1 + 迭代序号（4字节小端）)，共迭代spinCount次
+func hashProtectionPassword(password, algorithm string, salt []byte, spinCount int, legacy bool) ([]byte, error) {
+	newHash, err := protectionHashFunc(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	input := password
+	if legacy {
+		input = legacyPasswordKey(password)
+	}
+
+	h := newHash()
+	h.Write(salt)
+	h.Write(utf16LEBytes(input))
+	result := h.Sum(nil)
+
+	iterator := make([]byte, 4)
+	for i := 0; i < spinCount; i++ {
+		binary.LittleEndian.PutUint32(iterator, uint32(i))
+		h.Reset()
+		h.Write(result)
+		h.Write(iterator)
+		result = h.Sum(result[:0])
+	}
+	return result, nil
+}
+
+// protectionHashFunc 根据算法名称返回哈希函数
+func protectionHashFunc(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "SHA-1":
+		return sha1.New, nil
+	case "SHA-256":
+		return sha256.New, nil
+	case "SHA-384":
+		return sha512.New384, nil
+	case "SHA-512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("不支持的哈希算法: %s", algorithm)
+	}
+}
+
+// cryptAlgorithmName 将旧版格式的算法编号（cryptAlgorithmSid）转换为算法名称
+func cryptAlgorithmName(sid string) string {
+	switch sid {
+	case "4":
+		return "SHA-1"
+	case "12":
+		return "SHA-256"
+	case "13":
+		return "SHA-384"
+	case "14":
+		return "SHA-512"
+	default:
+		return sid
+	}
+}
+
+// utf16LEBytes 将字符串转换为UTF-16LE字节序列
+func utf16LEBytes(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, len(units)*2)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(data[i*2:], unit)
+	}
+	return data
+}
+
+// legacyInitialCodes 旧版密码哈希的初始码（按密码长度索引）
+var legacyInitialCodes = [maxPasswordLength]uint16{
+	0xE1F0, 0x1D0F, 0xCC9C, 0x84C0, 0x110C, 0x0E10, 0xF1CE,
+	0x313E, 0x1872, 0xE139, 0xD40F, 0x84F9, 0x280C, 0xA96A,
+	0x4EC3,
+}
+
+// legacyEncryptionMatrixSeeds 旧版密码哈希加密矩阵每行的首个值
+// 每行其余的值由前一个值左移一位并在溢出时异或0x1021得到
+var legacyEncryptionMatrixSeeds = [maxPasswordLength]uint16{
+	0xAEFC, 0x7B61, 0x4563, 0x0375, 0xD849, 0x6F45, 0xEB23, 0x47D3,
+	0xB861, 0x45A0, 0xAA51, 0x76B4, 0x3730, 0x3331, 0x1021,
+}
+
+// legacyPasswordKey 计算旧版（Word 97-2003）密码哈希，
+// 返回按字节反序的大写十六进制字符串
+func legacyPasswordKey(password string) string {
+	if password == "" {
+		return ""
+	}
+
+	runes := []rune(password)
+	if len(runes) > maxPasswordLength {
+		runes = runes[:maxPasswordLength]
+	}
+	// 每个字符取低字节，低字节为0时取高字节
+	chars := make([]byte, len(runes))
+	for i, r := range runes {
+		b := byte(r & 0xFF)
+		if b == 0 {
+			b = byte((r >> 8) & 0xFF)
+		}
+		chars[i] = b
+	}
+
+	high := legacyInitialCodes[len(chars)-1]
+	for i, ch := range chars {
+		value := legacyEncryptionMatrixSeeds[maxPasswordLength-len(chars)+i]
+		for bit := 0; bit < 7; bit++ {
+			if ch&(1<<bit) != 0 {
+				high ^= value
+			}
+			overflow := value&0x8000 != 0
+			value <<= 1
+			if overflow {
+				value ^= 0x1021
+			}
+		}
+	}
+
+	var low uint16
+	for i := len(chars) - 1; i >= 0; i-- {
+		low = (((low >> 14) & 0x0001) | ((low << 1) & 0x7FFF)) ^ uint16(chars[i])
+	}
+	low = (((low >> 14) & 0x0001) | ((low << 1) & 0x7FFF)) ^ uint16(len(chars)) ^ 0xCE4B
+
+	key := uint32(high)<<16 | uint32(low)
+	return fmt.Sprintf("%02X%02X%02X%02X", byte(key), byte(key>>8), byte(key>>16), byte(key>>24))
+}
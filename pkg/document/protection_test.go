@@ -0,0 +1,122 @@
+// Package document 文档保护功能测试
+package document
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDocumentProtection 测试文档保护、密码验证和可编辑区域的保存与读取
+func TestDocumentProtection(t *testing.T) {
+	doc := New()
+	para := doc.AddParagraph("姓名：")
+	para.AddFormattedText("请填写", nil)
+	para.AddFormattedText("（必填）", nil)
+
+	if err := doc.Protect(ProtectionMode("invalid"), ""); err == nil {
+		t.Error("不支持的保护模式应返回错误")
+	}
+	if err := para.AddEditableRange(1, 3, ""); err == nil {
+		t.Error("超出范围的Run索引应返回错误")
+	}
+	if err := para.AddEditableRange(1, 1, ""); err != nil {
+		t.Fatalf("添加可编辑区域失败: %v", err)
+	}
+	if err := doc.Protect(ProtectionReadOnly, "secret"); err != nil {
+		t.Fatalf("设置文档保护失败: %v", err)
+	}
+
+	data, err := doc.ToBytes()
+	if err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	settingsXML := string(doc.parts["word/settings.xml"])
+	if !strings.Contains(settingsXML, `w:algorithmName="SHA-512"`) || strings.Contains(settingsXML, "secret") {
+		t.Errorf("保护设置应包含SHA-512哈希而非明文密码: %s", settingsXML)
+	}
+
+	opened, err := OpenFromMemory(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if opened.GetProtectionMode() != ProtectionReadOnly {
+		t.Errorf("保护模式应为只读，实际为: %q", opened.GetProtectionMode())
+	}
+	if !opened.VerifyPassword("secret") || opened.VerifyPassword("wrong") || opened.VerifyPassword("") {
+		t.Error("密码验证结果不正确")
+	}
+
+	marks := opened.Body.GetParagraphs()[0].Permissions
+	if len(marks) != 2 || marks[0].Start == nil || marks[0].Start.EdGrp != EditorEveryone ||
+		marks[0].Position != 1 || marks[1].End == nil || marks[1].Position != 2 ||
+		marks[0].Start.ID != marks[1].End.ID {
+		t.Fatalf("可编辑区域解析不正确: %+v", marks)
+	}
+
+	// 重新保存后保护设置和可编辑区域仍然保留
+	filename := filepath.Join(t.TempDir(), "protected.docx")
+	if err := opened.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if !reopened.IsProtected() || !reopened.VerifyPassword("secret") {
+		t.Error("重新保存后应保留文档保护")
+	}
+	if len(reopened.Body.GetParagraphs()[0].Permissions) != 2 {
+		t.Error("重新保存后应保留可编辑区域")
+	}
+
+	reopened.Unprotect()
+	if reopened.IsProtected() || !reopened.VerifyPassword("") {
+		t.Error("移除保护后文档不应受保护")
+	}
+}
+
+// TestProtectionSpinCountLimit 测试超出范围的迭代次数直接判定密码不正确
+func TestProtectionSpinCountLimit(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	hash, err := hashProtectionPassword("secret", "SHA-512", salt, 10, true)
+	if err != nil {
+		t.Fatalf("计算密码哈希失败: %v", err)
+	}
+	hashValue := base64.StdEncoding.EncodeToString(hash)
+	saltValue := base64.StdEncoding.EncodeToString(salt)
+
+	if !verifyProtectionHash("secret", "SHA-512", hashValue, saltValue, "10") {
+		t.Error("迭代次数有效时密码应验证通过")
+	}
+	for _, spinCount := range []string{"2147483647", "10000001", "-1"} {
+		if verifyProtectionHash("secret", "SHA-512", hashValue, saltValue, spinCount) {
+			t.Errorf("迭代次数 %s 应验证失败", spinCount)
+		}
+	}
+}
+
+// TestProtectionTrackedChanges 测试修订保护和无密码保护
+func TestProtectionTrackedChanges(t *testing.T) {
+	doc := New()
+	if err := doc.Protect(ProtectionTrackedChanges, ""); err != nil {
+		t.Fatalf("设置文档保护失败: %v", err)
+	}
+	if !doc.Settings().TrackRevisions {
+		t.Error("修订保护应打开修订跟踪")
+	}
+	if doc.Settings().DocumentProtection.HashValue != "" || !doc.VerifyPassword("") {
+		t.Error("无密码保护不应保存密码哈希")
+	}
+
+	para := doc.AddParagraph("内容")
+	if err := para.AddEditableRange(0, 0, "user@example.com"); err != nil {
+		t.Fatalf("添加可编辑区域失败: %v", err)
+	}
+	if para.Permissions[0].Start.Ed != "user@example.com" || para.Permissions[0].Start.EdGrp != "" {
+		t.Errorf("单个用户应写入w:ed属性: %+v", para.Permissions[0].Start)
+	}
+}
@@ -0,0 +1,363 @@
+// Package document 从参考文档导入样式、编号、主题、字体、页面设置和页眉页脚
+package document
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// ApplyStylesOptions 从参考文档导入格式的选项，零值表示导入全部内容并使用参考文档的样式
+type ApplyStylesOptions struct {
+	// ConflictPolicy 样式冲突处理策略，为空时使用 style.ConflictOverwrite（参考文档的样式优先）
+	ConflictPolicy style.StyleConflictPolicy
+	// SkipNumbering 不导入编号定义（参考文档样式中的编号属性会被移除）
+	SkipNumbering bool
+	// SkipTheme 不导入主题
+	SkipTheme bool
+	// SkipFonts 不导入字体表（包括嵌入字体）
+	SkipFonts bool
+	// SkipPageSettings 不导入页面尺寸、边距、分栏等页面设置
+	SkipPageSettings bool
+	// SkipHeadersFooters 不导入页眉页脚
+	SkipHeadersFooters bool
+}
+
+// ApplyStylesFrom 从参考文档（.docx 或 .dotx 模板）导入样式、编号定义、主题、字体、
+// 页面设置和页眉页脚，类似 pandoc 的 --reference-doc
+//
+// 样式按 ID 或"类型+名称"匹配：参考文档中名为 "heading 1" 的样式（中文版 Word 的ID为 "1"）
+// 会替换本文档的 Heading1，正文、页眉页脚中的样式引用同步更新为参考文档的ID。
+// 编号定义以新的ID追加，不影响本文档已有的列表。参考文档中未被使用的页眉页脚不会导入。
+//
+// 示例:
+//
+//	ref, err := document.Open("template.dotx")
+//	if err != nil {
+//		return err
+//	}
+//	doc := document.New()
+//	doc.AddHeadingParagraph("标题", 1)
+//	err = doc.ApplyStylesFrom(ref, nil)
+func (d *Document) ApplyStylesFrom(ref *Document, opts *ApplyStylesOptions) error {
+	if ref == nil {
+		return NewValidationError("reference", "", "参考文档不能为空")
+	}
+	if opts == nil {
+		opts = &ApplyStylesOptions{}
+	}
+
+	// 参考文档打开时补充的预定义样式不导入
+	stylesXML, err := ref.styleManager.MarshalStylesXML(func(string) bool { return false })
+	if err != nil {
+		return WrapError("apply_styles_from", err)
+	}
+	result, err := d.styleManager.MergeStyles(stylesXML, &style.MergeOptions{ConflictPolicy: opts.ConflictPolicy})
+	if err != nil {
+		return WrapError("apply_styles_from", err)
+	}
+	d.renameStyleReferences(result.Remapped)
+
+	numIDs := make(map[string]string)
+	if !opts.SkipNumbering {
+		numIDs = d.importNumbering(ref, result.Renamed)
+	}
+	for _, styleID := range result.Imported {
+		s := d.styleManager.GetStyle(styleID)
+		if s == nil || s.ParagraphPr == nil || s.ParagraphPr.NumPr == nil || s.ParagraphPr.NumPr.NumID == nil {
+			continue
+		}
+		if newID, ok := numIDs[s.ParagraphPr.NumPr.NumID.Val]; ok {
+			s.ParagraphPr.NumPr.NumID = &style.NumPrID{Val: newID}
+		} else if s.ParagraphPr.NumPr.NumID.Val != "0" {
+			Debugf("样式 %s 引用的编号定义未导入，移除编号属性", styleID)
+			s.ParagraphPr.NumPr = nil
+		}
+	}
+
+	if !opts.SkipTheme {
+		if theme := ref.Theme(); theme != nil {
+			if err := d.ApplyTheme(theme); err != nil {
+				return WrapError("apply_styles_from", err)
+			}
+		}
+	}
+	if !opts.SkipFonts {
+		if err := d.importFonts(ref); err != nil {
+			return WrapError("apply_styles_from", err)
+		}
+	}
+
+	refSectPr := lastSectionProperties(ref.Body)
+	if !opts.SkipPageSettings && refSectPr != nil {
+		d.importPageSettings(refSectPr)
+	}
+	if !opts.SkipHeadersFooters && refSectPr != nil {
+		if err := ref.serializeHeaderFooters(); err != nil {
+			return WrapError("apply_styles_from", err)
+		}
+		for _, headerFooterType := range []HeaderFooterType{HeaderFooterTypeDefault, HeaderFooterTypeFirst, HeaderFooterTypeEven} {
+			for _, isHeader := range []bool{true, false} {
+				if err := d.importHeaderFooter(ref, headerFooterType, isHeader, result.Renamed); err != nil {
+					return WrapError("apply_styles_from", err)
+				}
+			}
+		}
+		if refSectPr.TitlePage != nil {
+			d.SetDifferentFirstPage(true)
+		}
+		if ref.Settings().EvenAndOddHeaders {
+			d.SetDifferentOddEvenPages(true)
+		}
+	}
+
+	Infof("已从参考文档导入样式: %d 个样式，%d 个编号定义", len(result.Imported), len(numIDs))
+	return nil
+}
+
+// lastSectionProperties 返回正文最后一节的节属性，不存在时返回nil
+func lastSectionProperties(body *Body) *SectionProperties {
+	if body == nil {
+		return nil
+	}
+	var sectPr *SectionProperties
+	for _, element := range body.Elements {
+		if s, ok := element.(*SectionProperties); ok {
+			sectPr = s
+		}
+	}
+	return sectPr
+}
+
+// importNumbering 以新的ID导入参考文档的编号定义，返回编号实例ID的映射（参考文档ID -> 新ID）
+// styleIDs 为参考文档样式ID的变化，用于更新编号级别关联的段落样式
+func (d *Document) importNumbering(ref *Document, styleIDs map[string]string) map[string]string {
+	numIDs := make(map[string]string)
+	if ref.numberingManager == nil || len(ref.numberingManager.numInstances) == 0 {
+		return numIDs
+	}
+
+	// 通过序列化复制编号定义，避免与参考文档共享对象
+	data, err := ref.numberingManager.marshal()
+	if err != nil {
+		Warnf("复制参考文档编号定义失败: %v", err)
+		return numIDs
+	}
+	numbering, err := parseNumberingXML(data)
+	if err != nil {
+		Warnf("复制参考文档编号定义失败: %v", err)
+		return numIDs
+	}
+
+	d.ensureNumberingInitialized()
+	manager := d.getNumberingManager()
+	renameStyle := func(value *NumberingValue) {
+		if value != nil {
+			if newID, ok := styleIDs[value.Val]; ok {
+				value.Val = newID
+			}
+		}
+	}
+
+	abstractIDs := make(map[string]string, len(numbering.AbstractNums))
+	for _, abstractNum := range numbering.AbstractNums {
+		newID := strconv.Itoa(manager.nextAbstractNumID)
+		manager.nextAbstractNumID++
+		abstractIDs[abstractNum.AbstractNumID] = newID
+		abstractNum.AbstractNumID = newID
+		renameStyle(abstractNum.StyleLink)
+		renameStyle(abstractNum.NumStyleLink)
+		for _, level := range abstractNum.Levels {
+			if level.PStyle != nil {
+				if newStyleID, ok := styleIDs[level.PStyle.Val]; ok {
+					level.PStyle.Val = newStyleID
+				}
+			}
+		}
+		manager.abstractNums["abstract_"+newID] = abstractNum
+	}
+	for _, instance := range numbering.NumberingInstances {
+		newID := strconv.Itoa(manager.nextNumID)
+		manager.nextNumID++
+		numIDs[instance.NumID] = newID
+		instance.NumID = newID
+		if instance.AbstractNumID != nil {
+			instance.AbstractNumID.Val = abstractIDs[instance.AbstractNumID.Val]
+		}
+		manager.numInstances[newID] = instance
+	}
+
+	d.updateNumberingFile()
+	return numIDs
+}
+
+// importPageSettings 复制参考文档的页面尺寸、边距、边框、行号、页码格式、分栏、垂直对齐和文档网格
+func (d *Document) importPageSettings(refSectPr *SectionProperties) {
+	source := cloneSectionProperties(refSectPr)
+	sectPr := d.getSectionProperties()
+	sectPr.PageSize = source.PageSize
+	sectPr.PageMargins = source.PageMargins
+	sectPr.PageBorders = source.PageBorders
+	sectPr.LineNumType = source.LineNumType
+	sectPr.PageNumType = source.PageNumType
+	sectPr.Columns = source.Columns
+	sectPr.VerticalAlign = source.VerticalAlign
+	sectPr.DocGrid = source.DocGrid
+}
+
+// importHeaderFooter 复制参考文档指定类型的页眉（isHeader为真）或页脚，包括其中的图片
+// 本文档已有同类型的页眉页脚时被替换
+func (d *Document) importHeaderFooter(ref *Document, headerFooterType HeaderFooterType, isHeader bool, styleIDs map[string]string) error {
+	// 写回参考文档中已编辑的页眉页脚，然后读取部件
+	if err := ref.serializeHeaderFooters(); err != nil {
+		return WrapError("import_header_footer", err)
+	}
+	refPart := ref.headerFooterPartName(headerFooterType, isHeader)
+	data, ok := ref.parts[refPart]
+	if refPart == "" || !ok {
+		return nil
+	}
+
+	prefix, contentType := "footer", "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+	if isHeader {
+		prefix, contentType = "header", "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	}
+	partName, relationID := d.prepareHeaderFooterPart(prefix, headerFooterType, isHeader)
+	delete(d.headers, partName)
+	delete(d.footers, partName)
+	// 样式引用直接在XML中更新，避免解析为对象后丢失未建模的内容
+	d.parts[partName] = renameStylesInXML(append([]byte(nil), data...), styleIDs)
+	d.addContentType(partName, contentType)
+
+	// 复制页眉页脚引用的图片，文件名与本文档已有图片冲突时重命名
+	if relsData, ok := ref.parts[headerFooterRelsPartName(refPart)]; ok {
+		var relationships Relationships
+		if err := xml.Unmarshal(relsData, &relationships); err != nil {
+			return WrapErrorWithContext("import_header_footer", err, refPart)
+		}
+		for i, rel := range relationships.Relationships {
+			if rel.Type != imageRelationshipType {
+				continue
+			}
+			imageData, ok := ref.parts[path.Join(path.Dir(refPart), rel.Target)]
+			if !ok {
+				continue
+			}
+			target := rel.Target
+			ext := path.Ext(target)
+			for n := 2; ; n++ {
+				existing, exists := d.parts[path.Join("word", target)]
+				if !exists || string(existing) == string(imageData) {
+					break
+				}
+				target = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(rel.Target, ext), n, ext)
+			}
+			d.parts[path.Join("word", target)] = imageData
+			if format, err := detectImageFormat(imageData); err == nil {
+				d.addImageContentType(format)
+			}
+			relationships.Relationships[i].Target = target
+		}
+		relationships.Xmlns = "http://schemas.openxmlformats.org/package/2006/relationships"
+		output, err := xml.MarshalIndent(relationships, "", "  ")
+		if err != nil {
+			return WrapErrorWithContext("import_header_footer", err, refPart)
+		}
+		d.parts[headerFooterRelsPartName(partName)] = append([]byte(xml.Header), output...)
+	} else {
+		delete(d.parts, headerFooterRelsPartName(partName))
+	}
+
+	if isHeader {
+		d.addHeaderReference(headerFooterType, relationID)
+	} else {
+		d.addFooterReference(headerFooterType, relationID)
+	}
+	Debugf("导入参考文档的%s: %s -> %s", prefix, refPart, partName)
+	return nil
+}
+
+// fontRelationshipIDPattern 匹配嵌入字体元素中的关系ID属性（r:id）
+var fontRelationshipIDPattern = regexp.MustCompile(`(\w+:id=")([^"]*)(")`)
+
+// importFonts 导入参考文档字体表中本文档没有的字体，包括嵌入的字体数据
+func (d *Document) importFonts(ref *Document) error {
+	if ref.fonts == nil {
+		if _, ok := ref.parts[fontTablePartName]; !ok {
+			return nil
+		}
+	}
+	// 写回参考文档待保存的嵌入字体，然后读取字体表的快照
+	if err := ref.serializeFontTable(); err != nil {
+		return err
+	}
+	refTable, err := parseFontTableXML(ref.parts[fontTablePartName])
+	if err != nil {
+		return err
+	}
+	refRels := &Relationships{}
+	if data, ok := ref.parts[fontTableRelsPartName]; ok {
+		if err := xml.Unmarshal(data, refRels); err != nil {
+			return err
+		}
+	}
+
+	table := d.getFontTable()
+	embedded := false
+	for _, refEntry := range refTable.fonts {
+		if table.font(refEntry.name) != nil {
+			continue
+		}
+		entry := &fontEntry{name: refEntry.name, start: refEntry.start, endName: refEntry.endName}
+		for _, child := range refEntry.children {
+			raw := child.raw
+			if strings.HasPrefix(child.name.Local, "embed") {
+				match := fontRelationshipIDPattern.FindSubmatch(raw)
+				if match == nil {
+					continue
+				}
+				fontData, ok := ref.parts[fontRelationshipPartName(refRels, string(match[2]))]
+				if !ok {
+					continue
+				}
+				partName := table.nextFontPartName(d.parts)
+				relID := nextRelationshipID(table.relationships)
+				table.relationships.Relationships = append(table.relationships.Relationships, Relationship{
+					ID:     relID,
+					Type:   fontRelationshipType,
+					Target: strings.TrimPrefix(partName, "word/"),
+				})
+				// 混淆密钥保存在 w:fontKey 中，字体数据可直接复制
+				d.parts[partName] = append([]byte(nil), fontData...)
+				raw = fontRelationshipIDPattern.ReplaceAll(raw, []byte("${1}"+relID+"${3}"))
+				embedded = true
+			}
+			entry.children = append(entry.children, &settingsElement{name: child.name, raw: raw})
+		}
+		table.fonts = append(table.fonts, entry)
+	}
+
+	if embedded {
+		if len(table.rootStart) > 0 && !strings.Contains(string(table.rootStart), `xmlns:r="`) {
+			table.rootStart = []byte(strings.TrimSuffix(string(table.rootStart), ">") + ` xmlns:r="` + officeRelationshipsNS + `">`)
+		}
+		d.ensureObfuscatedFontContentType()
+		d.Settings().EmbedTrueTypeFonts = true
+	}
+	return nil
+}
+
+// fontRelationshipPartName 根据字体表关系ID获取嵌入字体的部件名称
+func fontRelationshipPartName(relationships *Relationships, relID string) string {
+	for _, rel := range relationships.Relationships {
+		if rel.ID == relID {
+			return "word/" + strings.TrimPrefix(rel.Target, "/word/")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,191 @@
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// newReferenceDocument 创建使用中文版Word样式ID的参考文档，包含标题编号、页眉、页面设置和主题
+func newReferenceDocument(t *testing.T) *Document {
+	t.Helper()
+	data := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:eastAsia="仿宋_GB2312"/><w:sz w:val="32"/></w:rPr></w:rPrDefault></w:docDefaults>
+  <w:style w:type="paragraph" w:default="1" w:styleId="a"><w:name w:val="Normal"/><w:qFormat/></w:style>
+  <w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/><w:basedOn w:val="a"/><w:next w:val="a"/><w:qFormat/><w:rPr><w:color w:val="C00000"/><w:sz w:val="44"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="a3"><w:name w:val="header"/><w:basedOn w:val="a"/><w:pPr><w:jc w:val="center"/></w:pPr></w:style>
+</w:styles>`)
+
+	ref := New()
+	if err := ref.styleManager.LoadStylesFromDocument(data); err != nil {
+		t.Fatalf("加载参考样式失败: %v", err)
+	}
+	ref.parts["word/styles.xml"] = data
+	if err := ref.SetHeadingNumbering(HeadingNumberingLegal); err != nil {
+		t.Fatalf("设置标题编号失败: %v", err)
+	}
+	header := createStandardHeader()
+	header.AddParagraph("品牌页眉").SetStyle("a3")
+	if err := ref.storeHeader(HeaderFooterTypeDefault, header); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	settings := DefaultPageSettings()
+	settings.Orientation = OrientationLandscape
+	if err := ref.SetPageSettings(settings); err != nil {
+		t.Fatalf("设置页面失败: %v", err)
+	}
+	theme := DefaultTheme()
+	theme.Colors.Accent1 = "C00000"
+	if err := ref.ApplyTheme(theme); err != nil {
+		t.Fatalf("设置主题失败: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "reference.docx")
+	if err := ref.Save(path); err != nil {
+		t.Fatalf("保存参考文档失败: %v", err)
+	}
+	opened, err := Open(path)
+	if err != nil {
+		t.Fatalf("打开参考文档失败: %v", err)
+	}
+	return opened
+}
+
+// TestApplyStylesFrom 测试从参考文档导入样式、编号、主题、页面设置和页眉页脚
+func TestApplyStylesFrom(t *testing.T) {
+	ref := newReferenceDocument(t)
+
+	doc := New()
+	heading := doc.AddHeadingParagraph("第一章", 1)
+	item := doc.AddNumberedList("条目", 0, ListTypeDecimal)
+	listNumID := item.Properties.NumberingProperties.NumID.Val
+
+	if err := doc.ApplyStylesFrom(ref, nil); err != nil {
+		t.Fatalf("导入参考文档失败: %v", err)
+	}
+
+	sm := doc.GetStyleManager()
+	if heading.Properties.ParagraphStyle.Val != "1" || sm.GetStyle("Heading1") != nil {
+		t.Errorf("标题样式引用应改为参考文档的ID: %s", heading.Properties.ParagraphStyle.Val)
+	}
+	if h1 := sm.GetStyle("1"); h1 == nil || h1.RunPr.Color.Val != "C00000" || h1.BasedOn.Val != "a" {
+		t.Errorf("参考文档的标题样式导入不正确: %+v", h1)
+	}
+	if sm.GetStyle("Normal") != nil || sm.GetDefaultStyle(style.StyleTypeParagraph).StyleID != "a" {
+		t.Error("默认段落样式应为参考文档的样式")
+	}
+	if sm.GetDocumentDefaults().RunProperties().FontSize.Val != "32" {
+		t.Error("文档默认格式应来自参考文档")
+	}
+	if next := doc.AddHeadingParagraph("第二章", 1); next.Properties.ParagraphStyle.Val != "1" {
+		t.Errorf("导入后新增的标题应使用参考文档的标题样式: %s", next.Properties.ParagraphStyle.Val)
+	}
+
+	// 编号定义以新ID导入，正文中已有列表的编号不变
+	numPr := sm.GetStyle("1").ParagraphPr.NumPr
+	if numPr == nil || numPr.NumID.Val == listNumID {
+		t.Fatalf("标题样式的编号应指向新导入的编号定义: %+v", numPr)
+	}
+	definitions := make(map[string]*ListDefinition)
+	for _, definition := range doc.GetListDefinitions() {
+		definitions[definition.NumID] = definition
+	}
+	if definitions[listNumID] == nil || definitions[numPr.NumID.Val] == nil ||
+		definitions[listNumID].AbstractNum == definitions[numPr.NumID.Val].AbstractNum {
+		t.Errorf("编号定义导入不正确: %v", definitions)
+	}
+
+	if header := doc.GetHeader(HeaderFooterTypeDefault); header == nil || header.GetText() != "品牌页眉" ||
+		header.GetParagraphs()[0].Properties.ParagraphStyle.Val != "a3" {
+		t.Error("页眉应从参考文档导入")
+	}
+	if doc.GetPageSettings().Orientation != OrientationLandscape {
+		t.Error("页面设置应从参考文档导入")
+	}
+	if theme := doc.Theme(); theme == nil || theme.Colors.Accent1 != "C00000" {
+		t.Error("主题应从参考文档导入")
+	}
+
+	path := filepath.Join(t.TempDir(), "applied.docx")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("重新打开文档失败: %v", err)
+	}
+	stylesXML := string(reopened.parts["word/styles.xml"])
+	if !strings.Contains(stylesXML, `w:styleId="1"`) || strings.Contains(stylesXML, `w:styleId="Heading1"`) {
+		t.Errorf("保存的样式表不正确:\n%s", stylesXML)
+	}
+}
+
+// TestApplyStylesFromHeaderXML 测试导入的页眉保留原始XML，只更新样式引用
+func TestApplyStylesFromHeaderXML(t *testing.T) {
+	ref := newReferenceDocument(t)
+	refPart := ref.headerFooterPartName(HeaderFooterTypeDefault, true)
+	ref.parts[refPart] = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:pPr><w:pStyle w:val="a3"/><w:tabs><w:tab w:val="right" w:pos="9000"/></w:tabs></w:pPr><w:bookmarkStart w:id="0" w:name="top"/><w:r><w:t>品牌页眉</w:t></w:r><w:bookmarkEnd w:id="0"/></w:p></w:hdr>`)
+
+	doc := New()
+	doc.GetStyleManager().AddStyle(&style.Style{Type: string(style.StyleTypeCharacter), StyleID: "a3", Name: &style.StyleName{Val: "自定义"}})
+	doc.parts["word/_rels/header1.xml.rels"] = []byte(`<Relationships/>`)
+	if err := doc.ApplyStylesFrom(ref, &ApplyStylesOptions{ConflictPolicy: style.ConflictRename}); err != nil {
+		t.Fatalf("导入参考文档失败: %v", err)
+	}
+
+	partName := doc.headerFooterPartName(HeaderFooterTypeDefault, true)
+	if len(doc.headers) != 0 {
+		t.Error("导入页眉不应解析为对象")
+	}
+	if _, ok := doc.parts[headerFooterRelsPartName(partName)]; ok {
+		t.Error("参考页眉没有关系部件时不应保留旧的关系部件")
+	}
+	output := string(doc.parts[partName])
+	if strings.Contains(output, `w:val="a3"`) || !strings.Contains(output, "<w:tabs>") || !strings.Contains(output, "<w:bookmarkStart") {
+		t.Errorf("导入的页眉应保留原始内容并更新样式引用: %s", output)
+	}
+}
+
+// TestApplyStylesFromConflictPolicy 测试保留现有样式和重命名两种冲突策略
+func TestApplyStylesFromConflictPolicy(t *testing.T) {
+	ref := newReferenceDocument(t)
+
+	doc := New()
+	heading := doc.AddHeadingParagraph("标题", 1)
+	err := doc.ApplyStylesFrom(ref, &ApplyStylesOptions{
+		ConflictPolicy:     style.ConflictKeepExisting,
+		SkipHeadersFooters: true,
+		SkipPageSettings:   true,
+	})
+	if err != nil {
+		t.Fatalf("导入参考文档失败: %v", err)
+	}
+	sm := doc.GetStyleManager()
+	if heading.Properties.ParagraphStyle.Val != "Heading1" || sm.GetStyle("1") != nil || sm.GetStyle("a3") == nil {
+		t.Error("保留现有样式时只应导入本文档没有的样式")
+	}
+	if sm.GetStyle("a3").BasedOn.Val != "Normal" {
+		t.Errorf("导入样式的基础样式应改为本文档的同名样式: %s", sm.GetStyle("a3").BasedOn.Val)
+	}
+	if doc.GetHeader(HeaderFooterTypeDefault) != nil || doc.GetPageSettings().Orientation == OrientationLandscape {
+		t.Error("跳过的内容不应导入")
+	}
+
+	renamed := New()
+	renamed.GetStyleManager().AddStyle(&style.Style{Type: string(style.StyleTypeCharacter), StyleID: "a3", Name: &style.StyleName{Val: "自定义"}})
+	if err := renamed.ApplyStylesFrom(ref, &ApplyStylesOptions{ConflictPolicy: style.ConflictRename}); err != nil {
+		t.Fatalf("导入参考文档失败: %v", err)
+	}
+	header := renamed.GetHeader(HeaderFooterTypeDefault)
+	importedID := header.GetParagraphs()[0].Properties.ParagraphStyle.Val
+	if importedID == "a3" || renamed.GetStyleManager().GetStyle(importedID).Name.Val != "header (2)" {
+		t.Errorf("ID冲突的导入样式应被重命名，页眉引用同步更新: %s", importedID)
+	}
+	if h1 := renamed.GetStyleManager().GetStyle("1"); h1 == nil || h1.Name.Val != "heading 1 (2)" {
+		t.Errorf("名称冲突的导入样式应使用新名称: %+v", h1)
+	}
+}
@@ -0,0 +1,737 @@
+// Package document 提供文档设置（settings.xml）的读写功能
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Settings 文档设置（word/settings.xml）
+//
+// 通过 Document.Settings 获取，修改后在保存文档时写回。未建模的设置元素
+// （如 w:proofState、w:docVars、w14/w15 扩展元素等）以及根元素上的命名空间
+// 声明会原样保留，布尔字段为 false、指针字段为 nil、字符串字段为空时
+// 对应元素不会输出。
+type Settings struct {
+	// Zoom 显示比例
+	Zoom *Zoom
+	// DisplayBackgroundShape 显示页面背景
+	DisplayBackgroundShape bool
+	// EmbedTrueTypeFonts 在文档中嵌入TrueType字体
+	EmbedTrueTypeFonts bool
+	// SaveSubsetFonts 仅嵌入使用到的字符
+	SaveSubsetFonts bool
+	// MirrorMargins 对称页边距
+	MirrorMargins bool
+	// TrackRevisions 修订跟踪
+	TrackRevisions bool
+	// DocumentProtection 文档保护
+	DocumentProtection *DocumentProtection
+	// DefaultTabStop 默认制表位（缇）
+	DefaultTabStop *DefaultTabStop
+	// AutoHyphenation 自动断字
+	AutoHyphenation bool
+	// DefaultTableStyle 新建表格的默认样式ID
+	DefaultTableStyle string
+	// EvenAndOddHeaders 奇偶页使用不同的页眉页脚
+	EvenAndOddHeaders bool
+	// CharacterSpacingControl 字符间距控制（doNotCompress、compressPunctuation 等）
+	CharacterSpacingControl *CharacterSpacingControl
+	// UpdateFields 打开文档时提示更新域（目录、页码引用等）
+	UpdateFields bool
+	// FootnotePr 文档级脚注属性
+	FootnotePr *FootnotePr
+	// EndnotePr 文档级尾注属性
+	EndnotePr *EndnotePr
+	// Compat 兼容性选项
+	Compat *Compat
+	// Rsids 修订会话标识
+	Rsids *Rsids
+	// ThemeFontLang 主题字体语言
+	ThemeFontLang *ThemeFontLang
+	// DecimalSymbol 小数点符号
+	DecimalSymbol string
+	// ListSeparator 列表分隔符
+	ListSeparator string
+
+	rootName  string             // 根元素名称（含前缀）
+	rootStart []byte             // 原始根元素开始标签（保留命名空间声明和 mc:Ignorable）
+	elements  []*settingsElement // 未建模的子元素，按原文档顺序
+}
+
+// DefaultTabStop 默认制表位设置
+type DefaultTabStop struct {
+	XMLName xml.Name `xml:"w:defaultTabStop"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// CharacterSpacingControl 字符间距控制
+type CharacterSpacingControl struct {
+	XMLName xml.Name `xml:"w:characterSpacingControl"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Zoom 显示比例设置
+type Zoom struct {
+	XMLName xml.Name `xml:"w:zoom"`
+	Val     string   `xml:"w:val,attr,omitempty"` // 预设缩放（none、fullPage、bestFit、textFit）
+	Percent string   `xml:"w:percent,attr"`       // 缩放百分比
+}
+
+// DocumentProtection 文档保护设置
+type DocumentProtection struct {
+	XMLName     xml.Name `xml:"w:documentProtection"`
+	Edit        string   `xml:"w:edit,attr,omitempty"`        // 允许的编辑类型（readOnly、comments、trackedChanges、forms）
+	Formatting  string   `xml:"w:formatting,attr,omitempty"`  // 是否限制格式设置
+	Enforcement string   `xml:"w:enforcement,attr,omitempty"` // 是否强制保护
+
+	// 密码哈希（Word 2010 及以后版本）
+	AlgorithmName string `xml:"w:algorithmName,attr,omitempty"`
+	HashValue     string `xml:"w:hashValue,attr,omitempty"`
+	SaltValue     string `xml:"w:saltValue,attr,omitempty"`
+	SpinCount     string `xml:"w:spinCount,attr,omitempty"`
+
+	// 密码哈希（旧版格式）
+	CryptProviderType   string `xml:"w:cryptProviderType,attr,omitempty"`
+	CryptAlgorithmClass string `xml:"w:cryptAlgorithmClass,attr,omitempty"`
+	CryptAlgorithmType  string `xml:"w:cryptAlgorithmType,attr,omitempty"`
+	CryptAlgorithmSid   string `xml:"w:cryptAlgorithmSid,attr,omitempty"`
+	CryptSpinCount      string `xml:"w:cryptSpinCount,attr,omitempty"`
+	Hash                string `xml:"w:hash,attr,omitempty"`
+	Salt                string `xml:"w:salt,attr,omitempty"`
+}
+
+// Compat 兼容性选项
+type Compat struct {
+	// Settings 兼容性设置（w:compatSetting），如 compatibilityMode
+	Settings []*CompatSetting
+
+	legacy [][]byte // 旧版兼容性开关元素（原样保留）
+}
+
+// CompatSetting 兼容性设置项
+type CompatSetting struct {
+	XMLName xml.Name `xml:"w:compatSetting"`
+	Name    string   `xml:"w:name,attr"`
+	URI     string   `xml:"w:uri,attr"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Rsids 修订会话标识（Revision Save ID）
+type Rsids struct {
+	RsidRoot string   // 创建文档时的会话标识
+	Rsids    []string // 各次编辑会话的标识
+}
+
+// ThemeFontLang 主题字体语言
+type ThemeFontLang struct {
+	XMLName  xml.Name `xml:"w:themeFontLang"`
+	Val      string   `xml:"w:val,attr,omitempty"`      // 西文语言，如 en-US
+	EastAsia string   `xml:"w:eastAsia,attr,omitempty"` // 东亚语言，如 zh-CN
+	Bidi     string   `xml:"w:bidi,attr,omitempty"`     // 双向文字语言，如 ar-SA
+}
+
+// compatSettingURI Word 兼容性设置的命名空间
+const compatSettingURI = "http://schemas.microsoft.com/office/word"
+
+// settingsElement 未建模的设置元素
+type settingsElement struct {
+	name xml.Name // 元素名称（Space为前缀）
+	raw  []byte   // 原始XML
+}
+
+// settingsNode 设置元素的简单节点树，用于解析已建模的元素
+type settingsNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*settingsNode
+}
+
+// settingsSchemaPrefixes 属于 CT_Settings 定义的元素前缀（w、m:mathPr、sl:schemaLibrary）
+var settingsSchemaPrefixes = map[string]bool{"w": true, "m": true, "sl": true}
+
+// settingsElementOrder CT_Settings 子元素顺序
+var settingsElementOrder = []string{
+	"writeProtection", "view", "zoom", "removePersonalInformation", "removeDateAndTime",
+	"doNotDisplayPageBoundaries", "displayBackgroundShape", "printPostScriptOverText",
+	"printFractionalCharacterWidth", "printFormsData", "embedTrueTypeFonts", "embedSystemFonts",
+	"saveSubsetFonts", "saveFormsData", "mirrorMargins", "alignBordersAndEdges",
+	"bordersDoNotSurroundHeader", "bordersDoNotSurroundFooter", "gutterAtTop", "hideSpellingErrors",
+	"hideGrammaticalErrors", "activeWritingStyle", "proofState", "formsDesign", "attachedTemplate",
+	"linkStyles", "stylePaneFormatFilter", "stylePaneSortMethod", "documentType", "mailMerge",
+	"revisionView", "trackRevisions", "doNotTrackMoves", "doNotTrackFormatting", "documentProtection",
+	"autoFormatOverride", "styleLockTheme", "styleLockQFSet", "defaultTabStop", "autoHyphenation",
+	"consecutiveHyphenLimit", "hyphenationZone", "doNotHyphenateCaps", "showEnvelope", "summaryLength",
+	"clickAndTypeStyle", "defaultTableStyle", "evenAndOddHeaders", "bookFoldRevPrinting",
+	"bookFoldPrinting", "bookFoldPrintingSheets", "drawingGridHorizontalSpacing",
+	"drawingGridVerticalSpacing", "displayHorizontalDrawingGridEvery", "displayVerticalDrawingGridEvery",
+	"doNotUseMarginsForDrawingGridOrigin", "drawingGridHorizontalOrigin", "drawingGridVerticalOrigin",
+	"doNotShadeFormData", "noPunctuationKerning", "characterSpacingControl", "printTwoOnOne",
+	"strictFirstAndLastChars", "noLineBreaksAfter", "noLineBreaksBefore", "savePreviewPicture",
+	"doNotValidateAgainstSchema", "saveInvalidXml", "ignoreMixedContent", "alwaysShowPlaceholderText",
+	"doNotDemarcateInvalidXml", "saveXmlDataOnly", "useXSLTWhenSaving", "saveThroughXslt",
+	"showXMLTags", "alwaysMergeEmptyNamespace", "updateFields", "hdrShapeDefaults", "footnotePr",
+	"endnotePr", "compat", "docVars", "rsids", "mathPr", "attachedSchema", "themeFontLang",
+	"clrSchemeMapping", "doNotIncludeSubdocsInStats", "doNotAutoCompressPictures", "forceUpgrade",
+	"captions", "readModeInkLockDown", "smartTagType", "schemaLibrary", "shapeDefaults",
+	"doNotEmbedSmartTags", "decimalSymbol", "listSeparator",
+}
+
+// Settings 获取文档设置
+//
+// 打开的文档返回从 word/settings.xml 解析的设置，新建文档返回默认设置。
+// 对返回值的修改会在保存文档时写回 settings.xml。
+//
+// 示例:
+//
+//	settings := doc.Settings()
+//	settings.UpdateFields = true
+//	settings.EvenAndOddHeaders = true
+//	settings.SetZoom(120)
+//	settings.SetCompatibilityMode(15)
+func (d *Document) Settings() *Settings {
+	if d.settings != nil {
+		return d.settings
+	}
+
+	if data, ok := d.parts["word/settings.xml"]; ok && len(data) > 0 {
+		settings, err := parseSettingsXML(data)
+		if err == nil {
+			d.settings = settings
+			return d.settings
+		}
+		Warnf("解析settings.xml失败，使用默认设置: %v", err)
+	}
+
+	d.initializeSettings()
+	return d.settings
+}
+
+// SetZoom 设置显示比例（百分比）
+func (s *Settings) SetZoom(percent int) {
+	s.Zoom = &Zoom{Percent: strconv.Itoa(percent)}
+}
+
+// CompatibilityMode 获取兼容模式（如 15 表示 Word 2013 及以上，14 表示 Word 2010），未设置时返回0
+func (s *Settings) CompatibilityMode() int {
+	if s.Compat == nil {
+		return 0
+	}
+	for _, setting := range s.Compat.Settings {
+		if setting.Name == "compatibilityMode" {
+			mode, _ := strconv.Atoi(setting.Val)
+			return mode
+		}
+	}
+	return 0
+}
+
+// SetCompatibilityMode 设置兼容模式
+func (s *Settings) SetCompatibilityMode(mode int) {
+	if s.Compat == nil {
+		s.Compat = &Compat{}
+	}
+	for _, setting := range s.Compat.Settings {
+		if setting.Name == "compatibilityMode" {
+			setting.Val = strconv.Itoa(mode)
+			return
+		}
+	}
+	s.Compat.Settings = append(s.Compat.Settings, &CompatSetting{
+		Name: "compatibilityMode",
+		URI:  compatSettingURI,
+		Val:  strconv.Itoa(mode),
+	})
+}
+
+// ensureSettingsInitialized 确保文档设置已初始化
+func (d *Document) ensureSettingsInitialized() {
+	// 检查settings.xml是否存在，如果不存在则创建默认设置
+	if _, exists := d.parts["word/settings.xml"]; !exists {
+		d.initializeSettings()
+	}
+}
+
+// initializeSettings 初始化文档设置
+func (d *Document) initializeSettings() {
+	// 创建默认设置
+	if err := d.saveSettings(d.createDefaultSettings()); err != nil {
+		Errorf("初始化settings.xml失败: %v", err)
+	}
+
+	// 添加内容类型
+	d.addContentType("word/settings.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml")
+
+	// 添加关系
+	d.addSettingsRelationship()
+}
+
+// createDefaultSettings 创建默认设置
+func (d *Document) createDefaultSettings() *Settings {
+	return &Settings{
+		DefaultTabStop: &DefaultTabStop{
+			Val: "708",
+		},
+		CharacterSpacingControl: &CharacterSpacingControl{
+			Val: "doNotCompress",
+		},
+	}
+}
+
+// saveSettings 保存settings.xml文件
+func (d *Document) saveSettings(settings *Settings) error {
+	d.settings = settings
+	return d.serializeSettings()
+}
+
+// serializeSettings 将文档设置写回 word/settings.xml（未访问过设置时保持原文件不变）
+func (d *Document) serializeSettings() error {
+	if d.settings == nil {
+		return nil
+	}
+
+	data, err := d.settings.marshal()
+	if err != nil {
+		return WrapError("serialize_settings", err)
+	}
+	d.parts["word/settings.xml"] = data
+	return nil
+}
+
+// addSettingsRelationship 添加设置文件关系
+func (d *Document) addSettingsRelationship() {
+	const settingsType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type == settingsType {
+			return
+		}
+	}
+
+	relationship := Relationship{
+		ID:     fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2), // +2因为rId1保留给styles
+		Type:   settingsType,
+		Target: "settings.xml",
+	}
+	d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, relationship)
+}
+
+// clone 深拷贝文档设置
+func (s *Settings) clone() *Settings {
+	data, err := s.marshal()
+	if err != nil {
+		return nil
+	}
+	cloned, err := parseSettingsXML(data)
+	if err != nil {
+		return nil
+	}
+	return cloned
+}
+
+// parseSettingsXML 解析 settings.xml，已建模的元素解析为字段，其余元素原样保留
+func parseSettingsXML(data []byte) (*Settings, error) {
+	settings := &Settings{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	var elementStart int64
+	var current *settingsElement
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, WrapError("parse_settings", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 1:
+				settings.rootName = qualifiedName(t.Name)
+				root := string(data[offset:decoder.InputOffset()])
+				if strings.HasSuffix(root, "/>") {
+					root = strings.TrimSuffix(root, "/>") + ">"
+				}
+				settings.rootStart = []byte(root)
+			case 2:
+				elementStart = offset
+				current = &settingsElement{name: t.Name}
+			}
+		case xml.EndElement:
+			if depth == 2 && current != nil {
+				current.raw = append([]byte(nil), data[elementStart:decoder.InputOffset()]...)
+				if !settings.decodeElement(current) {
+					settings.elements = append(settings.elements, current)
+				}
+				current = nil
+			}
+			depth--
+		}
+	}
+
+	if settings.rootName == "" {
+		return nil, NewValidationError("settings.xml", "", "缺少w:settings根元素")
+	}
+	return settings, nil
+}
+
+// decodeElement 将已建模的元素解析到对应字段，返回false表示元素需原样保留
+func (s *Settings) decodeElement(element *settingsElement) bool {
+	if element.name.Space != "w" {
+		return false
+	}
+	node, err := parseSettingsNode(element.raw)
+	if err != nil {
+		return false
+	}
+	val := getAttributeValue(node.attrs, "val")
+
+	switch element.name.Local {
+	case "zoom":
+		s.Zoom = &Zoom{Val: val, Percent: getAttributeValue(node.attrs, "percent")}
+	case "displayBackgroundShape":
+		s.DisplayBackgroundShape = parseOnOff(val)
+	case "embedTrueTypeFonts":
+		s.EmbedTrueTypeFonts = parseOnOff(val)
+	case "saveSubsetFonts":
+		s.SaveSubsetFonts = parseOnOff(val)
+	case "mirrorMargins":
+		s.MirrorMargins = parseOnOff(val)
+	case "trackRevisions":
+		s.TrackRevisions = parseOnOff(val)
+	case "documentProtection":
+		s.DocumentProtection = &DocumentProtection{
+			Edit:                getAttributeValue(node.attrs, "edit"),
+			Formatting:          getAttributeValue(node.attrs, "formatting"),
+			Enforcement:         getAttributeValue(node.attrs, "enforcement"),
+			AlgorithmName:       getAttributeValue(node.attrs, "algorithmName"),
+			HashValue:           getAttributeValue(node.attrs, "hashValue"),
+			SaltValue:           getAttributeValue(node.attrs, "saltValue"),
+			SpinCount:           getAttributeValue(node.attrs, "spinCount"),
+			CryptProviderType:   getAttributeValue(node.attrs, "cryptProviderType"),
+			CryptAlgorithmClass: getAttributeValue(node.attrs, "cryptAlgorithmClass"),
+			CryptAlgorithmType:  getAttributeValue(node.attrs, "cryptAlgorithmType"),
+			CryptAlgorithmSid:   getAttributeValue(node.attrs, "cryptAlgorithmSid"),
+			CryptSpinCount:      getAttributeValue(node.attrs, "cryptSpinCount"),
+			Hash:                getAttributeValue(node.attrs, "hash"),
+			Salt:                getAttributeValue(node.attrs, "salt"),
+		}
+	case "defaultTabStop":
+		s.DefaultTabStop = &DefaultTabStop{Val: val}
+	case "autoHyphenation":
+		s.AutoHyphenation = parseOnOff(val)
+	case "defaultTableStyle":
+		s.DefaultTableStyle = val
+	case "evenAndOddHeaders":
+		s.EvenAndOddHeaders = parseOnOff(val)
+	case "characterSpacingControl":
+		s.CharacterSpacingControl = &CharacterSpacingControl{Val: val}
+	case "updateFields":
+		s.UpdateFields = parseOnOff(val)
+	case "footnotePr":
+		s.FootnotePr = &FootnotePr{}
+		for _, child := range node.children {
+			childVal := getAttributeValue(child.attrs, "val")
+			switch child.name.Local {
+			case "pos":
+				s.FootnotePr.Pos = &FootnotePos{Val: childVal}
+			case "numFmt":
+				s.FootnotePr.NumFmt = &FootnoteNumFmt{Val: childVal}
+			case "numStart":
+				s.FootnotePr.NumStart = &FootnoteNumStart{Val: childVal}
+			case "numRestart":
+				s.FootnotePr.NumRestart = &FootnoteNumRestart{Val: childVal}
+			case "footnote":
+				s.FootnotePr.SpecialFootnotes = append(s.FootnotePr.SpecialFootnotes,
+					&NoteSpecialRef{ID: getAttributeValue(child.attrs, "id")})
+			}
+		}
+	case "endnotePr":
+		s.EndnotePr = &EndnotePr{}
+		for _, child := range node.children {
+			childVal := getAttributeValue(child.attrs, "val")
+			switch child.name.Local {
+			case "pos":
+				s.EndnotePr.Pos = &EndnotePos{Val: childVal}
+			case "numFmt":
+				s.EndnotePr.NumFmt = &EndnoteNumFmt{Val: childVal}
+			case "numStart":
+				s.EndnotePr.NumStart = &EndnoteNumStart{Val: childVal}
+			case "numRestart":
+				s.EndnotePr.NumRestart = &EndnoteNumRestart{Val: childVal}
+			case "endnote":
+				s.EndnotePr.SpecialEndnotes = append(s.EndnotePr.SpecialEndnotes,
+					&NoteSpecialRef{ID: getAttributeValue(child.attrs, "id")})
+			}
+		}
+	case "compat":
+		s.Compat = parseCompat(element.raw, node)
+	case "rsids":
+		s.Rsids = &Rsids{}
+		for _, child := range node.children {
+			childVal := getAttributeValue(child.attrs, "val")
+			switch child.name.Local {
+			case "rsidRoot":
+				s.Rsids.RsidRoot = childVal
+			case "rsid":
+				s.Rsids.Rsids = append(s.Rsids.Rsids, childVal)
+			}
+		}
+	case "themeFontLang":
+		s.ThemeFontLang = &ThemeFontLang{
+			Val:      val,
+			EastAsia: getAttributeValue(node.attrs, "eastAsia"),
+			Bidi:     getAttributeValue(node.attrs, "bidi"),
+		}
+	case "decimalSymbol":
+		s.DecimalSymbol = val
+	case "listSeparator":
+		s.ListSeparator = val
+	default:
+		return false
+	}
+	return true
+}
+
+// parseCompat 解析兼容性选项，旧版开关元素按原始XML保留
+func parseCompat(raw []byte, node *settingsNode) *Compat {
+	compat := &Compat{}
+	for _, child := range node.children {
+		if child.name.Local == "compatSetting" {
+			compat.Settings = append(compat.Settings, &CompatSetting{
+				Name: getAttributeValue(child.attrs, "name"),
+				URI:  getAttributeValue(child.attrs, "uri"),
+				Val:  getAttributeValue(child.attrs, "val"),
+			})
+		}
+	}
+
+	// 重新扫描以截取旧版开关元素的原始XML
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	depth := 0
+	var start int64
+	var name string
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				start, name = offset, t.Name.Local
+			}
+		case xml.EndElement:
+			if depth == 2 && name != "compatSetting" {
+				compat.legacy = append(compat.legacy, append([]byte(nil), raw[start:decoder.InputOffset()]...))
+			}
+			depth--
+		}
+	}
+	return compat
+}
+
+// parseSettingsNode 将单个元素的原始XML解析为节点树
+func parseSettingsNode(raw []byte) (*settingsNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	var stack []*settingsNode
+	var root *settingsNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &settingsNode{name: t.Name, attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if root == nil {
+		return nil, NewValidationError("element", string(raw), "空元素")
+	}
+	return root, nil
+}
+
+// parseOnOff 解析 ST_OnOff 值，缺省表示开启
+func parseOnOff(val string) bool {
+	switch val {
+	case "false", "0", "off":
+		return false
+	default:
+		return true
+	}
+}
+
+// qualifiedName 返回带前缀的元素名称
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// marshal 按 CT_Settings 的元素顺序序列化文档设置
+func (s *Settings) marshal() ([]byte, error) {
+	typed, err := s.typedElements()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	rootName := s.rootName
+	if len(s.rootStart) > 0 {
+		buf.Write(s.rootStart)
+	} else {
+		rootName = "w:settings"
+		buf.WriteString(`<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	}
+
+	written := make(map[*settingsElement]bool)
+	for _, name := range settingsElementOrder {
+		if data, ok := typed[name]; ok {
+			buf.WriteString("\n  ")
+			buf.Write(data)
+			continue
+		}
+		for _, element := range s.elements {
+			if element.name.Local == name && settingsSchemaPrefixes[element.name.Space] && !written[element] {
+				buf.WriteString("\n  ")
+				buf.Write(element.raw)
+				written[element] = true
+			}
+		}
+	}
+
+	// 扩展元素（w14、w15等）位于末尾，保持原有顺序
+	for _, element := range s.elements {
+		if !written[element] {
+			buf.WriteString("\n  ")
+			buf.Write(element.raw)
+		}
+	}
+
+	buf.WriteString("\n</" + rootName + ">")
+	return buf.Bytes(), nil
+}
+
+// typedElements 序列化已建模的设置元素，键为元素本地名称
+func (s *Settings) typedElements() (map[string][]byte, error) {
+	elements := make(map[string][]byte)
+	var firstErr error
+	add := func(name string, v interface{}) {
+		data, err := xml.Marshal(v)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		elements[name] = data
+	}
+	addFlag := func(name string, on bool) {
+		if on {
+			elements[name] = []byte("<w:" + name + "/>")
+		}
+	}
+	addVal := func(name, val string) {
+		if val != "" {
+			add(name, &settingsVal{XMLName: xml.Name{Local: "w:" + name}, Val: val})
+		}
+	}
+
+	if s.Zoom != nil {
+		add("zoom", s.Zoom)
+	}
+	addFlag("displayBackgroundShape", s.DisplayBackgroundShape)
+	addFlag("embedTrueTypeFonts", s.EmbedTrueTypeFonts)
+	addFlag("saveSubsetFonts", s.SaveSubsetFonts)
+	addFlag("mirrorMargins", s.MirrorMargins)
+	addFlag("trackRevisions", s.TrackRevisions)
+	if s.DocumentProtection != nil {
+		add("documentProtection", s.DocumentProtection)
+	}
+	if s.DefaultTabStop != nil {
+		add("defaultTabStop", s.DefaultTabStop)
+	}
+	addFlag("autoHyphenation", s.AutoHyphenation)
+	addVal("defaultTableStyle", s.DefaultTableStyle)
+	addFlag("evenAndOddHeaders", s.EvenAndOddHeaders)
+	if s.CharacterSpacingControl != nil {
+		add("characterSpacingControl", s.CharacterSpacingControl)
+	}
+	addFlag("updateFields", s.UpdateFields)
+	if s.FootnotePr != nil {
+		add("footnotePr", s.FootnotePr)
+	}
+	if s.EndnotePr != nil {
+		add("endnotePr", s.EndnotePr)
+	}
+	if s.Compat != nil {
+		var compat bytes.Buffer
+		compat.WriteString("<w:compat>")
+		for _, legacy := range s.Compat.legacy {
+			compat.Write(legacy)
+		}
+		for _, setting := range s.Compat.Settings {
+			data, err := xml.Marshal(setting)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			compat.Write(data)
+		}
+		compat.WriteString("</w:compat>")
+		elements["compat"] = compat.Bytes()
+	}
+	if s.Rsids != nil {
+		rsids := &rsidsXML{}
+		if s.Rsids.RsidRoot != "" {
+			rsids.RsidRoot = &settingsVal{Val: s.Rsids.RsidRoot}
+		}
+		for _, rsid := range s.Rsids.Rsids {
+			rsids.Rsids = append(rsids.Rsids, &settingsVal{Val: rsid})
+		}
+		add("rsids", rsids)
+	}
+	if s.ThemeFontLang != nil {
+		add("themeFontLang", s.ThemeFontLang)
+	}
+	addVal("decimalSymbol", s.DecimalSymbol)
+	addVal("listSeparator", s.ListSeparator)
+
+	return elements, firstErr
+}
+
+// settingsVal 只有 w:val 属性的设置元素
+type settingsVal struct {
+	XMLName xml.Name
+	Val     string `xml:"w:val,attr"`
+}
+
+// rsidsXML w:rsids 元素的序列化结构
+type rsidsXML struct {
+	XMLName  xml.Name       `xml:"w:rsids"`
+	RsidRoot *settingsVal   `xml:"w:rsidRoot,omitempty"`
+	Rsids    []*settingsVal `xml:"w:rsid,omitempty"`
+}
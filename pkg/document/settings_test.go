@@ -0,0 +1,113 @@
+// Package document 文档设置功能测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testSettingsXML Word生成的典型settings.xml（含扩展元素和分隔符脚注引用）
+const testSettingsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:settings xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml" xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml" mc:Ignorable="w14 w15"><w:zoom w:percent="110"/><w:bordersDoNotSurroundHeader/><w:proofState w:spelling="clean" w:grammar="clean"/><w:defaultTabStop w:val="420"/><w:drawingGridVerticalSpacing w:val="156"/><w:characterSpacingControl w:val="compressPunctuation"/><w:footnotePr><w:footnote w:id="-1"/><w:footnote w:id="0"/></w:footnotePr><w:compat><w:spaceForUL/><w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="15"/></w:compat><w:rsids><w:rsidRoot w:val="00A1B2C3"/><w:rsid w:val="00A1B2C3"/><w:rsid w:val="00D4E5F6"/></w:rsids><w:themeFontLang w:val="en-US" w:eastAsia="zh-CN"/><w:decimalSymbol w:val="."/><w:listSeparator w:val=","/><w14:docId w14:val="1F2E3D4C"/><w15:chartTrackingRefBased/></w:settings>`
+
+// TestParseSettingsXML 测试解析设置及保留未建模元素
+func TestParseSettingsXML(t *testing.T) {
+	settings, err := parseSettingsXML([]byte(testSettingsXML))
+	if err != nil {
+		t.Fatalf("解析settings.xml失败: %v", err)
+	}
+
+	if settings.Zoom.Percent != "110" || settings.DefaultTabStop.Val != "420" {
+		t.Errorf("显示比例或默认制表位解析不正确: %+v %+v", settings.Zoom, settings.DefaultTabStop)
+	}
+	if settings.CompatibilityMode() != 15 {
+		t.Errorf("兼容模式应为15，实际为: %d", settings.CompatibilityMode())
+	}
+	if settings.Rsids.RsidRoot != "00A1B2C3" || len(settings.Rsids.Rsids) != 2 {
+		t.Errorf("rsids解析不正确: %+v", settings.Rsids)
+	}
+	if settings.ThemeFontLang.EastAsia != "zh-CN" || settings.DecimalSymbol != "." || settings.ListSeparator != "," {
+		t.Error("主题字体语言或区域符号解析不正确")
+	}
+	if len(settings.FootnotePr.SpecialFootnotes) != 2 {
+		t.Error("应保留分隔符脚注引用")
+	}
+
+	settings.EvenAndOddHeaders = true
+	settings.UpdateFields = true
+	settings.SetCompatibilityMode(14)
+	data, err := settings.marshal()
+	if err != nil {
+		t.Fatalf("序列化settings.xml失败: %v", err)
+	}
+	output := string(data)
+
+	for _, expected := range []string{
+		`mc:Ignorable="w14 w15"`,
+		`<w:proofState w:spelling="clean" w:grammar="clean"/>`,
+		`<w:spaceForUL/><w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="14">`,
+		`<w:footnote w:id="-1">`,
+		`<w14:docId w14:val="1F2E3D4C"/>`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("序列化结果应包含 %s", expected)
+		}
+	}
+
+	// 元素需按照CT_Settings顺序输出
+	order := []string{"<w:zoom", "<w:bordersDoNotSurroundHeader", "<w:proofState", "<w:defaultTabStop",
+		"<w:evenAndOddHeaders", "<w:drawingGridVerticalSpacing", "<w:characterSpacingControl",
+		"<w:updateFields", "<w:footnotePr", "<w:compat>", "<w:rsids", "<w:themeFontLang",
+		"<w:decimalSymbol", "<w:listSeparator", "<w14:docId", "<w15:chartTrackingRefBased"}
+	last := -1
+	for _, element := range order {
+		index := strings.Index(output, element)
+		if index <= last {
+			t.Fatalf("元素 %s 的位置不正确: %s", element, output)
+		}
+		last = index
+	}
+}
+
+// TestDocumentSettingsRoundTrip 测试设置修改后保存并重新打开
+func TestDocumentSettingsRoundTrip(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("设置测试")
+
+	settings := doc.Settings()
+	settings.TrackRevisions = true
+	settings.MirrorMargins = true
+	settings.DefaultTableStyle = "TableGrid"
+	settings.SetZoom(150)
+	settings.DocumentProtection = &DocumentProtection{Edit: "readOnly", Enforcement: "1"}
+
+	filename := filepath.Join(t.TempDir(), "settings.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	reopened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	got := reopened.Settings()
+	if !got.TrackRevisions || !got.MirrorMargins || got.DefaultTableStyle != "TableGrid" {
+		t.Errorf("重新打开后设置不正确: %+v", got)
+	}
+	if got.Zoom == nil || got.Zoom.Percent != "150" {
+		t.Error("显示比例应为150")
+	}
+	if got.DocumentProtection == nil || got.DocumentProtection.Edit != "readOnly" {
+		t.Error("文档保护设置应保留")
+	}
+
+	// 设置脚注配置不应覆盖已有设置
+	if err := reopened.SetFootnoteConfig(&FootnoteConfig{NumberFormat: FootnoteFormatLowerRoman, StartNumber: 1}); err != nil {
+		t.Fatalf("设置脚注配置失败: %v", err)
+	}
+	settingsXML := string(reopened.parts["word/settings.xml"])
+	if !strings.Contains(settingsXML, "<w:trackRevisions/>") || !strings.Contains(settingsXML, `w:val="lowerRoman"`) {
+		t.Errorf("脚注配置应与已有设置共存: %s", settingsXML)
+	}
+}
@@ -0,0 +1,912 @@
+// Package document 数字签名功能实现
+//
+// 按OPC（ECMA-376 第2部分）数字签名框架对文档包签名：签名部件
+// _xmlsignatures/sigN.xml 是对各部件及关系的XML数字签名（XML-DSig），
+// 通过签名来源部件 _xmlsignatures/origin.sigs 与包关联。
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 数字签名相关的命名空间、关系类型和内容类型
+const (
+	xmlDSigNamespace       = "http://www.w3.org/2000/09/xmldsig#"
+	packageSigNamespace    = "http://schemas.openxmlformats.org/package/2006/digital-signature"
+	relationshipsNamespace = "http://schemas.openxmlformats.org/package/2006/relationships"
+
+	signatureOriginRelType = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/origin"
+	signatureRelType       = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/signature"
+
+	signatureOriginContentType = "application/vnd.openxmlformats-package.digital-signature-origin"
+	signatureContentType       = "application/vnd.openxmlformats-package.digital-signature-xmlsignature+xml"
+	relationshipsContentType   = "application/vnd.openxmlformats-package.relationships+xml"
+
+	relationshipTransform = "http://schemas.openxmlformats.org/package/2006/RelationshipTransform"
+	objectReferenceType   = "http://www.w3.org/2000/09/xmldsig#Object"
+
+	signaturePartDir     = "_xmlsignatures/"
+	signatureOriginPart  = "_xmlsignatures/origin.sigs"
+	signatureOriginRels  = "_xmlsignatures/_rels/origin.sigs.rels"
+	signatureTimeFormat  = "YYYY-MM-DDThh:mm:ssTZD"
+	packageObjectID      = "idPackageObject"
+	packageSignatureID   = "idPackageSignature"
+	signatureTimeLayout  = "2006-01-02T15:04:05Z07:00"
+	contentTypesPartName = "[Content_Types].xml"
+)
+
+// SignatureOptions 签名选项
+type SignatureOptions struct {
+	Hash        crypto.Hash // 摘要算法，支持SHA-1/SHA-256/SHA-384/SHA-512，默认SHA-256
+	SigningTime time.Time   // 签名时间，默认当前时间
+}
+
+// SignatureInfo 文档中一个数字签名的验证结果
+type SignatureInfo struct {
+	Part          string            // 签名部件名称，如 _xmlsignatures/sig1.xml
+	Certificate   *x509.Certificate // 签名证书（是否信任由调用方判断）
+	SigningTime   time.Time         // 签名时间
+	Valid         bool              // 签名值正确且所有被签名的部件均未修改
+	ModifiedParts []string          // 签名后被修改或删除的部件
+	UnsignedParts []string          // 未被该签名覆盖的部件（如签名后新增的部件）
+	Error         error             // 签名本身无效的原因
+}
+
+// Sign 对文档进行数字签名
+//
+// 签名覆盖文档包中除签名部件和[Content_Types].xml外的所有部件，
+// 关系部件通过关系变换（RelationshipTransform）签名，因此后续添加的签名不会使已有签名失效。
+// 签名在调用时根据文档当前内容生成，签名后再修改文档会导致签名失效。
+// 支持RSA和ECDSA密钥，cert 必须与 signer 的公钥匹配。
+func (d *Document) Sign(signer crypto.Signer, cert *x509.Certificate, opts *SignatureOptions) error {
+	if signer == nil || cert == nil {
+		return NewValidationError("signer", "", "签名密钥和证书不能为空")
+	}
+	if publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(cert.PublicKey) {
+		return NewValidationError("cert", cert.Subject.String(), "证书与签名密钥不匹配")
+	}
+
+	options := SignatureOptions{Hash: crypto.SHA256, SigningTime: time.Now()}
+	if opts != nil {
+		if opts.Hash != 0 {
+			options.Hash = opts.Hash
+		}
+		if !opts.SigningTime.IsZero() {
+			options.SigningTime = opts.SigningTime
+		}
+	}
+	if _, err := digestMethodURI(options.Hash); err != nil {
+		return NewValidationError("hash", options.Hash.String(), err.Error())
+	}
+	if _, err := signatureMethodURI(signer.Public(), options.Hash); err != nil {
+		return NewValidationError("signer", "", err.Error())
+	}
+
+	// 先建立签名来源部件及其关系，使签名覆盖最终的包结构
+	d.ensureSignatureOrigin()
+	partName := d.nextSignaturePartName()
+	d.addContentType(partName, signatureContentType)
+
+	data, err := d.ToBytes()
+	if err != nil {
+		return WrapError("serialize_document", err)
+	}
+	parts, err := readZipParts(data)
+	if err != nil {
+		return WrapError("read_package", err)
+	}
+
+	signature, err := buildPackageSignature(parts, signer, cert, &options)
+	if err != nil {
+		Errorf("生成数字签名失败: %v", err)
+		return WrapError("sign_document", err)
+	}
+	d.parts[partName] = signature
+	if err := d.addSignatureRelationship(path.Base(partName)); err != nil {
+		return WrapError("sign_document", err)
+	}
+
+	Infof("文档签名完成: %s (%s)", partName, cert.Subject.CommonName)
+	return nil
+}
+
+// ensureSignatureOrigin 确保包中存在签名来源部件及指向它的关系
+func (d *Document) ensureSignatureOrigin() {
+	hasOrigin := false
+	for _, rel := range d.relationships.Relationships {
+		if rel.Type == signatureOriginRelType {
+			hasOrigin = true
+			break
+		}
+	}
+	if !hasOrigin {
+		d.relationships.Relationships = append(d.relationships.Relationships, Relationship{
+			ID:     nextRelationshipID(d.relationships),
+			Type:   signatureOriginRelType,
+			Target: signatureOriginPart,
+		})
+	}
+	if _, exists := d.parts[signatureOriginPart]; !exists {
+		d.parts[signatureOriginPart] = []byte{}
+	}
+
+	for _, def := range d.contentTypes.Defaults {
+		if strings.EqualFold(def.Extension, "sigs") {
+			return
+		}
+	}
+	d.contentTypes.Defaults = append(d.contentTypes.Defaults, Default{
+		Extension:   "sigs",
+		ContentType: signatureOriginContentType,
+	})
+}
+
+// nextSignaturePartName 返回下一个可用的签名部件名称
+func (d *Document) nextSignaturePartName() string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%ssig%d.xml", signaturePartDir, i)
+		if _, exists := d.parts[name]; !exists {
+			return name
+		}
+	}
+}
+
+// addSignatureRelationship 在签名来源部件的关系中添加签名部件
+func (d *Document) addSignatureRelationship(target string) error {
+	rels := &Relationships{Xmlns: relationshipsNamespace}
+	if data, exists := d.parts[signatureOriginRels]; exists {
+		if err := xml.Unmarshal(data, rels); err != nil {
+			return fmt.Errorf("解析签名关系失败: %w", err)
+		}
+		rels.Xmlns = relationshipsNamespace
+	}
+	rels.Relationships = append(rels.Relationships, Relationship{
+		ID:     nextRelationshipID(rels),
+		Type:   signatureRelType,
+		Target: target,
+	})
+
+	data, err := xml.MarshalIndent(rels, "", "  ")
+	if err != nil {
+		return err
+	}
+	d.parts[signatureOriginRels] = append([]byte(xml.Header), data...)
+	return nil
+}
+
+// buildPackageSignature 生成签名部件的内容
+func buildPackageSignature(parts map[string][]byte, signer crypto.Signer, cert *x509.Certificate, opts *SignatureOptions) ([]byte, error) {
+	digestURI, _ := digestMethodURI(opts.Hash)
+	signatureURI, err := signatureMethodURI(signer.Public(), opts.Hash)
+	if err != nil {
+		return nil, err
+	}
+	contentTypes := parsePackageContentTypes(parts[contentTypesPartName])
+
+	// 生成清单：每个部件一个引用
+	names := make([]string, 0, len(parts))
+	for name := range parts {
+		if isSignatureExcludedPart(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var manifest strings.Builder
+	for _, name := range names {
+		contentType := contentTypes.lookup(name)
+		uri := escapeXMLAttr(partReferenceURI(name, contentType))
+		if contentType == relationshipsContentType || strings.HasSuffix(name, ".rels") {
+			ids, err := signableRelationshipIDs(parts[name])
+			if err != nil {
+				return nil, fmt.Errorf("解析关系部件 %s 失败: %w", name, err)
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			transformed, err := transformRelationships(parts[name], ids, nil)
+			if err != nil {
+				return nil, err
+			}
+			manifest.WriteString(`<Reference URI="` + uri + `"><Transforms><Transform Algorithm="` + relationshipTransform + `">`)
+			for _, id := range ids {
+				manifest.WriteString(`<mdssi:RelationshipReference xmlns:mdssi="` + packageSigNamespace + `" SourceId="` + escapeXMLAttr(id) + `"/>`)
+			}
+			manifest.WriteString(`</Transform><Transform Algorithm="` + c14nAlgorithm + `"/></Transforms>`)
+			manifest.WriteString(digestElements(digestURI, hashBytes(opts.Hash, transformed)) + `</Reference>`)
+			continue
+		}
+		manifest.WriteString(`<Reference URI="` + uri + `">`)
+		manifest.WriteString(digestElements(digestURI, hashBytes(opts.Hash, parts[name])) + `</Reference>`)
+	}
+
+	object := `<Object Id="` + packageObjectID + `"><Manifest>` + manifest.String() + `</Manifest>` +
+		`<SignatureProperties><SignatureProperty Id="idSignatureTime" Target="#` + packageSignatureID + `">` +
+		`<mdssi:SignatureTime xmlns:mdssi="` + packageSigNamespace + `"><mdssi:Format>` + signatureTimeFormat +
+		`</mdssi:Format><mdssi:Value>` + opts.SigningTime.UTC().Format(signatureTimeLayout) + `</mdssi:Value></mdssi:SignatureTime>` +
+		`</SignatureProperty></SignatureProperties></Object>`
+
+	objectDigest, err := digestSignatureElement(object, opts.Hash)
+	if err != nil {
+		return nil, err
+	}
+	signedInfo := `<SignedInfo><CanonicalizationMethod Algorithm="` + c14nAlgorithm + `"/>` +
+		`<SignatureMethod Algorithm="` + signatureURI + `"/>` +
+		`<Reference Type="` + objectReferenceType + `" URI="#` + packageObjectID + `">` +
+		digestElements(digestURI, objectDigest) + `</Reference></SignedInfo>`
+
+	canonicalSignedInfo, err := canonicalSignatureElement(signedInfo)
+	if err != nil {
+		return nil, err
+	}
+	signatureValue, err := signDigest(signer, opts.Hash, hashBytes(opts.Hash, canonicalSignedInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<Signature xmlns="` + xmlDSigNamespace + `" Id="` + packageSignatureID + `">`)
+	buf.WriteString(signedInfo)
+	buf.WriteString(`<SignatureValue>` + base64.StdEncoding.EncodeToString(signatureValue) + `</SignatureValue>`)
+	buf.WriteString(`<KeyInfo><X509Data><X509Certificate>` + base64.StdEncoding.EncodeToString(cert.Raw) +
+		`</X509Certificate></X509Data></KeyInfo>`)
+	buf.WriteString(object)
+	buf.WriteString(`</Signature>`)
+	return buf.Bytes(), nil
+}
+
+// canonicalSignatureElement 在签名根元素的命名空间上下文中规范化签名的子元素
+func canonicalSignatureElement(element string) ([]byte, error) {
+	root, err := parseXMLTree([]byte(`<Signature xmlns="` + xmlDSigNamespace + `">` + element + `</Signature>`))
+	if err != nil {
+		return nil, err
+	}
+	child, ok := root.children[0].(*xmlNode)
+	if !ok {
+		return nil, fmt.Errorf("签名元素无效")
+	}
+	return canonicalize(child, c14nAlgorithm)
+}
+
+// digestSignatureElement 计算签名子元素规范化后的摘要
+func digestSignatureElement(element string, hash crypto.Hash) ([]byte, error) {
+	canonical, err := canonicalSignatureElement(element)
+	if err != nil {
+		return nil, err
+	}
+	return hashBytes(hash, canonical), nil
+}
+
+// digestElements 生成 DigestMethod 和 DigestValue 元素
+func digestElements(digestURI string, digest []byte) string {
+	return `<DigestMethod Algorithm="` + digestURI + `"/><DigestValue>` +
+		base64.StdEncoding.EncodeToString(digest) + `</DigestValue>`
+}
+
+// signDigest 对摘要签名，ECDSA签名转换为XML-DSig要求的 r||s 格式
+func signDigest(signer crypto.Signer, hash crypto.Hash, digest []byte) ([]byte, error) {
+	signature, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return signature, nil
+	}
+
+	var parsed struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature, &parsed); err != nil {
+		return nil, fmt.Errorf("解析ECDSA签名失败: %w", err)
+	}
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	result := make([]byte, 2*size)
+	parsed.R.FillBytes(result[:size])
+	parsed.S.FillBytes(result[size:])
+	return result, nil
+}
+
+// VerifySignatures 验证文档中的数字签名
+//
+// 返回每个签名的验证结果，包括签名证书、签名时间以及签名后被修改的部件。
+// 文档没有签名时返回空列表。此方法只检查签名与内容是否一致，
+// 证书是否可信（证书链、吊销状态等）需由调用方根据 Certificate 判断。
+func VerifySignatures(filename string) ([]*SignatureInfo, error) {
+	reader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, WrapErrorWithContext("open_file", err, filename)
+	}
+	defer reader.Close()
+
+	parts := make(map[string][]byte, len(reader.File))
+	for _, file := range reader.File {
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, WrapErrorWithContext("read_part", err, file.Name)
+		}
+		parts[file.Name] = data
+	}
+
+	signatureParts, err := findSignatureParts(parts)
+	if err != nil {
+		return nil, WrapErrorWithContext("verify_signatures", err, filename)
+	}
+
+	contentTypes := parsePackageContentTypes(parts[contentTypesPartName])
+	results := make([]*SignatureInfo, 0, len(signatureParts))
+	for _, partName := range signatureParts {
+		info := verifyPackageSignature(partName, parts, contentTypes)
+		if info.Valid {
+			Infof("签名有效: %s", partName)
+		} else {
+			Warnf("签名无效: %s", partName)
+		}
+		results = append(results, info)
+	}
+	return results, nil
+}
+
+// findSignatureParts 通过签名来源部件的关系查找所有签名部件
+func findSignatureParts(parts map[string][]byte) ([]string, error) {
+	rootRels, exists := parts["_rels/.rels"]
+	if !exists {
+		return nil, nil
+	}
+	rels := &Relationships{}
+	if err := xml.Unmarshal(rootRels, rels); err != nil {
+		return nil, fmt.Errorf("解析包关系失败: %w", err)
+	}
+
+	var signatureParts []string
+	for _, rel := range rels.Relationships {
+		if rel.Type != signatureOriginRelType {
+			continue
+		}
+		origin := resolvePartName("", rel.Target)
+		originRels, exists := parts[path.Join(path.Dir(origin), "_rels", path.Base(origin)+".rels")]
+		if !exists {
+			continue
+		}
+		sigRels := &Relationships{}
+		if err := xml.Unmarshal(originRels, sigRels); err != nil {
+			return nil, fmt.Errorf("解析签名关系失败: %w", err)
+		}
+		for _, sigRel := range sigRels.Relationships {
+			if sigRel.Type == signatureRelType {
+				signatureParts = append(signatureParts, resolvePartName(path.Dir(origin), sigRel.Target))
+			}
+		}
+	}
+	return signatureParts, nil
+}
+
+// verifyPackageSignature 验证单个签名部件
+func verifyPackageSignature(partName string, parts map[string][]byte, contentTypes *packageContentTypes) *SignatureInfo {
+	info := &SignatureInfo{Part: partName}
+	data, exists := parts[partName]
+	if !exists {
+		info.Error = fmt.Errorf("签名部件不存在")
+		return info
+	}
+	root, err := parseXMLTree(data)
+	if err != nil || !root.is(xmlDSigNamespace, "Signature") {
+		info.Error = fmt.Errorf("签名部件格式无效")
+		return info
+	}
+
+	signedInfo := root.child(xmlDSigNamespace, "SignedInfo")
+	signatureValue := root.child(xmlDSigNamespace, "SignatureValue")
+	if signedInfo == nil || signatureValue == nil {
+		info.Error = fmt.Errorf("缺少 SignedInfo 或 SignatureValue")
+		return info
+	}
+
+	// 读取签名证书
+	if keyInfo := root.child(xmlDSigNamespace, "KeyInfo"); keyInfo != nil {
+		if x509Data := keyInfo.child(xmlDSigNamespace, "X509Data"); x509Data != nil {
+			if certNode := x509Data.child(xmlDSigNamespace, "X509Certificate"); certNode != nil {
+				if raw, err := base64.StdEncoding.DecodeString(compactBase64(certNode.text())); err == nil {
+					info.Certificate, _ = x509.ParseCertificate(raw)
+				}
+			}
+		}
+	}
+	if info.Certificate == nil {
+		info.Error = fmt.Errorf("签名中缺少有效的X509证书")
+		return info
+	}
+	info.SigningTime = parseSignatureTime(root)
+
+	// 验证SignedInfo的签名值
+	canonicalization := ""
+	if method := signedInfo.child(xmlDSigNamespace, "CanonicalizationMethod"); method != nil {
+		canonicalization = method.attr("Algorithm")
+	}
+	canonical, err := canonicalize(signedInfo, canonicalization)
+	if err != nil {
+		info.Error = err
+		return info
+	}
+	method := signedInfo.child(xmlDSigNamespace, "SignatureMethod")
+	if method == nil {
+		info.Error = fmt.Errorf("缺少 SignatureMethod")
+		return info
+	}
+	value, err := base64.StdEncoding.DecodeString(compactBase64(signatureValue.text()))
+	if err != nil {
+		info.Error = fmt.Errorf("签名值无效")
+		return info
+	}
+	if err := verifySignatureValue(info.Certificate.PublicKey, method.attr("Algorithm"), canonical, value); err != nil {
+		info.Error = err
+		return info
+	}
+
+	// 验证SignedInfo中对签名对象的引用，再验证对象清单中对各部件的引用
+	covered := map[string]bool{}
+	for _, reference := range signedInfo.childElements(xmlDSigNamespace, "Reference") {
+		uri := reference.attr("URI")
+		if !strings.HasPrefix(uri, "#") {
+			if err := verifyPartReference(reference, parts, contentTypes, covered, info); err != nil {
+				info.Error = err
+				return info
+			}
+			continue
+		}
+		target := root.findByID(strings.TrimPrefix(uri, "#"))
+		if target == nil {
+			info.Error = fmt.Errorf("找不到签名引用的对象: %s", uri)
+			return info
+		}
+		digest, err := referenceDigest(reference, func(algorithm string) ([]byte, error) {
+			return canonicalize(target, algorithm)
+		})
+		if err != nil {
+			info.Error = err
+			return info
+		}
+		if !digest {
+			info.Error = fmt.Errorf("签名对象 %s 已被修改", uri)
+			return info
+		}
+
+		for _, manifest := range manifestsOf(target) {
+			for _, partRef := range manifest.childElements(xmlDSigNamespace, "Reference") {
+				if err := verifyPartReference(partRef, parts, contentTypes, covered, info); err != nil {
+					info.Error = err
+					return info
+				}
+			}
+		}
+	}
+
+	for name := range parts {
+		if !covered[name] && !isSignatureExcludedPart(name) {
+			info.UnsignedParts = append(info.UnsignedParts, name)
+		}
+	}
+	sort.Strings(info.ModifiedParts)
+	sort.Strings(info.UnsignedParts)
+	info.Valid = len(info.ModifiedParts) == 0
+	return info
+}
+
+// manifestsOf 返回签名对象中的清单元素
+func manifestsOf(object *xmlNode) []*xmlNode {
+	if object.is(xmlDSigNamespace, "Manifest") {
+		return []*xmlNode{object}
+	}
+	return object.childElements(xmlDSigNamespace, "Manifest")
+}
+
+// verifyPartReference 验证对包部件的引用，部件被修改时记录到 ModifiedParts
+func verifyPartReference(reference *xmlNode, parts map[string][]byte, contentTypes *packageContentTypes,
+	covered map[string]bool, info *SignatureInfo) error {
+	name, contentType, err := parsePartReferenceURI(reference.attr("URI"))
+	if err != nil {
+		return err
+	}
+	covered[name] = true
+
+	data, exists := parts[name]
+	if !exists || (contentType != "" && !strings.EqualFold(contentTypes.lookup(name), contentType)) {
+		info.ModifiedParts = append(info.ModifiedParts, name)
+		return nil
+	}
+
+	matched, err := referenceDigest(reference, func(algorithm string) ([]byte, error) {
+		return applyPartTransforms(reference, data, algorithm)
+	})
+	if err != nil {
+		return fmt.Errorf("验证部件 %s 失败: %w", name, err)
+	}
+	if !matched {
+		info.ModifiedParts = append(info.ModifiedParts, name)
+	}
+	return nil
+}
+
+// referenceDigest 计算引用的摘要并与签名中保存的值比较
+// transform 根据引用中的规范化算法（可能为空）返回待计算摘要的数据
+func referenceDigest(reference *xmlNode, transform func(algorithm string) ([]byte, error)) (bool, error) {
+	digestMethod := reference.child(xmlDSigNamespace, "DigestMethod")
+	digestValue := reference.child(xmlDSigNamespace, "DigestValue")
+	if digestMethod == nil || digestValue == nil {
+		return false, fmt.Errorf("引用缺少摘要信息")
+	}
+	hash, err := hashFromDigestURI(digestMethod.attr("Algorithm"))
+	if err != nil {
+		return false, err
+	}
+	expected, err := base64.StdEncoding.DecodeString(compactBase64(digestValue.text()))
+	if err != nil {
+		return false, fmt.Errorf("摘要值无效")
+	}
+
+	algorithm := ""
+	if transforms := reference.child(xmlDSigNamespace, "Transforms"); transforms != nil {
+		for _, t := range transforms.childElements(xmlDSigNamespace, "Transform") {
+			if a := t.attr("Algorithm"); a != relationshipTransform {
+				algorithm = a
+			}
+		}
+	}
+	data, err := transform(algorithm)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(hashBytes(hash, data), expected), nil
+}
+
+// applyPartTransforms 对部件数据应用引用中的变换
+func applyPartTransforms(reference *xmlNode, data []byte, algorithm string) ([]byte, error) {
+	transforms := reference.child(xmlDSigNamespace, "Transforms")
+	if transforms == nil {
+		return data, nil
+	}
+
+	for _, t := range transforms.childElements(xmlDSigNamespace, "Transform") {
+		if t.attr("Algorithm") != relationshipTransform {
+			continue
+		}
+		var ids, types []string
+		for _, child := range t.children {
+			node, ok := child.(*xmlNode)
+			if !ok || node.namespaceURI(node.prefix) != packageSigNamespace {
+				continue
+			}
+			switch node.local {
+			case "RelationshipReference":
+				ids = append(ids, node.attr("SourceId"))
+			case "RelationshipsGroupReference":
+				types = append(types, node.attr("SourceType"))
+			}
+		}
+		// 关系变换的结果已是规范形式
+		return transformRelationships(data, ids, types)
+	}
+
+	if algorithm == "" {
+		return data, nil
+	}
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalize(root, algorithm)
+}
+
+// packageRelationship 关系变换使用的关系信息
+type packageRelationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr"`
+}
+
+// parsePackageRelationships 解析关系部件
+func parsePackageRelationships(data []byte) ([]packageRelationship, error) {
+	var rels struct {
+		Relationships []packageRelationship `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, err
+	}
+	return rels.Relationships, nil
+}
+
+// signableRelationshipIDs 返回关系部件中需要签名的关系ID（签名来源关系除外）
+func signableRelationshipIDs(data []byte) ([]string, error) {
+	rels, err := parsePackageRelationships(data)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, rel := range rels {
+		if rel.Type != signatureOriginRelType {
+			ids = append(ids, rel.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// transformRelationships 执行OPC关系变换
+//
+// 按ID或类型选出关系，按ID排序，补全默认的 TargetMode="Internal"，
+// 并输出规范化的XML（ECMA-376 第2部分 13.2.4.24）。
+func transformRelationships(data []byte, ids, types []string) ([]byte, error) {
+	rels, err := parsePackageRelationships(data)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]packageRelationship, 0, len(rels))
+	for _, rel := range rels {
+		if containsString(ids, rel.ID) || containsString(types, rel.Type) {
+			if rel.TargetMode == "" {
+				rel.TargetMode = "Internal"
+			}
+			selected = append(selected, rel)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+
+	var buf bytes.Buffer
+	buf.WriteString(`<Relationships xmlns="` + relationshipsNamespace + `">`)
+	for _, rel := range selected {
+		buf.WriteString(`<Relationship Id="` + escapeCanonicalAttr(rel.ID) +
+			`" Target="` + escapeCanonicalAttr(rel.Target) +
+			`" TargetMode="` + escapeCanonicalAttr(rel.TargetMode) +
+			`" Type="` + escapeCanonicalAttr(rel.Type) + `"></Relationship>`)
+	}
+	buf.WriteString(`</Relationships>`)
+	return buf.Bytes(), nil
+}
+
+// packageContentTypes 包的内容类型表
+type packageContentTypes struct {
+	defaults  map[string]string
+	overrides map[string]string
+}
+
+// parsePackageContentTypes 解析[Content_Types].xml
+func parsePackageContentTypes(data []byte) *packageContentTypes {
+	types := &packageContentTypes{defaults: map[string]string{}, overrides: map[string]string{}}
+	contentTypes := &ContentTypes{}
+	if err := xml.Unmarshal(data, contentTypes); err != nil {
+		return types
+	}
+	for _, def := range contentTypes.Defaults {
+		types.defaults[strings.ToLower(def.Extension)] = def.ContentType
+	}
+	for _, override := range contentTypes.Overrides {
+		types.overrides[strings.ToLower(strings.TrimPrefix(override.PartName, "/"))] = override.ContentType
+	}
+	return types
+}
+
+// lookup 返回部件的内容类型
+func (t *packageContentTypes) lookup(partName string) string {
+	if contentType, ok := t.overrides[strings.ToLower(partName)]; ok {
+		return contentType
+	}
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(partName)), ".")
+	return t.defaults[ext]
+}
+
+// partReferenceURI 生成签名清单中引用部件的URI
+func partReferenceURI(partName, contentType string) string {
+	uri := (&url.URL{Path: "/" + partName}).EscapedPath()
+	if contentType != "" {
+		uri += "?ContentType=" + contentType
+	}
+	return uri
+}
+
+// parsePartReferenceURI 解析部件引用URI，返回部件名称和内容类型
+func parsePartReferenceURI(uri string) (string, string, error) {
+	partURI, query, _ := strings.Cut(uri, "?")
+	name, err := url.PathUnescape(partURI)
+	if err != nil || !strings.HasPrefix(name, "/") {
+		return "", "", fmt.Errorf("无效的部件引用: %s", uri)
+	}
+	contentType := ""
+	if strings.HasPrefix(query, "ContentType=") {
+		contentType = strings.TrimPrefix(query, "ContentType=")
+	}
+	return strings.TrimPrefix(name, "/"), contentType, nil
+}
+
+// resolvePartName 将关系目标解析为部件名称
+func resolvePartName(sourceDir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Clean(path.Join("/", sourceDir, target)), "/")
+}
+
+// isSignatureExcludedPart 检查部件是否不参与签名
+func isSignatureExcludedPart(name string) bool {
+	return name == contentTypesPartName || strings.HasPrefix(name, signaturePartDir)
+}
+
+// parseSignatureTime 读取签名属性中的签名时间
+func parseSignatureTime(root *xmlNode) time.Time {
+	var result time.Time
+	var search func(node *xmlNode)
+	search = func(node *xmlNode) {
+		if !result.IsZero() {
+			return
+		}
+		if node.is(packageSigNamespace, "SignatureTime") {
+			if value := node.child(packageSigNamespace, "Value"); value != nil {
+				if t, err := time.Parse(signatureTimeLayout, strings.TrimSpace(value.text())); err == nil {
+					result = t
+				}
+			}
+			return
+		}
+		for _, child := range node.children {
+			if c, ok := child.(*xmlNode); ok {
+				search(c)
+			}
+		}
+	}
+	search(root)
+	return result
+}
+
+// verifySignatureValue 使用证书公钥验证签名值
+func verifySignatureValue(publicKey crypto.PublicKey, algorithm string, signed, signature []byte) error {
+	var hash crypto.Hash
+	for _, candidate := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		if uri, err := signatureMethodURI(publicKey, candidate); err == nil && uri == algorithm {
+			hash = candidate
+			break
+		}
+	}
+	if hash == 0 {
+		return fmt.Errorf("不支持的签名算法: %s", algorithm)
+	}
+	digest := hashBytes(hash, signed)
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+			return fmt.Errorf("签名值验证失败")
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("签名值验证失败")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return fmt.Errorf("签名值验证失败")
+		}
+	default:
+		return fmt.Errorf("不支持的公钥类型")
+	}
+	return nil
+}
+
+// digestMethodURI 返回摘要算法的URI
+func digestMethodURI(hash crypto.Hash) (string, error) {
+	switch hash {
+	case crypto.SHA1:
+		return "http://www.w3.org/2000/09/xmldsig#sha1", nil
+	case crypto.SHA256:
+		return "http://www.w3.org/2001/04/xmlenc#sha256", nil
+	case crypto.SHA384:
+		return "http://www.w3.org/2001/04/xmldsig-more#sha384", nil
+	case crypto.SHA512:
+		return "http://www.w3.org/2001/04/xmlenc#sha512", nil
+	default:
+		return "", fmt.Errorf("不支持的摘要算法: %v", hash)
+	}
+}
+
+// hashFromDigestURI 根据摘要算法URI返回哈希算法
+func hashFromDigestURI(uri string) (crypto.Hash, error) {
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		if candidate, _ := digestMethodURI(hash); candidate == uri {
+			return hash, nil
+		}
+	}
+	return 0, fmt.Errorf("不支持的摘要算法: %s", uri)
+}
+
+// signatureMethodURI 返回签名算法的URI
+func signatureMethodURI(publicKey crypto.PublicKey, hash crypto.Hash) (string, error) {
+	names := map[crypto.Hash]string{
+		crypto.SHA1:   "sha1",
+		crypto.SHA256: "sha256",
+		crypto.SHA384: "sha384",
+		crypto.SHA512: "sha512",
+	}
+	name, ok := names[hash]
+	if !ok {
+		return "", fmt.Errorf("不支持的摘要算法: %v", hash)
+	}
+
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if hash == crypto.SHA1 {
+			return "http://www.w3.org/2000/09/xmldsig#rsa-sha1", nil
+		}
+		return "http://www.w3.org/2001/04/xmldsig-more#rsa-" + name, nil
+	case *ecdsa.PublicKey:
+		return "http://www.w3.org/2001/04/xmldsig-more#ecdsa-" + name, nil
+	default:
+		return "", fmt.Errorf("仅支持RSA和ECDSA密钥")
+	}
+}
+
+// hashBytes 计算数据的摘要
+func hashBytes(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// readZipParts 读取ZIP包中的所有部件
+func readZipParts(data []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	parts := make(map[string][]byte, len(reader.File))
+	for _, file := range reader.File {
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parts[file.Name] = content
+	}
+	return parts, nil
+}
+
+// readZipFile 读取ZIP包中单个文件的内容
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// compactBase64 去除Base64文本中的空白字符
+func compactBase64(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// containsString 检查字符串切片是否包含指定值
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
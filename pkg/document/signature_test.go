@@ -0,0 +1,212 @@
+// Package document 数字签名功能测试
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createTestCertificate 生成测试用的自签名证书
+func createTestCertificate(t *testing.T, signer crypto.Signer, name string) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("解析证书失败: %v", err)
+	}
+	return cert
+}
+
+// rewriteZipPart 修改ZIP包中的部件内容，modify 返回nil时删除部件
+func rewriteZipPart(t *testing.T, filename, partName string, modify func([]byte) []byte) {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("读取文件失败: %v", err)
+	}
+	parts, err := readZipParts(data)
+	if err != nil {
+		t.Fatalf("读取ZIP失败: %v", err)
+	}
+	if content := modify(parts[partName]); content != nil {
+		parts[partName] = content
+	} else {
+		delete(parts, partName)
+	}
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for name, content := range parts {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("写入ZIP失败: %v", err)
+		}
+		w.Write(content)
+	}
+	writer.Close()
+	if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+}
+
+// TestDocumentSignature 测试签名、验证以及篡改检测
+func TestDocumentSignature(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("生成RSA密钥失败: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成ECDSA密钥失败: %v", err)
+	}
+	rsaCert := createTestCertificate(t, rsaKey, "审批人")
+	ecCert := createTestCertificate(t, ecKey, "复核人")
+
+	doc := New()
+	doc.AddParagraph("审批通过的合同")
+	doc.AddHeader(HeaderFooterTypeDefault, "合同")
+
+	if err := doc.Sign(ecKey, rsaCert, nil); err == nil {
+		t.Error("证书与密钥不匹配时应返回错误")
+	}
+	signingTime := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	if err := doc.Sign(rsaKey, rsaCert, &SignatureOptions{SigningTime: signingTime}); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	if err := doc.Sign(ecKey, ecCert, &SignatureOptions{Hash: crypto.SHA512}); err != nil {
+		t.Fatalf("第二次签名失败: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "signed.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	results, err := VerifySignatures(filename)
+	if err != nil {
+		t.Fatalf("验证签名失败: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("应有两个签名，实际为: %d", len(results))
+	}
+	for _, result := range results {
+		if !result.Valid || result.Error != nil || len(result.ModifiedParts) != 0 || len(result.UnsignedParts) != 0 {
+			t.Errorf("签名 %s 应有效: %+v", result.Part, result)
+		}
+	}
+	if results[0].Certificate.Subject.CommonName != "审批人" || !results[0].SigningTime.Equal(signingTime) {
+		t.Errorf("签名信息不正确: %+v", results[0])
+	}
+
+	// 修改正文后签名应失效，并报告被修改的部件
+	rewriteZipPart(t, filename, "word/document.xml", func(data []byte) []byte {
+		return bytes.Replace(data, []byte("审批通过"), []byte("审批驳回"), 1)
+	})
+	rewriteZipPart(t, filename, "word/extra.xml", func([]byte) []byte { return []byte("<extra/>") })
+	results, err = VerifySignatures(filename)
+	if err != nil {
+		t.Fatalf("验证签名失败: %v", err)
+	}
+	for _, result := range results {
+		if result.Valid || strings.Join(result.ModifiedParts, ",") != "word/document.xml" {
+			t.Errorf("应检测到正文被修改: %+v", result)
+		}
+		if strings.Join(result.UnsignedParts, ",") != "word/extra.xml" {
+			t.Errorf("应报告未签名的部件: %+v", result.UnsignedParts)
+		}
+	}
+
+	// 篡改签名本身
+	rewriteZipPart(t, filename, "_xmlsignatures/sig1.xml", func(data []byte) []byte {
+		return bytes.Replace(data, []byte("idSignatureTime"), []byte("idSignatureTim2"), 1)
+	})
+	results, err = VerifySignatures(filename)
+	if err != nil {
+		t.Fatalf("验证签名失败: %v", err)
+	}
+	if results[0].Valid || results[0].Error == nil {
+		t.Error("签名对象被篡改时应返回错误")
+	}
+}
+
+// TestRelationshipTransform 测试OPC关系变换
+func TestRelationshipTransform(t *testing.T) {
+	rels := []byte(`<?xml version="1.0"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId3" Type="http://example.com/b" Target="b.xml" TargetMode="External"/>
+  <Relationship Id="rId1" Type="http://example.com/a" Target="a.xml"/>
+  <Relationship Id="rId2" Type="http://example.com/c" Target="c.xml"/>
+</Relationships>`)
+
+	result, err := transformRelationships(rels, []string{"rId3", "rId1"}, nil)
+	if err != nil {
+		t.Fatalf("关系变换失败: %v", err)
+	}
+	expected := `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="a.xml" TargetMode="Internal" Type="http://example.com/a"></Relationship>` +
+		`<Relationship Id="rId3" Target="b.xml" TargetMode="External" Type="http://example.com/b"></Relationship>` +
+		`</Relationships>`
+	if string(result) != expected {
+		t.Errorf("关系变换结果不正确:\n%s", result)
+	}
+}
+
+// TestCanonicalize 测试XML规范化
+func TestCanonicalize(t *testing.T) {
+	input := `<a:root xmlns:a="urn:a" xmlns="urn:default" xmlns:unused="urn:u"><a:child z="1" a:y="2" b="&lt;&quot;"/>` +
+		"<child>x &amp; y\r\n</child></a:root>"
+	root, err := parseXMLTree([]byte(input))
+	if err != nil {
+		t.Fatalf("解析XML失败: %v", err)
+	}
+	child := root.children[0].(*xmlNode)
+
+	inclusive, err := canonicalize(child, c14nAlgorithm)
+	if err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	expected := `<a:child xmlns="urn:default" xmlns:a="urn:a" xmlns:unused="urn:u" b="&lt;&quot;" z="1" a:y="2"></a:child>`
+	if string(inclusive) != expected {
+		t.Errorf("包含式规范化结果不正确:\n%s", inclusive)
+	}
+
+	exclusive, err := canonicalize(child, excC14NAlgorithm)
+	if err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	expected = `<a:child xmlns:a="urn:a" b="&lt;&quot;" z="1" a:y="2"></a:child>`
+	if string(exclusive) != expected {
+		t.Errorf("排他式规范化结果不正确:\n%s", exclusive)
+	}
+
+	full, err := canonicalize(root, c14nAlgorithm)
+	if err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if !strings.HasSuffix(string(full), "<child>x &amp; y\n</child></a:root>") {
+		t.Errorf("文本规范化结果不正确:\n%s", full)
+	}
+}
@@ -0,0 +1,250 @@
+// Package document 段落和Run的样式应用
+package document
+
+import (
+	"fmt"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// 段落和Run实现样式应用器接口
+var (
+	_ style.StyleApplicator = (*Paragraph)(nil)
+	_ style.StyleApplicator = (*Run)(nil)
+)
+
+// bindParagraph 将段落及其Run关联到文档，应用样式时据此校验和补充样式
+func (d *Document) bindParagraph(p *Paragraph) *Paragraph {
+	p.doc = d
+	for i := range p.Runs {
+		p.Runs[i].doc = d
+	}
+	return p
+}
+
+// bindTable 将表格及其单元格中的段落关联到文档，之后通过表格方法添加的段落同样关联
+func (d *Document) bindTable(t *Table) *Table {
+	t.doc = d
+	for i := range t.Rows {
+		for j := range t.Rows[i].Cells {
+			t.bindCell(&t.Rows[i].Cells[j])
+		}
+	}
+	return t
+}
+
+// bindCell 将单元格中的段落和嵌套表格关联到表格所属的文档
+func (t *Table) bindCell(cell *TableCell) {
+	if t.doc == nil {
+		return
+	}
+	for i := range cell.Paragraphs {
+		t.doc.bindParagraph(&cell.Paragraphs[i])
+	}
+	for i := range cell.Tables {
+		t.doc.bindTable(&cell.Tables[i])
+	}
+}
+
+// bindElements 将元素列表中的段落和表格关联到文档
+func (d *Document) bindElements(elements []interface{}) {
+	for _, element := range elements {
+		switch elem := element.(type) {
+		case *Paragraph:
+			d.bindParagraph(elem)
+		case *Table:
+			d.bindTable(elem)
+		}
+	}
+}
+
+// resolveAppliedStyle 校验要应用的样式并返回实际使用的样式ID
+// 文档中缺少的预定义样式会自动补充，中文版Word文档中同名的内置样式（如 "1"）优先使用
+func (d *Document) resolveAppliedStyle(styleID string, styleType style.StyleType) (string, error) {
+	s, err := d.styleManager.EnsureStyle(styleID)
+	if err != nil {
+		return "", NewValidationError("style", styleID, "样式不存在")
+	}
+	if s.Type != string(styleType) {
+		return "", NewValidationError("style", styleID, fmt.Sprintf("样式类型为 %s，不能作为 %s 样式使用", s.Type, styleType))
+	}
+	return s.StyleID, nil
+}
+
+// setStyleID 写入段落样式引用
+func (p *Paragraph) setStyleID(styleID string) {
+	if p.Properties == nil {
+		p.Properties = &ParagraphProperties{}
+	}
+	p.Properties.ParagraphStyle = &ParagraphStyle{Val: styleID}
+	Debugf("设置段落样式: %s", styleID)
+}
+
+// ApplyStyle 应用段落样式
+//
+// 段落属于文档时会校验样式：样式必须是段落样式，文档中缺少的预定义样式
+// （Heading1、Quote等）在首次使用时自动加入样式表。未关联文档的段落（如直接构造的
+// Paragraph）只写入样式引用。
+func (p *Paragraph) ApplyStyle(styleID string) error {
+	if p.doc != nil {
+		resolved, err := p.doc.resolveAppliedStyle(styleID, style.StyleTypeParagraph)
+		if err != nil {
+			return err
+		}
+		styleID = resolved
+	}
+	p.setStyleID(styleID)
+	return nil
+}
+
+// ApplyHeadingStyle 应用标题样式，level 为1-9
+// 文档自带的标题样式（如中文版Word的 "1"）优先于 Heading1 - Heading9
+func (p *Paragraph) ApplyHeadingStyle(level int) error {
+	if level < 1 || level > 9 {
+		return NewValidationError("heading_level", fmt.Sprintf("%d", level), "标题级别必须在1-9之间")
+	}
+	styleID := fmt.Sprintf("Heading%d", level)
+	if p.doc != nil {
+		styleID = p.doc.headingStyleIDs()[level-1]
+	}
+	return p.ApplyStyle(styleID)
+}
+
+// ApplyTitleStyle 应用文档标题样式
+func (p *Paragraph) ApplyTitleStyle() error {
+	return p.ApplyStyle(style.StyleTitle)
+}
+
+// ApplySubtitleStyle 应用副标题样式
+func (p *Paragraph) ApplySubtitleStyle() error {
+	return p.ApplyStyle(style.StyleSubtitle)
+}
+
+// ApplyQuoteStyle 应用引用样式
+func (p *Paragraph) ApplyQuoteStyle() error {
+	return p.ApplyStyle(style.StyleQuote)
+}
+
+// ApplyCodeBlockStyle 应用代码块样式
+func (p *Paragraph) ApplyCodeBlockStyle() error {
+	return p.ApplyStyle(style.StyleCodeBlock)
+}
+
+// ApplyListParagraphStyle 应用列表段落样式
+func (p *Paragraph) ApplyListParagraphStyle() error {
+	return p.ApplyStyle(style.StyleListParagraph)
+}
+
+// ApplyNormalStyle 恢复为文档的默认段落样式（移除段落样式引用）
+func (p *Paragraph) ApplyNormalStyle() error {
+	if p.Properties != nil {
+		p.Properties.ParagraphStyle = nil
+	}
+	Debugf("段落恢复默认样式")
+	return nil
+}
+
+// setStyleID 写入字符样式引用
+func (r *Run) setStyleID(styleID string) {
+	if r.Properties == nil {
+		r.Properties = &RunProperties{}
+	}
+	r.Properties.RunStyle = &RunStyle{Val: styleID}
+	Debugf("设置字符样式: %s", styleID)
+}
+
+// SetStyle 设置Run的字符样式（w:rStyle）。
+//
+// 文档中缺少的预定义字符样式会自动补充；样式不存在时仍写入样式引用并记录警告，
+// 需要校验结果时请使用 ApplyStyle。
+//
+// 示例:
+//
+//	para := doc.AddParagraph("普通文本")
+//	para.AddFormattedText("重点", nil)
+//	para.Runs[1].SetStyle("Strong")
+func (r *Run) SetStyle(styleID string) {
+	if err := r.ApplyStyle(styleID); err != nil {
+		Warnf("设置字符样式 %s 失败: %v", styleID, err)
+		r.setStyleID(styleID)
+	}
+}
+
+// ApplyStyle 应用字符样式
+//
+// Run属于文档时会校验样式：样式必须是字符样式，文档中缺少的预定义样式
+// （Strong、Emphasis、CodeChar）在首次使用时自动加入样式表。
+func (r *Run) ApplyStyle(styleID string) error {
+	if r.doc != nil {
+		resolved, err := r.doc.resolveAppliedStyle(styleID, style.StyleTypeCharacter)
+		if err != nil {
+			return err
+		}
+		styleID = resolved
+	}
+	r.setStyleID(styleID)
+	return nil
+}
+
+// applyLinkedStyle 应用段落样式的链接字符样式，不存在时根据段落样式的字符格式创建
+func (r *Run) applyLinkedStyle(paragraphStyleID string) error {
+	if r.doc == nil {
+		return NewValidationError("style", paragraphStyleID, "Run未关联文档，无法使用段落样式的链接字符样式")
+	}
+	resolved, err := r.doc.resolveAppliedStyle(paragraphStyleID, style.StyleTypeParagraph)
+	if err != nil {
+		return err
+	}
+	character, err := r.doc.styleManager.EnsureLinkedCharacterStyle(resolved)
+	if err != nil {
+		return WrapError("apply_linked_style", err)
+	}
+	r.setStyleID(character.StyleID)
+	return nil
+}
+
+// ApplyHeadingStyle 应用标题样式的链接字符样式，level 为1-9
+func (r *Run) ApplyHeadingStyle(level int) error {
+	if level < 1 || level > 9 {
+		return NewValidationError("heading_level", fmt.Sprintf("%d", level), "标题级别必须在1-9之间")
+	}
+	styleID := fmt.Sprintf("Heading%d", level)
+	if r.doc != nil {
+		styleID = r.doc.headingStyleIDs()[level-1]
+	}
+	return r.applyLinkedStyle(styleID)
+}
+
+// ApplyTitleStyle 应用文档标题样式的链接字符样式
+func (r *Run) ApplyTitleStyle() error {
+	return r.applyLinkedStyle(style.StyleTitle)
+}
+
+// ApplySubtitleStyle 应用副标题样式的链接字符样式
+func (r *Run) ApplySubtitleStyle() error {
+	return r.applyLinkedStyle(style.StyleSubtitle)
+}
+
+// ApplyQuoteStyle 应用引用样式的链接字符样式
+func (r *Run) ApplyQuoteStyle() error {
+	return r.applyLinkedStyle(style.StyleQuote)
+}
+
+// ApplyCodeBlockStyle 应用代码字符样式（CodeChar）
+func (r *Run) ApplyCodeBlockStyle() error {
+	return r.ApplyStyle(style.StyleCodeChar)
+}
+
+// ApplyListParagraphStyle 应用列表段落样式的链接字符样式
+func (r *Run) ApplyListParagraphStyle() error {
+	return r.applyLinkedStyle(style.StyleListParagraph)
+}
+
+// ApplyNormalStyle 恢复为默认字符格式（移除字符样式引用）
+func (r *Run) ApplyNormalStyle() error {
+	if r.Properties != nil {
+		r.Properties.RunStyle = nil
+	}
+	Debugf("Run恢复默认字符样式")
+	return nil
+}
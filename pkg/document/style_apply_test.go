@@ -0,0 +1,168 @@
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestParagraphAndRunStyleApplicator 测试段落和Run的样式应用与校验
+func TestParagraphAndRunStyleApplicator(t *testing.T) {
+	doc := New()
+	para := doc.AddParagraph("引用内容")
+	para.AddFormattedText("重点", nil)
+
+	if err := para.ApplyQuoteStyle(); err != nil || para.Properties.ParagraphStyle.Val != style.StyleQuote {
+		t.Fatalf("应用引用样式失败: %v", err)
+	}
+	if err := para.ApplyStyle(style.StyleStrong); err == nil {
+		t.Error("字符样式不能应用到段落")
+	}
+	if err := para.ApplyStyle("Missing"); err == nil || para.Properties.ParagraphStyle.Val != style.StyleQuote {
+		t.Error("不存在的样式应返回错误且不修改段落")
+	}
+	if err := para.ApplyHeadingStyle(10); err == nil {
+		t.Error("标题级别超出范围应返回错误")
+	}
+
+	run := &para.Runs[1]
+	if err := run.ApplyStyle(style.StyleStrong); err != nil || run.Properties.RunStyle.Val != style.StyleStrong {
+		t.Fatalf("应用字符样式失败: %v", err)
+	}
+	if err := run.ApplyStyle(style.StyleQuote); err == nil {
+		t.Error("段落样式不能应用到Run")
+	}
+	if err := run.ApplyHeadingStyle(2); err != nil {
+		t.Fatalf("应用标题链接字符样式失败: %v", err)
+	}
+	sm := doc.GetStyleManager()
+	linked := sm.GetStyle(run.Properties.RunStyle.Val)
+	if linked == nil || linked.Type != string(style.StyleTypeCharacter) || linked.Link.Val != "Heading2" ||
+		sm.GetStyle("Heading2").Link.Val != linked.StyleID || linked.RunPr.Bold == nil {
+		t.Errorf("标题的链接字符样式不正确: %+v", linked)
+	}
+	if err := run.ApplyNormalStyle(); err != nil || run.Properties.RunStyle != nil {
+		t.Error("恢复默认字符样式应移除字符样式引用")
+	}
+	if err := para.ApplyNormalStyle(); err != nil || para.Properties.ParagraphStyle != nil {
+		t.Error("恢复默认段落样式应移除段落样式引用")
+	}
+
+	// 未关联文档的段落只写入样式引用
+	detached := &Paragraph{}
+	if err := detached.ApplyStyle("Custom"); err != nil || detached.Properties.ParagraphStyle.Val != "Custom" {
+		t.Errorf("未关联文档的段落应直接写入样式引用: %v", err)
+	}
+}
+
+// TestApplyStyleInsertsPredefinedStyles 测试打开的文档在首次使用时补充预定义样式
+func TestApplyStyleInsertsPredefinedStyles(t *testing.T) {
+	doc := newReferenceDocument(t)
+	sm := doc.GetStyleManager()
+	if sm.GetStyle(style.StyleQuote) != nil || sm.GetStyle(style.StyleCodeChar) != nil {
+		t.Fatal("参考文档不应包含引用和代码字符样式")
+	}
+
+	quote := doc.AddParagraph("引用")
+	if err := quote.ApplyQuoteStyle(); err != nil {
+		t.Fatalf("应用引用样式失败: %v", err)
+	}
+	if s := sm.GetStyle(style.StyleQuote); s == nil || s.BasedOn.Val != "a" {
+		t.Errorf("补充的引用样式应基于文档自带的默认段落样式: %+v", s)
+	}
+	heading := doc.AddParagraph("标题")
+	if err := heading.ApplyStyle(style.StyleHeading1); err != nil || heading.Properties.ParagraphStyle.Val != "1" {
+		t.Errorf("应使用文档自带的同名标题样式: %+v", heading.Properties.ParagraphStyle)
+	}
+	code := doc.AddParagraph("")
+	code.AddFormattedText("fmt.Println()", nil)
+	code.Runs[len(code.Runs)-1].SetStyle(style.StyleCodeChar)
+
+	path := filepath.Join(t.TempDir(), "applied.docx")
+	if err := doc.Save(path); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("重新打开文档失败: %v", err)
+	}
+	stylesXML := string(reopened.parts["word/styles.xml"])
+	for _, id := range []string{style.StyleQuote, style.StyleCodeChar} {
+		if !strings.Contains(stylesXML, `w:styleId="`+id+`"`) {
+			t.Errorf("样式表应包含补充的样式 %s", id)
+		}
+	}
+	if strings.Contains(stylesXML, `w:styleId="Normal"`) || strings.Contains(stylesXML, `w:styleId="Title"`) {
+		t.Error("未使用的预定义样式不应写入样式表")
+	}
+
+	paragraphs := reopened.Body.GetParagraphs()
+	last := paragraphs[len(paragraphs)-1]
+	if err := last.Runs[0].ApplyStyle(style.StyleEmphasis); err != nil {
+		t.Errorf("打开的文档中的Run应关联文档: %v", err)
+	}
+	if err := last.Runs[0].ApplyStyle("1"); err == nil {
+		t.Error("打开的文档中的Run应校验样式类型")
+	}
+}
+
+// TestApplyStyleInTablesAndTemplates 测试表格单元格和模板渲染结果中的段落关联文档
+func TestApplyStyleInTablesAndTemplates(t *testing.T) {
+	doc := newReferenceDocument(t)
+	table, err := doc.AddTable(&TableConfig{Rows: 1, Cols: 2, Width: 4000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	if err := table.AppendRow([]string{"a", "b"}); err != nil {
+		t.Fatalf("添加行失败: %v", err)
+	}
+	if err := table.SetCellFormattedText(1, 1, "粗体", &TextFormat{Bold: true}); err != nil {
+		t.Fatalf("设置单元格失败: %v", err)
+	}
+	added, err := table.AddCellParagraph(0, 0, "新段落")
+	if err != nil {
+		t.Fatalf("添加单元格段落失败: %v", err)
+	}
+
+	cells := []*Paragraph{&table.Rows[0].Cells[0].Paragraphs[0], &table.Rows[1].Cells[0].Paragraphs[0], &table.Rows[1].Cells[1].Paragraphs[0], added}
+	for i, para := range cells {
+		if err := para.ApplyStyle("NoSuchStyle"); err == nil {
+			t.Errorf("第 %d 个单元格段落应校验样式", i)
+		}
+	}
+	if err := cells[2].Runs[0].ApplyStyle(style.StyleCodeChar); err != nil {
+		t.Errorf("单元格中的Run应关联文档: %v", err)
+	}
+	if err := cells[1].ApplyQuoteStyle(); err != nil {
+		t.Fatalf("应用引用样式失败: %v", err)
+	}
+	if doc.GetStyleManager().GetStyle(style.StyleQuote) == nil {
+		t.Error("单元格段落使用的预定义样式应加入样式表")
+	}
+
+	// 模板渲染结果中的段落关联到渲染生成的文档
+	doc.AddParagraph("{{#each items}}")
+	doc.AddParagraph("{{this}}")
+	doc.AddParagraph("{{/each}}")
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("styled", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+	data := NewTemplateData()
+	data.SetList("items", []interface{}{"一", "二"})
+	result, err := engine.RenderTemplateToDocument("styled", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+	for _, para := range result.Body.GetParagraphs() {
+		if err := para.ApplyStyle("NoSuchStyle"); err == nil {
+			t.Fatal("渲染结果中的段落应校验样式")
+		}
+	}
+	rendered := result.Body.GetTables()[0]
+	if err := rendered.Rows[0].Cells[0].Paragraphs[0].ApplyStyle("NoSuchStyle"); err == nil {
+		t.Error("渲染结果中表格的段落应校验样式")
+	}
+}
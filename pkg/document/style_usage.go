@@ -0,0 +1,391 @@
+// Package document 文档中样式的引用情况
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// usedStyleIDs 收集保存样式表时视为已使用的样式ID（段落、字符和表格样式）
+// 编号定义中的样式链接不计入，只被编号引用的隐式样式不写入样式表
+func (d *Document) usedStyleIDs() map[string]bool {
+	return d.referencedStyleIDs(false)
+}
+
+// referencedStyleIDs 收集文档中引用的样式ID
+//
+// 正文和已加载的页眉页脚从对象中收集，其余部件（未加载的页眉页脚、脚注、尾注、批注，
+// includeNumbering 为真时还包括编号定义）直接扫描XML，避免为此解析并重新序列化这些部件。
+func (d *Document) referencedStyleIDs(includeNumbering bool) map[string]bool {
+	used := make(map[string]bool)
+	containers := d.styleContainers()
+	for _, container := range containers {
+		forEachStyleReference(container.elements, func(styleID string, location StyleLocation) {
+			used[styleID] = true
+		})
+	}
+	d.scanStyleReferences(containers, includeNumbering, func(part, styleID string) {
+		used[styleID] = true
+	})
+	return used
+}
+
+// styleContainer 包含段落和表格的文档部件
+type styleContainer struct {
+	part     string
+	elements []interface{}
+}
+
+// styleContainers 返回正文和已加载的页眉页脚的内容，页眉页脚按部件名称排序
+func (d *Document) styleContainers() []styleContainer {
+	var containers []styleContainer
+	if d.Body != nil {
+		containers = append(containers, styleContainer{part: "word/document.xml", elements: d.Body.Elements})
+	}
+	var parts []styleContainer
+	for partName, header := range d.headers {
+		parts = append(parts, styleContainer{part: partName, elements: header.Elements})
+	}
+	for partName, footer := range d.footers {
+		parts = append(parts, styleContainer{part: partName, elements: footer.Elements})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].part < parts[j].part })
+	return append(containers, parts...)
+}
+
+// forEachStyleReference 按文档顺序遍历元素中的样式引用
+// 段落和文本样式的位置为段落序号，表格样式的位置为表格序号
+func forEachStyleReference(elements []interface{}, fn func(styleID string, location StyleLocation)) {
+	index := 0
+	forEachParagraph(elements, func(p *Paragraph) {
+		if p.Properties != nil && p.Properties.ParagraphStyle != nil {
+			fn(p.Properties.ParagraphStyle.Val, StyleLocation{Index: index, Run: -1})
+		}
+		for i := range p.Runs {
+			if props := p.Runs[i].Properties; props != nil && props.RunStyle != nil {
+				fn(props.RunStyle.Val, StyleLocation{Index: index, Run: i})
+			}
+		}
+		index++
+	})
+	tableIndex := 0
+	forEachTable(elements, func(table *Table) {
+		if table.Properties != nil && table.Properties.TableStyle != nil {
+			fn(table.Properties.TableStyle.Val, StyleLocation{Index: tableIndex, Run: -1})
+		}
+		tableIndex++
+	})
+}
+
+// scanStyleReferences 扫描未包含在 containers 中的文本部件XML中的样式引用
+// includeNumbering 为真时同时扫描编号定义中的 pStyle、styleLink 和 numStyleLink
+func (d *Document) scanStyleReferences(containers []styleContainer, includeNumbering bool, fn func(part, styleID string)) {
+	loaded := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		loaded[container.part] = true
+	}
+	for _, partName := range d.textPartNames(false) {
+		if loaded[partName] || (!includeNumbering && partName == "word/numbering.xml") {
+			continue
+		}
+		scanXMLElements(d.parts[partName], func(name xml.Name, attrs []xml.Attr, text string) {
+			switch name.Local {
+			case "pStyle", "rStyle", "tblStyle", "styleLink", "numStyleLink":
+			default:
+				return
+			}
+			for _, attr := range attrs {
+				if attr.Name.Local == "val" {
+					fn(partName, attr.Value)
+				}
+			}
+		})
+	}
+}
+
+// forEachTable 按文档顺序遍历元素中的所有表格（包括嵌套表格和SDT中的表格）
+func forEachTable(elements []interface{}, fn func(*Table)) {
+	var visit func(table *Table)
+	visit = func(table *Table) {
+		fn(table)
+		for i := range table.Rows {
+			for j := range table.Rows[i].Cells {
+				for k := range table.Rows[i].Cells[j].Tables {
+					visit(&table.Rows[i].Cells[j].Tables[k])
+				}
+			}
+		}
+	}
+
+	for _, element := range elements {
+		switch elem := element.(type) {
+		case *Table:
+			visit(elem)
+		case *SDT:
+			if elem.Content != nil {
+				forEachTable(elem.Content.Elements, fn)
+			}
+		}
+	}
+}
+
+// renameStyleReferences 按映射（旧ID -> 新ID）更新正文和所有页眉页脚中的样式引用
+// 未加载的页眉页脚直接修改部件XML中的样式引用，不解析为对象
+func (d *Document) renameStyleReferences(ids map[string]string) {
+	if len(ids) == 0 {
+		return
+	}
+	containers := d.styleContainers()
+	loaded := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		loaded[container.part] = true
+		renameElementStyles(container.elements, ids)
+	}
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type != headerRelationshipType && rel.Type != footerRelationshipType {
+			continue
+		}
+		partName := d.relationshipPartName(rel.ID)
+		if data, ok := d.parts[partName]; ok && !loaded[partName] {
+			d.parts[partName] = renameStylesInXML(data, ids)
+		}
+	}
+}
+
+// styleValPattern 匹配样式引用元素中的 val 属性值
+var styleValPattern = regexp.MustCompile(`\bval\s*=\s*["']([^"']*)["']`)
+
+// renameStylesInXML 按映射更新XML部件中段落、文本和表格的样式引用
+// 只替换样式引用的属性值，其余内容保持原样
+func renameStylesInXML(data []byte, ids map[string]string) []byte {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var output bytes.Buffer
+	last := 0
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err != nil {
+			break
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch element.Name.Local {
+		case "pStyle", "rStyle", "tblStyle":
+		default:
+			continue
+		}
+		newID, ok := ids[getAttributeValue(element.Attr, "val")]
+		if !ok {
+			continue
+		}
+		match := styleValPattern.FindSubmatchIndex(data[start:decoder.InputOffset()])
+		if match == nil {
+			continue
+		}
+		output.Write(data[last : start+match[2]])
+		xml.EscapeText(&output, []byte(newID))
+		last = start + match[3]
+	}
+	if last == 0 {
+		return data
+	}
+	output.Write(data[last:])
+	return output.Bytes()
+}
+
+// renameElementStyles 按映射更新元素中段落、文本和表格的样式引用
+func renameElementStyles(elements []interface{}, ids map[string]string) {
+	forEachParagraph(elements, func(p *Paragraph) {
+		if p.Properties != nil && p.Properties.ParagraphStyle != nil {
+			if newID, ok := ids[p.Properties.ParagraphStyle.Val]; ok {
+				p.Properties.ParagraphStyle.Val = newID
+			}
+		}
+		for i := range p.Runs {
+			if props := p.Runs[i].Properties; props != nil && props.RunStyle != nil {
+				if newID, ok := ids[props.RunStyle.Val]; ok {
+					props.RunStyle.Val = newID
+				}
+			}
+		}
+	})
+	forEachTable(elements, func(table *Table) {
+		if table.Properties != nil && table.Properties.TableStyle != nil {
+			if newID, ok := ids[table.Properties.TableStyle.Val]; ok {
+				table.Properties.TableStyle.Val = newID
+			}
+		}
+	})
+}
+
+// StyleUsageReport 文档的样式使用情况
+type StyleUsageReport struct {
+	// Styles 样式表中所有样式的使用情况，按样式ID排序
+	Styles []*StyleUsage
+	// Missing 被引用但样式表中不存在的样式ID（已排序）
+	Missing []string
+	// DirectFormatting 带有直接格式的段落和文本，按文档顺序排列
+	DirectFormatting []*DirectFormatting
+}
+
+// StyleUsage 单个样式的使用情况
+type StyleUsage struct {
+	StyleID string
+	Name    string
+	Type    style.StyleType
+	// Locations 直接引用该样式的位置
+	Locations []StyleLocation
+	// Used 样式被引用、是默认样式，或被使用的样式通过 basedOn、next、link 引用
+	// 为假的样式会被 RemoveUnusedStyles 删除
+	Used bool
+}
+
+// StyleLocation 样式引用或直接格式在文档中的位置
+type StyleLocation struct {
+	// Part 部件名称，如 word/document.xml、word/header1.xml
+	Part string
+	// Index 段落序号（段落和字符样式），或表格序号（表格样式），按文档顺序从0开始，
+	// 包括表格单元格和内容控件中的内容；从未解析的部件（如脚注、批注）中扫描到的引用为-1
+	Index int
+	// Run 文本在段落中的序号，段落和表格为-1
+	Run int
+}
+
+// DirectFormatting 段落或文本上的直接格式（未通过样式设置的格式）
+type DirectFormatting struct {
+	Location StyleLocation
+	// StyleID 段落或文本引用的样式ID，未引用时为空
+	StyleID string
+	// Text 段落或文本的内容
+	Text string
+	// Properties 直接设置的属性（XML元素名称，如 "b"、"sz"、"jc"）
+	Properties []string
+}
+
+// AnalyzeStyleUsage 分析文档的样式使用情况
+//
+// 报告样式表中每个样式在正文、页眉页脚中被引用的位置，以及带有直接格式的段落和文本。
+// 段落的编号和节属性不视为直接格式。脚注、尾注、批注和编号定义中的样式引用同样计入，
+// 但不报告其中的直接格式。
+func (d *Document) AnalyzeStyleUsage() *StyleUsageReport {
+	report := &StyleUsageReport{}
+	locations := make(map[string][]StyleLocation)
+
+	containers := d.analysisContainers()
+	for _, container := range containers {
+		part := container.part
+		forEachStyleReference(container.elements, func(styleID string, location StyleLocation) {
+			location.Part = part
+			locations[styleID] = append(locations[styleID], location)
+		})
+
+		index := 0
+		forEachParagraph(container.elements, func(p *Paragraph) {
+			if data := paragraphFormattingXML(p.Properties); data != nil {
+				formatting := &DirectFormatting{
+					Location:   StyleLocation{Part: part, Index: index, Run: -1},
+					Text:       d.extractParagraphText(p),
+					Properties: formattingElementNames(data),
+				}
+				if p.Properties.ParagraphStyle != nil {
+					formatting.StyleID = p.Properties.ParagraphStyle.Val
+				}
+				report.DirectFormatting = append(report.DirectFormatting, formatting)
+			}
+			for i := range p.Runs {
+				run := &p.Runs[i]
+				if data := runFormattingXML(run.Properties); data != nil {
+					formatting := &DirectFormatting{
+						Location:   StyleLocation{Part: part, Index: index, Run: i},
+						Text:       run.Text.Content,
+						Properties: formattingElementNames(data),
+					}
+					if run.Properties.RunStyle != nil {
+						formatting.StyleID = run.Properties.RunStyle.Val
+					}
+					report.DirectFormatting = append(report.DirectFormatting, formatting)
+				}
+			}
+			index++
+		})
+	}
+	d.scanStyleReferences(containers, true, func(part, styleID string) {
+		locations[styleID] = append(locations[styleID], StyleLocation{Part: part, Index: -1, Run: -1})
+	})
+
+	used := d.styleManager.UsedStyles(func(styleID string) bool {
+		return len(locations[styleID]) > 0
+	})
+	for _, s := range d.styleManager.GetAllStyles() {
+		usage := &StyleUsage{
+			StyleID:   s.StyleID,
+			Type:      style.StyleType(s.Type),
+			Locations: locations[s.StyleID],
+			Used:      used[s.StyleID],
+		}
+		if s.Name != nil {
+			usage.Name = s.Name.Val
+		}
+		report.Styles = append(report.Styles, usage)
+	}
+	sort.Slice(report.Styles, func(i, j int) bool { return report.Styles[i].StyleID < report.Styles[j].StyleID })
+
+	for styleID := range locations {
+		if !d.styleManager.StyleExists(styleID) {
+			report.Missing = append(report.Missing, styleID)
+		}
+	}
+	sort.Strings(report.Missing)
+	return report
+}
+
+// analysisContainers 返回正文和所有页眉页脚的内容
+// 未加载的页眉页脚解析为临时对象，不加入缓存，不影响保存时的原样输出
+func (d *Document) analysisContainers() []styleContainer {
+	containers := d.styleContainers()
+	loaded := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		loaded[container.part] = true
+	}
+	var parts []styleContainer
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type != headerRelationshipType && rel.Type != footerRelationshipType {
+			continue
+		}
+		partName := d.relationshipPartName(rel.ID)
+		if loaded[partName] {
+			continue
+		}
+		loaded[partName] = true
+		elements, err := d.headerFooterElements(partName)
+		if err != nil {
+			Warnf("解析页眉页脚失败 %s: %v", partName, err)
+			continue
+		}
+		parts = append(parts, styleContainer{part: partName, elements: elements})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].part < parts[j].part })
+	return append(containers, parts...)
+}
+
+// RemoveUnusedStyles 删除文档中未被使用的样式，返回已排序的被删除样式ID
+//
+// 默认样式、正文、页眉页脚、脚注、尾注、批注和编号定义中引用的样式，以及它们通过
+// basedOn、next、link 直接或间接引用的样式会被保留。
+func (d *Document) RemoveUnusedStyles() []string {
+	used := d.referencedStyleIDs(true)
+	removed := d.styleManager.RemoveUnusedStyles(func(styleID string) bool {
+		return used[styleID]
+	})
+	if len(removed) > 0 {
+		Infof("删除了 %d 个未使用的样式: %s", len(removed), strings.Join(removed, ", "))
+	}
+	return removed
+}
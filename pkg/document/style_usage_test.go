@@ -0,0 +1,214 @@
+package document
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestStylesRoundTripOnSave 测试打开后再保存的文档保持样式表不变，只写入用到的隐式样式
+func TestStylesRoundTripOnSave(t *testing.T) {
+	dir := t.TempDir()
+	doc := New()
+	doc.AddParagraph("正文")
+	first := filepath.Join(dir, "first.docx")
+	if err := doc.Save(first); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	opened, err := Open(first)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	original := string(opened.parts["word/styles.xml"])
+	second := filepath.Join(dir, "second.docx")
+	if err := opened.Save(second); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	if got := string(opened.parts["word/styles.xml"]); got != original {
+		t.Errorf("未修改样式时 styles.xml 应保持不变:\n%s", got)
+	}
+
+	// 表格样式和字符样式的引用都会被识别
+	opened.AddHeadingParagraph("标题", 1)
+	table, err := opened.AddTable(&TableConfig{Rows: 1, Cols: 1, Width: 2000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.Properties.TableStyle = &TableStyle{Val: "TableGrid"}
+	used := opened.usedStyleIDs()
+	if !used["Heading1"] || !used["TableGrid"] {
+		t.Errorf("样式引用收集不正确: %v", used)
+	}
+	if err := opened.Save(second); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	if stylesXML := string(opened.parts["word/styles.xml"]); !strings.Contains(stylesXML, `w:styleId="Heading1"`) ||
+		!strings.HasPrefix(stylesXML, original[:strings.Index(original, "<w:style ")]) {
+		t.Errorf("保存后的 styles.xml 不正确:\n%s", stylesXML)
+	}
+}
+
+// TestAnalyzeStyleUsage 测试样式使用情况分析和未使用样式的删除
+func TestAnalyzeStyleUsage(t *testing.T) {
+	doc := New()
+	doc.AddHeadingParagraph("标题", 1)
+	doc.AddParagraph("缺失样式").SetStyle("Missing")
+	para := doc.AddParagraph("")
+	para.AddFormattedText("加粗", &TextFormat{Bold: true})
+	para.SetAlignment(AlignCenter)
+
+	sm := doc.GetStyleManager()
+	sm.AddStyle(&style.Style{Type: string(style.StyleTypeParagraph), StyleID: "HeaderText", Name: &style.StyleName{Val: "Header Text"}})
+	sm.AddStyle(&style.Style{Type: string(style.StyleTypeParagraph), StyleID: "Unused", Name: &style.StyleName{Val: "Unused"}})
+	header := createStandardHeader()
+	header.AddParagraph("页眉").SetStyle("HeaderText")
+	if err := doc.storeHeader(HeaderFooterTypeDefault, header); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+
+	report := doc.AnalyzeStyleUsage()
+	usages := make(map[string]*StyleUsage)
+	for _, usage := range report.Styles {
+		usages[usage.StyleID] = usage
+	}
+	if got := usages["Heading1"].Locations; len(got) != 1 || got[0] != (StyleLocation{Part: "word/document.xml", Index: 0, Run: -1}) {
+		t.Errorf("标题样式的位置不正确: %+v", got)
+	}
+	if got := usages["HeaderText"].Locations; len(got) != 1 || !strings.HasPrefix(got[0].Part, "word/header") {
+		t.Errorf("页眉中的样式引用应被识别: %+v", got)
+	}
+	if !usages["Normal"].Used || !usages["Heading1"].Used || usages["Unused"].Used || usages["Heading2"].Used {
+		t.Error("样式使用标记不正确")
+	}
+	if !reflect.DeepEqual(report.Missing, []string{"Missing"}) {
+		t.Errorf("缺失样式不正确: %v", report.Missing)
+	}
+
+	var paragraphFormatting, runFormatting *DirectFormatting
+	for _, formatting := range report.DirectFormatting {
+		if formatting.Location.Index == 2 && formatting.Location.Run == -1 {
+			paragraphFormatting = formatting
+		}
+		if formatting.Text == "加粗" {
+			runFormatting = formatting
+		}
+	}
+	if paragraphFormatting == nil || !reflect.DeepEqual(paragraphFormatting.Properties, []string{"jc"}) {
+		t.Errorf("段落直接格式不正确: %+v", paragraphFormatting)
+	}
+	if runFormatting == nil || !containsString(runFormatting.Properties, "b") {
+		t.Errorf("文本直接格式不正确: %+v", runFormatting)
+	}
+
+	removed := doc.RemoveUnusedStyles()
+	if !containsString(removed, "Unused") || !containsString(removed, "Heading2") {
+		t.Errorf("未使用的样式应被删除: %v", removed)
+	}
+	for _, styleID := range []string{"Normal", "Heading1", "HeaderText"} {
+		if !sm.StyleExists(styleID) {
+			t.Errorf("使用中的样式 %s 不应被删除", styleID)
+		}
+	}
+}
+
+// TestStyleScansKeepHeadersUnloaded 测试样式分析、清理和重命名不解析未加载的页眉
+func TestStyleScansKeepHeadersUnloaded(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+	doc.GetStyleManager().AddStyle(&style.Style{Type: string(style.StyleTypeParagraph), StyleID: "HeaderText", Name: &style.StyleName{Val: "Header Text"}})
+	header := createStandardHeader()
+	header.AddParagraph("页眉").SetStyle("HeaderText")
+	if err := doc.storeHeader(HeaderFooterTypeDefault, header); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "styles.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	partName := opened.headerFooterPartName(HeaderFooterTypeDefault, true)
+	original := string(opened.parts[partName])
+	report := opened.AnalyzeStyleUsage()
+	removed := opened.RemoveUnusedStyles()
+	if err := opened.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	if len(opened.headers) != 0 || string(opened.parts[partName]) != original {
+		t.Errorf("样式分析和清理不应解析或改写页眉，已缓存 %d 个", len(opened.headers))
+	}
+	if len(report.Missing) != 0 || containsString(removed, "HeaderText") {
+		t.Errorf("页眉中的样式引用应被识别: %v %v", report.Missing, removed)
+	}
+
+	opened.renameStyleReferences(map[string]string{"HeaderText": "PageHeader"})
+	if len(opened.headers) != 0 {
+		t.Error("重命名样式引用不应解析页眉")
+	}
+	renamed := string(opened.parts[partName])
+	if !strings.Contains(renamed, `w:val="PageHeader"`) || strings.Contains(renamed, "HeaderText") {
+		t.Errorf("页眉中的样式引用应被重命名: %s", renamed)
+	}
+	if strings.Replace(renamed, "PageHeader", "HeaderText", 1) != original {
+		t.Error("重命名只应修改样式引用")
+	}
+}
+
+// TestNormalizeFormatting 测试重复的直接格式转换为样式并合并相邻文本
+func TestNormalizeFormatting(t *testing.T) {
+	doc := New()
+	for i := 0; i < 3; i++ {
+		para := doc.AddParagraph("")
+		para.Runs = nil
+		para.AddFormattedText("重点", &TextFormat{Bold: true, FontColor: "FF0000"})
+		para.AddFormattedText("内容", &TextFormat{Bold: true, FontColor: "FF0000"})
+		para.AddFormattedText("普通", nil)
+		para.SetAlignment(AlignCenter)
+	}
+	single := doc.AddParagraph("单独")
+	single.SetIndentation(0.5, 0, 0)
+
+	// 格式相同的现有段落样式被直接引用
+	doc.GetStyleManager().AddStyle(&style.Style{
+		Type:        string(style.StyleTypeParagraph),
+		StyleID:     "Centered",
+		Name:        &style.StyleName{Val: "Centered"},
+		BasedOn:     &style.BasedOn{Val: "Normal"},
+		ParagraphPr: &style.ParagraphProperties{Justification: &style.Justification{Val: "center"}},
+	})
+
+	result, err := doc.NormalizeFormatting(nil)
+	if err != nil {
+		t.Fatalf("转换直接格式失败: %v", err)
+	}
+	if !reflect.DeepEqual(result.ReusedStyles, []string{"Centered"}) || result.ParagraphsUpdated != 3 {
+		t.Errorf("段落格式应转换为现有样式: %+v", result)
+	}
+	if !reflect.DeepEqual(result.CreatedStyles, []string{"NormalizedCharacter1"}) || result.RunsUpdated != 6 || result.RunsMerged != 3 {
+		t.Errorf("文本格式转换结果不正确: %+v", result)
+	}
+
+	created := doc.GetStyleManager().GetStyle("NormalizedCharacter1")
+	if created == nil || created.Type != string(style.StyleTypeCharacter) || created.RunPr.Bold == nil || created.RunPr.Color.Val != "FF0000" {
+		t.Fatalf("新建的字符样式不正确: %+v", created)
+	}
+
+	para := doc.Body.Elements[0].(*Paragraph)
+	if para.Properties.ParagraphStyle.Val != "Centered" || para.Properties.Justification != nil {
+		t.Errorf("段落应改为引用样式: %+v", para.Properties)
+	}
+	if len(para.Runs) != 2 || para.Runs[0].Text.Content != "重点内容" ||
+		!reflect.DeepEqual(para.Runs[0].Properties, &RunProperties{RunStyle: &RunStyle{Val: "NormalizedCharacter1"}}) {
+		t.Errorf("相邻的相同格式文本应被合并: %+v", para.Runs)
+	}
+	if single.Properties.ParagraphStyle != nil || single.Properties.Indentation == nil {
+		t.Error("只出现一次的直接格式应保持不变")
+	}
+}
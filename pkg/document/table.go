@@ -13,20 +13,23 @@ type Table struct {
 	Properties *TableProperties `xml:"w:tblPr,omitempty"`
 	Grid       *TableGrid       `xml:"w:tblGrid,omitempty"`
 	Rows       []TableRow       `xml:"w:tr"`
+
+	doc *Document // 所属文档，单元格中的段落据此应用样式
 }
 
 // TableProperties 表格属性
+// 注意：字段顺序必须符合OpenXML标准，w:tblStyle在最前，w:tblLook在最后
 type TableProperties struct {
 	XMLName      xml.Name          `xml:"w:tblPr"`
+	TableStyle   *TableStyle       `xml:"w:tblStyle,omitempty"` // 表格样式
 	TableW       *TableWidth       `xml:"w:tblW,omitempty"`
 	TableJc      *TableJc          `xml:"w:jc,omitempty"`
-	TableLook    *TableLook        `xml:"w:tblLook,omitempty"`
-	TableStyle   *TableStyle       `xml:"w:tblStyle,omitempty"`   // 表格样式
+	TableInd     *TableIndentation `xml:"w:tblInd,omitempty"`     // 表格缩进
 	TableBorders *TableBorders     `xml:"w:tblBorders,omitempty"` // 表格边框
 	Shd          *TableShading     `xml:"w:shd,omitempty"`        // 表格底纹/背景
-	TableCellMar *TableCellMargins `xml:"w:tblCellMar,omitempty"` // 表格单元格边距
 	TableLayout  *TableLayoutType  `xml:"w:tblLayout,omitempty"`  // 表格布局类型
-	TableInd     *TableIndentation `xml:"w:tblInd,omitempty"`     // 表格缩进
+	TableCellMar *TableCellMargins `xml:"w:tblCellMar,omitempty"` // 表格单元格边距
+	TableLook    *TableLook        `xml:"w:tblLook,omitempty"`    // 表格外观（条件格式开关）
 }
 
 // TableWidth 表格宽度
@@ -356,7 +359,7 @@ func (d *Document) CreateTable(config *TableConfig) (*Table, error) {
 	}
 
 	Info(fmt.Sprintf("创建表格成功：%d行 x %d列", config.Rows, config.Cols))
-	return table, nil
+	return d.bindTable(table), nil
 }
 
 // AddTable 将表格添加到文档中
@@ -442,6 +445,7 @@ func (t *Table) InsertRow(position int, data []string) error {
 		if i < len(data) {
 			newRow.Cells[i].Paragraphs[0].Runs[0].Text.Content = data[i]
 		}
+		t.bindCell(&newRow.Cells[i])
 	}
 
 	// 插入行
@@ -553,6 +557,7 @@ func (t *Table) InsertColumn(position int, data []string, width int) error {
 		if i < len(data) {
 			newCell.Paragraphs[0].Runs[0].Text.Content = data[i]
 		}
+		t.bindCell(&newCell)
 
 		// 插入单元格
 		if position == len(t.Rows[i].Cells) {
@@ -673,6 +678,7 @@ func (t *Table) SetCellText(row, col int, text string) error {
 			cell.Paragraphs[0].Runs[0].Text.Content = text
 		}
 	}
+	t.bindCell(cell)
 
 	return nil
 }
@@ -727,6 +733,7 @@ func (t *Table) ClearTable() {
 					},
 				},
 			}
+			t.bindCell(&t.Rows[i].Cells[j])
 		}
 	}
 	Info("表格内容已清空")
@@ -772,6 +779,9 @@ func (t *Table) CopyTable() *Table {
 	}
 
 	Info("表格复制成功")
+	if t.doc != nil {
+		t.doc.bindTable(newTable)
+	}
 	return newTable
 }
 
@@ -859,6 +869,7 @@ func (t *Table) SetCellFormat(row, col int, format *CellFormat) error {
 	// 确保单元格有段落
 	if len(cell.Paragraphs) == 0 {
 		cell.Paragraphs = []Paragraph{{}}
+		t.bindCell(cell)
 	}
 
 	// 设置水平对齐
@@ -913,6 +924,7 @@ func (t *Table) SetCellFormat(row, col int, format *CellFormat) error {
 				ASCII: format.TextFormat.FontFamily,
 			}
 		}
+		applyThemeFormat(run.Properties, format.TextFormat)
 	}
 
 	Info(fmt.Sprintf("设置单元格(%d,%d)格式成功", row, col))
@@ -953,6 +965,7 @@ func (t *Table) SetCellFormattedText(row, col int, text string, format *TextForm
 				Val: format.FontColor,
 			}
 		}
+		applyThemeFormat(run.Properties, format)
 
 		if format.FontSize > 0 {
 			run.Properties.FontSize = &FontSize{
@@ -967,6 +980,7 @@ func (t *Table) SetCellFormattedText(row, col int, text string, format *TextForm
 			Runs: []Run{run},
 		},
 	}
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("设置单元格(%d,%d)富文本内容成功", row, col))
 	return nil
@@ -1011,6 +1025,7 @@ func (t *Table) AddCellFormattedText(row, col int, text string, format *TextForm
 				Val: format.FontColor,
 			}
 		}
+		applyThemeFormat(run.Properties, format)
 
 		if format.FontSize > 0 {
 			run.Properties.FontSize = &FontSize{
@@ -1021,6 +1036,7 @@ func (t *Table) AddCellFormattedText(row, col int, text string, format *TextForm
 
 	// 添加运行到第一个段落
 	cell.Paragraphs[0].Runs = append(cell.Paragraphs[0].Runs, run)
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("添加格式化文本到单元格(%d,%d)成功", row, col))
 	return nil
@@ -1104,6 +1120,7 @@ func (t *Table) MergeCellsVertical(startRow, endRow, col int) error {
 		}
 		// 清空被合并单元格的内容
 		cell.Paragraphs = []Paragraph{{}}
+		t.bindCell(cell)
 	}
 
 	Info(fmt.Sprintf("垂直合并单元格：行%d到%d，列%d", startRow, endRow, col))
@@ -1171,6 +1188,7 @@ func (t *Table) UnmergeCells(row, col int) error {
 				},
 				Paragraphs: []Paragraph{{}},
 			}
+			t.bindCell(&newCell)
 
 			// 在指定位置插入新单元格
 			insertPos := col + i
@@ -1198,6 +1216,7 @@ func (t *Table) UnmergeCells(row, col int) error {
 						otherCell.Properties.VMerge = nil
 						if len(otherCell.Paragraphs) == 0 {
 							otherCell.Paragraphs = []Paragraph{{}}
+							t.bindCell(otherCell)
 						}
 					} else {
 						break
@@ -2084,6 +2103,9 @@ type CellBorderConfig struct {
 }
 
 // ApplyTableStyle 应用表格样式
+//
+// 表格引用样式模板或自定义表格样式，并通过 w:tblLook 开启样式中的条件格式。
+// 样式模板对应的表格样式会在保存文档时自动加入样式表。
 func (t *Table) ApplyTableStyle(config *TableStyleConfig) error {
 	if t.Properties == nil {
 		t.Properties = &TableProperties{}
@@ -2100,66 +2122,8 @@ func (t *Table) ApplyTableStyle(config *TableStyleConfig) error {
 		}
 	}
 
-	// 设置表格外观选项
-	if t.Properties.TableLook == nil {
-		t.Properties.TableLook = &TableLook{}
-	}
-
-	// 构建TableLook值
-	lookVal := "0000"
-	if config.FirstRowHeader {
-		t.Properties.TableLook.FirstRow = "1"
-		lookVal = "0400"
-	} else {
-		t.Properties.TableLook.FirstRow = "0"
-	}
-
-	if config.LastRowTotal {
-		t.Properties.TableLook.LastRow = "1"
-		if lookVal == "0400" {
-			lookVal = "0440"
-		} else {
-			lookVal = "0040"
-		}
-	} else {
-		t.Properties.TableLook.LastRow = "0"
-	}
-
-	if config.FirstColumnHeader {
-		t.Properties.TableLook.FirstCol = "1"
-		switch lookVal {
-		case "0400":
-			lookVal = "0500"
-		case "0040":
-			lookVal = "0140"
-		case "0440":
-			lookVal = "0540"
-		default:
-			lookVal = "0100"
-		}
-	} else {
-		t.Properties.TableLook.FirstCol = "0"
-	}
-
-	if config.LastColumnTotal {
-		t.Properties.TableLook.LastCol = "1"
-	} else {
-		t.Properties.TableLook.LastCol = "0"
-	}
-
-	if config.BandedRows {
-		t.Properties.TableLook.NoHBand = "0"
-	} else {
-		t.Properties.TableLook.NoHBand = "1"
-	}
-
-	if config.BandedColumns {
-		t.Properties.TableLook.NoVBand = "0"
-	} else {
-		t.Properties.TableLook.NoVBand = "1"
-	}
-
-	t.Properties.TableLook.Val = lookVal
+	// 设置表格外观选项，表格样式中对应的条件格式（标题行、镶边行等）据此生效
+	t.Properties.TableLook = newTableLook(config)
 
 	Info(fmt.Sprintf("应用表格样式成功：%s", config.Template))
 	return nil
@@ -2270,6 +2234,10 @@ func (t *Table) SetCellShading(row, col int, config *ShadingConfig) error {
 }
 
 // SetAlternatingRowColors 设置奇偶行颜色交替
+//
+// 该方法直接设置每个单元格的底纹，插入或删除行后不会重新计算；
+// 需要随行数变化保持正确的镶边效果时，应使用带 band1Horz 条件格式的表格样式
+// （参见 style.NewTableStyleBuilder）并通过 ApplyTableStyle 开启 BandedRows。
 func (t *Table) SetAlternatingRowColors(evenRowColor, oddRowColor string) error {
 	for i := range t.Rows {
 		var bgColor string
@@ -2676,6 +2644,7 @@ func (t *Table) AddCellParagraph(row, col int, text string) (*Paragraph, error)
 
 	// 添加到单元格
 	cell.Paragraphs = append(cell.Paragraphs, *para)
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("向单元格(%d,%d)添加段落成功", row, col))
 	return &cell.Paragraphs[len(cell.Paragraphs)-1], nil
@@ -2722,6 +2691,7 @@ func (t *Table) AddCellFormattedParagraph(row, col int, text string, format *Tex
 			color := strings.TrimPrefix(format.FontColor, "#")
 			runProps.Color = &Color{Val: color}
 		}
+		applyThemeFormat(runProps, format)
 
 		if format.FontSize > 0 {
 			runProps.FontSize = &FontSize{Val: fmt.Sprintf("%d", format.FontSize*2)}
@@ -2755,6 +2725,7 @@ func (t *Table) AddCellFormattedParagraph(row, col int, text string, format *Tex
 
 	// 添加到单元格
 	cell.Paragraphs = append(cell.Paragraphs, *para)
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("向单元格(%d,%d)添加格式化段落成功", row, col))
 	return &cell.Paragraphs[len(cell.Paragraphs)-1], nil
@@ -2783,6 +2754,7 @@ func (t *Table) ClearCellParagraphs(row, col int) error {
 			},
 		},
 	}
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("清空单元格(%d,%d)段落成功", row, col))
 	return nil
@@ -2925,6 +2897,7 @@ func (t *Table) AddNestedTable(row, col int, config *TableConfig) (*Table, error
 
 	// 添加到单元格的嵌套表格列表
 	cell.Tables = append(cell.Tables, *nestedTable)
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("向单元格(%d,%d)添加嵌套表格成功：%d行 x %d列", row, col, config.Rows, config.Cols))
 	return &cell.Tables[len(cell.Tables)-1], nil
@@ -3018,6 +2991,7 @@ func (t *Table) AddCellList(row, col int, config *CellListConfig) error {
 		// 添加到单元格
 		cell.Paragraphs = append(cell.Paragraphs, para)
 	}
+	t.bindCell(cell)
 
 	Info(fmt.Sprintf("向单元格(%d,%d)添加列表成功：%d个列表项", row, col, len(config.Items)))
 	return nil
@@ -0,0 +1,277 @@
+// Package document 表格样式模板与条件格式
+package document
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// tblLook 的 w:val 十六进制位掩码
+const (
+	tableLookFirstRow uint64 = 0x0020
+	tableLookLastRow  uint64 = 0x0040
+	tableLookFirstCol uint64 = 0x0080
+	tableLookLastCol  uint64 = 0x0100
+	tableLookNoHBand  uint64 = 0x0200
+	tableLookNoVBand  uint64 = 0x0400
+)
+
+// newTableLook 根据表格样式配置创建表格外观，同时写入位掩码和独立属性
+func newTableLook(config *TableStyleConfig) *TableLook {
+	var bits uint64
+	flag := func(enabled bool, mask uint64) string {
+		if enabled {
+			bits |= mask
+			return "1"
+		}
+		return "0"
+	}
+
+	look := &TableLook{
+		FirstRow: flag(config.FirstRowHeader, tableLookFirstRow),
+		LastRow:  flag(config.LastRowTotal, tableLookLastRow),
+		FirstCol: flag(config.FirstColumnHeader, tableLookFirstCol),
+		LastCol:  flag(config.LastColumnTotal, tableLookLastCol),
+		NoHBand:  flag(!config.BandedRows, tableLookNoHBand),
+		NoVBand:  flag(!config.BandedColumns, tableLookNoVBand),
+	}
+	look.Val = fmt.Sprintf("%04X", bits)
+	return look
+}
+
+// IsEnabled 判断表格外观是否启用了指定的条件格式
+// 优先读取独立属性（如 w:firstRow），不存在时读取 w:val 位掩码
+func (tl *TableLook) IsEnabled(condition style.TableStyleOverrideType) bool {
+	if tl == nil {
+		return condition == style.TableConditionWholeTable
+	}
+
+	var bits uint64
+	if tl.Val != "" {
+		if parsed, err := strconv.ParseUint(tl.Val, 16, 32); err == nil {
+			bits = parsed
+		}
+	}
+	flag := func(attr string, mask uint64) bool {
+		if attr != "" {
+			return parseOnOff(attr)
+		}
+		return bits&mask != 0
+	}
+
+	firstRow := flag(tl.FirstRow, tableLookFirstRow)
+	lastRow := flag(tl.LastRow, tableLookLastRow)
+	firstCol := flag(tl.FirstCol, tableLookFirstCol)
+	lastCol := flag(tl.LastCol, tableLookLastCol)
+
+	switch condition {
+	case style.TableConditionWholeTable:
+		return true
+	case style.TableConditionFirstRow:
+		return firstRow
+	case style.TableConditionLastRow:
+		return lastRow
+	case style.TableConditionFirstCol:
+		return firstCol
+	case style.TableConditionLastCol:
+		return lastCol
+	case style.TableConditionBand1Horz, style.TableConditionBand2Horz:
+		return !flag(tl.NoHBand, tableLookNoHBand)
+	case style.TableConditionBand1Vert, style.TableConditionBand2Vert:
+		return !flag(tl.NoVBand, tableLookNoVBand)
+	case style.TableConditionNWCell:
+		return firstRow && firstCol
+	case style.TableConditionNECell:
+		return firstRow && lastCol
+	case style.TableConditionSWCell:
+		return lastRow && firstCol
+	case style.TableConditionSECell:
+		return lastRow && lastCol
+	}
+	return false
+}
+
+// cellConditions 返回单元格适用的条件格式类型，按应用顺序排列
+// 镶边行（列）跳过已启用的标题行和汇总行（首列和末列），每个镶边包含的行（列）数来自表格样式
+func cellConditions(table *Table, tableStyle *style.Style, row, col int) []style.TableStyleOverrideType {
+	var look *TableLook
+	if table.Properties != nil {
+		look = table.Properties.TableLook
+	}
+	rowCount, colCount := table.GetRowCount(), 0
+	if row >= 0 && row < rowCount {
+		colCount = len(table.Rows[row].Cells)
+	}
+
+	rowBand, colBand := 1, 1
+	if tableStyle != nil && tableStyle.TablePr != nil {
+		if tableStyle.TablePr.RowBandSize != nil {
+			if size, err := strconv.Atoi(tableStyle.TablePr.RowBandSize.Val); err == nil && size > 0 {
+				rowBand = size
+			}
+		}
+		if tableStyle.TablePr.ColBandSize != nil {
+			if size, err := strconv.Atoi(tableStyle.TablePr.ColBandSize.Val); err == nil && size > 0 {
+				colBand = size
+			}
+		}
+	}
+
+	firstRow := row == 0 && look.IsEnabled(style.TableConditionFirstRow)
+	lastRow := row == rowCount-1 && look.IsEnabled(style.TableConditionLastRow)
+	firstCol := col == 0 && look.IsEnabled(style.TableConditionFirstCol)
+	lastCol := col == colCount-1 && look.IsEnabled(style.TableConditionLastCol)
+
+	applies := map[style.TableStyleOverrideType]bool{
+		style.TableConditionWholeTable: true,
+		style.TableConditionFirstRow:   firstRow,
+		style.TableConditionLastRow:    lastRow,
+		style.TableConditionFirstCol:   firstCol,
+		style.TableConditionLastCol:    lastCol,
+		style.TableConditionNWCell:     firstRow && firstCol,
+		style.TableConditionNECell:     firstRow && lastCol,
+		style.TableConditionSWCell:     lastRow && firstCol,
+		style.TableConditionSECell:     lastRow && lastCol,
+	}
+
+	if !firstRow && !lastRow && look.IsEnabled(style.TableConditionBand1Horz) {
+		index := row
+		if look.IsEnabled(style.TableConditionFirstRow) {
+			index--
+		}
+		if (index/rowBand)%2 == 0 {
+			applies[style.TableConditionBand1Horz] = true
+		} else {
+			applies[style.TableConditionBand2Horz] = true
+		}
+	}
+	if !firstCol && !lastCol && look.IsEnabled(style.TableConditionBand1Vert) {
+		index := col
+		if look.IsEnabled(style.TableConditionFirstCol) {
+			index--
+		}
+		if (index/colBand)%2 == 0 {
+			applies[style.TableConditionBand1Vert] = true
+		} else {
+			applies[style.TableConditionBand2Vert] = true
+		}
+	}
+
+	var result []style.TableStyleOverrideType
+	for _, condition := range style.TableConditionOrder {
+		if applies[condition] {
+			result = append(result, condition)
+		}
+	}
+	return result
+}
+
+// tableTemplateAccent 彩色模板使用的主题强调色及其浅色
+var tableTemplateAccent = map[TableStyleTemplate][2]string{
+	TableStyleTemplateColorful1: {"4472C4", "D9E2F3"},
+	TableStyleTemplateColorful2: {"ED7D31", "FBE4D5"},
+	TableStyleTemplateColorful3: {"70AD47", "E2EFD9"},
+	TableStyleTemplateColumns1:  {"4472C4", "D9E2F3"},
+	TableStyleTemplateColumns2:  {"ED7D31", "FBE4D5"},
+	TableStyleTemplateColumns3:  {"70AD47", "E2EFD9"},
+	TableStyleTemplateRows1:     {"4472C4", "D9E2F3"},
+	TableStyleTemplateRows2:     {"ED7D31", "FBE4D5"},
+	TableStyleTemplateRows3:     {"70AD47", "E2EFD9"},
+}
+
+// tableTemplateStyle 创建表格样式模板对应的表格样式，未知模板返回nil
+func tableTemplateStyle(template TableStyleTemplate) *style.Style {
+	border := func(val, color string) *style.TblBorder {
+		return &style.TblBorder{Val: val, Sz: "4", Space: "0", Color: color}
+	}
+	bold := &style.QuickRunConfig{Bold: true}
+	accent := tableTemplateAccent[template]
+	builder := style.NewTableStyleBuilder(string(template), string(template))
+
+	switch template {
+	case TableStyleTemplateNormal:
+		return builder.Build()
+	case TableStyleTemplateGrid:
+		return builder.
+			Borders(style.SingleTableBorders(4, "auto")).
+			Build()
+	case TableStyleTemplateList:
+		return builder.
+			Borders(&style.TblBorders{Top: border("single", "4472C4"), Bottom: border("single", "4472C4"), InsideH: border("single", "4472C4")}).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{RunConfig: bold}).
+			Build()
+	case TableStyleTemplateColorful1, TableStyleTemplateColorful2, TableStyleTemplateColorful3:
+		return builder.
+			Borders(style.SingleTableBorders(4, accent[0])).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{
+				RunConfig: &style.QuickRunConfig{Bold: true, FontColor: "FFFFFF"},
+				Fill:      accent[0],
+			}).
+			Conditional(style.TableConditionLastRow, &style.TableConditionConfig{RunConfig: bold}).
+			Conditional(style.TableConditionFirstCol, &style.TableConditionConfig{RunConfig: bold}).
+			Conditional(style.TableConditionBand1Horz, &style.TableConditionConfig{Fill: accent[1]}).
+			Build()
+	case TableStyleTemplateColumns1, TableStyleTemplateColumns2, TableStyleTemplateColumns3:
+		return builder.
+			Borders(style.SingleTableBorders(4, accent[0])).
+			Conditional(style.TableConditionFirstCol, &style.TableConditionConfig{
+				RunConfig: &style.QuickRunConfig{Bold: true, FontColor: "FFFFFF"},
+				Fill:      accent[0],
+			}).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{RunConfig: bold}).
+			Conditional(style.TableConditionBand1Vert, &style.TableConditionConfig{Fill: accent[1]}).
+			Build()
+	case TableStyleTemplateRows1, TableStyleTemplateRows2, TableStyleTemplateRows3:
+		return builder.
+			Borders(&style.TblBorders{Top: border("single", accent[0]), Bottom: border("single", accent[0]), InsideH: border("single", accent[0])}).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{
+				RunConfig: bold,
+				Borders:   &style.TcBorders{Bottom: &style.TblBorder{Val: "single", Sz: "12", Space: "0", Color: accent[0]}},
+			}).
+			Conditional(style.TableConditionBand1Horz, &style.TableConditionConfig{Fill: accent[1]}).
+			Build()
+	case TableStyleTemplatePlain1:
+		return builder.
+			Borders(style.SingleTableBorders(4, "BFBFBF")).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{RunConfig: bold}).
+			Conditional(style.TableConditionBand1Horz, &style.TableConditionConfig{Fill: "F2F2F2"}).
+			Build()
+	case TableStyleTemplatePlain2:
+		return builder.
+			Borders(&style.TblBorders{Top: border("single", "7F7F7F"), Bottom: border("single", "7F7F7F")}).
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{
+				RunConfig: bold,
+				Borders:   &style.TcBorders{Bottom: border("single", "7F7F7F")},
+			}).
+			Build()
+	case TableStyleTemplatePlain3:
+		return builder.
+			Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{RunConfig: bold}).
+			Conditional(style.TableConditionBand1Horz, &style.TableConditionConfig{Fill: "F2F2F2"}).
+			Build()
+	}
+	return nil
+}
+
+// ensureTableTemplateStyles 为引用了样式模板但样式表中不存在对应样式的表格补充模板样式
+func (d *Document) ensureTableTemplateStyles() {
+	if d.Body == nil {
+		return
+	}
+
+	forEachTable(d.Body.Elements, func(table *Table) {
+		if table.Properties == nil || table.Properties.TableStyle == nil {
+			return
+		}
+		styleID := table.Properties.TableStyle.Val
+		if d.styleManager.GetStyle(styleID) != nil {
+			return
+		}
+		if templateStyle := tableTemplateStyle(TableStyleTemplate(styleID)); templateStyle != nil {
+			d.styleManager.AddStyle(templateStyle)
+			Debugf("添加表格样式模板: %s", styleID)
+		}
+	})
+}
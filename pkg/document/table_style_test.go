@@ -0,0 +1,113 @@
+// Package document 表格样式条件格式测试
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestTableLook 测试表格外观的位掩码和独立属性
+func TestTableLook(t *testing.T) {
+	look := newTableLook(&TableStyleConfig{FirstRowHeader: true, FirstColumnHeader: true})
+	if look.Val != "06A0" || look.FirstRow != "1" || look.NoHBand != "1" {
+		t.Errorf("表格外观不正确: %+v", look)
+	}
+
+	// Word 常见的 04A0：标题行、首列、不使用镶边列
+	look = &TableLook{Val: "04A0"}
+	expected := map[style.TableStyleOverrideType]bool{
+		style.TableConditionFirstRow:  true,
+		style.TableConditionFirstCol:  true,
+		style.TableConditionLastRow:   false,
+		style.TableConditionBand1Horz: true,
+		style.TableConditionBand1Vert: false,
+		style.TableConditionNWCell:    true,
+	}
+	for condition, want := range expected {
+		if got := look.IsEnabled(condition); got != want {
+			t.Errorf("%s 应为 %v", condition, want)
+		}
+	}
+
+	// 独立属性优先于位掩码
+	look.FirstRow = "0"
+	if look.IsEnabled(style.TableConditionFirstRow) {
+		t.Error("独立属性应覆盖位掩码")
+	}
+}
+
+// TestTableStyleConditionalFormatting 测试样式模板的条件格式随行数变化保持正确
+func TestTableStyleConditionalFormatting(t *testing.T) {
+	doc := New()
+	table, err := doc.AddTable(&TableConfig{
+		Rows: 4, Cols: 2, Width: 4000,
+		Data: [][]string{{"名称", "数量"}, {"甲", "1"}, {"乙", "2"}, {"丙", "3"}},
+	})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	if err := table.ApplyTableStyle(&TableStyleConfig{Template: TableStyleTemplateColorful1, FirstRowHeader: true, BandedRows: true}); err != nil {
+		t.Fatalf("应用表格样式失败: %v", err)
+	}
+	if table.Properties.TableLook.Val != "0420" {
+		t.Errorf("表格外观位掩码不正确: %s", table.Properties.TableLook.Val)
+	}
+
+	check := func(doc *Document, table *Table) {
+		t.Helper()
+		header, _ := table.GetCell(0, 0)
+		if props := header.Paragraphs[0].Runs[0].EffectiveProperties(doc); !props.Bold || props.Color != "FFFFFF" {
+			t.Errorf("标题行条件格式未生效: %+v", props)
+		}
+		body, _ := table.GetCell(1, 0)
+		if props := body.Paragraphs[0].Runs[0].EffectiveProperties(doc); props.Bold || props.Color == "FFFFFF" {
+			t.Errorf("正文行不应使用标题行格式: %+v", props)
+		}
+	}
+	check(doc, table)
+
+	tableStyle := doc.tableStyle(table)
+	bands := func() []style.TableStyleOverrideType {
+		var result []style.TableStyleOverrideType
+		for row := 1; row < table.GetRowCount(); row++ {
+			conditions := cellConditions(table, tableStyle, row, 0)
+			result = append(result, conditions[len(conditions)-1])
+		}
+		return result
+	}
+	if got := bands(); got[0] != style.TableConditionBand1Horz || got[1] != style.TableConditionBand2Horz {
+		t.Errorf("镶边行不正确: %v", got)
+	}
+	if err := table.InsertRow(1, []string{"新", "0"}); err != nil {
+		t.Fatalf("插入行失败: %v", err)
+	}
+	if got := bands(); got[1] != style.TableConditionBand2Horz || got[2] != style.TableConditionBand1Horz {
+		t.Errorf("插入行后镶边行应重新计算: %v", got)
+	}
+
+	filename := filepath.Join(t.TempDir(), "table_style.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	stylesXML := string(doc.parts["word/styles.xml"])
+	if !strings.Contains(stylesXML, `w:styleId="TableColorful1"`) || !strings.Contains(stylesXML, `<w:tblStylePr w:type="firstRow">`) {
+		t.Error("styles.xml 中缺少样式模板对应的表格样式")
+	}
+	documentXML := string(doc.parts["word/document.xml"])
+	if strings.Index(documentXML, "<w:tblStyle ") > strings.Index(documentXML, "<w:tblW ") ||
+		strings.Index(documentXML, "<w:tblLook ") < strings.Index(documentXML, "<w:tblBorders>") {
+		t.Error("w:tblPr 子元素顺序不符合规范")
+	}
+
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if s := opened.GetStyleManager().GetStyle("TableColorful1"); s == nil || s.GetConditionalFormat(style.TableConditionBand1Horz) == nil {
+		t.Fatal("重新打开后应解析表格样式的条件格式")
+	}
+	check(opened, opened.Body.GetTables()[0])
+}
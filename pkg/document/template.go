@@ -33,14 +33,12 @@ var (
 	ErrInvalidBlockDefinition = NewDocumentError("invalid_block_definition", fmt.Errorf("invalid block definition"), "")
 )
 
-// 预编译正则表达式用于页眉页脚变量替换
-var headerFooterVarPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
-
 // TemplateEngine 模板引擎
 type TemplateEngine struct {
-	cache    map[string]*Template // 模板缓存
-	mutex    sync.RWMutex         // 读写锁
-	basePath string               // 基础路径
+	cache    map[string]*Template          // 模板缓存
+	mutex    sync.RWMutex                  // 读写锁
+	basePath string                        // 基础路径
+	filters  map[string]TemplateFilterFunc // 自定义过滤器
 }
 
 // Template 模板结构
@@ -193,14 +191,15 @@ func (te *TemplateEngine) RemoveTemplate(name string) {
 func (te *TemplateEngine) parseTemplate(template *Template) error {
 	content := template.Content
 
-	// 解析变量: {{变量名}}
-	varPattern := regexp.MustCompile(`\{\{(\w+)\}\}`)
-	varMatches := varPattern.FindAllStringSubmatch(content, -1)
-	for _, match := range varMatches {
-		if len(match) >= 2 {
-			varName := match[1]
-			template.Variables[varName] = ""
-		}
+	// 解析变量: {{变量名}}、{{customer.name}}，记录表达式引用的根变量名
+	if nodes, err := parseTemplateNodes(content, false); err == nil {
+		walkTemplateNodes(nodes, func(node templateNode) {
+			if output, ok := node.(*templateOutputNode); ok {
+				for _, root := range templateExprRoots(output.expr) {
+					template.Variables[root] = ""
+				}
+			}
+		})
 	}
 
 	// 解析块定义: {{#block "blockName"}}...{{/block}}
@@ -225,7 +224,7 @@ func (te *TemplateEngine) parseTemplate(template *Template) error {
 	}
 
 	// 解析条件语句: {{#if 条件}}...{{/if}} (修复：添加 (?s) 标志以匹配换行符)
-	ifPattern := regexp.MustCompile(`(?s)\{\{#if\s+(\w+)\}\}(.*?)\{\{/if\}\}`)
+	ifPattern := regexp.MustCompile(`(?s)\{\{#if\s+([^{}]+?)\s*\}\}(.*?)\{\{/if\}\}`)
 	ifMatches := ifPattern.FindAllStringSubmatch(content, -1)
 	for _, match := range ifMatches {
 		if len(match) >= 3 {
@@ -244,7 +243,7 @@ func (te *TemplateEngine) parseTemplate(template *Template) error {
 	}
 
 	// 解析循环语句: {{#each 列表}}...{{/each}} (修复：添加 (?s) 标志以匹配换行符)
-	eachPattern := regexp.MustCompile(`(?s)\{\{#each\s+(\w+)\}\}(.*?)\{\{/each\}\}`)
+	eachPattern := regexp.MustCompile(`(?s)\{\{#each\s+([^{}]+?)\s*\}\}(.*?)\{\{/each\}\}`)
 	eachMatches := eachPattern.FindAllStringSubmatch(content, -1)
 	for _, match := range eachMatches {
 		if len(match) >= 3 {
@@ -330,8 +329,13 @@ func (te *TemplateEngine) RenderToDocument(templateName string, data *TemplateDa
 		doc = New()
 	}
 
-	// 渲染模板内容
-	renderedContent, err := te.renderTemplate(template, data)
+	// 渲染模板内容，富内容变量值和包含的文档在生成段落后插入
+	ctx := te.newTemplateContext(data)
+	ctx.partials = []string{template.Name}
+	if ctx.deferred, err = newTemplateDeferred(); err != nil {
+		return nil, WrapErrorWithContext("render_to_document", err, templateName)
+	}
+	renderedContent, err := te.renderTemplate(template, ctx)
 	if err != nil {
 		return nil, WrapErrorWithContext("render_to_document", err, templateName)
 	}
@@ -341,6 +345,15 @@ func (te *TemplateEngine) RenderToDocument(templateName string, data *TemplateDa
 		return nil, WrapErrorWithContext("render_to_document", err, templateName)
 	}
 
+	// 插入暂存的富内容和包含的文档，只替换标记，已渲染的文本不再解析
+	ctx.doc = doc
+	elements, err := te.insertDeferred(doc.Body.Elements, ctx)
+	if err != nil {
+		return nil, WrapErrorWithContext("render_to_document", err, templateName)
+	}
+	doc.Body.Elements = elements
+	doc.bindElements(doc.Body.Elements)
+
 	// 处理图片占位符
 	if err := te.processImagePlaceholders(doc, data); err != nil {
 		return nil, WrapErrorWithContext("render_to_document", err, templateName)
@@ -350,13 +363,13 @@ func (te *TemplateEngine) RenderToDocument(templateName string, data *TemplateDa
 }
 
 // renderTemplate 渲染模板
-func (te *TemplateEngine) renderTemplate(template *Template, data *TemplateData) (string, error) {
+func (te *TemplateEngine) renderTemplate(template *Template, ctx *templateContext) (string, error) {
 	var content string
 
 	// 处理继承：如果有父模板，使用父模板作为基础
 	if template.Parent != nil {
 		// 渲染父模板作为基础内容
-		parentContent, err := te.renderTemplate(template.Parent, data)
+		parentContent, err := te.renderTemplate(template.Parent, ctx)
 		if err != nil {
 			return "", err
 		}
@@ -370,19 +383,13 @@ func (te *TemplateEngine) renderTemplate(template *Template, data *TemplateData)
 	}
 
 	// 渲染块定义
-	content = te.renderBlocks(content, template, data)
-
-	// 渲染变量
-	content = te.renderVariables(content, data.Variables)
-
-	// 渲染循环语句（先处理循环，循环内部会处理条件语句）
-	content = te.renderLoops(content, data.Lists)
+	content = te.renderBlocks(content, template, ctx.data)
 
-	// 渲染条件语句（处理非循环内的条件语句）
-	content = te.renderConditionals(content, data.Conditions)
+	// 渲染变量、条件、循环、作用域块和局部模板
+	content = te.renderTemplateText(content, ctx)
 
 	// 渲染图片占位符
-	content = te.renderImages(content, data.Images)
+	content = te.renderImages(content, ctx.data.Images)
 
 	return content, nil
 }
@@ -431,240 +438,6 @@ func (te *TemplateEngine) renderBlocks(content string, template *Template, data
 	})
 }
 
-// renderVariables 渲染变量
-func (te *TemplateEngine) renderVariables(content string, variables map[string]interface{}) string {
-	varPattern := regexp.MustCompile(`\{\{(\w+)\}\}`)
-
-	return varPattern.ReplaceAllStringFunc(content, func(match string) string {
-		varName := varPattern.FindStringSubmatch(match)[1]
-		if value, exists := variables[varName]; exists {
-			return te.interfaceToString(value)
-		}
-		return match // 保持原样
-	})
-}
-
-// renderConditionals 渲染条件语句（支持if-else语法）
-func (te *TemplateEngine) renderConditionals(content string, conditions map[string]bool) string {
-	ifElsePattern := regexp.MustCompile(`(?s)\{\{#if\s+(\w+)\}\}(.*?)\{\{/if\}\}`)
-
-	return ifElsePattern.ReplaceAllStringFunc(content, func(match string) string {
-		matches := ifElsePattern.FindStringSubmatch(match)
-		if len(matches) >= 3 {
-			condition := matches[1]
-			blockContent := matches[2]
-
-			// 检查是否有else部分
-			elsePattern := regexp.MustCompile(`(?s)(.*?)\{\{else\}\}(.*?)`)
-			elseMatches := elsePattern.FindStringSubmatch(blockContent)
-
-			if len(elseMatches) >= 3 {
-				// 有else部分
-				ifContent := elseMatches[1]
-				elseContent := elseMatches[2]
-
-				if condValue, exists := conditions[condition]; exists && condValue {
-					return ifContent
-				} else {
-					return elseContent
-				}
-			} else {
-				// 没有else部分，按原逻辑处理
-				if condValue, exists := conditions[condition]; exists && condValue {
-					return blockContent
-				}
-			}
-		}
-		return "" // 条件不满足，返回空字符串
-	})
-}
-
-// renderLoops 渲染循环语句
-func (te *TemplateEngine) renderLoops(content string, lists map[string][]interface{}) string {
-	// 使用栈式方法正确处理嵌套循环
-	return te.renderLoopsNested(content, lists, 0)
-}
-
-// renderLoopsNested 使用递归方式处理嵌套循环
-func (te *TemplateEngine) renderLoopsNested(content string, lists map[string][]interface{}, depth int) string {
-	// 查找第一个 {{#each}} 标记
-	eachStartPattern := regexp.MustCompile(`\{\{#each\s+(\w+)\}\}`)
-	startMatch := eachStartPattern.FindStringIndex(content)
-
-	if startMatch == nil {
-		// 没有找到循环，直接返回
-		return content
-	}
-
-	// 找到了循环开始标记，现在需要找到匹配的结束标记
-	startPos := startMatch[0]
-	listVarMatch := eachStartPattern.FindStringSubmatch(content[startPos:])
-	if len(listVarMatch) < 2 {
-		return content
-	}
-
-	listVar := listVarMatch[1]
-	blockStart := startMatch[1] // {{#each xxx}} 之后的位置
-
-	// 使用栈来找到匹配的 {{/each}}
-	depth_counter := 1
-	pos := blockStart
-	blockEnd := -1
-
-	for pos < len(content) {
-		// 查找下一个 {{#each}} 或 {{/each}}
-		nextEach := eachStartPattern.FindStringIndex(content[pos:])
-		endPattern := regexp.MustCompile(`\{\{/each\}\}`)
-		nextEnd := endPattern.FindStringIndex(content[pos:])
-
-		if nextEnd == nil {
-			// 没有找到结束标记，语法错误
-			break
-		}
-
-		// 确定下一个是开始还是结束
-		if nextEach != nil && nextEach[0] < nextEnd[0] {
-			// 下一个是嵌套的开始标记
-			depth_counter++
-			pos = pos + nextEach[1]
-		} else {
-			// 下一个是结束标记
-			depth_counter--
-			if depth_counter == 0 {
-				// 找到了匹配的结束标记
-				blockEnd = pos + nextEnd[0]
-				break
-			}
-			pos = pos + nextEnd[1]
-		}
-	}
-
-	if blockEnd == -1 {
-		// 没有找到匹配的结束标记
-		return content
-	}
-
-	// 提取循环块内容
-	blockContent := content[blockStart:blockEnd]
-
-	// 处理循环
-	var result strings.Builder
-
-	// 添加循环之前的内容
-	result.WriteString(content[:startPos])
-
-	// 渲染循环
-	if listData, exists := lists[listVar]; exists {
-		for i, item := range listData {
-			// 创建循环上下文变量
-			loopContent := strings.ReplaceAll(blockContent, "{{this}}", te.interfaceToString(item))
-			loopContent = strings.ReplaceAll(loopContent, "{{@index}}", strconv.Itoa(i))
-			loopContent = strings.ReplaceAll(loopContent, "{{@first}}", strconv.FormatBool(i == 0))
-			loopContent = strings.ReplaceAll(loopContent, "{{@last}}", strconv.FormatBool(i == len(listData)-1))
-
-			// 如果item是map，处理属性访问
-			if itemMap, ok := item.(map[string]interface{}); ok {
-				// 首先处理嵌套的循环（在替换变量之前）
-				// 为嵌套循环创建新的lists map，包含当前项的列表数据
-				nestedLists := make(map[string][]interface{})
-				for key, value := range itemMap {
-					// 检查值是否是列表类型
-					if listValue, ok := value.([]interface{}); ok {
-						nestedLists[key] = listValue
-					}
-				}
-
-				// 如果有嵌套列表，递归处理嵌套循环
-				if len(nestedLists) > 0 {
-					loopContent = te.renderLoopsNested(loopContent, nestedLists, depth+1)
-				}
-
-				// 然后替换普通变量
-				for key, value := range itemMap {
-					placeholder := fmt.Sprintf("{{%s}}", key)
-					// 只替换非列表类型的值
-					if _, isList := value.([]interface{}); !isList {
-						loopContent = strings.ReplaceAll(loopContent, placeholder, te.interfaceToString(value))
-					}
-				}
-
-				// 处理循环内部的条件语句
-				loopContent = te.renderLoopConditionals(loopContent, itemMap)
-			}
-
-			result.WriteString(loopContent)
-		}
-	}
-
-	// 添加循环之后的内容，并递归处理剩余内容中的其他循环
-	remainingContent := content[blockEnd+len("{{/each}}"):]
-	remainingContent = te.renderLoopsNested(remainingContent, lists, depth)
-	result.WriteString(remainingContent)
-
-	return result.String()
-}
-
-// renderLoopConditionals 渲染循环内部的条件语句（支持if-else语法）
-func (te *TemplateEngine) renderLoopConditionals(content string, itemData map[string]interface{}) string {
-	ifElsePattern := regexp.MustCompile(`(?s)\{\{#if\s+(\w+)\}\}(.*?)\{\{/if\}\}`)
-
-	return ifElsePattern.ReplaceAllStringFunc(content, func(match string) string {
-		matches := ifElsePattern.FindStringSubmatch(match)
-		if len(matches) >= 3 {
-			condition := matches[1]
-			blockContent := matches[2]
-
-			// 检查是否有else部分
-			elsePattern := regexp.MustCompile(`(?s)(.*?)\{\{else\}\}(.*?)`)
-			elseMatches := elsePattern.FindStringSubmatch(blockContent)
-
-			var ifContent, elseContent string
-			hasElse := false
-
-			if len(elseMatches) >= 3 {
-				// 有else部分
-				ifContent = elseMatches[1]
-				elseContent = elseMatches[2]
-				hasElse = true
-			} else {
-				// 没有else部分
-				ifContent = blockContent
-			}
-
-			// 检查条件是否在当前循环项的数据中
-			if condValue, exists := itemData[condition]; exists {
-				// 转换为布尔值
-				conditionMet := false
-				switch v := condValue.(type) {
-				case bool:
-					conditionMet = v
-				case string:
-					conditionMet = v == "true" || v == "1" || v == "yes" || v != ""
-				case int:
-					conditionMet = v != 0
-				case int64:
-					conditionMet = v != 0
-				case float64:
-					conditionMet = v != 0.0
-				default:
-					// 对于其他类型，如果不为nil就认为是true
-					conditionMet = v != nil
-				}
-
-				if conditionMet {
-					return ifContent
-				} else if hasElse {
-					return elseContent
-				}
-			} else if hasElse {
-				// 条件不存在，返回else部分
-				return elseContent
-			}
-		}
-		return "" // 条件不满足且没有else，返回空字符串
-	})
-}
-
 // interfaceToString 将interface{}转换为字符串
 func (te *TemplateEngine) interfaceToString(value interface{}) string {
 	if value == nil {
@@ -711,6 +484,22 @@ func (te *TemplateEngine) ValidateTemplate(template *Template) error {
 		return WrapErrorWithContext("validate_template", err, template.Name)
 	}
 
+	// 检查表达式语法以及 else、unless、with 的嵌套关系
+	nodes, err := parseTemplateNodes(content, true)
+	if err != nil {
+		return WrapErrorWithContext("validate_template", err, template.Name)
+	}
+
+	// 检查过滤器是否已注册
+	if err := te.validateFilters(nodes); err != nil {
+		return WrapErrorWithContext("validate_template", err, template.Name)
+	}
+
+	// 检查局部模板是否已加载
+	if err := te.validatePartials(nodes); err != nil {
+		return WrapErrorWithContext("validate_template", err, template.Name)
+	}
+
 	return nil
 }
 
@@ -740,7 +529,7 @@ func (te *TemplateEngine) validateBlockStatements(content string) error {
 
 // validateIfStatements 验证if语句配对
 func (te *TemplateEngine) validateIfStatements(content string) error {
-	ifCount := len(regexp.MustCompile(`\{\{#if\s+\w+\}\}`).FindAllString(content, -1))
+	ifCount := len(regexp.MustCompile(`\{\{#if\s+[^{}]+\}\}`).FindAllString(content, -1))
 	endifCount := len(regexp.MustCompile(`\{\{/if\}\}`).FindAllString(content, -1))
 
 	if ifCount != endifCount {
@@ -752,7 +541,7 @@ func (te *TemplateEngine) validateIfStatements(content string) error {
 
 // validateEachStatements 验证each语句配对
 func (te *TemplateEngine) validateEachStatements(content string) error {
-	eachCount := len(regexp.MustCompile(`\{\{#each\s+\w+\}\}`).FindAllString(content, -1))
+	eachCount := len(regexp.MustCompile(`\{\{#each\s+[^{}]+\}\}`).FindAllString(content, -1))
 	endeachCount := len(regexp.MustCompile(`\{\{/each\}\}`).FindAllString(content, -1))
 
 	if eachCount != endeachCount {
@@ -797,40 +586,28 @@ func (te *TemplateEngine) extractTemplateContentFromDocument(doc *Document) (str
 }
 
 // extractHeaderFooterContent 从页眉页脚中提取模板内容
+// 未加载的页眉页脚临时解析，不加入缓存
 func (te *TemplateEngine) extractHeaderFooterContent(doc *Document, contentBuilder *strings.Builder) {
-	if doc.parts == nil {
+	if doc.documentRelationships == nil {
 		return
 	}
 
-	// 遍历所有部件，查找页眉页脚文件
-	for partName, partData := range doc.parts {
-		if strings.HasPrefix(partName, "word/header") || strings.HasPrefix(partName, "word/footer") {
-			// 解析页眉/页脚XML并提取文本
-			text := te.extractTextFromHeaderFooterXML(partData)
-			if text != "" {
-				contentBuilder.WriteString(text)
-				contentBuilder.WriteString("\n")
-			}
+	for _, rel := range doc.documentRelationships.Relationships {
+		if rel.Type != headerRelationshipType && rel.Type != footerRelationshipType {
+			continue
 		}
-	}
-}
-
-// extractTextFromHeaderFooterXML 从页眉/页脚XML中提取文本内容
-func (te *TemplateEngine) extractTextFromHeaderFooterXML(xmlData []byte) string {
-	var contentBuilder strings.Builder
-
-	// 使用正则表达式提取<w:t>标签中的文本内容
-	// 这是一个简化的解析方法，适用于提取模板变量
-	textPattern := regexp.MustCompile(`<w:t[^>]*>([^<]*)</w:t>`)
-	matches := textPattern.FindAllSubmatch(xmlData, -1)
-
-	for _, match := range matches {
-		if len(match) >= 2 {
-			contentBuilder.Write(match[1])
+		partName := doc.relationshipPartName(rel.ID)
+		elements, err := doc.headerFooterElements(partName)
+		if err != nil {
+			Warnf("解析页眉页脚失败 %s: %v", partName, err)
+			continue
+		}
+		content := headerFooterContent{Elements: elements}
+		if text := content.GetText(); text != "" {
+			contentBuilder.WriteString(text)
+			contentBuilder.WriteString("\n")
 		}
 	}
-
-	return contentBuilder.String()
 }
 
 // cloneDocument 深度复制文档所有元素和属性
@@ -859,6 +636,9 @@ func (te *TemplateEngine) cloneDocument(source *Document) *Document {
 		}
 	}
 
+	// 复制的段落和表格关联到新文档，渲染时再复制的元素随之关联
+	doc.bindElements(doc.Body.Elements)
+
 	// 深拷贝样式管理器，确保模板渲染时的样式与原模板一致
 	if source.styleManager != nil {
 		doc.styleManager = source.styleManager.Clone()
@@ -870,6 +650,10 @@ func (te *TemplateEngine) cloneDocument(source *Document) *Document {
 	if doc.parts == nil {
 		doc.parts = make(map[string][]byte)
 	}
+	// 先写回源文档中已编辑的页眉页脚，使复制的部件包含最新内容
+	if err := source.serializeHeaderFooters(); err != nil {
+		Warnf("序列化页眉页脚失败: %v", err)
+	}
 	te.cloneAllDocumentParts(source, doc)
 
 	// 复制文档关系（包含页眉页脚引用）
@@ -895,6 +679,28 @@ func (te *TemplateEngine) cloneDocument(source *Document) *Document {
 	// 复制图片ID计数器
 	doc.nextImageID = source.nextImageID
 
+	// 复制编号定义
+	if source.numberingManager != nil {
+		doc.numberingManager = source.numberingManager.clone()
+	}
+
+	// 复制文档设置
+	if source.settings != nil {
+		doc.settings = source.settings.clone()
+	}
+
+	// 复制字体表
+	if source.fonts != nil {
+		doc.fonts = source.fonts.clone()
+	}
+
+	// 复制页面背景
+	if source.background != nil {
+		background := *source.background
+		background.Content = append([]byte(nil), source.background.Content...)
+		doc.background = &background
+	}
+
 	return doc
 }
 
@@ -918,88 +724,7 @@ func (te *TemplateEngine) cloneAllDocumentParts(source, dest *Document) {
 
 // cloneSectionProperties 深度复制节属性
 func (te *TemplateEngine) cloneSectionProperties(source *SectionProperties) *SectionProperties {
-	if source == nil {
-		return nil
-	}
-
-	sectPr := &SectionProperties{
-		XmlnsR: source.XmlnsR,
-	}
-
-	// 复制页面尺寸
-	if source.PageSize != nil {
-		sectPr.PageSize = &PageSizeXML{
-			W:      source.PageSize.W,
-			H:      source.PageSize.H,
-			Orient: source.PageSize.Orient,
-		}
-	}
-
-	// 复制页面边距
-	if source.PageMargins != nil {
-		sectPr.PageMargins = &PageMargin{
-			Top:    source.PageMargins.Top,
-			Right:  source.PageMargins.Right,
-			Bottom: source.PageMargins.Bottom,
-			Left:   source.PageMargins.Left,
-			Header: source.PageMargins.Header,
-			Footer: source.PageMargins.Footer,
-			Gutter: source.PageMargins.Gutter,
-		}
-	}
-
-	// 复制分栏设置
-	if source.Columns != nil {
-		sectPr.Columns = &Columns{
-			Space: source.Columns.Space,
-			Num:   source.Columns.Num,
-		}
-	}
-
-	// 复制页眉引用
-	if source.HeaderReferences != nil {
-		sectPr.HeaderReferences = make([]*HeaderFooterReference, len(source.HeaderReferences))
-		for i, ref := range source.HeaderReferences {
-			sectPr.HeaderReferences[i] = &HeaderFooterReference{
-				Type: ref.Type,
-				ID:   ref.ID,
-			}
-		}
-	}
-
-	// 复制页脚引用
-	if source.FooterReferences != nil {
-		sectPr.FooterReferences = make([]*FooterReference, len(source.FooterReferences))
-		for i, ref := range source.FooterReferences {
-			sectPr.FooterReferences[i] = &FooterReference{
-				Type: ref.Type,
-				ID:   ref.ID,
-			}
-		}
-	}
-
-	// 复制首页不同设置
-	if source.TitlePage != nil {
-		sectPr.TitlePage = &TitlePage{}
-	}
-
-	// 复制页码类型
-	if source.PageNumType != nil {
-		sectPr.PageNumType = &PageNumType{
-			Fmt: source.PageNumType.Fmt,
-		}
-	}
-
-	// 复制文档网格
-	if source.DocGrid != nil {
-		sectPr.DocGrid = &DocGrid{
-			Type:      source.DocGrid.Type,
-			LinePitch: source.DocGrid.LinePitch,
-			CharSpace: source.DocGrid.CharSpace,
-		}
-	}
-
-	return sectPr
+	return cloneSectionProperties(source)
 }
 
 // cloneHeaderFooterParts 复制页眉页脚部件 (保留以兼容旧代码，现在由cloneAllDocumentParts处理)
@@ -1027,11 +752,13 @@ func (te *TemplateEngine) cloneParagraph(source *Paragraph) *Paragraph {
 	newPara := &Paragraph{
 		Properties: te.cloneParagraphProperties(source.Properties),
 		Runs:       make([]Run, len(source.Runs)),
+		doc:        source.doc,
 	}
 
 	for i, run := range source.Runs {
 		newPara.Runs[i] = te.cloneRun(&run)
 	}
+	newPara.Permissions = clonePermissionMarks(source.Permissions)
 
 	return newPara
 }
@@ -1088,6 +815,11 @@ func (te *TemplateEngine) cloneParagraphProperties(source *ParagraphProperties)
 		}
 	}
 
+	// 复制段落级节属性
+	if source.SectionProperties != nil {
+		props.SectionProperties = cloneSectionProperties(source.SectionProperties)
+	}
+
 	// 复制制表符
 	if source.Tabs != nil {
 		props.Tabs = &Tabs{
@@ -1110,6 +842,7 @@ func (te *TemplateEngine) cloneRun(source *Run) Run {
 	newRun := Run{
 		Properties: te.cloneRunProperties(source.Properties),
 		Text:       Text{Content: source.Text.Content, Space: source.Text.Space},
+		doc:        source.doc,
 	}
 
 	// 复制图像（如果有）
@@ -1128,6 +861,11 @@ func (te *TemplateEngine) cloneRun(source *Run) Run {
 		newRun.InstrText = source.InstrText
 	}
 
+	// 复制VML图形（如果有）
+	if source.Pict != nil {
+		newRun.Pict = &Pict{Content: append([]byte(nil), source.Pict.Content...)}
+	}
+
 	return newRun
 }
 
@@ -1139,9 +877,14 @@ func (te *TemplateEngine) cloneRunProperties(source *RunProperties) *RunProperti
 
 	props := &RunProperties{}
 
+	// 复制字符样式引用
+	if source.RunStyle != nil {
+		props.RunStyle = &RunStyle{Val: source.RunStyle.Val}
+	}
+
 	// 复制粗体
 	if source.Bold != nil {
-		props.Bold = &Bold{}
+		props.Bold = &Bold{Val: source.Bold.Val}
 	}
 
 	// 复制复杂脚本粗体
@@ -1151,7 +894,7 @@ func (te *TemplateEngine) cloneRunProperties(source *RunProperties) *RunProperti
 
 	// 复制斜体
 	if source.Italic != nil {
-		props.Italic = &Italic{}
+		props.Italic = &Italic{Val: source.Italic.Val}
 	}
 
 	// 复制复杂脚本斜体
@@ -1187,9 +930,8 @@ func (te *TemplateEngine) cloneRunProperties(source *RunProperties) *RunProperti
 
 	// 复制颜色
 	if source.Color != nil {
-		props.Color = &Color{
-			Val: source.Color.Val,
-		}
+		color := *source.Color
+		props.Color = &color
 	}
 
 	// 复制背景色
@@ -1201,13 +943,8 @@ func (te *TemplateEngine) cloneRunProperties(source *RunProperties) *RunProperti
 
 	// 完整复制字体族属性，包括所有字体设置
 	if source.FontFamily != nil {
-		props.FontFamily = &FontFamily{
-			ASCII:    source.FontFamily.ASCII,
-			HAnsi:    source.FontFamily.HAnsi,
-			EastAsia: source.FontFamily.EastAsia,
-			CS:       source.FontFamily.CS,
-			Hint:     source.FontFamily.Hint,
-		}
+		fontFamily := *source.FontFamily
+		props.FontFamily = &fontFamily
 	}
 
 	return props
@@ -1219,6 +956,7 @@ func (te *TemplateEngine) cloneTable(source *Table) *Table {
 		Properties: te.cloneTableProperties(source.Properties),
 		Grid:       te.cloneTableGrid(source.Grid),
 		Rows:       make([]TableRow, len(source.Rows)),
+		doc:        source.doc,
 	}
 
 	for i, row := range source.Rows {
@@ -1799,32 +1537,24 @@ func (te *TemplateEngine) RenderTemplateToDocument(templateName string, data *Te
 
 // replaceVariablesInDocument 在文档结构中直接替换变量
 func (te *TemplateEngine) replaceVariablesInDocument(doc *Document, data *TemplateData) error {
-	// 首先处理文档级别的循环（跨段落）
-	err := te.processDocumentLevelLoops(doc, data)
+	deferred, err := newTemplateDeferred()
 	if err != nil {
 		return err
 	}
+	ctx := te.newTemplateContext(data)
+	ctx.doc = doc
+	ctx.deferred = deferred
 
-	for _, element := range doc.Body.Elements {
-		switch elem := element.(type) {
-		case *Paragraph:
-			// 处理段落中的变量替换
-			err := te.replaceVariablesInParagraph(elem, data)
-			if err != nil {
-				return err
-			}
-
-		case *Table:
-			// 处理表格中的变量替换和模板语法
-			err := te.replaceVariablesInTable(elem, data)
-			if err != nil {
-				return err
-			}
-		}
+	// 展开局部模板和文档包含，处理文档级别的循环（跨段落）以及段落和表格中的变量替换
+	elements, err := te.renderDocumentBody(doc.Body.Elements, ctx)
+	if err != nil {
+		return err
 	}
+	doc.Body.Elements = elements
+	doc.bindElements(doc.Body.Elements)
 
 	// 处理页眉页脚中的变量替换
-	err = te.replaceVariablesInHeadersFooters(doc, data)
+	err = te.replaceVariablesInHeadersFooters(doc, ctx)
 	if err != nil {
 		return err
 	}
@@ -1839,69 +1569,46 @@ func (te *TemplateEngine) replaceVariablesInDocument(doc *Document, data *Templa
 }
 
 // replaceVariablesInHeadersFooters 在页眉页脚中替换变量
-func (te *TemplateEngine) replaceVariablesInHeadersFooters(doc *Document, data *TemplateData) error {
-	if doc.parts == nil {
+// 页眉页脚解析为与正文相同的对象后渲染并写回部件，不含模板语法的页眉页脚保持原样
+func (te *TemplateEngine) replaceVariablesInHeadersFooters(doc *Document, ctx *templateContext) error {
+	if doc.documentRelationships == nil {
 		return nil
 	}
 
-	for partName, partData := range doc.parts {
-		// 处理页眉文件
-		if strings.HasPrefix(partName, "word/header") && strings.HasSuffix(partName, ".xml") {
-			newData, err := te.replaceVariablesInXMLPart(partData, data)
-			if err != nil {
-				return fmt.Errorf("处理页眉变量替换失败 %s: %v", partName, err)
+	for _, rel := range doc.documentRelationships.Relationships {
+		partName := doc.relationshipPartName(rel.ID)
+		var part interface{}
+		var content *headerFooterContent
+		switch rel.Type {
+		case headerRelationshipType:
+			if header := doc.headerForPart(partName); header != nil {
+				part, content = header, &header.headerFooterContent
 			}
-			doc.parts[partName] = newData
-		}
-		// 处理页脚文件
-		if strings.HasPrefix(partName, "word/footer") && strings.HasSuffix(partName, ".xml") {
-			newData, err := te.replaceVariablesInXMLPart(partData, data)
-			if err != nil {
-				return fmt.Errorf("处理页脚变量替换失败 %s: %v", partName, err)
+		case footerRelationshipType:
+			if footer := doc.footerForPart(partName); footer != nil {
+				part, content = footer, &footer.headerFooterContent
 			}
-			doc.parts[partName] = newData
 		}
-	}
-
-	return nil
-}
-
-// replaceVariablesInXMLPart 在XML部件中替换变量
-func (te *TemplateEngine) replaceVariablesInXMLPart(xmlData []byte, data *TemplateData) ([]byte, error) {
-	content := string(xmlData)
+		if content == nil || !strings.Contains(content.GetText(), "{{") {
+			continue
+		}
 
-	// 替换变量: {{变量名}} - 使用预编译的正则表达式
-	content = headerFooterVarPattern.ReplaceAllStringFunc(content, func(match string) string {
-		matches := headerFooterVarPattern.FindStringSubmatch(match)
-		if len(matches) < 2 {
-			return match
+		elements, err := te.renderBodyElements(content.Elements, ctx)
+		if err != nil {
+			return fmt.Errorf("处理页眉页脚变量替换失败 %s: %v", partName, err)
 		}
-		varName := matches[1]
-		if value, exists := data.Variables[varName]; exists {
-			// 对XML内容进行转义
-			return te.escapeXMLContent(te.interfaceToString(value))
+		content.Elements = elements
+		doc.bindElements(content.Elements)
+		if err := doc.serializeHeaderFooterPart(partName, part, content.relationships); err != nil {
+			return fmt.Errorf("序列化页眉页脚失败 %s: %v", partName, err)
 		}
-		return match // 保持原样
-	})
-
-	// 替换条件语句
-	content = te.renderConditionals(content, data.Conditions)
-
-	return []byte(content), nil
-}
+	}
 
-// escapeXMLContent 转义XML特殊字符
-func (te *TemplateEngine) escapeXMLContent(s string) string {
-	s = strings.ReplaceAll(s, "&", "&amp;")
-	s = strings.ReplaceAll(s, "<", "&lt;")
-	s = strings.ReplaceAll(s, ">", "&gt;")
-	s = strings.ReplaceAll(s, "\"", "&quot;")
-	s = strings.ReplaceAll(s, "'", "&apos;")
-	return s
+	return nil
 }
 
 // processDocumentLevelLoops 处理文档级别的循环（跨段落）
-func (te *TemplateEngine) processDocumentLevelLoops(doc *Document, data *TemplateData) error {
+func (te *TemplateEngine) processDocumentLevelLoops(doc *Document, ctx *templateContext) error {
 	elements := doc.Body.Elements
 	newElements := make([]interface{}, 0)
 
@@ -1918,12 +1625,7 @@ func (te *TemplateEngine) processDocumentLevelLoops(doc *Document, data *Templat
 			}
 
 			// 检查是否包含循环开始标记
-			eachPattern := regexp.MustCompile(`\{\{#each\s+(\w+)\}\}`)
-			matches := eachPattern.FindStringSubmatch(fullText)
-
-			if len(matches) > 1 {
-				listVarName := matches[1]
-
+			if listExpr, ok := templateEachExpr(fullText); ok {
 				// 找到循环结束位置
 				loopEndIndex := -1
 				templateElements := make([]interface{}, 0)
@@ -1946,50 +1648,44 @@ func (te *TemplateEngine) processDocumentLevelLoops(doc *Document, data *Templat
 				}
 
 				if loopEndIndex >= 0 {
-					// 处理循环
-					if listData, exists := data.Lists[listVarName]; exists {
-						// 为每个数据项生成元素
-						for _, item := range listData {
-							if itemMap, ok := item.(map[string]interface{}); ok {
-								// 复制模板元素并替换变量
-								for _, templateElement := range templateElements {
-									if templatePara, ok := templateElement.(*Paragraph); ok {
-										newPara := te.cloneParagraph(templatePara)
-
-										// 处理段落文本
-										fullText := ""
-										for _, run := range newPara.Runs {
-											fullText += run.Text.Content
-										}
-
-										// 移除循环标记
-										content := fullText
-										content = regexp.MustCompile(`\{\{#each\s+\w+\}\}`).ReplaceAllString(content, "")
-										content = regexp.MustCompile(`\{\{/each\}\}`).ReplaceAllString(content, "")
-
-										// 替换变量
-										for key, value := range itemMap {
-											placeholder := fmt.Sprintf("{{%s}}", key)
-											content = strings.ReplaceAll(content, placeholder, te.interfaceToString(value))
-										}
-
-										// 如果内容不为空，创建新段落
-										if strings.TrimSpace(content) != "" {
-											// 保留原始段落的样式，不强制设置粗体 (Fix for Issue #88)
-											if len(newPara.Runs) > 0 {
-												// 保留原始Run的属性
-												newPara.Runs[0].Text.Content = content
-												newPara.Runs = newPara.Runs[:1]
-											} else {
-												// 如果没有原始Run，创建一个不带样式的新Run
-												newPara.Runs = []Run{{
-													Text: Text{Content: content},
-												}}
-											}
-											newElements = append(newElements, newPara)
-										}
-									}
+					// 为每个数据项生成元素，在当前项的作用域中渲染模板段落
+					for _, item := range te.evalTemplateItems(listExpr, ctx) {
+						itemCtx := ctx.withScope(item)
+						for _, templateElement := range templateElements {
+							templatePara, ok := templateElement.(*Paragraph)
+							if !ok {
+								continue
+							}
+							newPara := te.cloneParagraph(templatePara)
+
+							// 处理段落文本
+							fullText := ""
+							for _, run := range newPara.Runs {
+								fullText += run.Text.Content
+							}
+
+							// 移除循环标记
+							content := fullText
+							content = templateEachStartPattern.ReplaceAllString(content, "")
+							content = templateEachEndPattern.ReplaceAllString(content, "")
+
+							// 渲染变量和条件
+							content = te.renderTemplateText(content, itemCtx)
+
+							// 如果内容不为空，创建新段落
+							if strings.TrimSpace(content) != "" {
+								// 保留原始段落的样式，不强制设置粗体 (Fix for Issue #88)
+								if len(newPara.Runs) > 0 {
+									// 保留原始Run的属性
+									newPara.Runs[0].Text.Content = content
+									newPara.Runs = newPara.Runs[:1]
+								} else {
+									// 如果没有原始Run，创建一个不带样式的新Run
+									newPara.Runs = []Run{{
+										Text: Text{Content: content},
+									}}
 								}
+								newElements = append(newElements, newPara)
 							}
 						}
 					}
@@ -2011,8 +1707,19 @@ func (te *TemplateEngine) processDocumentLevelLoops(doc *Document, data *Templat
 	return nil
 }
 
+// evalTemplateItems 计算循环表达式并返回各个循环项，表达式无效时返回nil
+func (te *TemplateEngine) evalTemplateItems(source string, ctx *templateContext) []templateScope {
+	expr, err := parseTemplateExpr(source)
+	if err != nil {
+		Debugf("循环表达式无效: %v", err)
+		return nil
+	}
+	value, _ := expr.eval(ctx)
+	return templateItems(value)
+}
+
 // replaceVariablesInParagraph 在段落中替换变量（改进版本，更好地保持样式）
-func (te *TemplateEngine) replaceVariablesInParagraph(para *Paragraph, data *TemplateData) error {
+func (te *TemplateEngine) replaceVariablesInParagraph(para *Paragraph, ctx *templateContext) error {
 	// 首先识别所有变量占位符的位置
 	fullText := ""
 	runInfos := make([]struct {
@@ -2044,218 +1751,62 @@ func (te *TemplateEngine) replaceVariablesInParagraph(para *Paragraph, data *Tem
 		return nil
 	}
 
-	// 先处理循环语句（包括非表格循环）
-	processedText, hasLoopChanges := te.processNonTableLoops(fullText, data)
-	if hasLoopChanges {
-		// 重新构建段落
-		para.Runs = []Run{{
-			Text: Text{Content: processedText},
-			Properties: &RunProperties{
-				FontFamily: &FontFamily{
-					ASCII:    "仿宋",
-					HAnsi:    "仿宋",
-					EastAsia: "仿宋",
-				},
-				Bold: &Bold{},
-			},
-		}}
-		fullText = processedText
-	}
-
-	// 使用新的逐个变量替换方法
-	newRuns, hasVarChanges := te.replaceVariablesSequentially(runInfos, fullText, data)
+	// 逐个渲染模板片段，保持各片段原有的Run样式
+	newRuns, hasChanges := te.renderParagraphRuns(runInfos, fullText, ctx)
 
 	// 如果有变化，更新段落的Run
-	if hasVarChanges || hasLoopChanges {
+	if hasChanges {
 		para.Runs = newRuns
 	}
 
 	return nil
 }
 
-// processNonTableLoops 处理非表格循环
-func (te *TemplateEngine) processNonTableLoops(content string, data *TemplateData) (string, bool) {
-	eachPattern := regexp.MustCompile(`(?s)\{\{#each\s+(\w+)\}\}(.*?)\{\{/each\}\}`)
-	matches := eachPattern.FindAllStringSubmatchIndex(content, -1)
-
-	if len(matches) == 0 {
-		return content, false
-	}
-
-	var result strings.Builder
-	lastEnd := 0
-	hasChanges := false
-
-	for _, match := range matches {
-		// 找到变量名和块内容
-		fullMatch := content[match[0]:match[1]]
-		submatch := eachPattern.FindStringSubmatch(fullMatch)
-		if len(submatch) >= 3 {
-			listVar := submatch[1]
-			blockContent := submatch[2]
-
-			// 添加循环前的内容
-			result.WriteString(content[lastEnd:match[0]])
-
-			// 处理循环
-			if listData, exists := data.Lists[listVar]; exists {
-				for _, item := range listData {
-					if itemMap, ok := item.(map[string]interface{}); ok {
-						loopContent := blockContent
-						for key, value := range itemMap {
-							placeholder := fmt.Sprintf("{{%s}}", key)
-							loopContent = strings.ReplaceAll(loopContent, placeholder, te.interfaceToString(value))
-						}
-						result.WriteString(loopContent)
-					}
-				}
-			}
-
-			lastEnd = match[1]
-			hasChanges = true
-		}
-	}
-
-	// 添加剩余内容
-	if lastEnd < len(content) {
-		result.WriteString(content[lastEnd:])
-	}
-
-	return result.String(), hasChanges
-}
-
-// replaceVariablesSequentially 逐个替换变量，保持样式
-func (te *TemplateEngine) replaceVariablesSequentially(originalRunInfos []struct {
+// renderParagraphRuns 按顶层模板片段渲染段落文本
+// 普通文本保留原有Run的切分，变量、条件和循环的渲染结果使用其起始位置所在Run的样式
+func (te *TemplateEngine) renderParagraphRuns(originalRunInfos []struct {
 	startIndex int
 	endIndex   int
 	run        *Run
-}, originalText string, data *TemplateData) ([]Run, bool) {
-
-	// 找到所有变量位置
-	varPattern := regexp.MustCompile(`\{\{(\w+)\}\}`)
-	varMatches := varPattern.FindAllStringSubmatchIndex(originalText, -1)
-
-	if len(varMatches) == 0 {
-		// 没有变量，检查条件语句
-		return te.processConditionals(originalRunInfos, originalText, data)
-	}
+}, originalText string, ctx *templateContext) ([]Run, bool) {
+	nodes, _ := parseTemplateNodes(originalText, false)
 
 	newRuns := make([]Run, 0)
-	currentPos := 0
 	hasChanges := false
 
-	for _, varMatch := range varMatches {
-		varStart := varMatch[0]
-		varEnd := varMatch[1]
-		varNameStart := varMatch[2]
-		varNameEnd := varMatch[3]
-
-		// 添加变量前的文本（保持原样式）
-		if varStart > currentPos {
-			beforeText := originalText[currentPos:varStart]
-			beforeRuns := te.extractRunsForSegment(originalRunInfos, currentPos, varStart, beforeText)
-			newRuns = append(newRuns, beforeRuns...)
-		}
-
-		// 处理变量替换
-		varName := originalText[varNameStart:varNameEnd]
-		if value, exists := data.Variables[varName]; exists {
-			replacementText := te.interfaceToString(value)
-
-			// 为变量选择合适的样式（使用覆盖变量位置的Run样式）
-			varRun := te.findRunForPosition(originalRunInfos, varStart)
-			if varRun != nil {
-				newRun := te.cloneRun(varRun)
-				newRun.Text.Content = replacementText
-				newRuns = append(newRuns, newRun)
-				hasChanges = true
-			}
-		} else {
-			// 变量不存在，保持原始占位符
-			varText := originalText[varStart:varEnd]
-			varRun := te.findRunForPosition(originalRunInfos, varStart)
-			if varRun != nil {
-				newRun := te.cloneRun(varRun)
-				newRun.Text.Content = varText
-				newRuns = append(newRuns, newRun)
-			}
+	for _, node := range nodes {
+		start, end := node.span()
+		if _, ok := node.(*templateTextNode); ok {
+			newRuns = append(newRuns, te.extractRunsForSegment(originalRunInfos, start, end, originalText[start:end])...)
+			continue
 		}
 
-		currentPos = varEnd
-	}
-
-	// 添加最后剩余的文本
-	if currentPos < len(originalText) {
-		afterText := originalText[currentPos:]
-		afterRuns := te.extractRunsForSegment(originalRunInfos, currentPos, len(originalText), afterText)
-		newRuns = append(newRuns, afterRuns...)
-	}
-
-	// 如果没有找到任何变量但文本发生了变化，处理条件语句
-	if !hasChanges {
-		return te.processConditionals(originalRunInfos, originalText, data)
-	}
-
-	// 对结果处理条件语句（但要保持每个Run的独立性）
-	if hasChanges {
-		finalRuns := te.processConditionalsPreservingRuns(newRuns, data)
-		return finalRuns, true
-	}
-
-	return newRuns, hasChanges
-}
-
-// processConditionalsPreservingRuns 处理条件语句但保持Run的独立性
-func (te *TemplateEngine) processConditionalsPreservingRuns(runs []Run, data *TemplateData) []Run {
-	finalRuns := make([]Run, 0)
-
-	for _, run := range runs {
-		originalContent := run.Text.Content
-		processedContent := te.renderConditionals(originalContent, data.Conditions)
-
-		// 如果内容发生变化，更新这个Run
-		if processedContent != originalContent {
-			newRun := run // 复制Run结构
-			newRun.Text.Content = processedContent
-			finalRuns = append(finalRuns, newRun)
-		} else {
-			// 内容没有变化，保持原样
-			finalRuns = append(finalRuns, run)
+		// 富内容变量值插入带格式的Run
+		if output, ok := node.(*templateOutputNode); ok && ctx.doc != nil {
+			if value, ok := output.expr.eval(ctx); ok && isTemplateRichValue(value) {
+				if runs, ok := ctx.richRuns(value, te.findRunForPosition(originalRunInfos, start)); ok {
+					newRuns = append(newRuns, runs...)
+					hasChanges = true
+					continue
+				}
+			}
 		}
-	}
 
-	return finalRuns
-}
-
-// processConditionals 处理条件语句
-func (te *TemplateEngine) processConditionals(originalRunInfos []struct {
-	startIndex int
-	endIndex   int
-	run        *Run
-}, originalText string, data *TemplateData) ([]Run, bool) {
-
-	processedText := te.renderConditionals(originalText, data.Conditions)
-
-	if processedText == originalText {
-		// 没有变化，返回原始Runs
-		newRuns := make([]Run, len(originalRunInfos))
-		for i, runInfo := range originalRunInfos {
-			newRuns[i] = te.cloneRun(runInfo.run)
+		rendered := ctx.renderNode(node)
+		if rendered != originalText[start:end] {
+			hasChanges = true
+		}
+		if rendered == "" {
+			continue
+		}
+		if nodeRun := te.findRunForPosition(originalRunInfos, start); nodeRun != nil {
+			newRun := te.cloneRun(nodeRun)
+			newRun.Text.Content = rendered
+			newRuns = append(newRuns, newRun)
 		}
-		return newRuns, false
-	}
-
-	// 有条件语句被处理，简化处理
-	if len(originalRunInfos) == 1 {
-		newRun := te.cloneRun(originalRunInfos[0].run)
-		newRun.Text.Content = processedText
-		return []Run{newRun}, true
 	}
 
-	// 多个Run的情况，使用第一个Run的样式
-	newRun := te.cloneRun(originalRunInfos[0].run)
-	newRun.Text.Content = processedText
-	return []Run{newRun}, true
+	return newRuns, hasChanges
 }
 
 // extractRunsForSegment 为文本片段提取相应的Run（改进版本）
@@ -2327,24 +1878,46 @@ func min(a, b int) int {
 }
 
 // replaceVariablesInTable 在表格中替换变量和处理表格模板
-func (te *TemplateEngine) replaceVariablesInTable(table *Table, data *TemplateData) error {
+func (te *TemplateEngine) replaceVariablesInTable(table *Table, ctx *templateContext) error {
 	// 检查是否有表格循环模板
 	if len(table.Rows) > 0 && te.isTableTemplate(table) {
-		return te.renderTableTemplate(table, data)
+		return te.renderTableTemplate(table, ctx)
 	}
 
 	// 普通表格变量替换
 	for i := range table.Rows {
 		for j := range table.Rows[i].Cells {
-			for k := range table.Rows[i].Cells[j].Paragraphs {
-				err := te.replaceVariablesInParagraph(&table.Rows[i].Cells[j].Paragraphs[k], data)
+			cell := &table.Rows[i].Cells[j]
+			nested := len(cell.Tables)
+			paragraphs := make([]Paragraph, 0, len(cell.Paragraphs))
+			for k := range cell.Paragraphs {
+				// 只包含富内容占位符的段落替换为相应内容，单元格中的表格位于段落之后
+				if block, ok := ctx.richBlock(&cell.Paragraphs[k]); ok {
+					for _, element := range block {
+						switch elem := element.(type) {
+						case *Paragraph:
+							paragraphs = append(paragraphs, *elem)
+						case *Table:
+							cell.Tables = append(cell.Tables, *elem)
+						}
+					}
+					continue
+				}
+				err := te.replaceVariablesInParagraph(&cell.Paragraphs[k], ctx)
 				if err != nil {
 					return err
 				}
+				paragraphs = append(paragraphs, cell.Paragraphs[k])
 			}
-			// 递归处理嵌套表格
-			for k := range table.Rows[i].Cells[j].Tables {
-				err := te.replaceVariablesInTable(&table.Rows[i].Cells[j].Tables[k], data)
+			if len(paragraphs) == 0 {
+				// 单元格至少需要一个段落
+				paragraphs = append(paragraphs, Paragraph{})
+			}
+			cell.Paragraphs = paragraphs
+
+			// 递归处理嵌套表格（不包括刚插入的富内容表格）
+			for k := 0; k < nested; k++ {
+				err := te.replaceVariablesInTable(&cell.Tables[k], ctx)
 				if err != nil {
 					return err
 				}
@@ -2378,8 +1951,7 @@ func (te *TemplateEngine) isTableTemplate(table *Table) bool {
 
 // containsTemplateLoop 检查文本是否包含循环模板语法（支持跨Run检测）
 func (te *TemplateEngine) containsTemplateLoop(text string) bool {
-	eachPattern := regexp.MustCompile(`\{\{#each\s+\w+\}\}`)
-	return eachPattern.MatchString(text)
+	return templateEachStartPattern.MatchString(text)
 }
 
 // containsTemplateLoopInRuns 检查Run列表中是否包含循环模板语法（跨Run检测）
@@ -2394,14 +1966,14 @@ func (te *TemplateEngine) containsTemplateLoopInRuns(runs []Run) bool {
 }
 
 // renderTableTemplate 渲染表格模板
-func (te *TemplateEngine) renderTableTemplate(table *Table, data *TemplateData) error {
+func (te *TemplateEngine) renderTableTemplate(table *Table, ctx *templateContext) error {
 	if len(table.Rows) == 0 {
 		return nil
 	}
 
 	// 找到模板行（包含循环语法的行）
 	templateRowIndex := -1
-	var listVarName string
+	var listExpr string
 
 	for i, row := range table.Rows {
 		found := false
@@ -2415,11 +1987,9 @@ func (te *TemplateEngine) renderTableTemplate(table *Table, data *TemplateData)
 				}
 
 				// 检查合并后的文本中是否包含循环语法
-				eachPattern := regexp.MustCompile(`\{\{#each\s+(\w+)\}\}`)
-				matches := eachPattern.FindStringSubmatch(fullText)
-				if len(matches) > 1 {
+				if expr, ok := templateEachExpr(fullText); ok {
 					templateRowIndex = i
-					listVarName = matches[1]
+					listExpr = expr
 					found = true
 					break
 				}
@@ -2433,13 +2003,13 @@ func (te *TemplateEngine) renderTableTemplate(table *Table, data *TemplateData)
 		}
 	}
 
-	if templateRowIndex < 0 || listVarName == "" {
+	if templateRowIndex < 0 || listExpr == "" {
 		return nil
 	}
 
 	// 获取列表数据
-	listData, exists := data.Lists[listVarName]
-	if !exists || len(listData) == 0 {
+	items := te.evalTemplateItems(listExpr, ctx)
+	if len(items) == 0 {
 		// 删除模板行
 		table.Rows = append(table.Rows[:templateRowIndex], table.Rows[templateRowIndex+1:]...)
 		return nil
@@ -2456,105 +2026,92 @@ func (te *TemplateEngine) renderTableTemplate(table *Table, data *TemplateData)
 	}
 
 	// 为每个数据项生成新行
-	for _, item := range listData {
+	for _, item := range items {
 		newRow := te.cloneTableRow(&templateRow)
+		itemCtx := ctx.withScope(item)
 
 		// 在新行中替换变量
-		if itemMap, ok := item.(map[string]interface{}); ok {
-			for i := range newRow.Cells {
-				for j := range newRow.Cells[i].Paragraphs {
-					// 合并所有Run的文本
-					fullText := ""
-					originalRuns := newRow.Cells[i].Paragraphs[j].Runs
-					for _, run := range originalRuns {
-						fullText += run.Text.Content
-					}
-
-					// 移除模板语法标记
-					content := fullText
-					content = regexp.MustCompile(`\{\{#each\s+\w+\}\}`).ReplaceAllString(content, "")
-					content = regexp.MustCompile(`\{\{/each\}\}`).ReplaceAllString(content, "")
+		for i := range newRow.Cells {
+			for j := range newRow.Cells[i].Paragraphs {
+				// 合并所有Run的文本
+				fullText := ""
+				originalRuns := newRow.Cells[i].Paragraphs[j].Runs
+				for _, run := range originalRuns {
+					fullText += run.Text.Content
+				}
 
-					// 替换变量
-					for key, value := range itemMap {
-						placeholder := fmt.Sprintf("{{%s}}", key)
-						content = strings.ReplaceAll(content, placeholder, te.interfaceToString(value))
+				// 移除模板语法标记
+				content := fullText
+				content = templateEachStartPattern.ReplaceAllString(content, "")
+				content = templateEachEndPattern.ReplaceAllString(content, "")
+
+				// 在当前项的作用域中渲染变量和条件
+				content = te.renderTemplateText(content, itemCtx)
+
+				// 重建Run结构，更好地保持样式继承
+				if len(originalRuns) > 0 {
+					// 寻找第一个有实际内容或样式的Run作为样式模板
+					var templateRun *Run
+					for k := range originalRuns {
+						if originalRuns[k].Properties != nil || originalRuns[k].Text.Content != "" {
+							templateRun = &originalRuns[k]
+							break
+						}
 					}
 
-					// 处理条件语句
-					content = te.renderLoopConditionals(content, itemMap)
-
-					// 重建Run结构，更好地保持样式继承
-					if len(originalRuns) > 0 {
-						// 寻找第一个有实际内容或样式的Run作为样式模板
-						var templateRun *Run
-						for k := range originalRuns {
-							if originalRuns[k].Properties != nil || originalRuns[k].Text.Content != "" {
-								templateRun = &originalRuns[k]
-								break
-							}
+					if templateRun != nil {
+						newRun := te.cloneRun(templateRun)
+						newRun.Text.Content = content
+						newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
+					} else {
+						// 使用第一个Run但确保基本样式
+						newRun := te.cloneRun(&originalRuns[0])
+						newRun.Text.Content = content
+						// 确保基本的字体设置
+						if newRun.Properties == nil {
+							newRun.Properties = &RunProperties{}
 						}
-
-						if templateRun != nil {
-							newRun := te.cloneRun(templateRun)
-							newRun.Text.Content = content
-							newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
-						} else {
-							// 使用第一个Run但确保基本样式
-							newRun := te.cloneRun(&originalRuns[0])
-							newRun.Text.Content = content
-							// 确保基本的字体设置
-							if newRun.Properties == nil {
-								newRun.Properties = &RunProperties{}
-							}
-							if newRun.Properties.FontFamily == nil {
-								newRun.Properties.FontFamily = &FontFamily{
-									ASCII:    "仿宋",
-									HAnsi:    "仿宋",
-									EastAsia: "仿宋",
-								}
+						if newRun.Properties.FontFamily == nil {
+							newRun.Properties.FontFamily = &FontFamily{
+								ASCII:    "仿宋",
+								HAnsi:    "仿宋",
+								EastAsia: "仿宋",
 							}
-							newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
 						}
-					} else {
-						// 如果没有原始Run，创建新的但尝试继承段落样式
-						newRun := Run{
-							Text: Text{Content: content},
-							Properties: &RunProperties{
-								FontFamily: &FontFamily{
-									ASCII:    "仿宋",
-									HAnsi:    "仿宋",
-									EastAsia: "仿宋",
-								},
-								Bold: &Bold{},
+						newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
+					}
+				} else {
+					// 如果没有原始Run，创建新的但尝试继承段落样式
+					newRun := Run{
+						Text: Text{Content: content},
+						Properties: &RunProperties{
+							FontFamily: &FontFamily{
+								ASCII:    "仿宋",
+								HAnsi:    "仿宋",
+								EastAsia: "仿宋",
 							},
-						}
+							Bold: &Bold{},
+						},
+					}
 
-						// 如果段落有默认的Run属性，尝试继承
-						if len(templateRow.Cells) > i && len(templateRow.Cells[i].Paragraphs) > j {
-							templatePara := &templateRow.Cells[i].Paragraphs[j]
-							if len(templatePara.Runs) > 0 && templatePara.Runs[0].Properties != nil {
-								newRun.Properties = te.cloneRunProperties(templatePara.Runs[0].Properties)
-							}
+					// 如果段落有默认的Run属性，尝试继承
+					if len(templateRow.Cells) > i && len(templateRow.Cells[i].Paragraphs) > j {
+						templatePara := &templateRow.Cells[i].Paragraphs[j]
+						if len(templatePara.Runs) > 0 && templatePara.Runs[0].Properties != nil {
+							newRun.Properties = te.cloneRunProperties(templatePara.Runs[0].Properties)
 						}
-
-						newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
 					}
+
+					newRow.Cells[i].Paragraphs[j].Runs = []Run{newRun}
 				}
+			}
 
-				// 处理嵌套表格中的变量替换
-				for k := range newRow.Cells[i].Tables {
-					// 创建模板数据，用于嵌套表格的变量替换
-					nestedData := NewTemplateData()
-					nestedData.Variables = make(map[string]interface{})
-					for key, value := range itemMap {
-						nestedData.Variables[key] = value
-					}
-					// 递归处理嵌套表格
-					err := te.replaceVariablesInTable(&newRow.Cells[i].Tables[k], nestedData)
-					if err != nil {
-						Debugf("处理嵌套表格变量替换时出错: %v", err)
-					}
+			// 处理嵌套表格中的变量替换
+			for k := range newRow.Cells[i].Tables {
+				// 递归处理嵌套表格，嵌套表格可以访问当前项的字段
+				err := te.replaceVariablesInTable(&newRow.Cells[i].Tables[k], itemCtx)
+				if err != nil {
+					Debugf("处理嵌套表格变量替换时出错: %v", err)
 				}
 			}
 		}
@@ -237,21 +237,16 @@ func (tr *TemplateRenderer) analyzeTable(table *Table, index int) *TableAnalysis
 				}
 
 				// 检查循环语法
-				eachPattern := regexp.MustCompile(`\{\{#each\s+(\w+)\}\}`)
-				if matches := eachPattern.FindStringSubmatch(fullText); len(matches) > 1 {
+				if listExpr, ok := templateEachExpr(fullText); ok {
 					tableAnalysis.HasTemplate = true
 					tableAnalysis.TemplateRowIndex = rowIndex
-					tableAnalysis.LoopVariables = append(tableAnalysis.LoopVariables, matches[1])
+					tableAnalysis.LoopVariables = append(tableAnalysis.LoopVariables, listExpr)
 					rowHasLoop = true
 				}
 
 				// 提取变量
-				varPattern := regexp.MustCompile(`\{\{(\w+)\}\}`)
-				varMatches := varPattern.FindAllStringSubmatch(fullText, -1)
-				for _, match := range varMatches {
-					if len(match) >= 2 {
-						tableAnalysis.TemplateVars[match[1]] = true
-					}
+				for _, name := range collectTemplateReferences(fullText).variables {
+					tableAnalysis.TemplateVars[name] = true
 				}
 			}
 		}
@@ -265,32 +260,23 @@ func (tr *TemplateRenderer) analyzeTable(table *Table, index int) *TableAnalysis
 }
 
 // extractTemplateVariables 提取模板变量
+// 变量路径（如 {{customer.name}}）和比较表达式只记录根变量名
 func (tr *TemplateRenderer) extractTemplateVariables(text string, analysis *TemplateAnalysis) {
+	refs := collectTemplateReferences(text)
+
 	// 变量: {{变量名}}
-	varPattern := regexp.MustCompile(`\{\{(\w+)\}\}`)
-	varMatches := varPattern.FindAllStringSubmatch(text, -1)
-	for _, match := range varMatches {
-		if len(match) >= 2 {
-			analysis.Variables[match[1]] = true
-		}
+	for _, name := range refs.variables {
+		analysis.Variables[name] = true
 	}
 
 	// 条件: {{#if 条件}}
-	ifPattern := regexp.MustCompile(`\{\{#if\s+(\w+)\}\}`)
-	ifMatches := ifPattern.FindAllStringSubmatch(text, -1)
-	for _, match := range ifMatches {
-		if len(match) >= 2 {
-			analysis.Conditions[match[1]] = true
-		}
+	for _, name := range refs.conditions {
+		analysis.Conditions[name] = true
 	}
 
 	// 循环: {{#each 列表}}
-	eachPattern := regexp.MustCompile(`\{\{#each\s+(\w+)\}\}`)
-	eachMatches := eachPattern.FindAllStringSubmatch(text, -1)
-	for _, match := range eachMatches {
-		if len(match) >= 2 {
-			analysis.Lists[match[1]] = true
-		}
+	for _, name := range refs.lists {
+		analysis.Lists[name] = true
 	}
 }
 
@@ -0,0 +1,862 @@
+// Package document 模板表达式解析与求值
+package document
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprTokenKind 表达式词法单元类型
+type exprTokenKind int
+
+const (
+	exprTokenEOF exprTokenKind = iota
+	exprTokenIdent
+	exprTokenNumber
+	exprTokenString
+	exprTokenOperator
+)
+
+// exprToken 表达式词法单元
+type exprToken struct {
+	kind exprTokenKind
+	text string
+}
+
+// exprOperators 表达式运算符，双字符运算符在前以便优先匹配
+var exprOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ".", "-", "|"}
+
+// tokenizeTemplateExpr 将表达式切分为词法单元
+func tokenizeTemplateExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				value.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("字符串未结束")
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenString, text: value.String()})
+			i = j + 1
+		case unicode.IsDigit(r):
+			// 路径中的数字下标（items.0.name）不含小数部分
+			inPath := len(tokens) > 0 && tokens[len(tokens)-1].kind == exprTokenOperator && tokens[len(tokens)-1].text == "."
+			j, hasDot := i, inPath
+			for j < len(runes) {
+				if runes[j] == '.' && !hasDot && j+1 < len(runes) && unicode.IsDigit(runes[j+1]) {
+					hasDot = true
+				} else if !unicode.IsDigit(runes[j]) {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenNumber, text: string(runes[i:j])})
+			i = j
+		case r == '_' || r == '@' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenIdent, text: string(runes[i:j])})
+			i = j
+		default:
+			matched := ""
+			for _, op := range exprOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					matched = op
+					break
+				}
+			}
+			if matched == "" {
+				return nil, fmt.Errorf("无法识别的字符 %q", r)
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenOperator, text: matched})
+			i += len([]rune(matched))
+		}
+	}
+	return append(tokens, exprToken{kind: exprTokenEOF}), nil
+}
+
+// templateExpr 模板表达式节点，求值结果的第二个返回值表示值是否存在
+type templateExpr interface {
+	eval(ctx *templateContext) (interface{}, bool)
+}
+
+// literalExpr 字面量
+type literalExpr struct {
+	value interface{}
+}
+
+// pathSegment 路径的一段：字段名（.name）或索引（[expr]）
+type pathSegment struct {
+	name  string
+	index templateExpr
+}
+
+// pathExpr 变量路径，如 customer.address.city、items[0].name
+type pathExpr struct {
+	root     string
+	segments []pathSegment
+}
+
+// notExpr 逻辑非
+type notExpr struct {
+	operand templateExpr
+}
+
+// negExpr 取负
+type negExpr struct {
+	operand templateExpr
+}
+
+// binaryExpr 比较或逻辑运算
+type binaryExpr struct {
+	op          string
+	left, right templateExpr
+}
+
+// filterExpr 过滤器调用：input | name arg1 arg2
+type filterExpr struct {
+	input templateExpr
+	name  string
+	args  []templateExpr
+}
+
+// parseTemplateExpr 解析模板表达式
+//
+// 支持的语法：变量路径（a.b.c、a[0]、a["key"]、this、@index、@first、@last、@key、@root.a），
+// 字符串、数字、true/false/nil 字面量，比较运算 == != < <= > >=，
+// 逻辑运算 && || !（或 and or not），括号分组，以及优先级最低的过滤器管道
+// （amount | currency "CNY"、items | len，过滤器参数为字面量或变量路径）。
+func parseTemplateExpr(src string) (templateExpr, error) {
+	tokens, err := tokenizeTemplateExpr(src)
+	if err != nil {
+		return nil, NewValidationError("template_expression", src, err.Error())
+	}
+	parser := &exprParser{tokens: tokens}
+	expr, err := parser.parsePipe()
+	if err == nil && parser.peek().kind != exprTokenEOF {
+		err = fmt.Errorf("多余的内容 %q", parser.peek().text)
+	}
+	if err != nil {
+		return nil, NewValidationError("template_expression", src, err.Error())
+	}
+	return expr, nil
+}
+
+// exprParser 递归下降表达式解析器
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	token := p.tokens[p.pos]
+	if token.kind != exprTokenEOF {
+		p.pos++
+	}
+	return token
+}
+
+// accept 当前词法单元是给定运算符或关键字之一时消费并返回它
+func (p *exprParser) accept(texts ...string) (string, bool) {
+	token := p.peek()
+	if token.kind != exprTokenOperator && token.kind != exprTokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if token.text == text {
+			p.next()
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		return fmt.Errorf("缺少 %q", text)
+	}
+	return nil
+}
+
+// parsePipe 解析过滤器管道，管道左侧为完整的逻辑表达式
+func (p *exprParser) parsePipe() (templateExpr, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("|"); !ok {
+			return expr, nil
+		}
+		name := p.next()
+		if name.kind != exprTokenIdent {
+			return nil, fmt.Errorf("\"|\" 之后缺少过滤器名称")
+		}
+		filter := &filterExpr{input: expr, name: name.text}
+		for !p.atPipeEnd() {
+			arg, err := p.parseFilterArg()
+			if err != nil {
+				return nil, err
+			}
+			filter.args = append(filter.args, arg)
+		}
+		expr = filter
+	}
+}
+
+// atPipeEnd 判断过滤器参数列表是否结束
+func (p *exprParser) atPipeEnd() bool {
+	token := p.peek()
+	return token.kind == exprTokenEOF ||
+		(token.kind == exprTokenOperator && (token.text == "|" || token.text == ")" || token.text == "]"))
+}
+
+// parseFilterArg 解析过滤器参数：字面量、变量路径、负数或括号表达式
+func (p *exprParser) parseFilterArg() (templateExpr, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &negExpr{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parseOr() (templateExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (templateExpr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseComparison() (templateExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseUnary() (templateExpr, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	}
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negExpr{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (templateExpr, error) {
+	token := p.next()
+	switch token.kind {
+	case exprTokenString:
+		return &literalExpr{value: token.text}, nil
+	case exprTokenNumber:
+		if value, err := strconv.Atoi(token.text); err == nil {
+			return &literalExpr{value: value}, nil
+		}
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的数字 %q", token.text)
+		}
+		return &literalExpr{value: value}, nil
+	case exprTokenIdent:
+		switch token.text {
+		case "true":
+			return &literalExpr{value: true}, nil
+		case "false":
+			return &literalExpr{value: false}, nil
+		case "nil", "null":
+			return &literalExpr{value: nil}, nil
+		}
+		return p.parsePath(token.text)
+	case exprTokenOperator:
+		if token.text == "(" {
+			expr, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		}
+	case exprTokenEOF:
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	return nil, fmt.Errorf("意外的 %q", token.text)
+}
+
+// parsePath 解析变量路径的字段和索引部分
+func (p *exprParser) parsePath(root string) (templateExpr, error) {
+	path := &pathExpr{root: root}
+	for {
+		if _, ok := p.accept("."); ok {
+			token := p.next()
+			switch token.kind {
+			case exprTokenIdent:
+				path.segments = append(path.segments, pathSegment{name: token.text})
+			case exprTokenNumber:
+				index, err := strconv.Atoi(token.text)
+				if err != nil {
+					return nil, fmt.Errorf("无效的索引 %q", token.text)
+				}
+				path.segments = append(path.segments, pathSegment{index: &literalExpr{value: index}})
+			default:
+				return nil, fmt.Errorf("\".\" 之后缺少字段名")
+			}
+			continue
+		}
+		if _, ok := p.accept("["); ok {
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			path.segments = append(path.segments, pathSegment{index: index})
+			continue
+		}
+		return path, nil
+	}
+}
+
+func (e *literalExpr) eval(ctx *templateContext) (interface{}, bool) {
+	return e.value, true
+}
+
+func (e *pathExpr) eval(ctx *templateContext) (interface{}, bool) {
+	segments := e.segments
+	var value interface{}
+	var ok bool
+	if e.root == "@root" {
+		if len(segments) == 0 {
+			return nil, false
+		}
+		value, ok = ctx.lookupData(segments[0].name)
+		segments = segments[1:]
+	} else {
+		value, ok = ctx.lookup(e.root)
+	}
+
+	for _, segment := range segments {
+		if !ok {
+			return nil, false
+		}
+		if segment.index == nil {
+			value, ok = templateMember(value, segment.name)
+			continue
+		}
+		index, defined := segment.index.eval(ctx)
+		if !defined {
+			return nil, false
+		}
+		value, ok = templateIndex(value, index)
+	}
+	return value, ok
+}
+
+func (e *notExpr) eval(ctx *templateContext) (interface{}, bool) {
+	value, _ := e.operand.eval(ctx)
+	return !templateTruthy(value), true
+}
+
+func (e *negExpr) eval(ctx *templateContext) (interface{}, bool) {
+	value, ok := e.operand.eval(ctx)
+	number, isNumber := templateNumber(value)
+	if !ok || !isNumber {
+		return nil, false
+	}
+	return -number, true
+}
+
+func (e *binaryExpr) eval(ctx *templateContext) (interface{}, bool) {
+	left, _ := e.left.eval(ctx)
+	switch e.op {
+	case "&&":
+		if !templateTruthy(left) {
+			return false, true
+		}
+		right, _ := e.right.eval(ctx)
+		return templateTruthy(right), true
+	case "||":
+		if templateTruthy(left) {
+			return true, true
+		}
+		right, _ := e.right.eval(ctx)
+		return templateTruthy(right), true
+	}
+
+	right, _ := e.right.eval(ctx)
+	switch e.op {
+	case "==":
+		return templateEquals(left, right), true
+	case "!=":
+		return !templateEquals(left, right), true
+	}
+	if left == nil || right == nil {
+		return false, true
+	}
+	result := templateCompare(left, right)
+	switch e.op {
+	case "<":
+		return result < 0, true
+	case "<=":
+		return result <= 0, true
+	case ">":
+		return result > 0, true
+	default:
+		return result >= 0, true
+	}
+}
+
+func (e *filterExpr) eval(ctx *templateContext) (interface{}, bool) {
+	fn, ok := ctx.engine.lookupFilter(e.name)
+	if !ok {
+		Warnf("模板过滤器 %s 未注册", e.name)
+		return nil, false
+	}
+	// 只有 default 过滤器处理不存在的值，其余过滤器遇到不存在的值时保留占位符
+	value, defined := e.input.eval(ctx)
+	if !defined && e.name != "default" {
+		return nil, false
+	}
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		args[i], _ = arg.eval(ctx)
+	}
+	result, err := fn(value, args...)
+	if err != nil {
+		Warnf("模板过滤器 %s 执行失败: %v", e.name, err)
+		return nil, false
+	}
+	return result, true
+}
+
+// templateScope 模板作用域，对应 {{#each}} 的当前项或 {{#with}} 的值
+type templateScope struct {
+	value interface{}
+	loop  bool
+	index int
+	count int
+	key   string
+}
+
+// templateContext 模板渲染上下文
+// 变量按作用域由内向外查找，最后查找模板数据中的 Variables、Lists 和 Conditions；
+// {{#if name}} 的单独名称不在作用域中时先查找 Conditions
+type templateContext struct {
+	engine   *TemplateEngine
+	data     *TemplateData
+	scopes   []templateScope
+	partials []string          // 正在渲染的局部模板，用于检测循环引用
+	includes []string          // 正在展开的包含文档，用于检测循环包含
+	doc      *Document         // 目标文档，设置后富内容变量值可插入段落和表格
+	deferred *templateDeferred // 渲染中暂存的内容，生成段落后插入
+}
+
+// newTemplateContext 创建模板渲染上下文
+func (te *TemplateEngine) newTemplateContext(data *TemplateData) *templateContext {
+	if data == nil {
+		data = NewTemplateData()
+	}
+	return &templateContext{engine: te, data: data}
+}
+
+// withScope 返回增加了一层作用域的上下文
+func (ctx *templateContext) withScope(scope templateScope) *templateContext {
+	child := *ctx
+	child.scopes = append(append([]templateScope(nil), ctx.scopes...), scope)
+	return &child
+}
+
+// lookup 按名称查找变量
+func (ctx *templateContext) lookup(name string) (interface{}, bool) {
+	switch name {
+	case "this", "@index", "@first", "@last", "@key":
+		if len(ctx.scopes) == 0 {
+			return nil, false
+		}
+		scope := ctx.scopes[len(ctx.scopes)-1]
+		if name == "this" {
+			return scope.value, true
+		}
+		if !scope.loop {
+			return nil, false
+		}
+		switch name {
+		case "@index":
+			return scope.index, true
+		case "@first":
+			return scope.index == 0, true
+		case "@last":
+			return scope.index == scope.count-1, true
+		default:
+			return scope.key, scope.key != ""
+		}
+	}
+
+	for i := len(ctx.scopes) - 1; i >= 0; i-- {
+		if value, ok := templateMember(ctx.scopes[i].value, name); ok {
+			return value, true
+		}
+	}
+	return ctx.lookupData(name)
+}
+
+// evalCondition 计算 {{#if}} 和 {{#unless}} 的条件
+// 条件是单独的名称且不是作用域中的字段时优先使用 Conditions，不受同名变量影响
+func (ctx *templateContext) evalCondition(expr templateExpr) interface{} {
+	if path, ok := expr.(*pathExpr); ok && len(path.segments) == 0 {
+		if value, ok := ctx.data.Conditions[path.root]; ok && !ctx.inScope(path.root) {
+			return value
+		}
+	}
+	value, _ := expr.eval(ctx)
+	return value
+}
+
+// inScope 判断名称是否为作用域中的字段
+func (ctx *templateContext) inScope(name string) bool {
+	for _, scope := range ctx.scopes {
+		if _, ok := templateMember(scope.value, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupData 在模板数据中查找变量
+func (ctx *templateContext) lookupData(name string) (interface{}, bool) {
+	if value, ok := ctx.data.Variables[name]; ok {
+		return value, true
+	}
+	if value, ok := ctx.data.Lists[name]; ok {
+		return value, true
+	}
+	if value, ok := ctx.data.Conditions[name]; ok {
+		return value, true
+	}
+	return nil, false
+}
+
+// templateValue 解引用指针和接口
+func templateValue(value interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// templateMember 获取映射的键或结构体的字段
+// 结构体字段依次按字段名、json标签名和不区分大小写的字段名匹配
+func templateMember(value interface{}, name string) (interface{}, bool) {
+	v, ok := templateValue(value)
+	if !ok {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		item := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !item.IsValid() {
+			return nil, false
+		}
+		return item.Interface(), true
+	case reflect.Struct:
+		typ := v.Type()
+		if field, ok := typ.FieldByName(name); ok && field.PkgPath == "" {
+			return v.FieldByIndex(field.Index).Interface(), true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if tag == name || (tag == "" && strings.EqualFold(field.Name, name)) {
+				return v.Field(i).Interface(), true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.String:
+		if name == "length" {
+			return v.Len(), true
+		}
+	}
+	return nil, false
+}
+
+// templateIndex 按数字下标访问切片和数组，按字符串访问映射和结构体
+func templateIndex(value interface{}, index interface{}) (interface{}, bool) {
+	if name, ok := index.(string); ok {
+		return templateMember(value, name)
+	}
+	number, ok := templateNumber(index)
+	if !ok {
+		return nil, false
+	}
+	v, ok := templateValue(value)
+	if !ok || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, false
+	}
+	i := int(number)
+	if i < 0 || i >= v.Len() {
+		return nil, false
+	}
+	return v.Index(i).Interface(), true
+}
+
+// templateItems 将切片、数组或映射展开为循环作用域，映射按键排序
+func templateItems(value interface{}) []templateScope {
+	v, ok := templateValue(value)
+	if !ok {
+		return nil
+	}
+	var items []templateScope
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, templateScope{value: v.Index(i).Interface(), loop: true, index: i, count: v.Len()})
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for i, key := range keys {
+			items = append(items, templateScope{
+				value: v.MapIndex(key).Interface(),
+				loop:  true,
+				index: i,
+				count: len(keys),
+				key:   fmt.Sprint(key.Interface()),
+			})
+		}
+	}
+	return items
+}
+
+// templateTruthy 判断值在条件中是否为真
+// nil、false、0、空字符串和空集合为假，其余为真
+func templateTruthy(value interface{}) bool {
+	v, ok := templateValue(value)
+	if !ok {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() != 0
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	}
+	return true
+}
+
+// templateNumber 将数值或数字字符串转换为float64
+func templateNumber(value interface{}) (float64, bool) {
+	v, ok := templateValue(value)
+	if !ok {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// isTemplateNumeric 判断值是否为数值类型（不含字符串）
+func isTemplateNumeric(value interface{}) bool {
+	v, ok := templateValue(value)
+	if !ok {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// templateEquals 比较两个值是否相等
+// 任一方为数值时按数值比较，其余按字符串形式比较，nil只与nil相等
+func templateEquals(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if isTemplateNumeric(left) || isTemplateNumeric(right) {
+		l, lok := templateNumber(left)
+		r, rok := templateNumber(right)
+		if lok && rok {
+			return l == r
+		}
+	}
+	return fmt.Sprint(left) == fmt.Sprint(right)
+}
+
+// templateCompare 比较两个值的大小，双方都能转换为数字时按数值比较，否则按字符串比较
+func templateCompare(left, right interface{}) int {
+	l, lok := templateNumber(left)
+	r, rok := templateNumber(right)
+	if lok && rok {
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+}
+
+// templateExprRoots 返回表达式引用的根变量名（不含 this 和 @ 开头的特殊变量）
+func templateExprRoots(expr templateExpr) []string {
+	var roots []string
+	var walk func(templateExpr)
+	walk = func(e templateExpr) {
+		switch node := e.(type) {
+		case *pathExpr:
+			if node.root == "@root" && len(node.segments) > 0 && node.segments[0].index == nil {
+				roots = append(roots, node.segments[0].name)
+			} else if node.root != "this" && !strings.HasPrefix(node.root, "@") {
+				roots = append(roots, node.root)
+			}
+			for _, segment := range node.segments {
+				if segment.index != nil {
+					walk(segment.index)
+				}
+			}
+		case *notExpr:
+			walk(node.operand)
+		case *negExpr:
+			walk(node.operand)
+		case *binaryExpr:
+			walk(node.left)
+			walk(node.right)
+		case *filterExpr:
+			walk(node.input)
+			for _, arg := range node.args {
+				walk(arg)
+			}
+		}
+	}
+	walk(expr)
+	return roots
+}
+
+// templateExprFilters 返回表达式使用的过滤器名称
+func templateExprFilters(expr templateExpr) []string {
+	var names []string
+	var walk func(templateExpr)
+	walk = func(e templateExpr) {
+		switch node := e.(type) {
+		case *pathExpr:
+			for _, segment := range node.segments {
+				if segment.index != nil {
+					walk(segment.index)
+				}
+			}
+		case *notExpr:
+			walk(node.operand)
+		case *negExpr:
+			walk(node.operand)
+		case *binaryExpr:
+			walk(node.left)
+			walk(node.right)
+		case *filterExpr:
+			names = append(names, node.name)
+			walk(node.input)
+			for _, arg := range node.args {
+				walk(arg)
+			}
+		}
+	}
+	walk(expr)
+	return names
+}
@@ -0,0 +1,186 @@
+package document
+
+import (
+	"strings"
+	"testing"
+)
+
+// templateExprOrder 表达式测试使用的结构体数据
+type templateExprOrder struct {
+	ID       string `json:"id"`
+	Total    float64
+	Status   string
+	Customer map[string]interface{}
+	Items    []templateExprItem
+}
+
+type templateExprItem struct {
+	Name string `json:"name"`
+	Qty  int    `json:"qty"`
+}
+
+// newTemplateExprData 创建包含嵌套数据的模板数据
+func newTemplateExprData() *TemplateData {
+	data := NewTemplateData()
+	data.SetVariable("order", templateExprOrder{
+		ID:     "SO-001",
+		Total:  1280.5,
+		Status: "paid",
+		Customer: map[string]interface{}{
+			"name":    "张三",
+			"address": map[string]interface{}{"city": "杭州"},
+			"tags":    []interface{}{"vip", "new"},
+		},
+		Items: []templateExprItem{{Name: "键盘", Qty: 2}, {Name: "鼠标", Qty: 0}},
+	})
+	data.SetVariable("threshold", 1000)
+	data.SetList("lines", []interface{}{
+		map[string]interface{}{"name": "A", "price": 30},
+		map[string]interface{}{"name": "B", "price": 120},
+	})
+	data.SetCondition("showNote", false)
+	return data
+}
+
+// renderedText 返回文档所有段落的文本
+func renderedText(doc *Document) string {
+	var texts []string
+	for _, para := range doc.Body.GetParagraphs() {
+		text := ""
+		for _, run := range para.Runs {
+			text += run.Text.Content
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// TestTemplateExpressions 测试变量路径、比较运算和条件分支
+func TestTemplateExpressions(t *testing.T) {
+	engine := NewTemplateEngine()
+	data := newTemplateExprData()
+
+	cases := []struct {
+		template string
+		expected string
+	}{
+		{"{{order.Customer.address.city}}", "杭州"},
+		{"{{order.id}}/{{order.customer.tags[1]}}/{{order.Items.0.name}}", "SO-001/new/键盘"},
+		{"{{order.Items.length}}", "2"},
+		{"{{#if order.Total > threshold}}大额{{/if}}", "大额"},
+		{`{{#if order.Status == "paid" && !showNote}}已支付{{else}}未支付{{/if}}`, "已支付"},
+		{`{{#if order.Status == "open"}}待付{{else if order.Status == 'paid'}}已付{{else}}其他{{/if}}`, "已付"},
+		{"{{#unless showNote}}无备注{{else}}有备注{{/unless}}", "无备注"},
+		{"{{#with order.Customer}}{{name}}@{{address.city}}{{/with}}", "张三@杭州"},
+		{"{{#with missing}}有{{else}}无{{/with}}", "无"},
+		{"{{#each order.Items}}{{@index}}:{{name}}{{#if qty > 0}}x{{qty}}{{/if}}{{#unless @last}},{{/unless}}{{/each}}", "0:键盘x2,1:鼠标"},
+		{"{{#each lines}}{{#if price >= 100 || name == @root.order.id}}{{name}}{{/if}}{{/each}}", "B"},
+		{"{{#each empty}}有{{else}}空列表{{/each}}", "空列表"},
+		{"{{unknown.path}}", "{{unknown.path}}"},
+	}
+
+	for i, c := range cases {
+		name := "expr_" + string(rune('a'+i))
+		if _, err := engine.LoadTemplate(name, c.template); err != nil {
+			t.Fatalf("加载模板 %q 失败: %v", c.template, err)
+		}
+		doc, err := engine.RenderToDocument(name, data)
+		if err != nil {
+			t.Fatalf("渲染模板 %q 失败: %v", c.template, err)
+		}
+		if got := renderedText(doc); got != c.expected {
+			t.Errorf("模板 %q 渲染结果为 %q，期望 %q", c.template, got, c.expected)
+		}
+	}
+
+	// 表达式语法错误和块嵌套错误应在校验模板时报告
+	for _, invalid := range []string{"{{#if a >}}x{{/if}}", "{{#with a}}x{{/if}}", "{{else}}"} {
+		template, err := engine.LoadTemplate("invalid", invalid)
+		if err != nil {
+			t.Fatalf("加载模板 %q 失败: %v", invalid, err)
+		}
+		if err := engine.ValidateTemplate(template); err == nil {
+			t.Errorf("模板 %q 应校验失败", invalid)
+		}
+	}
+}
+
+// TestTemplateConditionPrecedence 测试条件与变量同名时 {{#if}} 使用条件的值
+func TestTemplateConditionPrecedence(t *testing.T) {
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplate("notice", "{{#if vip}}贵宾{{else}}普通{{/if}}|{{#unless vip}}无{{/unless}}|{{vip}}|{{#each members}}{{#if vip}}V{{else}}-{{/if}}{{/each}}"); err != nil {
+		t.Fatalf("加载模板失败: %v", err)
+	}
+
+	data := NewTemplateData()
+	data.SetVariable("vip", "")
+	data.SetCondition("vip", true)
+	data.SetList("members", []interface{}{
+		map[string]interface{}{"vip": false},
+		map[string]interface{}{"name": "李四"},
+	})
+
+	doc, err := engine.RenderToDocument("notice", data)
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+	// 作用域中的同名字段优先于条件
+	if got := renderedText(doc); got != "贵宾|||-V" {
+		t.Errorf("渲染结果为 %q，期望 %q", got, "贵宾|||-V")
+	}
+}
+
+// TestDocumentTemplateExpressions 测试文档模板中的表达式，包括表格循环和页眉
+func TestDocumentTemplateExpressions(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("客户：{{order.Customer.name}}")
+	doc.AddParagraph("{{#if order.Total > threshold}}大额订单{{else}}普通订单{{/if}}")
+	doc.AddParagraph("{{#each order.Items}}")
+	doc.AddParagraph("{{name}} 数量 {{qty}}")
+	doc.AddParagraph("{{/each}}")
+
+	table, err := doc.AddTable(&TableConfig{Rows: 2, Cols: 2, Width: 4000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.SetCellText(0, 0, "商品")
+	table.SetCellText(0, 1, "状态")
+	table.SetCellText(1, 0, "{{#each order.Items}}{{name}}")
+	table.SetCellText(1, 1, "{{#if qty > 0}}有货{{else}}缺货{{/if}}{{/each}}")
+
+	if err := doc.AddHeader(HeaderFooterTypeDefault, "{{#if order.Total > 1000}}VIP{{/if}} {{order.id}}"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("expr_doc", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+	result, err := engine.RenderTemplateToDocument("expr_doc", newTemplateExprData())
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	text := renderedText(result)
+	for _, expected := range []string{"客户：张三", "大额订单", "键盘 数量 2", "鼠标 数量 0"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, text)
+		}
+	}
+
+	rendered := result.Body.GetTables()[0]
+	if len(rendered.Rows) != 3 {
+		t.Fatalf("表格应有3行，实际 %d 行", len(rendered.Rows))
+	}
+	for i, expected := range [][2]string{{"键盘", "有货"}, {"鼠标", "缺货"}} {
+		name, _ := rendered.GetCellText(i+1, 0)
+		status, _ := rendered.GetCellText(i+1, 1)
+		if name != expected[0] || status != expected[1] {
+			t.Errorf("第 %d 行为 %s/%s，期望 %s/%s", i+1, name, status, expected[0], expected[1])
+		}
+	}
+
+	if header := result.GetHeader(HeaderFooterTypeDefault).GetText(); header != "VIP SO-001" {
+		t.Errorf("页眉中的比较表达式应被渲染: %s", header)
+	}
+}
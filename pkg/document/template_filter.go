@@ -0,0 +1,527 @@
+// Package document 模板过滤器
+package document
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TemplateFilterFunc 模板过滤器函数
+//
+// value 为管道左侧的值，args 为过滤器名称之后的参数（字符串、数字、布尔值或变量的值）。
+// 返回错误时保留原占位符并记录警告。
+//
+// 示例:
+//
+//	engine.RegisterFilter("mask", func(value interface{}, args ...interface{}) (interface{}, error) {
+//		s := fmt.Sprint(value)
+//		if len(s) <= 4 {
+//			return s, nil
+//		}
+//		return strings.Repeat("*", len(s)-4) + s[len(s)-4:], nil
+//	})
+//
+// 模板中使用 {{cardNo | mask}}。
+type TemplateFilterFunc func(value interface{}, args ...interface{}) (interface{}, error)
+
+// templateFilterNamePattern 过滤器名称格式
+var templateFilterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// builtinTemplateFilters 内置过滤器
+var builtinTemplateFilters = map[string]TemplateFilterFunc{
+	"upper":     filterUpper,
+	"lower":     filterLower,
+	"trim":      filterTrim,
+	"len":       filterLen,
+	"number":    filterNumber,
+	"currency":  filterCurrency,
+	"percent":   filterPercent,
+	"rmb_upper": filterRMBUpper,
+	"date":      filterDate,
+	"default":   filterDefault,
+	"truncate":  filterTruncate,
+	"join":      filterJoin,
+}
+
+// RegisterFilter 注册自定义模板过滤器，与内置过滤器同名时覆盖内置过滤器
+//
+// 过滤器在 {{value | name arg1 arg2}} 中使用，可以连续使用多个过滤器。
+func (te *TemplateEngine) RegisterFilter(name string, fn TemplateFilterFunc) error {
+	if !templateFilterNamePattern.MatchString(name) {
+		return NewValidationError("filter_name", name, "过滤器名称只能包含字母、数字和下划线，且不能以数字开头")
+	}
+	if fn == nil {
+		return NewValidationError("filter", name, "过滤器函数不能为空")
+	}
+
+	te.mutex.Lock()
+	defer te.mutex.Unlock()
+	if te.filters == nil {
+		te.filters = make(map[string]TemplateFilterFunc)
+	}
+	te.filters[name] = fn
+	return nil
+}
+
+// lookupFilter 查找过滤器，自定义过滤器优先
+func (te *TemplateEngine) lookupFilter(name string) (TemplateFilterFunc, bool) {
+	te.mutex.RLock()
+	fn, ok := te.filters[name]
+	te.mutex.RUnlock()
+	if ok {
+		return fn, true
+	}
+	fn, ok = builtinTemplateFilters[name]
+	return fn, ok
+}
+
+// validateFilters 检查模板使用的过滤器是否都已注册
+func (te *TemplateEngine) validateFilters(nodes []templateNode) error {
+	var err error
+	walkTemplateNodes(nodes, func(node templateNode) {
+		var exprs []templateExpr
+		switch n := node.(type) {
+		case *templateOutputNode:
+			exprs = append(exprs, n.expr)
+		case *templateIfNode:
+			for _, branch := range n.branches {
+				exprs = append(exprs, branch.expr)
+			}
+		case *templateEachNode:
+			exprs = append(exprs, n.expr)
+		case *templateWithNode:
+			exprs = append(exprs, n.expr)
+		case *templatePartialNode:
+			if n.expr != nil {
+				exprs = append(exprs, n.expr)
+			}
+		}
+		for _, expr := range exprs {
+			for _, name := range templateExprFilters(expr) {
+				if _, ok := te.lookupFilter(name); !ok && err == nil {
+					err = NewValidationError("template_filter", name, "过滤器未注册")
+				}
+			}
+		}
+	})
+	return err
+}
+
+// filterArgInt 读取整数参数，缺省时返回 def
+func filterArgInt(args []interface{}, i int, def int) (int, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	number, ok := templateNumber(args[i])
+	if !ok {
+		return 0, fmt.Errorf("第 %d 个参数应为数字，实际为 %v", i+1, args[i])
+	}
+	return int(number), nil
+}
+
+// filterArgString 读取字符串参数，缺省时返回 def
+func filterArgString(args []interface{}, i int, def string) string {
+	if i >= len(args) || args[i] == nil {
+		return def
+	}
+	return fmt.Sprint(args[i])
+}
+
+// filterNumberValue 将过滤器的输入转换为数字
+func filterNumberValue(value interface{}) (float64, error) {
+	number, ok := templateNumber(value)
+	if !ok {
+		return 0, fmt.Errorf("%v 不是数字", value)
+	}
+	return number, nil
+}
+
+// filterString 将值转换为字符串，nil 转换为空字符串
+func filterString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+func filterUpper(value interface{}, args ...interface{}) (interface{}, error) {
+	return strings.ToUpper(filterString(value)), nil
+}
+
+func filterLower(value interface{}, args ...interface{}) (interface{}, error) {
+	return strings.ToLower(filterString(value)), nil
+}
+
+func filterTrim(value interface{}, args ...interface{}) (interface{}, error) {
+	return strings.TrimSpace(filterString(value)), nil
+}
+
+// filterLen 返回字符串的字符数或切片、数组、映射的元素个数
+func filterLen(value interface{}, args ...interface{}) (interface{}, error) {
+	v, ok := templateValue(value)
+	if !ok {
+		return 0, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len(), nil
+	}
+	return nil, fmt.Errorf("%T 没有长度", value)
+}
+
+// numberLocale 数字格式的千位分隔符和小数点
+type numberLocale struct {
+	group   string
+	decimal string
+}
+
+// numberLocales 按语言代码区分的数字格式，未列出的语言使用英文格式
+// 以空格分组的语言使用不换行空格，避免数字在行尾被拆开
+var numberLocales = map[string]numberLocale{
+	"en": {",", "."},
+	"zh": {",", "."},
+	"ja": {",", "."},
+	"ko": {",", "."},
+	"de": {".", ","},
+	"es": {".", ","},
+	"it": {".", ","},
+	"nl": {".", ","},
+	"pt": {".", ","},
+	"id": {".", ","},
+	"tr": {".", ","},
+	"fr": {"\u00a0", ","},
+	"ru": {"\u00a0", ","},
+	"pl": {"\u00a0", ","},
+	"cs": {"\u00a0", ","},
+	"sv": {"\u00a0", ","},
+	"fi": {"\u00a0", ","},
+	"nb": {"\u00a0", ","},
+}
+
+// lookupNumberLocale 根据 "de"、"de-DE"、"zh_CN" 形式的语言代码查找数字格式
+func lookupNumberLocale(locale string) numberLocale {
+	if locale == "de-CH" || locale == "de_CH" {
+		return numberLocale{"'", "."}
+	}
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) > 0 {
+		if format, ok := numberLocales[strings.ToLower(parts[0])]; ok {
+			return format
+		}
+	}
+	return numberLocales["en"]
+}
+
+// maxFilterPrecision 数字过滤器支持的最大小数位数（float64 的有效十进制位数）
+const maxFilterPrecision = 15
+
+// formatGroupedNumber 按千位分组格式化数字，precision 小于0时使用最短表示，
+// 超过 maxFilterPrecision 时按最大小数位数处理
+// 舍入方式为四舍五入（strconv 对恰好位于中间的值采用银行家舍入，不适合金额）
+func formatGroupedNumber(number float64, precision int, locale numberLocale) string {
+	if precision > maxFilterPrecision {
+		precision = maxFilterPrecision
+	}
+	abs := math.Abs(number)
+	if precision >= 0 {
+		scale := math.Pow(10, float64(precision))
+		// 数值过大时放大后溢出，此时已没有小数部分，无需舍入
+		if scaled := abs * scale; !math.IsInf(scaled, 0) {
+			abs = math.Round(scaled) / scale
+		}
+	}
+	text := strconv.FormatFloat(abs, 'f', precision, 64)
+	integer, fraction := text, ""
+	if index := strings.IndexByte(text, '.'); index >= 0 {
+		integer, fraction = text[:index], text[index+1:]
+	}
+
+	var builder strings.Builder
+	if number < 0 && strings.Trim(text, "0.") != "" {
+		builder.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			builder.WriteString(locale.group)
+		}
+		builder.WriteRune(digit)
+	}
+	if fraction != "" {
+		builder.WriteString(locale.decimal)
+		builder.WriteString(fraction)
+	}
+	return builder.String()
+}
+
+// filterNumber 千位分组和小数位数：{{amount | number 2}}、{{amount | number 2 "de-DE"}}
+func filterNumber(value interface{}, args ...interface{}) (interface{}, error) {
+	number, err := filterNumberValue(value)
+	if err != nil {
+		return nil, err
+	}
+	precision, err := filterArgInt(args, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	return formatGroupedNumber(number, precision, lookupNumberLocale(filterArgString(args, 1, "zh-CN"))), nil
+}
+
+// currencyFormat 货币符号和默认小数位数
+type currencyFormat struct {
+	symbol    string
+	precision int
+}
+
+// currencyFormats 常用货币，未列出的货币以货币代码作为前缀
+var currencyFormats = map[string]currencyFormat{
+	"CNY": {"¥", 2},
+	"RMB": {"¥", 2},
+	"USD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"KRW": {"₩", 0},
+	"HKD": {"HK$", 2},
+	"TWD": {"NT$", 2},
+	"INR": {"₹", 2},
+}
+
+// filterCurrency 货币格式：{{amount | currency "CNY"}}，可选参数为小数位数和数字格式的语言代码
+func filterCurrency(value interface{}, args ...interface{}) (interface{}, error) {
+	number, err := filterNumberValue(value)
+	if err != nil {
+		return nil, err
+	}
+	code := strings.ToUpper(filterArgString(args, 0, "CNY"))
+	format, ok := currencyFormats[code]
+	if !ok {
+		format = currencyFormat{symbol: code + " ", precision: 2}
+	}
+	precision, err := filterArgInt(args, 1, format.precision)
+	if err != nil {
+		return nil, err
+	}
+
+	text := formatGroupedNumber(number, precision, lookupNumberLocale(filterArgString(args, 2, "zh-CN")))
+	if strings.HasPrefix(text, "-") {
+		return "-" + format.symbol + text[1:], nil
+	}
+	return format.symbol + text, nil
+}
+
+// filterPercent 百分比：{{rate | percent 1}}，0.1234 输出 12.3%
+func filterPercent(value interface{}, args ...interface{}) (interface{}, error) {
+	number, err := filterNumberValue(value)
+	if err != nil {
+		return nil, err
+	}
+	precision, err := filterArgInt(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return formatGroupedNumber(number*100, precision, lookupNumberLocale(filterArgString(args, 1, "zh-CN"))) + "%", nil
+}
+
+var (
+	rmbDigits     = []string{"零", "壹", "贰", "叁", "肆", "伍", "陆", "柒", "捌", "玖"}
+	rmbUnits      = []string{"", "拾", "佰", "仟"}
+	rmbGroupUnits = []string{"", "万", "亿", "万亿"}
+)
+
+// rmbMaxAmount 支持的最大金额（不含），即一万万亿
+const rmbMaxAmount = 1e16
+
+// filterRMBUpper 人民币大写金额：1234.5 输出 壹仟贰佰叁拾肆元伍角整
+func filterRMBUpper(value interface{}, args ...interface{}) (interface{}, error) {
+	number, err := filterNumberValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return rmbUpper(number)
+}
+
+// rmbUpper 将金额转换为人民币大写，金额按分四舍五入
+//
+// 整数部分按万、亿分节读写，中间连续的零只读一个"零"；没有角分时以"整"结尾，
+// 例如 100010.05 转换为 壹拾万零壹拾元零伍分。
+func rmbUpper(amount float64) (string, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) || math.Abs(amount) >= rmbMaxAmount {
+		return "", NewValidationError("amount", fmt.Sprint(amount), "金额超出人民币大写支持的范围")
+	}
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	if cents == 0 {
+		return "零元整", nil
+	}
+
+	var builder strings.Builder
+	if amount < 0 {
+		builder.WriteString("负")
+	}
+	yuan, jiao, fen := cents/100, cents/10%10, cents%10
+	if yuan > 0 {
+		builder.WriteString(rmbUpperInteger(yuan))
+		builder.WriteString("元")
+	}
+	switch {
+	case jiao == 0 && fen == 0:
+		builder.WriteString("整")
+	case fen == 0:
+		builder.WriteString(rmbDigits[jiao] + "角整")
+	case jiao == 0:
+		if yuan > 0 {
+			builder.WriteString("零")
+		}
+		builder.WriteString(rmbDigits[fen] + "分")
+	default:
+		builder.WriteString(rmbDigits[jiao] + "角" + rmbDigits[fen] + "分")
+	}
+	return builder.String(), nil
+}
+
+// rmbUpperInteger 将正整数转换为大写数字
+func rmbUpperInteger(number int64) string {
+	var groups []int64
+	for ; number > 0; number /= 10000 {
+		groups = append(groups, number%10000)
+	}
+
+	var builder strings.Builder
+	pendingZero := false
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := groups[i]
+		if group == 0 {
+			pendingZero = builder.Len() > 0
+			continue
+		}
+		// 与上一节之间有空缺的位数时补"零"
+		if builder.Len() > 0 && (pendingZero || group < 1000) {
+			builder.WriteString("零")
+		}
+		builder.WriteString(rmbUpperGroup(group))
+		builder.WriteString(rmbGroupUnits[i])
+		pendingZero = false
+	}
+	return builder.String()
+}
+
+// rmbUpperGroup 将0-9999的数字转换为大写，不含前导零
+func rmbUpperGroup(group int64) string {
+	var builder strings.Builder
+	zero := false
+	for pos, divisor := 3, int64(1000); pos >= 0; pos, divisor = pos-1, divisor/10 {
+		digit := group / divisor % 10
+		if digit == 0 {
+			zero = builder.Len() > 0
+			continue
+		}
+		if zero {
+			builder.WriteString("零")
+			zero = false
+		}
+		builder.WriteString(rmbDigits[digit] + rmbUnits[pos])
+	}
+	return builder.String()
+}
+
+// templateDateLayouts 解析字符串日期时尝试的格式
+var templateDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2006年01月02日",
+}
+
+// filterDate 日期格式化：{{date | date "2006年01月02日"}}，使用Go的时间格式
+// 支持 time.Time、常见格式的日期字符串和Unix时间戳（秒）
+func filterDate(value interface{}, args ...interface{}) (interface{}, error) {
+	layout := filterArgString(args, 0, "2006-01-02")
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case *time.Time:
+		if v != nil {
+			return v.Format(layout), nil
+		}
+	case string:
+		for _, candidate := range templateDateLayouts {
+			if t, err := time.ParseInLocation(candidate, strings.TrimSpace(v), time.Local); err == nil {
+				return t.Format(layout), nil
+			}
+		}
+		return nil, fmt.Errorf("无法解析日期 %q", v)
+	default:
+		if isTemplateNumeric(value) {
+			seconds, _ := templateNumber(value)
+			return time.Unix(int64(seconds), 0).Format(layout), nil
+		}
+	}
+	return nil, fmt.Errorf("%v 不是日期", value)
+}
+
+// filterDefault 值不存在、为空字符串或空集合时使用默认值：{{remark | default "无"}}
+func filterDefault(value interface{}, args ...interface{}) (interface{}, error) {
+	v, ok := templateValue(value)
+	if !ok {
+		return filterArgString(args, 0, ""), nil
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if v.Len() == 0 {
+			return filterArgString(args, 0, ""), nil
+		}
+	}
+	return value, nil
+}
+
+// filterTruncate 按字符数截断：{{summary | truncate 20}}，超出时追加省略号（可通过第二个参数指定）
+func filterTruncate(value interface{}, args ...interface{}) (interface{}, error) {
+	length, err := filterArgInt(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("截断长度不能为负数")
+	}
+	suffix := filterArgString(args, 1, "...")
+	runes := []rune(filterString(value))
+	if len(runes) <= length {
+		return string(runes), nil
+	}
+	return string(runes[:length]) + suffix, nil
+}
+
+// filterJoin 连接列表元素：{{tags | join "、"}}，默认分隔符为 ", "
+func filterJoin(value interface{}, args ...interface{}) (interface{}, error) {
+	separator := filterArgString(args, 0, ", ")
+	v, ok := templateValue(value)
+	if !ok {
+		return "", nil
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return filterString(value), nil
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = filterString(v.Index(i).Interface())
+	}
+	return strings.Join(parts, separator), nil
+}
@@ -0,0 +1,176 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRMBUpper 测试人民币大写金额转换
+func TestRMBUpper(t *testing.T) {
+	cases := map[float64]string{
+		0:            "零元整",
+		0.5:          "伍角整",
+		0.06:         "陆分",
+		10:           "壹拾元整",
+		1234.5:       "壹仟贰佰叁拾肆元伍角整",
+		1234.56:      "壹仟贰佰叁拾肆元伍角陆分",
+		1001:         "壹仟零壹元整",
+		100010.05:    "壹拾万零壹拾元零伍分",
+		10005000:     "壹仟万伍仟元整",
+		10000500:     "壹仟万零伍佰元整",
+		100000001:    "壹亿零壹元整",
+		1200000000.1: "壹拾贰亿元壹角整",
+		-45.678:      "负肆拾伍元陆角捌分",
+	}
+	for amount, expected := range cases {
+		got, err := rmbUpper(amount)
+		if err != nil || got != expected {
+			t.Errorf("金额 %v 转换为 %q，期望 %q（错误: %v）", amount, got, expected, err)
+		}
+	}
+	if _, err := rmbUpper(1e16); err == nil {
+		t.Error("超出范围的金额应返回错误")
+	}
+}
+
+// TestTemplateFilters 测试内置过滤器和自定义过滤器
+func TestTemplateFilters(t *testing.T) {
+	engine := NewTemplateEngine()
+	if err := engine.RegisterFilter("mask", func(value interface{}, args ...interface{}) (interface{}, error) {
+		s := fmt.Sprint(value)
+		return strings.Repeat("*", len(s)-4) + s[len(s)-4:], nil
+	}); err != nil {
+		t.Fatalf("注册过滤器失败: %v", err)
+	}
+	if err := engine.RegisterFilter("bad-name", filterUpper); err == nil {
+		t.Error("无效的过滤器名称应返回错误")
+	}
+
+	data := NewTemplateData()
+	data.SetVariable("amount", 1234.5)
+	data.SetVariable("rate", 0.1234)
+	data.SetVariable("name", "wordZero")
+	data.SetVariable("remark", "")
+	data.SetVariable("date", time.Date(2025, 3, 8, 10, 30, 0, 0, time.Local))
+	data.SetVariable("card", "6222020012345678")
+	data.SetVariable("summary", "模板过滤器支持管道语法")
+	data.SetList("tags", []interface{}{"发票", "合同", "报价"})
+	data.SetList("lines", []interface{}{
+		map[string]interface{}{"name": "服务费", "price": 1200},
+		map[string]interface{}{"name": "材料费", "price": 34.5},
+	})
+
+	cases := []struct {
+		template string
+		expected string
+	}{
+		{`{{amount | currency "CNY"}}`, "¥1,234.50"},
+		{`{{amount | currency "USD" 0}}`, "$1,235"},
+		{`{{amount | currency "EUR" 2 "de-DE"}}`, "€1.234,50"},
+		{"{{amount | rmb_upper}}", "壹仟贰佰叁拾肆元伍角整"},
+		{"{{1234567.891 | number 2}}", "1,234,567.89"},
+		{`{{1234567.891 | number 1 "fr"}}`, "1\u00a0234\u00a0567,9"},
+		{"{{rate | percent 1}}", "12.3%"},
+		{"{{rate | percent 500}}", "12.340000000000000%"},
+		{"{{2.5 | number 100}}", "2.500000000000000"},
+		{`{{date | date "2006年01月02日"}}`, "2025年03月08日"},
+		{`{{"2025-03-08" | date "01/02"}}`, "03/08"},
+		{"{{name | upper}}", "WORDZERO"},
+		{"{{tags | len}}", "3"},
+		{`{{tags | join "、"}}`, "发票、合同、报价"},
+		{`{{remark | default "无"}}`, "无"},
+		{`{{missing | default "未填写"}}`, "未填写"},
+		{"{{summary | truncate 5}}", "模板过滤器..."},
+		{"{{name | upper | truncate 4 \"\"}}", "WORD"},
+		{"{{card | mask}}", "************5678"},
+		{"{{#if (tags | len) > 2}}多个标签{{/if}}", "多个标签"},
+		{`{{#each lines}}{{name}}:{{price | currency "CNY"}};{{/each}}`, "服务费:¥1,200.00;材料费:¥34.50;"},
+		{"{{missing | upper}}", "{{missing | upper}}"},
+		{"{{name | unknown}}", "{{name | unknown}}"},
+	}
+
+	for i, c := range cases {
+		name := fmt.Sprintf("filter_%d", i)
+		if _, err := engine.LoadTemplate(name, c.template); err != nil {
+			t.Fatalf("加载模板 %q 失败: %v", c.template, err)
+		}
+		doc, err := engine.RenderToDocument(name, data)
+		if err != nil {
+			t.Fatalf("渲染模板 %q 失败: %v", c.template, err)
+		}
+		if got := renderedText(doc); got != c.expected {
+			t.Errorf("模板 %q 渲染结果为 %q，期望 %q", c.template, got, c.expected)
+		}
+	}
+
+	template, err := engine.LoadTemplate("unknown_filter", "{{name | unknown}}")
+	if err != nil {
+		t.Fatalf("加载模板失败: %v", err)
+	}
+	if err := engine.ValidateTemplate(template); err == nil {
+		t.Error("使用未注册过滤器的模板应校验失败")
+	}
+}
+
+// TestDocumentTemplateFilters 测试文档模板中循环和页眉页脚的过滤器
+func TestDocumentTemplateFilters(t *testing.T) {
+	doc := New()
+	doc.AddParagraph(`合计：{{total | currency "CNY"}}（{{total | rmb_upper}}）`)
+	doc.AddParagraph("{{#each items}}")
+	doc.AddParagraph(`{{@index}}. {{name | upper}} {{price | number 2}}`)
+	doc.AddParagraph("{{/each}}")
+
+	table, err := doc.AddTable(&TableConfig{Rows: 1, Cols: 2, Width: 4000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.SetCellText(0, 0, "{{#each items}}{{name | truncate 3}}")
+	table.SetCellText(0, 1, `{{price | currency "USD"}}{{/each}}`)
+
+	if err := doc.AddHeader(HeaderFooterTypeDefault, `开票日期：{{issued | date "2006年01月02日"}}`); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	if err := doc.AddFooter(HeaderFooterTypeDefault, "{{total | rmb_upper}}"); err != nil {
+		t.Fatalf("添加页脚失败: %v", err)
+	}
+
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("invoice", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+	data := NewTemplateData()
+	data.SetVariable("total", 10000500)
+	data.SetVariable("issued", "2025-12-26")
+	data.SetList("items", []interface{}{
+		map[string]interface{}{"name": "license", "price": 9999.5},
+		map[string]interface{}{"name": "support", "price": 5.25},
+	})
+
+	result, err := engine.RenderTemplateToDocument("invoice", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	text := renderedText(result)
+	for _, expected := range []string{"合计：¥10,000,500.00（壹仟万零伍佰元整）", "0. LICENSE 9,999.50", "1. SUPPORT 5.25"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, text)
+		}
+	}
+
+	rendered := result.Body.GetTables()[0]
+	name, _ := rendered.GetCellText(0, 0)
+	price, _ := rendered.GetCellText(1, 1)
+	if name != "lic..." || price != "$5.25" {
+		t.Errorf("表格循环中的过滤器结果不正确: %q %q", name, price)
+	}
+
+	if header := result.GetHeader(HeaderFooterTypeDefault).GetText(); !strings.Contains(header, "开票日期：2025年12月26日") {
+		t.Errorf("页眉中的过滤器应被执行: %s", header)
+	}
+	if footer := result.GetFooter(HeaderFooterTypeDefault).GetText(); !strings.Contains(footer, "壹仟万零伍佰元整") {
+		t.Errorf("页脚中的过滤器应被执行: %s", footer)
+	}
+}
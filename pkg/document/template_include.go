@@ -0,0 +1,450 @@
+// Package document 模板局部模板与文档包含
+package document
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxTemplateIncludeDepth 局部模板和文档包含的最大嵌套层数
+const maxTemplateIncludeDepth = 10
+
+// templateDeferredEnd 暂存内容标记的结束字符
+const templateDeferredEnd = "\uE001"
+
+// templateDeferred 一次渲染中暂存的内容，生成段落后替换其标记
+//
+// 标记由私用区字符和随机串组成，不是模板标签，模板数据中的文本也无法伪造；
+// 插入的内容已经渲染完成，不会再被解析为模板。
+type templateDeferred struct {
+	prefix string
+	values []interface{}
+	opened map[string]*Document // 本次渲染中已打开的包含文档
+}
+
+// templateInclude 待插入的包含文档路径
+type templateInclude string
+
+// templateRenderedBlock 已渲染的局部模板内容
+type templateRenderedBlock []interface{}
+
+// newTemplateDeferred 创建暂存内容，标记使用新的随机串
+func newTemplateDeferred() (*templateDeferred, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, WrapError("template_deferred", err)
+	}
+	return &templateDeferred{
+		prefix: "\uE000" + hex.EncodeToString(nonce) + ":",
+		opened: make(map[string]*Document),
+	}, nil
+}
+
+// add 暂存内容，返回其标记
+func (d *templateDeferred) add(value interface{}) string {
+	d.values = append(d.values, value)
+	return d.prefix + strconv.Itoa(len(d.values)-1) + templateDeferredEnd
+}
+
+// next 查找文本中的第一个标记，返回其位置和暂存的内容
+func (d *templateDeferred) next(text string) (int, int, interface{}, bool) {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], d.prefix)
+		if i < 0 {
+			return 0, 0, nil, false
+		}
+		start := offset + i
+		rest := text[start+len(d.prefix):]
+		if j := strings.Index(rest, templateDeferredEnd); j > 0 {
+			if index, err := strconv.Atoi(rest[:j]); err == nil && index >= 0 && index < len(d.values) {
+				return start, start + len(d.prefix) + j + len(templateDeferredEnd), d.values[index], true
+			}
+		}
+		offset = start + len(d.prefix)
+	}
+}
+
+// renderPartial 在当前数据上下文中渲染局部模板，无法渲染时保留原始标签
+func (ctx *templateContext) renderPartial(n *templatePartialNode) string {
+	template, err := ctx.engine.GetTemplate(n.name)
+	if err != nil {
+		Warnf("局部模板不存在: %s", n.name)
+		return n.source
+	}
+	child, ok := ctx.enterPartial(n)
+	if !ok {
+		return n.source
+	}
+
+	return ctx.engine.renderTemplateText(ctx.engine.templateSource(template), child)
+}
+
+// enterPartial 创建局部模板的渲染上下文，检测循环引用并计算数据作用域
+func (ctx *templateContext) enterPartial(n *templatePartialNode) (*templateContext, bool) {
+	if len(ctx.partials) >= maxTemplateIncludeDepth {
+		Warnf("局部模板嵌套过深: %s", strings.Join(ctx.partials, " > "))
+		return nil, false
+	}
+	for _, name := range ctx.partials {
+		if name == n.name {
+			Warnf("局部模板循环引用: %s > %s", strings.Join(ctx.partials, " > "), n.name)
+			return nil, false
+		}
+	}
+
+	child := *ctx
+	if n.expr != nil {
+		value, ok := n.expr.eval(ctx)
+		if !ok {
+			return nil, false
+		}
+		child = *ctx.withScope(templateScope{value: value})
+	}
+	child.partials = append(append([]string(nil), ctx.partials...), n.name)
+	return &child, true
+}
+
+// templateSource 返回应用继承和块定义后的模板内容（变量尚未渲染）
+func (te *TemplateEngine) templateSource(template *Template) string {
+	content := template.Content
+	if template.Parent != nil {
+		content = te.applyBlockOverrides(te.templateSource(template.Parent), template)
+	}
+	return te.renderBlocks(content, template, nil)
+}
+
+// validatePartials 检查模板引用的局部模板是否已加载
+func (te *TemplateEngine) validatePartials(nodes []templateNode) error {
+	var err error
+	walkTemplateNodes(nodes, func(node templateNode) {
+		if n, ok := node.(*templatePartialNode); ok && err == nil {
+			if _, lookupErr := te.GetTemplate(n.name); lookupErr != nil {
+				err = NewValidationError("template_partial", n.name, "局部模板未加载")
+			}
+		}
+	})
+	return err
+}
+
+// renderInclude 字符串模板中的包含指令以标记占位，生成段落后插入包含文档的正文
+// 目标文档已确定时（段落中的包含指令）保留原始标签
+func (ctx *templateContext) renderInclude(n *templateIncludeNode) string {
+	if ctx.doc != nil || ctx.deferred == nil {
+		return n.source
+	}
+	file, err := ctx.includeFile(n.expr, n.source)
+	if err != nil {
+		Warnf("文档包含无效: %v", err)
+		return n.source
+	}
+	return ctx.deferred.add(templateInclude(file))
+}
+
+// includeFile 在根数据中计算包含路径，相对路径基于 SetBasePath 设置的路径
+func (ctx *templateContext) includeFile(expr templateExpr, source string) (string, error) {
+	value, _ := expr.eval(ctx.engine.newTemplateContext(ctx.data))
+	file, ok := value.(string)
+	if !ok || file == "" {
+		return "", NewValidationError("template_include", source, "包含路径必须是非空字符串")
+	}
+	if !filepath.IsAbs(file) {
+		ctx.engine.mutex.RLock()
+		file = filepath.Join(ctx.engine.basePath, file)
+		ctx.engine.mutex.RUnlock()
+	}
+	return file, nil
+}
+
+// importInclude 打开包含文档并导入其正文，返回的上下文记录了正在展开的包含文档
+func (ctx *templateContext) importInclude(file string) ([]interface{}, *templateContext, error) {
+	if len(ctx.includes) >= maxTemplateIncludeDepth {
+		return nil, nil, NewValidationError("template_include", file, "文档包含嵌套过深")
+	}
+	for _, included := range ctx.includes {
+		if included == file {
+			return nil, nil, NewValidationError("template_include", file, "文档循环包含")
+		}
+	}
+
+	src, ok := ctx.deferred.opened[file]
+	if !ok {
+		var err error
+		if src, err = Open(file); err != nil {
+			return nil, nil, WrapErrorWithContext("template_include", err, file)
+		}
+		ctx.deferred.opened[file] = src
+	}
+	elements, err := ctx.doc.importBodyElements(src)
+	if err != nil {
+		return nil, nil, WrapErrorWithContext("template_include", err, file)
+	}
+
+	child := *ctx
+	child.includes = append(append([]string(nil), ctx.includes...), file)
+	Debugf("包含文档 %s: %d 个元素", file, len(elements))
+	return elements, &child, nil
+}
+
+// renderDocumentBody 渲染目标文档中的一组正文元素
+// 先在模板源中展开局部模板和包含指令，再渲染循环和变量，最后插入暂存的内容
+func (te *TemplateEngine) renderDocumentBody(elements []interface{}, ctx *templateContext) ([]interface{}, error) {
+	elements, err := te.expandTemplateDirectives(elements, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if elements, err = te.renderBodyElements(elements, ctx); err != nil {
+		return nil, err
+	}
+	return te.insertDeferred(elements, ctx)
+}
+
+// expandTemplateDirectives 展开独占一个段落的 {{> name}} 和 {{#include "file.docx"}} 指令
+//
+// 指令只在模板源中展开，变量替换之前进行，渲染结果不会再作为指令解析。
+// 文档模板的局部模板和包含的文档插入其正文（样式、编号和图片随之导入），由后续的循环和变量替换处理；
+// 带数据作用域的局部模板和字符串模板的局部模板在此按作用域渲染，以标记占位，全部渲染完成后插入。
+// 包含指令的相对路径基于 SetBasePath 设置的路径。
+func (te *TemplateEngine) expandTemplateDirectives(elements []interface{}, ctx *templateContext) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		para, ok := element.(*Paragraph)
+		if !ok {
+			result = append(result, element)
+			continue
+		}
+		spliced, expanded, err := te.expandParagraph(para, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !expanded {
+			result = append(result, element)
+			continue
+		}
+		result = append(result, spliced...)
+	}
+	return result, nil
+}
+
+// expandParagraph 展开只包含一个指令标签的段落
+func (te *TemplateEngine) expandParagraph(para *Paragraph, ctx *templateContext) ([]interface{}, bool, error) {
+	text := ""
+	for _, run := range para.Runs {
+		text += run.Text.Content
+	}
+	text = strings.TrimSpace(text)
+	tags := scanTemplateTags(text)
+	if len(tags) != 1 || tags[0].start != 0 || tags[0].end != len(text) {
+		return nil, false, nil
+	}
+
+	content := strings.TrimSpace(tags[0].inner)
+	if strings.HasPrefix(content, ">") {
+		return te.expandPartial(para, text, content, ctx)
+	}
+	if keyword, rest := splitTemplateKeyword(content); keyword == "#include" {
+		elements, err := te.expandInclude(rest, ctx)
+		return elements, err == nil, err
+	}
+	return nil, false, nil
+}
+
+// expandPartial 将局部模板展开为段落；无法在此处确定数据作用域时留给段落渲染处理
+func (te *TemplateEngine) expandPartial(para *Paragraph, source, content string, ctx *templateContext) ([]interface{}, bool, error) {
+	name, expr, err := parsePartialTag(content)
+	if err != nil {
+		return nil, false, WrapErrorWithContext("template_partial", err, source)
+	}
+	template, err := te.GetTemplate(name)
+	if err != nil {
+		return nil, false, nil
+	}
+	child, ok := ctx.enterPartial(&templatePartialNode{source: source, name: name, expr: expr})
+	if !ok {
+		return nil, false, nil
+	}
+
+	var elements []interface{}
+	if template.BaseDoc != nil {
+		elements, err = ctx.doc.importBodyElements(template.BaseDoc)
+		if err != nil {
+			return nil, false, WrapErrorWithContext("template_partial", err, name)
+		}
+		if elements, err = te.expandTemplateDirectives(elements, child); err != nil {
+			return nil, false, err
+		}
+		if expr == nil {
+			Debugf("展开局部模板 %s: %d 个元素", name, len(elements))
+			return elements, true, nil
+		}
+		if elements, err = te.renderBodyElements(elements, child); err != nil {
+			return nil, false, err
+		}
+	} else {
+		// 字符串模板中的富内容和包含指令以标记占位，与渲染结果一起插入
+		text := *child
+		text.doc = nil
+		rendered := te.renderTemplateText(te.templateSource(template), &text)
+		for _, line := range strings.Split(rendered, "\n") {
+			elements = append(elements, te.paragraphWithText(para, line))
+		}
+	}
+
+	// 已渲染的内容以标记占位，不再参与后续的渲染
+	Debugf("渲染局部模板 %s: %d 个元素", name, len(elements))
+	marker := ctx.deferred.add(templateRenderedBlock(elements))
+	return []interface{}{te.paragraphWithText(para, marker)}, true, nil
+}
+
+// expandInclude 插入包含文档的正文
+func (te *TemplateEngine) expandInclude(source string, ctx *templateContext) ([]interface{}, error) {
+	expr, err := parseTemplateExpr(source)
+	if err != nil {
+		return nil, WrapErrorWithContext("template_include", err, source)
+	}
+	file, err := ctx.includeFile(expr, source)
+	if err != nil {
+		return nil, err
+	}
+	elements, child, err := ctx.importInclude(file)
+	if err != nil {
+		return nil, err
+	}
+	return te.expandTemplateDirectives(elements, child)
+}
+
+// insertDeferred 将正文段落中的暂存内容标记替换为实际内容
+// 只包含一个标记的段落替换为相应的段落和表格，其他标记处插入Run
+func (te *TemplateEngine) insertDeferred(elements []interface{}, ctx *templateContext) ([]interface{}, error) {
+	if ctx.deferred == nil || len(ctx.deferred.values) == 0 {
+		return elements, nil
+	}
+	result := make([]interface{}, 0, len(elements))
+	for _, element := range elements {
+		para, ok := element.(*Paragraph)
+		if !ok {
+			result = append(result, element)
+			continue
+		}
+		text := ""
+		for _, run := range para.Runs {
+			text += run.Text.Content
+		}
+		text = strings.TrimSpace(text)
+		if start, end, value, ok := ctx.deferred.next(text); ok && start == 0 && end == len(text) {
+			block, err := ctx.deferredElements(value)
+			if err != nil {
+				return nil, err
+			}
+			// 已渲染的局部模板中可能还有嵌套的标记
+			if block, err = te.insertDeferred(block, ctx); err != nil {
+				return nil, err
+			}
+			result = append(result, block...)
+			continue
+		}
+		if err := te.insertDeferredRuns(para, ctx); err != nil {
+			return nil, err
+		}
+		result = append(result, para)
+	}
+	return result, nil
+}
+
+// insertDeferredRuns 在段落中的标记处插入暂存内容的Run，内容包含表格时插入其纯文本
+func (te *TemplateEngine) insertDeferredRuns(para *Paragraph, ctx *templateContext) error {
+	runs := make([]Run, 0, len(para.Runs))
+	for i := range para.Runs {
+		run := &para.Runs[i]
+		text := run.Text.Content
+		start, end, value, found := ctx.deferred.next(text)
+		if !found {
+			runs = append(runs, *run)
+			continue
+		}
+		for found {
+			if start > 0 {
+				before := te.cloneRun(run)
+				before.Text.Content = text[:start]
+				runs = append(runs, before)
+			}
+			elements, err := ctx.deferredElements(value)
+			if err != nil {
+				return err
+			}
+			inserted, ok := ctx.elementRuns(elements, run)
+			if !ok {
+				plain := te.cloneRun(run)
+				plain.Text.Content = templateRichText(&Document{Body: &Body{Elements: elements}})
+				inserted = []Run{plain}
+			}
+			runs = append(runs, inserted...)
+			text = text[end:]
+			start, end, value, found = ctx.deferred.next(text)
+		}
+		if text != "" {
+			after := te.cloneRun(run)
+			after.Text.Content = text
+			runs = append(runs, after)
+		}
+	}
+	para.Runs = runs
+	return nil
+}
+
+// deferredElements 返回暂存内容对应的段落和表格
+func (ctx *templateContext) deferredElements(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case templateRenderedBlock:
+		return ctx.doc.copyBodyElements(v)
+	case templateInclude:
+		elements, child, err := ctx.importInclude(string(v))
+		if err != nil {
+			return nil, err
+		}
+		return ctx.engine.renderDocumentBody(elements, child)
+	}
+	return ctx.richElements(value)
+}
+
+// paragraphWithText 以模板段落的格式创建包含指定文本的段落
+func (te *TemplateEngine) paragraphWithText(source *Paragraph, text string) *Paragraph {
+	para := te.cloneParagraph(source)
+	if len(para.Runs) == 0 {
+		para.Runs = []Run{{Text: Text{Content: text}}}
+		return para
+	}
+	para.Runs = para.Runs[:1]
+	para.Runs[0].Text.Content = text
+	return para
+}
+
+// renderBodyElements 渲染一组正文元素中的跨段落循环、段落和表格
+func (te *TemplateEngine) renderBodyElements(elements []interface{}, ctx *templateContext) ([]interface{}, error) {
+	part := &Document{Body: &Body{Elements: elements}}
+	if err := te.processDocumentLevelLoops(part, ctx); err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, 0, len(part.Body.Elements))
+	for _, element := range part.Body.Elements {
+		switch elem := element.(type) {
+		case *Paragraph:
+			// 只包含富内容占位符的段落替换为相应的段落和表格
+			if block, ok := ctx.richBlock(elem); ok {
+				result = append(result, block...)
+				continue
+			}
+			if err := te.replaceVariablesInParagraph(elem, ctx); err != nil {
+				return nil, err
+			}
+		case *Table:
+			if err := te.replaceVariablesInTable(elem, ctx); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, element)
+	}
+	return result, nil
+}
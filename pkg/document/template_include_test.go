@@ -0,0 +1,264 @@
+package document
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestTemplatePartials 测试字符串模板中的局部模板
+func TestTemplatePartials(t *testing.T) {
+	engine := NewTemplateEngine()
+	templates := map[string]string{
+		"signature": "签字：{{name}}\n日期：{{date}}",
+		"address":   "{{city}}{{street}}",
+		"party":     "{{name}}",
+		"main":      "合同\n{{> signature}}\n地址：{{> address customer.address}}\n{{#each parties}}{{> \"party\"}};{{/each}}",
+		"loop_a":    "A{{> loop_b}}",
+		"loop_b":    "B{{> loop_a}}",
+		"missing":   "前{{> nothing}}后",
+	}
+	for name, content := range templates {
+		if _, err := engine.LoadTemplate(name, content); err != nil {
+			t.Fatalf("加载模板 %s 失败: %v", name, err)
+		}
+	}
+
+	data := NewTemplateData()
+	data.SetVariable("name", "张三")
+	data.SetVariable("date", "2025-12-26")
+	data.SetVariable("customer", map[string]interface{}{
+		"address": map[string]interface{}{"city": "杭州", "street": "文一路"},
+	})
+	data.SetList("parties", []interface{}{
+		map[string]interface{}{"name": "甲方"},
+		map[string]interface{}{"name": "乙方"},
+	})
+
+	cases := map[string]string{
+		"main":    "合同\n签字：张三\n日期：2025-12-26\n地址：杭州文一路\n甲方;乙方;",
+		"loop_a":  "AB{{> loop_a}}",
+		"missing": "前{{> nothing}}后",
+	}
+	for name, expected := range cases {
+		doc, err := engine.RenderToDocument(name, data)
+		if err != nil {
+			t.Fatalf("渲染模板 %s 失败: %v", name, err)
+		}
+		if got := renderedText(doc); got != expected {
+			t.Errorf("模板 %s 渲染结果为 %q，期望 %q", name, got, expected)
+		}
+	}
+
+	missing, _ := engine.GetTemplate("missing")
+	if err := engine.ValidateTemplate(missing); err == nil {
+		t.Error("引用未加载局部模板的模板应校验失败")
+	}
+	main, _ := engine.GetTemplate("main")
+	if err := engine.ValidateTemplate(main); err != nil {
+		t.Errorf("模板校验失败: %v", err)
+	}
+}
+
+// TestDocumentTemplateIncludes 测试文档模板中的局部模板和文档包含
+func TestDocumentTemplateIncludes(t *testing.T) {
+	dir := t.TempDir()
+
+	// 标准条款文档：自定义样式、编号列表和图片
+	clause := New()
+	clause.GetStyleManager().CreateCustomStyle("ClauseTitle", "条款标题", style.StyleTypeParagraph, "Normal")
+	clause.AddParagraph("第十七条 保密条款").SetStyle("ClauseTitle")
+	clause.AddNumberedList("{{party}}不得泄露商业秘密", 0, ListTypeDecimal)
+	if _, err := clause.AddImageFromData(createTestImage(20, 20), "seal.png", ImageFormatPNG, 20, 20, nil); err != nil {
+		t.Fatalf("添加图片失败: %v", err)
+	}
+	if err := clause.Save(filepath.Join(dir, "clause_17.docx")); err != nil {
+		t.Fatalf("保存条款文档失败: %v", err)
+	}
+
+	// 包含自身的文档
+	self := New()
+	self.AddParagraph(`{{#include "self.docx"}}`)
+	if err := self.Save(filepath.Join(dir, "self.docx")); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	engine := NewTemplateEngine()
+	engine.SetBasePath(dir)
+
+	signature := New()
+	signature.AddParagraph("签章：{{signer}}")
+	if _, err := engine.LoadTemplateFromDocument("signature", signature); err != nil {
+		t.Fatalf("加载局部模板失败: %v", err)
+	}
+	if _, err := engine.LoadTemplate("note", "备注：{{id}}\n金额：{{total}}"); err != nil {
+		t.Fatalf("加载局部模板失败: %v", err)
+	}
+
+	contract := New()
+	contract.AddParagraph("合同编号：{{order.id}}")
+	item := contract.AddNumberedList("合同条目", 0, ListTypeDecimal)
+	contract.AddParagraph(`{{#include "clause_17.docx"}}`)
+	contract.AddParagraph("{{> signature}}")
+	contract.AddParagraph("{{> note order}}")
+	if _, err := engine.LoadTemplateFromDocument("contract", contract); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+
+	data := NewTemplateData()
+	data.SetVariable("party", "乙方")
+	data.SetVariable("signer", "李四")
+	data.SetVariable("order", map[string]interface{}{"id": "HT-017", "total": 5000})
+
+	result, err := engine.RenderTemplateToDocument("contract", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	text := renderedText(result)
+	for _, expected := range []string{"合同编号：HT-017", "第十七条 保密条款", "乙方不得泄露商业秘密", "签章：李四", "备注：HT-017\n金额：5000"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, text)
+		}
+	}
+	if strings.Contains(text, "{{") {
+		t.Errorf("渲染结果不应包含未处理的指令: %q", text)
+	}
+
+	// 样式、编号和图片随包含的正文导入
+	if result.GetStyleManager().GetStyle("ClauseTitle") == nil {
+		t.Error("包含文档的样式应导入")
+	}
+	definitions := make(map[string]bool)
+	for _, definition := range result.GetListDefinitions() {
+		definitions[definition.NumID] = true
+	}
+	var blip *Blip
+	for _, para := range result.Body.GetParagraphs() {
+		for i := range para.Runs {
+			if b := drawingBlip(para.Runs[i].Drawing); b != nil {
+				blip = b
+			}
+		}
+		if para.Properties == nil || para.Properties.NumberingProperties == nil {
+			continue
+		}
+		numID := para.Properties.NumberingProperties.NumID.Val
+		if !definitions[numID] {
+			t.Errorf("编号 %s 没有对应的编号定义", numID)
+		}
+		if strings.Contains(para.Runs[0].Text.Content, "乙方") && numID == item.Properties.NumberingProperties.NumID.Val {
+			t.Error("包含文档的列表应使用新的编号ID")
+		}
+	}
+	if blip == nil {
+		t.Fatal("包含文档的图片应插入")
+	}
+	found := false
+	for _, rel := range result.documentRelationships.Relationships {
+		if rel.ID == blip.Embed && rel.Type == imageRelationshipType {
+			_, found = result.parts["word/"+rel.Target]
+		}
+	}
+	if !found {
+		t.Errorf("图片关系 %s 应指向本文档中的图片", blip.Embed)
+	}
+
+	output := filepath.Join(dir, "contract.docx")
+	if err := result.Save(output); err != nil {
+		t.Fatalf("保存渲染结果失败: %v", err)
+	}
+	if reopened, err := Open(output); err != nil {
+		t.Errorf("重新打开渲染结果失败: %v", err)
+	} else if !strings.Contains(renderedText(reopened), "乙方不得泄露商业秘密") {
+		t.Error("保存后的文档应包含插入的条款")
+	}
+
+	// 字符串模板同样可以包含文档
+	if _, err := engine.LoadTemplate("appendix", "附件：\n{{#include \"clause_17.docx\"}}"); err != nil {
+		t.Fatalf("加载模板失败: %v", err)
+	}
+	appendix, err := engine.RenderToDocument("appendix", data)
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+	if text := renderedText(appendix); !strings.Contains(text, "乙方不得泄露商业秘密") {
+		t.Errorf("字符串模板应包含文档内容: %q", text)
+	}
+
+	// 缺失的文件和循环包含返回错误
+	for name, content := range map[string]string{"no_file": `{{#include "missing.docx"}}`, "cycle": `{{#include "self.docx"}}`} {
+		if _, err := engine.LoadTemplate(name, content); err != nil {
+			t.Fatalf("加载模板失败: %v", err)
+		}
+		if _, err := engine.RenderToDocument(name, data); err == nil {
+			t.Errorf("模板 %s 应渲染失败", name)
+		}
+	}
+}
+
+// TestTemplateDirectivesInDataStayLiteral 测试变量值中的包含和局部模板指令按普通文本输出
+func TestTemplateDirectivesInDataStayLiteral(t *testing.T) {
+	dir := t.TempDir()
+	secret := New()
+	secret.AddParagraph("机密内容")
+	secretFile := filepath.Join(dir, "secret.docx")
+	if err := secret.Save(secretFile); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+
+	engine := NewTemplateEngine()
+	templates := map[string]string{
+		"hidden":  "局部模板内容",
+		"wrapper": "{{comment}}",
+		"letter":  "备注：{{comment}}\n{{comment}}\n{{partial}}\n{{> wrapper}}",
+	}
+	for name, content := range templates {
+		if _, err := engine.LoadTemplate(name, content); err != nil {
+			t.Fatalf("加载模板 %s 失败: %v", name, err)
+		}
+	}
+	card := New()
+	card.AddParagraph("{{id}}：{{comment}}")
+	if _, err := engine.LoadTemplateFromDocument("card", card); err != nil {
+		t.Fatalf("加载局部模板失败: %v", err)
+	}
+	form := New()
+	form.AddParagraph("{{comment}}")
+	form.AddParagraph("{{partial}}")
+	form.AddParagraph("{{> wrapper}}")
+	form.AddParagraph("{{> card order}}")
+	if _, err := engine.LoadTemplateFromDocument("form", form); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+
+	include := `{{#include "` + secretFile + `"}}`
+	data := NewTemplateData()
+	data.SetVariable("comment", include)
+	data.SetVariable("partial", "{{> hidden}}")
+	data.SetVariable("order", map[string]interface{}{"id": "{{partial}}"})
+
+	letter, err := engine.RenderToDocument("letter", data)
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+	filled, err := engine.RenderTemplateToDocument("form", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	for name, doc := range map[string]*Document{"letter": letter, "form": filled} {
+		text := renderedText(doc)
+		if strings.Contains(text, "机密内容") || strings.Contains(text, "局部模板内容") {
+			t.Errorf("%s: 变量值中的指令不应展开: %q", name, text)
+		}
+		if strings.Count(text, include) < 2 || !strings.Contains(text, "{{> hidden}}") {
+			t.Errorf("%s: 变量值应按原文输出: %q", name, text)
+		}
+	}
+	if text := renderedText(filled); !strings.Contains(text, "{{partial}}：") {
+		t.Errorf("带数据作用域的局部模板不应再次渲染: %q", text)
+	}
+}
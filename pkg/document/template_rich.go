@@ -0,0 +1,199 @@
+// Package document 模板富内容变量值
+package document
+
+import (
+	"strings"
+	"sync"
+)
+
+// TemplateMarkdown 按Markdown渲染的模板变量值
+type TemplateMarkdown string
+
+// TemplateMarkdownRenderer 将Markdown文本转换为文档的函数
+type TemplateMarkdownRenderer func(content string) (*Document, error)
+
+var (
+	templateMarkdownMutex    sync.RWMutex
+	templateMarkdownRenderer TemplateMarkdownRenderer
+)
+
+// RegisterTemplateMarkdownRenderer 注册模板中Markdown变量值的渲染函数
+// 导入 markdown 包时会自动注册其默认转换器
+func RegisterTemplateMarkdownRenderer(renderer TemplateMarkdownRenderer) {
+	templateMarkdownMutex.Lock()
+	defer templateMarkdownMutex.Unlock()
+	templateMarkdownRenderer = renderer
+}
+
+// SetMarkdown 设置按Markdown渲染的变量
+func (td *TemplateData) SetMarkdown(name, content string) {
+	td.Variables[name] = TemplateMarkdown(content)
+}
+
+// isTemplateRichValue 判断变量值是否为富内容（表格、段落、文档片段或Markdown）
+//
+// 富内容独占一个段落时替换整个段落，与其他文本同在一个段落时插入带格式的Run；
+// 只能输出文本的位置（页眉页脚、跨段落循环、表格循环行）使用其纯文本。
+func isTemplateRichValue(value interface{}) bool {
+	switch value.(type) {
+	case *Table, *Paragraph, []*Paragraph, *Document, TemplateMarkdown:
+		return true
+	}
+	return false
+}
+
+// richElements 将富内容转换为可插入目标文档的段落和表格
+func (ctx *templateContext) richElements(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case *Table:
+		return ctx.doc.importElements(v.doc, []interface{}{v})
+	case *Paragraph:
+		return ctx.doc.importElements(v.doc, []interface{}{v})
+	case []*Paragraph:
+		// 相邻的同一文档的段落一起导入，编号定义只导入一次
+		elements := make([]interface{}, 0, len(v))
+		for start := 0; start < len(v); {
+			end := start + 1
+			for end < len(v) && v[end].doc == v[start].doc {
+				end++
+			}
+			group := make([]interface{}, 0, end-start)
+			for _, para := range v[start:end] {
+				group = append(group, para)
+			}
+			imported, err := ctx.doc.importElements(v[start].doc, group)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, imported...)
+			start = end
+		}
+		return elements, nil
+	case *Document:
+		return ctx.doc.importBodyElements(v)
+	case TemplateMarkdown:
+		src, err := renderTemplateMarkdown(string(v))
+		if err != nil {
+			return nil, err
+		}
+		return ctx.doc.importBodyElements(src)
+	}
+	return nil, NewValidationError("template_value", "", "不支持的富内容类型")
+}
+
+// renderTemplateMarkdown 使用注册的渲染函数转换Markdown文本
+func renderTemplateMarkdown(content string) (*Document, error) {
+	templateMarkdownMutex.RLock()
+	renderer := templateMarkdownRenderer
+	templateMarkdownMutex.RUnlock()
+	if renderer == nil {
+		return nil, NewValidationError("template_markdown", "", "未注册Markdown渲染函数，请导入markdown包")
+	}
+	doc, err := renderer(content)
+	if err != nil {
+		return nil, WrapError("template_markdown", err)
+	}
+	return doc, nil
+}
+
+// richBlock 段落只包含一个富内容占位符时，返回替换该段落的元素
+func (ctx *templateContext) richBlock(para *Paragraph) ([]interface{}, bool) {
+	if ctx.doc == nil {
+		return nil, false
+	}
+	text := ""
+	for _, run := range para.Runs {
+		text += run.Text.Content
+	}
+	nodes, err := parseTemplateNodes(strings.TrimSpace(text), false)
+	if err != nil || len(nodes) != 1 {
+		return nil, false
+	}
+	output, ok := nodes[0].(*templateOutputNode)
+	if !ok {
+		return nil, false
+	}
+	value, ok := output.expr.eval(ctx)
+	if !ok || !isTemplateRichValue(value) {
+		return nil, false
+	}
+	elements, err := ctx.richElements(value)
+	if err != nil {
+		Warnf("富内容变量 %s 插入失败: %v", output.source, err)
+		return nil, false
+	}
+	return elements, true
+}
+
+// richRuns 将富内容转换为段落内的Run，多个段落之间以换行符分隔
+// 没有格式的Run沿用占位符的格式；内容包含表格时返回false
+func (ctx *templateContext) richRuns(value interface{}, placeholder *Run) ([]Run, bool) {
+	elements, err := ctx.richElements(value)
+	if err != nil {
+		Warnf("富内容变量插入失败: %v", err)
+		return nil, false
+	}
+	return ctx.elementRuns(elements, placeholder)
+}
+
+// elementRuns 将段落转换为Run，规则与 richRuns 相同
+func (ctx *templateContext) elementRuns(elements []interface{}, placeholder *Run) ([]Run, bool) {
+	var runs []Run
+	for i, element := range elements {
+		para, ok := element.(*Paragraph)
+		if !ok {
+			return nil, false
+		}
+		if i > 0 {
+			runs = append(runs, Run{Break: &Break{}})
+		}
+		for _, run := range para.Runs {
+			if run.Properties == nil && placeholder != nil {
+				run.Properties = ctx.engine.cloneRun(placeholder).Properties
+			}
+			runs = append(runs, run)
+		}
+	}
+	return runs, true
+}
+
+// templateRichText 返回富内容的纯文本，段落之间以换行符分隔，单元格之间以制表符分隔
+func templateRichText(value interface{}) string {
+	var elements []interface{}
+	switch v := value.(type) {
+	case *Table:
+		elements = []interface{}{v}
+	case *Paragraph:
+		elements = []interface{}{v}
+	case []*Paragraph:
+		for _, para := range v {
+			elements = append(elements, para)
+		}
+	case *Document:
+		elements = v.Body.Elements
+	case TemplateMarkdown:
+		return string(v)
+	}
+
+	var lines []string
+	for _, element := range elements {
+		switch elem := element.(type) {
+		case *Paragraph:
+			text := ""
+			for _, run := range elem.Runs {
+				text += run.Text.Content
+			}
+			lines = append(lines, text)
+		case *Table:
+			for i := range elem.Rows {
+				cells := make([]string, 0, len(elem.Rows[i].Cells))
+				for j := range elem.Rows[i].Cells {
+					text, _ := elem.GetCellText(i, j)
+					cells = append(cells, text)
+				}
+				lines = append(lines, strings.Join(cells, "\t"))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,257 @@
+package document
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// newTemplateRichData 创建包含表格、段落和文档片段的模板数据
+func newTemplateRichData(t *testing.T) *TemplateData {
+	source := New()
+	summary := []*Paragraph{
+		source.AddParagraph("第一段摘要"),
+		source.AddFormattedParagraph("第二段重点", &TextFormat{Bold: true}),
+	}
+	highlight := source.AddParagraph("增长")
+	highlight.AddFormattedText("12%", &TextFormat{Bold: true})
+
+	table, err := source.CreateTable(&TableConfig{Rows: 2, Cols: 2, Width: 4000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.SetCellText(0, 0, "指标")
+	table.SetCellText(0, 1, "数值")
+	table.SetCellText(1, 0, "收入")
+	table.SetCellText(1, 1, "100")
+
+	fragment := New()
+	fragment.GetStyleManager().CreateCustomStyle("Narrative", "分析正文", style.StyleTypeParagraph, "Normal")
+	fragment.AddParagraph("分析师观点").SetStyle("Narrative")
+
+	data := NewTemplateData()
+	data.SetVariable("summary", summary)
+	data.SetVariable("highlight", highlight)
+	data.SetVariable("metrics", table)
+	data.SetVariable("analysis", fragment)
+	return data
+}
+
+// runIsBold 判断Run是否为粗体
+func runIsBold(run Run) bool {
+	return run.Properties != nil && run.Properties.Bold != nil
+}
+
+// TestDocumentTemplateRichValues 测试文档模板中的富内容变量值
+func TestDocumentTemplateRichValues(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("报告")
+	doc.AddParagraph("{{summary}}")
+	doc.AddParagraph("本季度{{highlight}}，详见下表")
+	doc.AddParagraph("{{metrics}}")
+	doc.AddParagraph("  {{analysis}}  ")
+
+	table, err := doc.AddTable(&TableConfig{Rows: 1, Cols: 2, Width: 4000})
+	if err != nil {
+		t.Fatalf("创建表格失败: %v", err)
+	}
+	table.SetCellText(0, 0, "{{summary}}")
+	table.SetCellText(0, 1, "摘要：{{highlight}}")
+
+	if err := doc.AddHeader(HeaderFooterTypeDefault, "{{highlight}}"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("report", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+	result, err := engine.RenderTemplateToDocument("report", newTemplateRichData(t))
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	paragraphs := result.Body.GetParagraphs()
+	texts := strings.Split(renderedText(result), "\n")
+	expected := []string{"报告", "第一段摘要", "第二段重点", "本季度增长12%，详见下表", "分析师观点"}
+	if len(texts) < len(expected) {
+		t.Fatalf("渲染结果段落不足: %q", texts)
+	}
+	for i, text := range expected {
+		if texts[i] != text {
+			t.Errorf("第 %d 段为 %q，期望 %q", i, texts[i], text)
+		}
+	}
+
+	// 段落列表替换占位段落并保留格式，行内占位符插入带格式的Run
+	if runs := paragraphs[2].Runs; len(runs) != 1 || !runIsBold(runs[0]) {
+		t.Errorf("段落列表应保留粗体格式: %+v", runs)
+	}
+	bold := false
+	for _, run := range paragraphs[3].Runs {
+		if run.Text.Content == "12%" {
+			bold = runIsBold(run)
+		}
+	}
+	if !bold {
+		t.Error("行内富内容应保留粗体Run")
+	}
+
+	// 表格和文档片段作为块内容插入，文档片段的样式随之导入
+	tables := result.Body.GetTables()
+	if len(tables) != 2 {
+		t.Fatalf("应有2个表格，实际 %d 个", len(tables))
+	}
+	if text, _ := tables[0].GetCellText(1, 0); text != "收入" {
+		t.Errorf("表格变量应插入表格: %q", text)
+	}
+	if paragraphs[4].Properties == nil || paragraphs[4].Properties.ParagraphStyle == nil ||
+		result.GetStyleManager().GetStyle(paragraphs[4].Properties.ParagraphStyle.Val) == nil {
+		t.Error("文档片段的样式应导入")
+	}
+
+	// 单元格中的块内容替换段落，行内内容插入Run
+	cell := tables[1].Rows[0].Cells
+	if len(cell[0].Paragraphs) != 2 {
+		t.Errorf("单元格中的段落列表应生成2个段落，实际 %d 个", len(cell[0].Paragraphs))
+	}
+	if text, _ := tables[1].GetCellText(0, 1); text != "摘要：增长12%" {
+		t.Errorf("单元格中的行内富内容不正确: %q", text)
+	}
+
+	// 页眉与正文使用相同的渲染方式
+	if header := result.GetHeader(HeaderFooterTypeDefault).GetText(); !strings.Contains(header, "增长12%") {
+		t.Errorf("页眉中的富内容应被插入: %s", header)
+	}
+}
+
+// TestDocumentTemplateRichValuesFromOtherDocument 测试来自其他文档的段落和表格连同图片和编号一起导入
+func TestDocumentTemplateRichValuesFromOtherDocument(t *testing.T) {
+	source := New()
+	if _, err := source.AddImageFromData(createTestImage(20, 20), "logo.png", ImageFormatPNG, 20, 20, nil); err != nil {
+		t.Fatalf("添加图片失败: %v", err)
+	}
+	logo := source.Body.GetParagraphs()[0]
+	items := []*Paragraph{
+		source.AddNumberedList("第一项", 0, ListTypeDecimal),
+		source.AddNumberedList("第二项", 0, ListTypeDecimal),
+	}
+
+	doc := New()
+	doc.AddParagraph("{{logo}}")
+	doc.AddParagraph("{{items}}")
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("imported", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+	data := NewTemplateData()
+	data.SetVariable("logo", logo)
+	data.SetVariable("items", items)
+	result, err := engine.RenderTemplateToDocument("imported", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	paragraphs := result.Body.GetParagraphs()
+	if len(paragraphs) != 3 {
+		t.Fatalf("应有3个段落，实际 %d 个", len(paragraphs))
+	}
+	blip := drawingBlip(paragraphs[0].Runs[0].Drawing)
+	if blip == nil {
+		t.Fatal("图片段落应被插入")
+	}
+	imported := false
+	for _, rel := range result.documentRelationships.Relationships {
+		if rel.ID == blip.Embed && rel.Type == imageRelationshipType && result.parts["word/"+rel.Target] != nil {
+			imported = true
+		}
+	}
+	if !imported {
+		t.Errorf("图片应复制到目标文档并使用目标文档的关系ID: %s", blip.Embed)
+	}
+
+	definitions := make(map[string]bool)
+	for _, definition := range result.GetListDefinitions() {
+		definitions[definition.NumID] = true
+	}
+	for _, para := range paragraphs[1:] {
+		numPr := para.Properties.NumberingProperties
+		if numPr == nil || numPr.NumID == nil || !definitions[numPr.NumID.Val] {
+			t.Errorf("列表段落的编号应指向导入的编号定义: %+v", numPr)
+		}
+	}
+}
+
+// TestTemplateRichValues 测试字符串模板中的富内容变量值
+func TestTemplateRichValues(t *testing.T) {
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplate("report", "报告\n{{summary}}\n{{#each sections}}{{title}}：{{body}}\n{{/each}}{{metrics}}"); err != nil {
+		t.Fatalf("加载模板失败: %v", err)
+	}
+
+	data := newTemplateRichData(t)
+	data.SetList("sections", []interface{}{
+		map[string]interface{}{"title": "结论", "body": data.Variables["highlight"]},
+	})
+	result, err := engine.RenderToDocument("report", data)
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+
+	text := renderedText(result)
+	for _, expected := range []string{"报告\n第一段摘要\n第二段重点\n", "结论：增长12%"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, text)
+		}
+	}
+	if strings.Contains(text, "{{") {
+		t.Errorf("渲染结果不应包含未处理的标签: %q", text)
+	}
+	if tables := result.Body.GetTables(); len(tables) != 1 {
+		t.Errorf("表格变量应插入表格，实际 %d 个", len(tables))
+	}
+}
+
+// TestTemplateRichValuesKeepLiteralBraces 测试插入富内容时不会再次解析其他变量值中的标签
+func TestTemplateRichValuesKeepLiteralBraces(t *testing.T) {
+	engine := NewTemplateEngine()
+	if _, err := engine.LoadTemplate("report", "{{summary}}\n备注：{{note}}，{{highlight}}"); err != nil {
+		t.Fatalf("加载模板失败: %v", err)
+	}
+	if _, err := engine.LoadTemplate("remark", "{{note}}\n{{metrics}}"); err != nil {
+		t.Fatalf("加载局部模板失败: %v", err)
+	}
+	form := New()
+	form.AddParagraph("{{> remark}}")
+	if _, err := engine.LoadTemplateFromDocument("form", form); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+
+	literal := "use {{#if x}}braces{{/if}} literally"
+	data := newTemplateRichData(t)
+	data.SetVariable("note", literal)
+	data.SetCondition("x", true)
+
+	report, err := engine.RenderToDocument("report", data)
+	if err != nil {
+		t.Fatalf("渲染模板失败: %v", err)
+	}
+	text := renderedText(report)
+	for _, expected := range []string{"第一段摘要\n第二段重点", "备注：" + literal + "，增长12%"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, text)
+		}
+	}
+
+	filled, err := engine.RenderTemplateToDocument("form", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+	if text := renderedText(filled); !strings.Contains(text, literal) {
+		t.Errorf("局部模板中的变量值应按原文输出: %q", text)
+	}
+	if tables := filled.Body.GetTables(); len(tables) != 1 {
+		t.Errorf("局部模板中的表格变量应插入表格，实际 %d 个", len(tables))
+	}
+}
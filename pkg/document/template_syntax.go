@@ -0,0 +1,501 @@
+// Package document 模板块语法解析与渲染
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 文档模板中定位循环标记的正则表达式，循环表达式可以是任意变量路径
+var (
+	templateEachStartPattern = regexp.MustCompile(`\{\{#each\s+([^{}]+?)\s*\}\}`)
+	templateEachEndPattern   = regexp.MustCompile(`\{\{/each\}\}`)
+)
+
+// templateNode 模板语法节点，start 和 end 为节点在源文本中的位置
+type templateNode interface {
+	span() (int, int)
+}
+
+// templateSpan 节点在源文本中的范围
+type templateSpan struct {
+	start, end int
+}
+
+func (s templateSpan) span() (int, int) {
+	return s.start, s.end
+}
+
+// templateTextNode 原样输出的文本，包括无法识别的标签（如 {{#image}}、{{#block}}）
+type templateTextNode struct {
+	templateSpan
+	text string
+}
+
+// templateOutputNode 输出表达式的值：{{expr}}
+type templateOutputNode struct {
+	templateSpan
+	source string
+	expr   templateExpr
+}
+
+// templateBranch 条件分支
+type templateBranch struct {
+	expr   templateExpr
+	negate bool
+	body   []templateNode
+}
+
+// templateIfNode 条件块：{{#if}}...{{else if}}...{{else}}...{{/if}} 和 {{#unless}}...{{/unless}}
+type templateIfNode struct {
+	templateSpan
+	branches []templateBranch
+	elseBody []templateNode
+}
+
+// templateEachNode 循环块：{{#each expr}}...{{else}}...{{/each}}
+type templateEachNode struct {
+	templateSpan
+	expr     templateExpr
+	body     []templateNode
+	elseBody []templateNode
+}
+
+// templateWithNode 作用域块：{{#with expr}}...{{else}}...{{/with}}
+type templateWithNode struct {
+	templateSpan
+	expr     templateExpr
+	body     []templateNode
+	elseBody []templateNode
+}
+
+// templatePartialNode 局部模板：{{> name}} 或 {{> name expr}}
+type templatePartialNode struct {
+	templateSpan
+	source string
+	name   string
+	expr   templateExpr // 局部模板的数据作用域，可以为nil
+}
+
+// templateIncludeNode 文档包含：{{#include "file.docx"}}
+type templateIncludeNode struct {
+	templateSpan
+	source string
+	expr   templateExpr
+}
+
+// templateTag 源文本中的 {{...}} 标签
+type templateTag struct {
+	start, end int
+	inner      string
+}
+
+// scanTemplateTags 查找源文本中的全部标签
+func scanTemplateTags(src string) []templateTag {
+	var tags []templateTag
+	pos := 0
+	for {
+		open := strings.Index(src[pos:], "{{")
+		if open < 0 {
+			return tags
+		}
+		open += pos
+		closeIndex := strings.Index(src[open+2:], "}}")
+		if closeIndex < 0 {
+			return tags
+		}
+		end := open + 2 + closeIndex + 2
+		tags = append(tags, templateTag{start: open, end: end, inner: src[open+2 : end-2]})
+		pos = end
+	}
+}
+
+// templateParser 模板块语法解析器
+type templateParser struct {
+	src    string
+	tags   []templateTag
+	next   int // 下一个待处理的标签
+	cursor int // 已处理到的源文本位置
+	strict bool
+}
+
+// parseTemplateNodes 将模板文本解析为语法节点
+// strict 为 false 时无法解析的表达式和未闭合的块按普通文本处理；为 true 时返回错误
+func parseTemplateNodes(src string, strict bool) ([]templateNode, error) {
+	p := &templateParser{src: src, tags: scanTemplateTags(src), strict: strict}
+	nodes, _, err := p.parseBody(nil)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// tagContent 返回标签去除空白后的内容
+func (p *templateParser) tagContent(tag templateTag) string {
+	return strings.TrimSpace(tag.inner)
+}
+
+// appendTemplateText 追加文本节点，与前一个文本节点相邻时合并
+func appendTemplateText(nodes []templateNode, src string, start, end int) []templateNode {
+	if start >= end {
+		return nodes
+	}
+	if len(nodes) > 0 {
+		if last, ok := nodes[len(nodes)-1].(*templateTextNode); ok && last.end == start {
+			last.end = end
+			last.text = src[last.start:end]
+			return nodes
+		}
+	}
+	return append(nodes, &templateTextNode{templateSpan: templateSpan{start, end}, text: src[start:end]})
+}
+
+// parseBody 解析节点直到 stop 接受的标签（返回该标签）或文本结束（返回nil）
+func (p *templateParser) parseBody(stop func(content string) bool) ([]templateNode, *templateTag, error) {
+	var nodes []templateNode
+	for p.next < len(p.tags) {
+		tag := p.tags[p.next]
+		content := p.tagContent(tag)
+		if stop != nil && stop(content) {
+			nodes = appendTemplateText(nodes, p.src, p.cursor, tag.start)
+			p.next++
+			p.cursor = tag.end
+			return nodes, &tag, nil
+		}
+
+		nodes = appendTemplateText(nodes, p.src, p.cursor, tag.start)
+		p.cursor = tag.end
+		savedNext := p.next
+		p.next++
+
+		node, err := p.parseTag(tag, content)
+		if err != nil {
+			if p.strict {
+				return nil, nil, err
+			}
+			// 宽松模式下将标签作为普通文本，从标签之后继续解析
+			node = nil
+			p.next = savedNext + 1
+		}
+		if node == nil {
+			nodes = appendTemplateText(nodes, p.src, tag.start, tag.end)
+			p.cursor = tag.end
+			continue
+		}
+		nodes = append(nodes, node)
+		_, p.cursor = node.span()
+	}
+	nodes = appendTemplateText(nodes, p.src, p.cursor, len(p.src))
+	p.cursor = len(p.src)
+	return nodes, nil, nil
+}
+
+// parseTag 解析一个标签，返回nil表示按普通文本处理
+func (p *templateParser) parseTag(tag templateTag, content string) (templateNode, error) {
+	keyword, rest := splitTemplateKeyword(content)
+	switch keyword {
+	case "#if", "#unless":
+		return p.parseIf(tag, keyword == "#unless", rest)
+	case "#each", "#with":
+		expr, err := parseTemplateExpr(rest)
+		if err != nil {
+			return nil, err
+		}
+		closing := "/" + keyword[1:]
+		body, elseBody, end, err := p.parseBlockBodies(tag, closing)
+		if err != nil {
+			return nil, err
+		}
+		if keyword == "#each" {
+			return &templateEachNode{templateSpan: templateSpan{tag.start, end}, expr: expr, body: body, elseBody: elseBody}, nil
+		}
+		return &templateWithNode{templateSpan: templateSpan{tag.start, end}, expr: expr, body: body, elseBody: elseBody}, nil
+	case "#include":
+		expr, err := parseTemplateExpr(rest)
+		if err != nil {
+			return nil, err
+		}
+		return &templateIncludeNode{templateSpan: templateSpan{tag.start, tag.end}, source: p.src[tag.start:tag.end], expr: expr}, nil
+	}
+
+	if strings.HasPrefix(content, ">") {
+		name, expr, err := parsePartialTag(content)
+		if err != nil {
+			return nil, err
+		}
+		return &templatePartialNode{templateSpan: templateSpan{tag.start, tag.end}, source: p.src[tag.start:tag.end], name: name, expr: expr}, nil
+	}
+	if p.strict && (keyword == "else" || content == "/if" || content == "/unless" || content == "/each" || content == "/with") {
+		return nil, NewValidationError("template", content, "多余的块结束标记")
+	}
+	// 其他块标签、结束标签、注释和 else 不是输出表达式
+	if content == "" || strings.HasPrefix(content, "#") || strings.HasPrefix(content, "/") ||
+		strings.HasPrefix(content, ">") || strings.HasPrefix(content, "!") || keyword == "else" || keyword == "extends" {
+		return nil, nil
+	}
+	expr, err := parseTemplateExpr(content)
+	if err != nil {
+		return nil, err
+	}
+	return &templateOutputNode{templateSpan: templateSpan{tag.start, tag.end}, source: p.src[tag.start:tag.end], expr: expr}, nil
+}
+
+// parseIf 解析条件块及其 else if / else 分支
+func (p *templateParser) parseIf(open templateTag, negate bool, condition string) (templateNode, error) {
+	closing := "/if"
+	if negate {
+		closing = "/unless"
+	}
+	node := &templateIfNode{}
+	for {
+		expr, err := parseTemplateExpr(condition)
+		if err != nil {
+			return nil, err
+		}
+		body, terminator, err := p.parseBody(func(content string) bool {
+			keyword, _ := splitTemplateKeyword(content)
+			return content == closing || keyword == "else"
+		})
+		if err != nil {
+			return nil, err
+		}
+		if terminator == nil {
+			return nil, NewValidationError("template", open.inner, fmt.Sprintf("缺少 {{%s}}", closing))
+		}
+		node.branches = append(node.branches, templateBranch{expr: expr, negate: negate, body: body})
+
+		content := p.tagContent(*terminator)
+		if content == closing {
+			node.templateSpan = templateSpan{open.start, terminator.end}
+			return node, nil
+		}
+		_, rest := splitTemplateKeyword(content)
+		if next, condition2 := splitTemplateKeyword(rest); next == "if" && condition2 != "" {
+			// {{else if ...}} 开始新的分支
+			condition, negate = condition2, false
+			continue
+		}
+		elseBody, terminator, err := p.parseBody(func(content string) bool {
+			return content == closing
+		})
+		if err != nil {
+			return nil, err
+		}
+		if terminator == nil {
+			return nil, NewValidationError("template", open.inner, fmt.Sprintf("缺少 {{%s}}", closing))
+		}
+		node.elseBody = elseBody
+		node.templateSpan = templateSpan{open.start, terminator.end}
+		return node, nil
+	}
+}
+
+// parseBlockBodies 解析循环块和作用域块的主体及 else 部分，返回块结束位置
+func (p *templateParser) parseBlockBodies(open templateTag, closing string) ([]templateNode, []templateNode, int, error) {
+	body, terminator, err := p.parseBody(func(content string) bool {
+		return content == closing || content == "else"
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if terminator == nil {
+		return nil, nil, 0, NewValidationError("template", open.inner, fmt.Sprintf("缺少 {{%s}}", closing))
+	}
+	if p.tagContent(*terminator) == closing {
+		return body, nil, terminator.end, nil
+	}
+	elseBody, terminator, err := p.parseBody(func(content string) bool {
+		return content == closing
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if terminator == nil {
+		return nil, nil, 0, NewValidationError("template", open.inner, fmt.Sprintf("缺少 {{%s}}", closing))
+	}
+	return body, elseBody, terminator.end, nil
+}
+
+// parsePartialTag 解析局部模板标签的名称和可选的数据作用域表达式
+// 名称可以加引号：{{> "clause-17"}}
+func parsePartialTag(content string) (string, templateExpr, error) {
+	name, rest := splitTemplateKeyword(strings.TrimSpace(strings.TrimPrefix(content, ">")))
+	if len(name) >= 2 && (name[0] == '"' || name[0] == '\'') && name[len(name)-1] == name[0] {
+		name = name[1 : len(name)-1]
+	}
+	if name == "" {
+		return "", nil, NewValidationError("template_partial", content, "缺少局部模板名称")
+	}
+	if rest == "" {
+		return name, nil, nil
+	}
+	expr, err := parseTemplateExpr(rest)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, expr, nil
+}
+
+// splitTemplateKeyword 将标签内容拆分为首个单词和其余部分
+func splitTemplateKeyword(content string) (string, string) {
+	content = strings.TrimSpace(content)
+	if index := strings.IndexFunc(content, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }); index >= 0 {
+		return content[:index], strings.TrimSpace(content[index:])
+	}
+	return content, ""
+}
+
+// templateReferences 模板文本引用的数据名称
+type templateReferences struct {
+	variables  []string // 输出表达式、复杂条件和作用域块引用的变量
+	conditions []string // 以单个名称作为条件的 #if / #unless / else if
+	lists      []string // 以单个名称作为循环对象的 #each
+}
+
+// collectTemplateReferences 按标签收集模板引用的数据名称
+// 逐个标签分析而不要求块完整闭合，适用于块跨越多个段落的文档模板
+func collectTemplateReferences(text string) templateReferences {
+	var refs templateReferences
+	for _, tag := range scanTemplateTags(text) {
+		content := strings.TrimSpace(tag.inner)
+		keyword, rest := splitTemplateKeyword(content)
+		if keyword == "else" {
+			keyword, rest = splitTemplateKeyword(rest)
+			if keyword != "if" {
+				continue
+			}
+			keyword = "#if"
+		}
+
+		source := content
+		switch keyword {
+		case "#if", "#unless", "#each", "#with":
+			source = rest
+		default:
+			if content == "" || strings.ContainsAny(content[:1], "#/>!") || keyword == "extends" {
+				continue
+			}
+		}
+		expr, err := parseTemplateExpr(source)
+		if err != nil {
+			continue
+		}
+
+		path, simple := expr.(*pathExpr)
+		simple = simple && len(path.segments) == 0 && path.root != "this" && !strings.HasPrefix(path.root, "@")
+		switch {
+		case simple && (keyword == "#if" || keyword == "#unless"):
+			refs.conditions = append(refs.conditions, path.root)
+		case simple && keyword == "#each":
+			refs.lists = append(refs.lists, path.root)
+		default:
+			refs.variables = append(refs.variables, templateExprRoots(expr)...)
+		}
+	}
+	return refs
+}
+
+// walkTemplateNodes 按顺序访问节点及其全部子节点
+func walkTemplateNodes(nodes []templateNode, visit func(templateNode)) {
+	for _, node := range nodes {
+		visit(node)
+		switch n := node.(type) {
+		case *templateIfNode:
+			for _, branch := range n.branches {
+				walkTemplateNodes(branch.body, visit)
+			}
+			walkTemplateNodes(n.elseBody, visit)
+		case *templateEachNode:
+			walkTemplateNodes(n.body, visit)
+			walkTemplateNodes(n.elseBody, visit)
+		case *templateWithNode:
+			walkTemplateNodes(n.body, visit)
+			walkTemplateNodes(n.elseBody, visit)
+		}
+	}
+}
+
+// renderTemplateText 渲染模板文本中的变量、条件、循环和作用域块
+func (te *TemplateEngine) renderTemplateText(content string, ctx *templateContext) string {
+	if !strings.Contains(content, "{{") {
+		return content
+	}
+	nodes, err := parseTemplateNodes(content, false)
+	if err != nil {
+		return content
+	}
+	return ctx.render(nodes)
+}
+
+// render 渲染节点列表
+func (ctx *templateContext) render(nodes []templateNode) string {
+	var result strings.Builder
+	for _, node := range nodes {
+		result.WriteString(ctx.renderNode(node))
+	}
+	return result.String()
+}
+
+// renderNode 渲染单个节点
+func (ctx *templateContext) renderNode(node templateNode) string {
+	switch n := node.(type) {
+	case *templateTextNode:
+		return n.text
+	case *templateOutputNode:
+		value, ok := n.expr.eval(ctx)
+		if !ok {
+			// 变量不存在时保留原始占位符
+			return n.source
+		}
+		var text string
+		switch {
+		case isTemplateRichValue(value) && ctx.doc == nil && ctx.deferred != nil:
+			// 字符串模板中暂存富内容，输出标记，在生成段落后插入
+			return ctx.deferred.add(value)
+		case isTemplateRichValue(value):
+			text = templateRichText(value)
+		default:
+			text = ctx.engine.interfaceToString(value)
+		}
+		return text
+	case *templateIfNode:
+		for _, branch := range n.branches {
+			if templateTruthy(ctx.evalCondition(branch.expr)) != branch.negate {
+				return ctx.render(branch.body)
+			}
+		}
+		return ctx.render(n.elseBody)
+	case *templateEachNode:
+		value, _ := n.expr.eval(ctx)
+		items := templateItems(value)
+		if len(items) == 0 {
+			return ctx.render(n.elseBody)
+		}
+		var result strings.Builder
+		for _, item := range items {
+			result.WriteString(ctx.withScope(item).render(n.body))
+		}
+		return result.String()
+	case *templateWithNode:
+		value, _ := n.expr.eval(ctx)
+		if !templateTruthy(value) {
+			return ctx.render(n.elseBody)
+		}
+		return ctx.withScope(templateScope{value: value}).render(n.body)
+	case *templatePartialNode:
+		return ctx.renderPartial(n)
+	case *templateIncludeNode:
+		return ctx.renderInclude(n)
+	}
+	return ""
+}
+
+// templateEachExpr 返回文本中第一个循环标记的表达式，如 "{{#each items}}" 中的 "items"
+func templateEachExpr(text string) (string, bool) {
+	matches := templateEachStartPattern.FindStringSubmatch(text)
+	if len(matches) < 2 {
+		return "", false
+	}
+	return matches[1], true
+}
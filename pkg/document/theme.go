@@ -0,0 +1,755 @@
+// Package document 提供文档主题（word/theme/theme1.xml）的解析与生成功能
+//
+// 主题定义了配色方案和字体方案。运行属性和样式可以通过 w:themeColor、
+// w:asciiTheme 等属性引用主题，替换主题即可统一修改整个文档的颜色和字体。
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	themeRelationshipType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	themeContentType      = "application/vnd.openxmlformats-officedocument.theme+xml"
+	drawingMLNamespace    = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	defaultThemePartName  = "word/theme/theme1.xml"
+)
+
+// 主题字体类别，用于 TextFormat.ThemeFont
+const (
+	// ThemeFontMajor 标题字体
+	ThemeFontMajor = "major"
+	// ThemeFontMinor 正文字体
+	ThemeFontMinor = "minor"
+)
+
+// Theme 文档主题
+//
+// 通过 Document.Theme 获取文档当前的主题，或使用 DefaultTheme 创建 Office 默认主题，
+// 修改后调用 Document.ApplyTheme 写回。从文档读取的主题在写回时保留格式方案
+// （fmtScheme）等未建模的内容。
+type Theme struct {
+	// Name 主题名称
+	Name string
+	// Colors 配色方案
+	Colors ThemeColorScheme
+	// Fonts 字体方案
+	Fonts ThemeFontScheme
+
+	raw []byte // 原始主题XML
+}
+
+// ThemeColorScheme 主题配色方案，颜色均为十六进制RGB（如 "4472C4"）
+type ThemeColorScheme struct {
+	Name              string
+	Dark1             string // 深色1（文字/背景-深色1，text1）
+	Light1            string // 浅色1（background1）
+	Dark2             string // 深色2（text2）
+	Light2            string // 浅色2（background2）
+	Accent1           string // 着色1
+	Accent2           string // 着色2
+	Accent3           string // 着色3
+	Accent4           string // 着色4
+	Accent5           string // 着色5
+	Accent6           string // 着色6
+	Hyperlink         string // 超链接
+	FollowedHyperlink string // 已访问的超链接
+}
+
+// ThemeFontScheme 主题字体方案
+type ThemeFontScheme struct {
+	Name  string
+	Major ThemeFontCollection // 标题字体
+	Minor ThemeFontCollection // 正文字体
+}
+
+// ThemeFontCollection 主题字体集合
+type ThemeFontCollection struct {
+	Latin         string            // 西文字体
+	EastAsian     string            // 东亚字体，为空时按文字脚本从 Scripts 中选择
+	ComplexScript string            // 复杂文种字体
+	Scripts       []ThemeScriptFont // 各文字脚本的字体（如 Hans 简体中文、Jpan 日文）
+}
+
+// ThemeScriptFont 文字脚本对应的字体
+type ThemeScriptFont struct {
+	Script   string // 脚本代码，如 Hans、Hant、Jpan、Hang
+	Typeface string // 字体名称
+}
+
+// themeColorElements 配色方案的元素名称，按 CT_ColorScheme 的顺序
+var themeColorElements = []string{
+	"dk1", "lt1", "dk2", "lt2", "accent1", "accent2", "accent3",
+	"accent4", "accent5", "accent6", "hlink", "folHlink",
+}
+
+// DefaultTheme 返回 Office 默认主题（Office 2013 及以后版本）
+func DefaultTheme() *Theme {
+	return &Theme{
+		Name: "Office 主题",
+		Colors: ThemeColorScheme{
+			Name:              "Office",
+			Dark1:             "000000",
+			Light1:            "FFFFFF",
+			Dark2:             "44546A",
+			Light2:            "E7E6E6",
+			Accent1:           "4472C4",
+			Accent2:           "ED7D31",
+			Accent3:           "A5A5A5",
+			Accent4:           "FFC000",
+			Accent5:           "5B9BD5",
+			Accent6:           "70AD47",
+			Hyperlink:         "0563C1",
+			FollowedHyperlink: "954F72",
+		},
+		Fonts: ThemeFontScheme{
+			Name: "Office",
+			Major: ThemeFontCollection{
+				Latin: "Calibri Light",
+				Scripts: []ThemeScriptFont{
+					{Script: "Jpan", Typeface: "游ゴシック Light"},
+					{Script: "Hang", Typeface: "맑은 고딕"},
+					{Script: "Hans", Typeface: "等线 Light"},
+					{Script: "Hant", Typeface: "新細明體"},
+					{Script: "Arab", Typeface: "Times New Roman"},
+					{Script: "Hebr", Typeface: "Times New Roman"},
+					{Script: "Thai", Typeface: "Angsana New"},
+				},
+			},
+			Minor: ThemeFontCollection{
+				Latin: "Calibri",
+				Scripts: []ThemeScriptFont{
+					{Script: "Jpan", Typeface: "游明朝"},
+					{Script: "Hang", Typeface: "맑은 고딕"},
+					{Script: "Hans", Typeface: "等线"},
+					{Script: "Hant", Typeface: "新細明體"},
+					{Script: "Arab", Typeface: "Arial"},
+					{Script: "Hebr", Typeface: "Arial"},
+					{Script: "Thai", Typeface: "Cordia New"},
+				},
+			},
+		},
+	}
+}
+
+// Theme 获取文档的主题，文档没有主题部件或解析失败时返回nil
+//
+// 返回值是主题的副本，修改后需调用 ApplyTheme 才会写回文档。
+func (d *Document) Theme() *Theme {
+	data, ok := d.parts[d.themePartName()]
+	if !ok || len(data) == 0 {
+		return nil
+	}
+	theme, err := parseThemeXML(data)
+	if err != nil {
+		Warnf("解析主题失败: %v", err)
+		return nil
+	}
+	return theme
+}
+
+// ApplyTheme 设置文档主题
+//
+// 写入 word/theme/theme1.xml（已有主题部件时覆盖），并按新主题更新正文、
+// 已加载的页眉页脚以及样式中主题颜色的回退值（w:val），使不支持主题的
+// 阅读器也能显示新的颜色。
+//
+// 示例:
+//
+//	theme := doc.Theme()
+//	if theme == nil {
+//		theme = document.DefaultTheme()
+//	}
+//	theme.Colors.Accent1 = "C00000"
+//	theme.Fonts.Minor.SetScriptFont("Hans", "仿宋_GB2312")
+//	err := doc.ApplyTheme(theme)
+func (d *Document) ApplyTheme(theme *Theme) error {
+	if theme == nil {
+		return NewValidationError("theme", "", "主题不能为空")
+	}
+	if err := theme.Colors.validate(); err != nil {
+		return WrapError("apply_theme", err)
+	}
+
+	data, err := theme.marshal()
+	if err != nil {
+		return WrapError("apply_theme", err)
+	}
+
+	partName := d.themePartName()
+	d.parts[partName] = data
+	d.addContentType(partName, themeContentType)
+	hasRelationship := false
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type == themeRelationshipType {
+			hasRelationship = true
+			break
+		}
+	}
+	if !hasRelationship {
+		d.documentRelationships.Relationships = append(d.documentRelationships.Relationships, Relationship{
+			ID:     fmt.Sprintf("rId%d", len(d.documentRelationships.Relationships)+2), // +2因为rId1保留给styles
+			Type:   themeRelationshipType,
+			Target: strings.TrimPrefix(partName, "word/"),
+		})
+	}
+
+	d.refreshThemeColors(theme)
+	Infof("应用文档主题: %s", theme.Name)
+	return nil
+}
+
+// themePartName 返回文档主题部件的名称，未关联主题时返回默认名称
+func (d *Document) themePartName() string {
+	for _, rel := range d.documentRelationships.Relationships {
+		if rel.Type == themeRelationshipType {
+			return path.Join("word", rel.Target)
+		}
+	}
+	return defaultThemePartName
+}
+
+// refreshThemeColors 按主题重新计算主题颜色的回退值
+func (d *Document) refreshThemeColors(theme *Theme) {
+	refresh := func(color *Color) {
+		if color == nil || color.ThemeColor == "" {
+			return
+		}
+		if val, ok := theme.ResolveColor(color.ThemeColor, color.ThemeTint, color.ThemeShade); ok {
+			color.Val = val
+		}
+	}
+	refreshParagraph := func(p *Paragraph) {
+		for i := range p.Runs {
+			if p.Runs[i].Properties != nil {
+				refresh(p.Runs[i].Properties.Color)
+			}
+		}
+	}
+
+	forEachParagraph(d.Body.Elements, refreshParagraph)
+	for _, header := range d.headers {
+		forEachParagraph(header.Elements, refreshParagraph)
+	}
+	for _, footer := range d.footers {
+		forEachParagraph(footer.Elements, refreshParagraph)
+	}
+	if d.styleManager != nil {
+		for _, s := range d.styleManager.GetAllStyles() {
+			if s.RunPr == nil || s.RunPr.Color == nil || s.RunPr.Color.ThemeColor == "" {
+				continue
+			}
+			color := s.RunPr.Color
+			if val, ok := theme.ResolveColor(color.ThemeColor, color.ThemeTint, color.ThemeShade); ok {
+				color.Val = val
+			}
+		}
+	}
+}
+
+// Color 返回配色方案中指定名称的颜色，名称可以是 ST_ThemeColor 的值
+// （如 accent1、text1、background2、hyperlink）或方案元素名（如 dk1、lt2）
+func (c *ThemeColorScheme) Color(name string) (string, bool) {
+	var val string
+	switch name {
+	case "dark1", "text1", "dk1", "tx1":
+		val = c.Dark1
+	case "light1", "background1", "lt1", "bg1":
+		val = c.Light1
+	case "dark2", "text2", "dk2", "tx2":
+		val = c.Dark2
+	case "light2", "background2", "lt2", "bg2":
+		val = c.Light2
+	case "accent1":
+		val = c.Accent1
+	case "accent2":
+		val = c.Accent2
+	case "accent3":
+		val = c.Accent3
+	case "accent4":
+		val = c.Accent4
+	case "accent5":
+		val = c.Accent5
+	case "accent6":
+		val = c.Accent6
+	case "hyperlink", "hlink":
+		val = c.Hyperlink
+	case "followedHyperlink", "folHlink":
+		val = c.FollowedHyperlink
+	default:
+		return "", false
+	}
+	return val, val != ""
+}
+
+// setColor 按方案元素名设置颜色
+func (c *ThemeColorScheme) setColor(element, val string) {
+	switch element {
+	case "dk1":
+		c.Dark1 = val
+	case "lt1":
+		c.Light1 = val
+	case "dk2":
+		c.Dark2 = val
+	case "lt2":
+		c.Light2 = val
+	case "accent1":
+		c.Accent1 = val
+	case "accent2":
+		c.Accent2 = val
+	case "accent3":
+		c.Accent3 = val
+	case "accent4":
+		c.Accent4 = val
+	case "accent5":
+		c.Accent5 = val
+	case "accent6":
+		c.Accent6 = val
+	case "hlink":
+		c.Hyperlink = val
+	case "folHlink":
+		c.FollowedHyperlink = val
+	}
+}
+
+// validate 检查配色方案中的颜色是否均为6位十六进制RGB
+func (c *ThemeColorScheme) validate() error {
+	for _, element := range themeColorElements {
+		val, _ := c.Color(element)
+		if !isHexColor(val) {
+			return NewValidationError("theme.colors."+element, val, "主题颜色必须是6位十六进制RGB值")
+		}
+	}
+	return nil
+}
+
+// isHexColor 检查是否为6位十六进制颜色
+func isHexColor(val string) bool {
+	if len(val) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(val, 16, 32)
+	return err == nil
+}
+
+// ScriptFont 返回指定文字脚本的字体
+func (c *ThemeFontCollection) ScriptFont(script string) string {
+	for _, font := range c.Scripts {
+		if font.Script == script {
+			return font.Typeface
+		}
+	}
+	return ""
+}
+
+// SetScriptFont 设置指定文字脚本的字体，typeface 为空时删除
+func (c *ThemeFontCollection) SetScriptFont(script, typeface string) {
+	for i, font := range c.Scripts {
+		if font.Script == script {
+			if typeface == "" {
+				c.Scripts = append(c.Scripts[:i], c.Scripts[i+1:]...)
+			} else {
+				c.Scripts[i].Typeface = typeface
+			}
+			return
+		}
+	}
+	if typeface != "" {
+		c.Scripts = append(c.Scripts, ThemeScriptFont{Script: script, Typeface: typeface})
+	}
+}
+
+// ResolveFont 解析主题字体引用（如 minorHAnsi、majorEastAsia、minorBidi）对应的字体名称
+//
+// script 为东亚或复杂文种字体未直接指定时使用的文字脚本（如 "Hans"），可为空。
+func (t *Theme) ResolveFont(themeFont, script string) string {
+	var collection *ThemeFontCollection
+	var kind string
+	switch {
+	case strings.HasPrefix(themeFont, ThemeFontMajor):
+		collection, kind = &t.Fonts.Major, strings.TrimPrefix(themeFont, ThemeFontMajor)
+	case strings.HasPrefix(themeFont, ThemeFontMinor):
+		collection, kind = &t.Fonts.Minor, strings.TrimPrefix(themeFont, ThemeFontMinor)
+	default:
+		return ""
+	}
+
+	var typeface string
+	switch kind {
+	case "Ascii", "HAnsi":
+		return collection.Latin
+	case "EastAsia":
+		typeface = collection.EastAsian
+	case "Bidi":
+		typeface = collection.ComplexScript
+	default:
+		return ""
+	}
+	if typeface == "" && script != "" {
+		typeface = collection.ScriptFont(script)
+	}
+	return typeface
+}
+
+// ResolveColor 计算主题颜色的RGB值
+//
+// themeColor 为 ST_ThemeColor 的值（如 accent1），tint 和 shade 为 w:themeTint、
+// w:themeShade 的十六进制值（可为空），按 HSL 亮度进行淡化或加深。
+func (t *Theme) ResolveColor(themeColor, tint, shade string) (string, bool) {
+	val, ok := t.Colors.Color(themeColor)
+	if !ok || !isHexColor(val) {
+		return "", false
+	}
+	if tint == "" && shade == "" {
+		return strings.ToUpper(val), true
+	}
+
+	rgb, _ := strconv.ParseUint(val, 16, 32)
+	h, s, l := rgbToHSL(float64(rgb>>16&0xFF)/255, float64(rgb>>8&0xFF)/255, float64(rgb&0xFF)/255)
+	if factor, err := strconv.ParseUint(tint, 16, 8); err == nil {
+		f := float64(factor) / 255
+		l = l*f + (1 - f)
+	}
+	if factor, err := strconv.ParseUint(shade, 16, 8); err == nil {
+		l *= float64(factor) / 255
+	}
+	r, g, b := hslToRGB(h, s, l)
+	return fmt.Sprintf("%02X%02X%02X", toByte(r), toByte(g), toByte(b)), true
+}
+
+// toByte 将0-1范围的分量转换为字节
+func toByte(v float64) int {
+	return int(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// rgbToHSL 将RGB（0-1）转换为HSL（均为0-1）
+func rgbToHSL(r, g, b float64) (h, s, l float64) {
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+	delta := max - min
+	if l > 0.5 {
+		s = delta / (2 - max - min)
+	} else {
+		s = delta / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / delta
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	return h / 6, s, l
+}
+
+// hslToRGB 将HSL（均为0-1）转换为RGB（0-1）
+func hslToRGB(h, s, l float64) (r, g, b float64) {
+	if s == 0 {
+		return l, l, l
+	}
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		if t < 0 {
+			t++
+		}
+		if t > 1 {
+			t--
+		}
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		default:
+			return p
+		}
+	}
+	return hue(h + 1.0/3), hue(h), hue(h - 1.0/3)
+}
+
+// parseThemeXML 解析主题XML中的配色方案和字体方案
+func parseThemeXML(data []byte) (*Theme, error) {
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, err
+	}
+	if !root.is(drawingMLNamespace, "theme") {
+		return nil, NewValidationError("theme", root.qualifiedName(), "缺少a:theme根元素")
+	}
+
+	theme := &Theme{Name: root.attr("name"), raw: append([]byte(nil), data...)}
+	elements := root.child(drawingMLNamespace, "themeElements")
+	if elements == nil {
+		return nil, NewValidationError("theme", "", "缺少a:themeElements元素")
+	}
+
+	if scheme := elements.child(drawingMLNamespace, "clrScheme"); scheme != nil {
+		theme.Colors.Name = scheme.attr("name")
+		for _, name := range themeColorElements {
+			element := scheme.child(drawingMLNamespace, name)
+			if element == nil {
+				continue
+			}
+			if srgb := element.child(drawingMLNamespace, "srgbClr"); srgb != nil {
+				theme.Colors.setColor(name, strings.ToUpper(srgb.attr("val")))
+			} else if sys := element.child(drawingMLNamespace, "sysClr"); sys != nil {
+				theme.Colors.setColor(name, strings.ToUpper(sys.attr("lastClr")))
+			}
+		}
+	}
+
+	if scheme := elements.child(drawingMLNamespace, "fontScheme"); scheme != nil {
+		theme.Fonts.Name = scheme.attr("name")
+		parseCollection := func(node *xmlNode, collection *ThemeFontCollection) {
+			if node == nil {
+				return
+			}
+			if latin := node.child(drawingMLNamespace, "latin"); latin != nil {
+				collection.Latin = latin.attr("typeface")
+			}
+			if ea := node.child(drawingMLNamespace, "ea"); ea != nil {
+				collection.EastAsian = ea.attr("typeface")
+			}
+			if cs := node.child(drawingMLNamespace, "cs"); cs != nil {
+				collection.ComplexScript = cs.attr("typeface")
+			}
+			for _, font := range node.childElements(drawingMLNamespace, "font") {
+				collection.Scripts = append(collection.Scripts, ThemeScriptFont{
+					Script:   font.attr("script"),
+					Typeface: font.attr("typeface"),
+				})
+			}
+		}
+		parseCollection(scheme.child(drawingMLNamespace, "majorFont"), &theme.Fonts.Major)
+		parseCollection(scheme.child(drawingMLNamespace, "minorFont"), &theme.Fonts.Minor)
+	}
+	return theme, nil
+}
+
+// themeNameAttrPattern 匹配根元素的 name 属性
+var themeNameAttrPattern = regexp.MustCompile(`\sname="[^"]*"`)
+
+// marshal 生成主题XML；从文档读取的主题仅替换配色方案、字体方案和名称
+func (t *Theme) marshal() ([]byte, error) {
+	if len(t.raw) == 0 {
+		return t.marshalNew(), nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(t.raw))
+	var buf bytes.Buffer
+	var last int64
+	depth := 0
+	var replaceStart int64
+	var replacing string
+	prefix := "a"
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				prefix = tok.Name.Space
+				tag := string(t.raw[offset:decoder.InputOffset()])
+				name := ` name="` + escapeXMLAttr(t.Name) + `"`
+				if themeNameAttrPattern.MatchString(tag) {
+					tag = themeNameAttrPattern.ReplaceAllLiteralString(tag, name)
+				} else if t.Name != "" {
+					tag = strings.Replace(tag, qualifiedName(tok.Name), qualifiedName(tok.Name)+name, 1)
+				}
+				buf.Write(t.raw[last:offset])
+				buf.WriteString(tag)
+				last = decoder.InputOffset()
+			}
+			if depth == 3 && replacing == "" && (tok.Name.Local == "clrScheme" || tok.Name.Local == "fontScheme") {
+				replacing, replaceStart = tok.Name.Local, offset
+			}
+		case xml.EndElement:
+			if depth == 3 && replacing == tok.Name.Local {
+				buf.Write(t.raw[last:replaceStart])
+				if replacing == "clrScheme" {
+					buf.WriteString(t.colorSchemeXML(prefix))
+				} else {
+					buf.WriteString(t.fontSchemeXML(prefix))
+				}
+				last = decoder.InputOffset()
+				replacing = ""
+			}
+			depth--
+		}
+	}
+	buf.Write(t.raw[last:])
+	return buf.Bytes(), nil
+}
+
+// marshalNew 生成完整的主题XML（格式方案使用简化的默认设置）
+func (t *Theme) marshalNew() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<a:theme xmlns:a="` + drawingMLNamespace + `" name="` + escapeXMLAttr(t.Name) + `">`)
+	buf.WriteString(`<a:themeElements>`)
+	buf.WriteString(t.colorSchemeXML("a"))
+	buf.WriteString(t.fontSchemeXML("a"))
+	buf.WriteString(defaultFormatSchemeXML)
+	buf.WriteString(`</a:themeElements><a:objectDefaults/><a:extraClrSchemeLst/></a:theme>`)
+	return buf.Bytes()
+}
+
+// colorSchemeXML 生成配色方案元素
+func (t *Theme) colorSchemeXML(prefix string) string {
+	p := prefix + ":"
+	if prefix == "" {
+		p = ""
+	}
+	var sb strings.Builder
+	sb.WriteString(`<` + p + `clrScheme name="` + escapeXMLAttr(t.Colors.Name) + `">`)
+	for _, name := range themeColorElements {
+		val, _ := t.Colors.Color(name)
+		val = strings.ToUpper(val)
+		sb.WriteString(`<` + p + name + `>`)
+		switch {
+		case name == "dk1" && val == "000000":
+			sb.WriteString(`<` + p + `sysClr val="windowText" lastClr="000000"/>`)
+		case name == "lt1" && val == "FFFFFF":
+			sb.WriteString(`<` + p + `sysClr val="window" lastClr="FFFFFF"/>`)
+		default:
+			sb.WriteString(`<` + p + `srgbClr val="` + val + `"/>`)
+		}
+		sb.WriteString(`</` + p + name + `>`)
+	}
+	sb.WriteString(`</` + p + `clrScheme>`)
+	return sb.String()
+}
+
+// fontSchemeXML 生成字体方案元素
+func (t *Theme) fontSchemeXML(prefix string) string {
+	p := prefix + ":"
+	if prefix == "" {
+		p = ""
+	}
+	var sb strings.Builder
+	writeCollection := func(name string, c *ThemeFontCollection) {
+		sb.WriteString(`<` + p + name + `>`)
+		sb.WriteString(`<` + p + `latin typeface="` + escapeXMLAttr(c.Latin) + `"/>`)
+		sb.WriteString(`<` + p + `ea typeface="` + escapeXMLAttr(c.EastAsian) + `"/>`)
+		sb.WriteString(`<` + p + `cs typeface="` + escapeXMLAttr(c.ComplexScript) + `"/>`)
+		for _, font := range c.Scripts {
+			sb.WriteString(`<` + p + `font script="` + escapeXMLAttr(font.Script) + `" typeface="` + escapeXMLAttr(font.Typeface) + `"/>`)
+		}
+		sb.WriteString(`</` + p + name + `>`)
+	}
+	sb.WriteString(`<` + p + `fontScheme name="` + escapeXMLAttr(t.Fonts.Name) + `">`)
+	writeCollection("majorFont", &t.Fonts.Major)
+	writeCollection("minorFont", &t.Fonts.Minor)
+	sb.WriteString(`</` + p + `fontScheme>`)
+	return sb.String()
+}
+
+// defaultFormatSchemeXML 新建主题使用的格式方案（填充、线条、效果和背景各三级）
+const defaultFormatSchemeXML = `<a:fmtScheme name="Office">` +
+	`<a:fillStyleLst>` +
+	`<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>` +
+	`<a:solidFill><a:schemeClr val="phClr"><a:tint val="50000"/></a:schemeClr></a:solidFill>` +
+	`<a:solidFill><a:schemeClr val="phClr"><a:shade val="80000"/></a:schemeClr></a:solidFill>` +
+	`</a:fillStyleLst>` +
+	`<a:lnStyleLst>` +
+	`<a:ln w="6350" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:prstDash val="solid"/><a:miter lim="800000"/></a:ln>` +
+	`<a:ln w="12700" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:prstDash val="solid"/><a:miter lim="800000"/></a:ln>` +
+	`<a:ln w="19050" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:prstDash val="solid"/><a:miter lim="800000"/></a:ln>` +
+	`</a:lnStyleLst>` +
+	`<a:effectStyleLst>` +
+	`<a:effectStyle><a:effectLst/></a:effectStyle>` +
+	`<a:effectStyle><a:effectLst/></a:effectStyle>` +
+	`<a:effectStyle><a:effectLst/></a:effectStyle>` +
+	`</a:effectStyleLst>` +
+	`<a:bgFillStyleLst>` +
+	`<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>` +
+	`<a:solidFill><a:schemeClr val="phClr"><a:tint val="95000"/></a:schemeClr></a:solidFill>` +
+	`<a:solidFill><a:schemeClr val="phClr"><a:shade val="80000"/></a:schemeClr></a:solidFill>` +
+	`</a:bgFillStyleLst>` +
+	`</a:fmtScheme>`
+
+// applyThemeFormat 将文本格式中的主题字体和主题颜色写入运行属性
+func applyThemeFormat(props *RunProperties, format *TextFormat) {
+	if props == nil || format == nil {
+		return
+	}
+	if format.ThemeFont != "" {
+		if props.FontFamily == nil {
+			props.FontFamily = &FontFamily{}
+		}
+		props.FontFamily.ASCIITheme = format.ThemeFont + "HAnsi"
+		props.FontFamily.HAnsiTheme = format.ThemeFont + "HAnsi"
+		props.FontFamily.EastAsiaTheme = format.ThemeFont + "EastAsia"
+		props.FontFamily.CSTheme = format.ThemeFont + "Bidi"
+	}
+	if format.ThemeColor != "" {
+		val := strings.TrimPrefix(format.FontColor, "#")
+		if val == "" {
+			val, _ = DefaultTheme().ResolveColor(format.ThemeColor, "", "")
+		}
+		if val == "" {
+			val = "auto"
+		}
+		props.Color = &Color{Val: val, ThemeColor: format.ThemeColor}
+	}
+}
+
+// parseFontFamilyAttrs 从 w:rFonts 的属性解析字体设置
+func parseFontFamilyAttrs(attrs []xml.Attr) *FontFamily {
+	return &FontFamily{
+		ASCII:         getAttributeValue(attrs, "ascii"),
+		HAnsi:         getAttributeValue(attrs, "hAnsi"),
+		EastAsia:      getAttributeValue(attrs, "eastAsia"),
+		CS:            getAttributeValue(attrs, "cs"),
+		Hint:          getAttributeValue(attrs, "hint"),
+		ASCIITheme:    getAttributeValue(attrs, "asciiTheme"),
+		HAnsiTheme:    getAttributeValue(attrs, "hAnsiTheme"),
+		EastAsiaTheme: getAttributeValue(attrs, "eastAsiaTheme"),
+		CSTheme:       getAttributeValue(attrs, "cstheme"),
+	}
+}
+
+// parseColorAttrs 从 w:color 的属性解析颜色，未设置颜色时返回nil
+func parseColorAttrs(attrs []xml.Attr) *Color {
+	color := &Color{
+		Val:        getAttributeValue(attrs, "val"),
+		ThemeColor: getAttributeValue(attrs, "themeColor"),
+		ThemeTint:  getAttributeValue(attrs, "themeTint"),
+		ThemeShade: getAttributeValue(attrs, "themeShade"),
+	}
+	if color.Val == "" && color.ThemeColor == "" {
+		return nil
+	}
+	return color
+}
@@ -0,0 +1,116 @@
+// Package document 文档主题功能测试
+package document
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// colorsClose 比较两个十六进制颜色，各分量允许1的舍入误差
+func colorsClose(a, b string) bool {
+	x, errA := strconv.ParseUint(a, 16, 32)
+	y, errB := strconv.ParseUint(b, 16, 32)
+	if errA != nil || errB != nil {
+		return false
+	}
+	for shift := 0; shift <= 16; shift += 8 {
+		diff := int(x>>shift&0xFF) - int(y>>shift&0xFF)
+		if diff > 1 || diff < -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestThemeResolve 测试主题颜色和主题字体的解析
+func TestThemeResolve(t *testing.T) {
+	theme := DefaultTheme()
+
+	tests := []struct {
+		color, tint, shade string
+		expected           string
+	}{
+		{"accent1", "", "", "4472C4"},
+		{"text1", "", "", "000000"},
+		{"accent1", "66", "", "B4C6E7"}, // 着色1，淡色60%
+		{"accent1", "", "BF", "2F5496"}, // 着色1，深色25%
+		{"background1", "", "F2", "F2F2F2"},
+	}
+	for _, tt := range tests {
+		val, ok := theme.ResolveColor(tt.color, tt.tint, tt.shade)
+		if !ok || !colorsClose(val, tt.expected) {
+			t.Errorf("ResolveColor(%s, %s, %s) = %s，期望 %s", tt.color, tt.tint, tt.shade, val, tt.expected)
+		}
+	}
+	if _, ok := theme.ResolveColor("unknown", "", ""); ok {
+		t.Error("未知的主题颜色应返回false")
+	}
+
+	if font := theme.ResolveFont("minorEastAsia", "Hans"); font != "等线" {
+		t.Errorf("正文东亚字体解析错误: %s", font)
+	}
+	if font := theme.ResolveFont("majorHAnsi", ""); font != "Calibri Light" {
+		t.Errorf("标题西文字体解析错误: %s", font)
+	}
+}
+
+// TestApplyTheme 测试主题的生成、引用和替换
+func TestApplyTheme(t *testing.T) {
+	doc := New()
+	if doc.Theme() != nil {
+		t.Error("新建文档不应有主题")
+	}
+	if err := doc.ApplyTheme(&Theme{}); err == nil {
+		t.Error("颜色不完整的主题应返回错误")
+	}
+	if err := doc.ApplyTheme(DefaultTheme()); err != nil {
+		t.Fatalf("应用主题失败: %v", err)
+	}
+	doc.AddFormattedParagraph("品牌标题", &TextFormat{ThemeFont: ThemeFontMajor, ThemeColor: "accent1"})
+
+	filename := filepath.Join(t.TempDir(), "theme.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	documentXML := string(doc.parts["word/document.xml"])
+	for _, expected := range []string{`w:themeColor="accent1"`, `w:val="4472C4"`, `w:eastAsiaTheme="majorEastAsia"`, `w:cstheme="majorBidi"`} {
+		if !strings.Contains(documentXML, expected) {
+			t.Errorf("正文缺少主题引用 %s", expected)
+		}
+	}
+
+	// 重新打开并更换品牌色和中文字体
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	theme := opened.Theme()
+	if theme == nil || theme.Colors.Accent1 != "4472C4" || theme.Fonts.Minor.ScriptFont("Hans") != "等线" {
+		t.Fatalf("主题解析不正确: %+v", theme)
+	}
+	run := opened.Body.GetParagraphs()[0].Runs[0]
+	if run.Properties.Color.ThemeColor != "accent1" || run.Properties.FontFamily.ASCIITheme != "majorHAnsi" {
+		t.Errorf("运行的主题引用解析不正确: %+v %+v", run.Properties.Color, run.Properties.FontFamily)
+	}
+
+	theme.Name = "企业主题"
+	theme.Colors.Accent1 = "C00000"
+	theme.Fonts.Major.SetScriptFont("Hans", "方正小标宋简体")
+	if err := opened.ApplyTheme(theme); err != nil {
+		t.Fatalf("替换主题失败: %v", err)
+	}
+	if color := opened.Body.GetParagraphs()[0].Runs[0].Properties.Color; color.Val != "C00000" {
+		t.Errorf("主题颜色的回退值应随主题更新: %s", color.Val)
+	}
+
+	themeXML := string(opened.parts["word/theme/theme1.xml"])
+	if !strings.Contains(themeXML, `name="企业主题"`) || !strings.Contains(themeXML, `<a:srgbClr val="C00000"/>`) ||
+		!strings.Contains(themeXML, `typeface="方正小标宋简体"`) || !strings.Contains(themeXML, "<a:fmtScheme") {
+		t.Errorf("替换后的主题内容不正确:\n%s", themeXML)
+	}
+	if strings.Count(themeXML, "<a:clrScheme") != 1 || strings.Count(themeXML, "<a:fontScheme") != 1 {
+		t.Error("配色方案和字体方案应只被替换一次")
+	}
+}
@@ -20,9 +20,20 @@ type TOCConfig struct {
 // TOCEntry 目录条目
 type TOCEntry struct {
 	Text       string // 条目文本
+	Number     string // 标题编号（设置了标题编号时，如 "1.2"、"第一章"）
 	Level      int    // 级别（1-9）
 	PageNum    int    // 页码
 	BookmarkID string // 书签ID（用于超链接）
+
+	numberSuffix string // 编号与文本之间的分隔（由编号级别的 w:suff 决定）
+}
+
+// displayText 返回目录中显示的条目文本（包含标题编号）
+func (e TOCEntry) displayText() string {
+	if e.Number == "" {
+		return e.Text
+	}
+	return e.Number + e.numberSuffix + e.Text
 }
 
 // TOCField 目录域
@@ -89,7 +100,7 @@ func (d *Document) GenerateTOC(config *TOCConfig) error {
 	// 为每个标题条目添加到目录中
 	for i, entry := range entries {
 		entryID := fmt.Sprintf("14746%d", 3000+i)
-		tocSDT.AddTOCEntry(entry.Text, entry.Level, entry.PageNum, entryID)
+		tocSDT.AddTOCEntry(entry.displayText(), entry.Level, entry.PageNum, entryID)
 	}
 
 	// 完成目录SDT构建
@@ -173,7 +184,7 @@ func (d *Document) UpdateTOC() error {
 	// 为每个标题条目添加到目录中
 	for i, entry := range entries {
 		entryID := fmt.Sprintf("14746%d", 3000+i)
-		tocSDT.AddTOCEntry(entry.Text, entry.Level, entry.PageNum, entryID)
+		tocSDT.AddTOCEntry(entry.displayText(), entry.Level, entry.PageNum, entryID)
 	}
 
 	// 完成目录SDT构建
@@ -227,18 +238,26 @@ func (d *Document) AddHeadingWithBookmark(text string, level int, bookmarkName s
 func (d *Document) collectHeadings(maxLevel int) []TOCEntry {
 	var entries []TOCEntry
 	pageNum := 1 // 简化处理，实际需要计算真实页码
+	numberer := d.newListNumberer()
 
 	for _, element := range d.Body.Elements {
 		if paragraph, ok := element.(*Paragraph); ok {
 			level := d.getHeadingLevel(paragraph)
-			if level > 0 && level <= maxLevel {
+			if level == 0 {
+				continue
+			}
+			number, suffix := numberer.next(paragraph, level)
+			if level <= maxLevel {
 				text := d.extractParagraphText(paragraph)
 				if text != "" {
 					entry := TOCEntry{
 						Text:       text,
+						Number:     number,
 						Level:      level,
 						PageNum:    pageNum,
 						BookmarkID: fmt.Sprintf("_Toc_%s", strings.ReplaceAll(text, " ", "_")),
+
+						numberSuffix: suffix,
 					}
 					entries = append(entries, entry)
 				}
@@ -409,7 +428,7 @@ func (d *Document) addTOCEntry(entry TOCEntry, config *TOCConfig) error {
 		// 标题文本
 		titleRun := Run{
 			Properties: &RunProperties{},
-			Text:       Text{Content: entry.Text},
+			Text:       Text{Content: entry.displayText()},
 		}
 		hyperlink.Runs = append(hyperlink.Runs, titleRun)
 
@@ -437,7 +456,7 @@ func (d *Document) addTOCEntry(entry TOCEntry, config *TOCConfig) error {
 		// 简化处理，直接作为文本添加
 		hyperlinkRun := Run{
 			Properties: &RunProperties{},
-			Text:       Text{Content: entry.Text},
+			Text:       Text{Content: entry.displayText()},
 		}
 		entryPara.Runs = append(entryPara.Runs, hyperlinkRun)
 
@@ -452,7 +471,7 @@ func (d *Document) addTOCEntry(entry TOCEntry, config *TOCConfig) error {
 		// 不使用超链接的简单文本
 		titleRun := Run{
 			Properties: &RunProperties{},
-			Text:       Text{Content: entry.Text},
+			Text:       Text{Content: entry.displayText()},
 		}
 		entryPara.Runs = append(entryPara.Runs, titleRun)
 
@@ -828,7 +847,7 @@ func (d *Document) createTOCEntryWithFields(entry TOCEntry, config *TOCConfig) *
 
 	// 添加标题文本
 	para.Runs = append(para.Runs, Run{
-		Text: Text{Content: entry.Text},
+		Text: Text{Content: entry.displayText()},
 	})
 
 	// 添加制表符
@@ -903,10 +922,15 @@ func (d *Document) collectHeadingsAndAddBookmarks(maxLevel int) []TOCEntry {
 	// 需要一个新的Elements切片来插入书签
 	newElements := make([]interface{}, 0, len(d.Body.Elements)*2)
 	entryIndex := 0
+	numberer := d.newListNumberer()
 
 	for _, element := range d.Body.Elements {
 		if paragraph, ok := element.(*Paragraph); ok {
 			level := d.getHeadingLevel(paragraph)
+			number, suffix := "", ""
+			if level > 0 {
+				number, suffix = numberer.next(paragraph, level)
+			}
 			if level > 0 && level <= maxLevel {
 				text := d.extractParagraphText(paragraph)
 				if text != "" {
@@ -915,9 +939,12 @@ func (d *Document) collectHeadingsAndAddBookmarks(maxLevel int) []TOCEntry {
 
 					entry := TOCEntry{
 						Text:       text,
+						Number:     number,
 						Level:      level,
 						PageNum:    pageNum,
 						BookmarkID: anchor,
+
+						numberSuffix: suffix,
 					}
 					entries = append(entries, entry)
 
@@ -0,0 +1,405 @@
+// Package document TrueType字体解析与子集化
+//
+// 嵌入字体时只需要读取少量表：cmap（字符到字形的映射）、head/maxp/loca/glyf
+// （字形数据）以及 OS/2（嵌入许可）。子集化采用清空未使用字形的方式：
+// 保持字形编号和其他表不变，只将未使用字形在 glyf 中的数据置空，
+// 因此 hmtx、GSUB 等引用字形编号的表无需改写。
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// SFNT 版本标识
+const (
+	sfntVersionTrueType = 0x00010000
+	sfntVersionApple    = 0x74727565 // 'true'
+	sfntVersionCFF      = 0x4F54544F // 'OTTO'
+	sfntVersionTTC      = 0x74746366 // 'ttcf'
+
+	// headCheckSumMagic head.checkSumAdjustment 的计算基数
+	headCheckSumMagic = 0xB1B0AFBA
+)
+
+// sfntTable 字体表
+type sfntTable struct {
+	tag  string
+	data []byte
+}
+
+// sfntFont 解析后的SFNT字体（TrueType/OpenType）
+type sfntFont struct {
+	version uint32
+	tables  []*sfntTable
+}
+
+// parseSFNT 解析SFNT字体的表目录
+func parseSFNT(data []byte) (*sfntFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("字体数据过短")
+	}
+	font := &sfntFont{version: binary.BigEndian.Uint32(data)}
+	switch font.version {
+	case sfntVersionTrueType, sfntVersionApple, sfntVersionCFF:
+	case sfntVersionTTC:
+		return nil, fmt.Errorf("不支持字体集合（TTC），请提供单个字体")
+	default:
+		return nil, fmt.Errorf("不是有效的TrueType/OpenType字体")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+numTables*16 {
+		return nil, fmt.Errorf("字体表目录不完整")
+	}
+	for i := 0; i < numTables; i++ {
+		record := data[12+i*16:]
+		offset := binary.BigEndian.Uint32(record[8:])
+		length := binary.BigEndian.Uint32(record[12:])
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("字体表 %s 超出数据范围", record[:4])
+		}
+		font.tables = append(font.tables, &sfntTable{
+			tag:  string(record[:4]),
+			data: data[offset : offset+length],
+		})
+	}
+	return font, nil
+}
+
+// table 返回指定标签的表数据，不存在时返回nil
+func (f *sfntFont) table(tag string) []byte {
+	for _, t := range f.tables {
+		if t.tag == tag {
+			return t.data
+		}
+	}
+	return nil
+}
+
+// embeddingRestricted 检查 OS/2 表的 fsType 是否禁止嵌入（受限许可）
+func (f *sfntFont) embeddingRestricted() bool {
+	os2 := f.table("OS/2")
+	if len(os2) < 10 {
+		return false
+	}
+	return binary.BigEndian.Uint16(os2[8:])&0x000F == 0x0002
+}
+
+// bytes 重新生成字体文件，表按标签排序并重新计算校验和
+func (f *sfntFont) bytes() []byte {
+	tables := append([]*sfntTable(nil), f.tables...)
+	sort.Slice(tables, func(i, j int) bool { return tables[i].tag < tables[j].tag })
+
+	numTables := len(tables)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= numTables {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	header := make([]byte, 12+numTables*16)
+	binary.BigEndian.PutUint32(header, f.version)
+	binary.BigEndian.PutUint16(header[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(numTables*16-searchRange))
+
+	var body bytes.Buffer
+	headOffset := -1
+	for i, t := range tables {
+		data := t.data
+		if t.tag == "head" && len(data) >= 12 {
+			// 计算校验和时 checkSumAdjustment 视为0
+			data = append([]byte(nil), data...)
+			binary.BigEndian.PutUint32(data[8:], 0)
+			headOffset = len(header) + body.Len()
+		}
+		record := header[12+i*16:]
+		copy(record, t.tag)
+		binary.BigEndian.PutUint32(record[4:], sfntChecksum(data))
+		binary.BigEndian.PutUint32(record[8:], uint32(len(header)+body.Len()))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(data)))
+		body.Write(data)
+		for body.Len()%4 != 0 {
+			body.WriteByte(0)
+		}
+	}
+
+	result := append(header, body.Bytes()...)
+	if headOffset >= 0 {
+		binary.BigEndian.PutUint32(result[headOffset+8:], headCheckSumMagic-sfntChecksum(result))
+	}
+	return result
+}
+
+// sfntChecksum 计算表校验和（按大端32位整数求和，末尾不足4字节补0）
+func sfntChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// glyphIndices 通过 cmap 表查找字符对应的字形编号（未映射的字符被忽略）
+func (f *sfntFont) glyphIndices(chars map[rune]bool) (map[uint16]bool, error) {
+	cmap := f.table("cmap")
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("字体缺少cmap表")
+	}
+
+	// 优先使用完整Unicode映射（格式12），其次为BMP映射（格式4）
+	var format4, format12 []byte
+	symbol := false
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+i*8+8 <= len(cmap); i++ {
+		record := cmap[4+i*8:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := binary.BigEndian.Uint32(record[4:])
+		if uint64(offset)+2 > uint64(len(cmap)) {
+			continue
+		}
+		subtable := cmap[offset:]
+		switch format := binary.BigEndian.Uint16(subtable); {
+		case format == 12 && (platform == 0 || (platform == 3 && encoding == 10)):
+			format12 = subtable
+		case format == 4 && (platform == 0 || (platform == 3 && (encoding == 1 || encoding == 0))):
+			if format4 == nil || encoding == 1 {
+				format4 = subtable
+				symbol = platform == 3 && encoding == 0
+			}
+		}
+	}
+
+	glyphs := make(map[uint16]bool)
+	switch {
+	case format12 != nil:
+		if len(format12) < 16 {
+			return nil, fmt.Errorf("cmap格式12子表不完整")
+		}
+		numGroups := int(binary.BigEndian.Uint32(format12[12:]))
+		for i := 0; i < numGroups && 16+i*12+12 <= len(format12); i++ {
+			group := format12[16+i*12:]
+			start := rune(binary.BigEndian.Uint32(group))
+			end := rune(binary.BigEndian.Uint32(group[4:]))
+			startGlyph := binary.BigEndian.Uint32(group[8:])
+			for char := range chars {
+				if char >= start && char <= end {
+					glyphs[uint16(startGlyph+uint32(char-start))] = true
+				}
+			}
+		}
+	case format4 != nil:
+		for char := range chars {
+			if glyph := cmapFormat4Lookup(format4, char); glyph != 0 {
+				glyphs[glyph] = true
+			} else if symbol && char < 0x100 {
+				// 符号字体的字符映射在 U+F000 区
+				if glyph := cmapFormat4Lookup(format4, 0xF000+char); glyph != 0 {
+					glyphs[glyph] = true
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("字体缺少可识别的Unicode cmap子表")
+	}
+	return glyphs, nil
+}
+
+// cmapFormat4Lookup 在格式4子表中查找字符的字形编号
+func cmapFormat4Lookup(subtable []byte, char rune) uint16 {
+	if char > 0xFFFF || len(subtable) < 14 {
+		return 0
+	}
+	segCount := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + segCount*2 + 2
+	idDeltas := startCodes + segCount*2
+	idRangeOffsets := idDeltas + segCount*2
+	if len(subtable) < idRangeOffsets+segCount*2 {
+		return 0
+	}
+
+	code := uint16(char)
+	for i := 0; i < segCount; i++ {
+		if code > binary.BigEndian.Uint16(subtable[endCodes+i*2:]) {
+			continue
+		}
+		start := binary.BigEndian.Uint16(subtable[startCodes+i*2:])
+		if code < start {
+			return 0
+		}
+		delta := binary.BigEndian.Uint16(subtable[idDeltas+i*2:])
+		rangeOffset := int(binary.BigEndian.Uint16(subtable[idRangeOffsets+i*2:]))
+		if rangeOffset == 0 {
+			return code + delta
+		}
+		position := idRangeOffsets + i*2 + rangeOffset + int(code-start)*2
+		if position+2 > len(subtable) {
+			return 0
+		}
+		if glyph := binary.BigEndian.Uint16(subtable[position:]); glyph != 0 {
+			return glyph + delta
+		}
+		return 0
+	}
+	return 0
+}
+
+// subsetTrueType 生成只包含指定字符字形的TrueType字体
+//
+// 未使用字形的轮廓数据被清空，字形编号保持不变。CFF轮廓的OpenType字体不支持子集化。
+func subsetTrueType(data []byte, chars map[rune]bool) ([]byte, error) {
+	font, err := parseSFNT(data)
+	if err != nil {
+		return nil, err
+	}
+	if font.version == sfntVersionCFF {
+		return nil, fmt.Errorf("CFF轮廓字体不支持子集化")
+	}
+	head, maxp := font.table("head"), font.table("maxp")
+	loca, glyf := font.table("loca"), font.table("glyf")
+	if len(head) < 54 || len(maxp) < 6 || loca == nil || glyf == nil {
+		return nil, fmt.Errorf("字体缺少字形表")
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	longOffsets := binary.BigEndian.Uint16(head[50:]) == 1
+	offsets, err := parseLoca(loca, numGlyphs, longOffsets, len(glyf))
+	if err != nil {
+		return nil, err
+	}
+
+	keep, err := font.glyphIndices(chars)
+	if err != nil {
+		return nil, err
+	}
+	keep[0] = true // .notdef
+
+	// 加入复合字形引用的组件字形
+	pending := make([]uint16, 0, len(keep))
+	for glyph := range keep {
+		pending = append(pending, glyph)
+	}
+	for len(pending) > 0 {
+		glyph := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if int(glyph) >= numGlyphs {
+			continue
+		}
+		for _, component := range compositeComponents(glyf[offsets[glyph]:offsets[glyph+1]]) {
+			if !keep[component] {
+				keep[component] = true
+				pending = append(pending, component)
+			}
+		}
+	}
+
+	var newGlyf bytes.Buffer
+	newOffsets := make([]int, numGlyphs+1)
+	for glyph := 0; glyph < numGlyphs; glyph++ {
+		newOffsets[glyph] = newGlyf.Len()
+		if keep[uint16(glyph)] {
+			newGlyf.Write(glyf[offsets[glyph]:offsets[glyph+1]])
+			for newGlyf.Len()%4 != 0 {
+				newGlyf.WriteByte(0)
+			}
+		}
+	}
+	newOffsets[numGlyphs] = newGlyf.Len()
+
+	var newLoca []byte
+	if longOffsets {
+		newLoca = make([]byte, (numGlyphs+1)*4)
+		for i, offset := range newOffsets {
+			binary.BigEndian.PutUint32(newLoca[i*4:], uint32(offset))
+		}
+	} else {
+		newLoca = make([]byte, (numGlyphs+1)*2)
+		for i, offset := range newOffsets {
+			binary.BigEndian.PutUint16(newLoca[i*2:], uint16(offset/2))
+		}
+	}
+
+	subset := &sfntFont{version: font.version}
+	for _, t := range font.tables {
+		switch t.tag {
+		case "glyf":
+			subset.tables = append(subset.tables, &sfntTable{tag: t.tag, data: newGlyf.Bytes()})
+		case "loca":
+			subset.tables = append(subset.tables, &sfntTable{tag: t.tag, data: newLoca})
+		case "DSIG":
+			// 子集化后原数字签名失效
+		default:
+			subset.tables = append(subset.tables, t)
+		}
+	}
+	return subset.bytes(), nil
+}
+
+// parseLoca 解析字形偏移表，返回 numGlyphs+1 个偏移
+func parseLoca(loca []byte, numGlyphs int, longOffsets bool, glyfLength int) ([]int, error) {
+	size := 2
+	if longOffsets {
+		size = 4
+	}
+	if len(loca) < (numGlyphs+1)*size {
+		return nil, fmt.Errorf("loca表不完整")
+	}
+	offsets := make([]int, numGlyphs+1)
+	for i := range offsets {
+		if longOffsets {
+			offsets[i] = int(binary.BigEndian.Uint32(loca[i*4:]))
+		} else {
+			offsets[i] = int(binary.BigEndian.Uint16(loca[i*2:])) * 2
+		}
+		if offsets[i] > glyfLength || (i > 0 && offsets[i] < offsets[i-1]) {
+			return nil, fmt.Errorf("loca表偏移无效")
+		}
+	}
+	return offsets, nil
+}
+
+// compositeComponents 返回复合字形引用的组件字形编号，简单字形返回nil
+func compositeComponents(glyph []byte) []uint16 {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+
+	const (
+		argsAreWords   = 0x0001
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		haveTwoByTwo   = 0x0080
+	)
+	var components []uint16
+	for pos := 10; pos+4 <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[pos:])
+		components = append(components, binary.BigEndian.Uint16(glyph[pos+2:]))
+		pos += 4
+		if flags&argsAreWords != 0 {
+			pos += 4
+		} else {
+			pos += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			pos += 2
+		case flags&haveXYScale != 0:
+			pos += 4
+		case flags&haveTwoByTwo != 0:
+			pos += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return components
+}
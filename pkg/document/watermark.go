@@ -0,0 +1,533 @@
+// Package document 水印功能实现
+package document
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WatermarkType 水印类型
+type WatermarkType string
+
+const (
+	// WatermarkTypeText 文字水印
+	WatermarkTypeText WatermarkType = "text"
+	// WatermarkTypeImage 图片水印
+	WatermarkTypeImage WatermarkType = "image"
+)
+
+const (
+	// textWatermarkShapeID Word文字水印形状的ID前缀
+	textWatermarkShapeID = "PowerPlusWaterMarkObject"
+	// imageWatermarkShapeID Word图片水印形状的ID前缀
+	imageWatermarkShapeID = "WordPictureWatermark"
+
+	// 默认水印参数
+	defaultWatermarkFont  = "宋体"
+	defaultWatermarkColor = "silver"
+	// defaultWatermarkWidth 无法确定版心宽度时使用的水印宽度（磅，A4默认页边距）
+	defaultWatermarkWidth = 415.0
+)
+
+// Watermark 文档中已有水印的信息
+type Watermark struct {
+	Type         WatermarkType // 水印类型
+	Text         string        // 文字水印内容
+	Font         string        // 文字水印字体
+	Color        string        // 文字水印颜色（如 silver、#FF0000）
+	Rotation     int           // 旋转角度（0-359，315为斜向）
+	Transparency float64       // 透明度（0-1）
+	ImageData    []byte        // 图片水印数据
+	Washout      bool          // 图片水印是否冲蚀
+}
+
+// SetTextWatermark 设置文字水印
+//
+// 水印以Word标准的艺术字形状写入各节的页眉，位于正文文字下方。
+// 参数说明：
+//   - text: 水印文字，如 "DRAFT"、"机密"
+//   - font: 字体名称，为空时使用宋体
+//   - color: 颜色，支持 silver 等颜色名称或 "FF0000"/"#FF0000"，为空时使用 silver
+//   - size: 字号（磅），为0时自动适应版心宽度
+//   - rotation: 旋转角度，315（或-45）为斜向，0为水平
+//   - transparency: 透明度（0-1），0.5为半透明
+//
+// 已有的水印（文字或图片）会被替换。文档中不存在的页眉会自动创建，
+// 包括设置了首页不同或奇偶页不同时的首页页眉和偶数页页眉。
+//
+// 示例:
+//
+//	doc.SetTextWatermark("机密", "宋体", "C0C0C0", 0, 315, 0.5)
+func (d *Document) SetTextWatermark(text, font, color string, size float64, rotation int, transparency float64) error {
+	if strings.TrimSpace(text) == "" {
+		return NewValidationError("watermark_text", text, "水印文字不能为空")
+	}
+	if size < 0 {
+		return NewValidationError("watermark_size", fmt.Sprintf("%g", size), "字号不能为负数")
+	}
+	if transparency < 0 || transparency >= 1 {
+		return NewValidationError("watermark_transparency", fmt.Sprintf("%g", transparency), "透明度必须在0到1之间")
+	}
+	if font == "" {
+		font = defaultWatermarkFont
+	}
+	color = normalizeWatermarkColor(color)
+	rotation = ((rotation % 360) + 360) % 360
+
+	// 计算形状尺寸：文字水印通过 fitshape 缩放以填满形状
+	units := watermarkTextUnits(text)
+	width, height := 0.0, 0.0
+	if size > 0 {
+		width, height = units*size, size
+	} else {
+		width = d.watermarkContentWidth()
+		height = width / units
+	}
+
+	headers := d.watermarkHeaders(true)
+	for i, header := range headers {
+		vml := buildTextWatermarkVML(i+1, text, font, color, width, height, rotation, transparency)
+		insertWatermark(&header.headerFooterContent, vml)
+	}
+
+	Infof("已设置文字水印: %s（%d个页眉）", text, len(headers))
+	return nil
+}
+
+// SetImageWatermark 设置图片水印
+//
+// scale 为缩放比例（1为原始大小），为0时自动适应版心宽度；
+// washout 为真时使用Word的"冲蚀"效果淡化图片。已有的水印会被替换。
+func (d *Document) SetImageWatermark(data []byte, scale float64, washout bool) error {
+	if len(data) == 0 {
+		return NewValidationError("watermark_image", "", "图片数据不能为空")
+	}
+	if scale < 0 {
+		return NewValidationError("watermark_scale", fmt.Sprintf("%g", scale), "缩放比例不能为负数")
+	}
+
+	format, err := detectImageFormat(data)
+	if err != nil {
+		return WrapError("detect_image_format", err)
+	}
+	pixelWidth, pixelHeight, err := getImageDimensions(data, format)
+	if err != nil {
+		return WrapError("get_image_dimensions", err)
+	}
+	if pixelWidth <= 0 || pixelHeight <= 0 {
+		return NewValidationError("watermark_image", "", "无法获取图片尺寸")
+	}
+
+	// 像素按96DPI换算为磅
+	width := float64(pixelWidth) * 0.75 * scale
+	height := float64(pixelHeight) * 0.75 * scale
+	if scale == 0 {
+		width = d.watermarkContentWidth()
+		height = width * float64(pixelHeight) / float64(pixelWidth)
+	}
+
+	headers := d.watermarkHeaders(true)
+	_, mediaFileName := d.addMediaPart(data, "watermark."+string(format), format)
+	for i, header := range headers {
+		relationID := header.addImageRelationship(mediaFileName)
+		vml := buildImageWatermarkVML(i+1, relationID, width, height, washout)
+		insertWatermark(&header.headerFooterContent, vml)
+	}
+
+	Infof("已设置图片水印（%d个页眉）", len(headers))
+	return nil
+}
+
+// RemoveWatermark 移除文档所有页眉中的水印
+func (d *Document) RemoveWatermark() error {
+	removed := 0
+	for _, header := range d.watermarkHeaders(false) {
+		if _, loaded := d.headers[header.partName]; !loaded {
+			if removeWatermarkRuns(&header.headerFooterContent) == 0 {
+				continue
+			}
+			// 临时解析的页眉包含水印时加入缓存，移除结果在保存时写回
+			if header = d.headerForPart(header.partName); header == nil {
+				continue
+			}
+		}
+		removed += removeWatermarkRuns(&header.headerFooterContent)
+	}
+	Infof("已移除 %d 个水印", removed)
+	return nil
+}
+
+// HasWatermark 检查文档是否包含水印
+func (d *Document) HasWatermark() bool {
+	return d.GetWatermark() != nil
+}
+
+// GetWatermark 获取文档中的水印信息（打开的文档同样适用），没有水印时返回nil
+func (d *Document) GetWatermark() *Watermark {
+	for _, header := range d.watermarkHeaders(false) {
+		for _, paragraph := range header.GetParagraphs() {
+			for _, run := range paragraph.Runs {
+				if !isWatermarkRun(&run) {
+					continue
+				}
+				watermark, relationID := parseWatermarkVML(run.Pict.Content)
+				if watermark == nil {
+					continue
+				}
+				if watermark.Type == WatermarkTypeImage {
+					watermark.ImageData = d.headerImageData(header, relationID)
+				}
+				return watermark
+			}
+		}
+	}
+	return nil
+}
+
+// headerImageData 根据页眉关系ID读取图片数据
+func (d *Document) headerImageData(header *Header, relationID string) []byte {
+	if header.relationships == nil {
+		return nil
+	}
+	for _, rel := range header.relationships.Relationships {
+		if rel.ID == relationID {
+			return d.parts["word/"+strings.TrimPrefix(rel.Target, "/word/")]
+		}
+	}
+	return nil
+}
+
+// watermarkHeaders 获取承载水印的所有页眉
+//
+// 后续节未设置的页眉会继承前一节，因此只需为第一节补充缺失的页眉。
+// create 为真时创建缺失的页眉（默认页眉，以及首页不同、奇偶页不同时的首页和偶数页页眉），
+// 返回的页眉加入缓存；为假时未加载的页眉临时解析，不加入缓存。
+func (d *Document) watermarkHeaders(create bool) []*Header {
+	sections := d.allSectionProperties()
+	if len(sections) == 0 {
+		if !create {
+			return nil
+		}
+		sections = []*SectionProperties{d.getSectionPropertiesForHeaderFooter()}
+	}
+
+	if create {
+		required := []HeaderFooterType{HeaderFooterTypeDefault}
+		if sections[0].TitlePage != nil {
+			required = append(required, HeaderFooterTypeFirst)
+		}
+		if d.Settings().EvenAndOddHeaders {
+			required = append(required, HeaderFooterTypeEven)
+		}
+		for _, headerType := range required {
+			if !hasHeaderReference(sections[0], headerType) {
+				d.createEmptyHeader(sections[0], headerType)
+			}
+		}
+	}
+
+	var headers []*Header
+	seen := make(map[string]bool)
+	for _, sectPr := range sections {
+		for _, ref := range sectPr.HeaderReferences {
+			partName := d.relationshipPartName(ref.ID)
+			if partName == "" || seen[partName] {
+				continue
+			}
+			seen[partName] = true
+			var header *Header
+			if create {
+				header = d.headerForPart(partName)
+			} else {
+				header = d.peekHeader(partName)
+			}
+			if header != nil {
+				headers = append(headers, header)
+			}
+		}
+	}
+	return headers
+}
+
+// allSectionProperties 按文档顺序获取所有节属性（段落级节属性和主体级节属性）
+func (d *Document) allSectionProperties() []*SectionProperties {
+	var sections []*SectionProperties
+	var bodySectPr *SectionProperties
+	for _, element := range d.Body.Elements {
+		switch e := element.(type) {
+		case *Paragraph:
+			if e.Properties != nil && e.Properties.SectionProperties != nil {
+				sections = append(sections, e.Properties.SectionProperties)
+			}
+		case *SectionProperties:
+			bodySectPr = e
+		}
+	}
+	if bodySectPr != nil {
+		sections = append(sections, bodySectPr)
+	}
+	return sections
+}
+
+// hasHeaderReference 检查节属性是否包含指定类型的页眉引用
+func hasHeaderReference(sectPr *SectionProperties, headerType HeaderFooterType) bool {
+	for _, ref := range sectPr.HeaderReferences {
+		if ref.Type == string(headerType) {
+			return true
+		}
+	}
+	return false
+}
+
+// createEmptyHeader 为指定节创建空页眉
+func (d *Document) createEmptyHeader(sectPr *SectionProperties, headerType HeaderFooterType) *Header {
+	partName, relationID := d.newHeaderFooterPart("header", headerType, true)
+	header := createStandardHeader()
+	d.cacheHeader(partName, header)
+	if err := d.serializeHeaderFooterPart(partName, header, nil); err != nil {
+		Warnf("序列化页眉失败 %s: %v", partName, err)
+	}
+	d.addContentType(partName, "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml")
+	addHeaderReferenceTo(sectPr, headerType, relationID)
+	Debugf("为水印创建页眉: %s (%s)", partName, headerType)
+	return header
+}
+
+// watermarkContentWidth 获取最后一节的版心宽度（磅）
+func (d *Document) watermarkContentWidth() float64 {
+	sectPr := d.getSectionProperties()
+	if sectPr.PageSize == nil || sectPr.PageMargins == nil {
+		return defaultWatermarkWidth
+	}
+	pageWidth, err1 := strconv.Atoi(sectPr.PageSize.W)
+	left, err2 := strconv.Atoi(sectPr.PageMargins.Left)
+	right, err3 := strconv.Atoi(sectPr.PageMargins.Right)
+	if err1 != nil || err2 != nil || err3 != nil || pageWidth-left-right <= 0 {
+		return defaultWatermarkWidth
+	}
+	return float64(pageWidth-left-right) / 20.0
+}
+
+// watermarkTextUnits 估算文字宽度（以字号为单位），中日韩字符按全角计算
+func watermarkTextUnits(text string) float64 {
+	units := 0.0
+	for _, r := range text {
+		if r > 0x2E7F {
+			units += 1
+		} else {
+			units += 0.6
+		}
+	}
+	if units == 0 {
+		return 1
+	}
+	return units
+}
+
+// normalizeWatermarkColor 规范化颜色值，十六进制颜色添加#前缀
+func normalizeWatermarkColor(color string) string {
+	color = strings.TrimSpace(color)
+	if color == "" {
+		return defaultWatermarkColor
+	}
+	if len(color) == 6 {
+		if _, err := strconv.ParseUint(color, 16, 32); err == nil {
+			return "#" + strings.ToUpper(color)
+		}
+	}
+	return color
+}
+
+// isWatermarkRun 检查运行是否为水印形状
+func isWatermarkRun(run *Run) bool {
+	if run.Pict == nil {
+		return false
+	}
+	return bytes.Contains(run.Pict.Content, []byte(textWatermarkShapeID)) ||
+		bytes.Contains(run.Pict.Content, []byte(imageWatermarkShapeID))
+}
+
+// insertWatermark 移除已有水印并在页眉第一个段落开头插入新的水印
+func insertWatermark(content *headerFooterContent, vml string) {
+	removeWatermarkRuns(content)
+
+	run := Run{Pict: &Pict{Content: []byte(vml)}}
+	if len(content.Elements) > 0 {
+		if paragraph, ok := content.Elements[0].(*Paragraph); ok {
+			paragraph.Runs = append([]Run{run}, paragraph.Runs...)
+			return
+		}
+	}
+	paragraph := &Paragraph{Runs: []Run{run}}
+	content.Elements = append([]interface{}{paragraph}, content.Elements...)
+}
+
+// removeWatermarkRuns 移除页眉中的水印形状，返回移除的数量
+func removeWatermarkRuns(content *headerFooterContent) int {
+	removed := 0
+	for _, paragraph := range content.GetParagraphs() {
+		runs := paragraph.Runs[:0]
+		for _, run := range paragraph.Runs {
+			if isWatermarkRun(&run) {
+				removed++
+				continue
+			}
+			runs = append(runs, run)
+		}
+		paragraph.Runs = runs
+	}
+	return removed
+}
+
+// watermarkShapeStyle 水印形状的定位样式（相对版心居中，位于文字下方）
+func watermarkShapeStyle(width, height float64, rotation int, zIndex int) string {
+	style := fmt.Sprintf("position:absolute;margin-left:0;margin-top:0;width:%spt;height:%spt;", formatPoints(width), formatPoints(height))
+	if rotation != 0 {
+		style += fmt.Sprintf("rotation:%d;", rotation)
+	}
+	style += fmt.Sprintf("z-index:%d;mso-position-horizontal:center;mso-position-horizontal-relative:margin;"+
+		"mso-position-vertical:center;mso-position-vertical-relative:margin", zIndex)
+	return style
+}
+
+// formatPoints 格式化磅值
+func formatPoints(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// escapeXMLAttr 转义XML属性值
+func escapeXMLAttr(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+// buildTextWatermarkVML 构建Word标准的文字水印（艺术字）VML
+func buildTextWatermarkVML(index int, text, font, color string, width, height float64, rotation int, transparency float64) string {
+	width = float64(int(width*100)) / 100
+	height = float64(int(height*100)) / 100
+
+	var b strings.Builder
+	b.WriteString(`<v:shapetype id="_x0000_t136" coordsize="21600,21600" o:spt="136" adj="10800" path="m@7,l@8,m@5,21600l@6,21600e">`)
+	b.WriteString(`<v:formulas><v:f eqn="sum #0 0 10800"/><v:f eqn="prod #0 2 1"/><v:f eqn="sum 21600 0 @1"/><v:f eqn="sum 0 0 @2"/>`)
+	b.WriteString(`<v:f eqn="sum 21600 0 @3"/><v:f eqn="if @0 @3 0"/><v:f eqn="if @0 21600 @1"/><v:f eqn="if @0 0 @2"/>`)
+	b.WriteString(`<v:f eqn="if @0 @4 21600"/><v:f eqn="mid @5 @6"/><v:f eqn="mid @8 @5"/><v:f eqn="mid @7 @8"/>`)
+	b.WriteString(`<v:f eqn="mid @6 @7"/><v:f eqn="sum @6 0 @5"/></v:formulas>`)
+	b.WriteString(`<v:path textpathok="t" o:connecttype="custom" o:connectlocs="@9,0;@10,10800;@11,21600;@12,10800" o:connectangles="270,180,90,0"/>`)
+	b.WriteString(`<v:textpath on="t" fitshape="t"/><v:handles><v:h position="#0,bottomRight" xrange="6629,14971"/></v:handles>`)
+	b.WriteString(`<o:lock v:ext="edit" text="t" shapetype="t"/></v:shapetype>`)
+
+	fmt.Fprintf(&b, `<v:shape id="%s%d" o:spid="_x0000_s%d" type="#_x0000_t136" style="%s" o:allowincell="f" fillcolor="%s" stroked="f">`,
+		textWatermarkShapeID, index, 2048+index, watermarkShapeStyle(width, height, rotation, -251657216+index), escapeXMLAttr(color))
+	if transparency > 0 {
+		fmt.Fprintf(&b, `<v:fill opacity="%s"/>`, strconv.FormatFloat(1-transparency, 'f', -1, 64))
+	}
+	fmt.Fprintf(&b, `<v:textpath style="%s" string="%s"/>`,
+		escapeXMLAttr(fmt.Sprintf(`font-family:"%s";font-size:1pt`, font)), escapeXMLAttr(text))
+	b.WriteString(`<w10:wrap anchorx="margin" anchory="margin"/></v:shape>`)
+	return b.String()
+}
+
+// buildImageWatermarkVML 构建Word标准的图片水印VML
+func buildImageWatermarkVML(index int, relationID string, width, height float64, washout bool) string {
+	width = float64(int(width*100)) / 100
+	height = float64(int(height*100)) / 100
+
+	var b strings.Builder
+	b.WriteString(`<v:shapetype id="_x0000_t75" coordsize="21600,21600" o:spt="75" o:preferrelative="t" path="m@4@5l@4@11@9@11@9@5xe" filled="f" stroked="f">`)
+	b.WriteString(`<v:stroke joinstyle="miter"/><v:formulas><v:f eqn="if lineDrawn pixelLineWidth 0"/><v:f eqn="sum @0 1 0"/>`)
+	b.WriteString(`<v:f eqn="sum 0 0 @1"/><v:f eqn="prod @2 1 2"/><v:f eqn="prod @3 21600 pixelWidth"/><v:f eqn="prod @3 21600 pixelHeight"/>`)
+	b.WriteString(`<v:f eqn="sum @0 0 1"/><v:f eqn="prod @6 1 2"/><v:f eqn="prod @7 21600 pixelWidth"/><v:f eqn="sum @8 21600 0"/>`)
+	b.WriteString(`<v:f eqn="prod @7 21600 pixelHeight"/><v:f eqn="sum @10 21600 0"/></v:formulas>`)
+	b.WriteString(`<v:path o:extrusionok="f" gradientshapeok="t" o:connecttype="rect"/><o:lock v:ext="edit" aspectratio="t"/></v:shapetype>`)
+
+	fmt.Fprintf(&b, `<v:shape id="%s%d" o:spid="_x0000_s%d" type="#_x0000_t75" style="%s" o:allowincell="f">`,
+		imageWatermarkShapeID, index, 2048+index, watermarkShapeStyle(width, height, 0, -251656192+index))
+	if washout {
+		fmt.Fprintf(&b, `<v:imagedata r:id="%s" o:title="" gain="19661f" blacklevel="22938f"/>`, relationID)
+	} else {
+		fmt.Fprintf(&b, `<v:imagedata r:id="%s" o:title=""/>`, relationID)
+	}
+	b.WriteString(`</v:shape>`)
+	return b.String()
+}
+
+// parseWatermarkVML 从VML内容中解析水印信息，图片水印同时返回图片的关系ID
+func parseWatermarkVML(content []byte) (*Watermark, string) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var watermark *Watermark
+	relationID := ""
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			Warnf("解析水印形状失败: %v", err)
+			return watermark, relationID
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "shape":
+			id := getAttributeValue(start.Attr, "id")
+			style := getAttributeValue(start.Attr, "style")
+			switch {
+			case strings.HasPrefix(id, textWatermarkShapeID):
+				watermark = &Watermark{
+					Type:     WatermarkTypeText,
+					Color:    getAttributeValue(start.Attr, "fillcolor"),
+					Rotation: parseVMLStyleInt(style, "rotation"),
+				}
+			case strings.HasPrefix(id, imageWatermarkShapeID):
+				watermark = &Watermark{Type: WatermarkTypeImage}
+			}
+		case "fill":
+			if watermark != nil {
+				if opacity, err := strconv.ParseFloat(strings.TrimSuffix(getAttributeValue(start.Attr, "opacity"), "f"), 64); err == nil {
+					watermark.Transparency = 1 - opacity
+				}
+			}
+		case "textpath":
+			if watermark != nil && watermark.Type == WatermarkTypeText {
+				watermark.Text = getAttributeValue(start.Attr, "string")
+				watermark.Font = strings.Trim(parseVMLStyleValue(getAttributeValue(start.Attr, "style"), "font-family"), `"'`)
+			}
+		case "imagedata":
+			if watermark != nil && watermark.Type == WatermarkTypeImage {
+				relationID = getAttributeValue(start.Attr, "id")
+				watermark.Washout = getAttributeValue(start.Attr, "gain") != ""
+			}
+		}
+	}
+	return watermark, relationID
+}
+
+// parseVMLStyleValue 获取VML样式字符串中指定属性的值
+func parseVMLStyleValue(style, name string) string {
+	for _, item := range strings.Split(style, ";") {
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == name {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// parseVMLStyleInt 获取VML样式字符串中指定属性的整数值
+func parseVMLStyleInt(style, name string) int {
+	value, err := strconv.Atoi(parseVMLStyleValue(style, name))
+	if err != nil {
+		return 0
+	}
+	return value
+}
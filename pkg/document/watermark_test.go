@@ -0,0 +1,138 @@
+// Package document 水印功能测试
+package document
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTextWatermark 测试文字水印的设置、检测和移除
+func TestTextWatermark(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+	if err := doc.AddHeader(HeaderFooterTypeDefault, "页眉"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	doc.SetDifferentFirstPage(true)
+
+	if err := doc.SetTextWatermark("", "", "", 0, 315, 0.5); err == nil {
+		t.Error("空水印文字应返回错误")
+	}
+	if err := doc.SetTextWatermark("机密", "黑体", "FF0000", 0, -45, 0.5); err != nil {
+		t.Fatalf("设置文字水印失败: %v", err)
+	}
+	if doc.GetHeader(HeaderFooterTypeFirst) == nil {
+		t.Error("设置首页不同时应创建首页页眉")
+	}
+
+	filename := filepath.Join(t.TempDir(), "watermark.docx")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	opened, err := Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+
+	watermark := opened.GetWatermark()
+	if watermark == nil {
+		t.Fatal("打开的文档应检测到水印")
+	}
+	if !opened.HasWatermark() || len(opened.headers) != 0 {
+		t.Errorf("读取水印不应缓存页眉，已缓存 %d 个", len(opened.headers))
+	}
+	if watermark.Type != WatermarkTypeText || watermark.Text != "机密" || watermark.Font != "黑体" {
+		t.Errorf("水印信息不正确: %+v", watermark)
+	}
+	if watermark.Color != "#FF0000" || watermark.Rotation != 315 || watermark.Transparency != 0.5 {
+		t.Errorf("水印样式不正确: %+v", watermark)
+	}
+	for _, headerType := range []HeaderFooterType{HeaderFooterTypeDefault, HeaderFooterTypeFirst} {
+		header := opened.GetHeader(headerType)
+		if header == nil || len(header.GetParagraphs()) == 0 || !isWatermarkRun(&header.GetParagraphs()[0].Runs[0]) {
+			t.Errorf("%s 页眉应包含水印", headerType)
+		}
+	}
+	if text := opened.GetHeader(HeaderFooterTypeDefault).GetText(); text != "页眉" {
+		t.Errorf("原有页眉文字应保留，实际为: %q", text)
+	}
+
+	if err := opened.RemoveWatermark(); err != nil {
+		t.Fatalf("移除水印失败: %v", err)
+	}
+	if opened.HasWatermark() {
+		t.Error("移除后不应再检测到水印")
+	}
+
+	// 未加载页眉的文档移除水印后保存
+	opened, err = Open(filename)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if err := opened.RemoveWatermark(); err != nil {
+		t.Fatalf("移除水印失败: %v", err)
+	}
+	removedFile := filepath.Join(t.TempDir(), "removed.docx")
+	if err := opened.Save(removedFile); err != nil {
+		t.Fatalf("保存文档失败: %v", err)
+	}
+	reopened, err := Open(removedFile)
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	if reopened.HasWatermark() {
+		t.Error("移除的水印应在保存后生效")
+	}
+}
+
+// TestImageWatermark 测试图片水印
+func TestImageWatermark(t *testing.T) {
+	doc := New()
+	doc.AddParagraph("正文")
+	doc.Settings().EvenAndOddHeaders = true
+
+	imageData := createTestImage(200, 100)
+	if err := doc.SetImageWatermark(imageData, 0, true); err != nil {
+		t.Fatalf("设置图片水印失败: %v", err)
+	}
+	if doc.GetHeader(HeaderFooterTypeDefault) == nil || doc.GetHeader(HeaderFooterTypeEven) == nil {
+		t.Fatal("应创建默认页眉和偶数页页眉")
+	}
+
+	data, err := doc.ToBytes()
+	if err != nil {
+		t.Fatalf("序列化文档失败: %v", err)
+	}
+	opened, err := OpenFromMemory(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("打开文档失败: %v", err)
+	}
+	watermark := opened.GetWatermark()
+	if watermark == nil || watermark.Type != WatermarkTypeImage || !watermark.Washout {
+		t.Fatalf("图片水印信息不正确: %+v", watermark)
+	}
+	if !bytes.Equal(watermark.ImageData, imageData) {
+		t.Error("应能读取水印图片数据")
+	}
+
+	// 替换为文字水印时移除原有图片水印
+	if err := opened.SetTextWatermark("DRAFT", "", "", 48, 0, 0); err != nil {
+		t.Fatalf("设置文字水印失败: %v", err)
+	}
+	header := opened.GetHeader(HeaderFooterTypeEven)
+	count := 0
+	for _, run := range header.GetParagraphs()[0].Runs {
+		if isWatermarkRun(&run) {
+			count++
+			if !strings.Contains(string(run.Pict.Content), textWatermarkShapeID) {
+				t.Error("图片水印应被替换为文字水印")
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("页眉中应只有一个水印，实际为: %d", count)
+	}
+}
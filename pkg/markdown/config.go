@@ -17,6 +17,10 @@ type ConvertOptions struct {
 	DefaultFontFamily string            // 默认字体
 	DefaultFontSize   float64           // 默认字号
 
+	// 参考文档（类似 pandoc 的 --reference-doc）
+	ReferenceDocument string                       // 参考文档路径（.docx/.dotx），转换后导入其样式、编号、主题、页面设置和页眉页脚
+	ReferenceOptions  *document.ApplyStylesOptions // 导入参考文档的选项，为nil时全部导入
+
 	// 图片处理
 	ImageBasePath string  // 图片基础路径
 	EmbedImages   bool    // 是否嵌入图片
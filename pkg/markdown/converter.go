@@ -102,6 +102,17 @@ func (c *Converter) ConvertBytes(content []byte, opts *ConvertOptions) (*documen
 		return nil, err
 	}
 
+	// 渲染完成后导入参考文档的格式，正文中的样式引用随之更新
+	if c.opts.ReferenceDocument != "" {
+		ref, err := document.Open(c.opts.ReferenceDocument)
+		if err != nil {
+			return nil, NewConversionError("ReferenceDocument", "failed to open reference document", 0, 0, err)
+		}
+		if err := doc.ApplyStylesFrom(ref, c.opts.ReferenceOptions); err != nil {
+			return nil, NewConversionError("ReferenceDocument", "failed to apply reference document styles", 0, 0, err)
+		}
+	}
+
 	return doc, nil
 }
 
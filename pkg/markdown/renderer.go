@@ -193,12 +193,10 @@ func (r *WordRenderer) renderInlineContent(node ast.Node, para *document.Paragra
 			para.AddFormattedText(text, format)
 
 		case *ast.Link:
-			text := r.extractTextContent(n)
-			// 简单处理链接，后续可以扩展为超链接
-			format := &document.TextFormat{
-				FontColor: "0000FF", // 蓝色
-			}
-			para.AddFormattedText(text, format)
+			r.renderLinkToParagraph(string(n.Destination), r.extractTextContent(n), para)
+
+		case *ast.AutoLink:
+			r.renderLinkToParagraph(string(n.URL(r.source)), string(n.Label(r.source)), para)
 
 		case *ast.Image:
 			r.renderImageInline(n, para)
@@ -630,11 +628,9 @@ func (r *WordRenderer) renderTaskItemContent(parent ast.Node, para *document.Par
 			}
 			para.AddFormattedText(text, format)
 		case *ast.Link:
-			text := r.extractTextContent(n)
-			format := &document.TextFormat{
-				FontColor: "0000FF", // 蓝色
-			}
-			para.AddFormattedText(text, format)
+			r.renderLinkToParagraph(string(n.Destination), r.extractTextContent(n), para)
+		case *ast.AutoLink:
+			r.renderLinkToParagraph(string(n.URL(r.source)), string(n.Label(r.source)), para)
 		default:
 			// 检查是否为行内数学公式
 			if r.opts.EnableMath && child.Kind() == mathjax.KindInlineMath {
@@ -650,6 +646,14 @@ func (r *WordRenderer) renderTaskItemContent(parent ast.Node, para *document.Par
 	}
 }
 
+// renderLinkToParagraph 将链接渲染为可点击的超链接，使用Word默认的超链接颜色和下划线
+func (r *WordRenderer) renderLinkToParagraph(destination, text string, para *document.Paragraph) {
+	para.AddHyperlink(destination, text, &document.TextFormat{
+		FontColor: "0563C1",
+		Underline: true,
+	})
+}
+
 // renderInlineMathToParagraph 将行内数学公式渲染到段落中
 func (r *WordRenderer) renderInlineMathToParagraph(node ast.Node, para *document.Paragraph) {
 	latex := r.extractMathContent(node)
@@ -0,0 +1,12 @@
+package markdown
+
+import "github.com/zerx-lab/wordZero/pkg/document"
+
+// 注册模板引擎的Markdown渲染函数，使 TemplateData.SetMarkdown 设置的变量按Markdown插入模板
+func init() {
+	document.RegisterTemplateMarkdownRenderer(func(content string) (*document.Document, error) {
+		opts := DefaultOptions()
+		opts.GenerateTOC = false
+		return NewConverter(opts).ConvertString(content, opts)
+	})
+}
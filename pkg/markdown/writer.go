@@ -62,6 +62,8 @@ type MarkdownWriter struct {
 	output    strings.Builder
 	imageNum  int
 	footnotes []string
+	labels    map[*document.Paragraph]string      // 列表段落的编号标签
+	lists     map[string]*document.ListDefinition // 按编号实例ID索引的列表定义
 }
 
 // Write 生成Markdown内容
@@ -73,6 +75,11 @@ func (w *MarkdownWriter) Write() ([]byte, error) {
 
 	// 遍历文档段落
 	if w.doc.Body != nil {
+		w.labels = w.doc.GetListLabels()
+		w.lists = make(map[string]*document.ListDefinition)
+		for _, definition := range w.doc.GetListDefinitions() {
+			w.lists[definition.NumID] = definition
+		}
 		for _, para := range w.doc.Body.GetParagraphs() {
 			err := w.writeParagraph(para)
 			if err != nil {
@@ -203,13 +210,19 @@ func (w *MarkdownWriter) writeListItem(para *document.Paragraph) error {
 		return nil
 	}
 
-	// 简单的列表项处理
+	// 按列表级别缩进，并使用文档中实际渲染的编号
+	indent := strings.Repeat("  ", w.getListLevel(para))
 	marker := w.opts.BulletListMarker
-	if w.isNumberedList(para) {
-		marker = "1."
+	if label := w.labels[para]; w.isNumberedList(para) && label != "" {
+		if orderedListMarker.MatchString(label) {
+			marker = label
+		} else {
+			// 多级编号（如 2.1.a）不是合法的Markdown有序列表标记，保留为文本
+			text = label + " " + text
+		}
 	}
 
-	w.output.WriteString(marker + " " + text + "\n")
+	w.output.WriteString(indent + marker + " " + text + "\n")
 
 	return nil
 }
@@ -404,18 +417,39 @@ func (w *MarkdownWriter) getHeadingLevel(style string) int {
 	return 1
 }
 
+// orderedListMarker 可直接作为Markdown有序列表标记的编号
+var orderedListMarker = regexp.MustCompile(`^\d+[.)]$`)
+
 // isListParagraph 判断是否为列表段落
 func (w *MarkdownWriter) isListParagraph(para *document.Paragraph) bool {
-	if para.Properties == nil {
+	if para.Properties == nil || para.Properties.NumberingProperties == nil {
 		return false
 	}
-	return para.Properties.NumberingProperties != nil
+	numPr := para.Properties.NumberingProperties
+	return numPr.NumID != nil && numPr.NumID.Val != "0"
 }
 
 // isNumberedList 判断是否为编号列表
 func (w *MarkdownWriter) isNumberedList(para *document.Paragraph) bool {
-	// 简单实现，实际应该检查编号格式
-	return false
+	definition := w.lists[para.Properties.NumberingProperties.NumID.Val]
+	if definition == nil {
+		return false
+	}
+	level := definition.GetLevel(w.getListLevel(para))
+	return level != nil && level.NumFmt != nil && level.NumFmt.Val != "bullet" && level.NumFmt.Val != "none"
+}
+
+// getListLevel 获取列表段落的级别
+func (w *MarkdownWriter) getListLevel(para *document.Paragraph) int {
+	numPr := para.Properties.NumberingProperties
+	if numPr.ILevel == nil {
+		return 0
+	}
+	level, err := strconv.Atoi(numPr.ILevel.Val)
+	if err != nil {
+		return 0
+	}
+	return level
 }
 
 // isCodeStyle 判断是否为代码样式
@@ -177,6 +177,9 @@ type QuickRunConfig struct {
 	Underline bool   `json:"underline,omitempty"` // 下划线
 	Strike    bool   `json:"strike,omitempty"`    // 删除线
 	Highlight string `json:"highlight,omitempty"` // 高亮颜色
+
+	ThemeFont  string `json:"themeFont,omitempty"`  // 主题字体：major（标题字体）或 minor（正文字体）
+	ThemeColor string `json:"themeColor,omitempty"` // 主题颜色，如 accent1、text1
 }
 
 // getStyleDisplayName 获取样式显示名称
@@ -277,6 +280,24 @@ func createRunProperties(config *QuickRunConfig) *RunProperties {
 		props.Color = &Color{Val: config.FontColor}
 	}
 
+	// 主题引用
+	if config.ThemeFont != "" {
+		if props.FontFamily == nil {
+			props.FontFamily = &FontFamily{}
+		}
+		props.FontFamily.ASCIITheme = config.ThemeFont + "HAnsi"
+		props.FontFamily.HAnsiTheme = config.ThemeFont + "HAnsi"
+		props.FontFamily.EastAsiaTheme = config.ThemeFont + "EastAsia"
+		props.FontFamily.CSTheme = config.ThemeFont + "Bidi"
+	}
+	if config.ThemeColor != "" {
+		val := config.FontColor
+		if val == "" {
+			val = "auto" // 回退值，应用主题时按主题颜色更新
+		}
+		props.Color = &Color{Val: val, ThemeColor: config.ThemeColor}
+	}
+
 	// 格式设置
 	if config.Bold {
 		props.Bold = &Bold{}
@@ -1,6 +1,8 @@
 package style
 
 import (
+	"encoding/xml"
+	"strings"
 	"testing"
 )
 
@@ -300,6 +302,31 @@ func TestCreateRunProperties(t *testing.T) {
 	}
 }
 
+func TestCreateRunPropertiesWithTheme(t *testing.T) {
+	props := createRunProperties(&QuickRunConfig{ThemeFont: "major", ThemeColor: "accent1"})
+
+	if props.FontFamily == nil || props.FontFamily.EastAsiaTheme != "majorEastAsia" || props.FontFamily.CSTheme != "majorBidi" {
+		t.Errorf("主题字体设置不正确: %+v", props.FontFamily)
+	}
+	if props.Color == nil || props.Color.ThemeColor != "accent1" {
+		t.Errorf("主题颜色设置不正确: %+v", props.Color)
+	}
+
+	// 主题引用应输出到样式XML
+	sm := NewStyleManager()
+	sm.AddStyle(&Style{Type: string(StyleTypeCharacter), StyleID: "Brand", RunPr: props})
+	data, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"w:styles"`
+		Styles  []*Style `xml:"w:style"`
+	}{Styles: []*Style{sm.GetStyle("Brand")}})
+	if err != nil {
+		t.Fatalf("序列化样式失败: %v", err)
+	}
+	if !strings.Contains(string(data), `w:cstheme="majorBidi"`) {
+		t.Errorf("样式XML缺少主题字体属性: %s", data)
+	}
+}
+
 func TestCreateParagraphPropertiesWithSnapToGrid(t *testing.T) {
 	// 测试 SnapToGrid = false 时禁用网格对齐
 	snapToGridFalse := false
@@ -0,0 +1,116 @@
+// Package style 文档默认格式（docDefaults）管理
+package style
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// DocDefaults 文档默认格式（w:docDefaults）
+// 位于样式层叠的最底层，未被任何样式或直接格式设置的属性取此处的值
+type DocDefaults struct {
+	XMLName    xml.Name    `xml:"w:docDefaults"`
+	RPrDefault *RPrDefault `xml:"w:rPrDefault,omitempty"`
+	PPrDefault *PPrDefault `xml:"w:pPrDefault,omitempty"`
+}
+
+// RPrDefault 默认字符属性
+type RPrDefault struct {
+	XMLName xml.Name       `xml:"w:rPrDefault"`
+	RunPr   *RunProperties `xml:"w:rPr,omitempty"`
+}
+
+// PPrDefault 默认段落属性
+type PPrDefault struct {
+	XMLName     xml.Name             `xml:"w:pPrDefault"`
+	ParagraphPr *ParagraphProperties `xml:"w:pPr,omitempty"`
+}
+
+// RunProperties 返回默认字符属性，未设置时返回nil
+func (dd *DocDefaults) RunProperties() *RunProperties {
+	if dd == nil || dd.RPrDefault == nil {
+		return nil
+	}
+	return dd.RPrDefault.RunPr
+}
+
+// ParagraphProperties 返回默认段落属性，未设置时返回nil
+func (dd *DocDefaults) ParagraphProperties() *ParagraphProperties {
+	if dd == nil || dd.PPrDefault == nil {
+		return nil
+	}
+	return dd.PPrDefault.ParagraphPr
+}
+
+// SetDocumentDefaults 设置文档默认格式
+// runPr 和 paraPr 为 nil 时清除对应的默认值，传入的属性会被复制
+//
+// 示例：
+//
+//	sm.SetDocumentDefaults(&style.RunProperties{
+//		FontFamily: &style.FontFamily{ASCII: "Times New Roman", EastAsia: "宋体"},
+//		FontSize:   &style.FontSize{Val: "21"}, // 五号
+//	}, &style.ParagraphProperties{
+//		Spacing: &style.Spacing{Line: "360", LineRule: "auto"},
+//	})
+func (sm *StyleManager) SetDocumentDefaults(runPr *RunProperties, paraPr *ParagraphProperties) {
+	if runPr == nil && paraPr == nil {
+		sm.docDefaults = nil
+		return
+	}
+
+	defaults := &DocDefaults{}
+	if runPr != nil {
+		defaults.RPrDefault = &RPrDefault{RunPr: sm.cloneRunProperties(runPr)}
+	}
+	if paraPr != nil {
+		defaults.PPrDefault = &PPrDefault{ParagraphPr: sm.cloneParagraphProperties(paraPr)}
+	}
+	sm.docDefaults = defaults
+}
+
+// GetDocumentDefaults 获取文档默认格式，未设置时返回nil
+func (sm *StyleManager) GetDocumentDefaults() *DocDefaults {
+	return sm.docDefaults
+}
+
+// GetDefaultStyle 获取指定类型的默认样式（w:default="1"）
+// 未引用样式的段落、文本和表格使用对应类型的默认样式
+func (sm *StyleManager) GetDefaultStyle(styleType StyleType) *Style {
+	var result *Style
+	for _, s := range sm.styles {
+		if !s.Default || s.Type != string(styleType) {
+			continue
+		}
+		// 存在多个默认样式时按样式ID取第一个，保证结果稳定
+		if result == nil || s.StyleID < result.StyleID {
+			result = s
+		}
+	}
+	return result
+}
+
+// ParseDocDefaults 从样式XML中解析文档默认格式，不存在时返回nil
+func ParseDocDefaults(xmlData []byte) (*DocDefaults, error) {
+	var styles Styles
+	if err := newStylesDecoder(xmlData).Decode(&styles); err != nil {
+		return nil, fmt.Errorf("解析样式XML失败: %v", err)
+	}
+	return styles.DocDefaults, nil
+}
+
+// cloneDocDefaults 深拷贝文档默认格式
+func (sm *StyleManager) cloneDocDefaults(source *DocDefaults) *DocDefaults {
+	if source == nil {
+		return nil
+	}
+
+	cloned := &DocDefaults{}
+	if source.RPrDefault != nil {
+		cloned.RPrDefault = &RPrDefault{RunPr: sm.cloneRunProperties(source.RPrDefault.RunPr)}
+	}
+	if source.PPrDefault != nil {
+		cloned.PPrDefault = &PPrDefault{ParagraphPr: sm.cloneParagraphProperties(source.PPrDefault.ParagraphPr)}
+	}
+	return cloned
+}
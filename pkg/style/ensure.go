@@ -0,0 +1,161 @@
+// Package style 预定义样式的按需插入
+package style
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	predefinedOnce    sync.Once
+	predefinedManager *StyleManager
+)
+
+// builtinStyleNames 预定义样式对应的Word内置样式名称
+// Word在 styles.xml 中始终以英文名称保存内置样式，中文版Word的样式ID却是 "1"、"a" 等
+var builtinStyleNames = map[string]string{
+	StyleNormal:        "Normal",
+	StyleTitle:         "Title",
+	StyleSubtitle:      "Subtitle",
+	StyleQuote:         "Quote",
+	StyleListParagraph: "List Paragraph",
+	StyleEmphasis:      "Emphasis",
+	StyleStrong:        "Strong",
+}
+
+// PredefinedStyle 返回指定ID的预定义样式副本，不是预定义样式时返回nil
+func PredefinedStyle(styleID string) *Style {
+	predefinedOnce.Do(func() {
+		predefinedManager = NewStyleManager()
+	})
+	s := predefinedManager.GetStyle(styleID)
+	if s == nil {
+		return nil
+	}
+	return predefinedManager.cloneStyle(s)
+}
+
+// EnsureStyle 确保样式存在并返回文档中实际使用的样式
+//
+// 查找顺序为：同ID的样式（加载文档时补充的隐式样式除外）；与预定义样式同类型、
+// 同名称（或对应的Word内置名称，如中文版Word中ID为 "1" 的 "heading 1"）的样式；
+// 最后插入预定义样式的副本，其 basedOn、next、link 引用的样式同样按此规则补充。
+// 插入的样式只在被使用时写入样式表。
+// 调用方应使用返回样式的ID，它可能与 styleID 不同。
+func (sm *StyleManager) EnsureStyle(styleID string) (*Style, error) {
+	existing := sm.GetStyle(styleID)
+	if existing != nil && !sm.implicit[styleID] {
+		return existing, nil
+	}
+	predefined := PredefinedStyle(styleID)
+	if predefined == nil {
+		if existing != nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("样式 %s 不存在", styleID)
+	}
+	// 加载文档时补充的隐式样式让位于文档自带的同名样式
+	if s := sm.findPredefinedEquivalent(predefined); s != nil {
+		return s, nil
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	if predefined.Default && sm.GetDefaultStyle(StyleType(predefined.Type)) != nil {
+		predefined.Default = false
+	}
+	// 先加入样式再补充引用，避免 link 等相互引用导致无限递归
+	sm.AddStyle(predefined)
+	if sm.implicit == nil {
+		sm.implicit = make(map[string]bool)
+	}
+	sm.implicit[predefined.StyleID] = true
+
+	if predefined.BasedOn != nil {
+		if based, err := sm.EnsureStyle(predefined.BasedOn.Val); err == nil {
+			predefined.BasedOn.Val = based.StyleID
+		} else {
+			predefined.BasedOn = nil
+		}
+	}
+	if predefined.Next != nil {
+		if next, err := sm.EnsureStyle(predefined.Next.Val); err == nil {
+			predefined.Next.Val = next.StyleID
+		} else {
+			predefined.Next = nil
+		}
+	}
+	if predefined.Link != nil {
+		if linked, err := sm.EnsureStyle(predefined.Link.Val); err == nil {
+			predefined.Link.Val = linked.StyleID
+		} else {
+			predefined.Link = nil
+		}
+	}
+	return predefined, nil
+}
+
+// findPredefinedEquivalent 查找与预定义样式同类型、同名称的现有样式，不含隐式样式
+func (sm *StyleManager) findPredefinedEquivalent(predefined *Style) *Style {
+	names := make(map[string]bool)
+	if predefined.Name != nil {
+		names[strings.ToLower(predefined.Name.Val)] = true
+	}
+	if name, ok := builtinStyleNames[predefined.StyleID]; ok {
+		names[strings.ToLower(name)] = true
+	}
+
+	var result *Style
+	for _, s := range sm.styles {
+		if sm.implicit[s.StyleID] || s.Type != predefined.Type || s.Name == nil {
+			continue
+		}
+		if !names[strings.ToLower(strings.TrimSpace(s.Name.Val))] {
+			continue
+		}
+		// 存在多个同名样式时按样式ID取第一个，保证结果稳定
+		if result == nil || s.StyleID < result.StyleID {
+			result = s
+		}
+	}
+	return result
+}
+
+// EnsureLinkedCharacterStyle 返回段落样式的链接字符样式，不存在时创建
+//
+// 新建的字符样式ID为段落样式ID加 "Char" 后缀，复制段落样式的字符格式，
+// 并与段落样式互相链接，用于把标题、引用等段落样式的外观应用到部分文本。
+func (sm *StyleManager) EnsureLinkedCharacterStyle(paragraphStyleID string) (*Style, error) {
+	paragraph := sm.GetStyle(paragraphStyleID)
+	if paragraph == nil || paragraph.Type != string(StyleTypeParagraph) {
+		return nil, fmt.Errorf("段落样式 %s 不存在", paragraphStyleID)
+	}
+	if linked := sm.GetLinkedStyle(paragraphStyleID); linked != nil && linked.Type == string(StyleTypeCharacter) {
+		return linked, nil
+	}
+
+	characterID := paragraphStyleID + "Char"
+	if sm.StyleExists(characterID) {
+		characterID = sm.uniqueStyleID(characterID, nil)
+	}
+	name := paragraphStyleID
+	if paragraph.Name != nil {
+		name = paragraph.Name.Val
+	}
+	character := &Style{
+		Type:        string(StyleTypeCharacter),
+		StyleID:     characterID,
+		CustomStyle: paragraph.CustomStyle,
+		Name:        &StyleName{Val: name + " Char"},
+	}
+	if paragraph.RunPr != nil {
+		character.RunPr = sm.cloneRunProperties(paragraph.RunPr)
+	}
+	sm.AddStyle(character)
+	if err := sm.LinkStyles(paragraphStyleID, characterID); err != nil {
+		return nil, err
+	}
+	return character, nil
+}
@@ -0,0 +1,199 @@
+// Package style 从其他样式表导入样式
+package style
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StyleConflictPolicy 导入样式与现有样式冲突（ID相同，或类型和名称相同）时的处理策略
+type StyleConflictPolicy string
+
+const (
+	// ConflictOverwrite 使用导入的样式替换现有样式
+	ConflictOverwrite StyleConflictPolicy = "overwrite"
+	// ConflictKeepExisting 保留现有样式，跳过导入的样式
+	ConflictKeepExisting StyleConflictPolicy = "keepExisting"
+	// ConflictRename 保留现有样式，导入的样式使用新的ID和名称（如 "Heading1_2"、"heading 1 (2)"）
+	ConflictRename StyleConflictPolicy = "rename"
+)
+
+// MergeOptions 导入样式的选项
+type MergeOptions struct {
+	// ConflictPolicy 冲突处理策略，为空时使用 ConflictOverwrite
+	ConflictPolicy StyleConflictPolicy
+}
+
+// MergeResult 导入样式的结果
+type MergeResult struct {
+	// Imported 导入后样式表中来自导入数据的样式ID（已按导入后的ID排序）
+	Imported []string
+	// Skipped 因冲突保留现有样式而跳过的导入样式ID
+	Skipped []string
+	// Renamed 导入样式ID的变化：导入数据中的ID -> 样式表中对应样式的ID
+	// 用于更新导入的其他内容（如编号定义、页眉页脚）中的样式引用
+	Renamed map[string]string
+	// Remapped 现有样式ID的变化：被名称相同的导入样式替换的样式ID -> 导入样式的ID
+	// 用于更新文档正文中的样式引用
+	Remapped map[string]string
+}
+
+// MergeStylesFromXML 从XML数据合并样式（保留现有样式，只添加新的）
+func (sm *StyleManager) MergeStylesFromXML(xmlData []byte) error {
+	_, err := sm.MergeStyles(xmlData, &MergeOptions{ConflictPolicy: ConflictKeepExisting})
+	return err
+}
+
+// MergeStyles 从XML数据（word/styles.xml）导入样式、文档默认格式和隐含样式设置
+//
+// 导入的样式与现有样式ID相同，或类型相同且名称相同（不区分大小写，如 "heading 1"）
+// 时视为冲突，按 ConflictPolicy 处理。使用 ConflictOverwrite 时，名称相同但ID不同的
+// 现有样式被删除，其他现有样式对它的引用（basedOn、next、link）改为导入样式的ID，
+// 调用方需按 MergeResult.Remapped 更新文档内容中的样式引用。
+// 文档默认格式和隐含样式设置在 ConflictOverwrite 时被替换，否则只在现有样式表没有时导入。
+func (sm *StyleManager) MergeStyles(xmlData []byte, opts *MergeOptions) (*MergeResult, error) {
+	var styles Styles
+	if err := newStylesDecoder(xmlData).Decode(&styles); err != nil {
+		return nil, fmt.Errorf("解析样式XML失败: %v", err)
+	}
+
+	policy := ConflictOverwrite
+	if opts != nil && opts.ConflictPolicy != "" {
+		policy = opts.ConflictPolicy
+	}
+	switch policy {
+	case ConflictOverwrite, ConflictKeepExisting, ConflictRename:
+	default:
+		return nil, fmt.Errorf("无效的样式冲突策略: %s", policy)
+	}
+
+	result := &MergeResult{
+		Renamed:  make(map[string]string),
+		Remapped: make(map[string]string),
+	}
+
+	if styles.DocDefaults != nil && (policy == ConflictOverwrite || sm.docDefaults == nil) {
+		sm.docDefaults = styles.DocDefaults
+	}
+	if styles.LatentStyles != nil && (policy == ConflictOverwrite || sm.latentStyles == nil) {
+		sm.latentStyles = styles.LatentStyles
+	}
+
+	// 按类型和名称索引现有样式，用于识别ID不同的同一内置样式（如 "Heading1" 与中文版Word的 "1"）
+	byName := make(map[string]string, len(sm.styles))
+	for styleID, s := range sm.styles {
+		byName[styleNameKey(s)] = styleID
+	}
+	imported := make(map[string]bool, len(styles.Styles))
+	for i := range styles.Styles {
+		imported[styles.Styles[i].StyleID] = true
+	}
+
+	var added []*Style
+	addedIDs := make(map[string]bool, len(styles.Styles))
+	for i := range styles.Styles {
+		s := &styles.Styles[i]
+		importedID := s.StyleID
+		conflictID := ""
+		if sm.StyleExists(importedID) {
+			conflictID = importedID
+		} else if existingID, ok := byName[styleNameKey(s)]; ok && s.Name != nil {
+			conflictID = existingID
+		}
+
+		switch {
+		case conflictID == "":
+		case policy == ConflictKeepExisting:
+			result.Skipped = append(result.Skipped, importedID)
+			if conflictID != importedID {
+				result.Renamed[importedID] = conflictID
+			}
+			continue
+		case policy == ConflictRename:
+			// 现有样式保留默认样式的地位
+			s.Default = false
+			if conflictID == importedID {
+				s.StyleID = sm.uniqueStyleID(importedID, imported)
+				result.Renamed[importedID] = s.StyleID
+			}
+			if s.Name != nil {
+				for i := 2; ; i++ {
+					name := &StyleName{Val: fmt.Sprintf("%s (%d)", s.Name.Val, i)}
+					if _, exists := byName[styleNameKey(&Style{Type: s.Type, Name: name})]; !exists {
+						s.Name = name
+						break
+					}
+				}
+			}
+		case conflictID != importedID:
+			// 同名样式被导入样式替换，但导入数据中另有同ID样式时保留该ID给导入数据
+			if !imported[conflictID] {
+				sm.RemoveStyle(conflictID)
+				delete(sm.implicit, conflictID)
+			}
+			result.Remapped[conflictID] = importedID
+		}
+		added = append(added, s)
+		addedIDs[s.StyleID] = true
+		byName[styleNameKey(s)] = s.StyleID
+	}
+
+	// 导入样式之间的引用使用导入后的ID，现有样式对被替换样式的引用改为导入样式的ID
+	for _, s := range added {
+		renameStyleLinks(s, result.Renamed)
+		sm.AddStyle(s)
+		delete(sm.implicit, s.StyleID)
+		result.Imported = append(result.Imported, s.StyleID)
+	}
+	if len(result.Remapped) > 0 {
+		for _, s := range sm.styles {
+			if !addedIDs[s.StyleID] {
+				renameStyleLinks(s, result.Remapped)
+			}
+		}
+	}
+	sort.Strings(result.Imported)
+	sort.Strings(result.Skipped)
+	return result, nil
+}
+
+// styleNameKey 返回样式按类型和名称匹配的键
+func styleNameKey(s *Style) string {
+	if s.Name == nil {
+		return ""
+	}
+	return s.Type + "\x00" + strings.ToLower(strings.TrimSpace(s.Name.Val))
+}
+
+// uniqueStyleID 为重命名的导入样式生成不与现有样式和导入数据冲突的ID
+func (sm *StyleManager) uniqueStyleID(styleID string, imported map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", styleID, i)
+		if !sm.StyleExists(candidate) && !imported[candidate] {
+			return candidate
+		}
+	}
+}
+
+// renameStyleLinks 按映射更新样式的 basedOn、next 和 link 引用
+func renameStyleLinks(s *Style, ids map[string]string) {
+	if len(ids) == 0 {
+		return
+	}
+	if s.BasedOn != nil {
+		if newID, ok := ids[s.BasedOn.Val]; ok {
+			s.BasedOn = &BasedOn{Val: newID}
+		}
+	}
+	if s.Next != nil {
+		if newID, ok := ids[s.Next.Val]; ok {
+			s.Next = &Next{Val: newID}
+		}
+	}
+	if s.Link != nil {
+		if newID, ok := ids[s.Link.Val]; ok {
+			s.Link = &StyleLink{Val: newID}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package style 样式库元数据、隐含样式与链接样式
+package style
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// Aliases 样式别名
+type Aliases struct {
+	XMLName xml.Name `xml:"w:aliases"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// StyleLink 链接样式（段落样式与字符样式互相引用）
+type StyleLink struct {
+	XMLName xml.Name `xml:"w:link"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// UIPriority 样式在样式库中的排序优先级，数值越小越靠前
+type UIPriority struct {
+	XMLName xml.Name `xml:"w:uiPriority"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Rsid 样式的修订标识
+type Rsid struct {
+	XMLName xml.Name `xml:"w:rsid"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// LatentStyles 隐含样式设置（w:latentStyles）
+// 控制尚未写入样式表的内置样式在样式库中的默认可见性和排序
+type LatentStyles struct {
+	XMLName           xml.Name       `xml:"w:latentStyles"`
+	DefLockedState    string         `xml:"w:defLockedState,attr,omitempty"`
+	DefUIPriority     string         `xml:"w:defUIPriority,attr,omitempty"`
+	DefSemiHidden     string         `xml:"w:defSemiHidden,attr,omitempty"`
+	DefUnhideWhenUsed string         `xml:"w:defUnhideWhenUsed,attr,omitempty"`
+	DefQFormat        string         `xml:"w:defQFormat,attr,omitempty"`
+	Count             string         `xml:"w:count,attr,omitempty"`
+	Exceptions        []LsdException `xml:"w:lsdException"`
+}
+
+// LsdException 单个内置样式的隐含样式设置
+type LsdException struct {
+	XMLName        xml.Name `xml:"w:lsdException"`
+	Name           string   `xml:"w:name,attr"`
+	Locked         string   `xml:"w:locked,attr,omitempty"`
+	UIPriority     string   `xml:"w:uiPriority,attr,omitempty"`
+	SemiHidden     string   `xml:"w:semiHidden,attr,omitempty"`
+	UnhideWhenUsed string   `xml:"w:unhideWhenUsed,attr,omitempty"`
+	QFormat        string   `xml:"w:qFormat,attr,omitempty"`
+}
+
+// GetException 按样式名称获取隐含样式设置，不存在时返回nil
+func (ls *LatentStyles) GetException(name string) *LsdException {
+	if ls == nil {
+		return nil
+	}
+	for i := range ls.Exceptions {
+		if ls.Exceptions[i].Name == name {
+			return &ls.Exceptions[i]
+		}
+	}
+	return nil
+}
+
+// GetLatentStyles 获取隐含样式设置，未设置时返回nil
+func (sm *StyleManager) GetLatentStyles() *LatentStyles {
+	return sm.latentStyles
+}
+
+// SetLatentStyles 设置隐含样式设置，传入nil时清除
+func (sm *StyleManager) SetLatentStyles(latentStyles *LatentStyles) {
+	sm.latentStyles = cloneLatentStyles(latentStyles)
+}
+
+// LinkedStylePair 互相链接的段落样式和字符样式
+// Word 对段落中的部分文本应用链接的段落样式时，实际使用对应的字符样式
+type LinkedStylePair struct {
+	Paragraph *Style
+	Character *Style
+}
+
+// GetLinkedStyle 获取与指定样式链接的样式，未链接或链接的样式不存在时返回nil
+func (sm *StyleManager) GetLinkedStyle(styleID string) *Style {
+	s := sm.GetStyle(styleID)
+	if s == nil || s.Link == nil {
+		return nil
+	}
+	return sm.GetStyle(s.Link.Val)
+}
+
+// GetLinkedStylePairs 获取所有链接样式对，按段落样式ID排序
+// 只返回段落样式与字符样式互相引用的样式对
+func (sm *StyleManager) GetLinkedStylePairs() []LinkedStylePair {
+	var pairs []LinkedStylePair
+	for _, paragraph := range sm.styles {
+		if paragraph.Type != string(StyleTypeParagraph) {
+			continue
+		}
+		character := sm.GetLinkedStyle(paragraph.StyleID)
+		if character == nil || character.Type != string(StyleTypeCharacter) ||
+			character.Link == nil || character.Link.Val != paragraph.StyleID {
+			continue
+		}
+		pairs = append(pairs, LinkedStylePair{Paragraph: paragraph, Character: character})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Paragraph.StyleID < pairs[j].Paragraph.StyleID
+	})
+	return pairs
+}
+
+// LinkStyles 将段落样式与字符样式互相链接
+func (sm *StyleManager) LinkStyles(paragraphStyleID, characterStyleID string) error {
+	paragraph := sm.GetStyle(paragraphStyleID)
+	if paragraph == nil || paragraph.Type != string(StyleTypeParagraph) {
+		return fmt.Errorf("段落样式 %s 不存在", paragraphStyleID)
+	}
+	character := sm.GetStyle(characterStyleID)
+	if character == nil || character.Type != string(StyleTypeCharacter) {
+		return fmt.Errorf("字符样式 %s 不存在", characterStyleID)
+	}
+
+	paragraph.Link = &StyleLink{Val: characterStyleID}
+	character.Link = &StyleLink{Val: paragraphStyleID}
+	return nil
+}
+
+// cloneLatentStyles 深拷贝隐含样式设置
+func cloneLatentStyles(source *LatentStyles) *LatentStyles {
+	if source == nil {
+		return nil
+	}
+	cloned := *source
+	cloned.Exceptions = append([]LsdException(nil), source.Exceptions...)
+	return &cloned
+}
@@ -0,0 +1,477 @@
+// Package style 样式表的无损读写
+package style
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// UnknownElement 未建模的XML元素，解析后原样保留并在保存时写回
+type UnknownElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr       `xml:",any,attr"`
+	Children []UnknownElement `xml:",any"`
+	Text     string           `xml:",chardata"`
+}
+
+// cloneUnknownElements 深拷贝未建模元素
+func cloneUnknownElements(source []UnknownElement) []UnknownElement {
+	if source == nil {
+		return nil
+	}
+	cloned := make([]UnknownElement, len(source))
+	for i, element := range source {
+		cloned[i] = UnknownElement{
+			XMLName:  element.XMLName,
+			Attrs:    append([]xml.Attr(nil), element.Attrs...),
+			Children: cloneUnknownElements(element.Children),
+			Text:     element.Text,
+		}
+	}
+	return cloned
+}
+
+// OpenXML 规定的子元素顺序，未建模元素按此顺序插入到已建模元素之间
+var (
+	styleChildOrder = []string{
+		"w:name", "w:aliases", "w:basedOn", "w:next", "w:link", "w:autoRedefine", "w:hidden",
+		"w:uiPriority", "w:semiHidden", "w:unhideWhenUsed", "w:qFormat", "w:locked", "w:personal",
+		"w:personalCompose", "w:personalReply", "w:rsid", "w:pPr", "w:rPr", "w:tblPr", "w:trPr",
+		"w:tcPr", "w:tblStylePr",
+	}
+	paragraphPropertiesOrder = []string{
+		"w:pStyle", "w:keepNext", "w:keepLines", "w:pageBreakBefore", "w:framePr", "w:widowControl",
+		"w:numPr", "w:suppressLineNumbers", "w:pBdr", "w:shd", "w:tabs", "w:suppressAutoHyphens",
+		"w:kinsoku", "w:wordWrap", "w:overflowPunct", "w:topLinePunct", "w:autoSpaceDE", "w:autoSpaceDN",
+		"w:bidi", "w:adjustRightInd", "w:snapToGrid", "w:spacing", "w:ind", "w:contextualSpacing",
+		"w:mirrorIndents", "w:suppressOverlap", "w:jc", "w:textDirection", "w:textAlignment",
+		"w:textboxTightWrap", "w:outlineLvl", "w:divId", "w:cnfStyle", "w:rPr", "w:sectPr", "w:pPrChange",
+	}
+	runPropertiesOrder = []string{
+		"w:rStyle", "w:rFonts", "w:b", "w:bCs", "w:i", "w:iCs", "w:caps", "w:smallCaps", "w:strike",
+		"w:dstrike", "w:outline", "w:shadow", "w:emboss", "w:imprint", "w:noProof", "w:snapToGrid",
+		"w:vanish", "w:webHidden", "w:color", "w:spacing", "w:w", "w:kern", "w:position", "w:sz",
+		"w:szCs", "w:highlight", "w:u", "w:effect", "w:bdr", "w:shd", "w:fitText", "w:vertAlign",
+		"w:rtl", "w:cs", "w:em", "w:lang", "w:eastAsianLayout", "w:specVanish", "w:oMath", "w:rPrChange",
+	}
+	tablePropertiesOrder = []string{
+		"w:tblStyle", "w:tblpPr", "w:tblOverlap", "w:bidiVisual", "w:tblStyleRowBandSize",
+		"w:tblStyleColBandSize", "w:tblW", "w:jc", "w:tblCellSpacing", "w:tblInd", "w:tblBorders",
+		"w:shd", "w:tblLayout", "w:tblCellMar", "w:tblLook", "w:tblCaption", "w:tblDescription",
+	}
+	tableRowPropertiesOrder = []string{
+		"w:cnfStyle", "w:divId", "w:gridBefore", "w:gridAfter", "w:wBefore", "w:wAfter", "w:cantSplit",
+		"w:trHeight", "w:tblHeader", "w:tblCellSpacing", "w:jc", "w:hidden",
+	}
+	tableCellPropertiesOrder = []string{
+		"w:cnfStyle", "w:tcW", "w:gridSpan", "w:hMerge", "w:vMerge", "w:tcBorders", "w:shd", "w:noWrap",
+		"w:tcMar", "w:textDirection", "w:tcFitText", "w:vAlign", "w:hideMark",
+	}
+)
+
+// MarshalXML 输出样式，未建模的子元素按规定顺序写回
+func (s Style) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type style Style
+	known := style(s)
+	known.Extra = nil
+	return marshalOrdered(e, "w:style", known, s.Extra, styleChildOrder)
+}
+
+// MarshalXML 输出段落属性，未建模的子元素按规定顺序写回
+func (p ParagraphProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type paragraphProperties ParagraphProperties
+	known := paragraphProperties(p)
+	known.Extra = nil
+	return marshalOrdered(e, "w:pPr", known, p.Extra, paragraphPropertiesOrder)
+}
+
+// MarshalXML 输出字符属性，未建模的子元素按规定顺序写回
+func (r RunProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type runProperties RunProperties
+	known := runProperties(r)
+	known.Extra = nil
+	return marshalOrdered(e, "w:rPr", known, r.Extra, runPropertiesOrder)
+}
+
+// MarshalXML 输出表格属性，未建模的子元素按规定顺序写回
+func (t TableProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type tableProperties TableProperties
+	known := tableProperties(t)
+	known.Extra = nil
+	return marshalOrdered(e, "w:tblPr", known, t.Extra, tablePropertiesOrder)
+}
+
+// MarshalXML 输出表格行属性，未建模的子元素按规定顺序写回
+func (t TableRowProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type tableRowProperties TableRowProperties
+	known := tableRowProperties(t)
+	known.Extra = nil
+	return marshalOrdered(e, "w:trPr", known, t.Extra, tableRowPropertiesOrder)
+}
+
+// MarshalXML 输出表格单元格属性，未建模的子元素按规定顺序写回
+func (t TableCellProperties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type tableCellProperties TableCellProperties
+	known := tableCellProperties(t)
+	known.Extra = nil
+	return marshalOrdered(e, "w:tcPr", known, t.Extra, tableCellPropertiesOrder)
+}
+
+// marshalOrdered 输出已建模字段，并把未建模的子元素按 order 规定的顺序插入
+func marshalOrdered(e *xml.Encoder, name string, known interface{}, extra []UnknownElement, order []string) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if len(extra) == 0 {
+		return e.EncodeElement(known, start)
+	}
+
+	// 将已建模字段转换为通用元素后与未建模元素统一排序
+	data, err := xml.Marshal(known)
+	if err != nil {
+		return err
+	}
+	var root UnknownElement
+	if err := newStylesDecoder(data).Decode(&root); err != nil {
+		return err
+	}
+
+	children := append(root.Children, extra...)
+	position := func(element UnknownElement) int {
+		for i, childName := range order {
+			if childName == element.XMLName.Local {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return position(children[i]) < position(children[j])
+	})
+
+	start.Attr = root.Attrs
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := e.Encode(child); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// stylesSource 解析时的原始样式XML，按根元素的子元素切分
+type stylesSource struct {
+	prefix []byte // XML声明及根元素开始标签
+	parts  []stylesSourcePart
+	suffix []byte // 根元素结束标签及其后的内容
+}
+
+// stylesSourcePart 原始样式XML中的一个子元素
+type stylesSourcePart struct {
+	name        string // 元素名称，如 "w:style"
+	styleID     string
+	raw         []byte // 原始XML（含前导空白）
+	fingerprint []byte // 解析结果重新序列化后的内容，用于判断是否被修改
+}
+
+// newStylesSource 切分原始样式XML并记录各部分解析结果的指纹
+// styles 必须是同一份XML的解析结果，且尚未被修改
+func newStylesSource(data []byte, styles *Styles) (*stylesSource, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	source := &stylesSource{}
+	var current stylesSourcePart
+	var prevEnd int64
+	depth, styleIndex := 0, 0
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := decoder.InputOffset()
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 1:
+				source.prefix = append([]byte(nil), data[:end]...)
+				if bytes.HasSuffix(source.prefix, []byte("/>")) {
+					// 空的根元素 <w:styles/>
+					source.prefix = append(source.prefix[:len(source.prefix)-2], '>')
+					source.suffix = append([]byte("</"+prefixedName(t.Name).Local+">"), data[end:]...)
+					return source, nil
+				}
+				prevEnd = end
+			case 2:
+				current = stylesSourcePart{name: prefixedName(t.Name).Local}
+				for _, attr := range t.Attr {
+					if prefixedName(attr.Name).Local == "w:styleId" {
+						current.styleID = attr.Value
+					}
+				}
+			}
+		case xml.EndElement:
+			switch depth {
+			case 1:
+				source.suffix = append([]byte(nil), data[prevEnd:]...)
+			case 2:
+				current.raw = append([]byte(nil), data[prevEnd:end]...)
+				switch current.name {
+				case "w:docDefaults":
+					current.fingerprint = fingerprint(styles.DocDefaults)
+				case "w:latentStyles":
+					current.fingerprint = fingerprint(styles.LatentStyles)
+				case "w:style":
+					if styleIndex < len(styles.Styles) {
+						current.fingerprint = fingerprint(&styles.Styles[styleIndex])
+					}
+					styleIndex++
+				}
+				source.parts = append(source.parts, current)
+				prevEnd = end
+			}
+			depth--
+		}
+	}
+
+	if source.prefix == nil {
+		return nil, fmt.Errorf("样式XML缺少根元素")
+	}
+	return source, nil
+}
+
+// has 判断原始XML中是否包含指定名称的子元素
+func (src *stylesSource) has(name string) bool {
+	for _, part := range src.parts {
+		if part.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint 返回值的序列化结果，序列化失败时返回nil
+func fingerprint(v interface{}) []byte {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// SetSourceXML 记录样式XML原文作为之后增量输出的基础，不修改当前样式
+// 通常在首次生成 styles.xml 后调用；解析样式时会自动记录
+func (sm *StyleManager) SetSourceXML(xmlData []byte) error {
+	var styles Styles
+	if err := newStylesDecoder(xmlData).Decode(&styles); err != nil {
+		return fmt.Errorf("解析样式XML失败: %v", err)
+	}
+	source, err := newStylesSource(xmlData, &styles)
+	if err != nil {
+		return fmt.Errorf("解析样式XML失败: %v", err)
+	}
+	sm.source = source
+	return nil
+}
+
+// HasSourceXML 判断是否记录了原始样式XML
+func (sm *StyleManager) HasSourceXML() bool {
+	return sm.source != nil
+}
+
+// stylesDocument 新建样式表时使用的根元素，包含完整的命名空间
+type stylesDocument struct {
+	XMLName      xml.Name      `xml:"w:styles"`
+	XmlnsW       string        `xml:"xmlns:w,attr"`
+	XmlnsMC      string        `xml:"xmlns:mc,attr"`
+	XmlnsO       string        `xml:"xmlns:o,attr"`
+	XmlnsR       string        `xml:"xmlns:r,attr"`
+	XmlnsM       string        `xml:"xmlns:m,attr"`
+	XmlnsV       string        `xml:"xmlns:v,attr"`
+	XmlnsW14     string        `xml:"xmlns:w14,attr"`
+	XmlnsW10     string        `xml:"xmlns:w10,attr"`
+	XmlnsSL      string        `xml:"xmlns:sl,attr"`
+	XmlnsWPS     string        `xml:"xmlns:wpsCustomData,attr"`
+	MCIgnorable  string        `xml:"mc:Ignorable,attr"`
+	DocDefaults  *DocDefaults  `xml:"w:docDefaults,omitempty"`
+	LatentStyles *LatentStyles `xml:"w:latentStyles,omitempty"`
+	Styles       []*Style      `xml:"w:style"`
+}
+
+// MarshalStylesXML 生成样式表XML（word/styles.xml）
+//
+// 对从文档解析的样式表，未修改的样式、文档默认格式和隐含样式设置按原始XML输出，
+// 保留未建模的内容；修改过的部分重新生成，删除的样式不再输出，新增的样式追加在末尾。
+// 加载文档时补充的预定义样式只在 isUsed 返回 true 或被其他输出的样式引用时写入，
+// isUsed 为 nil 时全部写入。
+func (sm *StyleManager) MarshalStylesXML(isUsed func(styleID string) bool) ([]byte, error) {
+	keep := sm.stylesToWrite(isUsed)
+
+	if sm.source == nil {
+		doc := stylesDocument{
+			XmlnsW:       "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+			XmlnsMC:      "http://schemas.openxmlformats.org/markup-compatibility/2006",
+			XmlnsO:       "urn:schemas-microsoft-com:office:office",
+			XmlnsR:       "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
+			XmlnsM:       "http://schemas.openxmlformats.org/officeDocument/2006/math",
+			XmlnsV:       "urn:schemas-microsoft-com:vml",
+			XmlnsW14:     "http://schemas.microsoft.com/office/word/2010/wordml",
+			XmlnsW10:     "urn:schemas-microsoft-com:office:word",
+			XmlnsSL:      "http://schemas.openxmlformats.org/schemaLibrary/2006/main",
+			XmlnsWPS:     "http://www.wps.cn/officeDocument/2013/wpsCustomData",
+			MCIgnorable:  "w14",
+			DocDefaults:  sm.docDefaults,
+			LatentStyles: sm.latentStyles,
+		}
+		for _, s := range sm.GetAllStyles() {
+			if keep[s.StyleID] {
+				doc.Styles = append(doc.Styles, s)
+			}
+		}
+		data, err := xml.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), data...), nil
+	}
+
+	src := sm.source
+	var buf bytes.Buffer
+	buf.Write(src.prefix)
+
+	indent := []byte("\n")
+	write := func(raw, previous []byte, current interface{}) error {
+		leading := raw[:len(raw)-len(bytes.TrimLeft(raw, " \t\r\n"))]
+		if len(raw) > 0 && bytes.Equal(fingerprint(current), previous) {
+			buf.Write(raw)
+			return nil
+		}
+		if len(raw) == 0 {
+			leading = indent
+		}
+		data, err := xml.Marshal(current)
+		if err != nil {
+			return err
+		}
+		buf.Write(leading)
+		buf.Write(data)
+		return nil
+	}
+
+	// w:docDefaults 和 w:latentStyles 必须位于所有 w:style 之前
+	latentWritten := src.has("w:latentStyles") || sm.latentStyles == nil
+	if !src.has("w:docDefaults") && sm.docDefaults != nil {
+		if err := write(nil, nil, sm.docDefaults); err != nil {
+			return nil, err
+		}
+	}
+
+	written := make(map[string]bool)
+	for _, part := range src.parts {
+		if part.name != "w:docDefaults" && !latentWritten {
+			if err := write(nil, nil, sm.latentStyles); err != nil {
+				return nil, err
+			}
+			latentWritten = true
+		}
+
+		var err error
+		switch part.name {
+		case "w:docDefaults":
+			if sm.docDefaults != nil {
+				err = write(part.raw, part.fingerprint, sm.docDefaults)
+			}
+		case "w:latentStyles":
+			if sm.latentStyles != nil {
+				err = write(part.raw, part.fingerprint, sm.latentStyles)
+			}
+		case "w:style":
+			s := sm.styles[part.styleID]
+			if s == nil || written[part.styleID] || !keep[part.styleID] {
+				continue
+			}
+			written[part.styleID] = true
+			indent = part.raw[:len(part.raw)-len(bytes.TrimLeft(part.raw, " \t\r\n"))]
+			err = write(part.raw, part.fingerprint, s)
+		default:
+			buf.Write(part.raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !latentWritten {
+		if err := write(nil, nil, sm.latentStyles); err != nil {
+			return nil, err
+		}
+	}
+
+	// 新增的样式按ID排序追加，保证输出稳定
+	var added []string
+	for styleID := range sm.styles {
+		if keep[styleID] && !written[styleID] {
+			added = append(added, styleID)
+		}
+	}
+	sort.Strings(added)
+	for _, styleID := range added {
+		if err := write(nil, nil, sm.styles[styleID]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.Write(src.suffix)
+	return buf.Bytes(), nil
+}
+
+// stylesToWrite 返回需要写入样式表的样式ID
+// 隐式样式在被使用或被其他写入的样式（basedOn、next、link）引用时才写入
+func (sm *StyleManager) stylesToWrite(isUsed func(styleID string) bool) map[string]bool {
+	keep := make(map[string]bool, len(sm.styles))
+	for styleID := range sm.styles {
+		if !sm.implicit[styleID] || isUsed == nil || isUsed(styleID) {
+			keep[styleID] = true
+		}
+	}
+	sm.addReferencedStyles(keep)
+	return keep
+}
+
+// addReferencedStyles 将集合中样式通过 basedOn、next、link 直接或间接引用的样式加入集合
+func (sm *StyleManager) addReferencedStyles(keep map[string]bool) {
+	pending := make([]string, 0, len(keep))
+	for styleID := range keep {
+		pending = append(pending, styleID)
+	}
+
+	for len(pending) > 0 {
+		s := sm.styles[pending[len(pending)-1]]
+		pending = pending[:len(pending)-1]
+		if s == nil {
+			continue
+		}
+		var references []string
+		if s.BasedOn != nil {
+			references = append(references, s.BasedOn.Val)
+		}
+		if s.Next != nil {
+			references = append(references, s.Next.Val)
+		}
+		if s.Link != nil {
+			references = append(references, s.Link.Val)
+		}
+		for _, styleID := range references {
+			if _, exists := sm.styles[styleID]; exists && !keep[styleID] {
+				keep[styleID] = true
+				pending = append(pending, styleID)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package style
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 )
@@ -21,20 +22,40 @@ const (
 )
 
 // Style 样式定义
+// 注意：字段顺序必须符合OpenXML标准
 type Style struct {
-	XMLName     xml.Name             `xml:"w:style"`
-	Type        string               `xml:"w:type,attr"`
-	StyleID     string               `xml:"w:styleId,attr"`
-	Name        *StyleName           `xml:"w:name,omitempty"`
-	BasedOn     *BasedOn             `xml:"w:basedOn,omitempty"`
-	Next        *Next                `xml:"w:next,omitempty"`
-	Default     bool                 `xml:"w:default,attr,omitempty"`
-	CustomStyle bool                 `xml:"w:customStyle,attr,omitempty"`
+	XMLName     xml.Name   `xml:"w:style"`
+	Type        string     `xml:"w:type,attr"`
+	StyleID     string     `xml:"w:styleId,attr"`
+	Name        *StyleName `xml:"w:name,omitempty"`
+	Aliases     *Aliases   `xml:"w:aliases,omitempty"` // 样式别名，多个别名以逗号分隔
+	BasedOn     *BasedOn   `xml:"w:basedOn,omitempty"`
+	Next        *Next      `xml:"w:next,omitempty"`
+	Default     bool       `xml:"w:default,attr,omitempty"`
+	CustomStyle bool       `xml:"w:customStyle,attr,omitempty"`
+
+	// 样式库（UI）元数据
+	Link           *StyleLink  `xml:"w:link,omitempty"`           // 链接的段落/字符样式
+	AutoRedefine   *OnOff      `xml:"w:autoRedefine,omitempty"`   // 自动更新
+	Hidden         *OnOff      `xml:"w:hidden,omitempty"`         // 从界面中隐藏
+	UIPriority     *UIPriority `xml:"w:uiPriority,omitempty"`     // 样式库排序优先级
+	SemiHidden     *OnOff      `xml:"w:semiHidden,omitempty"`     // 在推荐列表中隐藏
+	UnhideWhenUsed *OnOff      `xml:"w:unhideWhenUsed,omitempty"` // 使用后取消隐藏
+	QFormat        *OnOff      `xml:"w:qFormat,omitempty"`        // 显示在快速样式库中
+	Locked         *OnOff      `xml:"w:locked,omitempty"`         // 锁定
+	Rsid           *Rsid       `xml:"w:rsid,omitempty"`           // 修订标识
+
 	ParagraphPr *ParagraphProperties `xml:"w:pPr,omitempty"`
 	RunPr       *RunProperties       `xml:"w:rPr,omitempty"`
 	TablePr     *TableProperties     `xml:"w:tblPr,omitempty"`
 	TableRowPr  *TableRowProperties  `xml:"w:trPr,omitempty"`
 	TableCellPr *TableCellProperties `xml:"w:tcPr,omitempty"`
+
+	// TableStylePr 表格样式的条件格式（首行、末行、镶边行等），仅用于表格样式
+	TableStylePr []*TableStyleProperties `xml:"w:tblStylePr,omitempty"`
+
+	// Extra 未建模的子元素，解析后原样保留
+	Extra []UnknownElement `xml:",any"`
 }
 
 // StyleName 样式名称
@@ -62,13 +83,18 @@ type ParagraphProperties struct {
 	KeepNext        *KeepNext        `xml:"w:keepNext,omitempty"`
 	KeepLines       *KeepLines       `xml:"w:keepLines,omitempty"`
 	PageBreak       *PageBreak       `xml:"w:pageBreakBefore,omitempty"`
+	NumPr           *NumPr           `xml:"w:numPr,omitempty"`
 	ParagraphBorder *ParagraphBorder `xml:"w:pBdr,omitempty"`
 	Shading         *Shading         `xml:"w:shd,omitempty"`
+	Tabs            *Tabs            `xml:"w:tabs,omitempty"`
 	SnapToGrid      *SnapToGrid      `xml:"w:snapToGrid,omitempty"`
 	Spacing         *Spacing         `xml:"w:spacing,omitempty"`
 	Indentation     *Indentation     `xml:"w:ind,omitempty"`
 	Justification   *Justification   `xml:"w:jc,omitempty"`
 	OutlineLevel    *OutlineLevel    `xml:"w:outlineLvl,omitempty"`
+
+	// Extra 未建模的子元素，解析后原样保留
+	Extra []UnknownElement `xml:",any"`
 }
 
 // ParagraphBorder 段落边框
@@ -89,11 +115,26 @@ type ParagraphBorderLine struct {
 	Space   string   `xml:"w:space,attr"`
 }
 
+// Tabs 制表位
+type Tabs struct {
+	XMLName xml.Name `xml:"w:tabs"`
+	Tabs    []TabDef `xml:"w:tab"`
+}
+
+// TabDef 制表位定义
+type TabDef struct {
+	XMLName xml.Name `xml:"w:tab"`
+	Val     string   `xml:"w:val,attr"`              // 对齐方式（left、center、right、decimal、bar、clear）
+	Leader  string   `xml:"w:leader,attr,omitempty"` // 前导符（dot、hyphen、underscore 等）
+	Pos     string   `xml:"w:pos,attr"`              // 位置（缇）
+}
+
 // Shading 阴影/填充色
 type Shading struct {
 	XMLName xml.Name `xml:"w:shd"`
 	Fill    string   `xml:"w:fill,attr"`
 	Val     string   `xml:"w:val,attr,omitempty"`
+	Color   string   `xml:"w:color,attr,omitempty"`
 }
 
 // RunProperties 字符样式属性
@@ -108,14 +149,28 @@ type RunProperties struct {
 	Color      *Color      `xml:"w:color,omitempty"`
 	FontSize   *FontSize   `xml:"w:sz,omitempty"`
 	Highlight  *Highlight  `xml:"w:highlight,omitempty"`
+
+	// Extra 未建模的子元素（如 w:kern、w:lang），解析后原样保留
+	Extra []UnknownElement `xml:",any"`
 }
 
 // TableProperties 表格样式属性
 type TableProperties struct {
-	XMLName    xml.Name       `xml:"w:tblPr"`
-	TblInd     *TblIndent     `xml:"w:tblInd,omitempty"`     // 表格缩进
-	TblBorders *TblBorders    `xml:"w:tblBorders,omitempty"` // 表格边框
-	TblCellMar *TblCellMargin `xml:"w:tblCellMar,omitempty"` // 表格单元格边距
+	XMLName     xml.Name       `xml:"w:tblPr"`
+	RowBandSize *BandSize      `xml:"w:tblStyleRowBandSize,omitempty"` // 镶边行包含的行数
+	ColBandSize *BandSize      `xml:"w:tblStyleColBandSize,omitempty"` // 镶边列包含的列数
+	TblInd      *TblIndent     `xml:"w:tblInd,omitempty"`              // 表格缩进
+	TblBorders  *TblBorders    `xml:"w:tblBorders,omitempty"`          // 表格边框
+	Shading     *Shading       `xml:"w:shd,omitempty"`                 // 表格底纹
+	TblCellMar  *TblCellMargin `xml:"w:tblCellMar,omitempty"`          // 表格单元格边距
+
+	// Extra 未建模的子元素，解析后原样保留
+	Extra []UnknownElement `xml:",any"`
+}
+
+// BandSize 镶边行/列的大小
+type BandSize struct {
+	Val string `xml:"w:val,attr"`
 }
 
 // TblIndent 表格缩进
@@ -161,14 +216,40 @@ type TblCellSpace struct {
 
 // TableRowProperties 表格行样式属性
 type TableRowProperties struct {
-	XMLName xml.Name `xml:"w:trPr"`
-	// 表格行样式属性将在后续实现
+	XMLName   xml.Name `xml:"w:trPr"`
+	CantSplit *OnOff   `xml:"w:cantSplit,omitempty"` // 行不跨页断开
+	TblHeader *OnOff   `xml:"w:tblHeader,omitempty"` // 标题行重复
+	Jc        *TableJc `xml:"w:jc,omitempty"`        // 行对齐方式
+
+	// Extra 未建模的子元素，解析后原样保留
+	Extra []UnknownElement `xml:",any"`
 }
 
 // TableCellProperties 表格单元格样式属性
 type TableCellProperties struct {
-	XMLName xml.Name `xml:"w:tcPr"`
-	// 表格单元格样式属性将在后续实现
+	XMLName   xml.Name   `xml:"w:tcPr"`
+	TcBorders *TcBorders `xml:"w:tcBorders,omitempty"` // 单元格边框
+	Shading   *Shading   `xml:"w:shd,omitempty"`       // 单元格底纹
+	NoWrap    *OnOff     `xml:"w:noWrap,omitempty"`    // 不自动换行
+	VAlign    *VAlign    `xml:"w:vAlign,omitempty"`    // 垂直对齐
+
+	// Extra 未建模的子元素，解析后原样保留
+	Extra []UnknownElement `xml:",any"`
+}
+
+// OnOff 仅含可选 w:val 的开关元素，省略 w:val 表示开启
+type OnOff struct {
+	Val string `xml:"w:val,attr,omitempty"`
+}
+
+// TableJc 表格行对齐方式
+type TableJc struct {
+	Val string `xml:"w:val,attr"`
+}
+
+// VAlign 单元格垂直对齐方式（top、center、bottom）
+type VAlign struct {
+	Val string `xml:"w:val,attr"`
 }
 
 // 基础样式元素定义
@@ -188,6 +269,7 @@ type Justification struct {
 type Indentation struct {
 	XMLName   xml.Name `xml:"w:ind"`
 	FirstLine string   `xml:"w:firstLine,attr,omitempty"`
+	Hanging   string   `xml:"w:hanging,attr,omitempty"`
 	Left      string   `xml:"w:left,attr,omitempty"`
 	Right     string   `xml:"w:right,attr,omitempty"`
 }
@@ -209,6 +291,25 @@ type OutlineLevel struct {
 	Val     string   `xml:"w:val,attr"`
 }
 
+// NumPr 样式编号属性（将样式与编号定义关联，如标题编号）
+type NumPr struct {
+	XMLName xml.Name   `xml:"w:numPr"`
+	ILvl    *NumPrILvl `xml:"w:ilvl,omitempty"`
+	NumID   *NumPrID   `xml:"w:numId,omitempty"`
+}
+
+// NumPrILvl 编号级别
+type NumPrILvl struct {
+	XMLName xml.Name `xml:"w:ilvl"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// NumPrID 编号实例ID
+type NumPrID struct {
+	XMLName xml.Name `xml:"w:numId"`
+	Val     string   `xml:"w:val,attr"`
+}
+
 // SnapToGrid 网格对齐设置
 // 设置为 "0" 时禁用网格对齐，"1" 时启用网格对齐，允许自定义行间距生效（符合 OOXML 规范，仅支持 "0" 或 "1"）
 // 注意：此类型在 document 包中有相同定义，这是有意为之，因为两个包可独立使用
@@ -217,12 +318,16 @@ type SnapToGrid struct {
 	Val     string   `xml:"w:val,attr,omitempty"`
 }
 
+// Bold 粗体（切换属性），Val 为 "0"/"false" 时表示关闭
 type Bold struct {
 	XMLName xml.Name `xml:"w:b"`
+	Val     string   `xml:"w:val,attr,omitempty"`
 }
 
+// Italic 斜体（切换属性），Val 为 "0"/"false" 时表示关闭
 type Italic struct {
 	XMLName xml.Name `xml:"w:i"`
+	Val     string   `xml:"w:val,attr,omitempty"`
 }
 
 type Underline struct {
@@ -240,8 +345,11 @@ type FontSize struct {
 }
 
 type Color struct {
-	XMLName xml.Name `xml:"w:color"`
-	Val     string   `xml:"w:val,attr"`
+	XMLName    xml.Name `xml:"w:color"`
+	Val        string   `xml:"w:val,attr"`
+	ThemeColor string   `xml:"w:themeColor,attr,omitempty"` // 主题颜色（如 accent1、text1），Val 为其回退值
+	ThemeTint  string   `xml:"w:themeTint,attr,omitempty"`  // 主题颜色淡化（十六进制 00-FF）
+	ThemeShade string   `xml:"w:themeShade,attr,omitempty"` // 主题颜色加深（十六进制 00-FF）
 }
 
 type FontFamily struct {
@@ -250,6 +358,12 @@ type FontFamily struct {
 	EastAsia string   `xml:"w:eastAsia,attr,omitempty"`
 	HAnsi    string   `xml:"w:hAnsi,attr,omitempty"`
 	CS       string   `xml:"w:cs,attr,omitempty"`
+
+	// 主题字体引用（如 minorHAnsi、majorEastAsia），优先于上面的字体名称
+	ASCIITheme    string `xml:"w:asciiTheme,attr,omitempty"`
+	EastAsiaTheme string `xml:"w:eastAsiaTheme,attr,omitempty"`
+	HAnsiTheme    string `xml:"w:hAnsiTheme,attr,omitempty"`
+	CSTheme       string `xml:"w:cstheme,attr,omitempty"`
 }
 
 type Highlight struct {
@@ -259,14 +373,23 @@ type Highlight struct {
 
 // Styles 样式集合
 type Styles struct {
-	XMLName xml.Name `xml:"w:styles"`
-	Xmlns   string   `xml:"xmlns:w,attr"`
-	Styles  []Style  `xml:"w:style"`
+	XMLName      xml.Name      `xml:"w:styles"`
+	Xmlns        string        `xml:"xmlns:w,attr"`
+	DocDefaults  *DocDefaults  `xml:"w:docDefaults,omitempty"`
+	LatentStyles *LatentStyles `xml:"w:latentStyles,omitempty"`
+	Styles       []Style       `xml:"w:style"`
 }
 
 // StyleManager 样式管理器
 type StyleManager struct {
-	styles map[string]*Style
+	styles       map[string]*Style
+	docDefaults  *DocDefaults  // 文档默认格式（w:docDefaults）
+	latentStyles *LatentStyles // 隐含样式设置（w:latentStyles）
+
+	// source 解析时的原始样式XML，用于无损输出未修改的部分
+	source *stylesSource
+	// implicit 加载文档时补充的预定义样式，仅在被使用时输出
+	implicit map[string]bool
 }
 
 // NewStyleManager 创建新的样式管理器
@@ -1120,6 +1243,11 @@ func (sm *StyleManager) GetStyleWithInheritance(styleID string) *Style {
 		Next:        style.Next,
 		Default:     style.Default,
 		CustomStyle: style.CustomStyle,
+		Aliases:     style.Aliases,
+		Link:        style.Link,
+		UIPriority:  style.UIPriority,
+		QFormat:     style.QFormat,
+		SemiHidden:  style.SemiHidden,
 	}
 
 	// 合并段落属性
@@ -1134,6 +1262,19 @@ func (sm *StyleManager) GetStyleWithInheritance(styleID string) *Style {
 	} else if baseStyle.TablePr != nil {
 		mergedStyle.TablePr = baseStyle.TablePr
 	}
+	if style.TableRowPr != nil {
+		mergedStyle.TableRowPr = style.TableRowPr
+	} else {
+		mergedStyle.TableRowPr = baseStyle.TableRowPr
+	}
+	if style.TableCellPr != nil {
+		mergedStyle.TableCellPr = style.TableCellPr
+	} else {
+		mergedStyle.TableCellPr = baseStyle.TableCellPr
+	}
+
+	// 按类型合并表格条件格式
+	mergedStyle.TableStylePr = mergeTableStyleProperties(baseStyle.TableStylePr, style.TableStylePr)
 
 	return mergedStyle
 }
@@ -1184,6 +1325,13 @@ func mergeParagraphProperties(base, override *ParagraphProperties) *ParagraphPro
 		merged.Shading = base.Shading
 	}
 
+	// 合并制表位
+	if override.Tabs != nil {
+		merged.Tabs = override.Tabs
+	} else if base.Tabs != nil {
+		merged.Tabs = base.Tabs
+	}
+
 	// 合并其他属性
 	if override.KeepNext != nil {
 		merged.KeepNext = override.KeepNext
@@ -1209,6 +1357,12 @@ func mergeParagraphProperties(base, override *ParagraphProperties) *ParagraphPro
 		merged.OutlineLevel = base.OutlineLevel
 	}
 
+	if override.NumPr != nil {
+		merged.NumPr = override.NumPr
+	} else if base.NumPr != nil {
+		merged.NumPr = base.NumPr
+	}
+
 	return merged
 }
 
@@ -1311,7 +1465,14 @@ func (sm *StyleManager) StyleExists(styleID string) bool {
 // Clone 深拷贝样式管理器，用于模板渲染时避免样式冲突
 func (sm *StyleManager) Clone() *StyleManager {
 	clonedSM := &StyleManager{
-		styles: make(map[string]*Style),
+		styles:       make(map[string]*Style),
+		docDefaults:  sm.cloneDocDefaults(sm.docDefaults),
+		latentStyles: cloneLatentStyles(sm.latentStyles),
+		source:       sm.source, // 原始XML只读，可以共享
+		implicit:     make(map[string]bool),
+	}
+	for styleID := range sm.implicit {
+		clonedSM.implicit[styleID] = true
 	}
 
 	// 深拷贝所有样式
@@ -1350,6 +1511,26 @@ func (sm *StyleManager) cloneStyle(source *Style) *Style {
 		cloned.Next = &Next{Val: source.Next.Val}
 	}
 
+	// 克隆样式库元数据
+	if source.Aliases != nil {
+		cloned.Aliases = &Aliases{Val: source.Aliases.Val}
+	}
+	if source.Link != nil {
+		cloned.Link = &StyleLink{Val: source.Link.Val}
+	}
+	if source.UIPriority != nil {
+		cloned.UIPriority = &UIPriority{Val: source.UIPriority.Val}
+	}
+	if source.Rsid != nil {
+		cloned.Rsid = &Rsid{Val: source.Rsid.Val}
+	}
+	cloned.AutoRedefine = cloneOnOff(source.AutoRedefine)
+	cloned.Hidden = cloneOnOff(source.Hidden)
+	cloned.SemiHidden = cloneOnOff(source.SemiHidden)
+	cloned.UnhideWhenUsed = cloneOnOff(source.UnhideWhenUsed)
+	cloned.QFormat = cloneOnOff(source.QFormat)
+	cloned.Locked = cloneOnOff(source.Locked)
+
 	// 克隆段落属性
 	if source.ParagraphPr != nil {
 		cloned.ParagraphPr = sm.cloneParagraphProperties(source.ParagraphPr)
@@ -1375,6 +1556,12 @@ func (sm *StyleManager) cloneStyle(source *Style) *Style {
 		cloned.TableCellPr = sm.cloneTableCellProperties(source.TableCellPr)
 	}
 
+	// 克隆表格条件格式
+	for _, conditional := range source.TableStylePr {
+		cloned.TableStylePr = append(cloned.TableStylePr, sm.cloneTableStyleProperties(conditional))
+	}
+
+	cloned.Extra = cloneUnknownElements(source.Extra)
 	return cloned
 }
 
@@ -1407,6 +1594,7 @@ func (sm *StyleManager) cloneParagraphProperties(source *ParagraphProperties) *P
 	if source.Indentation != nil {
 		cloned.Indentation = &Indentation{
 			FirstLine: source.Indentation.FirstLine,
+			Hanging:   source.Indentation.Hanging,
 			Left:      source.Indentation.Left,
 			Right:     source.Indentation.Right,
 		}
@@ -1452,8 +1640,17 @@ func (sm *StyleManager) cloneParagraphProperties(source *ParagraphProperties) *P
 	// 复制阴影
 	if source.Shading != nil {
 		cloned.Shading = &Shading{
-			Fill: source.Shading.Fill,
-			Val:  source.Shading.Val,
+			Fill:  source.Shading.Fill,
+			Val:   source.Shading.Val,
+			Color: source.Shading.Color,
+		}
+	}
+
+	// 复制制表位
+	if source.Tabs != nil {
+		cloned.Tabs = &Tabs{}
+		for _, tab := range source.Tabs.Tabs {
+			cloned.Tabs.Tabs = append(cloned.Tabs.Tabs, TabDef{Val: tab.Val, Leader: tab.Leader, Pos: tab.Pos})
 		}
 	}
 
@@ -1483,6 +1680,18 @@ func (sm *StyleManager) cloneParagraphProperties(source *ParagraphProperties) *P
 		}
 	}
 
+	// 复制编号属性
+	if source.NumPr != nil {
+		cloned.NumPr = &NumPr{}
+		if source.NumPr.ILvl != nil {
+			cloned.NumPr.ILvl = &NumPrILvl{Val: source.NumPr.ILvl.Val}
+		}
+		if source.NumPr.NumID != nil {
+			cloned.NumPr.NumID = &NumPrID{Val: source.NumPr.NumID.Val}
+		}
+	}
+
+	cloned.Extra = cloneUnknownElements(source.Extra)
 	return cloned
 }
 
@@ -1496,11 +1705,11 @@ func (sm *StyleManager) cloneRunProperties(source *RunProperties) *RunProperties
 
 	// 克隆字体格式
 	if source.Bold != nil {
-		cloned.Bold = &Bold{}
+		cloned.Bold = &Bold{Val: source.Bold.Val}
 	}
 
 	if source.Italic != nil {
-		cloned.Italic = &Italic{}
+		cloned.Italic = &Italic{Val: source.Italic.Val}
 	}
 
 	if source.Underline != nil {
@@ -1518,16 +1727,25 @@ func (sm *StyleManager) cloneRunProperties(source *RunProperties) *RunProperties
 
 	// 克隆颜色
 	if source.Color != nil {
-		cloned.Color = &Color{Val: source.Color.Val}
+		cloned.Color = &Color{
+			Val:        source.Color.Val,
+			ThemeColor: source.Color.ThemeColor,
+			ThemeTint:  source.Color.ThemeTint,
+			ThemeShade: source.Color.ThemeShade,
+		}
 	}
 
 	// 克隆字体族
 	if source.FontFamily != nil {
 		cloned.FontFamily = &FontFamily{
-			ASCII:    source.FontFamily.ASCII,
-			EastAsia: source.FontFamily.EastAsia,
-			HAnsi:    source.FontFamily.HAnsi,
-			CS:       source.FontFamily.CS,
+			ASCII:         source.FontFamily.ASCII,
+			EastAsia:      source.FontFamily.EastAsia,
+			HAnsi:         source.FontFamily.HAnsi,
+			CS:            source.FontFamily.CS,
+			ASCIITheme:    source.FontFamily.ASCIITheme,
+			EastAsiaTheme: source.FontFamily.EastAsiaTheme,
+			HAnsiTheme:    source.FontFamily.HAnsiTheme,
+			CSTheme:       source.FontFamily.CSTheme,
 		}
 	}
 
@@ -1536,6 +1754,7 @@ func (sm *StyleManager) cloneRunProperties(source *RunProperties) *RunProperties
 		cloned.Highlight = &Highlight{Val: source.Highlight.Val}
 	}
 
+	cloned.Extra = cloneUnknownElements(source.Extra)
 	return cloned
 }
 
@@ -1547,6 +1766,20 @@ func (sm *StyleManager) cloneTableProperties(source *TableProperties) *TableProp
 
 	cloned := &TableProperties{}
 
+	// 克隆镶边大小
+	if source.RowBandSize != nil {
+		cloned.RowBandSize = &BandSize{Val: source.RowBandSize.Val}
+	}
+	if source.ColBandSize != nil {
+		cloned.ColBandSize = &BandSize{Val: source.ColBandSize.Val}
+	}
+
+	// 克隆表格底纹
+	if source.Shading != nil {
+		shading := *source.Shading
+		cloned.Shading = &shading
+	}
+
 	// 克隆表格缩进
 	if source.TblInd != nil {
 		cloned.TblInd = &TblIndent{
@@ -1647,6 +1880,7 @@ func (sm *StyleManager) cloneTableProperties(source *TableProperties) *TableProp
 		}
 	}
 
+	cloned.Extra = cloneUnknownElements(source.Extra)
 	return cloned
 }
 
@@ -1656,8 +1890,14 @@ func (sm *StyleManager) cloneTableRowProperties(source *TableRowProperties) *Tab
 		return nil
 	}
 
-	// 目前表格行属性是空结构体，简单返回新实例
-	cloned := &TableRowProperties{}
+	cloned := &TableRowProperties{
+		CantSplit: cloneOnOff(source.CantSplit),
+		TblHeader: cloneOnOff(source.TblHeader),
+	}
+	if source.Jc != nil {
+		cloned.Jc = &TableJc{Val: source.Jc.Val}
+	}
+	cloned.Extra = cloneUnknownElements(source.Extra)
 
 	return cloned
 }
@@ -1668,8 +1908,18 @@ func (sm *StyleManager) cloneTableCellProperties(source *TableCellProperties) *T
 		return nil
 	}
 
-	// 目前表格单元格属性是空结构体，简单返回新实例
-	cloned := &TableCellProperties{}
+	cloned := &TableCellProperties{
+		TcBorders: cloneTcBorders(source.TcBorders),
+		NoWrap:    cloneOnOff(source.NoWrap),
+	}
+	if source.Shading != nil {
+		shading := *source.Shading
+		cloned.Shading = &shading
+	}
+	if source.VAlign != nil {
+		cloned.VAlign = &VAlign{Val: source.VAlign.Val}
+	}
+	cloned.Extra = cloneUnknownElements(source.Extra)
 
 	return cloned
 }
@@ -1819,18 +2069,23 @@ func convertRunPropertiesToMap(props *RunProperties) map[string]interface{} {
 
 // ParseStylesFromXML 从XML数据解析样式
 func (sm *StyleManager) ParseStylesFromXML(xmlData []byte) error {
-	type stylesXML struct {
-		XMLName xml.Name `xml:"w:styles"`
-		Styles  []Style  `xml:"w:style"`
+	var styles Styles
+	if err := newStylesDecoder(xmlData).Decode(&styles); err != nil {
+		return fmt.Errorf("解析样式XML失败: %v", err)
 	}
 
-	var styles stylesXML
-	if err := xml.Unmarshal(xmlData, &styles); err != nil {
+	// 记录原始XML，保存时未修改的部分原样输出
+	source, err := newStylesSource(xmlData, &styles)
+	if err != nil {
 		return fmt.Errorf("解析样式XML失败: %v", err)
 	}
 
 	// 清空现有样式（除非我们想要合并）
 	sm.styles = make(map[string]*Style)
+	sm.docDefaults = styles.DocDefaults
+	sm.latentStyles = styles.LatentStyles
+	sm.source = source
+	sm.implicit = nil
 
 	// 添加解析的样式
 	for i := range styles.Styles {
@@ -1840,26 +2095,46 @@ func (sm *StyleManager) ParseStylesFromXML(xmlData []byte) error {
 	return nil
 }
 
-// MergeStylesFromXML 从XML数据合并样式（保留现有样式，只添加新的）
-func (sm *StyleManager) MergeStylesFromXML(xmlData []byte) error {
-	type stylesXML struct {
-		XMLName xml.Name `xml:"w:styles"`
-		Styles  []Style  `xml:"w:style"`
-	}
+// prefixedTokenReader 以带前缀的名称（如 "w:style"）返回XML标记
+//
+// 样式结构体的标签使用 "w:" 前缀名称，而标准解码器会把前缀解析为命名空间，
+// 导致 Word 生成的 styles.xml 无法匹配。这里直接使用原始标记保留前缀。
+type prefixedTokenReader struct {
+	decoder *xml.Decoder
+}
 
-	var styles stylesXML
-	if err := xml.Unmarshal(xmlData, &styles); err != nil {
-		return fmt.Errorf("解析样式XML失败: %v", err)
+// Token 返回下一个带前缀名称的XML标记
+func (r *prefixedTokenReader) Token() (xml.Token, error) {
+	token, err := r.decoder.RawToken()
+	if err != nil {
+		return nil, err
 	}
-
-	// 只添加不存在的样式，保留现有样式
-	for i := range styles.Styles {
-		if !sm.StyleExists(styles.Styles[i].StyleID) {
-			sm.AddStyle(&styles.Styles[i])
+	switch t := token.(type) {
+	case xml.StartElement:
+		start := t.Copy()
+		start.Name = prefixedName(start.Name)
+		for i := range start.Attr {
+			start.Attr[i].Name = prefixedName(start.Attr[i].Name)
 		}
+		return start, nil
+	case xml.EndElement:
+		return xml.EndElement{Name: prefixedName(t.Name)}, nil
+	default:
+		return xml.CopyToken(token), nil
 	}
+}
 
-	return nil
+// prefixedName 将 {Space: "w", Local: "style"} 转换为 {Local: "w:style"}
+func prefixedName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// newStylesDecoder 创建按前缀名称匹配的样式XML解码器
+func newStylesDecoder(xmlData []byte) *xml.Decoder {
+	return xml.NewTokenDecoder(&prefixedTokenReader{decoder: xml.NewDecoder(bytes.NewReader(xmlData))})
 }
 
 // LoadStylesFromDocument 从现有文档加载样式，优先保留原有样式设置
@@ -1877,6 +2152,12 @@ func (sm *StyleManager) LoadStylesFromDocument(xmlData []byte) error {
 		return fmt.Errorf("解析现有样式失败，使用默认样式: %v", err)
 	}
 
+	parsed := make(map[string]*Style, len(sm.styles))
+	for styleID, s := range sm.styles {
+		parsed[styleID] = s
+	}
+	defaultParagraph := sm.GetDefaultStyle(StyleTypeParagraph)
+
 	// 确保基本样式存在，如果不存在则添加
 	if !sm.StyleExists("Normal") {
 		sm.addNormalStyle()
@@ -1891,6 +2172,20 @@ func (sm *StyleManager) LoadStylesFromDocument(xmlData []byte) error {
 		}
 	}
 
+	// 保留文档中已有的同ID样式，补充的样式标记为隐式样式，只在被使用时写入样式表
+	sm.implicit = make(map[string]bool)
+	for styleID := range sm.styles {
+		if original, ok := parsed[styleID]; ok {
+			sm.styles[styleID] = original
+		} else {
+			sm.implicit[styleID] = true
+		}
+	}
+	// 文档已有默认段落样式（如中文版Word的 "a"）时，补充的 Normal 不能再作为默认样式
+	if defaultParagraph != nil && sm.implicit["Normal"] {
+		sm.styles["Normal"].Default = false
+	}
+
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package style
 
 import (
+	"encoding/xml"
+	"strings"
 	"testing"
 )
 
@@ -367,3 +369,182 @@ func BenchmarkStyleWithInheritance(b *testing.B) {
 		sm.GetStyleWithInheritance("Heading1")
 	}
 }
+
+// TestParseStylesWithDocDefaults 测试解析带命名空间的样式XML及文档默认格式
+func TestParseStylesWithDocDefaults(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:docDefaults>
+    <w:rPrDefault><w:rPr><w:rFonts w:asciiTheme="minorHAnsi" w:eastAsiaTheme="minorEastAsia"/><w:sz w:val="21"/></w:rPr></w:rPrDefault>
+    <w:pPrDefault><w:pPr><w:spacing w:after="160" w:line="259" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+  </w:docDefaults>
+  <w:style w:type="paragraph" w:default="1" w:styleId="a"><w:name w:val="Normal"/><w:pPr><w:ind w:hanging="210"/></w:pPr></w:style>
+  <w:style w:type="character" w:styleId="a3"><w:name w:val="Strong"/><w:rPr><w:b w:val="0"/></w:rPr></w:style>
+</w:styles>`)
+
+	sm := NewStyleManager()
+	if err := sm.ParseStylesFromXML(data); err != nil {
+		t.Fatalf("解析样式失败: %v", err)
+	}
+
+	defaults := sm.GetDocumentDefaults()
+	if defaults.RunProperties().FontSize.Val != "21" || defaults.RunProperties().FontFamily.EastAsiaTheme != "minorEastAsia" {
+		t.Errorf("默认字符属性解析不正确: %+v", defaults.RunProperties())
+	}
+	if defaults.ParagraphProperties().Spacing.Line != "259" {
+		t.Errorf("默认段落属性解析不正确: %+v", defaults.ParagraphProperties())
+	}
+
+	normal := sm.GetDefaultStyle(StyleTypeParagraph)
+	if normal == nil || normal.StyleID != "a" || normal.ParagraphPr.Indentation.Hanging != "210" {
+		t.Errorf("默认段落样式解析不正确: %+v", normal)
+	}
+	if strong := sm.GetStyle("a3"); strong == nil || strong.RunPr.Bold.Val != "0" {
+		t.Error("粗体的关闭值应被保留")
+	}
+
+	// 克隆后修改默认格式不影响原管理器
+	cloned := sm.Clone()
+	cloned.SetDocumentDefaults(nil, nil)
+	if cloned.GetDocumentDefaults() != nil || sm.GetDocumentDefaults() == nil {
+		t.Error("文档默认格式的克隆或清除不正确")
+	}
+}
+
+// TestTableStyleConditionalFormats 测试表格条件格式的解析、构建和继承
+func TestTableStyleConditionalFormats(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:style w:type="table" w:default="1" w:styleId="TableNormal"><w:name w:val="Normal Table"/></w:style>
+  <w:style w:type="table" w:styleId="GridTable4-Accent1"><w:name w:val="Grid Table 4 Accent 1"/><w:basedOn w:val="TableNormal"/>
+    <w:tblPr><w:tblStyleRowBandSize w:val="1"/><w:tblStyleColBandSize w:val="1"/><w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="8EAADB"/></w:tblBorders></w:tblPr>
+    <w:tblStylePr w:type="firstRow"><w:rPr><w:b/><w:color w:val="FFFFFF"/></w:rPr><w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="4472C4"/></w:tcPr></w:tblStylePr>
+    <w:tblStylePr w:type="band1Horz"><w:tcPr><w:shd w:val="clear" w:color="auto" w:fill="D9E2F3"/></w:tcPr></w:tblStylePr>
+  </w:style>
+</w:styles>`)
+
+	sm := NewStyleManager()
+	if err := sm.ParseStylesFromXML(data); err != nil {
+		t.Fatalf("解析样式失败: %v", err)
+	}
+	grid := sm.GetStyle("GridTable4-Accent1")
+	if grid == nil || grid.TablePr.RowBandSize.Val != "1" || len(grid.TableStylePr) != 2 {
+		t.Fatalf("表格样式解析不正确: %+v", grid)
+	}
+	header := grid.GetConditionalFormat(TableConditionFirstRow)
+	if header == nil || header.RunPr.Color.Val != "FFFFFF" || header.TableCellPr.Shading.Fill != "4472C4" {
+		t.Errorf("标题行条件格式解析不正确: %+v", header)
+	}
+
+	// 构建器生成的样式继承基础样式的条件格式，同类型的条件格式合并
+	custom := NewTableStyleBuilder("BrandTable", "品牌表格").
+		BasedOn("GridTable4-Accent1").
+		BandSize(2, 0).
+		Conditional(TableConditionFirstRow, &TableConditionConfig{RunConfig: &QuickRunConfig{Italic: true}}).
+		Conditional(TableConditionLastRow, &TableConditionConfig{Fill: "EEEEEE", VAlign: "center"}).
+		Build()
+	sm.AddStyle(custom)
+	merged := sm.GetStyleWithInheritance("BrandTable")
+	types := make([]TableStyleOverrideType, 0, len(merged.TableStylePr))
+	for _, conditional := range merged.TableStylePr {
+		types = append(types, conditional.Type)
+	}
+	if len(types) != 3 || types[0] != TableConditionBand1Horz || types[1] != TableConditionFirstRow || types[2] != TableConditionLastRow {
+		t.Errorf("条件格式应按应用顺序排列: %v", types)
+	}
+	header = merged.GetConditionalFormat(TableConditionFirstRow)
+	if header.RunPr.Bold == nil || header.RunPr.Italic == nil || header.TableCellPr.Shading.Fill != "4472C4" {
+		t.Errorf("标题行条件格式未与基础样式合并: %+v", header)
+	}
+
+	output, err := xml.Marshal(sm.Clone().GetStyle("BrandTable"))
+	if err != nil {
+		t.Fatalf("序列化表格样式失败: %v", err)
+	}
+	for _, want := range []string{`<w:tblStyleRowBandSize w:val="2">`, `<w:tblStylePr w:type="lastRow">`, `<w:vAlign w:val="center">`} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("序列化结果缺少 %s:\n%s", want, output)
+		}
+	}
+}
+
+// TestStylesLosslessRoundTrip 测试样式表的无损读写、元数据保留和链接样式
+func TestStylesLosslessRoundTrip(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml">
+  <w:docDefaults><w:rPrDefault><w:rPr><w:sz w:val="21"/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr></w:rPrDefault></w:docDefaults>
+  <w:latentStyles w:defLockedState="0" w:defUIPriority="99" w:defSemiHidden="0" w:defUnhideWhenUsed="0" w:defQFormat="0" w:count="376">
+    <w:lsdException w:name="Normal" w:uiPriority="0" w:qFormat="1"/>
+    <w:lsdException w:name="heading 1" w:uiPriority="9" w:qFormat="1"/>
+  </w:latentStyles>
+  <w:style w:type="paragraph" w:default="1" w:styleId="a"><w:name w:val="Normal"/><w:qFormat/><w:pPr><w:widowControl w:val="0"/><w:jc w:val="both"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/><w:aliases w:val="章标题"/><w:basedOn w:val="a"/><w:next w:val="a"/><w:link w:val="10"/><w:uiPriority w:val="9"/><w:qFormat/><w:rsid w:val="00A1B2C3"/><w:pPr><w:keepNext/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:bCs/><w:kern w:val="44"/><w:sz w:val="44"/></w:rPr></w:style>
+  <w:style w:type="character" w:customStyle="1" w:styleId="10"><w:name w:val="标题 1 字符"/><w:basedOn w:val="a0"/><w:link w:val="1"/><w:uiPriority w:val="9"/><w:rPr><w:b/><w:kern w:val="44"/><w:sz w:val="44"/><w14:ligatures w14:val="standard"/></w:rPr></w:style>
+  <w:style w:type="character" w:default="1" w:styleId="a0"><w:name w:val="Default Paragraph Font"/><w:uiPriority w:val="1"/><w:semiHidden/><w:unhideWhenUsed/></w:style>
+</w:styles>`)
+
+	sm := NewStyleManager()
+	if err := sm.ParseStylesFromXML(data); err != nil {
+		t.Fatalf("解析样式失败: %v", err)
+	}
+
+	// 未修改时原样输出
+	output, err := sm.MarshalStylesXML(nil)
+	if err != nil {
+		t.Fatalf("生成样式表失败: %v", err)
+	}
+	if string(output) != string(data) {
+		t.Errorf("未修改的样式表应原样输出:\n%s", output)
+	}
+
+	heading := sm.GetStyle("1")
+	if heading.Aliases.Val != "章标题" || heading.UIPriority.Val != "9" || heading.QFormat == nil || heading.Rsid.Val != "00A1B2C3" {
+		t.Errorf("样式元数据解析不正确: %+v", heading)
+	}
+	if a0 := sm.GetStyle("a0"); a0.SemiHidden == nil || a0.UnhideWhenUsed == nil {
+		t.Error("semiHidden 和 unhideWhenUsed 应被保留")
+	}
+	latent := sm.GetLatentStyles()
+	if latent == nil || latent.Count != "376" || latent.GetException("heading 1").UIPriority != "9" {
+		t.Errorf("隐含样式设置解析不正确: %+v", latent)
+	}
+
+	pairs := sm.GetLinkedStylePairs()
+	if len(pairs) != 1 || pairs[0].Paragraph.StyleID != "1" || pairs[0].Character.StyleID != "10" {
+		t.Errorf("链接样式对不正确: %+v", pairs)
+	}
+	if err := sm.LinkStyles("10", "1"); err == nil {
+		t.Error("段落样式与字符样式参数颠倒时应返回错误")
+	}
+
+	// 修改后的样式重新生成，未建模的子元素按规定顺序保留，其余部分保持原样
+	heading.RunPr.Color = &Color{Val: "2F5496"}
+	sm.RemoveStyle("a0")
+	output, err = sm.MarshalStylesXML(nil)
+	if err != nil {
+		t.Fatalf("生成样式表失败: %v", err)
+	}
+	result := string(output)
+	for _, want := range []string{
+		`<w:rPr><w:b></w:b><w:bCs></w:bCs><w:color w:val="2F5496"></w:color><w:kern w:val="44"></w:kern><w:sz w:val="44"></w:sz></w:rPr>`,
+		`<w:link w:val="10"></w:link><w:uiPriority w:val="9"></w:uiPriority><w:qFormat></w:qFormat><w:rsid w:val="00A1B2C3"></w:rsid>`,
+		`<w14:ligatures w14:val="standard"/>`,
+		`<w:lsdException w:name="heading 1" w:uiPriority="9" w:qFormat="1"/>`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("样式表缺少 %s:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, `w:styleId="a0"`) {
+		t.Error("删除的样式不应输出")
+	}
+
+	// 重新解析生成的样式表结果一致
+	reparsed := NewStyleManager()
+	if err := reparsed.ParseStylesFromXML(output); err != nil {
+		t.Fatalf("重新解析样式表失败: %v", err)
+	}
+	if s := reparsed.GetStyle("1"); s.RunPr.Color.Val != "2F5496" || len(s.RunPr.Extra) != 2 || s.Link.Val != "10" {
+		t.Errorf("重新解析的样式不正确: %+v", s)
+	}
+}
@@ -0,0 +1,1112 @@
+// Package style 样式表（设计令牌）的导入导出
+package style
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StyleSheetFormat 样式表文件格式
+type StyleSheetFormat string
+
+const (
+	// StyleSheetJSON JSON格式
+	StyleSheetJSON StyleSheetFormat = "json"
+	// StyleSheetYAML YAML格式
+	StyleSheetYAML StyleSheetFormat = "yaml"
+)
+
+// StyleSheetVersion 当前的样式表格式版本
+const StyleSheetVersion = 1
+
+// StyleSheet 可版本管理的样式表（设计令牌）
+//
+// 长度使用磅（pt），字号使用磅，颜色使用十六进制（如 "1F4E79"）。
+// 样式表未覆盖的XML子元素以原始XML字符串保存在 extra 中，导入时原样写回。
+type StyleSheet struct {
+	Version  int                 `json:"version" yaml:"version"`
+	Defaults *StyleSheetDefaults `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Styles   []*StyleDefinition  `json:"styles" yaml:"styles"`
+}
+
+// StyleSheetDefaults 文档默认格式（docDefaults）
+type StyleSheetDefaults struct {
+	Paragraph *ParagraphTokens `json:"paragraph,omitempty" yaml:"paragraph,omitempty"`
+	Run       *RunTokens       `json:"run,omitempty" yaml:"run,omitempty"`
+}
+
+// StyleDefinition 样式表中的样式定义
+type StyleDefinition struct {
+	ID             string    `json:"id" yaml:"id"`
+	Name           string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Type           StyleType `json:"type" yaml:"type"`
+	BasedOn        string    `json:"basedOn,omitempty" yaml:"basedOn,omitempty"`
+	Next           string    `json:"next,omitempty" yaml:"next,omitempty"`
+	Link           string    `json:"link,omitempty" yaml:"link,omitempty"`
+	Aliases        string    `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Default        bool      `json:"default,omitempty" yaml:"default,omitempty"`
+	Custom         bool      `json:"custom,omitempty" yaml:"custom,omitempty"`
+	UIPriority     *int      `json:"uiPriority,omitempty" yaml:"uiPriority,omitempty"`
+	QuickFormat    bool      `json:"quickFormat,omitempty" yaml:"quickFormat,omitempty"`
+	Hidden         bool      `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	SemiHidden     bool      `json:"semiHidden,omitempty" yaml:"semiHidden,omitempty"`
+	UnhideWhenUsed bool      `json:"unhideWhenUsed,omitempty" yaml:"unhideWhenUsed,omitempty"`
+	AutoRedefine   bool      `json:"autoRedefine,omitempty" yaml:"autoRedefine,omitempty"`
+	Locked         bool      `json:"locked,omitempty" yaml:"locked,omitempty"`
+
+	Paragraph *ParagraphTokens `json:"paragraph,omitempty" yaml:"paragraph,omitempty"`
+	Run       *RunTokens       `json:"run,omitempty" yaml:"run,omitempty"`
+	Table     *TableTokens     `json:"table,omitempty" yaml:"table,omitempty"`
+	Row       *TableRowTokens  `json:"row,omitempty" yaml:"row,omitempty"`
+	Cell      *TableCellTokens `json:"cell,omitempty" yaml:"cell,omitempty"`
+	// Conditions 表格样式的条件格式（首行、镶边行等）
+	Conditions []*TableConditionTokens `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	// Extra 未建模的样式子元素（原始XML）
+	Extra []string `json:"extra,omitempty" yaml:"extra,omitempty"`
+}
+
+// ParagraphTokens 段落属性
+type ParagraphTokens struct {
+	Alignment       string         `json:"alignment,omitempty" yaml:"alignment,omitempty"`             // 对齐方式（left、center、right、both、distribute）
+	SpaceBefore     *float64       `json:"spaceBefore,omitempty" yaml:"spaceBefore,omitempty"`         // 段前间距（磅）
+	SpaceAfter      *float64       `json:"spaceAfter,omitempty" yaml:"spaceAfter,omitempty"`           // 段后间距（磅）
+	LineSpacing     *float64       `json:"lineSpacing,omitempty" yaml:"lineSpacing,omitempty"`         // 行距：lineRule 为 auto 时为倍数，exact、atLeast 时为磅值
+	LineRule        string         `json:"lineRule,omitempty" yaml:"lineRule,omitempty"`               // 行距规则（auto、exact、atLeast），默认为 auto
+	FirstLineIndent *float64       `json:"firstLineIndent,omitempty" yaml:"firstLineIndent,omitempty"` // 首行缩进（磅）
+	HangingIndent   *float64       `json:"hangingIndent,omitempty" yaml:"hangingIndent,omitempty"`     // 悬挂缩进（磅）
+	LeftIndent      *float64       `json:"leftIndent,omitempty" yaml:"leftIndent,omitempty"`           // 左缩进（磅）
+	RightIndent     *float64       `json:"rightIndent,omitempty" yaml:"rightIndent,omitempty"`         // 右缩进（磅）
+	KeepNext        bool           `json:"keepNext,omitempty" yaml:"keepNext,omitempty"`               // 与下段同页
+	KeepLines       bool           `json:"keepLines,omitempty" yaml:"keepLines,omitempty"`             // 段中不分页
+	PageBreakBefore bool           `json:"pageBreakBefore,omitempty" yaml:"pageBreakBefore,omitempty"` // 段前分页
+	OutlineLevel    *int           `json:"outlineLevel,omitempty" yaml:"outlineLevel,omitempty"`       // 大纲级别（0-8）
+	SnapToGrid      *bool          `json:"snapToGrid,omitempty" yaml:"snapToGrid,omitempty"`           // 对齐文档网格
+	Numbering       *NumberingLink `json:"numbering,omitempty" yaml:"numbering,omitempty"`             // 关联的编号定义
+	Borders         *BorderSet     `json:"borders,omitempty" yaml:"borders,omitempty"`                 // 段落边框（top、left、bottom、right）
+	Shading         *ShadingTokens `json:"shading,omitempty" yaml:"shading,omitempty"`                 // 段落底纹
+	Tabs            []TabStopToken `json:"tabs,omitempty" yaml:"tabs,omitempty"`                       // 制表位
+	Extra           []string       `json:"extra,omitempty" yaml:"extra,omitempty"`                     // 未建模的属性（原始XML）
+}
+
+// NumberingLink 样式关联的编号定义
+type NumberingLink struct {
+	NumID int  `json:"numId" yaml:"numId"`                     // 编号实例ID（numbering.xml 中的 w:num）
+	Level *int `json:"level,omitempty" yaml:"level,omitempty"` // 编号级别（0-8）
+}
+
+// BorderSet 边框集合，段落边框只使用 top、left、bottom、right
+type BorderSet struct {
+	Top     *BorderToken `json:"top,omitempty" yaml:"top,omitempty"`
+	Left    *BorderToken `json:"left,omitempty" yaml:"left,omitempty"`
+	Bottom  *BorderToken `json:"bottom,omitempty" yaml:"bottom,omitempty"`
+	Right   *BorderToken `json:"right,omitempty" yaml:"right,omitempty"`
+	InsideH *BorderToken `json:"insideH,omitempty" yaml:"insideH,omitempty"`
+	InsideV *BorderToken `json:"insideV,omitempty" yaml:"insideV,omitempty"`
+	TL2BR   *BorderToken `json:"tl2br,omitempty" yaml:"tl2br,omitempty"`
+	TR2BL   *BorderToken `json:"tr2bl,omitempty" yaml:"tr2bl,omitempty"`
+}
+
+// BorderToken 边框线
+type BorderToken struct {
+	Style string   `json:"style" yaml:"style"`                     // 线型（single、double、dashed、none 等）
+	Size  *float64 `json:"size,omitempty" yaml:"size,omitempty"`   // 线宽（磅）
+	Color string   `json:"color,omitempty" yaml:"color,omitempty"` // 颜色
+	Space *float64 `json:"space,omitempty" yaml:"space,omitempty"` // 与文本的间距（磅）
+}
+
+// ShadingTokens 底纹
+type ShadingTokens struct {
+	Fill    string `json:"fill,omitempty" yaml:"fill,omitempty"`       // 填充色
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"` // 图案（clear、solid 等）
+	Color   string `json:"color,omitempty" yaml:"color,omitempty"`     // 图案颜色
+}
+
+// TabStopToken 制表位
+type TabStopToken struct {
+	Position  float64 `json:"position" yaml:"position"`                 // 位置（磅）
+	Alignment string  `json:"alignment" yaml:"alignment"`               // 对齐方式（left、center、right、decimal、bar、clear）
+	Leader    string  `json:"leader,omitempty" yaml:"leader,omitempty"` // 前导符（dot、hyphen、underscore 等）
+}
+
+// RunTokens 字符属性
+type RunTokens struct {
+	Fonts      *FontTokens `json:"fonts,omitempty" yaml:"fonts,omitempty"`
+	Size       *float64    `json:"size,omitempty" yaml:"size,omitempty"`             // 字号（磅）
+	Color      string      `json:"color,omitempty" yaml:"color,omitempty"`           // 颜色
+	ThemeColor string      `json:"themeColor,omitempty" yaml:"themeColor,omitempty"` // 主题颜色（如 accent1），color 为其回退值
+	ThemeTint  string      `json:"themeTint,omitempty" yaml:"themeTint,omitempty"`   // 主题颜色淡化（十六进制 00-FF）
+	ThemeShade string      `json:"themeShade,omitempty" yaml:"themeShade,omitempty"` // 主题颜色加深（十六进制 00-FF）
+	Bold       *bool       `json:"bold,omitempty" yaml:"bold,omitempty"`             // 粗体，false 表示显式关闭
+	Italic     *bool       `json:"italic,omitempty" yaml:"italic,omitempty"`         // 斜体，false 表示显式关闭
+	Underline  string      `json:"underline,omitempty" yaml:"underline,omitempty"`   // 下划线类型（single、double 等）
+	Strike     bool        `json:"strike,omitempty" yaml:"strike,omitempty"`         // 删除线
+	Highlight  string      `json:"highlight,omitempty" yaml:"highlight,omitempty"`   // 突出显示颜色（yellow 等）
+	Extra      []string    `json:"extra,omitempty" yaml:"extra,omitempty"`           // 未建模的属性（原始XML）
+}
+
+// FontTokens 字体
+type FontTokens struct {
+	ASCII         string `json:"ascii,omitempty" yaml:"ascii,omitempty"`
+	EastAsia      string `json:"eastAsia,omitempty" yaml:"eastAsia,omitempty"`
+	HAnsi         string `json:"hAnsi,omitempty" yaml:"hAnsi,omitempty"`
+	CS            string `json:"cs,omitempty" yaml:"cs,omitempty"`
+	ASCIITheme    string `json:"asciiTheme,omitempty" yaml:"asciiTheme,omitempty"`
+	EastAsiaTheme string `json:"eastAsiaTheme,omitempty" yaml:"eastAsiaTheme,omitempty"`
+	HAnsiTheme    string `json:"hAnsiTheme,omitempty" yaml:"hAnsiTheme,omitempty"`
+	CSTheme       string `json:"csTheme,omitempty" yaml:"csTheme,omitempty"`
+}
+
+// TableTokens 表格属性
+type TableTokens struct {
+	RowBandSize *int           `json:"rowBandSize,omitempty" yaml:"rowBandSize,omitempty"` // 镶边行包含的行数
+	ColBandSize *int           `json:"colBandSize,omitempty" yaml:"colBandSize,omitempty"` // 镶边列包含的列数
+	Indent      *float64       `json:"indent,omitempty" yaml:"indent,omitempty"`           // 表格缩进（磅）
+	Borders     *BorderSet     `json:"borders,omitempty" yaml:"borders,omitempty"`         // 表格边框
+	Shading     *ShadingTokens `json:"shading,omitempty" yaml:"shading,omitempty"`         // 表格底纹
+	CellMargins *MarginTokens  `json:"cellMargins,omitempty" yaml:"cellMargins,omitempty"` // 单元格边距
+	Extra       []string       `json:"extra,omitempty" yaml:"extra,omitempty"`             // 未建模的属性（原始XML）
+}
+
+// MarginTokens 边距（磅）
+type MarginTokens struct {
+	Top    *float64 `json:"top,omitempty" yaml:"top,omitempty"`
+	Left   *float64 `json:"left,omitempty" yaml:"left,omitempty"`
+	Bottom *float64 `json:"bottom,omitempty" yaml:"bottom,omitempty"`
+	Right  *float64 `json:"right,omitempty" yaml:"right,omitempty"`
+}
+
+// TableRowTokens 表格行属性
+type TableRowTokens struct {
+	CantSplit bool     `json:"cantSplit,omitempty" yaml:"cantSplit,omitempty"` // 行不跨页断开
+	Header    bool     `json:"header,omitempty" yaml:"header,omitempty"`       // 标题行重复
+	Alignment string   `json:"alignment,omitempty" yaml:"alignment,omitempty"` // 行对齐方式
+	Extra     []string `json:"extra,omitempty" yaml:"extra,omitempty"`         // 未建模的属性（原始XML）
+}
+
+// TableCellTokens 表格单元格属性
+type TableCellTokens struct {
+	Borders           *BorderSet     `json:"borders,omitempty" yaml:"borders,omitempty"`                     // 单元格边框
+	Shading           *ShadingTokens `json:"shading,omitempty" yaml:"shading,omitempty"`                     // 单元格底纹
+	NoWrap            bool           `json:"noWrap,omitempty" yaml:"noWrap,omitempty"`                       // 不自动换行
+	VerticalAlignment string         `json:"verticalAlignment,omitempty" yaml:"verticalAlignment,omitempty"` // 垂直对齐（top、center、bottom）
+	Extra             []string       `json:"extra,omitempty" yaml:"extra,omitempty"`                         // 未建模的属性（原始XML）
+}
+
+// TableConditionTokens 表格条件格式
+type TableConditionTokens struct {
+	Type      TableStyleOverrideType `json:"type" yaml:"type"`
+	Paragraph *ParagraphTokens       `json:"paragraph,omitempty" yaml:"paragraph,omitempty"`
+	Run       *RunTokens             `json:"run,omitempty" yaml:"run,omitempty"`
+	Table     *TableTokens           `json:"table,omitempty" yaml:"table,omitempty"`
+	Row       *TableRowTokens        `json:"row,omitempty" yaml:"row,omitempty"`
+	Cell      *TableCellTokens       `json:"cell,omitempty" yaml:"cell,omitempty"`
+}
+
+// ExportStyleSheet 将文档默认格式和所有段落、字符、表格、编号样式导出为样式表
+// 加载文档时补充但文档中未定义的预定义样式不导出
+func (sm *StyleManager) ExportStyleSheet(w io.Writer, format StyleSheetFormat) error {
+	sheet := sm.StyleSheet()
+	switch format {
+	case StyleSheetJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(sheet); err != nil {
+			return fmt.Errorf("导出样式表失败: %v", err)
+		}
+	case StyleSheetYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(sheet); err != nil {
+			return fmt.Errorf("导出样式表失败: %v", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("导出样式表失败: %v", err)
+		}
+	default:
+		return fmt.Errorf("不支持的样式表格式: %s", format)
+	}
+	return nil
+}
+
+// StyleSheet 返回当前样式的样式表表示，样式按类型（段落、字符、表格、编号）和ID排序
+func (sm *StyleManager) StyleSheet() *StyleSheet {
+	sheet := &StyleSheet{Version: StyleSheetVersion}
+	if sm.docDefaults != nil {
+		defaults := &StyleSheetDefaults{
+			Paragraph: paragraphTokens(sm.docDefaults.ParagraphProperties()),
+			Run:       runTokens(sm.docDefaults.RunProperties()),
+		}
+		if defaults.Paragraph != nil || defaults.Run != nil {
+			sheet.Defaults = defaults
+		}
+	}
+
+	keep := sm.stylesToWrite(func(string) bool { return false })
+	var styles []*Style
+	for styleID, s := range sm.styles {
+		if keep[styleID] {
+			styles = append(styles, s)
+		}
+	}
+	typeOrder := map[string]int{
+		string(StyleTypeParagraph): 0, string(StyleTypeCharacter): 1,
+		string(StyleTypeTable): 2, string(StyleTypeNumbering): 3,
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		if typeOrder[styles[i].Type] != typeOrder[styles[j].Type] {
+			return typeOrder[styles[i].Type] < typeOrder[styles[j].Type]
+		}
+		return styles[i].StyleID < styles[j].StyleID
+	})
+	for _, s := range styles {
+		sheet.Styles = append(sheet.Styles, styleDefinition(s))
+	}
+	return sheet
+}
+
+// ImportStyleSheet 从JSON或YAML样式表导入样式和文档默认格式（按内容自动识别格式）
+//
+// 样式表中的样式替换ID相同的现有样式。导入前校验整个样式表：样式ID唯一、类型有效、
+// basedOn 引用的样式存在且类型相同、基础样式链没有循环；校验失败时不修改任何样式。
+func (sm *StyleManager) ImportStyleSheet(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("读取样式表失败: %v", err)
+	}
+	sheet, err := ParseStyleSheet(data)
+	if err != nil {
+		return err
+	}
+	return sm.ApplyStyleSheet(sheet)
+}
+
+// ParseStyleSheet 解析JSON或YAML样式表，未知字段视为错误
+func ParseStyleSheet(data []byte) (*StyleSheet, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("样式表为空")
+	}
+
+	var sheet StyleSheet
+	if trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&sheet); err != nil {
+			return nil, fmt.Errorf("解析JSON样式表失败: %v", err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(trimmed))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&sheet); err != nil {
+			return nil, fmt.Errorf("解析YAML样式表失败: %v", err)
+		}
+	}
+	return &sheet, nil
+}
+
+// ApplyStyleSheet 校验并导入样式表，规则同 ImportStyleSheet
+func (sm *StyleManager) ApplyStyleSheet(sheet *StyleSheet) error {
+	if sheet.Version > StyleSheetVersion {
+		return fmt.Errorf("不支持的样式表版本: %d", sheet.Version)
+	}
+
+	styles := make([]*Style, 0, len(sheet.Styles))
+	defined := make(map[string]*Style, len(sheet.Styles))
+	for i, def := range sheet.Styles {
+		if def == nil || def.ID == "" {
+			return fmt.Errorf("第 %d 个样式缺少ID", i+1)
+		}
+		if _, exists := defined[def.ID]; exists {
+			return fmt.Errorf("样式ID重复: %s", def.ID)
+		}
+		s, err := def.toStyle()
+		if err != nil {
+			return fmt.Errorf("样式 %s 无效: %v", def.ID, err)
+		}
+		styles = append(styles, s)
+		defined[def.ID] = s
+	}
+	if err := sm.validateBasedOn(defined); err != nil {
+		return err
+	}
+
+	var docDefaults *DocDefaults
+	if sheet.Defaults != nil {
+		paraPr, err := sheet.Defaults.Paragraph.toProperties()
+		if err != nil {
+			return fmt.Errorf("文档默认段落格式无效: %v", err)
+		}
+		runPr, err := sheet.Defaults.Run.toProperties()
+		if err != nil {
+			return fmt.Errorf("文档默认字符格式无效: %v", err)
+		}
+		docDefaults = &DocDefaults{}
+		if runPr != nil {
+			docDefaults.RPrDefault = &RPrDefault{RunPr: runPr}
+		}
+		if paraPr != nil {
+			docDefaults.PPrDefault = &PPrDefault{ParagraphPr: paraPr}
+		}
+	}
+
+	if docDefaults != nil {
+		sm.docDefaults = docDefaults
+	}
+	for _, s := range styles {
+		sm.AddStyle(s)
+		delete(sm.implicit, s.StyleID)
+	}
+	return nil
+}
+
+// validateBasedOn 校验导入样式的基础样式：引用的样式存在、类型相同，基础样式链没有循环
+func (sm *StyleManager) validateBasedOn(defined map[string]*Style) error {
+	lookup := func(styleID string) *Style {
+		if s, ok := defined[styleID]; ok {
+			return s
+		}
+		return sm.styles[styleID]
+	}
+
+	ids := make([]string, 0, len(defined))
+	for styleID := range defined {
+		ids = append(ids, styleID)
+	}
+	sort.Strings(ids)
+	for _, styleID := range ids {
+		s := defined[styleID]
+		if s.BasedOn == nil {
+			continue
+		}
+		base := lookup(s.BasedOn.Val)
+		if base == nil {
+			return fmt.Errorf("样式 %s 的基础样式 %s 不存在", styleID, s.BasedOn.Val)
+		}
+		if base.Type != s.Type {
+			return fmt.Errorf("样式 %s（%s）的基础样式 %s 类型不同（%s）", styleID, s.Type, base.StyleID, base.Type)
+		}
+
+		visited := map[string]bool{styleID: true}
+		chain := []string{styleID}
+		for current := s; current.BasedOn != nil; {
+			next := current.BasedOn.Val
+			chain = append(chain, next)
+			if visited[next] {
+				return fmt.Errorf("样式的基础样式存在循环: %v", chain)
+			}
+			visited[next] = true
+			if current = lookup(next); current == nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// styleDefinition 将样式转换为样式表定义
+func styleDefinition(s *Style) *StyleDefinition {
+	def := &StyleDefinition{
+		ID:             s.StyleID,
+		Type:           StyleType(s.Type),
+		Default:        s.Default,
+		Custom:         s.CustomStyle,
+		QuickFormat:    onOffEnabled(s.QFormat),
+		Hidden:         onOffEnabled(s.Hidden),
+		SemiHidden:     onOffEnabled(s.SemiHidden),
+		UnhideWhenUsed: onOffEnabled(s.UnhideWhenUsed),
+		AutoRedefine:   onOffEnabled(s.AutoRedefine),
+		Locked:         onOffEnabled(s.Locked),
+		Paragraph:      paragraphTokens(s.ParagraphPr),
+		Run:            runTokens(s.RunPr),
+		Table:          tableTokens(s.TablePr),
+		Row:            tableRowTokens(s.TableRowPr),
+		Cell:           tableCellTokens(s.TableCellPr),
+		Extra:          extraXML(s.Extra),
+	}
+	if s.Name != nil {
+		def.Name = s.Name.Val
+	}
+	if s.BasedOn != nil {
+		def.BasedOn = s.BasedOn.Val
+	}
+	if s.Next != nil {
+		def.Next = s.Next.Val
+	}
+	if s.Link != nil {
+		def.Link = s.Link.Val
+	}
+	if s.Aliases != nil {
+		def.Aliases = s.Aliases.Val
+	}
+	if s.UIPriority != nil {
+		if priority, err := strconv.Atoi(s.UIPriority.Val); err == nil {
+			def.UIPriority = &priority
+		}
+	}
+	for _, conditional := range s.TableStylePr {
+		def.Conditions = append(def.Conditions, &TableConditionTokens{
+			Type:      conditional.Type,
+			Paragraph: paragraphTokens(conditional.ParagraphPr),
+			Run:       runTokens(conditional.RunPr),
+			Table:     tableTokens(conditional.TablePr),
+			Row:       tableRowTokens(conditional.TableRowPr),
+			Cell:      tableCellTokens(conditional.TableCellPr),
+		})
+	}
+	return def
+}
+
+// toStyle 将样式表定义转换为样式
+func (def *StyleDefinition) toStyle() (*Style, error) {
+	switch def.Type {
+	case StyleTypeParagraph, StyleTypeCharacter, StyleTypeTable, StyleTypeNumbering:
+	default:
+		return nil, fmt.Errorf("无效的样式类型: %q", def.Type)
+	}
+	if def.Type != StyleTypeTable && (def.Table != nil || def.Row != nil || def.Cell != nil || len(def.Conditions) > 0) {
+		return nil, fmt.Errorf("只有表格样式可以设置表格属性和条件格式")
+	}
+
+	s := &Style{
+		Type:           string(def.Type),
+		StyleID:        def.ID,
+		Default:        def.Default,
+		CustomStyle:    def.Custom,
+		QFormat:        newOnOff(def.QuickFormat),
+		Hidden:         newOnOff(def.Hidden),
+		SemiHidden:     newOnOff(def.SemiHidden),
+		UnhideWhenUsed: newOnOff(def.UnhideWhenUsed),
+		AutoRedefine:   newOnOff(def.AutoRedefine),
+		Locked:         newOnOff(def.Locked),
+	}
+	if def.Name != "" {
+		s.Name = &StyleName{Val: def.Name}
+	}
+	if def.BasedOn != "" {
+		s.BasedOn = &BasedOn{Val: def.BasedOn}
+	}
+	if def.Next != "" {
+		s.Next = &Next{Val: def.Next}
+	}
+	if def.Link != "" {
+		s.Link = &StyleLink{Val: def.Link}
+	}
+	if def.Aliases != "" {
+		s.Aliases = &Aliases{Val: def.Aliases}
+	}
+	if def.UIPriority != nil {
+		s.UIPriority = &UIPriority{Val: strconv.Itoa(*def.UIPriority)}
+	}
+
+	var err error
+	if s.ParagraphPr, err = def.Paragraph.toProperties(); err != nil {
+		return nil, err
+	}
+	if s.RunPr, err = def.Run.toProperties(); err != nil {
+		return nil, err
+	}
+	if s.TablePr, err = def.Table.toProperties(); err != nil {
+		return nil, err
+	}
+	if s.TableRowPr, err = def.Row.toProperties(); err != nil {
+		return nil, err
+	}
+	if s.TableCellPr, err = def.Cell.toProperties(); err != nil {
+		return nil, err
+	}
+	if s.Extra, err = parseExtraXML(def.Extra); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[TableStyleOverrideType]bool, len(def.Conditions))
+	for _, condition := range def.Conditions {
+		if condition == nil || conditionOrderIndex(condition.Type) < 0 {
+			return nil, fmt.Errorf("无效的条件格式类型")
+		}
+		if seen[condition.Type] {
+			return nil, fmt.Errorf("条件格式重复: %s", condition.Type)
+		}
+		seen[condition.Type] = true
+		conditional := &TableStyleProperties{Type: condition.Type}
+		if conditional.ParagraphPr, err = condition.Paragraph.toProperties(); err != nil {
+			return nil, err
+		}
+		if conditional.RunPr, err = condition.Run.toProperties(); err != nil {
+			return nil, err
+		}
+		if conditional.TablePr, err = condition.Table.toProperties(); err != nil {
+			return nil, err
+		}
+		if conditional.TableRowPr, err = condition.Row.toProperties(); err != nil {
+			return nil, err
+		}
+		if conditional.TableCellPr, err = condition.Cell.toProperties(); err != nil {
+			return nil, err
+		}
+		s.SetConditionalFormat(conditional)
+	}
+	return s, nil
+}
+
+// paragraphTokens 将段落属性转换为样式表表示
+func paragraphTokens(p *ParagraphProperties) *ParagraphTokens {
+	if p == nil {
+		return nil
+	}
+	tokens := &ParagraphTokens{
+		KeepNext:        p.KeepNext != nil,
+		KeepLines:       p.KeepLines != nil,
+		PageBreakBefore: p.PageBreak != nil,
+		Borders:         paragraphBorderSet(p.ParagraphBorder),
+		Shading:         shadingTokens(p.Shading),
+		Extra:           extraXML(p.Extra),
+	}
+	if p.Justification != nil {
+		tokens.Alignment = p.Justification.Val
+	}
+	if p.Spacing != nil {
+		tokens.SpaceBefore = scaledValue(p.Spacing.Before, 20)
+		tokens.SpaceAfter = scaledValue(p.Spacing.After, 20)
+		tokens.LineRule = p.Spacing.LineRule
+		if p.Spacing.LineRule == "" || p.Spacing.LineRule == "auto" {
+			tokens.LineSpacing = scaledValue(p.Spacing.Line, 240)
+		} else {
+			tokens.LineSpacing = scaledValue(p.Spacing.Line, 20)
+		}
+	}
+	if p.Indentation != nil {
+		tokens.FirstLineIndent = scaledValue(p.Indentation.FirstLine, 20)
+		tokens.HangingIndent = scaledValue(p.Indentation.Hanging, 20)
+		tokens.LeftIndent = scaledValue(p.Indentation.Left, 20)
+		tokens.RightIndent = scaledValue(p.Indentation.Right, 20)
+	}
+	if p.OutlineLevel != nil {
+		if level, err := strconv.Atoi(p.OutlineLevel.Val); err == nil {
+			tokens.OutlineLevel = &level
+		}
+	}
+	if p.SnapToGrid != nil {
+		enabled := p.SnapToGrid.Val != "0" && p.SnapToGrid.Val != "false"
+		tokens.SnapToGrid = &enabled
+	}
+	if p.NumPr != nil && p.NumPr.NumID != nil {
+		if numID, err := strconv.Atoi(p.NumPr.NumID.Val); err == nil {
+			tokens.Numbering = &NumberingLink{NumID: numID}
+			if p.NumPr.ILvl != nil {
+				if level, err := strconv.Atoi(p.NumPr.ILvl.Val); err == nil {
+					tokens.Numbering.Level = &level
+				}
+			}
+		}
+	}
+	if p.Tabs != nil {
+		for _, tab := range p.Tabs.Tabs {
+			position := scaledValue(tab.Pos, 20)
+			if position == nil {
+				continue
+			}
+			tokens.Tabs = append(tokens.Tabs, TabStopToken{Position: *position, Alignment: tab.Val, Leader: tab.Leader})
+		}
+	}
+	return tokens
+}
+
+// toProperties 将样式表表示转换为段落属性
+func (tokens *ParagraphTokens) toProperties() (*ParagraphProperties, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	p := &ParagraphProperties{Shading: tokens.Shading.toShading()}
+	if tokens.Alignment != "" {
+		p.Justification = &Justification{Val: tokens.Alignment}
+	}
+	if tokens.SpaceBefore != nil || tokens.SpaceAfter != nil || tokens.LineSpacing != nil || tokens.LineRule != "" {
+		p.Spacing = &Spacing{
+			Before:   unscaledValue(tokens.SpaceBefore, 20),
+			After:    unscaledValue(tokens.SpaceAfter, 20),
+			LineRule: tokens.LineRule,
+		}
+		switch tokens.LineRule {
+		case "", "auto":
+			p.Spacing.Line = unscaledValue(tokens.LineSpacing, 240)
+		case "exact", "atLeast":
+			p.Spacing.Line = unscaledValue(tokens.LineSpacing, 20)
+		default:
+			return nil, fmt.Errorf("无效的行距规则: %s", tokens.LineRule)
+		}
+	}
+	if tokens.FirstLineIndent != nil || tokens.HangingIndent != nil || tokens.LeftIndent != nil || tokens.RightIndent != nil {
+		p.Indentation = &Indentation{
+			FirstLine: unscaledValue(tokens.FirstLineIndent, 20),
+			Hanging:   unscaledValue(tokens.HangingIndent, 20),
+			Left:      unscaledValue(tokens.LeftIndent, 20),
+			Right:     unscaledValue(tokens.RightIndent, 20),
+		}
+	}
+	if tokens.KeepNext {
+		p.KeepNext = &KeepNext{}
+	}
+	if tokens.KeepLines {
+		p.KeepLines = &KeepLines{}
+	}
+	if tokens.PageBreakBefore {
+		p.PageBreak = &PageBreak{}
+	}
+	if tokens.OutlineLevel != nil {
+		if *tokens.OutlineLevel < 0 || *tokens.OutlineLevel > 9 {
+			return nil, fmt.Errorf("大纲级别必须在0-9之间: %d", *tokens.OutlineLevel)
+		}
+		p.OutlineLevel = &OutlineLevel{Val: strconv.Itoa(*tokens.OutlineLevel)}
+	}
+	if tokens.SnapToGrid != nil {
+		p.SnapToGrid = &SnapToGrid{Val: "0"}
+		if *tokens.SnapToGrid {
+			p.SnapToGrid.Val = "1"
+		}
+	}
+	if tokens.Numbering != nil {
+		p.NumPr = &NumPr{NumID: &NumPrID{Val: strconv.Itoa(tokens.Numbering.NumID)}}
+		if level := tokens.Numbering.Level; level != nil {
+			if *level < 0 || *level > 8 {
+				return nil, fmt.Errorf("编号级别必须在0-8之间: %d", *level)
+			}
+			p.NumPr.ILvl = &NumPrILvl{Val: strconv.Itoa(*level)}
+		}
+	}
+	if borders := tokens.Borders; borders != nil {
+		if borders.InsideH != nil || borders.InsideV != nil || borders.TL2BR != nil || borders.TR2BL != nil {
+			return nil, fmt.Errorf("段落边框只支持 top、left、bottom、right")
+		}
+		p.ParagraphBorder = &ParagraphBorder{
+			Top:    borders.Top.toParagraphBorderLine(),
+			Left:   borders.Left.toParagraphBorderLine(),
+			Bottom: borders.Bottom.toParagraphBorderLine(),
+			Right:  borders.Right.toParagraphBorderLine(),
+		}
+	}
+	if len(tokens.Tabs) > 0 {
+		p.Tabs = &Tabs{}
+		for _, tab := range tokens.Tabs {
+			if tab.Alignment == "" {
+				return nil, fmt.Errorf("制表位缺少对齐方式")
+			}
+			p.Tabs.Tabs = append(p.Tabs.Tabs, TabDef{Val: tab.Alignment, Leader: tab.Leader, Pos: unscaledValue(&tab.Position, 20)})
+		}
+	}
+	var err error
+	if p.Extra, err = parseExtraXML(tokens.Extra); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// runTokens 将字符属性转换为样式表表示
+func runTokens(r *RunProperties) *RunTokens {
+	if r == nil {
+		return nil
+	}
+	tokens := &RunTokens{
+		Strike: r.Strike != nil,
+		Extra:  extraXML(r.Extra),
+	}
+	if f := r.FontFamily; f != nil {
+		tokens.Fonts = &FontTokens{
+			ASCII: f.ASCII, EastAsia: f.EastAsia, HAnsi: f.HAnsi, CS: f.CS,
+			ASCIITheme: f.ASCIITheme, EastAsiaTheme: f.EastAsiaTheme, HAnsiTheme: f.HAnsiTheme, CSTheme: f.CSTheme,
+		}
+	}
+	if r.FontSize != nil {
+		tokens.Size = scaledValue(r.FontSize.Val, 2)
+	}
+	if r.Color != nil {
+		tokens.Color = r.Color.Val
+		tokens.ThemeColor = r.Color.ThemeColor
+		tokens.ThemeTint = r.Color.ThemeTint
+		tokens.ThemeShade = r.Color.ThemeShade
+	}
+	if r.Bold != nil {
+		enabled := r.Bold.Val != "0" && r.Bold.Val != "false"
+		tokens.Bold = &enabled
+	}
+	if r.Italic != nil {
+		enabled := r.Italic.Val != "0" && r.Italic.Val != "false"
+		tokens.Italic = &enabled
+	}
+	if r.Underline != nil {
+		tokens.Underline = r.Underline.Val
+		if tokens.Underline == "" {
+			tokens.Underline = "single"
+		}
+	}
+	if r.Highlight != nil {
+		tokens.Highlight = r.Highlight.Val
+	}
+	return tokens
+}
+
+// toProperties 将样式表表示转换为字符属性
+func (tokens *RunTokens) toProperties() (*RunProperties, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	r := &RunProperties{}
+	if f := tokens.Fonts; f != nil {
+		r.FontFamily = &FontFamily{
+			ASCII: f.ASCII, EastAsia: f.EastAsia, HAnsi: f.HAnsi, CS: f.CS,
+			ASCIITheme: f.ASCIITheme, EastAsiaTheme: f.EastAsiaTheme, HAnsiTheme: f.HAnsiTheme, CSTheme: f.CSTheme,
+		}
+	}
+	if tokens.Size != nil {
+		if *tokens.Size <= 0 {
+			return nil, fmt.Errorf("字号必须大于0: %v", *tokens.Size)
+		}
+		r.FontSize = &FontSize{Val: unscaledValue(tokens.Size, 2)}
+	}
+	if tokens.Color != "" || tokens.ThemeColor != "" {
+		color := tokens.Color
+		if color == "" {
+			color = "auto"
+		}
+		r.Color = &Color{Val: color, ThemeColor: tokens.ThemeColor, ThemeTint: tokens.ThemeTint, ThemeShade: tokens.ThemeShade}
+	}
+	if tokens.Bold != nil {
+		r.Bold = &Bold{}
+		if !*tokens.Bold {
+			r.Bold.Val = "0"
+		}
+	}
+	if tokens.Italic != nil {
+		r.Italic = &Italic{}
+		if !*tokens.Italic {
+			r.Italic.Val = "0"
+		}
+	}
+	if tokens.Underline != "" {
+		r.Underline = &Underline{Val: tokens.Underline}
+	}
+	if tokens.Strike {
+		r.Strike = &Strike{}
+	}
+	if tokens.Highlight != "" {
+		r.Highlight = &Highlight{Val: tokens.Highlight}
+	}
+	var err error
+	if r.Extra, err = parseExtraXML(tokens.Extra); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// tableTokens 将表格属性转换为样式表表示
+func tableTokens(t *TableProperties) *TableTokens {
+	if t == nil {
+		return nil
+	}
+	tokens := &TableTokens{
+		Shading: shadingTokens(t.Shading),
+		Extra:   extraXML(t.Extra),
+	}
+	if t.RowBandSize != nil {
+		if size, err := strconv.Atoi(t.RowBandSize.Val); err == nil {
+			tokens.RowBandSize = &size
+		}
+	}
+	if t.ColBandSize != nil {
+		if size, err := strconv.Atoi(t.ColBandSize.Val); err == nil {
+			tokens.ColBandSize = &size
+		}
+	}
+	if t.TblInd != nil {
+		tokens.Indent = scaledValue(t.TblInd.W, 20)
+	}
+	if b := t.TblBorders; b != nil {
+		tokens.Borders = &BorderSet{
+			Top: tableBorderToken(b.Top), Left: tableBorderToken(b.Left),
+			Bottom: tableBorderToken(b.Bottom), Right: tableBorderToken(b.Right),
+			InsideH: tableBorderToken(b.InsideH), InsideV: tableBorderToken(b.InsideV),
+		}
+	}
+	if m := t.TblCellMar; m != nil {
+		tokens.CellMargins = &MarginTokens{}
+		if m.Top != nil {
+			tokens.CellMargins.Top = scaledValue(m.Top.W, 20)
+		}
+		if m.Left != nil {
+			tokens.CellMargins.Left = scaledValue(m.Left.W, 20)
+		}
+		if m.Bottom != nil {
+			tokens.CellMargins.Bottom = scaledValue(m.Bottom.W, 20)
+		}
+		if m.Right != nil {
+			tokens.CellMargins.Right = scaledValue(m.Right.W, 20)
+		}
+	}
+	return tokens
+}
+
+// toProperties 将样式表表示转换为表格属性
+func (tokens *TableTokens) toProperties() (*TableProperties, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	t := &TableProperties{Shading: tokens.Shading.toShading()}
+	if tokens.RowBandSize != nil {
+		t.RowBandSize = &BandSize{Val: strconv.Itoa(*tokens.RowBandSize)}
+	}
+	if tokens.ColBandSize != nil {
+		t.ColBandSize = &BandSize{Val: strconv.Itoa(*tokens.ColBandSize)}
+	}
+	if tokens.Indent != nil {
+		t.TblInd = &TblIndent{W: unscaledValue(tokens.Indent, 20), Type: "dxa"}
+	}
+	if b := tokens.Borders; b != nil {
+		if b.TL2BR != nil || b.TR2BL != nil {
+			return nil, fmt.Errorf("表格边框不支持 tl2br、tr2bl")
+		}
+		t.TblBorders = &TblBorders{
+			Top: b.Top.toTableBorder(), Left: b.Left.toTableBorder(),
+			Bottom: b.Bottom.toTableBorder(), Right: b.Right.toTableBorder(),
+			InsideH: b.InsideH.toTableBorder(), InsideV: b.InsideV.toTableBorder(),
+		}
+	}
+	if m := tokens.CellMargins; m != nil {
+		t.TblCellMar = &TblCellMargin{
+			Top: cellSpace(m.Top), Left: cellSpace(m.Left),
+			Bottom: cellSpace(m.Bottom), Right: cellSpace(m.Right),
+		}
+	}
+	var err error
+	if t.Extra, err = parseExtraXML(tokens.Extra); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// tableRowTokens 将表格行属性转换为样式表表示
+func tableRowTokens(t *TableRowProperties) *TableRowTokens {
+	if t == nil {
+		return nil
+	}
+	tokens := &TableRowTokens{
+		CantSplit: onOffEnabled(t.CantSplit),
+		Header:    onOffEnabled(t.TblHeader),
+		Extra:     extraXML(t.Extra),
+	}
+	if t.Jc != nil {
+		tokens.Alignment = t.Jc.Val
+	}
+	return tokens
+}
+
+// toProperties 将样式表表示转换为表格行属性
+func (tokens *TableRowTokens) toProperties() (*TableRowProperties, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	t := &TableRowProperties{
+		CantSplit: newOnOff(tokens.CantSplit),
+		TblHeader: newOnOff(tokens.Header),
+	}
+	if tokens.Alignment != "" {
+		t.Jc = &TableJc{Val: tokens.Alignment}
+	}
+	var err error
+	if t.Extra, err = parseExtraXML(tokens.Extra); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// tableCellTokens 将表格单元格属性转换为样式表表示
+func tableCellTokens(t *TableCellProperties) *TableCellTokens {
+	if t == nil {
+		return nil
+	}
+	tokens := &TableCellTokens{
+		Shading: shadingTokens(t.Shading),
+		NoWrap:  onOffEnabled(t.NoWrap),
+		Extra:   extraXML(t.Extra),
+	}
+	if b := t.TcBorders; b != nil {
+		tokens.Borders = &BorderSet{
+			Top: tableBorderToken(b.Top), Left: tableBorderToken(b.Left),
+			Bottom: tableBorderToken(b.Bottom), Right: tableBorderToken(b.Right),
+			InsideH: tableBorderToken(b.InsideH), InsideV: tableBorderToken(b.InsideV),
+			TL2BR: tableBorderToken(b.TL2BR), TR2BL: tableBorderToken(b.TR2BL),
+		}
+	}
+	if t.VAlign != nil {
+		tokens.VerticalAlignment = t.VAlign.Val
+	}
+	return tokens
+}
+
+// toProperties 将样式表表示转换为表格单元格属性
+func (tokens *TableCellTokens) toProperties() (*TableCellProperties, error) {
+	if tokens == nil {
+		return nil, nil
+	}
+	t := &TableCellProperties{
+		Shading: tokens.Shading.toShading(),
+		NoWrap:  newOnOff(tokens.NoWrap),
+	}
+	if b := tokens.Borders; b != nil {
+		t.TcBorders = &TcBorders{
+			Top: b.Top.toTableBorder(), Left: b.Left.toTableBorder(),
+			Bottom: b.Bottom.toTableBorder(), Right: b.Right.toTableBorder(),
+			InsideH: b.InsideH.toTableBorder(), InsideV: b.InsideV.toTableBorder(),
+			TL2BR: b.TL2BR.toTableBorder(), TR2BL: b.TR2BL.toTableBorder(),
+		}
+	}
+	if tokens.VerticalAlignment != "" {
+		t.VAlign = &VAlign{Val: tokens.VerticalAlignment}
+	}
+	var err error
+	if t.Extra, err = parseExtraXML(tokens.Extra); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// paragraphBorderSet 将段落边框转换为样式表表示
+func paragraphBorderSet(b *ParagraphBorder) *BorderSet {
+	if b == nil {
+		return nil
+	}
+	convert := func(line *ParagraphBorderLine) *BorderToken {
+		if line == nil {
+			return nil
+		}
+		return &BorderToken{Style: line.Val, Size: scaledValue(line.Sz, 8), Color: line.Color, Space: scaledValue(line.Space, 1)}
+	}
+	return &BorderSet{Top: convert(b.Top), Left: convert(b.Left), Bottom: convert(b.Bottom), Right: convert(b.Right)}
+}
+
+// tableBorderToken 将表格或单元格边框线转换为样式表表示
+func tableBorderToken(b *TblBorder) *BorderToken {
+	if b == nil {
+		return nil
+	}
+	return &BorderToken{Style: b.Val, Size: scaledValue(b.Sz, 8), Color: b.Color, Space: scaledValue(b.Space, 1)}
+}
+
+// toParagraphBorderLine 将样式表表示转换为段落边框线
+func (b *BorderToken) toParagraphBorderLine() *ParagraphBorderLine {
+	if b == nil {
+		return nil
+	}
+	return &ParagraphBorderLine{Val: b.Style, Sz: unscaledValue(b.Size, 8), Color: b.color(), Space: unscaledValue(b.Space, 1)}
+}
+
+// toTableBorder 将样式表表示转换为表格或单元格边框线
+func (b *BorderToken) toTableBorder() *TblBorder {
+	if b == nil {
+		return nil
+	}
+	return &TblBorder{Val: b.Style, Sz: unscaledValue(b.Size, 8), Color: b.color(), Space: unscaledValue(b.Space, 1)}
+}
+
+// color 返回边框颜色，未设置时为 auto
+func (b *BorderToken) color() string {
+	if b.Color == "" {
+		return "auto"
+	}
+	return b.Color
+}
+
+// shadingTokens 将底纹转换为样式表表示
+func shadingTokens(s *Shading) *ShadingTokens {
+	if s == nil {
+		return nil
+	}
+	return &ShadingTokens{Fill: s.Fill, Pattern: s.Val, Color: s.Color}
+}
+
+// toShading 将样式表表示转换为底纹
+func (tokens *ShadingTokens) toShading() *Shading {
+	if tokens == nil {
+		return nil
+	}
+	pattern := tokens.Pattern
+	if pattern == "" {
+		pattern = "clear"
+	}
+	return &Shading{Fill: tokens.Fill, Val: pattern, Color: tokens.Color}
+}
+
+// cellSpace 将磅值转换为单元格边距
+func cellSpace(points *float64) *TblCellSpace {
+	if points == nil {
+		return nil
+	}
+	return &TblCellSpace{W: unscaledValue(points, 20), Type: "dxa"}
+}
+
+// onOffEnabled 判断开关元素是否开启
+func onOffEnabled(o *OnOff) bool {
+	return o != nil && o.Val != "0" && o.Val != "false"
+}
+
+// newOnOff 根据开关状态创建开关元素，关闭时返回nil
+func newOnOff(enabled bool) *OnOff {
+	if !enabled {
+		return nil
+	}
+	return &OnOff{}
+}
+
+// scaledValue 将XML中的整数值除以 scale 转换为样式表中的数值（如缇转换为磅），无效值返回nil
+func scaledValue(value string, scale float64) *float64 {
+	if value == "" {
+		return nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	result := number / scale
+	return &result
+}
+
+// unscaledValue 将样式表中的数值乘以 scale 转换为XML中的整数值，未设置时返回空字符串
+func unscaledValue(value *float64, scale float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(int(math.Round(*value * scale)))
+}
+
+// extraXML 将未建模的元素序列化为XML字符串
+func extraXML(elements []UnknownElement) []string {
+	var result []string
+	for _, element := range elements {
+		data, err := xml.Marshal(element)
+		if err != nil {
+			continue
+		}
+		result = append(result, string(data))
+	}
+	return result
+}
+
+// parseExtraXML 解析样式表中的原始XML字符串
+func parseExtraXML(values []string) ([]UnknownElement, error) {
+	var elements []UnknownElement
+	for _, value := range values {
+		var element UnknownElement
+		if err := newStylesDecoder([]byte(value)).Decode(&element); err != nil {
+			return nil, fmt.Errorf("无效的XML %q: %v", value, err)
+		}
+		elements = append(elements, element)
+	}
+	return elements, nil
+}
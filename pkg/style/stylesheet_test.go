@@ -0,0 +1,129 @@
+package style
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// newStyleSheetManager 创建包含表格条件格式、制表位、编号链接和未建模属性的样式管理器
+func newStyleSheetManager(t *testing.T) *StyleManager {
+	t.Helper()
+	sm := NewStyleManager()
+	sm.AddStyle(NewTableStyleBuilder("BrandTable", "品牌表格").
+		Borders(SingleTableBorders(4, "4472C4")).
+		CellMargins(0, 108, 0, 108).
+		BandSize(1, 1).
+		Conditional(TableConditionFirstRow, &TableConditionConfig{
+			RunConfig: &QuickRunConfig{Bold: true, FontColor: "FFFFFF"},
+			Fill:      "4472C4",
+			VAlign:    "center",
+		}).
+		Conditional(TableConditionBand1Horz, &TableConditionConfig{Fill: "D9E2F3"}).
+		Build())
+
+	heading := sm.GetStyle("Heading1")
+	heading.ParagraphPr.NumPr = &NumPr{ILvl: &NumPrILvl{Val: "0"}, NumID: &NumPrID{Val: "3"}}
+	heading.ParagraphPr.Tabs = &Tabs{Tabs: []TabDef{{Val: "right", Leader: "dot", Pos: "9350"}}}
+	heading.RunPr.Extra = []UnknownElement{{
+		XMLName: xml.Name{Local: "w:kern"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "w:val"}, Value: "44"}},
+	}}
+	sm.SetDocumentDefaults(&RunProperties{FontSize: &FontSize{Val: "21"}}, &ParagraphProperties{
+		Spacing: &Spacing{After: "160", Line: "259", LineRule: "auto"},
+	})
+	return sm
+}
+
+// TestStyleSheetRoundTrip 测试样式表以JSON和YAML导出后导入，样式保持不变
+func TestStyleSheetRoundTrip(t *testing.T) {
+	source := newStyleSheetManager(t)
+
+	for _, format := range []StyleSheetFormat{StyleSheetJSON, StyleSheetYAML} {
+		var buf bytes.Buffer
+		if err := source.ExportStyleSheet(&buf, format); err != nil {
+			t.Fatalf("导出%s样式表失败: %v", format, err)
+		}
+		if format == StyleSheetYAML && !strings.Contains(buf.String(), "type: firstRow") {
+			t.Errorf("YAML样式表应包含条件格式:\n%s", buf.String())
+		}
+
+		target := &StyleManager{styles: make(map[string]*Style)}
+		if err := target.ImportStyleSheet(&buf); err != nil {
+			t.Fatalf("导入%s样式表失败: %v", format, err)
+		}
+		if len(target.styles) != len(source.styles) {
+			t.Fatalf("%s样式数量不一致: %d != %d", format, len(target.styles), len(source.styles))
+		}
+		for styleID, expected := range source.styles {
+			want, _ := xml.Marshal(expected)
+			got, _ := xml.Marshal(target.GetStyle(styleID))
+			if string(got) != string(want) {
+				t.Errorf("%s样式 %s 不一致:\n得到 %s\n期望 %s", format, styleID, got, want)
+			}
+		}
+		want, _ := xml.Marshal(source.docDefaults)
+		got, _ := xml.Marshal(target.docDefaults)
+		if string(got) != string(want) {
+			t.Errorf("%s文档默认格式不一致:\n得到 %s\n期望 %s", format, got, want)
+		}
+	}
+}
+
+// TestImportStyleSheetValidation 测试导入样式表时的校验
+func TestImportStyleSheetValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		sheet string
+		err   string
+	}{
+		{"基础样式不存在", `{"version":1,"styles":[{"id":"Brand","type":"paragraph","basedOn":"Missing"}]}`, "不存在"},
+		{"基础样式类型不同", `{"version":1,"styles":[{"id":"Brand","type":"character","basedOn":"Normal"}]}`, "类型不同"},
+		{"循环引用", "version: 1\nstyles:\n  - id: A\n    type: paragraph\n    basedOn: B\n  - id: B\n    type: paragraph\n    basedOn: A\n", "循环"},
+		{"未知字段", "version: 1\nstyles:\n  - id: A\n    type: paragraph\n    colour: red\n", "colour"},
+		{"ID重复", `{"styles":[{"id":"A","type":"paragraph"},{"id":"A","type":"paragraph"}]}`, "重复"},
+		{"段落样式设置条件格式", `{"styles":[{"id":"A","type":"paragraph","conditions":[{"type":"firstRow"}]}]}`, "表格样式"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewStyleManager()
+			count := len(sm.styles)
+			err := sm.ImportStyleSheet(strings.NewReader(tt.sheet))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("期望包含 %q 的错误，得到 %v", tt.err, err)
+			}
+			if len(sm.styles) != count {
+				t.Error("校验失败时不应修改样式")
+			}
+		})
+	}
+
+	sm := NewStyleManager()
+	sheet := `
+version: 1
+styles:
+  - id: Normal
+    type: paragraph
+    name: Normal
+    default: true
+    run: {fonts: {ascii: Arial, eastAsia: 微软雅黑}, size: 10.5}
+  - id: BrandTitle
+    type: paragraph
+    name: Brand Title
+    basedOn: Heading1
+    paragraph: {alignment: center, spaceAfter: 12, lineSpacing: 1.5}
+    run: {color: 1F4E79, bold: false}
+`
+	if err := sm.ImportStyleSheet(strings.NewReader(sheet)); err != nil {
+		t.Fatalf("导入样式表失败: %v", err)
+	}
+	title := sm.GetStyle("BrandTitle")
+	if title == nil || title.ParagraphPr.Spacing.After != "240" || title.ParagraphPr.Spacing.Line != "360" ||
+		title.RunPr.Bold.Val != "0" || title.RunPr.Color.Val != "1F4E79" {
+		t.Errorf("导入的样式不正确: %+v", title)
+	}
+	if normal := sm.GetStyle("Normal"); normal.RunPr.FontSize.Val != "21" || normal.RunPr.FontFamily.EastAsia != "微软雅黑" {
+		t.Errorf("同ID样式应被替换: %+v", normal.RunPr)
+	}
+}
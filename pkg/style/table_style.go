@@ -0,0 +1,352 @@
+// Package style 表格样式及其条件格式
+package style
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// TableStyleOverrideType 表格条件格式的类型（w:tblStylePr/@w:type）
+type TableStyleOverrideType string
+
+const (
+	// TableConditionWholeTable 整个表格
+	TableConditionWholeTable TableStyleOverrideType = "wholeTable"
+	// TableConditionBand1Vert 奇数镶边列
+	TableConditionBand1Vert TableStyleOverrideType = "band1Vert"
+	// TableConditionBand2Vert 偶数镶边列
+	TableConditionBand2Vert TableStyleOverrideType = "band2Vert"
+	// TableConditionBand1Horz 奇数镶边行
+	TableConditionBand1Horz TableStyleOverrideType = "band1Horz"
+	// TableConditionBand2Horz 偶数镶边行
+	TableConditionBand2Horz TableStyleOverrideType = "band2Horz"
+	// TableConditionFirstCol 首列
+	TableConditionFirstCol TableStyleOverrideType = "firstCol"
+	// TableConditionLastCol 末列
+	TableConditionLastCol TableStyleOverrideType = "lastCol"
+	// TableConditionFirstRow 标题行
+	TableConditionFirstRow TableStyleOverrideType = "firstRow"
+	// TableConditionLastRow 汇总行
+	TableConditionLastRow TableStyleOverrideType = "lastRow"
+	// TableConditionNECell 右上角单元格
+	TableConditionNECell TableStyleOverrideType = "neCell"
+	// TableConditionNWCell 左上角单元格
+	TableConditionNWCell TableStyleOverrideType = "nwCell"
+	// TableConditionSECell 右下角单元格
+	TableConditionSECell TableStyleOverrideType = "seCell"
+	// TableConditionSWCell 左下角单元格
+	TableConditionSWCell TableStyleOverrideType = "swCell"
+)
+
+// TableConditionOrder 条件格式的应用顺序，靠后的条件优先级更高
+var TableConditionOrder = []TableStyleOverrideType{
+	TableConditionWholeTable,
+	TableConditionBand1Vert, TableConditionBand2Vert,
+	TableConditionBand1Horz, TableConditionBand2Horz,
+	TableConditionFirstCol, TableConditionLastCol,
+	TableConditionFirstRow, TableConditionLastRow,
+	TableConditionNECell, TableConditionNWCell, TableConditionSECell, TableConditionSWCell,
+}
+
+// TableStyleProperties 表格样式的条件格式（w:tblStylePr）
+type TableStyleProperties struct {
+	XMLName     xml.Name               `xml:"w:tblStylePr"`
+	Type        TableStyleOverrideType `xml:"w:type,attr"`
+	ParagraphPr *ParagraphProperties   `xml:"w:pPr,omitempty"`
+	RunPr       *RunProperties         `xml:"w:rPr,omitempty"`
+	TablePr     *TableProperties       `xml:"w:tblPr,omitempty"`
+	TableRowPr  *TableRowProperties    `xml:"w:trPr,omitempty"`
+	TableCellPr *TableCellProperties   `xml:"w:tcPr,omitempty"`
+}
+
+// TcBorders 单元格边框
+type TcBorders struct {
+	XMLName xml.Name   `xml:"w:tcBorders"`
+	Top     *TblBorder `xml:"w:top,omitempty"`
+	Left    *TblBorder `xml:"w:left,omitempty"`
+	Bottom  *TblBorder `xml:"w:bottom,omitempty"`
+	Right   *TblBorder `xml:"w:right,omitempty"`
+	InsideH *TblBorder `xml:"w:insideH,omitempty"`
+	InsideV *TblBorder `xml:"w:insideV,omitempty"`
+	TL2BR   *TblBorder `xml:"w:tl2br,omitempty"`
+	TR2BL   *TblBorder `xml:"w:tr2bl,omitempty"`
+}
+
+// GetConditionalFormat 获取指定类型的条件格式，不存在时返回nil
+func (s *Style) GetConditionalFormat(conditionType TableStyleOverrideType) *TableStyleProperties {
+	for _, conditional := range s.TableStylePr {
+		if conditional.Type == conditionType {
+			return conditional
+		}
+	}
+	return nil
+}
+
+// SetConditionalFormat 设置条件格式，替换同类型的已有条件格式并按应用顺序排列
+func (s *Style) SetConditionalFormat(conditional *TableStyleProperties) {
+	if conditional == nil {
+		return
+	}
+
+	var result []*TableStyleProperties
+	for _, conditionType := range TableConditionOrder {
+		if conditionType == conditional.Type {
+			result = append(result, conditional)
+		} else if existing := s.GetConditionalFormat(conditionType); existing != nil {
+			result = append(result, existing)
+		}
+	}
+	// 保留未知类型的条件格式
+	for _, existing := range s.TableStylePr {
+		if conditionOrderIndex(existing.Type) < 0 {
+			result = append(result, existing)
+		}
+	}
+	if conditionOrderIndex(conditional.Type) < 0 {
+		result = append(result, conditional)
+	}
+	s.TableStylePr = result
+}
+
+// conditionOrderIndex 返回条件类型在应用顺序中的位置，未知类型返回-1
+func conditionOrderIndex(conditionType TableStyleOverrideType) int {
+	for i, t := range TableConditionOrder {
+		if t == conditionType {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeTableStyleProperties 按类型合并基础样式和当前样式的条件格式
+func mergeTableStyleProperties(base, override []*TableStyleProperties) []*TableStyleProperties {
+	if len(base) == 0 {
+		return override
+	}
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := &Style{}
+	for _, conditional := range base {
+		merged.SetConditionalFormat(conditional)
+	}
+	for _, conditional := range override {
+		baseConditional := merged.GetConditionalFormat(conditional.Type)
+		if baseConditional == nil {
+			merged.SetConditionalFormat(conditional)
+			continue
+		}
+		result := &TableStyleProperties{
+			Type:        conditional.Type,
+			ParagraphPr: mergeParagraphProperties(baseConditional.ParagraphPr, conditional.ParagraphPr),
+			RunPr:       mergeRunProperties(baseConditional.RunPr, conditional.RunPr),
+			TablePr:     conditional.TablePr,
+			TableRowPr:  conditional.TableRowPr,
+			TableCellPr: conditional.TableCellPr,
+		}
+		if result.TablePr == nil {
+			result.TablePr = baseConditional.TablePr
+		}
+		if result.TableRowPr == nil {
+			result.TableRowPr = baseConditional.TableRowPr
+		}
+		if result.TableCellPr == nil {
+			result.TableCellPr = baseConditional.TableCellPr
+		}
+		merged.SetConditionalFormat(result)
+	}
+	return merged.TableStylePr
+}
+
+// cloneTableStyleProperties 深拷贝条件格式
+func (sm *StyleManager) cloneTableStyleProperties(source *TableStyleProperties) *TableStyleProperties {
+	if source == nil {
+		return nil
+	}
+	return &TableStyleProperties{
+		Type:        source.Type,
+		ParagraphPr: sm.cloneParagraphProperties(source.ParagraphPr),
+		RunPr:       sm.cloneRunProperties(source.RunPr),
+		TablePr:     sm.cloneTableProperties(source.TablePr),
+		TableRowPr:  sm.cloneTableRowProperties(source.TableRowPr),
+		TableCellPr: sm.cloneTableCellProperties(source.TableCellPr),
+	}
+}
+
+// cloneOnOff 深拷贝开关元素
+func cloneOnOff(source *OnOff) *OnOff {
+	if source == nil {
+		return nil
+	}
+	return &OnOff{Val: source.Val}
+}
+
+// cloneTcBorders 深拷贝单元格边框
+func cloneTcBorders(source *TcBorders) *TcBorders {
+	if source == nil {
+		return nil
+	}
+	cloneBorder := func(border *TblBorder) *TblBorder {
+		if border == nil {
+			return nil
+		}
+		cloned := *border
+		return &cloned
+	}
+	return &TcBorders{
+		Top:     cloneBorder(source.Top),
+		Left:    cloneBorder(source.Left),
+		Bottom:  cloneBorder(source.Bottom),
+		Right:   cloneBorder(source.Right),
+		InsideH: cloneBorder(source.InsideH),
+		InsideV: cloneBorder(source.InsideV),
+		TL2BR:   cloneBorder(source.TL2BR),
+		TR2BL:   cloneBorder(source.TR2BL),
+	}
+}
+
+// TableConditionConfig 条件格式配置
+type TableConditionConfig struct {
+	RunConfig       *QuickRunConfig       // 文字格式
+	ParagraphConfig *QuickParagraphConfig // 段落格式
+	Fill            string                // 单元格底纹颜色（十六进制）
+	Borders         *TcBorders            // 单元格边框
+	VAlign          string                // 单元格垂直对齐：top、center、bottom
+}
+
+// TableStyleBuilder 自定义表格样式构建器
+//
+// 示例：
+//
+//	tableStyle := style.NewTableStyleBuilder("BrandTable", "品牌表格").
+//		Borders(style.SingleTableBorders(4, "4472C4")).
+//		Conditional(style.TableConditionFirstRow, &style.TableConditionConfig{
+//			RunConfig: &style.QuickRunConfig{Bold: true, FontColor: "FFFFFF"},
+//			Fill:      "4472C4",
+//		}).
+//		Conditional(style.TableConditionBand1Horz, &style.TableConditionConfig{Fill: "D9E2F3"}).
+//		Build()
+//	doc.GetStyleManager().AddStyle(tableStyle)
+type TableStyleBuilder struct {
+	style *Style
+}
+
+// NewTableStyleBuilder 创建表格样式构建器，默认基于普通表格样式
+func NewTableStyleBuilder(styleID, name string) *TableStyleBuilder {
+	return &TableStyleBuilder{
+		style: &Style{
+			Type:        string(StyleTypeTable),
+			StyleID:     styleID,
+			CustomStyle: true,
+			Name:        &StyleName{Val: name},
+			BasedOn:     &BasedOn{Val: "a1"},
+		},
+	}
+}
+
+// BasedOn 设置基础样式，传入空字符串表示不基于其他样式
+func (b *TableStyleBuilder) BasedOn(styleID string) *TableStyleBuilder {
+	if styleID == "" {
+		b.style.BasedOn = nil
+	} else {
+		b.style.BasedOn = &BasedOn{Val: styleID}
+	}
+	return b
+}
+
+// Borders 设置整个表格的边框
+func (b *TableStyleBuilder) Borders(borders *TblBorders) *TableStyleBuilder {
+	b.tableProperties().TblBorders = borders
+	return b
+}
+
+// Shading 设置整个表格的底纹
+func (b *TableStyleBuilder) Shading(fill string) *TableStyleBuilder {
+	b.tableProperties().Shading = &Shading{Val: "clear", Color: "auto", Fill: fill}
+	return b
+}
+
+// CellMargins 设置单元格边距（磅）
+func (b *TableStyleBuilder) CellMargins(top, left, bottom, right int) *TableStyleBuilder {
+	space := func(points int) *TblCellSpace {
+		return &TblCellSpace{W: fmt.Sprintf("%d", points*20), Type: "dxa"}
+	}
+	b.tableProperties().TblCellMar = &TblCellMargin{Top: space(top), Left: space(left), Bottom: space(bottom), Right: space(right)}
+	return b
+}
+
+// BandSize 设置每个镶边包含的行数和列数（默认均为1）
+func (b *TableStyleBuilder) BandSize(rows, cols int) *TableStyleBuilder {
+	props := b.tableProperties()
+	if rows > 0 {
+		props.RowBandSize = &BandSize{Val: fmt.Sprintf("%d", rows)}
+	}
+	if cols > 0 {
+		props.ColBandSize = &BandSize{Val: fmt.Sprintf("%d", cols)}
+	}
+	return b
+}
+
+// Run 设置整个表格的文字格式
+func (b *TableStyleBuilder) Run(config *QuickRunConfig) *TableStyleBuilder {
+	if config != nil {
+		b.style.RunPr = createRunProperties(config)
+	}
+	return b
+}
+
+// Paragraph 设置整个表格的段落格式
+func (b *TableStyleBuilder) Paragraph(config *QuickParagraphConfig) *TableStyleBuilder {
+	if config != nil {
+		b.style.ParagraphPr = createParagraphProperties(config)
+	}
+	return b
+}
+
+// Conditional 设置条件格式（如标题行、镶边行），同类型的条件格式会被替换
+func (b *TableStyleBuilder) Conditional(conditionType TableStyleOverrideType, config *TableConditionConfig) *TableStyleBuilder {
+	if config == nil {
+		return b
+	}
+
+	conditional := &TableStyleProperties{Type: conditionType}
+	if config.RunConfig != nil {
+		conditional.RunPr = createRunProperties(config.RunConfig)
+	}
+	if config.ParagraphConfig != nil {
+		conditional.ParagraphPr = createParagraphProperties(config.ParagraphConfig)
+	}
+	if config.Fill != "" || config.Borders != nil || config.VAlign != "" {
+		conditional.TableCellPr = &TableCellProperties{TcBorders: config.Borders}
+		if config.Fill != "" {
+			conditional.TableCellPr.Shading = &Shading{Val: "clear", Color: "auto", Fill: config.Fill}
+		}
+		if config.VAlign != "" {
+			conditional.TableCellPr.VAlign = &VAlign{Val: config.VAlign}
+		}
+	}
+	b.style.SetConditionalFormat(conditional)
+	return b
+}
+
+// Build 返回构建的表格样式
+func (b *TableStyleBuilder) Build() *Style {
+	return b.style
+}
+
+// tableProperties 获取或创建表格属性
+func (b *TableStyleBuilder) tableProperties() *TableProperties {
+	if b.style.TablePr == nil {
+		b.style.TablePr = &TableProperties{}
+	}
+	return b.style.TablePr
+}
+
+// SingleTableBorders 创建四周及内部均为单线的表格边框，size 单位为1/8磅
+func SingleTableBorders(size int, color string) *TblBorders {
+	border := func() *TblBorder {
+		return &TblBorder{Val: "single", Sz: fmt.Sprintf("%d", size), Space: "0", Color: color}
+	}
+	return &TblBorders{Top: border(), Left: border(), Bottom: border(), Right: border(), InsideH: border(), InsideV: border()}
+}
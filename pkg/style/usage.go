@@ -0,0 +1,57 @@
+// Package style 样式的使用情况和清理
+package style
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UsedStyles 返回被使用的样式ID
+//
+// 被使用的样式包括 isUsed 返回真的样式、默认样式，以及它们通过 basedOn、next、link
+// 直接或间接引用的样式。
+func (sm *StyleManager) UsedStyles(isUsed func(styleID string) bool) map[string]bool {
+	used := make(map[string]bool, len(sm.styles))
+	for styleID, s := range sm.styles {
+		if s.Default || (isUsed != nil && isUsed(styleID)) {
+			used[styleID] = true
+		}
+	}
+	sm.addReferencedStyles(used)
+	return used
+}
+
+// RemoveUnusedStyles 删除未被使用的样式（判断规则见 UsedStyles），返回已排序的被删除样式ID
+func (sm *StyleManager) RemoveUnusedStyles(isUsed func(styleID string) bool) []string {
+	used := sm.UsedStyles(isUsed)
+	var removed []string
+	for styleID := range sm.styles {
+		if !used[styleID] {
+			removed = append(removed, styleID)
+		}
+	}
+	sort.Strings(removed)
+	for _, styleID := range removed {
+		sm.RemoveStyle(styleID)
+		delete(sm.implicit, styleID)
+	}
+	return removed
+}
+
+// ParseRunPropertiesXML 解析字符属性XML（w:rPr），未建模的子元素保存在 Extra 中
+func ParseRunPropertiesXML(xmlData []byte) (*RunProperties, error) {
+	var props RunProperties
+	if err := newStylesDecoder(xmlData).Decode(&props); err != nil {
+		return nil, fmt.Errorf("解析字符属性XML失败: %v", err)
+	}
+	return &props, nil
+}
+
+// ParseParagraphPropertiesXML 解析段落属性XML（w:pPr），未建模的子元素保存在 Extra 中
+func ParseParagraphPropertiesXML(xmlData []byte) (*ParagraphProperties, error) {
+	var props ParagraphProperties
+	if err := newStylesDecoder(xmlData).Decode(&props); err != nil {
+		return nil, fmt.Errorf("解析段落属性XML失败: %v", err)
+	}
+	return &props, nil
+}
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/markdown"
+)
+
+// TestMarkdownLinks 测试Markdown链接转换为超链接域
+func TestMarkdownLinks(t *testing.T) {
+	opts := markdown.DefaultOptions()
+	doc, err := markdown.NewConverter(opts).ConvertString("访问[官网](https://example.com/?q=\"a\")或<https://wordzero.dev>，见[附录](#appendix)", opts)
+	if err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	paragraphs := doc.Body.GetParagraphs()
+	if len(paragraphs) != 1 {
+		t.Fatalf("应有1个段落，实际 %d 个", len(paragraphs))
+	}
+
+	var instructions []string
+	var text strings.Builder
+	for _, run := range paragraphs[0].Runs {
+		if run.InstrText != nil {
+			instructions = append(instructions, strings.TrimSpace(run.InstrText.Content))
+		}
+		text.WriteString(run.Text.Content)
+		if run.Text.Content == "官网" && (run.Properties == nil || run.Properties.Underline == nil) {
+			t.Error("超链接文本应有下划线")
+		}
+	}
+	expected := []string{`HYPERLINK "https://example.com/?q=\"a\""`, `HYPERLINK "https://wordzero.dev"`, `HYPERLINK \l "appendix"`}
+	if strings.Join(instructions, "|") != strings.Join(expected, "|") {
+		t.Errorf("超链接域指令不正确: %q", instructions)
+	}
+	if text.String() != "访问官网或https://wordzero.dev，见附录" {
+		t.Errorf("段落文本不正确: %q", text.String())
+	}
+}
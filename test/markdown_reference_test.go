@@ -0,0 +1,42 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/document"
+	"github.com/zerx-lab/wordZero/pkg/markdown"
+	"github.com/zerx-lab/wordZero/pkg/style"
+)
+
+// TestMarkdownReferenceDocument 测试Markdown转换时导入参考文档的样式和页眉
+func TestMarkdownReferenceDocument(t *testing.T) {
+	ref := document.New()
+	heading := ref.GetStyleManager().GetStyle("Heading1")
+	heading.RunPr.Color = &style.Color{Val: "1F3864"}
+	if err := ref.AddHeader(document.HeaderFooterTypeDefault, "公司模板"); err != nil {
+		t.Fatalf("添加页眉失败: %v", err)
+	}
+	refPath := filepath.Join(t.TempDir(), "reference.docx")
+	if err := ref.Save(refPath); err != nil {
+		t.Fatalf("保存参考文档失败: %v", err)
+	}
+
+	opts := markdown.DefaultOptions()
+	opts.ReferenceDocument = refPath
+	doc, err := markdown.NewConverter(opts).ConvertString("# 标题\n\n正文", opts)
+	if err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	if color := doc.GetStyleManager().GetStyle("Heading1").RunPr.Color; color == nil || color.Val != "1F3864" {
+		t.Errorf("标题样式应来自参考文档: %+v", color)
+	}
+	if header := doc.GetHeader(document.HeaderFooterTypeDefault); header == nil || header.GetText() != "公司模板" {
+		t.Error("页眉应来自参考文档")
+	}
+
+	opts.ReferenceDocument = filepath.Join(t.TempDir(), "missing.docx")
+	if _, err := markdown.NewConverter(opts).ConvertString("# 标题", opts); err == nil {
+		t.Error("参考文档不存在时应返回错误")
+	}
+}
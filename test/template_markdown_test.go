@@ -0,0 +1,56 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zerx-lab/wordZero/pkg/document"
+	_ "github.com/zerx-lab/wordZero/pkg/markdown"
+)
+
+// TestTemplateMarkdownValues 测试模板中按Markdown渲染的变量
+func TestTemplateMarkdownValues(t *testing.T) {
+	doc := document.New()
+	doc.AddParagraph("季度报告")
+	doc.AddParagraph("{{narrative}}")
+	doc.AddParagraph("摘要：{{brief}}")
+
+	engine := document.NewTemplateEngine()
+	if _, err := engine.LoadTemplateFromDocument("report", doc); err != nil {
+		t.Fatalf("加载文档模板失败: %v", err)
+	}
+
+	data := document.NewTemplateData()
+	data.SetMarkdown("narrative", "## 收入分析\n\n收入**同比增长**12%。\n\n- 华东区\n- 华南区")
+	data.SetMarkdown("brief", "**稳定增长**，详见[附录](https://example.com)")
+
+	result, err := engine.RenderTemplateToDocument("report", data)
+	if err != nil {
+		t.Fatalf("渲染文档模板失败: %v", err)
+	}
+
+	var texts []string
+	bold := false
+	for _, para := range result.Body.GetParagraphs() {
+		text := ""
+		for _, run := range para.Runs {
+			text += run.Text.Content
+			if run.Text.Content == "稳定增长" {
+				bold = run.Properties != nil && run.Properties.Bold != nil
+			}
+		}
+		texts = append(texts, text)
+	}
+	joined := strings.Join(texts, "\n")
+	for _, expected := range []string{"收入分析", "收入同比增长12%。", "华东区", "华南区", "摘要：稳定增长，详见附录"} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("渲染结果应包含 %q，实际为 %q", expected, joined)
+		}
+	}
+	if strings.Contains(joined, "{{") || strings.Contains(joined, "**") {
+		t.Errorf("Markdown变量应被渲染: %q", joined)
+	}
+	if !bold {
+		t.Error("行内Markdown应保留粗体格式")
+	}
+}